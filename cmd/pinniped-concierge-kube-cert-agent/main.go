@@ -31,6 +31,14 @@ func main() {
 	switch os.Args[1] {
 	case "sleep":
 		sleep(math.MaxInt64)
+	case "check":
+		// Used as the readiness probe of the agent pod, so it only verifies that the files are readable.
+		if _, err := ioutil.ReadFile(getenv("CERT_PATH")); err != nil {
+			fail("could not read CERT_PATH: %v", err)
+		}
+		if _, err := ioutil.ReadFile(getenv("KEY_PATH")); err != nil {
+			fail("could not read KEY_PATH: %v", err)
+		}
 	case "print":
 		certBytes, err := ioutil.ReadFile(getenv("CERT_PATH"))
 		if err != nil {
@@ -46,6 +46,34 @@ func TestEntrypoint(t *testing.T) {
 			args:      []string{"/path/to/binary", "sleep"},
 			wantSleep: 2562047*time.Hour + 47*time.Minute + 16*time.Second + 854775807*time.Nanosecond, // math.MaxInt64 nanoseconds, approximately 290 years
 		},
+		{
+			name: "check with missing cert file",
+			args: []string{"/path/to/binary", "check"},
+			env: map[string]string{
+				"CERT_PATH": "./does/not/exist",
+				"KEY_PATH":  "./testdata/test.key",
+			},
+			wantFail: true,
+			wantLog:  "could not read CERT_PATH: open ./does/not/exist: no such file or directory\n",
+		},
+		{
+			name: "check with missing key file",
+			args: []string{"/path/to/binary", "check"},
+			env: map[string]string{
+				"CERT_PATH": "./testdata/test.crt",
+				"KEY_PATH":  "./does/not/exist",
+			},
+			wantFail: true,
+			wantLog:  "could not read KEY_PATH: open ./does/not/exist: no such file or directory\n",
+		},
+		{
+			name: "successful check",
+			args: []string{"/path/to/binary", "check"},
+			env: map[string]string{
+				"CERT_PATH": "./testdata/test.crt",
+				"KEY_PATH":  "./testdata/test.key",
+			},
+		},
 		{
 			name: "missing cert file",
 			args: []string{"/path/to/binary", "print"},
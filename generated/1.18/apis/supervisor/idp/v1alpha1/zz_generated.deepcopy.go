@@ -9,6 +9,7 @@
 package v1alpha1
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -417,11 +418,21 @@ func (in *OIDCAuthorizationConfig) DeepCopyInto(out *OIDCAuthorizationConfig) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ReplaceScopes != nil {
+		in, out := &in.ReplaceScopes, &out.ReplaceScopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.AdditionalAuthorizeParameters != nil {
 		in, out := &in.AdditionalAuthorizeParameters, &out.AdditionalAuthorizeParameters
 		*out = make([]Parameter, len(*in))
 		copy(*out, *in)
 	}
+	if in.AdditionalTokenParameters != nil {
+		in, out := &in.AdditionalTokenParameters, &out.AdditionalTokenParameters
+		*out = make([]Parameter, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -435,6 +446,23 @@ func (in *OIDCAuthorizationConfig) DeepCopy() *OIDCAuthorizationConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCCertificateAuthorityStatus) DeepCopyInto(out *OIDCCertificateAuthorityStatus) {
+	*out = *in
+	in.NotAfter.DeepCopyInto(&out.NotAfter)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCCertificateAuthorityStatus.
+func (in *OIDCCertificateAuthorityStatus) DeepCopy() *OIDCCertificateAuthorityStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCCertificateAuthorityStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OIDCClaims) DeepCopyInto(out *OIDCClaims) {
 	*out = *in
@@ -533,7 +561,12 @@ func (in *OIDCIdentityProviderSpec) DeepCopyInto(out *OIDCIdentityProviderSpec)
 	*out = *in
 	if in.TLS != nil {
 		in, out := &in.TLS, &out.TLS
-		*out = new(TLSSpec)
+		*out = new(OIDCTLSSpec)
+		**out = **in
+	}
+	if in.RequestTimeout != nil {
+		in, out := &in.RequestTimeout, &out.RequestTimeout
+		*out = new(v1.Duration)
 		**out = **in
 	}
 	in.AuthorizationConfig.DeepCopyInto(&out.AuthorizationConfig)
@@ -562,6 +595,11 @@ func (in *OIDCIdentityProviderStatus) DeepCopyInto(out *OIDCIdentityProviderStat
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CertificateAuthority != nil {
+		in, out := &in.CertificateAuthority, &out.CertificateAuthority
+		*out = new(OIDCCertificateAuthorityStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -575,6 +613,23 @@ func (in *OIDCIdentityProviderStatus) DeepCopy() *OIDCIdentityProviderStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCTLSSpec) DeepCopyInto(out *OIDCTLSSpec) {
+	*out = *in
+	out.TLSSpec = in.TLSSpec
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCTLSSpec.
+func (in *OIDCTLSSpec) DeepCopy() *OIDCTLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCTLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Parameter) DeepCopyInto(out *Parameter) {
 	*out = *in
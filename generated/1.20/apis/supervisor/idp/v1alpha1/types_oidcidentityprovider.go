@@ -18,13 +18,16 @@ const (
 
 	// PhaseError is the phase for an OIDCIdentityProvider in an unhealthy state.
 	PhaseError OIDCIdentityProviderPhase = "Error"
+
+	// PhaseDisabled is the phase for an OIDCIdentityProvider which is disabled by its spec.disabled setting.
+	PhaseDisabled OIDCIdentityProviderPhase = "Disabled"
 )
 
 // OIDCIdentityProviderStatus is the status of an OIDC identity provider.
 type OIDCIdentityProviderStatus struct {
 	// Phase summarizes the overall status of the OIDCIdentityProvider.
 	// +kubebuilder:default=Pending
-	// +kubebuilder:validation:Enum=Pending;Ready;Error
+	// +kubebuilder:validation:Enum=Pending;Ready;Error;Disabled
 	Phase OIDCIdentityProviderPhase `json:"phase,omitempty"`
 
 	// Represents the observations of an identity provider's current state.
@@ -33,6 +36,33 @@ type OIDCIdentityProviderStatus struct {
 	// +listType=map
 	// +listMapKey=type
 	Conditions []Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// DiscoveredConfigHash is a SHA-256 hash of the provider metadata which was most recently observed through OIDC
+	// discovery (or of the manually configured endpoints). It only changes when the provider's metadata changes, which
+	// makes it easy to see when the provider rotated its endpoints.
+	// +optional
+	DiscoveredConfigHash string `json:"discoveredConfigHash,omitempty"`
+
+	// CertificateAuthority summarizes the first certificate of the CA bundle in spec.tls.certificateAuthorityData,
+	// which helps to debug TLS errors when connecting to the issuer. It is not set when no CA bundle is configured or
+	// when the CA bundle does not contain any valid certificates.
+	// +optional
+	CertificateAuthority *OIDCCertificateAuthorityStatus `json:"certificateAuthority,omitempty"`
+}
+
+// OIDCCertificateAuthorityStatus summarizes a CA certificate which is trusted for requests to the issuer.
+type OIDCCertificateAuthorityStatus struct {
+	// Subject is the distinguished name of the subject of the certificate.
+	Subject string `json:"subject"`
+
+	// Issuer is the distinguished name of the issuer of the certificate.
+	Issuer string `json:"issuer"`
+
+	// NotAfter is the time at which the certificate expires.
+	NotAfter metav1.Time `json:"notAfter"`
+
+	// Certificates is the total number of certificates in the CA bundle.
+	Certificates int32 `json:"certificates"`
 }
 
 // OIDCAuthorizationConfig provides information about how to form the OAuth2 authorization
@@ -69,23 +99,46 @@ type OIDCAuthorizationConfig struct {
 	// +optional
 	AdditionalScopes []string `json:"additionalScopes,omitempty"`
 
+	// replaceScopes, when set, replaces the entire list of scopes that will be requested from your OIDC provider,
+	// including the default scopes, which is useful when your OIDC provider rejects some of the default scopes (e.g.
+	// "profile" or "email") and requires a minimal set of scopes. These scopes are requested in the order given, except
+	// that the "openid" scope is always requested first when it is not included, since it is always required according
+	// to the OIDC spec. When this field is set, additionalScopes is ignored. When set, it must not be empty.
+	// +optional
+	// +kubebuilder:validation:MinItems=1
+	ReplaceScopes []string `json:"replaceScopes,omitempty"`
+
+	// allowUnadvertisedScopes, when true, will allow the Supervisor to request scopes from your OIDC provider which
+	// are not listed in the "scopes_supported" value of its discovery document. By default, the OIDCIdentityProvider
+	// will report a failing ScopesSupported condition when any requested scope is not advertised by your OIDC
+	// provider, because requesting an unsupported scope (e.g. "offline_access") will otherwise cause confusing errors
+	// during login or refresh. Set this to true if your OIDC provider supports scopes that it does not advertise.
+	// allowUnadvertisedScopes defaults to false.
+	// +optional
+	AllowUnadvertisedScopes bool `json:"allowUnadvertisedScopes,omitempty"`
+
 	// additionalAuthorizeParameters are extra query parameters that should be included in the authorize request to your
 	// OIDC provider in the authorization request during an OIDC Authorization Code Flow. By default, no extra
 	// parameters are sent. The standard parameters that will be sent are "response_type", "scope", "client_id",
 	// "state", "nonce", "code_challenge", "code_challenge_method", and "redirect_uri". These parameters cannot be
-	// included in this setting. Additionally, the "hd" parameter cannot be included in this setting at this time.
-	// The "hd" parameter is used by Google's OIDC provider to provide a hint as to which "hosted domain" the user
-	// should use during login. However, Pinniped does not yet support validating the hosted domain in the resulting
-	// ID token, so it is not yet safe to use this feature of Google's OIDC provider with Pinniped.
+	// included in this setting. The "hd" parameter, which is used by Google's OIDC provider to provide a hint as to
+	// which "hosted domain" the user should use during login, should be configured using the hostedDomain setting
+	// instead. If the "hd" parameter is included in this setting, then its value must be the same as hostedDomain.
 	// This setting does not influence the parameters sent to the token endpoint in the Resource Owner Password
 	// Credentials Grant. The Pinniped Supervisor requires that your OIDC provider returns refresh tokens to the
 	// Supervisor from the authorization flows. Some OIDC providers may require a certain value for the "prompt"
 	// parameter in order to properly request refresh tokens. See the documentation of your OIDC provider's
 	// authorization endpoint for its requirements for what to include in the request in order to receive a refresh
 	// token in the response, if anything. If your provider requires the prompt parameter to request a refresh token,
-	// then include it here. Also note that most providers also require a certain scope to be requested in order to
-	// receive refresh tokens. See the additionalScopes setting for more information about using scopes to request
-	// refresh tokens.
+	// then include it here. The value of the "prompt" parameter must be a space-separated list of the values defined
+	// by the OIDC spec, which are "none", "login", "consent", and "select_account". The standard "acr_values" and
+	// "ui_locales" parameters may be used to request authentication context classes and the languages of the login
+	// UI. Their values must be space-separated lists of at most 256 characters, and every item of the "ui_locales"
+	// parameter must be a BCP47 language tag, e.g. "fr-CA en". Providers which do not support these parameters will
+	// typically ignore them. The value of every parameter must
+	// be at most 1024 characters long and must not contain control characters. Also note that most providers also
+	// require a certain scope to be requested in order to receive refresh tokens. See the additionalScopes setting for
+	// more information about using scopes to request refresh tokens.
 	// +optional
 	// +patchMergeKey=name
 	// +patchStrategy=merge
@@ -93,6 +146,30 @@ type OIDCAuthorizationConfig struct {
 	// +listMapKey=name
 	AdditionalAuthorizeParameters []Parameter `json:"additionalAuthorizeParameters,omitempty"`
 
+	// additionalTokenParameters are extra parameters that should be included in the token request to your OIDC
+	// provider when the Supervisor exchanges an authorization code for tokens during an OIDC Authorization Code Flow,
+	// e.g. the "audience" or "resource" parameters which some providers use to choose the audience of the issued
+	// access token. By default, no extra parameters are sent. The standard parameters that will be sent are
+	// "grant_type", "code", "redirect_uri", "code_verifier", and, depending on the client authentication method,
+	// "client_id" and "client_secret". These parameters, and the other parameters of the token request of other grant
+	// types ("refresh_token", "username", "password", and "scope"), cannot be included in this setting. This setting
+	// does not influence the parameters sent to the token endpoint in the Resource Owner Password Credentials Grant or
+	// when refreshing tokens. The value of every parameter must be at most 1024 characters long and must not contain
+	// control characters.
+	// +optional
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=name
+	AdditionalTokenParameters []Parameter `json:"additionalTokenParameters,omitempty"`
+
+	// hostedDomain is the Google Workspace domain to which users must belong in order to log in using Google's OIDC
+	// provider. When set, it will be sent as the "hd" parameter in the authorize request to your OIDC provider during
+	// an OIDC Authorization Code Flow, and the "hd" claim of each ID token returned by your OIDC provider must exactly
+	// match this value, otherwise the login will fail. By default, no hosted domain is requested or validated.
+	// +optional
+	HostedDomain string `json:"hostedDomain,omitempty"`
+
 	// allowPasswordGrant, when true, will allow the use of OAuth 2.0's Resource Owner Password Credentials Grant
 	// (see https://datatracker.ietf.org/doc/html/rfc6749#section-4.3) to authenticate to the OIDC provider using a
 	// username and password without a web browser, in addition to the usual browser-based OIDC Authorization Code Flow.
@@ -111,6 +188,23 @@ type OIDCAuthorizationConfig struct {
 	// allowPasswordGrant defaults to false.
 	// +optional
 	AllowPasswordGrant bool `json:"allowPasswordGrant,omitempty"`
+
+	// authorizationEndpoint, when set along with tokenEndpoint and jwksURI, is the URL of the authorization endpoint
+	// of your OIDC provider. When these three endpoints are set, the Supervisor will use them instead of performing
+	// OIDC discovery against the issuer, which is useful when your OIDC provider's discovery document is broken or
+	// not reachable by the Supervisor. Must be an https URL.
+	// +optional
+	AuthorizationEndpoint string `json:"authorizationEndpoint,omitempty"`
+
+	// tokenEndpoint is the URL of the token endpoint of your OIDC provider. See authorizationEndpoint.
+	// Must be an https URL.
+	// +optional
+	TokenEndpoint string `json:"tokenEndpoint,omitempty"`
+
+	// jwksURI is the URL of the JSON Web Key Set of your OIDC provider, which contains the keys used to verify
+	// ID tokens. See authorizationEndpoint. Must be an https URL.
+	// +optional
+	JWKSURI string `json:"jwksURI,omitempty"`
 }
 
 // Parameter is a key/value pair which represents a parameter in an HTTP request.
@@ -132,22 +226,81 @@ type OIDCClaims struct {
 	// +optional
 	Groups string `json:"groups"`
 
+	// GroupsDelimiter specifies a single character which separates the group names when the upstream provider returns
+	// the groups claim as one string, e.g. "," for "admins,developers" or " " for "admins developers". When not set,
+	// a string value of the groups claim is treated as the name of a single group. A groups claim which is an array
+	// of strings is always used as-is.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=1
+	// +optional
+	GroupsDelimiter string `json:"groupsDelimiter,omitempty"`
+
 	// Username provides the name of the ID token claim or userinfo endpoint response claim that will be used to
 	// ascertain an identity's username. When not set, the username will be an automatically constructed unique string
 	// which will include the issuer URL of your OIDC provider along with the value of the "sub" (subject) claim from
 	// the ID token.
+	//
+	// Alternatively, this may be a template which combines the values of several claims, where each claim is
+	// referenced by enclosing its name in curly braces, e.g. "{preferred_username}@{iss}". Every claim referenced
+	// by the template must have a non-empty string value, or else the login will fail.
 	// +optional
 	Username string `json:"username"`
+
+	// IgnoreEmailVerified specifies that logins should not fail when the upstream "email_verified" claim is false or
+	// is not a boolean. By default, when the username claim is "email" or is a template which references the "email"
+	// claim, the "email_verified" claim must be true when it is present. Only set this for providers which are known
+	// to never verify email addresses, since anyone who can use an unverified email address with your provider could
+	// then log in with that username. This may only be set when the username claim references the "email" claim.
+	// +optional
+	IgnoreEmailVerified bool `json:"ignoreEmailVerified,omitempty"`
 }
 
+// OIDCClientAuthMethod is the method used by an OIDC client to authenticate to the token endpoint of an OIDC provider.
+//
+// +kubebuilder:validation:Enum=basic;post
+type OIDCClientAuthMethod string
+
+const (
+	// OIDCClientAuthMethodBasic sends the client credentials using HTTP Basic authentication (client_secret_basic).
+	OIDCClientAuthMethodBasic OIDCClientAuthMethod = "basic"
+
+	// OIDCClientAuthMethodPost sends the client credentials in the request body (client_secret_post).
+	OIDCClientAuthMethodPost OIDCClientAuthMethod = "post"
+)
+
 // OIDCClient contains information about an OIDC client (e.g., client ID and client
 // secret).
 type OIDCClient struct {
 	// SecretName contains the name of a namespace-local Secret object that provides the clientID and
 	// clientSecret for an OIDC client. If only the SecretName is specified in an OIDCClient
 	// struct, then it is expected that the Secret is of type "secrets.pinniped.dev/oidc-client" with keys
-	// "clientID" and "clientSecret".
+	// "clientID" and "clientSecret". Alternatively, when neither of those keys is present, the Secret may have a
+	// single key "oidc.json" which contains a JSON object with "clientID" and "clientSecret" fields.
 	SecretName string `json:"secretName"`
+
+	// AuthMethod specifies how the client credentials are sent to the token endpoint of the OIDC provider.
+	// "basic" uses HTTP Basic authentication (client_secret_basic) and "post" puts them in the request body
+	// (client_secret_post). When not set, HTTP Basic authentication is tried first, falling back to the
+	// request body when the OIDC provider rejects it.
+	// +optional
+	AuthMethod OIDCClientAuthMethod `json:"authMethod,omitempty"`
+}
+
+// OIDCTLSSpec is the configuration for TLS parameters related to OIDC identity provider integration.
+type OIDCTLSSpec struct {
+	TLSSpec `json:",inline"`
+
+	// Name of a Secret of type "kubernetes.io/tls" in the same namespace as the OIDCIdentityProvider, which contains a
+	// client certificate and private key to present to the issuer, for issuers which require mutual TLS. If omitted,
+	// no client certificate is presented.
+	// +optional
+	ClientCertificateSecretName string `json:"clientCertificateSecretName,omitempty"`
+
+	// X.509 Certificate Authority (base64-encoded PEM bundle) to trust for requests to the JWKS URL of the issuer
+	// instead of certificateAuthorityData, e.g. when the JWKS is served by a CDN. If omitted, certificateAuthorityData
+	// is also trusted for requests to the JWKS URL.
+	// +optional
+	JWKSCertificateAuthorityData string `json:"jwksCertificateAuthorityData,omitempty"`
 }
 
 // OIDCIdentityProviderSpec is the spec for configuring an OIDC identity provider.
@@ -160,7 +313,31 @@ type OIDCIdentityProviderSpec struct {
 
 	// TLS configuration for discovery/JWKS requests to the issuer.
 	// +optional
-	TLS *TLSSpec `json:"tls,omitempty"`
+	TLS *OIDCTLSSpec `json:"tls,omitempty"`
+
+	// ProxyURL is the URL of an HTTP(S) or SOCKS5 proxy through which discovery/JWKS requests and other requests
+	// from the Supervisor to the issuer should be sent. If omitted, the proxy is determined by the HTTP_PROXY,
+	// HTTPS_PROXY, and NO_PROXY environment variables of the Supervisor pods.
+	// +optional
+	ProxyURL string `json:"proxyURL,omitempty"`
+
+	// RequestTimeout is how long the Supervisor waits for each discovery/JWKS request to the issuer to complete.
+	// Must be greater than zero and at most ten minutes. If omitted, requests time out after one minute.
+	// +optional
+	RequestTimeout *metav1.Duration `json:"requestTimeout,omitempty"`
+
+	// DisableDiscoveryCache, when true, makes the Supervisor perform OIDC discovery against the issuer every time it
+	// validates this OIDCIdentityProvider, instead of reusing the result of a recent discovery. This can be useful
+	// for issuers which change their endpoints frequently, or while debugging. Failed discovery is still retried
+	// with an increasing delay. Defaults to false.
+	// +optional
+	DisableDiscoveryCache bool `json:"disableDiscoveryCache,omitempty"`
+
+	// Disabled, when true, takes this OIDCIdentityProvider out of use without deleting it, e.g. during maintenance of
+	// the issuer. A disabled OIDCIdentityProvider cannot be used to log in, and the Supervisor makes no requests to
+	// its issuer. Its status phase will be "Disabled". Defaults to false.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
 
 	// AuthorizationConfig holds information about how to form the OAuth2 authorization request
 	// parameters to be used with this OIDC identity provider.
@@ -9,6 +9,7 @@
 package v1alpha1
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -51,7 +52,7 @@ func (in *CredentialIssuerFrontend) DeepCopyInto(out *CredentialIssuerFrontend)
 	if in.ImpersonationProxyInfo != nil {
 		in, out := &in.ImpersonationProxyInfo, &out.ImpersonationProxyInfo
 		*out = new(ImpersonationProxyInfo)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
@@ -186,9 +187,29 @@ func (in *CredentialIssuerStrategy) DeepCopy() *CredentialIssuerStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImpersonationProxyCASubject) DeepCopyInto(out *ImpersonationProxyCASubject) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImpersonationProxyCASubject.
+func (in *ImpersonationProxyCASubject) DeepCopy() *ImpersonationProxyCASubject {
+	if in == nil {
+		return nil
+	}
+	out := new(ImpersonationProxyCASubject)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImpersonationProxyInfo) DeepCopyInto(out *ImpersonationProxyInfo) {
 	*out = *in
+	if in.CertificateNotAfter != nil {
+		in, out := &in.CertificateNotAfter, &out.CertificateNotAfter
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -205,6 +226,21 @@ func (in *ImpersonationProxyInfo) DeepCopy() *ImpersonationProxyInfo {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImpersonationProxyServiceSpec) DeepCopyInto(out *ImpersonationProxyServiceSpec) {
 	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LoadBalancerSourceRanges != nil {
+		in, out := &in.LoadBalancerSourceRanges, &out.LoadBalancerSourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LoadBalancerClass != nil {
+		in, out := &in.LoadBalancerClass, &out.LoadBalancerClass
+		*out = new(string)
+		**out = **in
+	}
 	if in.Annotations != nil {
 		in, out := &in.Annotations, &out.Annotations
 		*out = make(map[string]string, len(*in))
@@ -229,6 +265,41 @@ func (in *ImpersonationProxyServiceSpec) DeepCopy() *ImpersonationProxyServiceSp
 func (in *ImpersonationProxySpec) DeepCopyInto(out *ImpersonationProxySpec) {
 	*out = *in
 	in.Service.DeepCopyInto(&out.Service)
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CACertificateLifetime != nil {
+		in, out := &in.CACertificateLifetime, &out.CACertificateLifetime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.CACertificateRenewalThresholdPercent != nil {
+		in, out := &in.CACertificateRenewalThresholdPercent, &out.CACertificateRenewalThresholdPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CACertificateSubject != nil {
+		in, out := &in.CACertificateSubject, &out.CACertificateSubject
+		*out = new(ImpersonationProxyCASubject)
+		**out = **in
+	}
+	if in.TLSCertificateLifetime != nil {
+		in, out := &in.TLSCertificateLifetime, &out.TLSCertificateLifetime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.AdditionalHostnames != nil {
+		in, out := &in.AdditionalHostnames, &out.AdditionalHostnames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AdditionalIPs != nil {
+		in, out := &in.AdditionalIPs, &out.AdditionalIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -20,7 +20,7 @@ type FrontendType string
 type StrategyStatus string
 
 // StrategyReason enumerates the detailed reason why a strategy is in a particular status.
-// +kubebuilder:validation:Enum=Listening;Pending;Disabled;ErrorDuringSetup;CouldNotFetchKey;CouldNotGetClusterInfo;FetchedKey
+// +kubebuilder:validation:Enum=Listening;Pending;Disabled;ErrorDuringSetup;ServiceError;SecretError;CAError;ValidationError;ServerStartError;CouldNotFetchKey;CouldNotGetClusterInfo;FetchedKey
 type StrategyReason string
 
 const (
@@ -37,6 +37,11 @@ const (
 	PendingStrategyReason                = StrategyReason("Pending")
 	DisabledStrategyReason               = StrategyReason("Disabled")
 	ErrorDuringSetupStrategyReason       = StrategyReason("ErrorDuringSetup")
+	ServiceErrorStrategyReason           = StrategyReason("ServiceError")
+	SecretErrorStrategyReason            = StrategyReason("SecretError")
+	CAErrorStrategyReason                = StrategyReason("CAError")
+	ValidationErrorStrategyReason        = StrategyReason("ValidationError")
+	ServerStartErrorStrategyReason       = StrategyReason("ServerStartError")
 	CouldNotFetchKeyStrategyReason       = StrategyReason("CouldNotFetchKey")
 	CouldNotGetClusterInfoStrategyReason = StrategyReason("CouldNotGetClusterInfo")
 	FetchedKeyStrategyReason             = StrategyReason("FetchedKey")
@@ -66,7 +71,7 @@ const (
 
 // ImpersonationProxyServiceType enumerates the types of service that can be provisioned for the impersonation proxy.
 //
-// +kubebuilder:validation:Enum=LoadBalancer;ClusterIP;None
+// +kubebuilder:validation:Enum=LoadBalancer;ClusterIP;None;Existing
 type ImpersonationProxyServiceType string
 
 const (
@@ -78,8 +83,62 @@ const (
 
 	// ImpersonationProxyServiceTypeNone does not automatically provision any service.
 	ImpersonationProxyServiceTypeNone = ImpersonationProxyServiceType("None")
+
+	// ImpersonationProxyServiceTypeExisting does not provision any service, but uses the address of a service
+	// which was provisioned by other means.
+	ImpersonationProxyServiceTypeExisting = ImpersonationProxyServiceType("Existing")
+)
+
+// ImpersonationProxyServiceExternalTrafficPolicy enumerates the external traffic policies that can be set on the
+// Service provisioned for the impersonation proxy.
+//
+// +kubebuilder:validation:Enum=Cluster;Local
+type ImpersonationProxyServiceExternalTrafficPolicy string
+
+const (
+	// ImpersonationProxyServiceExternalTrafficPolicyCluster routes external traffic to all impersonation proxy endpoints.
+	ImpersonationProxyServiceExternalTrafficPolicyCluster = ImpersonationProxyServiceExternalTrafficPolicy("Cluster")
+
+	// ImpersonationProxyServiceExternalTrafficPolicyLocal routes external traffic only to node-local impersonation
+	// proxy endpoints, which preserves the client source IP.
+	ImpersonationProxyServiceExternalTrafficPolicyLocal = ImpersonationProxyServiceExternalTrafficPolicy("Local")
 )
 
+// ImpersonationProxyServiceAddressType enumerates the kinds of Service address that can be preferred when
+// advertising the impersonation proxy.
+//
+// +kubebuilder:validation:Enum=Hostname;IP;IPv4;IPv6
+type ImpersonationProxyServiceAddressType string
+
+const (
+	// ImpersonationProxyServiceAddressTypeHostname prefers the hostname of the load balancer ingress.
+	ImpersonationProxyServiceAddressTypeHostname = ImpersonationProxyServiceAddressType("Hostname")
+
+	// ImpersonationProxyServiceAddressTypeIP prefers the IP address of the load balancer ingress.
+	ImpersonationProxyServiceAddressTypeIP = ImpersonationProxyServiceAddressType("IP")
+
+	// ImpersonationProxyServiceAddressTypeIPv4 prefers an IPv4 address of the Service.
+	ImpersonationProxyServiceAddressTypeIPv4 = ImpersonationProxyServiceAddressType("IPv4")
+
+	// ImpersonationProxyServiceAddressTypeIPv6 prefers an IPv6 address of the Service.
+	ImpersonationProxyServiceAddressTypeIPv6 = ImpersonationProxyServiceAddressType("IPv6")
+)
+
+// ImpersonationProxyCASubject describes the subject of the CA certificate generated for the impersonation proxy.
+type ImpersonationProxyCASubject struct {
+	// CommonName specifies the Common Name (CN) of the CA certificate.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=64
+	CommonName string `json:"commonName"`
+
+	// Organization specifies the Organization (O) of the CA certificate.
+	//
+	// +kubebuilder:validation:MaxLength=64
+	// +optional
+	Organization string `json:"organization,omitempty"`
+}
+
 // ImpersonationProxySpec describes the intended configuration of the Concierge impersonation proxy.
 type ImpersonationProxySpec struct {
 	// Mode configures whether the impersonation proxy should be started:
@@ -100,6 +159,105 @@ type ImpersonationProxySpec struct {
 	//
 	// +optional
 	ExternalEndpoint string `json:"externalEndpoint,omitempty"`
+
+	// Port specifies the port on which the impersonation proxy listens inside the Concierge pods. This is also
+	// used as the target port of the provisioned Service. If not set, the impersonationProxyServerPort from the
+	// Concierge's static configuration is used, which defaults to 8444.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+
+	// CACertificateLifetime specifies how long the CA certificate which is generated by the Concierge to issue
+	// the impersonation proxy's TLS serving certificate should be valid. Must be at least one hour.
+	// If not set, the CA certificate is valid for approximately 100 years.
+	//
+	// +optional
+	CACertificateLifetime *metav1.Duration `json:"caCertificateLifetime,omitempty"`
+
+	// CACertificateRenewalThresholdPercent specifies when the generated CA certificate should be replaced by a new one.
+	// When the remaining lifetime of the CA certificate falls below this percentage of its total lifetime, a new
+	// CA certificate is generated and the TLS serving certificate is reissued from the new CA. Defaults to 25.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	// +optional
+	CACertificateRenewalThresholdPercent *int32 `json:"caCertificateRenewalThresholdPercent,omitempty"`
+
+	// CACertificateSubject specifies the subject of the CA certificate which is generated by the Concierge to issue
+	// the impersonation proxy's TLS serving certificate. This is useful to make the CA of each cluster identifiable,
+	// e.g. by audit tooling. Changes take effect the next time that a CA certificate is generated. If not set, the
+	// CommonName is "Pinniped Impersonation Proxy Serving CA" and no Organization is set.
+	//
+	// +optional
+	CACertificateSubject *ImpersonationProxyCASubject `json:"caCertificateSubject,omitempty"`
+
+	// TLSCertificateLifetime specifies how long the impersonation proxy's TLS serving certificate which is issued by
+	// the Concierge should be valid. The TLS serving certificate is reissued when less than a third of its lifetime
+	// remains, and it never outlives the CA certificate which issued it. Must be at least one hour.
+	// If not set, the TLS serving certificate is valid until its CA certificate expires.
+	//
+	// +optional
+	TLSCertificateLifetime *metav1.Duration `json:"tlsCertificateLifetime,omitempty"`
+
+	// AdditionalHostnames specifies zero or more DNS names to include in the impersonation proxy's TLS serving
+	// certificate in addition to the name of the advertised endpoint. This is useful when clients reach the
+	// impersonation proxy using a DNS name which differs from its external endpoint or load balancer hostname.
+	//
+	// +optional
+	AdditionalHostnames []string `json:"additionalHostnames,omitempty"`
+
+	// AdditionalIPs specifies zero or more IP addresses to include in the impersonation proxy's TLS serving
+	// certificate in addition to the address of the advertised endpoint.
+	//
+	// +optional
+	AdditionalIPs []string `json:"additionalIPs,omitempty"`
+
+	// WildcardHostname specifies that the impersonation proxy's TLS serving certificate should also be valid for
+	// every subdomain of the hostname of the external endpoint, e.g. "*.example.com" in addition to "example.com".
+	// This is useful when clients reach the impersonation proxy through several subdomains, e.g. regional CNAMEs.
+	// This may only be set when spec.impersonationProxy.externalEndpoint is a hostname rather than an IP address.
+	//
+	// +optional
+	WildcardHostname bool `json:"wildcardHostname,omitempty"`
+
+	// TLSCertificateSecretName is the name of a Secret of type "kubernetes.io/tls" in the Concierge's namespace which
+	// contains the impersonation proxy's TLS serving certificate and private key, e.g. as issued by your own CA. When
+	// set, the Concierge does not generate a CA or a serving certificate for the impersonation proxy, and
+	// tlsCertificateAuthorityData must also be set. The certificate must be currently valid, must verify against
+	// tlsCertificateAuthorityData, and must be valid for the host of the advertised endpoint.
+	//
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	TLSCertificateSecretName string `json:"tlsCertificateSecretName,omitempty"`
+
+	// TLSCertificateAuthorityData is the base64-encoded PEM bundle of the CA certificates which clients should trust
+	// when connecting to the impersonation proxy. It is advertised in the status of the CredentialIssuer, and may
+	// only be set together with tlsCertificateSecretName.
+	//
+	// +optional
+	TLSCertificateAuthorityData string `json:"tlsCertificateAuthorityData,omitempty"`
+
+	// GeneratedCASecretName overrides the name of the Secret in the Concierge's namespace in which the Concierge
+	// stores the CA which it generates for the impersonation proxy. When it is changed, the Concierge generates a new
+	// CA in the newly named Secret and deletes the previously named one. Defaults to the name from the Concierge's
+	// static configuration.
+	//
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	GeneratedCASecretName string `json:"generatedCASecretName,omitempty"`
+
+	// GeneratedTLSSecretName overrides the name of the Secret in the Concierge's namespace in which the Concierge
+	// stores the TLS serving certificate which it generates for the impersonation proxy. When it is changed, the
+	// Concierge issues a new serving certificate in the newly named Secret and deletes the previously named one.
+	// Defaults to the name from the Concierge's static configuration.
+	//
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	GeneratedTLSSecretName string `json:"generatedTLSSecretName,omitempty"`
 }
 
 // ImpersonationProxyServiceSpec describes how the Concierge should provision a Service to expose the impersonation proxy.
@@ -109,9 +267,32 @@ type ImpersonationProxyServiceSpec struct {
 	// If the type is "None", then the "spec.impersonationProxy.externalEndpoint" field must be set to a non-empty
 	// value so that the Concierge can properly advertise the endpoint in the CredentialIssuer's status.
 	//
+	// If the type is "Existing", then the "spec.impersonationProxy.service.existingServiceName" field must name a
+	// Service which was provisioned by other means. The Concierge reads the address of that Service to advertise the
+	// endpoint and to issue the TLS serving certificate, but never creates, updates, or deletes it.
+	//
 	// +kubebuilder:default:="LoadBalancer"
 	Type ImpersonationProxyServiceType `json:"type,omitempty"`
 
+	// ExistingServiceName specifies the name of a Service in the Concierge's namespace which exposes the
+	// impersonation proxy and which is not managed by the Concierge. This is only used when the type is "Existing".
+	// When that Service is of type LoadBalancer, the addresses of its ingress are used, and otherwise its cluster IPs
+	// are used.
+	//
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	ExistingServiceName string `json:"existingServiceName,omitempty"`
+
+	// Port specifies the port on which the provisioned Service exposes the impersonation proxy. The target port of
+	// the Service is still the port on which the impersonation proxy listens inside the Concierge pods. When the
+	// endpoint is read from the provisioned Service, this port is included in the advertised endpoint unless it is 443.
+	// This is only used when the type is "LoadBalancer" or "ClusterIP". Defaults to 443.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+
 	// LoadBalancerIP specifies the IP address to set in the spec.loadBalancerIP field of the provisioned Service.
 	// This is not supported on all cloud providers.
 	//
@@ -120,6 +301,61 @@ type ImpersonationProxyServiceSpec struct {
 	// +optional
 	LoadBalancerIP string `json:"loadBalancerIP,omitempty"`
 
+	// ClusterIP specifies the IP address to request in the spec.clusterIP field of the provisioned Service, e.g. to
+	// give it a stable address which was allocated in advance. It must be an unused IP address within the service
+	// CIDR of the cluster. The Service is recreated when this changes, because Kubernetes does not allow the cluster
+	// IP of an existing Service to change. This is only used when the type is "ClusterIP". If not set, Kubernetes
+	// assigns a cluster IP.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=255
+	// +optional
+	ClusterIP string `json:"clusterIP,omitempty"`
+
+	// LoadBalancerSourceRanges specifies zero or more CIDRs to set in the spec.loadBalancerSourceRanges field
+	// of the provisioned Service, which restricts the client IPs that may connect to the load balancer.
+	// This is only used when the type is "LoadBalancer", and is not supported on all cloud providers.
+	//
+	// +optional
+	LoadBalancerSourceRanges []string `json:"loadBalancerSourceRanges,omitempty"`
+
+	// LoadBalancerClass specifies the spec.loadBalancerClass field of the provisioned Service, which selects the load
+	// balancer implementation which provisions it in a cluster which has more than one, e.g. "metallb.io/metallb".
+	// The Service is recreated when this changes, because Kubernetes does not allow the load balancer class of an
+	// existing Service to change. This is only used when the type is "LoadBalancer". If not set, the default load
+	// balancer implementation of the cluster is used.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	LoadBalancerClass *string `json:"loadBalancerClass,omitempty"`
+
+	// ExternalTrafficPolicy specifies the spec.externalTrafficPolicy field of the provisioned Service.
+	// Set this to "Local" to preserve the source IP of clients. This is only used when the type is "LoadBalancer".
+	// Defaults to "Cluster".
+	//
+	// +optional
+	ExternalTrafficPolicy ImpersonationProxyServiceExternalTrafficPolicy `json:"externalTrafficPolicy,omitempty"`
+
+	// PreferredAddressType specifies whether the hostname or the IP address of the load balancer's ingress is
+	// advertised as the endpoint of the impersonation proxy when the load balancer reports both. The preferred
+	// address is also used as the Common Name of the TLS serving certificate, which is reissued when this changes.
+	// The certificate is always valid for every hostname and IP address of the load balancer. IPv4 and IPv6 prefer
+	// an IP address of that family, and also choose which of the cluster IPs of a dual-stack ClusterIP Service is
+	// advertised, while its certificate remains valid for all of them. If not set, the first hostname of a load
+	// balancer, or the primary cluster IP of a ClusterIP Service, is preferred.
+	//
+	// +optional
+	PreferredAddressType ImpersonationProxyServiceAddressType `json:"preferredAddressType,omitempty"`
+
+	// Unmanaged specifies that the Concierge should create the provisioned Service when it does not exist, but should
+	// not revert later changes which other actors make to it, e.g. to add finalizers or cloud-specific fields. The
+	// Concierge still reads the Service to advertise the endpoint and to issue the TLS serving certificate, and still
+	// deletes it when it is no longer needed. Changes to the other fields of this spec are not applied to an existing
+	// unmanaged Service. This is only used when the type is "LoadBalancer" or "ClusterIP".
+	//
+	// +optional
+	Unmanaged bool `json:"unmanaged,omitempty"`
+
 	// Annotations specifies zero or more key/value pairs to set as annotations on the provisioned Service.
 	//
 	// +optional
@@ -208,6 +444,11 @@ type ImpersonationProxyInfo struct {
 	// CertificateAuthorityData is the base64-encoded PEM CA bundle of the impersonation proxy.
 	// +kubebuilder:validation:MinLength=1
 	CertificateAuthorityData string `json:"certificateAuthorityData"`
+
+	// CertificateNotAfter is the time at which the TLS serving certificate of the impersonation proxy expires.
+	// It is updated whenever the certificate is reissued.
+	// +optional
+	CertificateNotAfter *metav1.Time `json:"certificateNotAfter,omitempty"`
 }
 
 // CredentialIssuer describes the configuration and status of the Pinniped Concierge credential issuer.
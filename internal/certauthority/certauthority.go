@@ -98,11 +98,16 @@ func Load(certPEM string, keyPEM string) (*CA, error) {
 
 // New generates a fresh certificate authority with the given Common Name and TTL.
 func New(commonName string, ttl time.Duration) (*CA, error) {
-	return newInternal(commonName, ttl, secureEnv())
+	return NewWithSubject(pkix.Name{CommonName: commonName}, ttl)
 }
 
-// newInternal is the internal guts of New, broken out for easier testing.
-func newInternal(commonName string, ttl time.Duration, env env) (*CA, error) {
+// NewWithSubject generates a fresh certificate authority with the given subject and TTL.
+func NewWithSubject(subject pkix.Name, ttl time.Duration) (*CA, error) {
+	return newInternal(subject, ttl, secureEnv())
+}
+
+// newInternal is the internal guts of NewWithSubject, broken out for easier testing.
+func newInternal(subject pkix.Name, ttl time.Duration, env env) (*CA, error) {
 	ca := CA{env: env}
 	// Generate a random serial for the CA
 	serialNumber, err := randomSerial(env.serialRNG)
@@ -125,7 +130,7 @@ func newInternal(commonName string, ttl time.Duration, env env) (*CA, error) {
 	// Create CA cert template
 	caTemplate := x509.Certificate{
 		SerialNumber:          serialNumber,
-		Subject:               pkix.Name{CommonName: commonName},
+		Subject:               subject,
 		NotBefore:             notBefore,
 		NotAfter:              notAfter,
 		IsCA:                  true,
@@ -176,7 +181,13 @@ func (c *CA) IssueClientCert(username string, groups []string, ttl time.Duration
 // IssueServerCert issues a new server certificate for the given identity and duration.
 // The dnsNames and ips are each optional, but at least one of them should be specified.
 func (c *CA) IssueServerCert(dnsNames []string, ips []net.IP, ttl time.Duration) (*tls.Certificate, error) {
-	return c.issueCert(x509.ExtKeyUsageServerAuth, pkix.Name{}, dnsNames, ips, ttl)
+	return c.IssueServerCertWithCommonName("", dnsNames, ips, ttl)
+}
+
+// IssueServerCertWithCommonName is like IssueServerCert, but also sets the Common Name of the certificate's subject.
+// Clients do not use the Common Name to verify the certificate, so it is only informational.
+func (c *CA) IssueServerCertWithCommonName(commonName string, dnsNames []string, ips []net.IP, ttl time.Duration) (*tls.Certificate, error) {
+	return c.issueCert(x509.ExtKeyUsageServerAuth, pkix.Name{CommonName: commonName}, dnsNames, ips, ttl)
 }
 
 // Similar to IssueClientCert, but returning the new cert as a pair of PEM-formatted byte slices
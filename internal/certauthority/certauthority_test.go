@@ -7,6 +7,7 @@ import (
 	"crypto"
 	"crypto/tls"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -102,6 +103,17 @@ func TestNew(t *testing.T) {
 	require.NotNil(t, ca.privateKey)
 }
 
+func TestNewWithSubject(t *testing.T) {
+	ca, err := NewWithSubject(pkix.Name{CommonName: "Test CA", Organization: []string{"Test Org"}}, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, ca)
+
+	caCert, err := x509.ParseCertificate(ca.caCertBytes)
+	require.NoError(t, err)
+	require.Equal(t, "Test CA", caCert.Subject.CommonName)
+	require.Equal(t, []string{"Test Org"}, caCert.Subject.Organization)
+}
+
 func TestNewInternal(t *testing.T) {
 	now := time.Date(2020, 7, 10, 12, 41, 12, 1234, time.UTC)
 
@@ -159,7 +171,7 @@ func TestNewInternal(t *testing.T) {
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := newInternal("Test CA", tt.ttl, tt.env)
+			got, err := newInternal(pkix.Name{CommonName: "Test CA"}, tt.ttl, tt.env)
 			if tt.wantErr != "" {
 				require.EqualError(t, err, tt.wantErr)
 				require.Nil(t, got)
@@ -402,27 +414,33 @@ func TestIssueMethods(t *testing.T) {
 		require.NoError(t, err)
 		certPEM, keyPEM, err := ToPEM(serverCert)
 		require.NoError(t, err)
-		validateServerCert(t, ca.Bundle(), certPEM, keyPEM, dnsNames, ips, ttl)
+		validateServerCert(t, ca.Bundle(), certPEM, keyPEM, "", dnsNames, ips, ttl)
 
 		certPEM, keyPEM, err = ca.IssueServerCertPEM(dnsNames, ips, ttl)
 		require.NoError(t, err)
-		validateServerCert(t, ca.Bundle(), certPEM, keyPEM, dnsNames, ips, ttl)
+		validateServerCert(t, ca.Bundle(), certPEM, keyPEM, "", dnsNames, ips, ttl)
 
 		certPEM, keyPEM, err = ca.IssueServerCertPEM(nil, ips, ttl)
 		require.NoError(t, err)
-		validateServerCert(t, ca.Bundle(), certPEM, keyPEM, nil, ips, ttl)
+		validateServerCert(t, ca.Bundle(), certPEM, keyPEM, "", nil, ips, ttl)
 
 		certPEM, keyPEM, err = ca.IssueServerCertPEM(dnsNames, nil, ttl)
 		require.NoError(t, err)
-		validateServerCert(t, ca.Bundle(), certPEM, keyPEM, dnsNames, nil, ttl)
+		validateServerCert(t, ca.Bundle(), certPEM, keyPEM, "", dnsNames, nil, ttl)
 
 		certPEM, keyPEM, err = ca.IssueServerCertPEM([]string{}, ips, ttl)
 		require.NoError(t, err)
-		validateServerCert(t, ca.Bundle(), certPEM, keyPEM, nil, ips, ttl)
+		validateServerCert(t, ca.Bundle(), certPEM, keyPEM, "", nil, ips, ttl)
 
 		certPEM, keyPEM, err = ca.IssueServerCertPEM(dnsNames, []net.IP{}, ttl)
 		require.NoError(t, err)
-		validateServerCert(t, ca.Bundle(), certPEM, keyPEM, dnsNames, nil, ttl)
+		validateServerCert(t, ca.Bundle(), certPEM, keyPEM, "", dnsNames, nil, ttl)
+
+		serverCert, err = ca.IssueServerCertWithCommonName("pinniped.dev", dnsNames, ips, ttl)
+		require.NoError(t, err)
+		certPEM, keyPEM, err = ToPEM(serverCert)
+		require.NoError(t, err)
+		validateServerCert(t, ca.Bundle(), certPEM, keyPEM, "pinniped.dev", dnsNames, ips, ttl)
 	})
 }
 
@@ -437,12 +455,12 @@ func validateClientCert(t *testing.T, caBundle []byte, certPEM []byte, keyPEM []
 	v.RequireEmptyIPs()
 }
 
-func validateServerCert(t *testing.T, caBundle []byte, certPEM []byte, keyPEM []byte, expectedDNSNames []string, expectedIPs []net.IP, expectedTTL time.Duration) {
+func validateServerCert(t *testing.T, caBundle []byte, certPEM []byte, keyPEM []byte, expectedCommonName string, expectedDNSNames []string, expectedIPs []net.IP, expectedTTL time.Duration) {
 	const fudgeFactor = 10 * time.Second
 	v := testutil.ValidateServerCertificate(t, string(caBundle), string(certPEM))
 	v.RequireLifetime(time.Now(), time.Now().Add(expectedTTL), certBackdate+fudgeFactor)
 	v.RequireMatchesPrivateKey(string(keyPEM))
-	v.RequireCommonName("")
+	v.RequireCommonName(expectedCommonName)
 	v.RequireDNSNames(expectedDNSNames)
 	v.RequireIPs(expectedIPs)
 }
@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -20,14 +21,47 @@ const (
 )
 
 type ClusterHost struct {
-	client kubernetes.Interface
+	client                    kubernetes.Interface
+	controlPlaneNodeSelectors []labels.Selector
 }
 
 func New(client kubernetes.Interface) *ClusterHost {
 	return &ClusterHost{client: client}
 }
 
+// NewWithControlPlaneNodeSelectors returns a ClusterHost which considers any node matching at least one of the
+// selectors to be a control plane node, e.g. to match both the legacy and the current node role labels of clusters
+// which are being upgraded. An empty list behaves the same as New.
+func NewWithControlPlaneNodeSelectors(client kubernetes.Interface, controlPlaneNodeSelectors []labels.Selector) *ClusterHost {
+	return &ClusterHost{client: client, controlPlaneNodeSelectors: controlPlaneNodeSelectors}
+}
+
 func (c *ClusterHost) HasControlPlaneNodes(ctx context.Context) (bool, error) {
+	switch len(c.controlPlaneNodeSelectors) {
+	case 0:
+		// Look for the well-known node role labels below.
+	case 1:
+		nodes, err := c.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: c.controlPlaneNodeSelectors[0].String()})
+		if err != nil {
+			return false, fmt.Errorf("error fetching nodes: %v", err)
+		}
+		return len(nodes.Items) > 0, nil
+	default:
+		// Label selectors cannot express a logical OR, so list all nodes and match each selector locally.
+		nodes, err := c.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return false, fmt.Errorf("error fetching nodes: %v", err)
+		}
+		for _, node := range nodes.Items {
+			for _, selector := range c.controlPlaneNodeSelectors {
+				if selector.Matches(labels.Set(node.Labels)) {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	}
+
 	nodes, err := c.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return false, fmt.Errorf("error fetching nodes: %v", err)
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -22,6 +23,7 @@ func TestHasControlPlaneNodes(t *testing.T) {
 	tests := []struct {
 		name            string
 		nodes           []*v1.Node
+		selectors       []labels.Selector
 		listNodesErr    error
 		wantErr         error
 		wantReturnValue bool
@@ -141,6 +143,97 @@ func TestHasControlPlaneNodes(t *testing.T) {
 			},
 			wantReturnValue: true,
 		},
+		{
+			name:         "Fetching nodes with a custom selector returns an error",
+			selectors:    []labels.Selector{labels.SelectorFromSet(labels.Set{"example.com/role": "control"})},
+			listNodesErr: errors.New("couldn't get nodes"),
+			wantErr:      errors.New("error fetching nodes: couldn't get nodes"),
+		},
+		{
+			name:      "Nodes found with a custom selector, but none match the selector",
+			selectors: []labels.Selector{labels.SelectorFromSet(labels.Set{"example.com/role": "control"})},
+			nodes: []*v1.Node{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "node-1",
+						Labels: map[string]string{"node-role.kubernetes.io/control-plane": ""},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "node-2",
+						Labels: map[string]string{"example.com/role": "worker"},
+					},
+				},
+			},
+			wantReturnValue: false,
+		},
+		{
+			name:      "Nodes found with a custom selector, including one which matches the selector",
+			selectors: []labels.Selector{labels.SelectorFromSet(labels.Set{"example.com/role": "control"})},
+			nodes: []*v1.Node{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "node-1",
+						Labels: map[string]string{"example.com/role": "worker"},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "node-2",
+						Labels: map[string]string{"example.com/role": "control"},
+					},
+				},
+			},
+			wantReturnValue: true,
+		},
+		{
+			name: "Fetching nodes with multiple custom selectors returns an error",
+			selectors: []labels.Selector{
+				labels.SelectorFromSet(labels.Set{"node-role.kubernetes.io/control-plane": ""}),
+				labels.SelectorFromSet(labels.Set{"node-role.kubernetes.io/master": ""}),
+			},
+			listNodesErr: errors.New("couldn't get nodes"),
+			wantErr:      errors.New("error fetching nodes: couldn't get nodes"),
+		},
+		{
+			name: "Nodes found with multiple custom selectors, but none match any selector",
+			selectors: []labels.Selector{
+				labels.SelectorFromSet(labels.Set{"node-role.kubernetes.io/control-plane": ""}),
+				labels.SelectorFromSet(labels.Set{"node-role.kubernetes.io/master": ""}),
+			},
+			nodes: []*v1.Node{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "node-1",
+						Labels: map[string]string{"node-role.kubernetes.io/worker": ""},
+					},
+				},
+			},
+			wantReturnValue: false,
+		},
+		{
+			name: "Nodes found with multiple custom selectors, including one with only the legacy master label",
+			selectors: []labels.Selector{
+				labels.SelectorFromSet(labels.Set{"node-role.kubernetes.io/control-plane": ""}),
+				labels.SelectorFromSet(labels.Set{"node-role.kubernetes.io/master": ""}),
+			},
+			nodes: []*v1.Node{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "node-1",
+						Labels: map[string]string{"node-role.kubernetes.io/worker": ""},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "node-2",
+						Labels: map[string]string{"node-role.kubernetes.io/master": ""},
+					},
+				},
+			},
+			wantReturnValue: true,
+		},
 	}
 	for _, tt := range tests {
 		test := tt
@@ -160,7 +253,7 @@ func TestHasControlPlaneNodes(t *testing.T) {
 				err := kubeClient.Tracker().Add(node)
 				require.NoError(t, err)
 			}
-			clusterHost := New(kubeClient)
+			clusterHost := NewWithControlPlaneNodeSelectors(kubeClient, test.selectors)
 			hasControlPlaneNodes, err := clusterHost.HasControlPlaneNodes(context.Background())
 			require.Equal(t, test.wantErr, err)
 			require.Equal(t, test.wantReturnValue, hasControlPlaneNodes)
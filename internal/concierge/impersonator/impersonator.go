@@ -63,24 +63,76 @@ import (
 // That start function takes a stopCh which can be used to stop the server.
 // Once a server has been stopped, don't start it again using the start function.
 // Instead, call the factory function again to get a new start function.
+// After the stopCh is closed, in-flight requests are given up to shutdownTimeout to finish
+// before any remaining connections are forcibly closed.
+// The server listens on the given port of the given bindAddress, or of all interfaces when bindAddress is nil.
 type FactoryFunc func(
 	port int,
+	bindAddress net.IP,
 	dynamicCertProvider dynamiccert.Private,
 	impersonationProxySignerCA dynamiccert.Public,
+	shutdownTimeout time.Duration,
 ) (func(stopCh <-chan struct{}) error, error)
 
+// DefaultHealthCheckPath is the path at which New's impersonator servers answer health checks.
+const DefaultHealthCheckPath = "/healthz"
+
 func New(
 	port int,
+	bindAddress net.IP,
 	dynamicCertProvider dynamiccert.Private,
 	impersonationProxySignerCA dynamiccert.Public,
+	shutdownTimeout time.Duration,
 ) (func(stopCh <-chan struct{}) error, error) {
-	return newInternal(port, dynamicCertProvider, impersonationProxySignerCA, kubeclient.Secure, nil, nil, nil)
+	return NewWithOptions(DefaultHealthCheckPath, ListenerOptions{})(port, bindAddress, dynamicCertProvider, impersonationProxySignerCA, shutdownTimeout)
+}
+
+// ListenerOptions configures the connections which are accepted by the listener of an impersonator server.
+type ListenerOptions struct {
+	// MaxConnections is the maximum number of client connections which may be open at the same time. When it is
+	// reached, new connections are closed as soon as they are accepted, before their TLS handshake. Zero means that
+	// the number of connections is not limited.
+	MaxConnections int
+
+	// TCPKeepAlivePeriod is the period of the TCP keep-alive probes which are sent on idle client connections, so
+	// that connections to clients which went away without closing them are eventually closed. Zero means that the
+	// default period of the Go runtime is used.
+	TCPKeepAlivePeriod time.Duration
+
+	// MinTLSVersion is the name of the minimum TLS version which clients must use, e.g. VersionTLS13. Empty means
+	// that the default minimum version of the ptls package is used, i.e. TLS 1.2.
+	MinTLSVersion string
+
+	// CipherSuites are the names of the TLS 1.2 cipher suites which clients may use. Empty means that the default
+	// cipher suites of the ptls package are used. They are ignored when the minimum TLS version is TLS 1.3, since
+	// the cipher suites of TLS 1.3 are not configurable.
+	CipherSuites []string
+}
+
+// NewWithOptions returns a FactoryFunc which creates impersonator servers that answer health checks at the given
+// path and apply the given options to the connections which they accept. Health checks are answered without
+// authenticating the client, e.g. so that the health checks of a cloud load balancer succeed, and are never proxied
+// to the Kubernetes API server.
+func NewWithOptions(healthCheckPath string, listenerOptions ListenerOptions) FactoryFunc {
+	return func(
+		port int,
+		bindAddress net.IP,
+		dynamicCertProvider dynamiccert.Private,
+		impersonationProxySignerCA dynamiccert.Public,
+		shutdownTimeout time.Duration,
+	) (func(stopCh <-chan struct{}) error, error) {
+		return newInternal(port, bindAddress, dynamicCertProvider, impersonationProxySignerCA, shutdownTimeout, healthCheckPath, listenerOptions, kubeclient.Secure, nil, nil, nil)
+	}
 }
 
 func newInternal( //nolint:funlen // yeah, it's kind of long.
 	port int,
+	bindAddress net.IP, // nil means all interfaces
 	dynamicCertProvider dynamiccert.Private,
 	impersonationProxySignerCA dynamiccert.Public,
+	shutdownTimeout time.Duration,
+	healthCheckPath string,
+	listenerOptions ListenerOptions,
 	restConfigFunc ptls.RestConfigFunc, // for unit testing, should always be kubeclient.Secure in production
 	clientOpts []kubeclient.Option, // for unit testing, should always be nil in production
 	recOpts func(*genericoptions.RecommendedOptions), // for unit testing, should always be nil in production
@@ -104,6 +156,9 @@ func newInternal( //nolint:funlen // yeah, it's kind of long.
 		recommendedOptions.Etcd = nil                                                   // turn off etcd storage because we don't need it yet
 		recommendedOptions.SecureServing.ServerCert.GeneratedCert = dynamicCertProvider // serving certs (end user facing)
 		recommendedOptions.SecureServing.BindPort = port
+		if bindAddress != nil {
+			recommendedOptions.SecureServing.BindAddress = bindAddress
+		}
 
 		// secure TLS for connections coming from external clients and going to the Kube API server
 		// this is best effort because not all options provide the right hooks to override TLS config
@@ -111,6 +166,7 @@ func newInternal( //nolint:funlen // yeah, it's kind of long.
 		if err := ptls.DefaultRecommendedOptions(recommendedOptions, restConfigFunc); err != nil {
 			return nil, fmt.Errorf("failed to secure recommended options: %w", err)
 		}
+		applyTLSOptions(recommendedOptions.SecureServing, listenerOptions)
 
 		// Wire up the impersonation proxy signer CA as another valid authenticator for client cert auth,
 		// along with the Kube API server's CA.
@@ -148,6 +204,12 @@ func newInternal( //nolint:funlen // yeah, it's kind of long.
 			return nil, err
 		}
 
+		// Keep track of the accepted connections so that they can be forcibly closed when a graceful
+		// shutdown does not finish within the shutdown timeout, e.g. due to a hung client connection.
+		// This also limits the number of open connections and sets their keep-alive period, when configured.
+		connTracker := &connTrackingListener{Listener: listener, conns: map[net.Conn]struct{}{}, options: listenerOptions}
+		serverConfig.SecureServing.Listener = connTracker
+
 		// Loopback authentication to this server does not really make sense since we just proxy everything to
 		// the Kube API server, thus we replace loopback connection config with one that does direct connections
 		// the Kube API server. Loopback config is mainly used by post start hooks, so this is mostly future proofing.
@@ -203,6 +265,9 @@ func newInternal( //nolint:funlen // yeah, it's kind of long.
 			handler = withBearerTokenPreservation(handler)
 			handler = filterlatency.TrackStarted(handler, "bearertokenpreservation")
 
+			// Answer health checks before any authentication is attempted.
+			handler = withHealthCheck(handler, healthCheckPath)
+
 			// Always set security headers so browsers do the right thing.
 			handler = filterlatency.TrackCompleted(handler)
 			handler = securityheader.Wrap(handler)
@@ -308,6 +373,9 @@ func newInternal( //nolint:funlen // yeah, it's kind of long.
 			return nil, err
 		}
 
+		// Bound how long the underlying http.Server.Shutdown waits for in-flight requests to drain.
+		impersonationProxyServer.ShutdownTimeout = shutdownTimeout
+
 		preparedRun := impersonationProxyServer.PrepareRun()
 
 		// Sanity check. Make sure that our custom authenticator is still in place and did not get changed or wrapped.
@@ -325,7 +393,31 @@ func newInternal( //nolint:funlen // yeah, it's kind of long.
 			return nil, constable.Error("invalid impersonator loopback rest config has wrong bearer token semantics")
 		}
 
-		return preparedRun.Run, nil
+		return func(stopCh <-chan struct{}) error {
+			runErrCh := make(chan error, 1)
+			go func() {
+				runErrCh <- preparedRun.Run(stopCh)
+			}()
+
+			select {
+			case err := <-runErrCh:
+				return err
+			case <-stopCh:
+			}
+
+			timer := time.NewTimer(shutdownTimeout)
+			defer timer.Stop()
+
+			select {
+			case err := <-runErrCh:
+				return err
+			case <-timer.C:
+				plog.Info("impersonation proxy did not shut down gracefully, closing remaining connections",
+					"shutdownTimeout", shutdownTimeout)
+				connTracker.closeAll()
+				return <-runErrCh
+			}
+		}, nil
 	}
 
 	result, err := constructServer()
@@ -340,6 +432,89 @@ func newInternal( //nolint:funlen // yeah, it's kind of long.
 	return result, nil
 }
 
+// connTrackingListener is a net.Listener which remembers the connections that it has accepted
+// until they are closed, so that they can all be closed at once. It closes any connection which
+// would exceed the maximum number of open connections from its options instead of returning it.
+type connTrackingListener struct {
+	net.Listener
+	options ListenerOptions
+
+	lock  sync.Mutex
+	conns map[net.Conn]struct{}
+}
+
+func (l *connTrackingListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		if tcpConn, ok := conn.(*net.TCPConn); ok && l.options.TCPKeepAlivePeriod > 0 {
+			_ = tcpConn.SetKeepAlive(true)
+			_ = tcpConn.SetKeepAlivePeriod(l.options.TCPKeepAlivePeriod)
+		}
+		if tracked := l.track(conn); tracked != nil {
+			return tracked, nil
+		}
+		plog.Warning("rejecting impersonation proxy connection because the maximum number of connections are open",
+			"remoteAddr", conn.RemoteAddr().String(), "maxConnections", l.options.MaxConnections)
+		_ = conn.Close()
+	}
+}
+
+// track remembers the connection, unless the maximum number of connections are already open, in which case it
+// returns nil.
+func (l *connTrackingListener) track(conn net.Conn) net.Conn {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.options.MaxConnections > 0 && len(l.conns) >= l.options.MaxConnections {
+		return nil
+	}
+	tracked := &trackedConn{Conn: conn, listener: l}
+	l.conns[tracked] = struct{}{}
+	return tracked
+}
+
+func (l *connTrackingListener) closeAll() {
+	l.lock.Lock()
+	conns := make([]net.Conn, 0, len(l.conns))
+	for conn := range l.conns {
+		conns = append(conns, conn)
+	}
+	l.lock.Unlock()
+
+	for _, conn := range conns {
+		_ = conn.Close()
+	}
+}
+
+type trackedConn struct {
+	net.Conn
+	listener *connTrackingListener
+}
+
+func (c *trackedConn) Close() error {
+	c.listener.lock.Lock()
+	delete(c.listener.conns, c)
+	c.listener.lock.Unlock()
+	return c.Conn.Close()
+}
+
+// applyTLSOptions overrides the default TLS version and cipher suites of the serving options with the configured ones.
+// The names are validated by the generic API server when the options are applied. This only affects the TLS handshake,
+// so client certificates are still verified the same way.
+func applyTLSOptions(opts *genericoptions.SecureServingOptionsWithLoopback, listenerOptions ListenerOptions) {
+	if listenerOptions.MinTLSVersion != "" {
+		opts.MinTLSVersion = listenerOptions.MinTLSVersion
+	}
+	if len(listenerOptions.CipherSuites) > 0 {
+		opts.CipherSuites = listenerOptions.CipherSuites
+	}
+	if opts.MinTLSVersion == "VersionTLS13" {
+		opts.CipherSuites = nil
+	}
+}
+
 func getReverseProxyClient(clientOpts []kubeclient.Option) (*kubeclient.Client, error) {
 	// just use the overrides given during unit tests
 	if len(clientOpts) != 0 {
@@ -447,6 +622,21 @@ func withBearerTokenPreservation(delegate http.Handler) http.Handler {
 	})
 }
 
+// withHealthCheck responds to GET and HEAD requests for the health check path with a 200 status, without delegating
+// them, so that they never require any client credentials. The TLS handshake already allows clients to connect
+// without presenting a client certificate, so this does not change how other requests are authenticated.
+func withHealthCheck(delegate http.Handler, healthCheckPath string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != healthCheckPath || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+			delegate.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+}
+
 func tokenFrom(ctx context.Context) string {
 	token, _ := ctx.Value(tokenKey).(string)
 	return token
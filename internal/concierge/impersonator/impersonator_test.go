@@ -6,6 +6,7 @@ package impersonator
 import (
 	"context"
 	"fmt"
+	"io"
 	"math/rand"
 	"net"
 	"net/http"
@@ -845,7 +846,7 @@ func TestImpersonator(t *testing.T) {
 			}
 
 			// Create an impersonator.  Use an invalid port number to make sure our listener override works.
-			runner, constructionErr := newInternal(-1000, certKeyContent, caContent, restConfigFunc, clientOpts, recOpts, recConfig)
+			runner, constructionErr := newInternal(-1000, nil, certKeyContent, caContent, time.Second, "/some/health/check", ListenerOptions{}, restConfigFunc, clientOpts, recOpts, recConfig)
 			if len(tt.wantConstructionError) > 0 {
 				require.EqualError(t, constructionErr, tt.wantConstructionError)
 				require.Nil(t, runner)
@@ -1005,6 +1006,11 @@ func TestImpersonator(t *testing.T) {
 				require.Equal(t, `{"hello": "birds"}`, string(ducksBody))
 			}
 
+			// health checks are always answered by the impersonator itself, even when anonymous auth is disabled
+			healthBody, errHealth := rc.Get().AbsPath("/some/health/check").DoRaw(ctx)
+			require.NoError(t, errHealth)
+			require.Equal(t, "ok", string(healthBody))
+
 			// this should always fail as unauthorized (even for TCR) because the cert is not valid
 
 			badCertConfig := kubeclient.SecureAnonymousClientConfig(clientKubeconfig)
@@ -2041,6 +2047,109 @@ func Test_withBearerTokenPreservation(t *testing.T) {
 	}
 }
 
+func Test_connTrackingListener(t *testing.T) {
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	listener := &connTrackingListener{Listener: inner, conns: map[net.Conn]struct{}{}}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	acceptedCh := make(chan net.Conn, 2)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			acceptedCh <- conn
+		}
+	}()
+
+	clientConn1, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = clientConn1.Close() })
+	clientConn2, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = clientConn2.Close() })
+
+	serverConn1, serverConn2 := <-acceptedCh, <-acceptedCh
+
+	// Closing a connection directly forgets about it.
+	require.NoError(t, serverConn1.Close())
+	listener.lock.Lock()
+	require.Len(t, listener.conns, 1)
+	listener.lock.Unlock()
+
+	// Closing all connections closes the ones that are still open.
+	listener.closeAll()
+	listener.lock.Lock()
+	require.Empty(t, listener.conns)
+	listener.lock.Unlock()
+	_, err = serverConn2.Write([]byte("hello"))
+	require.ErrorIs(t, err, net.ErrClosed)
+}
+
+func Test_connTrackingListenerMaxConnections(t *testing.T) {
+	const maxConnections = 2
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	listener := &connTrackingListener{
+		Listener: inner,
+		conns:    map[net.Conn]struct{}{},
+		options:  ListenerOptions{MaxConnections: maxConnections, TCPKeepAlivePeriod: time.Minute},
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	acceptedCh := make(chan net.Conn, maxConnections+1)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			acceptedCh <- conn
+		}
+	}()
+
+	dial := func() net.Conn {
+		clientConn, err := net.Dial("tcp", listener.Addr().String())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = clientConn.Close() })
+		return clientConn
+	}
+	requireClosedByServer := func(clientConn net.Conn) {
+		require.NoError(t, clientConn.SetReadDeadline(time.Now().Add(10*time.Second)))
+		_, err := clientConn.Read(make([]byte, 1))
+		require.ErrorIs(t, err, io.EOF)
+	}
+
+	// The connections up to the limit are accepted.
+	serverConns := make([]net.Conn, 0, maxConnections)
+	for i := 0; i < maxConnections; i++ {
+		dial()
+		serverConns = append(serverConns, <-acceptedCh)
+	}
+
+	// More connections than the limit are closed without being returned by Accept.
+	for i := 0; i < 3; i++ {
+		requireClosedByServer(dial())
+	}
+	require.Empty(t, acceptedCh)
+	listener.lock.Lock()
+	require.Len(t, listener.conns, maxConnections)
+	listener.lock.Unlock()
+
+	// Once a connection is closed, another one may be accepted.
+	require.NoError(t, serverConns[0].Close())
+	clientConn := dial()
+	serverConn := <-acceptedCh
+	_, err = serverConn.Write([]byte("hello"))
+	require.NoError(t, err)
+	got := make([]byte, 5)
+	_, err = io.ReadFull(clientConn, got)
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(got))
+}
+
 type attributeRecorder struct {
 	lock       sync.Mutex
 	attributes []authorizer.AttributesRecord
@@ -2051,3 +2160,44 @@ func (r *attributeRecorder) record(attributes authorizer.Attributes) {
 	defer r.lock.Unlock()
 	r.attributes = append(r.attributes, *attributes.(*authorizer.AttributesRecord))
 }
+
+func Test_applyTLSOptions(t *testing.T) {
+	defaultCipherSuites := []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}
+
+	tests := []struct {
+		name              string
+		listenerOptions   ListenerOptions
+		wantMinTLSVersion string
+		wantCipherSuites  []string
+	}{
+		{
+			name:              "no TLS options keeps the defaults",
+			wantMinTLSVersion: "VersionTLS12",
+			wantCipherSuites:  defaultCipherSuites,
+		},
+		{
+			name:              "cipher suites",
+			listenerOptions:   ListenerOptions{CipherSuites: []string{"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256"}},
+			wantMinTLSVersion: "VersionTLS12",
+			wantCipherSuites:  []string{"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256"},
+		},
+		{
+			name:              "TLS 1.3 clears the cipher suites",
+			listenerOptions:   ListenerOptions{MinTLSVersion: "VersionTLS13"},
+			wantMinTLSVersion: "VersionTLS13",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			opts := genericoptions.NewSecureServingOptions().WithLoopback()
+			opts.MinTLSVersion = "VersionTLS12"
+			opts.CipherSuites = defaultCipherSuites
+
+			applyTLSOptions(opts, tt.listenerOptions)
+
+			require.Equal(t, tt.wantMinTLSVersion, opts.MinTLSVersion)
+			require.Equal(t, tt.wantCipherSuites, opts.CipherSuites)
+		})
+	}
+}
@@ -8,10 +8,12 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"time"
 
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
@@ -21,9 +23,11 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/component-base/logs"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
 
 	"go.pinniped.dev/internal/certauthority/dynamiccertauthority"
 	"go.pinniped.dev/internal/concierge/apiserver"
+	"go.pinniped.dev/internal/concierge/impersonator"
 	conciergescheme "go.pinniped.dev/internal/concierge/scheme"
 	"go.pinniped.dev/internal/config/concierge"
 	"go.pinniped.dev/internal/controller/authenticator/authncache"
@@ -129,6 +133,22 @@ func (a *App) runServer(ctx context.Context) error {
 	// cert issuer used to issue certs to Pinniped clients wishing to login.
 	impersonationProxySigningCertProvider := dynamiccert.NewCA("impersonation-proxy-signing-cert")
 
+	// These selectors were already validated by the config reader, so parsing them again should not fail.
+	var controlPlaneNodeSelectors []labels.Selector
+	for _, selector := range cfg.ImpersonationProxyControlPlaneNodeSelectors {
+		controlPlaneNodeSelector, err := labels.Parse(selector)
+		if err != nil {
+			return fmt.Errorf("could not parse impersonationProxyControlPlaneNodeSelectors: %w", err)
+		}
+		controlPlaneNodeSelectors = append(controlPlaneNodeSelectors, controlPlaneNodeSelector)
+	}
+
+	// This address was already validated by the config reader. When it is not set, bind all interfaces.
+	var bindAddress net.IP
+	if cfg.ImpersonationProxyBindAddress != nil && *cfg.ImpersonationProxyBindAddress != "" {
+		bindAddress = net.ParseIP(*cfg.ImpersonationProxyBindAddress)
+	}
+
 	// Get the "real" name of the login concierge API group (i.e., the API group name with the
 	// injected suffix).
 	scheme, loginGV, identityGV := conciergescheme.New(*cfg.APIGroupSuffix)
@@ -150,7 +170,19 @@ func (a *App) runServer(ctx context.Context) error {
 			ServingCertRenewBefore:           time.Duration(*cfg.APIConfig.ServingCertificateConfig.RenewBeforeSeconds) * time.Second,
 			AuthenticatorCache:               authenticators,
 			// This port should be safe to cast because the config reader already validated it.
-			ImpersonationProxyServerPort: int(*cfg.ImpersonationProxyServerPort),
+			ImpersonationProxyServerPort:                int(*cfg.ImpersonationProxyServerPort),
+			ImpersonationProxyBindAddress:               bindAddress,
+			ImpersonationProxyControlPlaneNodeSelectors: controlPlaneNodeSelectors,
+			ImpersonationProxyHealthCheckPath:           *cfg.ImpersonationProxyHealthCheckPath,
+			ImpersonationProxySetOwnerReferences:        cfg.ImpersonationProxySetOwnerReferences != nil && *cfg.ImpersonationProxySetOwnerReferences,
+			ImpersonationProxyAnnotateIssuedSecrets:     cfg.ImpersonationProxyAnnotateIssuedSecrets != nil && *cfg.ImpersonationProxyAnnotateIssuedSecrets,
+			ImpersonationProxyListenerOptions: impersonator.ListenerOptions{
+				// These should be safe to cast because the config reader already validated that they are not negative.
+				MaxConnections:     int(pointer.Int64Deref(cfg.ImpersonationProxyMaxConnections, 0)),
+				TCPKeepAlivePeriod: time.Duration(pointer.Int64Deref(cfg.ImpersonationProxyTCPKeepAliveSeconds, 0)) * time.Second,
+				MinTLSVersion:      pointer.StringDeref(cfg.ImpersonationProxyMinTLSVersion, ""),
+				CipherSuites:       cfg.ImpersonationProxyCipherSuites,
+			},
 		},
 	)
 	if err != nil {
@@ -6,14 +6,22 @@
 package concierge
 
 import (
+	"crypto/tls"
 	"fmt"
 	"io/ioutil"
+	"net"
+	"path"
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/yaml"
 
 	"go.pinniped.dev/internal/constable"
+	"go.pinniped.dev/internal/crypto/ptls"
 	"go.pinniped.dev/internal/groupsuffix"
 	"go.pinniped.dev/internal/plog"
 )
@@ -32,6 +40,10 @@ const (
 	// impersonation proxy, and has been the value since. It was originally selected because the
 	// aggregated API server used to run on 8443 (has since changed), so 8444 was the next available port.
 	impersonationProxyPortDefault = 8444
+
+	// Use the same path as the Kubernetes API server so that load balancer health checks which were
+	// configured for the API server also work for the impersonation proxy.
+	impersonationProxyHealthCheckPathDefault = "/healthz"
 )
 
 // FromPath loads an Config from a provided local file path, inserts any
@@ -55,6 +67,7 @@ func FromPath(path string) (*Config, error) {
 	maybeSetAPIDefaults(&config.APIConfig)
 	maybeSetAggregatedAPIServerPortDefaults(&config.AggregatedAPIServerPort)
 	maybeSetImpersonationProxyServerPortDefaults(&config.ImpersonationProxyServerPort)
+	maybeSetImpersonationProxyHealthCheckPathDefault(&config.ImpersonationProxyHealthCheckPath)
 	maybeSetAPIGroupSuffixDefault(&config.APIGroupSuffix)
 	maybeSetKubeCertAgentDefaults(&config.KubeCertAgentConfig)
 
@@ -74,6 +87,38 @@ func FromPath(path string) (*Config, error) {
 		return nil, fmt.Errorf("validate impersonationProxyServerPort: %w", err)
 	}
 
+	if err := validateHealthCheckPath(*config.ImpersonationProxyHealthCheckPath); err != nil {
+		return nil, fmt.Errorf("validate impersonationProxyHealthCheckPath: %w", err)
+	}
+
+	if err := validateBindAddress(config.ImpersonationProxyBindAddress); err != nil {
+		return nil, fmt.Errorf("validate impersonationProxyBindAddress: %w", err)
+	}
+
+	if err := validateNonNegative(config.ImpersonationProxyMaxConnections); err != nil {
+		return nil, fmt.Errorf("validate impersonationProxyMaxConnections: %w", err)
+	}
+
+	if err := validateNonNegative(config.ImpersonationProxyTCPKeepAliveSeconds); err != nil {
+		return nil, fmt.Errorf("validate impersonationProxyTCPKeepAliveSeconds: %w", err)
+	}
+
+	if err := validateMinTLSVersion(config.ImpersonationProxyMinTLSVersion); err != nil {
+		return nil, fmt.Errorf("validate impersonationProxyMinTLSVersion: %w", err)
+	}
+
+	if err := validateCipherSuites(config.ImpersonationProxyCipherSuites, config.ImpersonationProxyMinTLSVersion); err != nil {
+		return nil, fmt.Errorf("validate impersonationProxyCipherSuites: %w", err)
+	}
+
+	if err := validateControlPlaneNodeSelectors(config.ImpersonationProxyControlPlaneNodeSelectors); err != nil {
+		return nil, fmt.Errorf("validate impersonationProxyControlPlaneNodeSelectors: %w", err)
+	}
+
+	if err := validateKubeCertAgent(&config.KubeCertAgentConfig); err != nil {
+		return nil, fmt.Errorf("validate kubeCertAgent: %w", err)
+	}
+
 	if err := validateNames(&config.NamesConfig); err != nil {
 		return nil, fmt.Errorf("validate names: %w", err)
 	}
@@ -117,6 +162,12 @@ func maybeSetImpersonationProxyServerPortDefaults(port **int64) {
 	}
 }
 
+func maybeSetImpersonationProxyHealthCheckPathDefault(path **string) {
+	if *path == nil {
+		*path = pointer.StringPtr(impersonationProxyHealthCheckPathDefault)
+	}
+}
+
 func maybeSetKubeCertAgentDefaults(cfg *KubeCertAgentSpec) {
 	if cfg.NamePrefix == nil {
 		cfg.NamePrefix = pointer.StringPtr("pinniped-kube-cert-agent-")
@@ -125,6 +176,14 @@ func maybeSetKubeCertAgentDefaults(cfg *KubeCertAgentSpec) {
 	if cfg.Image == nil {
 		cfg.Image = pointer.StringPtr("debian:latest")
 	}
+
+	if cfg.ImagePullPolicy == "" {
+		cfg.ImagePullPolicy = corev1.PullIfNotPresent
+	}
+
+	if cfg.ControllerManagerNamespace == nil {
+		cfg.ControllerManagerNamespace = pointer.StringPtr("kube-system")
+	}
 }
 
 func validateNames(names *NamesConfigSpec) error {
@@ -181,6 +240,106 @@ func validateAPIGroupSuffix(apiGroupSuffix string) error {
 	return groupsuffix.Validate(apiGroupSuffix)
 }
 
+func validateHealthCheckPath(healthCheckPath string) error {
+	if !strings.HasPrefix(healthCheckPath, "/") || path.Clean(healthCheckPath) != healthCheckPath {
+		return constable.Error("must be an absolute and clean URL path, e.g. /healthz")
+	}
+	return nil
+}
+
+func validateBindAddress(bindAddress *string) error {
+	if bindAddress == nil || *bindAddress == "" {
+		return nil
+	}
+	if net.ParseIP(*bindAddress) == nil {
+		return constable.Error("must be an IP address, e.g. 0.0.0.0 or ::")
+	}
+	return nil
+}
+
+func validateNonNegative(value *int64) error {
+	if value != nil && *value < 0 {
+		return constable.Error("must not be negative")
+	}
+	return nil
+}
+
+func validateMinTLSVersion(version *string) error {
+	if version == nil {
+		return nil
+	}
+	switch *version {
+	case "VersionTLS12", "VersionTLS13":
+		return nil
+	default:
+		return fmt.Errorf("%q is invalid: must be one of VersionTLS12 or VersionTLS13", *version)
+	}
+}
+
+func validateCipherSuites(cipherSuites []string, minTLSVersion *string) error {
+	if len(cipherSuites) == 0 {
+		return nil
+	}
+	if minTLSVersion != nil && *minTLSVersion == "VersionTLS13" {
+		return constable.Error("must not be set when impersonationProxyMinTLSVersion is VersionTLS13, because the cipher suites of TLS 1.3 are not configurable")
+	}
+	// Only allow the secure cipher suites which are used by default.
+	allowed := sets.NewString()
+	for _, id := range ptls.Default(nil).CipherSuites {
+		allowed.Insert(tls.CipherSuiteName(id))
+	}
+	for i, name := range cipherSuites {
+		if !allowed.Has(name) {
+			return fmt.Errorf("entry %d %q is invalid: must be one of %s", i, name, strings.Join(allowed.List(), ", "))
+		}
+	}
+	return nil
+}
+
+func validateControlPlaneNodeSelectors(selectors []string) error {
+	for i, selector := range selectors {
+		if selector == "" {
+			return fmt.Errorf("entry %d must not be empty", i)
+		}
+		if _, err := labels.Parse(selector); err != nil {
+			return fmt.Errorf("entry %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func validateKubeCertAgent(agentConfig *KubeCertAgentSpec) error {
+	for i, name := range agentConfig.ImagePullSecrets {
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+			return fmt.Errorf("imagePullSecrets[%d] %q is invalid: %s", i, name, strings.Join(errs, "; "))
+		}
+	}
+	if errs := validation.IsDNS1123Label(*agentConfig.ControllerManagerNamespace); len(errs) > 0 {
+		return fmt.Errorf("controllerManagerNamespace %q is invalid: %s", *agentConfig.ControllerManagerNamespace, strings.Join(errs, "; "))
+	}
+	if name := agentConfig.PreferredControllerManagerPod; name != "" {
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+			return fmt.Errorf("preferredControllerManagerPod %q is invalid: %s", name, strings.Join(errs, "; "))
+		}
+	}
+	switch agentConfig.ImagePullPolicy {
+	case corev1.PullAlways, corev1.PullIfNotPresent, corev1.PullNever:
+	default:
+		return fmt.Errorf("imagePullPolicy %q is invalid: must be one of Always, IfNotPresent, or Never", agentConfig.ImagePullPolicy)
+	}
+	for k, v := range agentConfig.PodLabels {
+		if errs := append(validation.IsQualifiedName(k), validation.IsValidLabelValue(v)...); len(errs) > 0 {
+			return fmt.Errorf("podLabels %q is invalid: %s", k, strings.Join(errs, "; "))
+		}
+	}
+	for k := range agentConfig.PodAnnotations {
+		if errs := validation.IsQualifiedName(strings.ToLower(k)); len(errs) > 0 {
+			return fmt.Errorf("podAnnotations %q is invalid: %s", k, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
 func validateServerPort(port *int64) error {
 	// It cannot be below 1024 because the container is not running as root.
 	if *port < 1024 || *port > 65535 {
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/pointer"
 
 	"go.pinniped.dev/internal/here"
@@ -35,6 +36,15 @@ func TestFromPath(t *testing.T) {
 				apiGroupSuffix: some.suffix.com
 				aggregatedAPIServerPort: 12345
 				impersonationProxyServerPort: 4242
+				impersonationProxyControlPlaneNodeSelectors: [node-role.kubernetes.io/master, node-role.kubernetes.io/control-plane]
+				impersonationProxyHealthCheckPath: /some/health/check
+				impersonationProxyBindAddress: 10.0.0.1
+				impersonationProxySetOwnerReferences: true
+				impersonationProxyAnnotateIssuedSecrets: true
+				impersonationProxyMaxConnections: 1000
+				impersonationProxyTCPKeepAliveSeconds: 60
+				impersonationProxyMinTLSVersion: VersionTLS12
+				impersonationProxyCipherSuites: [TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256]
 				names:
 				  servingCertificateSecret: pinniped-concierge-api-tls-serving-certificate
 				  credentialIssuer: pinniped-config
@@ -55,6 +65,29 @@ func TestFromPath(t *testing.T) {
 				  namePrefix: kube-cert-agent-name-prefix-
 				  image: kube-cert-agent-image
 				  imagePullSecrets: [kube-cert-agent-image-pull-secret]
+				  imagePullPolicy: Always
+				  command: [pinniped-concierge-kube-cert-agent, sleep, --some-flag]
+				  tolerations:
+				  - key: example.com/some-taint
+				    operator: Exists
+				    effect: NoSchedule
+				  nodeSelector:
+				    example.com/some-node-label: some-value
+				  priorityClassName: some-priority-class
+				  controllerManagerNamespace: some-controller-manager-namespace
+				  preferredControllerManagerPod: kube-controller-manager-node-1
+				  podSecurityContext:
+				    runAsUser: 1234
+				  containerSecurityContext:
+				    readOnlyRootFilesystem: true
+				  podLabels:
+				    some-pod-label: some-pod-label-value
+				  podAnnotations:
+				    example.com/some-pod-annotation: some-pod-annotation-value
+				  readinessProbe:
+				    periodSeconds: 30
+				    exec:
+				      command: [some-command]
 				logLevel: debug
 			`),
 			wantConfig: &Config{
@@ -67,9 +100,18 @@ func TestFromPath(t *testing.T) {
 						RenewBeforeSeconds: pointer.Int64Ptr(2400),
 					},
 				},
-				APIGroupSuffix:               pointer.StringPtr("some.suffix.com"),
-				AggregatedAPIServerPort:      pointer.Int64Ptr(12345),
-				ImpersonationProxyServerPort: pointer.Int64Ptr(4242),
+				APIGroupSuffix:                              pointer.StringPtr("some.suffix.com"),
+				AggregatedAPIServerPort:                     pointer.Int64Ptr(12345),
+				ImpersonationProxyServerPort:                pointer.Int64Ptr(4242),
+				ImpersonationProxyControlPlaneNodeSelectors: []string{"node-role.kubernetes.io/master", "node-role.kubernetes.io/control-plane"},
+				ImpersonationProxyHealthCheckPath:           pointer.StringPtr("/some/health/check"),
+				ImpersonationProxyBindAddress:               pointer.StringPtr("10.0.0.1"),
+				ImpersonationProxySetOwnerReferences:        pointer.BoolPtr(true),
+				ImpersonationProxyAnnotateIssuedSecrets:     pointer.BoolPtr(true),
+				ImpersonationProxyMaxConnections:            pointer.Int64Ptr(1000),
+				ImpersonationProxyTCPKeepAliveSeconds:       pointer.Int64Ptr(60),
+				ImpersonationProxyMinTLSVersion:             pointer.StringPtr("VersionTLS12"),
+				ImpersonationProxyCipherSuites:              []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256"},
 				NamesConfig: NamesConfigSpec{
 					ServingCertificateSecret:          "pinniped-concierge-api-tls-serving-certificate",
 					CredentialIssuer:                  "pinniped-config",
@@ -89,6 +131,31 @@ func TestFromPath(t *testing.T) {
 					NamePrefix:       pointer.StringPtr("kube-cert-agent-name-prefix-"),
 					Image:            pointer.StringPtr("kube-cert-agent-image"),
 					ImagePullSecrets: []string{"kube-cert-agent-image-pull-secret"},
+					ImagePullPolicy:  corev1.PullAlways,
+					Command:          []string{"pinniped-concierge-kube-cert-agent", "sleep", "--some-flag"},
+					Tolerations: []corev1.Toleration{{
+						Key:      "example.com/some-taint",
+						Operator: corev1.TolerationOpExists,
+						Effect:   corev1.TaintEffectNoSchedule,
+					}},
+					NodeSelector:                  map[string]string{"example.com/some-node-label": "some-value"},
+					PriorityClassName:             "some-priority-class",
+					ControllerManagerNamespace:    pointer.StringPtr("some-controller-manager-namespace"),
+					PreferredControllerManagerPod: "kube-controller-manager-node-1",
+					PodSecurityContext: &corev1.PodSecurityContext{
+						RunAsUser: pointer.Int64Ptr(1234),
+					},
+					ContainerSecurityContext: &corev1.SecurityContext{
+						ReadOnlyRootFilesystem: pointer.BoolPtr(true),
+					},
+					ReadinessProbe: &corev1.Probe{
+						ProbeHandler: corev1.ProbeHandler{
+							Exec: &corev1.ExecAction{Command: []string{"some-command"}},
+						},
+						PeriodSeconds: 30,
+					},
+					PodLabels:      map[string]string{"some-pod-label": "some-pod-label-value"},
+					PodAnnotations: map[string]string{"example.com/some-pod-annotation": "some-pod-annotation-value"},
 				},
 				LogLevel: plog.LevelDebug,
 			},
@@ -112,9 +179,10 @@ func TestFromPath(t *testing.T) {
 				DiscoveryInfo: DiscoveryInfoSpec{
 					URL: nil,
 				},
-				APIGroupSuffix:               pointer.StringPtr("pinniped.dev"),
-				AggregatedAPIServerPort:      pointer.Int64Ptr(10250),
-				ImpersonationProxyServerPort: pointer.Int64Ptr(8444),
+				APIGroupSuffix:                    pointer.StringPtr("pinniped.dev"),
+				AggregatedAPIServerPort:           pointer.Int64Ptr(10250),
+				ImpersonationProxyServerPort:      pointer.Int64Ptr(8444),
+				ImpersonationProxyHealthCheckPath: pointer.StringPtr("/healthz"),
 				APIConfig: APIConfigSpec{
 					ServingCertificateConfig: ServingCertificateConfigSpec{
 						DurationSeconds:    pointer.Int64Ptr(60 * 60 * 24 * 365),    // about a year
@@ -134,8 +202,10 @@ func TestFromPath(t *testing.T) {
 				},
 				Labels: map[string]string{},
 				KubeCertAgentConfig: KubeCertAgentSpec{
-					NamePrefix: pointer.StringPtr("pinniped-kube-cert-agent-"),
-					Image:      pointer.StringPtr("debian:latest"),
+					NamePrefix:                 pointer.StringPtr("pinniped-kube-cert-agent-"),
+					Image:                      pointer.StringPtr("debian:latest"),
+					ImagePullPolicy:            corev1.PullIfNotPresent,
+					ControllerManagerNamespace: pointer.StringPtr("kube-system"),
 				},
 			},
 		},
@@ -361,6 +431,213 @@ func TestFromPath(t *testing.T) {
 			`),
 			wantError: "validate impersonationProxyServerPort: must be within range 1024 to 65535",
 		},
+		{
+			name: "ImpersonationProxyHealthCheckPath is relative",
+			yaml: here.Doc(`
+				---
+				impersonationProxyHealthCheckPath: healthz
+			`),
+			wantError: "validate impersonationProxyHealthCheckPath: must be an absolute and clean URL path, e.g. /healthz",
+		},
+		{
+			name: "ImpersonationProxyHealthCheckPath is not clean",
+			yaml: here.Doc(`
+				---
+				impersonationProxyHealthCheckPath: /healthz/
+			`),
+			wantError: "validate impersonationProxyHealthCheckPath: must be an absolute and clean URL path, e.g. /healthz",
+		},
+		{
+			name: "ImpersonationProxyMaxConnections is negative",
+			yaml: here.Doc(`
+				---
+				impersonationProxyMaxConnections: -1
+			`),
+			wantError: "validate impersonationProxyMaxConnections: must not be negative",
+		},
+		{
+			name: "ImpersonationProxyTCPKeepAliveSeconds is negative",
+			yaml: here.Doc(`
+				---
+				impersonationProxyTCPKeepAliveSeconds: -1
+			`),
+			wantError: "validate impersonationProxyTCPKeepAliveSeconds: must not be negative",
+		},
+		{
+			name: "ImpersonationProxyMinTLSVersion is too old",
+			yaml: here.Doc(`
+				---
+				impersonationProxyMinTLSVersion: VersionTLS11
+			`),
+			wantError: `validate impersonationProxyMinTLSVersion: "VersionTLS11" is invalid: must be one of VersionTLS12 or VersionTLS13`,
+		},
+		{
+			name: "ImpersonationProxyMinTLSVersion is not a TLS version name",
+			yaml: here.Doc(`
+				---
+				impersonationProxyMinTLSVersion: "1.2"
+			`),
+			wantError: `validate impersonationProxyMinTLSVersion: "1.2" is invalid: must be one of VersionTLS12 or VersionTLS13`,
+		},
+		{
+			name: "ImpersonationProxyCipherSuites contains an insecure cipher suite",
+			yaml: here.Doc(`
+				---
+				impersonationProxyCipherSuites: [TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, TLS_RSA_WITH_RC4_128_SHA]
+			`),
+			wantError: `validate impersonationProxyCipherSuites: entry 1 "TLS_RSA_WITH_RC4_128_SHA" is invalid: must be one of ` +
+				"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384, TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256, " +
+				"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384, TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256",
+		},
+		{
+			name: "ImpersonationProxyCipherSuites is set with TLS 1.3",
+			yaml: here.Doc(`
+				---
+				impersonationProxyMinTLSVersion: VersionTLS13
+				impersonationProxyCipherSuites: [TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256]
+			`),
+			wantError: "validate impersonationProxyCipherSuites: must not be set when impersonationProxyMinTLSVersion is VersionTLS13, because the cipher suites of TLS 1.3 are not configurable",
+		},
+		{
+			name: "ImpersonationProxyBindAddress is not an IP address",
+			yaml: here.Doc(`
+				---
+				impersonationProxyBindAddress: localhost
+			`),
+			wantError: "validate impersonationProxyBindAddress: must be an IP address, e.g. 0.0.0.0 or ::",
+		},
+		{
+			name: "ImpersonationProxyControlPlaneNodeSelectors has an invalid entry",
+			yaml: here.Doc(`
+				---
+				impersonationProxyControlPlaneNodeSelectors: [node-role.kubernetes.io/master, "example.com/role in control"]
+			`),
+			wantError: "validate impersonationProxyControlPlaneNodeSelectors: entry 1: unable to parse requirement: found 'control' expected: '('",
+		},
+		{
+			name: "ImpersonationProxyControlPlaneNodeSelectors has an empty entry",
+			yaml: here.Doc(`
+				---
+				impersonationProxyControlPlaneNodeSelectors: [""]
+			`),
+			wantError: "validate impersonationProxyControlPlaneNodeSelectors: entry 0 must not be empty",
+		},
+		{
+			name: "KubeCertAgent ImagePullSecrets has an invalid name",
+			yaml: here.Doc(`
+				---
+				names:
+				  servingCertificateSecret: pinniped-concierge-api-tls-serving-certificate
+				  credentialIssuer: pinniped-config
+				  apiService: pinniped-api
+				  impersonationLoadBalancerService: impersonationLoadBalancerService-value
+				  impersonationClusterIPService: impersonationClusterIPService-value
+				  impersonationTLSCertificateSecret: impersonationTLSCertificateSecret-value
+				  impersonationCACertificateSecret: impersonationCACertificateSecret-value
+				  impersonationSignerSecret: impersonationSignerSecret-value
+				  agentServiceAccount: agentServiceAccount-value
+				kubeCertAgent:
+				  imagePullSecrets: [good-secret, Bad_Secret]
+			`),
+			wantError: "validate kubeCertAgent: imagePullSecrets[1] \"Bad_Secret\" is invalid: a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')",
+		},
+		{
+			name: "KubeCertAgent PodLabels has an invalid value",
+			yaml: here.Doc(`
+				---
+				names:
+				  servingCertificateSecret: pinniped-concierge-api-tls-serving-certificate
+				  credentialIssuer: pinniped-config
+				  apiService: pinniped-api
+				  impersonationLoadBalancerService: impersonationLoadBalancerService-value
+				  impersonationClusterIPService: impersonationClusterIPService-value
+				  impersonationTLSCertificateSecret: impersonationTLSCertificateSecret-value
+				  impersonationCACertificateSecret: impersonationCACertificateSecret-value
+				  impersonationSignerSecret: impersonationSignerSecret-value
+				  agentServiceAccount: agentServiceAccount-value
+				kubeCertAgent:
+				  podLabels:
+				    some-label: "not a valid value"
+			`),
+			wantError: "validate kubeCertAgent: podLabels \"some-label\" is invalid: a valid label must be an empty string or consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyValue',  or 'my_value',  or '12345', regex used for validation is '(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?')",
+		},
+		{
+			name: "KubeCertAgent ImagePullPolicy is invalid",
+			yaml: here.Doc(`
+				---
+				names:
+				  servingCertificateSecret: pinniped-concierge-api-tls-serving-certificate
+				  credentialIssuer: pinniped-config
+				  apiService: pinniped-api
+				  impersonationLoadBalancerService: impersonationLoadBalancerService-value
+				  impersonationClusterIPService: impersonationClusterIPService-value
+				  impersonationTLSCertificateSecret: impersonationTLSCertificateSecret-value
+				  impersonationCACertificateSecret: impersonationCACertificateSecret-value
+				  impersonationSignerSecret: impersonationSignerSecret-value
+				  agentServiceAccount: agentServiceAccount-value
+				kubeCertAgent:
+				  imagePullPolicy: Sometimes
+			`),
+			wantError: "validate kubeCertAgent: imagePullPolicy \"Sometimes\" is invalid: must be one of Always, IfNotPresent, or Never",
+		},
+		{
+			name: "KubeCertAgent ControllerManagerNamespace is invalid",
+			yaml: here.Doc(`
+				---
+				names:
+				  servingCertificateSecret: pinniped-concierge-api-tls-serving-certificate
+				  credentialIssuer: pinniped-config
+				  apiService: pinniped-api
+				  impersonationLoadBalancerService: impersonationLoadBalancerService-value
+				  impersonationClusterIPService: impersonationClusterIPService-value
+				  impersonationTLSCertificateSecret: impersonationTLSCertificateSecret-value
+				  impersonationCACertificateSecret: impersonationCACertificateSecret-value
+				  impersonationSignerSecret: impersonationSignerSecret-value
+				  agentServiceAccount: agentServiceAccount-value
+				kubeCertAgent:
+				  controllerManagerNamespace: some.namespace
+			`),
+			wantError: "validate kubeCertAgent: controllerManagerNamespace \"some.namespace\" is invalid: a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')",
+		},
+		{
+			name: "KubeCertAgent PreferredControllerManagerPod is invalid",
+			yaml: here.Doc(`
+				---
+				names:
+				  servingCertificateSecret: pinniped-concierge-api-tls-serving-certificate
+				  credentialIssuer: pinniped-config
+				  apiService: pinniped-api
+				  impersonationLoadBalancerService: impersonationLoadBalancerService-value
+				  impersonationClusterIPService: impersonationClusterIPService-value
+				  impersonationTLSCertificateSecret: impersonationTLSCertificateSecret-value
+				  impersonationCACertificateSecret: impersonationCACertificateSecret-value
+				  impersonationSignerSecret: impersonationSignerSecret-value
+				  agentServiceAccount: agentServiceAccount-value
+				kubeCertAgent:
+				  preferredControllerManagerPod: Bad_Pod
+			`),
+			wantError: "validate kubeCertAgent: preferredControllerManagerPod \"Bad_Pod\" is invalid: a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')",
+		},
+		{
+			name: "KubeCertAgent PodAnnotations has an invalid key",
+			yaml: here.Doc(`
+				---
+				names:
+				  servingCertificateSecret: pinniped-concierge-api-tls-serving-certificate
+				  credentialIssuer: pinniped-config
+				  apiService: pinniped-api
+				  impersonationLoadBalancerService: impersonationLoadBalancerService-value
+				  impersonationClusterIPService: impersonationClusterIPService-value
+				  impersonationTLSCertificateSecret: impersonationTLSCertificateSecret-value
+				  impersonationCACertificateSecret: impersonationCACertificateSecret-value
+				  impersonationSignerSecret: impersonationSignerSecret-value
+				  agentServiceAccount: agentServiceAccount-value
+				kubeCertAgent:
+				  podAnnotations:
+				    "not/a/valid/key": some-value
+			`),
+			wantError: "validate kubeCertAgent: podAnnotations \"not/a/valid/key\" is invalid: a qualified name must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]') with an optional DNS subdomain prefix and '/' (e.g. 'example.com/MyName')",
+		},
 		{
 			name: "ZeroRenewBefore",
 			yaml: here.Doc(`
@@ -3,19 +3,32 @@
 
 package concierge
 
-import "go.pinniped.dev/internal/plog"
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"go.pinniped.dev/internal/plog"
+)
 
 // Config contains knobs to setup an instance of the Pinniped Concierge.
 type Config struct {
-	DiscoveryInfo                DiscoveryInfoSpec `json:"discovery"`
-	APIConfig                    APIConfigSpec     `json:"api"`
-	APIGroupSuffix               *string           `json:"apiGroupSuffix,omitempty"`
-	AggregatedAPIServerPort      *int64            `json:"aggregatedAPIServerPort"`
-	ImpersonationProxyServerPort *int64            `json:"impersonationProxyServerPort"`
-	NamesConfig                  NamesConfigSpec   `json:"names"`
-	KubeCertAgentConfig          KubeCertAgentSpec `json:"kubeCertAgent"`
-	Labels                       map[string]string `json:"labels"`
-	LogLevel                     plog.LogLevel     `json:"logLevel"`
+	DiscoveryInfo                               DiscoveryInfoSpec `json:"discovery"`
+	APIConfig                                   APIConfigSpec     `json:"api"`
+	APIGroupSuffix                              *string           `json:"apiGroupSuffix,omitempty"`
+	AggregatedAPIServerPort                     *int64            `json:"aggregatedAPIServerPort"`
+	ImpersonationProxyServerPort                *int64            `json:"impersonationProxyServerPort"`
+	ImpersonationProxyControlPlaneNodeSelectors []string          `json:"impersonationProxyControlPlaneNodeSelectors,omitempty"`
+	ImpersonationProxyHealthCheckPath           *string           `json:"impersonationProxyHealthCheckPath,omitempty"`
+	ImpersonationProxyBindAddress               *string           `json:"impersonationProxyBindAddress,omitempty"`
+	ImpersonationProxySetOwnerReferences        *bool             `json:"impersonationProxySetOwnerReferences,omitempty"`
+	ImpersonationProxyAnnotateIssuedSecrets     *bool             `json:"impersonationProxyAnnotateIssuedSecrets,omitempty"`
+	ImpersonationProxyMaxConnections            *int64            `json:"impersonationProxyMaxConnections,omitempty"`
+	ImpersonationProxyTCPKeepAliveSeconds       *int64            `json:"impersonationProxyTCPKeepAliveSeconds,omitempty"`
+	ImpersonationProxyMinTLSVersion             *string           `json:"impersonationProxyMinTLSVersion,omitempty"`
+	ImpersonationProxyCipherSuites              []string          `json:"impersonationProxyCipherSuites,omitempty"`
+	NamesConfig                                 NamesConfigSpec   `json:"names"`
+	KubeCertAgentConfig                         KubeCertAgentSpec `json:"kubeCertAgent"`
+	Labels                                      map[string]string `json:"labels"`
+	LogLevel                                    plog.LogLevel     `json:"logLevel"`
 }
 
 // DiscoveryInfoSpec contains configuration knobs specific to
@@ -75,7 +88,61 @@ type KubeCertAgentSpec struct {
 	// for this value is "debian:latest".
 	Image *string `json:"image"`
 
+	// Command overrides the command of the kube-cert-agent container. By default, the container sleeps
+	// forever using the pinniped-concierge-kube-cert-agent binary. The container image must still contain
+	// that binary, because it is used to read the cluster signing certificate and key.
+	Command []string `json:"command,omitempty"`
+
 	// ImagePullSecrets is a list of names of Kubernetes Secret objects that will be used as
-	// ImagePullSecrets on the kube-cert-agent pods.
-	ImagePullSecrets []string
+	// ImagePullSecrets on the kube-cert-agent pods. The Secrets must exist in the namespace of the
+	// kube-cert-agent pods, so each name must be a valid DNS-1123 subdomain.
+	ImagePullSecrets []string `json:"imagePullSecrets,omitempty"`
+
+	// ImagePullPolicy is the image pull policy of the kube-cert-agent container. It must be one of
+	// Always, IfNotPresent, or Never. The default for this value is IfNotPresent.
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// Tolerations are added to the tolerations which the kube-cert-agent pods copy from the
+	// kube-controller-manager pod.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// NodeSelector entries are merged on top of the node selector which the kube-cert-agent pods
+	// copy from the kube-controller-manager pod.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// PriorityClassName is the name of the PriorityClass of the kube-cert-agent pods. By default,
+	// no PriorityClass is set.
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// ControllerManagerNamespace is the namespace in which the kube-controller-manager pods run, which
+	// must be a valid DNS-1123 label. The Concierge must be allowed to read pods in this namespace. The
+	// default for this value is "kube-system".
+	ControllerManagerNamespace *string `json:"controllerManagerNamespace,omitempty"`
+
+	// PreferredControllerManagerPod is the name of a kube-controller-manager pod in the ControllerManagerNamespace
+	// which the kube-cert-agent pods should be co-located with whenever it is running, e.g. on clusters where
+	// only some of the control plane nodes are configured with the cluster signing key. When that pod is not
+	// running, or by default, a kube-controller-manager pod is chosen automatically.
+	PreferredControllerManagerPod string `json:"preferredControllerManagerPod,omitempty"`
+
+	// PodSecurityContext overrides the pod-level security context of the kube-cert-agent pods. By default,
+	// the pods run as root, because the cluster signing key is usually only readable by root, and use the
+	// RuntimeDefault seccomp profile.
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+
+	// ContainerSecurityContext overrides the container-level security context of the kube-cert-agent pods.
+	// By default, the container drops all capabilities, does not allow privilege escalation, and has a
+	// read-only root filesystem.
+	ContainerSecurityContext *corev1.SecurityContext `json:"containerSecurityContext,omitempty"`
+
+	// ReadinessProbe overrides the readiness probe of the kube-cert-agent pods. By default, the pods
+	// are ready once they can read the cluster signing certificate and key files.
+	ReadinessProbe *corev1.Probe `json:"readinessProbe,omitempty"`
+
+	// PodLabels are applied to the kube-cert-agent pods, in addition to the labels which are applied
+	// to all Concierge resources. They cannot replace the label used to select the kube-cert-agent pods.
+	PodLabels map[string]string `json:"podLabels,omitempty"`
+
+	// PodAnnotations are applied to the kube-cert-agent pods.
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
 }
@@ -6,10 +6,18 @@
 package supervisor
 
 import (
+	"crypto/tls"
 	"fmt"
 	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/yaml"
 
@@ -22,17 +30,39 @@ const (
 	NetworkDisabled = "disabled"
 	NetworkUnix     = "unix"
 	NetworkTCP      = "tcp"
+	NetworkTCP4     = "tcp4"
+	NetworkTCP6     = "tcp6"
+
+	// defaultReadHeaderTimeout protects the endpoints from clients which open connections but never finish
+	// sending their request headers.
+	defaultReadHeaderTimeout = 10 * time.Second
+
+	// defaultShutdownGracePeriod leaves time for active connections to finish within the default termination
+	// grace period of a pod.
+	defaultShutdownGracePeriod = 20 * time.Second
+
+	TLSVersion12 = "1.2"
+	TLSVersion13 = "1.3"
 )
 
 // FromPath loads an Config from a provided local file path, inserts any
 // defaults (from the Config documentation), and verifies that the config is
 // valid (Config documentation).
+//
+// Before the file is parsed, any ${VAR} or $VAR references in it are replaced
+// by the values of those environment variables, and $$ is replaced by a
+// literal $. It is an error to reference an environment variable which is not set.
 func FromPath(path string) (*Config, error) {
 	data, err := ioutil.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("read file: %w", err)
 	}
 
+	data, err = expandEnv(data)
+	if err != nil {
+		return nil, fmt.Errorf("expand environment variables: %w", err)
+	}
+
 	var config Config
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("decode yaml: %w", err)
@@ -44,43 +74,155 @@ func FromPath(path string) (*Config, error) {
 
 	maybeSetAPIGroupSuffixDefault(&config.APIGroupSuffix)
 
-	if err := validateAPIGroupSuffix(*config.APIGroupSuffix); err != nil {
-		return nil, fmt.Errorf("validate apiGroupSuffix: %w", err)
+	// support setting this to null or {} or empty in the YAML
+	if config.Endpoints == nil {
+		config.Endpoints = &Endpoints{}
 	}
 
-	if err := validateNames(&config.NamesConfig); err != nil {
-		return nil, fmt.Errorf("validate names: %w", err)
+	maybeSetEndpointDefault(&config.Endpoints.HTTPS, defaultHTTPSEndpoint())
+	maybeSetEndpointDefault(&config.Endpoints.HTTP, defaultHTTPEndpoint())
+	maybeSetEndpointDefault(&config.Endpoints.Metrics, defaultMetricsEndpoint())
+	maybeSetEndpointTimeoutsDefaults(&config.Endpoints.Timeouts)
+	maybeSetShutdownGracePeriodDefault(&config.ShutdownGracePeriod)
+
+	if err := Validate(&config); err != nil {
+		return nil, err
 	}
 
 	if err := plog.ValidateAndSetLogLevelGlobally(config.LogLevel); err != nil {
 		return nil, fmt.Errorf("validate log level: %w", err)
 	}
 
-	// support setting this to null or {} or empty in the YAML
-	if config.Endpoints == nil {
-		config.Endpoints = &Endpoints{}
+	return &config, nil
+}
+
+// Validate verifies that the provided Config is valid (Config documentation)
+// without starting anything or changing any global state, so that a proposed
+// Config can be checked ahead of time. This is the same validation which is
+// performed by FromPath. Optional fields which are not set are treated as if
+// they had their default values. As a side effect, the parsed forms of the
+// TLS settings are filled in.
+func Validate(config *Config) error {
+	if config.APIGroupSuffix != nil {
+		if err := validateAPIGroupSuffix(*config.APIGroupSuffix); err != nil {
+			return fmt.Errorf("validate apiGroupSuffix: %w", err)
+		}
+	}
+
+	if err := validateLabels(config.Labels); err != nil {
+		return fmt.Errorf("validate labels: %w", err)
 	}
 
-	maybeSetEndpointDefault(&config.Endpoints.HTTPS, Endpoint{
+	if err := validateNames(&config.NamesConfig); err != nil {
+		return fmt.Errorf("validate names: %w", err)
+	}
+
+	if err := plog.ValidateLogLevel(config.LogLevel); err != nil {
+		return fmt.Errorf("validate log level: %w", err)
+	}
+
+	https, http, metrics := defaultHTTPSEndpoint(), defaultHTTPEndpoint(), defaultMetricsEndpoint()
+	var timeouts *EndpointTimeouts
+	var allowInsecureHTTP bool
+	if config.Endpoints != nil {
+		if config.Endpoints.HTTPS != nil {
+			https = *config.Endpoints.HTTPS
+		}
+		if config.Endpoints.HTTP != nil {
+			http = *config.Endpoints.HTTP
+		}
+		if config.Endpoints.Metrics != nil {
+			metrics = *config.Endpoints.Metrics
+		}
+		timeouts = config.Endpoints.Timeouts
+		allowInsecureHTTP = config.Endpoints.AllowInsecureHTTP
+	}
+
+	if err := validateEndpoint(https); err != nil {
+		return fmt.Errorf("validate https endpoint: %w", err)
+	}
+	if err := validateEndpoint(http); err != nil {
+		return fmt.Errorf("validate http endpoint: %w", err)
+	}
+	if err := validateEndpoint(metrics); err != nil {
+		return fmt.Errorf("validate metrics endpoint: %w", err)
+	}
+	if err := validateAtLeastOneEnabledEndpoint(https, http); err != nil {
+		return fmt.Errorf("validate endpoints: %w", err)
+	}
+	if err := validateInsecureHTTPAllowed(https, http, allowInsecureHTTP); err != nil {
+		return fmt.Errorf("validate endpoints: %w", err)
+	}
+
+	if timeouts != nil {
+		if err := validateEndpointTimeouts(timeouts); err != nil {
+			return fmt.Errorf("validate endpoint timeouts: %w", err)
+		}
+	}
+
+	if gracePeriod := config.ShutdownGracePeriod; gracePeriod != nil && gracePeriod.Duration < 0 {
+		return fmt.Errorf("validate shutdownGracePeriod: must not be negative, got %q", gracePeriod.Duration)
+	}
+
+	if err := parseTLS(config.TLS); err != nil {
+		return fmt.Errorf("validate tls: %w", err)
+	}
+
+	if threshold := config.UpstreamOIDCDiscoveryFailureThreshold; threshold != nil && *threshold < 1 {
+		return fmt.Errorf("validate upstreamOIDCDiscoveryFailureThreshold: must be at least 1, got %d", *threshold)
+	}
+
+	if pattern := config.UpstreamOIDCAllowedIssuerPattern; pattern != nil {
+		if _, err := regexp.Compile(anchoredPattern(*pattern)); err != nil {
+			return fmt.Errorf("validate upstreamOIDCAllowedIssuerPattern: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// anchoredPattern returns a regular expression which only matches when the given one matches the entire input.
+func anchoredPattern(pattern string) string {
+	return `^(?:` + pattern + `)$`
+}
+
+func expandEnv(data []byte) ([]byte, error) {
+	var undefined []string
+	expanded := os.Expand(string(data), func(name string) string {
+		if name == "$" {
+			return "$" // allow $$ to be used for a literal $
+		}
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			undefined = append(undefined, name)
+		}
+		return value
+	})
+	if len(undefined) > 0 {
+		sort.Strings(undefined)
+		return nil, fmt.Errorf("undefined environment variables: %s", strings.Join(undefined, ", "))
+	}
+	return []byte(expanded), nil
+}
+
+func defaultHTTPSEndpoint() Endpoint {
+	return Endpoint{
 		Network: NetworkTCP,
 		Address: ":8443",
-	})
-	maybeSetEndpointDefault(&config.Endpoints.HTTP, Endpoint{
+	}
+}
+
+func defaultHTTPEndpoint() Endpoint {
+	return Endpoint{
 		Network: NetworkTCP,
 		Address: ":8080",
-	})
-
-	if err := validateEndpoint(*config.Endpoints.HTTPS); err != nil {
-		return nil, fmt.Errorf("validate https endpoint: %w", err)
-	}
-	if err := validateEndpoint(*config.Endpoints.HTTP); err != nil {
-		return nil, fmt.Errorf("validate http endpoint: %w", err)
-	}
-	if err := validateAtLeastOneEnabledEndpoint(*config.Endpoints.HTTPS, *config.Endpoints.HTTP); err != nil {
-		return nil, fmt.Errorf("validate endpoints: %w", err)
 	}
+}
 
-	return &config, nil
+func defaultMetricsEndpoint() Endpoint {
+	return Endpoint{
+		Network: NetworkDisabled,
+	}
 }
 
 func maybeSetEndpointDefault(endpoint **Endpoint, defaultEndpoint Endpoint) {
@@ -90,6 +232,21 @@ func maybeSetEndpointDefault(endpoint **Endpoint, defaultEndpoint Endpoint) {
 	*endpoint = &defaultEndpoint
 }
 
+func maybeSetEndpointTimeoutsDefaults(timeouts **EndpointTimeouts) {
+	if *timeouts == nil {
+		*timeouts = &EndpointTimeouts{}
+	}
+	if (*timeouts).ReadHeaderTimeout == nil {
+		(*timeouts).ReadHeaderTimeout = &metav1.Duration{Duration: defaultReadHeaderTimeout}
+	}
+}
+
+func maybeSetShutdownGracePeriodDefault(gracePeriod **metav1.Duration) {
+	if *gracePeriod == nil {
+		*gracePeriod = &metav1.Duration{Duration: defaultShutdownGracePeriod}
+	}
+}
+
 func maybeSetAPIGroupSuffixDefault(apiGroupSuffix **string) {
 	if *apiGroupSuffix == nil {
 		*apiGroupSuffix = pointer.StringPtr(groupsuffix.PinnipedDefaultSuffix)
@@ -108,12 +265,40 @@ func validateNames(names *NamesConfigSpec) error {
 	if len(missingNames) > 0 {
 		return constable.Error("missing required names: " + strings.Join(missingNames, ", "))
 	}
+	seen := sets.NewString(names.DefaultTLSCertificateSecret)
+	for _, fallback := range names.DefaultTLSCertificateSecretFallbacks {
+		if fallback == "" {
+			return constable.Error("defaultTLSCertificateSecretFallbacks must not contain empty names")
+		}
+		if seen.Has(fallback) {
+			return fmt.Errorf("defaultTLSCertificateSecretFallbacks contains duplicate name %q", fallback)
+		}
+		seen.Insert(fallback)
+	}
+	return nil
+}
+
+func validateLabels(labels map[string]string) error {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys) // report the same error every time when there are several invalid labels
+
+	for _, k := range keys {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return fmt.Errorf("invalid key %q: %s", k, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(labels[k]); len(errs) > 0 {
+			return fmt.Errorf("invalid value %q for key %q: %s", labels[k], k, strings.Join(errs, "; "))
+		}
+	}
 	return nil
 }
 
 func validateEndpoint(endpoint Endpoint) error {
 	switch n := endpoint.Network; n {
-	case NetworkTCP, NetworkUnix:
+	case NetworkTCP, NetworkTCP4, NetworkTCP6, NetworkUnix:
 		if len(endpoint.Address) == 0 {
 			return fmt.Errorf("address must be set with %q network", n)
 		}
@@ -128,6 +313,75 @@ func validateEndpoint(endpoint Endpoint) error {
 	}
 }
 
+func parseTLS(tlsSpec *TLSSpec) error {
+	if tlsSpec == nil {
+		return nil
+	}
+
+	switch tlsSpec.MinVersion {
+	case "", TLSVersion12:
+		tlsSpec.ParsedMinVersion = tls.VersionTLS12
+	case TLSVersion13:
+		tlsSpec.ParsedMinVersion = tls.VersionTLS13
+	default:
+		return fmt.Errorf("unknown minVersion %q, must be %q or %q", tlsSpec.MinVersion, TLSVersion12, TLSVersion13)
+	}
+
+	if len(tlsSpec.CipherSuites) > 0 && tlsSpec.ParsedMinVersion == tls.VersionTLS13 {
+		return fmt.Errorf("cipherSuites cannot be set when minVersion is %q", TLSVersion13)
+	}
+
+	tlsSpec.ParsedCipherSuites = nil
+	for _, name := range tlsSpec.CipherSuites {
+		suite := cipherSuiteByName(name)
+		if suite == nil {
+			return fmt.Errorf("unknown cipher suite %q", name)
+		}
+		if !supportsTLS12(suite) {
+			return fmt.Errorf("cipher suite %q cannot be configured because it is only used by TLS 1.3", name)
+		}
+		tlsSpec.ParsedCipherSuites = append(tlsSpec.ParsedCipherSuites, suite.ID)
+	}
+
+	return nil
+}
+
+// cipherSuiteByName only looks at the cipher suites which Go does not consider to be insecure.
+func cipherSuiteByName(name string) *tls.CipherSuite {
+	for _, suite := range tls.CipherSuites() {
+		if suite.Name == name {
+			return suite
+		}
+	}
+	return nil
+}
+
+func supportsTLS12(suite *tls.CipherSuite) bool {
+	for _, version := range suite.SupportedVersions {
+		if version == tls.VersionTLS12 {
+			return true
+		}
+	}
+	return false
+}
+
+func validateEndpointTimeouts(timeouts *EndpointTimeouts) error {
+	for _, timeout := range []struct {
+		name  string
+		value *metav1.Duration
+	}{
+		{name: "readTimeout", value: timeouts.ReadTimeout},
+		{name: "readHeaderTimeout", value: timeouts.ReadHeaderTimeout},
+		{name: "writeTimeout", value: timeouts.WriteTimeout},
+		{name: "idleTimeout", value: timeouts.IdleTimeout},
+	} {
+		if timeout.value != nil && timeout.value.Duration < 0 {
+			return fmt.Errorf("%s must not be negative, got %q", timeout.name, timeout.value.Duration)
+		}
+	}
+	return nil
+}
+
 func validateAtLeastOneEnabledEndpoint(endpoints ...Endpoint) error {
 	for _, endpoint := range endpoints {
 		if endpoint.Network != NetworkDisabled {
@@ -136,3 +390,11 @@ func validateAtLeastOneEnabledEndpoint(endpoints ...Endpoint) error {
 	}
 	return constable.Error("all endpoints are disabled")
 }
+
+// validateInsecureHTTPAllowed rejects serving only over plain HTTP, unless that was explicitly allowed.
+func validateInsecureHTTPAllowed(https, http Endpoint, allowInsecureHTTP bool) error {
+	if https.Network == NetworkDisabled && http.Network != NetworkDisabled && !allowInsecureHTTP {
+		return constable.Error("http endpoint is enabled while https endpoint is disabled, set allowInsecureHTTP to true to allow this")
+	}
+	return nil
+}
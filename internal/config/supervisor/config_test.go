@@ -4,10 +4,13 @@
 package supervisor
 
 import (
+	"crypto/tls"
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 
 	"github.com/stretchr/testify/require"
@@ -19,6 +22,7 @@ func TestFromPath(t *testing.T) {
 	tests := []struct {
 		name       string
 		yaml       string
+		env        map[string]string
 		wantConfig *Config
 		wantError  string
 	}{
@@ -32,12 +36,14 @@ func TestFromPath(t *testing.T) {
 				  myLabelKey2: myLabelValue2
 				names:
 				  defaultTLSCertificateSecret: my-secret-name
+				  defaultTLSCertificateSecretFallbacks: [my-fallback-secret-name]
 				endpoints:
 				  https:
 				    network: unix
 				    address: :1234
 				  http:
 				    network: disabled
+				shutdownGracePeriod: 45s
 			`),
 			wantConfig: &Config{
 				APIGroupSuffix: pointer.StringPtr("some.suffix.com"),
@@ -46,9 +52,13 @@ func TestFromPath(t *testing.T) {
 					"myLabelKey2": "myLabelValue2",
 				},
 				NamesConfig: NamesConfigSpec{
-					DefaultTLSCertificateSecret: "my-secret-name",
+					DefaultTLSCertificateSecret:          "my-secret-name",
+					DefaultTLSCertificateSecretFallbacks: []string{"my-fallback-secret-name"},
 				},
 				Endpoints: &Endpoints{
+					Timeouts: &EndpointTimeouts{
+						ReadHeaderTimeout: &metav1.Duration{Duration: 10 * time.Second},
+					},
 					HTTPS: &Endpoint{
 						Network: "unix",
 						Address: ":1234",
@@ -56,23 +66,457 @@ func TestFromPath(t *testing.T) {
 					HTTP: &Endpoint{
 						Network: "disabled",
 					},
+					Metrics: &Endpoint{
+						Network: "disabled",
+					},
+				},
+				ShutdownGracePeriod: &metav1.Duration{Duration: 45 * time.Second},
+			},
+		},
+		{
+			name: "When only the required fields are present, causes other fields to be defaulted",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+			`),
+			wantConfig: &Config{
+				APIGroupSuffix: pointer.StringPtr("pinniped.dev"),
+				Labels:         map[string]string{},
+				NamesConfig: NamesConfigSpec{
+					DefaultTLSCertificateSecret: "my-secret-name",
+				},
+				Endpoints: &Endpoints{
+					Timeouts: &EndpointTimeouts{
+						ReadHeaderTimeout: &metav1.Duration{Duration: 10 * time.Second},
+					},
+					HTTPS: &Endpoint{
+						Network: "tcp",
+						Address: ":8443",
+					},
+					HTTP: &Endpoint{
+						Network: "tcp",
+						Address: ":8080",
+					},
+					Metrics: &Endpoint{
+						Network: "disabled",
+					},
+				},
+				ShutdownGracePeriod: &metav1.Duration{Duration: 20 * time.Second},
+			},
+		},
+		{
+			name: "tcp4 and tcp6 endpoints",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+				endpoints:
+				  https:
+				    network: tcp6
+				    address: "[::]:8443"
+				  http:
+				    network: tcp4
+				    address: 127.0.0.1:8080
+			`),
+			wantConfig: &Config{
+				APIGroupSuffix: pointer.StringPtr("pinniped.dev"),
+				Labels:         map[string]string{},
+				NamesConfig: NamesConfigSpec{
+					DefaultTLSCertificateSecret: "my-secret-name",
+				},
+				Endpoints: &Endpoints{
+					Timeouts: &EndpointTimeouts{
+						ReadHeaderTimeout: &metav1.Duration{Duration: 10 * time.Second},
+					},
+					HTTPS: &Endpoint{
+						Network: "tcp6",
+						Address: "[::]:8443",
+					},
+					HTTP: &Endpoint{
+						Network: "tcp4",
+						Address: "127.0.0.1:8080",
+					},
+					Metrics: &Endpoint{
+						Network: "disabled",
+					},
+				},
+				ShutdownGracePeriod: &metav1.Duration{Duration: 20 * time.Second},
+			},
+		},
+		{
+			name: "http only with allowInsecureHTTP",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+				endpoints:
+				  https:
+				    network: disabled
+				  http:
+				    network: tcp
+				    address: :8080
+				  allowInsecureHTTP: true
+			`),
+			wantConfig: &Config{
+				APIGroupSuffix: pointer.StringPtr("pinniped.dev"),
+				Labels:         map[string]string{},
+				NamesConfig: NamesConfigSpec{
+					DefaultTLSCertificateSecret: "my-secret-name",
+				},
+				Endpoints: &Endpoints{
+					Timeouts: &EndpointTimeouts{
+						ReadHeaderTimeout: &metav1.Duration{Duration: 10 * time.Second},
+					},
+					HTTPS: &Endpoint{
+						Network: "disabled",
+					},
+					HTTP: &Endpoint{
+						Network: "tcp",
+						Address: ":8080",
+					},
+					Metrics: &Endpoint{
+						Network: "disabled",
+					},
+					AllowInsecureHTTP: true,
+				},
+				ShutdownGracePeriod: &metav1.Duration{Duration: 20 * time.Second},
+			},
+		},
+		{
+			name: "metrics endpoint",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+				endpoints:
+				  metrics:
+				    network: tcp
+				    address: :9090
+			`),
+			wantConfig: &Config{
+				APIGroupSuffix: pointer.StringPtr("pinniped.dev"),
+				Labels:         map[string]string{},
+				NamesConfig: NamesConfigSpec{
+					DefaultTLSCertificateSecret: "my-secret-name",
+				},
+				Endpoints: &Endpoints{
+					Timeouts: &EndpointTimeouts{
+						ReadHeaderTimeout: &metav1.Duration{Duration: 10 * time.Second},
+					},
+					HTTPS: &Endpoint{
+						Network: "tcp",
+						Address: ":8443",
+					},
+					HTTP: &Endpoint{
+						Network: "tcp",
+						Address: ":8080",
+					},
+					Metrics: &Endpoint{
+						Network: "tcp",
+						Address: ":9090",
+					},
+				},
+				ShutdownGracePeriod: &metav1.Duration{Duration: 20 * time.Second},
+			},
+		},
+		{
+			name: "tls settings",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+				tls:
+				  minVersion: "1.2"
+				  cipherSuites: [TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384, TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256]
+			`),
+			wantConfig: &Config{
+				APIGroupSuffix: pointer.StringPtr("pinniped.dev"),
+				Labels:         map[string]string{},
+				NamesConfig: NamesConfigSpec{
+					DefaultTLSCertificateSecret: "my-secret-name",
+				},
+				Endpoints: &Endpoints{
+					Timeouts: &EndpointTimeouts{
+						ReadHeaderTimeout: &metav1.Duration{Duration: 10 * time.Second},
+					},
+					HTTPS: &Endpoint{
+						Network: "tcp",
+						Address: ":8443",
+					},
+					HTTP: &Endpoint{
+						Network: "tcp",
+						Address: ":8080",
+					},
+					Metrics: &Endpoint{
+						Network: "disabled",
+					},
+				},
+				TLS: &TLSSpec{
+					MinVersion:         "1.2",
+					CipherSuites:       []string{"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384", "TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256"},
+					ParsedMinVersion:   tls.VersionTLS12,
+					ParsedCipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384, tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305},
+				},
+				ShutdownGracePeriod: &metav1.Duration{Duration: 20 * time.Second},
+			},
+		},
+		{
+			name: "tls 1.3 only",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+				tls:
+				  minVersion: "1.3"
+			`),
+			wantConfig: &Config{
+				APIGroupSuffix: pointer.StringPtr("pinniped.dev"),
+				Labels:         map[string]string{},
+				NamesConfig: NamesConfigSpec{
+					DefaultTLSCertificateSecret: "my-secret-name",
+				},
+				Endpoints: &Endpoints{
+					Timeouts: &EndpointTimeouts{
+						ReadHeaderTimeout: &metav1.Duration{Duration: 10 * time.Second},
+					},
+					HTTPS: &Endpoint{
+						Network: "tcp",
+						Address: ":8443",
+					},
+					HTTP: &Endpoint{
+						Network: "tcp",
+						Address: ":8080",
+					},
+					Metrics: &Endpoint{
+						Network: "disabled",
+					},
+				},
+				TLS: &TLSSpec{
+					MinVersion:       "1.3",
+					ParsedMinVersion: tls.VersionTLS13,
+				},
+				ShutdownGracePeriod: &metav1.Duration{Duration: 20 * time.Second},
+			},
+		},
+		{
+			name: "tls unknown min version",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+				tls:
+				  minVersion: "1.1"
+			`),
+			wantError: `validate tls: unknown minVersion "1.1", must be "1.2" or "1.3"`,
+		},
+		{
+			name: "tls unknown cipher suite",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+				tls:
+				  cipherSuites: [TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, TLS_NOT_A_REAL_CIPHER]
+			`),
+			wantError: `validate tls: unknown cipher suite "TLS_NOT_A_REAL_CIPHER"`,
+		},
+		{
+			name: "tls insecure cipher suite",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+				tls:
+				  cipherSuites: [TLS_RSA_WITH_RC4_128_SHA]
+			`),
+			wantError: `validate tls: unknown cipher suite "TLS_RSA_WITH_RC4_128_SHA"`,
+		},
+		{
+			name: "tls 1.3 cipher suite",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+				tls:
+				  cipherSuites: [TLS_AES_128_GCM_SHA256]
+			`),
+			wantError: `validate tls: cipher suite "TLS_AES_128_GCM_SHA256" cannot be configured because it is only used by TLS 1.3`,
+		},
+		{
+			name: "tls cipher suites with tls 1.3",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+				tls:
+				  minVersion: "1.3"
+				  cipherSuites: [TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256]
+			`),
+			wantError: `validate tls: cipherSuites cannot be set when minVersion is "1.3"`,
+		},
+		{
+			name: "endpoint timeouts",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+				endpoints:
+				  timeouts:
+				    readTimeout: 1m
+				    readHeaderTimeout: 5s
+				    writeTimeout: 2m
+				    idleTimeout: 0s
+			`),
+			wantConfig: &Config{
+				APIGroupSuffix: pointer.StringPtr("pinniped.dev"),
+				Labels:         map[string]string{},
+				NamesConfig: NamesConfigSpec{
+					DefaultTLSCertificateSecret: "my-secret-name",
+				},
+				Endpoints: &Endpoints{
+					HTTPS: &Endpoint{
+						Network: "tcp",
+						Address: ":8443",
+					},
+					HTTP: &Endpoint{
+						Network: "tcp",
+						Address: ":8080",
+					},
+					Metrics: &Endpoint{
+						Network: "disabled",
+					},
+					Timeouts: &EndpointTimeouts{
+						ReadTimeout:       &metav1.Duration{Duration: time.Minute},
+						ReadHeaderTimeout: &metav1.Duration{Duration: 5 * time.Second},
+						WriteTimeout:      &metav1.Duration{Duration: 2 * time.Minute},
+						IdleTimeout:       &metav1.Duration{Duration: 0},
+					},
+				},
+				ShutdownGracePeriod: &metav1.Duration{Duration: 20 * time.Second},
+			},
+		},
+		{
+			name: "upstream OIDC discovery failure threshold",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+				upstreamOIDCDiscoveryFailureThreshold: 3
+				upstreamOIDCAllowedIssuerPattern: https://login\.corp\.example\.com(/.*)?
+			`),
+			wantConfig: &Config{
+				APIGroupSuffix: pointer.StringPtr("pinniped.dev"),
+				Labels:         map[string]string{},
+				NamesConfig: NamesConfigSpec{
+					DefaultTLSCertificateSecret: "my-secret-name",
+				},
+				Endpoints: &Endpoints{
+					Timeouts: &EndpointTimeouts{
+						ReadHeaderTimeout: &metav1.Duration{Duration: 10 * time.Second},
+					},
+					HTTPS: &Endpoint{
+						Network: "tcp",
+						Address: ":8443",
+					},
+					HTTP: &Endpoint{
+						Network: "tcp",
+						Address: ":8080",
+					},
+					Metrics: &Endpoint{
+						Network: "disabled",
+					},
 				},
+				UpstreamOIDCDiscoveryFailureThreshold: pointer.IntPtr(3),
+				UpstreamOIDCAllowedIssuerPattern:      pointer.StringPtr(`https://login\.corp\.example\.com(/.*)?`),
+				ShutdownGracePeriod:                   &metav1.Duration{Duration: 20 * time.Second},
 			},
 		},
 		{
-			name: "When only the required fields are present, causes other fields to be defaulted",
+			name: "endpoint readTimeout is negative",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+				endpoints:
+				  timeouts:
+				    readTimeout: -1s
+			`),
+			wantError: `validate endpoint timeouts: readTimeout must not be negative, got "-1s"`,
+		},
+		{
+			name: "endpoint readHeaderTimeout is negative",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+				endpoints:
+				  timeouts:
+				    readHeaderTimeout: -1s
+			`),
+			wantError: `validate endpoint timeouts: readHeaderTimeout must not be negative, got "-1s"`,
+		},
+		{
+			name: "endpoint writeTimeout is negative",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+				endpoints:
+				  timeouts:
+				    writeTimeout: -1s
+			`),
+			wantError: `validate endpoint timeouts: writeTimeout must not be negative, got "-1s"`,
+		},
+		{
+			name: "endpoint idleTimeout is negative",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+				endpoints:
+				  timeouts:
+				    idleTimeout: -1s
+			`),
+			wantError: `validate endpoint timeouts: idleTimeout must not be negative, got "-1s"`,
+		},
+		{
+			name: "shutdownGracePeriod is negative",
 			yaml: here.Doc(`
 				---
 				names:
 				  defaultTLSCertificateSecret: my-secret-name
+				shutdownGracePeriod: -1s
+			`),
+			wantError: `validate shutdownGracePeriod: must not be negative, got "-1s"`,
+		},
+		{
+			name: "environment variables are expanded",
+			yaml: here.Doc(`
+				---
+				apiGroupSuffix: ${TEST_SUPERVISOR_API_GROUP_SUFFIX}
+				labels:
+				  myLabelKey: $TEST_SUPERVISOR_LABEL_VALUE
+				names:
+				  defaultTLSCertificateSecret: ${TEST_SUPERVISOR_SECRET_NAME}-$${NOT_EXPANDED}
 			`),
+			env: map[string]string{
+				"TEST_SUPERVISOR_API_GROUP_SUFFIX": "some.suffix.com",
+				"TEST_SUPERVISOR_LABEL_VALUE":      "myLabelValue",
+				"TEST_SUPERVISOR_SECRET_NAME":      "my-secret-name",
+			},
 			wantConfig: &Config{
-				APIGroupSuffix: pointer.StringPtr("pinniped.dev"),
-				Labels:         map[string]string{},
+				APIGroupSuffix: pointer.StringPtr("some.suffix.com"),
+				Labels: map[string]string{
+					"myLabelKey": "myLabelValue",
+				},
 				NamesConfig: NamesConfigSpec{
-					DefaultTLSCertificateSecret: "my-secret-name",
+					DefaultTLSCertificateSecret: "my-secret-name-${NOT_EXPANDED}",
 				},
 				Endpoints: &Endpoints{
+					Timeouts: &EndpointTimeouts{
+						ReadHeaderTimeout: &metav1.Duration{Duration: 10 * time.Second},
+					},
 					HTTPS: &Endpoint{
 						Network: "tcp",
 						Address: ":8443",
@@ -81,9 +525,74 @@ func TestFromPath(t *testing.T) {
 						Network: "tcp",
 						Address: ":8080",
 					},
+					Metrics: &Endpoint{
+						Network: "disabled",
+					},
 				},
+				ShutdownGracePeriod: &metav1.Duration{Duration: 20 * time.Second},
 			},
 		},
+		{
+			name: "undefined environment variables",
+			yaml: here.Doc(`
+				---
+				apiGroupSuffix: ${TEST_SUPERVISOR_UNDEFINED_B}
+				names:
+				  defaultTLSCertificateSecret: ${TEST_SUPERVISOR_UNDEFINED_A}
+			`),
+			wantError: "expand environment variables: undefined environment variables: TEST_SUPERVISOR_UNDEFINED_A, TEST_SUPERVISOR_UNDEFINED_B",
+		},
+		{
+			name: "invalid label key",
+			yaml: here.Doc(`
+				---
+				labels:
+				  "not a valid key": myLabelValue
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+			`),
+			wantError: `validate labels: invalid key "not a valid key": name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]')`,
+		},
+		{
+			name: "invalid label value",
+			yaml: here.Doc(`
+				---
+				labels:
+				  myLabelKey: "-not-a-valid-value"
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+			`),
+			wantError: `validate labels: invalid value "-not-a-valid-value" for key "myLabelKey": a valid label must be an empty string or consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyValue',  or 'my_value',  or '12345', regex used for validation is '(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?')`,
+		},
+		{
+			name: "empty default TLS certificate secret fallback",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+				  defaultTLSCertificateSecretFallbacks: [my-fallback-secret-name, ""]
+			`),
+			wantError: "validate names: defaultTLSCertificateSecretFallbacks must not contain empty names",
+		},
+		{
+			name: "duplicate default TLS certificate secret fallback",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+				  defaultTLSCertificateSecretFallbacks: [my-fallback-secret-name, my-secret-name]
+			`),
+			wantError: `validate names: defaultTLSCertificateSecretFallbacks contains duplicate name "my-secret-name"`,
+		},
+		{
+			name: "fallbacks without a primary default TLS certificate secret",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecretFallbacks: [my-fallback-secret-name]
+			`),
+			wantError: "validate names: missing required names: defaultTLSCertificateSecret",
+		},
 		{
 			name: "all endpoints disabled",
 			yaml: here.Doc(`
@@ -98,6 +607,21 @@ func TestFromPath(t *testing.T) {
 			`),
 			wantError: "validate endpoints: all endpoints are disabled",
 		},
+		{
+			name: "http only without allowInsecureHTTP",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+				endpoints:
+				  https:
+				    network: disabled
+				  http:
+				    network: tcp
+				    address: :8080
+			`),
+			wantError: "validate endpoints: http endpoint is enabled while https endpoint is disabled, set allowInsecureHTTP to true to allow this",
+		},
 		{
 			name: "invalid https endpoint",
 			yaml: here.Doc(`
@@ -112,6 +636,21 @@ func TestFromPath(t *testing.T) {
 			`),
 			wantError: `validate https endpoint: unknown network "foo"`,
 		},
+		{
+			name: "invalid https endpoint with tcp-like network",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+				endpoints:
+				  https:
+				    network: tcp5
+				    address: :8443
+				  http:
+				    network: disabled
+			`),
+			wantError: `validate https endpoint: unknown network "tcp5"`,
+		},
 		{
 			name: "invalid http endpoint",
 			yaml: here.Doc(`
@@ -126,6 +665,31 @@ func TestFromPath(t *testing.T) {
 			`),
 			wantError: `validate http endpoint: unknown network "bar"`,
 		},
+		{
+			name: "invalid metrics endpoint",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+				endpoints:
+				  metrics:
+				    network: baz
+			`),
+			wantError: `validate metrics endpoint: unknown network "baz"`,
+		},
+		{
+			name: "metrics endpoint disabled with non-empty address",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+				endpoints:
+				  metrics:
+				    network: disabled
+				    address: :9090
+			`),
+			wantError: `validate metrics endpoint: address set to ":9090" when disabled, should be empty`,
+		},
 		{
 			name: "endpoint disabled with non-empty address",
 			yaml: here.Doc(`
@@ -151,6 +715,30 @@ func TestFromPath(t *testing.T) {
 			`),
 			wantError: `validate http endpoint: address must be set with "tcp" network`,
 		},
+		{
+			name: "endpoint tcp4 with empty address",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+				endpoints:
+				  http:
+				    network: tcp4
+			`),
+			wantError: `validate http endpoint: address must be set with "tcp4" network`,
+		},
+		{
+			name: "endpoint tcp6 with empty address",
+			yaml: here.Doc(`
+				---
+				names:
+				  defaultTLSCertificateSecret: my-secret-name
+				endpoints:
+				  https:
+				    network: tcp6
+			`),
+			wantError: `validate https endpoint: address must be set with "tcp6" network`,
+		},
 		{
 			name: "endpoint unix with empty address",
 			yaml: here.Doc(`
@@ -184,6 +772,10 @@ func TestFromPath(t *testing.T) {
 	for _, test := range tests {
 		test := test
 		t.Run(test.name, func(t *testing.T) {
+			for k, v := range test.env {
+				t.Setenv(k, v)
+			}
+
 			// Write yaml to temp file
 			f, err := ioutil.TempFile("", "pinniped-test-config-yaml-*")
 			require.NoError(t, err)
@@ -208,3 +800,280 @@ func TestFromPath(t *testing.T) {
 		})
 	}
 }
+
+func TestValidate(t *testing.T) {
+	// validConfig returns a Config which passes validation, for the test cases to break in one way each.
+	validConfig := func() *Config {
+		return &Config{
+			APIGroupSuffix: pointer.StringPtr("some.suffix.com"),
+			Labels:         map[string]string{"myLabelKey1": "myLabelValue1"},
+			NamesConfig:    NamesConfigSpec{DefaultTLSCertificateSecret: "my-secret-name"},
+			Endpoints: &Endpoints{
+				HTTPS:    &Endpoint{Network: NetworkTCP, Address: ":8443"},
+				HTTP:     &Endpoint{Network: NetworkDisabled},
+				Timeouts: &EndpointTimeouts{ReadHeaderTimeout: &metav1.Duration{Duration: 10 * time.Second}},
+			},
+		}
+	}
+	tests := []struct {
+		name      string
+		config    func() *Config
+		wantError string
+	}{
+		{
+			name:   "valid",
+			config: validConfig,
+		},
+		{
+			name: "only the required fields are set",
+			config: func() *Config {
+				return &Config{NamesConfig: NamesConfigSpec{DefaultTLSCertificateSecret: "my-secret-name"}}
+			},
+		},
+		{
+			name: "apiGroupSuffix is prefixed with '.'",
+			config: func() *Config {
+				c := validConfig()
+				c.APIGroupSuffix = pointer.StringPtr(".starts.with.dot")
+				return c
+			},
+			wantError: "validate apiGroupSuffix: a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')",
+		},
+		{
+			name: "invalid label key",
+			config: func() *Config {
+				c := validConfig()
+				c.Labels["not a valid key"] = "myLabelValue"
+				return c
+			},
+			wantError: `validate labels: invalid key "not a valid key": name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]')`,
+		},
+		{
+			name: "invalid label value",
+			config: func() *Config {
+				c := validConfig()
+				c.Labels["myLabelKey"] = "-not-a-valid-value"
+				return c
+			},
+			wantError: `validate labels: invalid value "-not-a-valid-value" for key "myLabelKey": a valid label must be an empty string or consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyValue',  or 'my_value',  or '12345', regex used for validation is '(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?')`,
+		},
+		{
+			name: "missing defaultTLSCertificateSecret name",
+			config: func() *Config {
+				c := validConfig()
+				c.NamesConfig.DefaultTLSCertificateSecret = ""
+				return c
+			},
+			wantError: "validate names: missing required names: defaultTLSCertificateSecret",
+		},
+		{
+			name: "invalid log level",
+			config: func() *Config {
+				c := validConfig()
+				c.LogLevel = "not-a-level"
+				return c
+			},
+			wantError: "validate log level: invalid log level, valid choices are the empty string, info, debug, trace and all",
+		},
+		{
+			name: "all endpoints disabled",
+			config: func() *Config {
+				c := validConfig()
+				c.Endpoints.HTTPS = &Endpoint{Network: NetworkDisabled}
+				return c
+			},
+			wantError: "validate endpoints: all endpoints are disabled",
+		},
+		{
+			name: "http only without allowInsecureHTTP",
+			config: func() *Config {
+				c := validConfig()
+				c.Endpoints.HTTPS = &Endpoint{Network: NetworkDisabled}
+				c.Endpoints.HTTP = &Endpoint{Network: NetworkTCP, Address: ":8080"}
+				return c
+			},
+			wantError: "validate endpoints: http endpoint is enabled while https endpoint is disabled, set allowInsecureHTTP to true to allow this",
+		},
+		{
+			name: "http only with allowInsecureHTTP",
+			config: func() *Config {
+				c := validConfig()
+				c.Endpoints.HTTPS = &Endpoint{Network: NetworkDisabled}
+				c.Endpoints.HTTP = &Endpoint{Network: NetworkTCP, Address: ":8080"}
+				c.Endpoints.AllowInsecureHTTP = true
+				return c
+			},
+		},
+		{
+			name: "invalid https endpoint",
+			config: func() *Config {
+				c := validConfig()
+				c.Endpoints.HTTPS = &Endpoint{Network: "foo"}
+				return c
+			},
+			wantError: `validate https endpoint: unknown network "foo"`,
+		},
+		{
+			name: "invalid http endpoint",
+			config: func() *Config {
+				c := validConfig()
+				c.Endpoints.HTTP = &Endpoint{Network: "bar"}
+				return c
+			},
+			wantError: `validate http endpoint: unknown network "bar"`,
+		},
+		{
+			name: "metrics endpoint",
+			config: func() *Config {
+				c := validConfig()
+				c.Endpoints.Metrics = &Endpoint{Network: NetworkTCP, Address: ":9090"}
+				return c
+			},
+		},
+		{
+			name: "metrics endpoint tcp with empty address",
+			config: func() *Config {
+				c := validConfig()
+				c.Endpoints.Metrics = &Endpoint{Network: NetworkTCP}
+				return c
+			},
+			wantError: `validate metrics endpoint: address must be set with "tcp" network`,
+		},
+		{
+			name: "endpoint disabled with non-empty address",
+			config: func() *Config {
+				c := validConfig()
+				c.Endpoints.HTTP = &Endpoint{Network: NetworkDisabled, Address: "wee"}
+				return c
+			},
+			wantError: `validate http endpoint: address set to "wee" when disabled, should be empty`,
+		},
+		{
+			name: "endpoint tcp6 with empty address",
+			config: func() *Config {
+				c := validConfig()
+				c.Endpoints.HTTPS = &Endpoint{Network: NetworkTCP6}
+				return c
+			},
+			wantError: `validate https endpoint: address must be set with "tcp6" network`,
+		},
+		{
+			name: "endpoint unix with empty address",
+			config: func() *Config {
+				c := validConfig()
+				c.Endpoints.HTTPS = &Endpoint{Network: NetworkUnix}
+				return c
+			},
+			wantError: `validate https endpoint: address must be set with "unix" network`,
+		},
+		{
+			name: "endpoint timeout is negative",
+			config: func() *Config {
+				c := validConfig()
+				c.Endpoints.Timeouts.IdleTimeout = &metav1.Duration{Duration: -time.Second}
+				return c
+			},
+			wantError: `validate endpoint timeouts: idleTimeout must not be negative, got "-1s"`,
+		},
+		{
+			name: "tls unknown min version",
+			config: func() *Config {
+				c := validConfig()
+				c.TLS = &TLSSpec{MinVersion: "1.1"}
+				return c
+			},
+			wantError: `validate tls: unknown minVersion "1.1", must be "1.2" or "1.3"`,
+		},
+		{
+			name: "tls unknown cipher suite",
+			config: func() *Config {
+				c := validConfig()
+				c.TLS = &TLSSpec{CipherSuites: []string{"TLS_NOT_A_REAL_CIPHER"}}
+				return c
+			},
+			wantError: `validate tls: unknown cipher suite "TLS_NOT_A_REAL_CIPHER"`,
+		},
+		{
+			name: "tls 1.3 cipher suite",
+			config: func() *Config {
+				c := validConfig()
+				c.TLS = &TLSSpec{CipherSuites: []string{"TLS_AES_128_GCM_SHA256"}}
+				return c
+			},
+			wantError: `validate tls: cipher suite "TLS_AES_128_GCM_SHA256" cannot be configured because it is only used by TLS 1.3`,
+		},
+		{
+			name: "tls cipher suites with tls 1.3",
+			config: func() *Config {
+				c := validConfig()
+				c.TLS = &TLSSpec{MinVersion: TLSVersion13, CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}}
+				return c
+			},
+			wantError: `validate tls: cipherSuites cannot be set when minVersion is "1.3"`,
+		},
+		{
+			name: "upstream OIDC discovery failure threshold is not positive",
+			config: func() *Config {
+				c := validConfig()
+				c.UpstreamOIDCDiscoveryFailureThreshold = pointer.IntPtr(0)
+				return c
+			},
+			wantError: "validate upstreamOIDCDiscoveryFailureThreshold: must be at least 1, got 0",
+		},
+		{
+			name: "upstream OIDC allowed issuer pattern is not a valid regular expression",
+			config: func() *Config {
+				c := validConfig()
+				c.UpstreamOIDCAllowedIssuerPattern = pointer.StringPtr("https://(")
+				return c
+			},
+			wantError: "validate upstreamOIDCAllowedIssuerPattern: error parsing regexp: missing closing ): `^(?:https://()$`",
+		},
+		{
+			name: "shutdown grace period is zero",
+			config: func() *Config {
+				c := validConfig()
+				c.ShutdownGracePeriod = &metav1.Duration{}
+				return c
+			},
+		},
+		{
+			name: "shutdown grace period is negative",
+			config: func() *Config {
+				c := validConfig()
+				c.ShutdownGracePeriod = &metav1.Duration{Duration: -time.Second}
+				return c
+			},
+			wantError: `validate shutdownGracePeriod: must not be negative, got "-1s"`,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			err := Validate(test.config())
+			if test.wantError != "" {
+				require.EqualError(t, err, test.wantError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDefaultTLSCertificateSecrets(t *testing.T) {
+	require.Equal(t, []string{"primary"}, (&NamesConfigSpec{DefaultTLSCertificateSecret: "primary"}).DefaultTLSCertificateSecrets())
+	require.Equal(t, []string{"primary", "fallback1", "fallback2"}, (&NamesConfigSpec{
+		DefaultTLSCertificateSecret:          "primary",
+		DefaultTLSCertificateSecretFallbacks: []string{"fallback1", "fallback2"},
+	}).DefaultTLSCertificateSecrets())
+}
+
+func TestUpstreamOIDCAllowedIssuerRegexp(t *testing.T) {
+	require.Nil(t, (&Config{}).UpstreamOIDCAllowedIssuerRegexp())
+
+	allowed := (&Config{UpstreamOIDCAllowedIssuerPattern: pointer.StringPtr(`https://login\.corp\.example\.com|https://other\.example\.com`)}).UpstreamOIDCAllowedIssuerRegexp()
+	require.True(t, allowed.MatchString("https://login.corp.example.com"))
+	require.True(t, allowed.MatchString("https://other.example.com"))
+	require.False(t, allowed.MatchString("https://login.corp.example.com.evil.example.com"))
+	require.False(t, allowed.MatchString("https://evil.example.com/https://other.example.com"))
+}
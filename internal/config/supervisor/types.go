@@ -3,7 +3,13 @@
 
 package supervisor
 
-import "go.pinniped.dev/internal/plog"
+import (
+	"regexp"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"go.pinniped.dev/internal/plog"
+)
 
 // Config contains knobs to setup an instance of the Pinniped Supervisor.
 type Config struct {
@@ -12,19 +18,87 @@ type Config struct {
 	NamesConfig    NamesConfigSpec   `json:"names"`
 	LogLevel       plog.LogLevel     `json:"logLevel"`
 	Endpoints      *Endpoints        `json:"endpoints"`
+	TLS            *TLSSpec          `json:"tls,omitempty"`
+
+	// UpstreamOIDCDiscoveryFailureThreshold is the number of consecutive failed OIDC discovery attempts after which
+	// the issuer of an OIDCIdentityProvider is reported as persistently unreachable. Defaults to 10.
+	UpstreamOIDCDiscoveryFailureThreshold *int `json:"upstreamOIDCDiscoveryFailureThreshold,omitempty"`
+
+	// UpstreamOIDCAllowedIssuerPattern is a regular expression which the issuer of an OIDCIdentityProvider must match
+	// in its entirety, e.g. to restrict tenants to the issuers of a corporate identity provider. When it is not set,
+	// any issuer is allowed.
+	UpstreamOIDCAllowedIssuerPattern *string `json:"upstreamOIDCAllowedIssuerPattern,omitempty"`
+
+	// ShutdownGracePeriod is how long the servers of the endpoints wait for active connections to finish after
+	// the Supervisor was asked to stop, before those connections are closed. Defaults to 20s.
+	ShutdownGracePeriod *metav1.Duration `json:"shutdownGracePeriod,omitempty"`
+}
+
+// UpstreamOIDCAllowedIssuerRegexp returns the compiled UpstreamOIDCAllowedIssuerPattern, anchored so that it must
+// match an entire issuer, or nil when any issuer is allowed. It must only be called on a validated Config.
+func (c *Config) UpstreamOIDCAllowedIssuerRegexp() *regexp.Regexp {
+	if c.UpstreamOIDCAllowedIssuerPattern == nil {
+		return nil
+	}
+	return regexp.MustCompile(anchoredPattern(*c.UpstreamOIDCAllowedIssuerPattern))
 }
 
 // NamesConfigSpec configures the names of some Kubernetes resources for the Supervisor.
 type NamesConfigSpec struct {
 	DefaultTLSCertificateSecret string `json:"defaultTLSCertificateSecret"`
+
+	// DefaultTLSCertificateSecretFallbacks are the names of Secrets to try in order when the primary
+	// DefaultTLSCertificateSecret does not contain a valid TLS certificate, e.g. during a certificate migration.
+	DefaultTLSCertificateSecretFallbacks []string `json:"defaultTLSCertificateSecretFallbacks,omitempty"`
+}
+
+// DefaultTLSCertificateSecrets returns the names of the default TLS certificate Secrets in the order in which they
+// should be tried, starting with the primary DefaultTLSCertificateSecret.
+func (n *NamesConfigSpec) DefaultTLSCertificateSecrets() []string {
+	return append([]string{n.DefaultTLSCertificateSecret}, n.DefaultTLSCertificateSecretFallbacks...)
 }
 
 type Endpoints struct {
-	HTTPS *Endpoint `json:"https,omitempty"`
-	HTTP  *Endpoint `json:"http,omitempty"`
+	HTTPS    *Endpoint         `json:"https,omitempty"`
+	HTTP     *Endpoint         `json:"http,omitempty"`
+	Timeouts *EndpointTimeouts `json:"timeouts,omitempty"`
+
+	// Metrics is a separate endpoint which only serves the Prometheus metrics of the Supervisor, so that they
+	// are not exposed by the public-facing endpoints. Disabled by default.
+	Metrics *Endpoint `json:"metrics,omitempty"`
+
+	// AllowInsecureHTTP allows the HTTP endpoint to be enabled while the HTTPS endpoint is disabled. Serving
+	// OIDC over plain HTTP only is usually a misconfiguration, so this must be set explicitly, e.g. when TLS
+	// is terminated by a service mesh in front of the Supervisor.
+	AllowInsecureHTTP bool `json:"allowInsecureHTTP,omitempty"`
+}
+
+// EndpointTimeouts configures the timeouts of the servers for all endpoints. See the documentation of the
+// fields of the same names on net/http.Server for their meanings. A zero value means no timeout.
+type EndpointTimeouts struct {
+	ReadTimeout       *metav1.Duration `json:"readTimeout,omitempty"`
+	ReadHeaderTimeout *metav1.Duration `json:"readHeaderTimeout,omitempty"`
+	WriteTimeout      *metav1.Duration `json:"writeTimeout,omitempty"`
+	IdleTimeout       *metav1.Duration `json:"idleTimeout,omitempty"`
 }
 
 type Endpoint struct {
 	Network string `json:"network"`
 	Address string `json:"address"`
 }
+
+// TLSSpec configures the TLS settings of the HTTPS endpoint of the Supervisor.
+type TLSSpec struct {
+	// MinVersion is the minimum TLS version, either "1.2" or "1.3". Defaults to "1.2".
+	MinVersion string `json:"minVersion,omitempty"`
+
+	// CipherSuites are the names of the TLS 1.2 cipher suites to allow, e.g. "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256".
+	// When not set, the Supervisor's default cipher suites are allowed. TLS 1.3 cipher suites cannot be configured.
+	CipherSuites []string `json:"cipherSuites,omitempty"`
+
+	// ParsedMinVersion is the crypto/tls value of MinVersion, set by FromPath.
+	ParsedMinVersion uint16 `json:"-"`
+
+	// ParsedCipherSuites are the crypto/tls IDs of CipherSuites, set by FromPath.
+	ParsedCipherSuites []uint16 `json:"-"`
+}
@@ -181,7 +181,7 @@ func newJWTAuthenticator(spec *auth1alpha1.JWTAuthenticatorSpec) (*jwtAuthentica
 		return nil, fmt.Errorf("issuer (%q) has invalid scheme (%q), require 'https'", spec.Issuer, issuerURL.Scheme)
 	}
 
-	client := phttp.Default(rootCAs)
+	client := phttp.Default(rootCAs, nil)
 	client.Timeout = 30 * time.Second // copied from Kube OIDC code
 
 	ctx := coreosoidc.ClientContext(context.Background(), client)
@@ -0,0 +1,91 @@
+// Copyright 2022 the Pinniped contributors. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package impersonatorconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.pinniped.dev/generated/latest/apis/concierge/config/v1alpha1"
+	"go.pinniped.dev/internal/crypto/ptls"
+)
+
+// ClientTLSConfigFromStrategy returns the URL of the impersonation proxy which is published by the given
+// CredentialIssuer strategy, and a TLS client config which trusts only the CA bundle published alongside it.
+//
+// The endpoint is always returned as an https URL. For consistency with older versions of the CredentialIssuer
+// status, an endpoint without a scheme is treated as an https endpoint, but any other scheme is rejected.
+// The returned TLS config does not present a client certificate, so callers which need one should add it to
+// the Certificates of the returned config.
+//
+// It returns an error when the strategy is not a successful impersonation proxy strategy, or when its published
+// endpoint or CA bundle cannot be used.
+func ClientTLSConfigFromStrategy(strategy *v1alpha1.CredentialIssuerStrategy) (*url.URL, *tls.Config, error) {
+	if strategy.Type != v1alpha1.ImpersonationProxyStrategyType {
+		return nil, nil, fmt.Errorf("strategy has type %q, not %q", strategy.Type, v1alpha1.ImpersonationProxyStrategyType)
+	}
+	if strategy.Status != v1alpha1.SuccessStrategyStatus {
+		return nil, nil, fmt.Errorf("impersonation proxy strategy has status %q: %s", strategy.Status, strategy.Message)
+	}
+	if strategy.Frontend == nil || strategy.Frontend.Type != v1alpha1.ImpersonationProxyFrontendType || strategy.Frontend.ImpersonationProxyInfo == nil {
+		return nil, nil, fmt.Errorf("impersonation proxy strategy does not have an impersonation proxy frontend")
+	}
+	info := strategy.Frontend.ImpersonationProxyInfo
+
+	endpoint, err := parseImpersonationProxyEndpoint(info.Endpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	caBundle, err := base64.StdEncoding.DecodeString(info.CertificateAuthorityData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("impersonation proxy CA bundle is not valid base64: %w", err)
+	}
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(caBundle) {
+		return nil, nil, fmt.Errorf("impersonation proxy CA bundle does not contain any valid PEM certificates")
+	}
+
+	tlsConfig := ptls.Default(rootCAs)
+	tlsConfig.ServerName = endpoint.Hostname()
+	return endpoint, tlsConfig, nil
+}
+
+// ClientTransportFromStrategy is like ClientTLSConfigFromStrategy, but returns an http.Transport which uses the
+// TLS client config instead of the config itself.
+func ClientTransportFromStrategy(strategy *v1alpha1.CredentialIssuerStrategy) (*url.URL, *http.Transport, error) {
+	endpoint, tlsConfig, err := ClientTLSConfigFromStrategy(strategy)
+	if err != nil {
+		return nil, nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return endpoint, transport, nil
+}
+
+// parseImpersonationProxyEndpoint parses the published endpoint of the impersonation proxy as an https URL.
+func parseImpersonationProxyEndpoint(endpoint string) (*url.URL, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("impersonation proxy endpoint is empty")
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("impersonation proxy endpoint is invalid: %w", err)
+	}
+	if parsed.Scheme != "https" {
+		return nil, fmt.Errorf("impersonation proxy endpoint %q must use https", endpoint)
+	}
+	if parsed.Hostname() == "" {
+		return nil, fmt.Errorf("impersonation proxy endpoint %q does not have a host", endpoint)
+	}
+	return parsed, nil
+}
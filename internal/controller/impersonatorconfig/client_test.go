@@ -0,0 +1,128 @@
+// Copyright 2022 the Pinniped contributors. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package impersonatorconfig
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.pinniped.dev/generated/latest/apis/concierge/config/v1alpha1"
+	"go.pinniped.dev/internal/testutil"
+)
+
+func TestClientTLSConfigFromStrategy(t *testing.T) {
+	caBundle, serverURL := testutil.TLSTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello from the impersonation proxy"))
+	})
+	encodedCABundle := base64.StdEncoding.EncodeToString([]byte(caBundle))
+
+	strategy := func(endpoint, caData string) *v1alpha1.CredentialIssuerStrategy {
+		return &v1alpha1.CredentialIssuerStrategy{
+			Type:   v1alpha1.ImpersonationProxyStrategyType,
+			Status: v1alpha1.SuccessStrategyStatus,
+			Reason: v1alpha1.ListeningStrategyReason,
+			Frontend: &v1alpha1.CredentialIssuerFrontend{
+				Type: v1alpha1.ImpersonationProxyFrontendType,
+				ImpersonationProxyInfo: &v1alpha1.ImpersonationProxyInfo{
+					Endpoint:                 endpoint,
+					CertificateAuthorityData: caData,
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		strategy     *v1alpha1.CredentialIssuerStrategy
+		wantEndpoint string
+		wantErr      string
+	}{
+		{
+			name:         "valid strategy",
+			strategy:     strategy(serverURL, encodedCABundle),
+			wantEndpoint: serverURL,
+		},
+		{
+			name:         "endpoint without a scheme",
+			strategy:     strategy(strings.TrimPrefix(serverURL, "https://"), encodedCABundle),
+			wantEndpoint: serverURL,
+		},
+		{
+			name: "not an impersonation proxy strategy",
+			strategy: &v1alpha1.CredentialIssuerStrategy{
+				Type:   v1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status: v1alpha1.SuccessStrategyStatus,
+			},
+			wantErr: `strategy has type "KubeClusterSigningCertificate", not "ImpersonationProxy"`,
+		},
+		{
+			name: "strategy is not successful",
+			strategy: &v1alpha1.CredentialIssuerStrategy{
+				Type:    v1alpha1.ImpersonationProxyStrategyType,
+				Status:  v1alpha1.ErrorStrategyStatus,
+				Message: "some error",
+			},
+			wantErr: `impersonation proxy strategy has status "Error": some error`,
+		},
+		{
+			name: "strategy without a frontend",
+			strategy: &v1alpha1.CredentialIssuerStrategy{
+				Type:   v1alpha1.ImpersonationProxyStrategyType,
+				Status: v1alpha1.SuccessStrategyStatus,
+			},
+			wantErr: "impersonation proxy strategy does not have an impersonation proxy frontend",
+		},
+		{
+			name:     "empty endpoint",
+			strategy: strategy("", encodedCABundle),
+			wantErr:  "impersonation proxy endpoint is empty",
+		},
+		{
+			name:     "http endpoint",
+			strategy: strategy("http://impersonation-proxy.example.com", encodedCABundle),
+			wantErr:  `impersonation proxy endpoint "http://impersonation-proxy.example.com" must use https`,
+		},
+		{
+			name:     "endpoint without a host",
+			strategy: strategy("https://", encodedCABundle),
+			wantErr:  `impersonation proxy endpoint "https://" does not have a host`,
+		},
+		{
+			name:     "CA bundle is not base64",
+			strategy: strategy(serverURL, "!!!"),
+			wantErr:  "impersonation proxy CA bundle is not valid base64: illegal base64 data at input byte 0",
+		},
+		{
+			name:     "CA bundle does not contain certificates",
+			strategy: strategy(serverURL, base64.StdEncoding.EncodeToString([]byte("not a certificate"))),
+			wantErr:  "impersonation proxy CA bundle does not contain any valid PEM certificates",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint, transport, err := ClientTransportFromStrategy(tt.strategy)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				require.Nil(t, endpoint)
+				require.Nil(t, transport)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantEndpoint, endpoint.String())
+
+			resp, err := (&http.Client{Transport: transport}).Get(endpoint.String())
+			require.NoError(t, err)
+			defer func() { require.NoError(t, resp.Body.Close()) }()
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Equal(t, "hello from the impersonation proxy", string(body))
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 the Pinniped contributors. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package impersonatorconfig
+
+import (
+	"fmt"
+	"net"
+
+	v1 "k8s.io/api/core/v1"
+
+	"go.pinniped.dev/generated/latest/apis/concierge/config/v1alpha1"
+)
+
+// EndpointFromService returns the host which clients should use to connect to the given Service, without a port.
+//
+// For a LoadBalancer Service, this is chosen from the ingress of the load balancer. When IPs are preferred, the first
+// valid IP is used, or the first valid IP of the preferred family when an IP family is preferred. Otherwise, for
+// backwards compatibility, the first hostname is used, or the first valid IP when there are no hostnames. For any
+// other type of Service, this is the cluster IP, unless an IP family is preferred and one of the cluster IPs of a
+// dual-stack Service has that family.
+//
+// It returns an error when the Service does not have a usable address, e.g. when the load balancer has not been
+// assigned an ingress yet.
+func EndpointFromService(svc *v1.Service, preferredAddressType v1alpha1.ImpersonationProxyServiceAddressType) (string, error) {
+	if svc.Spec.Type != v1.ServiceTypeLoadBalancer {
+		ip := svc.Spec.ClusterIP
+		if ip == "" || ip == v1.ClusterIPNone {
+			return "", fmt.Errorf("could not find valid IP addresses or hostnames from service %s/%s", svc.Namespace, svc.Name)
+		}
+		for _, clusterIP := range svc.Spec.ClusterIPs {
+			if parsedIP := net.ParseIP(clusterIP); parsedIP != nil && hasPreferredIPFamily(parsedIP, preferredAddressType) {
+				return clusterIP, nil
+			}
+		}
+		return ip, nil
+	}
+
+	var firstHostname, firstIP, firstPreferredIP string
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if firstHostname == "" && ingress.Hostname != "" {
+			firstHostname = ingress.Hostname
+		}
+		parsedIP := net.ParseIP(ingress.IP)
+		if parsedIP == nil {
+			continue
+		}
+		if firstIP == "" {
+			firstIP = ingress.IP
+		}
+		if firstPreferredIP == "" && hasPreferredIPFamily(parsedIP, preferredAddressType) {
+			firstPreferredIP = ingress.IP
+		}
+	}
+	preferIPs := preferredAddressType == v1alpha1.ImpersonationProxyServiceAddressTypeIP ||
+		preferredAddressType == v1alpha1.ImpersonationProxyServiceAddressTypeIPv4 ||
+		preferredAddressType == v1alpha1.ImpersonationProxyServiceAddressTypeIPv6
+	switch {
+	case firstPreferredIP != "":
+		return firstPreferredIP, nil
+	case preferIPs && firstIP != "":
+		return firstIP, nil
+	case firstHostname != "":
+		return firstHostname, nil
+	case firstIP != "":
+		return firstIP, nil
+	default:
+		return "", fmt.Errorf("could not find valid IP addresses or hostnames from load balancer %s/%s", svc.Namespace, svc.Name)
+	}
+}
+
+// hasPreferredIPFamily returns whether the IP has the IP family which is preferred by the address type.
+// It is always false when no IP family is preferred.
+func hasPreferredIPFamily(ip net.IP, preferredAddressType v1alpha1.ImpersonationProxyServiceAddressType) bool {
+	switch preferredAddressType {
+	case v1alpha1.ImpersonationProxyServiceAddressTypeIPv4:
+		return ip.To4() != nil
+	case v1alpha1.ImpersonationProxyServiceAddressTypeIPv6:
+		return ip.To4() == nil
+	default:
+		return false
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2022 the Pinniped contributors. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package impersonatorconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"go.pinniped.dev/generated/latest/apis/concierge/config/v1alpha1"
+)
+
+func TestEndpointFromService(t *testing.T) {
+	loadBalancer := func(ingress ...corev1.LoadBalancerIngress) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "some-namespace", Name: "some-service"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer, ClusterIP: "10.1.2.3"},
+			Status:     corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: ingress}},
+		}
+	}
+	clusterIP := func(ips ...string) *corev1.Service {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "some-namespace", Name: "some-service"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, ClusterIPs: ips},
+		}
+		if len(ips) > 0 {
+			svc.Spec.ClusterIP = ips[0]
+		}
+		return svc
+	}
+
+	tests := []struct {
+		name                 string
+		svc                  *corev1.Service
+		preferredAddressType v1alpha1.ImpersonationProxyServiceAddressType
+		want                 string
+		wantErr              string
+	}{
+		{
+			name: "load balancer with a hostname",
+			svc:  loadBalancer(corev1.LoadBalancerIngress{Hostname: "lb.example.com"}),
+			want: "lb.example.com",
+		},
+		{
+			name: "load balancer with an ip",
+			svc:  loadBalancer(corev1.LoadBalancerIngress{IP: "127.0.0.1"}),
+			want: "127.0.0.1",
+		},
+		{
+			name: "load balancer with hostnames and ips prefers the first hostname by default",
+			svc: loadBalancer(
+				corev1.LoadBalancerIngress{IP: "127.0.0.1"},
+				corev1.LoadBalancerIngress{Hostname: "lb-1.example.com"},
+				corev1.LoadBalancerIngress{Hostname: "lb-2.example.com"},
+			),
+			want: "lb-1.example.com",
+		},
+		{
+			name: "load balancer with hostnames and ips prefers the first ip when ips are preferred",
+			svc: loadBalancer(
+				corev1.LoadBalancerIngress{Hostname: "lb.example.com"},
+				corev1.LoadBalancerIngress{IP: "not-an-ip"},
+				corev1.LoadBalancerIngress{IP: "127.0.0.2"},
+				corev1.LoadBalancerIngress{IP: "127.0.0.3"},
+			),
+			preferredAddressType: v1alpha1.ImpersonationProxyServiceAddressTypeIP,
+			want:                 "127.0.0.2",
+		},
+		{
+			name:                 "load balancer with only hostnames when ips are preferred",
+			svc:                  loadBalancer(corev1.LoadBalancerIngress{Hostname: "lb.example.com"}),
+			preferredAddressType: v1alpha1.ImpersonationProxyServiceAddressTypeIP,
+			want:                 "lb.example.com",
+		},
+		{
+			name: "load balancer with ips of both families prefers the first ipv6 address when ipv6 is preferred",
+			svc: loadBalancer(
+				corev1.LoadBalancerIngress{Hostname: "lb.example.com"},
+				corev1.LoadBalancerIngress{IP: "127.0.0.2"},
+				corev1.LoadBalancerIngress{IP: "fd00::2"},
+			),
+			preferredAddressType: v1alpha1.ImpersonationProxyServiceAddressTypeIPv6,
+			want:                 "fd00::2",
+		},
+		{
+			name: "load balancer without ips of the preferred family falls back to another ip",
+			svc: loadBalancer(
+				corev1.LoadBalancerIngress{Hostname: "lb.example.com"},
+				corev1.LoadBalancerIngress{IP: "fd00::2"},
+			),
+			preferredAddressType: v1alpha1.ImpersonationProxyServiceAddressTypeIPv4,
+			want:                 "fd00::2",
+		},
+		{
+			name:    "load balancer with only invalid ips",
+			svc:     loadBalancer(corev1.LoadBalancerIngress{IP: "not-an-ip"}),
+			wantErr: "could not find valid IP addresses or hostnames from load balancer some-namespace/some-service",
+		},
+		{
+			name:    "load balancer without ingress",
+			svc:     loadBalancer(),
+			wantErr: "could not find valid IP addresses or hostnames from load balancer some-namespace/some-service",
+		},
+		{
+			name: "cluster ip",
+			svc:  clusterIP("10.1.2.3"),
+			want: "10.1.2.3",
+		},
+		{
+			name: "dual stack cluster ip",
+			svc:  clusterIP("10.1.2.3", "fd00::1"),
+			want: "10.1.2.3",
+		},
+		{
+			name:                 "dual stack cluster ip when ipv6 is preferred",
+			svc:                  clusterIP("10.1.2.3", "fd00::1"),
+			preferredAddressType: v1alpha1.ImpersonationProxyServiceAddressTypeIPv6,
+			want:                 "fd00::1",
+		},
+		{
+			name:                 "ipv6 first dual stack cluster ip when ipv4 is preferred",
+			svc:                  clusterIP("fd00::1", "10.1.2.3"),
+			preferredAddressType: v1alpha1.ImpersonationProxyServiceAddressTypeIPv4,
+			want:                 "10.1.2.3",
+		},
+		{
+			name:                 "single stack cluster ip without the preferred family",
+			svc:                  clusterIP("10.1.2.3"),
+			preferredAddressType: v1alpha1.ImpersonationProxyServiceAddressTypeIPv6,
+			want:                 "10.1.2.3",
+		},
+		{
+			name:    "cluster ip which has not been assigned yet",
+			svc:     clusterIP(),
+			wantErr: "could not find valid IP addresses or hostnames from service some-namespace/some-service",
+		},
+		{
+			name:    "headless service",
+			svc:     clusterIP(corev1.ClusterIPNone),
+			wantErr: "could not find valid IP addresses or hostnames from service some-namespace/some-service",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EndpointFromService(tt.svc, tt.preferredAddressType)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				require.Empty(t, got)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
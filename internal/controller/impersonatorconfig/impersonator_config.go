@@ -5,32 +5,41 @@ package impersonatorconfig
 
 import (
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"encoding/pem"
+	stderrors "errors"
 	"fmt"
 	"net"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/errors"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
-	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
 	corev1informers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/clock"
+	"k8s.io/utils/pointer"
 
 	"go.pinniped.dev/generated/latest/apis/concierge/config/v1alpha1"
 	pinnipedclientset "go.pinniped.dev/generated/latest/client/concierge/clientset/versioned"
@@ -48,23 +57,87 @@ import (
 )
 
 const (
-	defaultHTTPSPort             = 443
-	approximatelyOneHundredYears = 100 * 365 * 24 * time.Hour
-	caCommonName                 = "Pinniped Impersonation Proxy Serving CA"
-	caCrtKey                     = "ca.crt"
-	caKeyKey                     = "ca.key"
-	appLabelKey                  = "app"
-	annotationKeysKey            = "credentialissuer.pinniped.dev/annotation-keys"
+	defaultHTTPSPort              = 443
+	approximatelyOneHundredYears  = 100 * 365 * 24 * time.Hour
+	minimumCACertificateLifetime  = time.Hour
+	minimumTLSCertificateLifetime = time.Hour
+	defaultCARenewalPercent       = 25
+	tlsRenewalFraction            = 3 // reissue the TLS serving cert when less than 1/3 of its lifetime remains
+	caCommonName                  = "Pinniped Impersonation Proxy Serving CA"
+	maxCASubjectFieldLength       = 64 // ub-common-name and ub-organization-name from RFC 5280
+	caCrtKey                      = "ca.crt"
+	caKeyKey                      = "ca.key"
+
+	// caPreviousCrtKey holds the CA certificate from before the latest CA renewal in the CA Secret, until the TLS
+	// serving certificate which was issued by the new CA is observed. It is stored in the Secret rather than in
+	// memory, so that it is still advertised to clients after a restart of the Concierge during the renewal.
+	caPreviousCrtKey = "previous-ca.crt"
+
+	// caExternallyProvidedAnnotationKey can be set to "true" on the CA Secret by an operator who creates that
+	// Secret with a CA from their own PKI. Such a CA is never replaced by the controller, so the operator is
+	// responsible for renewing it before it expires.
+	caExternallyProvidedAnnotationKey = "impersonation-proxy.concierge.pinniped.dev/externally-provided-ca"
+
+	// caGeneratedFingerprintAnnotationKey records on the CA Secret the SHA-256 fingerprint of the CA certificate which
+	// the controller generated, so that a CA certificate which was later edited by hand is not treated as generated.
+	caGeneratedFingerprintAnnotationKey = "impersonation-proxy.concierge.pinniped.dev/generated-ca-sha256"
+
+	// caRotationRequestAnnotationKey can be set on the CredentialIssuer to an RFC3339 timestamp to immediately replace
+	// the generated CA, e.g. during incident response. The CA is replaced only when the timestamp is newer than the
+	// CA Secret, and only once for each distinct timestamp.
+	caRotationRequestAnnotationKey = "impersonation-proxy.concierge.pinniped.dev/rotate-ca"
+
+	// caLastRotationAnnotationKey records on the CA Secret the most recently processed CA rotation request.
+	caLastRotationAnnotationKey = "impersonation-proxy.concierge.pinniped.dev/last-ca-rotation"
+
+	// issuedAtAnnotationKey and issuanceReasonAnnotationKey optionally record on the generated CA and TLS Secrets when
+	// and why their certificates were issued, for auditing. The reasons are the same as in the issuance metrics.
+	issuedAtAnnotationKey       = "impersonation-proxy.concierge.pinniped.dev/issued-at"
+	issuanceReasonAnnotationKey = "impersonation-proxy.concierge.pinniped.dev/issuance-reason"
+
+	appLabelKey       = "app"
+	annotationKeysKey = "credentialissuer.pinniped.dev/annotation-keys"
+
+	// stopGracePeriod is how much longer than the shutdown timeout to wait for the impersonation proxy
+	// to report that it has stopped before giving up on it.
+	stopGracePeriod = 5 * time.Second
 )
 
+// DefaultShutdownTimeout is the default amount of time that the impersonation proxy gives in-flight
+// requests to finish when it is stopped.
+const DefaultShutdownTimeout = 10 * time.Second
+
+// DefaultCertIssuanceQPS and DefaultCertIssuanceBurst bound how quickly the controller may issue new serving
+// certificates for the impersonation proxy, to avoid CPU spikes when many syncs happen at once.
+const (
+	DefaultCertIssuanceQPS   = 1
+	DefaultCertIssuanceBurst = 5
+)
+
+// DefaultMaxSyncJitter is the default upper bound of the random delay which the controller adds to the syncs that
+// are triggered by its initial event and by changes to its Secrets, so that a burst of such events, e.g. after a CA
+// rotation or an informer restart, results in fewer and more spread out expensive syncs.
+const DefaultMaxSyncJitter = 3 * time.Second
+
+// DefaultSyncTimeout is the default upper bound of the time which a single sync may spend on its API calls and
+// certificate generation, so that an unresponsive API server cannot block the worker of the controller indefinitely.
+const DefaultSyncTimeout = 30 * time.Second
+
+// jitteredSyncKey is the queue key used for changes to the Secrets. Syncs for this key only enqueue a sync of the
+// singleton key after a random delay.
+var jitteredSyncKey = controllerlib.Key{Name: "jittered-sync"} //nolint:gochecknoglobals
+
 type impersonatorConfigController struct {
 	namespace                        string
 	credentialIssuerResourceName     string
 	impersonationProxyPort           int
+	impersonationProxyBindAddress    net.IP
+	controlPlaneNodeSelectors        []labels.Selector
+	shutdownTimeout                  time.Duration
 	generatedLoadBalancerServiceName string
 	generatedClusterIPServiceName    string
-	tlsSecretName                    string
-	caSecretName                     string
+	defaultTLSSecretName             string
+	defaultCASecretName              string
 	impersonationSignerSecretName    string
 
 	k8sClient         kubernetes.Interface
@@ -75,18 +148,65 @@ type impersonatorConfigController struct {
 	secretsInformer    corev1informers.SecretInformer
 
 	labels                           map[string]string
+	setOwnerReferences               bool
+	annotateIssuedSecrets            bool
 	clock                            clock.Clock
 	impersonationSigningCertProvider dynamiccert.Provider
 	impersonatorFunc                 impersonator.FactoryFunc
+	readinessProbe                   ReadinessProbeFunc
+	certIssuanceRateLimiter          flowcontrol.RateLimiter
+	maxSyncJitter                    time.Duration
+	syncTimeout                      time.Duration
+	metrics                          *impersonatorMetrics
 
 	hasControlPlaneNodes              *bool
 	serverStopCh                      chan struct{}
+	serverPort                        int
+	serverReady                       bool
 	errorCh                           chan error
 	tlsServingCertDynamicCertProvider dynamiccert.Private
+	tlsServingCertNotAfter            time.Time
 	infoLog                           logr.Logger
 	debugLog                          logr.Logger
+	initialSyncDelayed                bool
+	jitteredSyncNotBefore             time.Time
+
+	// The names of the generated Secrets which are used by the current sync, which may be overridden by the
+	// CredentialIssuer, and all names which were used for generated Secrets since this controller was started.
+	tlsSecretName        string
+	caSecretName         string
+	generatedSecretNames sets.String
+
+	// The owner references to set on the Services and Secrets created by the current sync, if any.
+	ownerReferences []metav1.OwnerReference
+
+	// The decisions made by the current sync, and the last decisions which were logged.
+	syncDecision       syncDecision
+	loggedSyncDecision *syncDecision
 }
 
+// syncDecision summarizes the decisions made by a sync, so that they can be logged whenever they change.
+type syncDecision struct {
+	mode           v1alpha1.ImpersonationProxyMode
+	effectiveMode  v1alpha1.ImpersonationProxyMode
+	serviceType    v1alpha1.ImpersonationProxyServiceType
+	caSecret       string
+	tlsSecret      string
+	strategyStatus v1alpha1.StrategyStatus
+	strategyReason v1alpha1.StrategyReason
+	strategyMsg    string
+}
+
+// What a sync did with the CA and TLS Secrets, as recorded in a syncDecision.
+const (
+	secretUnchanged = "unchanged"
+	secretCreated   = "created"
+	secretRenewed   = "renewed"
+	secretRotated   = "rotated"
+	secretReissued  = "reissued"
+	secretDeleted   = "deleted"
+)
+
 func NewImpersonatorConfigController(
 	namespace string,
 	credentialIssuerResourceName string,
@@ -97,19 +217,35 @@ func NewImpersonatorConfigController(
 	secretsInformer corev1informers.SecretInformer,
 	withInformer pinnipedcontroller.WithInformerOptionFunc,
 	impersonationProxyPort int,
+	impersonationProxyBindAddress net.IP,
+	controlPlaneNodeSelectors []labels.Selector,
+	shutdownTimeout time.Duration,
 	generatedLoadBalancerServiceName string,
 	generatedClusterIPServiceName string,
 	tlsSecretName string,
 	caSecretName string,
 	labels map[string]string,
+	setOwnerReferences bool,
+	annotateIssuedSecrets bool,
 	clock clock.Clock,
 	impersonatorFunc impersonator.FactoryFunc,
+	readinessProbe ReadinessProbeFunc,
 	impersonationSignerSecretName string,
 	impersonationSigningCertProvider dynamiccert.Provider,
+	certIssuanceRateLimiter flowcontrol.RateLimiter,
+	maxSyncJitter time.Duration,
+	syncTimeout time.Duration,
+	metricsRegisterer prometheus.Registerer,
 	log logr.Logger,
 ) controllerlib.Controller {
 	secretNames := sets.NewString(tlsSecretName, caSecretName, impersonationSignerSecretName)
 	log = log.WithName("impersonator-config-controller")
+	if syncTimeout <= 0 {
+		syncTimeout = DefaultSyncTimeout
+	}
+	if readinessProbe == nil {
+		readinessProbe = DialReadinessProbe
+	}
 	return controllerlib.New(
 		controllerlib.Config{
 			Name: "impersonator-config-controller",
@@ -117,10 +253,13 @@ func NewImpersonatorConfigController(
 				namespace:                         namespace,
 				credentialIssuerResourceName:      credentialIssuerResourceName,
 				impersonationProxyPort:            impersonationProxyPort,
+				impersonationProxyBindAddress:     impersonationProxyBindAddress,
+				controlPlaneNodeSelectors:         controlPlaneNodeSelectors,
+				shutdownTimeout:                   shutdownTimeout,
 				generatedLoadBalancerServiceName:  generatedLoadBalancerServiceName,
 				generatedClusterIPServiceName:     generatedClusterIPServiceName,
-				tlsSecretName:                     tlsSecretName,
-				caSecretName:                      caSecretName,
+				defaultTLSSecretName:              tlsSecretName,
+				defaultCASecretName:               caSecretName,
 				impersonationSignerSecretName:     impersonationSignerSecretName,
 				k8sClient:                         k8sClient,
 				pinnipedAPIClient:                 pinnipedAPIClient,
@@ -128,12 +267,22 @@ func NewImpersonatorConfigController(
 				servicesInformer:                  servicesInformer,
 				secretsInformer:                   secretsInformer,
 				labels:                            labels,
+				setOwnerReferences:                setOwnerReferences,
+				annotateIssuedSecrets:             annotateIssuedSecrets,
 				clock:                             clock,
 				impersonationSigningCertProvider:  impersonationSigningCertProvider,
 				impersonatorFunc:                  impersonatorFunc,
+				readinessProbe:                    readinessProbe,
+				certIssuanceRateLimiter:           certIssuanceRateLimiter,
+				maxSyncJitter:                     maxSyncJitter,
+				syncTimeout:                       syncTimeout,
+				metrics:                           newImpersonatorMetrics(metricsRegisterer),
 				tlsServingCertDynamicCertProvider: dynamiccert.NewServingCert("impersonation-proxy-serving-cert"),
 				infoLog:                           log.V(2),
 				debugLog:                          log.V(4),
+				tlsSecretName:                     tlsSecretName,
+				caSecretName:                      caSecretName,
+				generatedSecretNames:              sets.NewString(tlsSecretName, caSecretName),
 			},
 		},
 		withInformer(credentialIssuerInformer,
@@ -152,80 +301,266 @@ func NewImpersonatorConfigController(
 				case generatedLoadBalancerServiceName, generatedClusterIPServiceName:
 					return true
 				default:
-					return false
+					return obj.GetName() == existingServiceName(credentialIssuerInformer, credentialIssuerResourceName)
 				}
 			}),
 			controllerlib.InformerOption{},
 		),
 		withInformer(
 			secretsInformer,
-			pinnipedcontroller.SimpleFilterWithSingletonQueue(func(obj metav1.Object) bool {
-				return obj.GetNamespace() == namespace && secretNames.Has(obj.GetName())
+			pinnipedcontroller.SimpleFilter(func(obj metav1.Object) bool {
+				if obj.GetNamespace() != namespace {
+					return false
+				}
+				return secretNames.Has(obj.GetName()) || referencedSecretNames(credentialIssuerInformer, credentialIssuerResourceName).Has(obj.GetName())
+			}, func(_ metav1.Object) controllerlib.Key {
+				return jitteredSyncKey
 			}),
 			controllerlib.InformerOption{},
 		),
 	)
 }
 
+// delaySyncWithJitter returns true when the sync should be skipped because it was triggered by the initial event or
+// by a change to one of the Secrets, in which case a real sync is enqueued after a random delay of at most
+// maxSyncJitter. Triggers which happen while such a delayed sync is pending are coalesced into it. Syncs which are
+// triggered by changes to the CredentialIssuer or to the Service are never delayed, except for the initial one.
+func (c *impersonatorConfigController) delaySyncWithJitter(syncCtx controllerlib.Context) bool {
+	if c.maxSyncJitter <= 0 {
+		return false
+	}
+
+	// The delayed sync must not be delayed again, so it never uses the jittered key.
+	key := undelayedSyncKey(syncCtx.Key)
+
+	if !c.initialSyncDelayed {
+		c.initialSyncDelayed = true
+		c.enqueueJitteredSync(syncCtx.Queue, key, c.clock.Now())
+		return true
+	}
+
+	if syncCtx.Key != jitteredSyncKey {
+		return false
+	}
+
+	now := c.clock.Now()
+	if now.Before(c.jitteredSyncNotBefore) {
+		c.debugLog.Info("coalescing sync into already delayed sync", "notBefore", c.jitteredSyncNotBefore)
+		return true
+	}
+
+	c.enqueueJitteredSync(syncCtx.Queue, key, now)
+	return true
+}
+
+// undelayedSyncKey returns the key to enqueue for a sync which must not be delayed with jitter.
+func undelayedSyncKey(key controllerlib.Key) controllerlib.Key {
+	if key == jitteredSyncKey {
+		return controllerlib.Key{}
+	}
+	return key
+}
+
+// enqueueJitteredSync enqueues the key after a random delay between half of maxSyncJitter and maxSyncJitter.
+func (c *impersonatorConfigController) enqueueJitteredSync(queue controllerlib.Queue, key controllerlib.Key, now time.Time) {
+	delay := wait.Jitter(c.maxSyncJitter/2, 1)
+	if delay > c.maxSyncJitter {
+		delay = c.maxSyncJitter
+	}
+	c.jitteredSyncNotBefore = now.Add(delay)
+	c.debugLog.Info("delaying sync", "key", key, "delay", delay)
+	queue.AddAfter(key, delay)
+}
+
+// existingServiceName returns the name of the Service which was provisioned by other means for the impersonation proxy,
+// or an empty string when the CredentialIssuer does not reference such a Service.
+func existingServiceName(credentialIssuerInformer conciergeconfiginformers.CredentialIssuerInformer, credentialIssuerResourceName string) string {
+	credIssuer, err := credentialIssuerInformer.Lister().Get(credentialIssuerResourceName)
+	if err != nil || credIssuer.Spec.ImpersonationProxy == nil {
+		return ""
+	}
+	if credIssuer.Spec.ImpersonationProxy.Service.Type != v1alpha1.ImpersonationProxyServiceTypeExisting {
+		return ""
+	}
+	return credIssuer.Spec.ImpersonationProxy.Service.ExistingServiceName
+}
+
+// referencedSecretNames returns the names of the Secrets which are referenced by the CredentialIssuer, i.e. the Secret
+// which contains the TLS serving certificate which was provided by the operator and the overridden names of the
+// generated Secrets, if any.
+func referencedSecretNames(credentialIssuerInformer conciergeconfiginformers.CredentialIssuerInformer, credentialIssuerResourceName string) sets.String {
+	names := sets.NewString()
+	credIssuer, err := credentialIssuerInformer.Lister().Get(credentialIssuerResourceName)
+	if err != nil || credIssuer.Spec.ImpersonationProxy == nil {
+		return names
+	}
+	for _, name := range []string{
+		credIssuer.Spec.ImpersonationProxy.TLSCertificateSecretName,
+		credIssuer.Spec.ImpersonationProxy.GeneratedCASecretName,
+		credIssuer.Spec.ImpersonationProxy.GeneratedTLSSecretName,
+	} {
+		if name != "" {
+			names.Insert(name)
+		}
+	}
+	return names
+}
+
 func (c *impersonatorConfigController) Sync(syncCtx controllerlib.Context) error {
 	c.debugLog.Info("starting impersonatorConfigController Sync")
 
+	if c.delaySyncWithJitter(syncCtx) {
+		return nil
+	}
+
 	// Load the CredentialIssuer that we'll update with status.
 	credIssuer, err := c.credIssuerInformer.Lister().Get(c.credentialIssuerResourceName)
 	if err != nil {
 		return fmt.Errorf("could not get CredentialIssuer to update: %w", err)
 	}
 
-	strategy, err := c.doSync(syncCtx, credIssuer)
+	c.syncDecision = syncDecision{caSecret: secretUnchanged, tlsSecret: secretUnchanged}
+	c.ownerReferences = nil
+	if c.setOwnerReferences {
+		c.ownerReferences = credentialIssuerOwnerReferences(credIssuer)
+	}
+	// Bound the time which the sync may take, but keep using the unbounded context to update the status below, so that
+	// running out of time is reported on the CredentialIssuer.
+	ctx, cancel := context.WithTimeout(syncCtx.Context, c.syncTimeout)
+	defer cancel()
+	boundedSyncCtx := syncCtx
+	boundedSyncCtx.Context = ctx
+
+	strategy, err := c.doSync(boundedSyncCtx, credIssuer)
+	if err != nil && stderrors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("sync did not finish within %s: %w", c.syncTimeout, err)
+	}
 	if err != nil {
 		strategy = &v1alpha1.CredentialIssuerStrategy{
 			Type:           v1alpha1.ImpersonationProxyStrategyType,
 			Status:         v1alpha1.ErrorStrategyStatus,
 			Reason:         strategyReasonForError(err),
-			Message:        err.Error(),
+			Message:        strategyMessageForError(err),
 			LastUpdateTime: metav1.NewTime(c.clock.Now()),
 		}
 	}
+	c.logSyncDecisionWhenChanged(credIssuer, strategy)
+
+	// When a create failed because the object already exists, the informer cache is stale, e.g. because this or
+	// another instance of the Concierge just created it. That is not a failure, so requeue to recheck once the
+	// informer has caught up.
+	staleCache := err != nil && k8serrors.IsAlreadyExists(err)
 
-	err = utilerrors.NewAggregate([]error{err, issuerconfig.Update(
+	updateErr := issuerconfig.Update(
 		syncCtx.Context,
 		c.pinnipedAPIClient,
 		credIssuer,
 		*strategy,
-	)})
+	)
+	if staleCache && updateErr == nil {
+		c.debugLog.Info("requeuing impersonatorConfigController Sync because the informer cache is stale", "reason", err.Error())
+		return controllerlib.ErrSyntheticRequeue
+	}
 
+	err = utilerrors.NewAggregate([]error{err, updateErr})
 	if err == nil {
 		c.debugLog.Info("successfully finished impersonatorConfigController Sync")
 	}
 	return err
 }
 
+// logSyncDecisionWhenChanged logs a summary of the decisions made by the current sync, but only when they differ
+// from the last summary which was logged or when a Secret was changed, to keep the log volume low.
+func (c *impersonatorConfigController) logSyncDecisionWhenChanged(credIssuer *v1alpha1.CredentialIssuer, strategy *v1alpha1.CredentialIssuerStrategy) {
+	decision := c.syncDecision
+	decision.strategyStatus = strategy.Status
+	decision.strategyReason = strategy.Reason
+	decision.strategyMsg = strategy.Message
+	if c.loggedSyncDecision != nil && *c.loggedSyncDecision == decision {
+		return
+	}
+
+	c.infoLog.Info("impersonation proxy sync decisions changed",
+		"credentialIssuer", klog.KObj(credIssuer),
+		"mode", decision.mode,
+		"effectiveMode", decision.effectiveMode,
+		"serviceType", decision.serviceType,
+		"caSecret", decision.caSecret,
+		"tlsSecret", decision.tlsSecret,
+		"strategyStatus", decision.strategyStatus,
+		"strategyReason", decision.strategyReason,
+		"strategyMessage", decision.strategyMsg,
+	)
+
+	// Secrets which were just changed will be unchanged by the next sync unless something else happens.
+	decision.caSecret = secretUnchanged
+	decision.tlsSecret = secretUnchanged
+	c.loggedSyncDecision = &decision
+}
+
 // strategyReasonForError returns the proper v1alpha1.StrategyReason for a sync error. Some errors are occasionally
 // expected because there are multiple pods running, in these cases we should  report a Pending reason and we'll
-// recover on a following sync.
+// recover on a following sync. Otherwise, the reason is the category of the SetupError, when there is one.
 func strategyReasonForError(err error) v1alpha1.StrategyReason {
+	var setupErr *SetupError
 	switch {
 	case k8serrors.IsConflict(err), k8serrors.IsAlreadyExists(err):
 		return v1alpha1.PendingStrategyReason
+	case stderrors.As(err, &setupErr):
+		return setupErr.strategyReason()
 	default:
 		return v1alpha1.ErrorDuringSetupStrategyReason
 	}
 }
 
+// strategyMessageForError returns the message of the strategy for a sync error. When a Secret which was just written
+// by this or another pod is not yet in the informer cache, the message says so, because the sync will recover once the
+// informer catches up.
+func strategyMessageForError(err error) string {
+	var statusErr k8serrors.APIStatus
+	if strategyReasonForError(err) == v1alpha1.PendingStrategyReason && stderrors.As(err, &statusErr) {
+		if details := statusErr.Status().Details; details != nil {
+			switch details.Kind {
+			case "secrets":
+				return pendingMessageWaitingForSecret + ": " + err.Error()
+			case "services":
+				return pendingMessageWaitingForService + ": " + err.Error()
+			}
+		}
+	}
+	return err.Error()
+}
+
+// The messages of the pending strategy, which tell operators what the impersonation proxy is waiting for.
+const (
+	pendingMessageWaitingForLoadBalancer = "waiting for load balancer Service to be assigned IP or hostname"
+	pendingMessageWaitingForClusterIP    = "waiting for ClusterIP Service to be assigned a cluster IP"
+	pendingMessageWaitingForSecret       = "waiting for Secret to propagate to the informer cache"
+	pendingMessageWaitingForService      = "waiting for Service to propagate to the informer cache"
+	pendingMessageWaitingForReadiness    = "waiting for impersonation proxy to become ready"
+)
+
 type certNameInfo struct {
 	// ready will be true when the certificate name information is known.
-	// ready will be false when it is pending because we are waiting for a load balancer to get assigned an ip/hostname.
-	// When false, the other fields in this struct should not be considered meaningful and may be zero values.
+	// ready will be false when it is pending because we are waiting for a Service to get assigned an ip/hostname.
+	// When false, the other fields in this struct should not be considered meaningful and may be zero values,
+	// except for pendingMessage.
 	ready bool
 
-	// The IP address or hostname which was selected to be used as the name in the cert.
-	// Either selectedIP or selectedHostname will be set, but not both.
-	selectedIPs      []net.IP
-	selectedHostname string
+	// The reason why the certificate name information is not known yet, for the message of the pending strategy.
+	pendingMessage string
+
+	// The IP addresses and hostnames which were selected to be used as the names in the cert.
+	// At least one IP address or hostname will be set.
+	selectedIPs       []net.IP
+	selectedHostnames []string
 
 	// The name of the endpoint to which a client should connect to talk to the impersonator.
 	// This may be a hostname or an IP, and may include a port number.
 	clientEndpoint string
+
+	// The Common Name of the cert. This is only set when a preferred load balancer address type was configured.
+	commonName string
 }
 
 func (c *impersonatorConfigController) doSync(syncCtx controllerlib.Context, credIssuer *v1alpha1.CredentialIssuer) (*v1alpha1.CredentialIssuerStrategy, error) {
@@ -233,15 +568,17 @@ func (c *impersonatorConfigController) doSync(syncCtx controllerlib.Context, cre
 
 	impersonationSpec, err := c.loadImpersonationProxyConfiguration(credIssuer)
 	if err != nil {
-		return nil, err
+		return nil, newSetupError(ValidationError, err)
 	}
+	c.syncDecision.mode = impersonationSpec.Mode
+	c.syncDecision.serviceType = impersonationSpec.Service.Type
 
 	// Make a live API call to avoid the cost of having an informer watch all node changes on the cluster,
 	// since there could be lots and we don't especially care about node changes.
 	// Once we have concluded that there is or is not a visible control plane, then cache that decision
 	// to avoid listing nodes very often.
 	if c.hasControlPlaneNodes == nil {
-		hasControlPlaneNodes, err := clusterhost.New(c.k8sClient).HasControlPlaneNodes(ctx)
+		hasControlPlaneNodes, err := clusterhost.NewWithControlPlaneNodeSelectors(c.k8sClient, c.controlPlaneNodeSelectors).HasControlPlaneNodes(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -249,69 +586,131 @@ func (c *impersonatorConfigController) doSync(syncCtx controllerlib.Context, cre
 		c.debugLog.Info("queried for control plane nodes", "foundControlPlaneNodes", hasControlPlaneNodes)
 	}
 
+	c.syncDecision.effectiveMode = v1alpha1.ImpersonationProxyModeDisabled
 	if c.shouldHaveImpersonator(impersonationSpec) {
-		if err = c.ensureImpersonatorIsStarted(syncCtx); err != nil {
-			return nil, err
+		c.syncDecision.effectiveMode = v1alpha1.ImpersonationProxyModeEnabled
+	}
+
+	if c.shouldHaveImpersonator(impersonationSpec) {
+		if err = c.ensureImpersonatorIsStarted(syncCtx, c.desiredImpersonationProxyPort(impersonationSpec)); err != nil {
+			return nil, newSetupError(ServerStartError, err)
 		}
 	} else {
 		if err = c.ensureImpersonatorIsStopped(true); err != nil {
-			return nil, err
+			return nil, newSetupError(ServerStartError, err)
 		}
 	}
 
 	if c.shouldHaveLoadBalancer(impersonationSpec) {
 		if err = c.ensureLoadBalancerIsStarted(ctx, impersonationSpec); err != nil {
-			return nil, err
+			return nil, newSetupError(ServiceError, err)
 		}
 	} else {
 		if err = c.ensureLoadBalancerIsStopped(ctx); err != nil {
-			return nil, err
+			return nil, newSetupError(ServiceError, err)
 		}
 	}
 
 	if c.shouldHaveClusterIPService(impersonationSpec) {
 		if err = c.ensureClusterIPServiceIsStarted(ctx, impersonationSpec); err != nil {
-			return nil, err
+			return nil, newSetupError(ServiceError, err)
 		}
 	} else {
 		if err = c.ensureClusterIPServiceIsStopped(ctx); err != nil {
-			return nil, err
+			return nil, newSetupError(ServiceError, err)
 		}
 	}
 
 	nameInfo, err := c.findDesiredTLSCertificateName(impersonationSpec)
 	if err != nil {
+		return nil, newSetupError(ServiceError, err)
+	}
+
+	// Generating certificates cannot be interrupted, so do not start when the sync has already run out of time.
+	if err = ctx.Err(); err != nil {
 		return nil, err
 	}
 
-	var impersonationCA *certauthority.CA
-	if c.shouldHaveImpersonator(impersonationSpec) {
-		if impersonationCA, err = c.ensureCASecretIsCreated(ctx); err != nil {
-			return nil, err
+	var caBundle []byte
+	switch {
+	case c.shouldHaveImpersonator(impersonationSpec) && impersonationSpec.TLSCertificateSecretName != "":
+		// The operator provides the serving certificate, so there is no need for a generated one.
+		if err = c.ensureTLSSecretIsRemoved(ctx); err != nil {
+			return nil, newSetupError(SecretError, err)
 		}
-		if err = c.ensureTLSSecret(ctx, nameInfo, impersonationCA); err != nil {
-			return nil, err
+		// The CA data was already validated by loadImpersonationProxyConfiguration.
+		caBundle, _ = base64.StdEncoding.DecodeString(impersonationSpec.TLSCertificateAuthorityData)
+		if err = c.ensureOperatorTLSSecretIsLoaded(impersonationSpec, nameInfo, caBundle); err != nil {
+			return nil, newSetupError(SecretError, err)
 		}
-	} else {
+	case c.shouldHaveImpersonator(impersonationSpec):
+		impersonationCA, previousCACertPEM, err := c.ensureCASecretIsCreated(ctx, impersonationSpec, credIssuer.Annotations[caRotationRequestAnnotationKey])
+		if err != nil {
+			return nil, newSetupError(CAError, err)
+		}
+		if err = c.ensureTLSSecret(ctx, impersonationSpec, nameInfo, impersonationCA); err != nil {
+			return nil, newSetupError(SecretError, err)
+		}
+		previousCACertPEM, err = c.trimPreviousCACertWhenTLSSecretWasReissued(ctx, impersonationCA, previousCACertPEM)
+		if err != nil {
+			return nil, newSetupError(CAError, err)
+		}
+		caBundle = caBundleForClients(impersonationCA, previousCACertPEM)
+	default:
 		if err = c.ensureTLSSecretIsRemoved(ctx); err != nil {
-			return nil, err
+			return nil, newSetupError(SecretError, err)
 		}
 		c.clearTLSSecret()
 	}
 
-	credentialIssuerStrategyResult := c.doSyncResult(nameInfo, impersonationSpec, impersonationCA)
+	if err = c.ensureRenamedSecretsAreRemoved(ctx, impersonationSpec); err != nil {
+		return nil, newSetupError(SecretError, err)
+	}
+
+	credentialIssuerStrategyResult := c.doSyncResult(nameInfo, impersonationSpec, caBundle)
 
 	if c.shouldHaveImpersonator(impersonationSpec) {
 		if err = c.loadSignerCA(); err != nil {
-			return nil, err
+			return nil, newSetupError(CAError, err)
 		}
 	} else {
 		c.clearSignerCA()
 	}
 
+	// The server starts serving in the background, so do not advertise it to clients until it accepts connections.
+	if credentialIssuerStrategyResult.Status == v1alpha1.SuccessStrategyStatus && !c.impersonatorIsReady(syncCtx) {
+		credentialIssuerStrategyResult = &v1alpha1.CredentialIssuerStrategy{
+			Type:           v1alpha1.ImpersonationProxyStrategyType,
+			Status:         v1alpha1.ErrorStrategyStatus,
+			Reason:         v1alpha1.PendingStrategyReason,
+			Message:        pendingMessageWaitingForReadiness,
+			LastUpdateTime: metav1.NewTime(c.clock.Now()),
+		}
+	}
+
 	return credentialIssuerStrategyResult, nil
 }
 
+// impersonatorIsReady returns whether the running impersonation proxy accepts client connections. Once the proxy was
+// found to be ready, it is not probed again until it is restarted. When it is not ready yet, another sync is enqueued.
+func (c *impersonatorConfigController) impersonatorIsReady(syncCtx controllerlib.Context) bool {
+	if c.serverReady {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(syncCtx.Context, readinessProbeTimeout)
+	defer cancel()
+	if err := c.readinessProbe(ctx, c.impersonationProxyBindAddress, c.serverPort); err != nil {
+		c.debugLog.Info("impersonation proxy is not ready yet", "port", c.serverPort, "err", err.Error())
+		syncCtx.Queue.AddAfter(undelayedSyncKey(syncCtx.Key), readinessRetryInterval)
+		return false
+	}
+
+	c.infoLog.Info("impersonation proxy is ready", "port", c.serverPort)
+	c.serverReady = true
+	return true
+}
+
 func (c *impersonatorConfigController) loadImpersonationProxyConfiguration(credIssuer *v1alpha1.CredentialIssuer) (*v1alpha1.ImpersonationProxySpec, error) {
 	// Make a copy of the spec since we got this object from informer cache.
 	spec := credIssuer.Spec.DeepCopy().ImpersonationProxy
@@ -324,9 +723,38 @@ func (c *impersonatorConfigController) loadImpersonationProxyConfiguration(credI
 		spec.Service.Type = v1alpha1.ImpersonationProxyServiceTypeLoadBalancer
 	}
 
-	if err := validateCredentialIssuerSpec(spec); err != nil {
+	if err := ValidateImpersonationProxySpec(spec); err != nil {
 		return nil, fmt.Errorf("could not load CredentialIssuer spec.impersonationProxy: %w", err)
 	}
+
+	// The generated Services are deleted whenever they are not wanted, so an existing Service must not share their names.
+	if spec.Service.Type == v1alpha1.ImpersonationProxyServiceTypeExisting {
+		switch spec.Service.ExistingServiceName {
+		case c.generatedLoadBalancerServiceName, c.generatedClusterIPServiceName:
+			return nil, fmt.Errorf("could not load CredentialIssuer spec.impersonationProxy: invalid service existingServiceName %q (must not be the name of a Service which is managed by the Concierge)",
+				spec.Service.ExistingServiceName)
+		}
+	}
+	// Each generated Secret must have its own name, which must also differ from the name of the signer's Secret.
+	tlsSecretName, caSecretName := c.defaultTLSSecretName, c.defaultCASecretName
+	if spec.GeneratedTLSSecretName != "" {
+		tlsSecretName = spec.GeneratedTLSSecretName
+	}
+	if spec.GeneratedCASecretName != "" {
+		caSecretName = spec.GeneratedCASecretName
+	}
+	if tlsSecretName == caSecretName || tlsSecretName == c.impersonationSignerSecretName || caSecretName == c.impersonationSignerSecretName {
+		return nil, fmt.Errorf("could not load CredentialIssuer spec.impersonationProxy: the generated CA Secret %q, the generated TLS Secret %q, and the signer Secret %q must have different names",
+			caSecretName, tlsSecretName, c.impersonationSignerSecretName)
+	}
+	// The generated Secrets are deleted or overwritten by this controller, so the operator's Secret must not share their names.
+	switch spec.TLSCertificateSecretName {
+	case tlsSecretName, caSecretName, c.impersonationSignerSecretName:
+		return nil, fmt.Errorf("could not load CredentialIssuer spec.impersonationProxy: invalid tlsCertificateSecretName %q (must not be the name of a Secret which is managed by the Concierge)",
+			spec.TLSCertificateSecretName)
+	}
+	c.tlsSecretName, c.caSecretName = tlsSecretName, caSecretName
+	c.generatedSecretNames.Insert(tlsSecretName, caSecretName)
 	c.debugLog.Info("read impersonation proxy config", "credentialIssuer", c.credentialIssuerResourceName)
 	return spec, nil
 }
@@ -347,6 +775,24 @@ func (c *impersonatorConfigController) disabledExplicitly(config *v1alpha1.Imper
 	return config.Mode == v1alpha1.ImpersonationProxyModeDisabled
 }
 
+// desiredImpersonationProxyPort returns the port from the CredentialIssuer spec when it is set, or otherwise
+// the port from the static configuration which was passed to the controller's constructor.
+func (c *impersonatorConfigController) desiredImpersonationProxyPort(config *v1alpha1.ImpersonationProxySpec) int {
+	if config.Port != nil {
+		return int(*config.Port)
+	}
+	return c.impersonationProxyPort
+}
+
+// desiredServicePort returns the port of the provisioned Service from the CredentialIssuer spec when it is set, or
+// otherwise the default HTTPS port.
+func desiredServicePort(config *v1alpha1.ImpersonationProxySpec) int32 {
+	if config.Service.Port != nil {
+		return *config.Service.Port
+	}
+	return defaultHTTPSPort
+}
+
 func (c *impersonatorConfigController) shouldHaveLoadBalancer(config *v1alpha1.ImpersonationProxySpec) bool {
 	return c.shouldHaveImpersonator(config) && config.Service.Type == v1alpha1.ImpersonationProxyServiceTypeLoadBalancer
 }
@@ -379,7 +825,7 @@ func (c *impersonatorConfigController) tlsSecretExists() (bool, *v1.Secret, erro
 	return true, secret, nil
 }
 
-func (c *impersonatorConfigController) ensureImpersonatorIsStarted(syncCtx controllerlib.Context) error {
+func (c *impersonatorConfigController) ensureImpersonatorIsStarted(syncCtx controllerlib.Context, port int) error {
 	if c.serverStopCh != nil {
 		// The server was already started, but it could have died in the background, so make a non-blocking
 		// check to see if it has sent any errors on the errorCh.
@@ -398,25 +844,38 @@ func (c *impersonatorConfigController) ensureImpersonatorIsStarted(syncCtx contr
 			stoppingErr := c.ensureImpersonatorIsStopped(false)
 			return errors.NewAggregate([]error{runningErr, stoppingErr})
 		default:
-			// Seems like it is still running, so nothing to do.
-			return nil
+			// Seems like it is still running, so nothing to do unless it is listening on the wrong port.
+			if c.serverPort == port {
+				return nil
+			}
+			// The desired port has changed, so stop the server and start it again below on the new port.
+			c.infoLog.Info("impersonation proxy port changed", "oldPort", c.serverPort, "newPort", port)
+			if err := c.ensureImpersonatorIsStopped(true); err != nil {
+				return err
+			}
 		}
 	}
 
-	c.infoLog.Info("starting impersonation proxy", "port", c.impersonationProxyPort)
+	c.infoLog.Info("starting impersonation proxy", "port", port)
 	startImpersonatorFunc, err := c.impersonatorFunc(
-		c.impersonationProxyPort,
+		port,
+		c.impersonationProxyBindAddress,
 		c.tlsServingCertDynamicCertProvider,
 		c.impersonationSigningCertProvider,
+		c.shutdownTimeout,
 	)
 	if err != nil {
 		return err
 	}
 
-	c.serverStopCh = make(chan struct{})
+	stopCh := make(chan struct{})
 	// use a buffered channel so that startImpersonatorFunc can send
 	// on it without coordinating with the main controller go routine
-	c.errorCh = make(chan error, 1)
+	errorCh := make(chan error, 1)
+	c.serverStopCh = stopCh
+	c.serverPort = port
+	c.serverReady = false
+	c.errorCh = errorCh
 
 	// startImpersonatorFunc will block until the server shuts down (or fails to start), so run it in the background.
 	go func() {
@@ -427,7 +886,8 @@ func (c *impersonatorConfigController) ensureImpersonatorIsStarted(syncCtx contr
 		defer syncCtx.Queue.AddRateLimited(syncCtx.Key)
 
 		// Forward any errors returned by startImpersonatorFunc on the errorCh.
-		c.errorCh <- startImpersonatorFunc(c.serverStopCh)
+		// Use the local copies of the channels because the controller forgets about them once it stops the server.
+		errorCh <- startImpersonatorFunc(stopCh)
 	}()
 
 	return nil
@@ -438,15 +898,24 @@ func (c *impersonatorConfigController) ensureImpersonatorIsStopped(shouldCloseEr
 		return nil
 	}
 
-	c.infoLog.Info("stopping impersonation proxy", "port", c.impersonationProxyPort)
+	c.infoLog.Info("stopping impersonation proxy", "port", c.serverPort)
 	close(c.serverStopCh)
-	stopErr := <-c.errorCh
 
-	if shouldCloseErrChan {
-		close(c.errorCh)
+	var stopErr error
+	select {
+	case stopErr = <-c.errorCh:
+		if shouldCloseErrChan {
+			close(c.errorCh)
+		}
+	case <-c.clock.After(c.shutdownTimeout + stopGracePeriod):
+		// Don't let a hung server block the sync loop forever. The errorCh is buffered, so the background
+		// go routine can still send on it whenever the server finally stops, and therefore it must not be closed.
+		stopErr = fmt.Errorf("timed out waiting for impersonation proxy to stop after %s", c.shutdownTimeout+stopGracePeriod)
 	}
 
 	c.serverStopCh = nil
+	c.serverPort = 0
+	c.serverReady = false
 	c.errorCh = nil
 
 	return stopErr
@@ -459,22 +928,33 @@ func (c *impersonatorConfigController) ensureLoadBalancerIsStarted(ctx context.C
 			Type: v1.ServiceTypeLoadBalancer,
 			Ports: []v1.ServicePort{
 				{
-					TargetPort: intstr.FromInt(c.impersonationProxyPort),
-					Port:       defaultHTTPSPort,
+					TargetPort: intstr.FromInt(c.desiredImpersonationProxyPort(config)),
+					Port:       desiredServicePort(config),
 					Protocol:   v1.ProtocolTCP,
 				},
 			},
-			LoadBalancerIP: config.Service.LoadBalancerIP,
-			Selector:       map[string]string{appLabelKey: appNameLabel},
+			LoadBalancerIP:           config.Service.LoadBalancerIP,
+			LoadBalancerSourceRanges: config.Service.LoadBalancerSourceRanges,
+			LoadBalancerClass:        config.Service.LoadBalancerClass,
+			ExternalTrafficPolicy:    desiredExternalTrafficPolicy(config),
+			Selector:                 map[string]string{appLabelKey: appNameLabel},
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        c.generatedLoadBalancerServiceName,
-			Namespace:   c.namespace,
-			Labels:      c.labels,
-			Annotations: config.Service.Annotations,
+			Name:            c.generatedLoadBalancerServiceName,
+			Namespace:       c.namespace,
+			Labels:          c.labels,
+			Annotations:     config.Service.Annotations,
+			OwnerReferences: c.ownerReferences,
 		},
 	}
-	return c.createOrUpdateService(ctx, &loadBalancer)
+	return c.createOrUpdateService(ctx, &loadBalancer, config.Service.Unmanaged)
+}
+
+func desiredExternalTrafficPolicy(config *v1alpha1.ImpersonationProxySpec) v1.ServiceExternalTrafficPolicyType {
+	if config.Service.ExternalTrafficPolicy == v1alpha1.ImpersonationProxyServiceExternalTrafficPolicyLocal {
+		return v1.ServiceExternalTrafficPolicyTypeLocal
+	}
+	return v1.ServiceExternalTrafficPolicyTypeCluster
 }
 
 func (c *impersonatorConfigController) ensureLoadBalancerIsStopped(ctx context.Context) error {
@@ -505,21 +985,23 @@ func (c *impersonatorConfigController) ensureClusterIPServiceIsStarted(ctx conte
 			Type: v1.ServiceTypeClusterIP,
 			Ports: []v1.ServicePort{
 				{
-					TargetPort: intstr.FromInt(c.impersonationProxyPort),
-					Port:       defaultHTTPSPort,
+					TargetPort: intstr.FromInt(c.desiredImpersonationProxyPort(config)),
+					Port:       desiredServicePort(config),
 					Protocol:   v1.ProtocolTCP,
 				},
 			},
-			Selector: map[string]string{appLabelKey: appNameLabel},
+			ClusterIP: config.Service.ClusterIP,
+			Selector:  map[string]string{appLabelKey: appNameLabel},
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        c.generatedClusterIPServiceName,
-			Namespace:   c.namespace,
-			Labels:      c.labels,
-			Annotations: config.Service.Annotations,
+			Name:            c.generatedClusterIPServiceName,
+			Namespace:       c.namespace,
+			Labels:          c.labels,
+			Annotations:     config.Service.Annotations,
+			OwnerReferences: c.ownerReferences,
 		},
 	}
-	return c.createOrUpdateService(ctx, &clusterIP)
+	return c.createOrUpdateService(ctx, &clusterIP, config.Service.Unmanaged)
 }
 
 func (c *impersonatorConfigController) ensureClusterIPServiceIsStopped(ctx context.Context) error {
@@ -543,7 +1025,21 @@ func (c *impersonatorConfigController) ensureClusterIPServiceIsStopped(ctx conte
 	return utilerrors.FilterOut(err, k8serrors.IsNotFound)
 }
 
-func (c *impersonatorConfigController) createOrUpdateService(ctx context.Context, desiredService *v1.Service) error {
+// credentialIssuerOwnerReferences returns the owner references which make the CredentialIssuer the owner of the
+// Services and Secrets created by this controller, so that they are garbage collected when it is deleted. The
+// CredentialIssuer is cluster-scoped, so it may own objects in any namespace.
+func credentialIssuerOwnerReferences(credIssuer *v1alpha1.CredentialIssuer) []metav1.OwnerReference {
+	return []metav1.OwnerReference{{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       "CredentialIssuer",
+		Name:       credIssuer.Name,
+		UID:        credIssuer.UID,
+	}}
+}
+
+// createOrUpdateService creates the desired Service when it does not exist. Otherwise, unless the Service is unmanaged,
+// it updates the fields of the existing Service which are part of the desired state.
+func (c *impersonatorConfigController) createOrUpdateService(ctx context.Context, desiredService *v1.Service, unmanaged bool) error {
 	log := c.infoLog.WithValues("serviceType", desiredService.Spec.Type, "service", klog.KObj(desiredService))
 
 	// Prepare to remember which annotation keys were added from the CredentialIssuer spec, both for
@@ -577,13 +1073,67 @@ func (c *impersonatorConfigController) createOrUpdateService(ctx context.Context
 		return err
 	}
 
+	// Leave an unmanaged Service as it is, so that changes made by other actors are not reverted.
+	if unmanaged {
+		c.debugLog.Info("not updating unmanaged service for impersonation proxy", "service", klog.KObj(existingService))
+		return nil
+	}
+
+	// The cluster IP and the load balancer class of an existing Service cannot be changed, so recreate the Service to
+	// request different ones. A load balancer class which was removed from the spec is left as it is on the Service.
+	clusterIPChanged := desiredService.Spec.ClusterIP != "" && desiredService.Spec.ClusterIP != existingService.Spec.ClusterIP
+	loadBalancerClassChanged := desiredService.Spec.LoadBalancerClass != nil &&
+		(existingService.Spec.LoadBalancerClass == nil || *desiredService.Spec.LoadBalancerClass != *existingService.Spec.LoadBalancerClass)
+	if clusterIPChanged || loadBalancerClassChanged {
+		if clusterIPChanged {
+			log.Info("recreating service for impersonation proxy to change its cluster IP",
+				"oldClusterIP", existingService.Spec.ClusterIP,
+				"newClusterIP", desiredService.Spec.ClusterIP,
+			)
+		}
+		if loadBalancerClassChanged {
+			log.Info("recreating service for impersonation proxy to change its load balancer class, "+
+				"because the load balancer class of an existing service cannot be changed",
+				"oldLoadBalancerClass", pointer.StringDeref(existingService.Spec.LoadBalancerClass, ""),
+				"newLoadBalancerClass", *desiredService.Spec.LoadBalancerClass,
+			)
+		}
+		err = c.k8sClient.CoreV1().Services(c.namespace).Delete(ctx, existingService.Name, metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{
+				UID:             &existingService.UID,
+				ResourceVersion: &existingService.ResourceVersion,
+			},
+		})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+		_, err = c.k8sClient.CoreV1().Services(c.namespace).Create(ctx, desiredService, metav1.CreateOptions{})
+		return err
+	}
+
 	// The Service already exists, so update only the specific fields that are meaningfully part of our desired state.
 	updatedService := existingService.DeepCopy()
 	updatedService.ObjectMeta.Labels = desiredService.ObjectMeta.Labels
 	updatedService.Spec.LoadBalancerIP = desiredService.Spec.LoadBalancerIP
+	updatedService.Spec.LoadBalancerSourceRanges = desiredService.Spec.LoadBalancerSourceRanges
+	if desiredService.Spec.ExternalTrafficPolicy != "" {
+		// Only set for Service types which support it, i.e. not for a ClusterIP Service.
+		updatedService.Spec.ExternalTrafficPolicy = desiredService.Spec.ExternalTrafficPolicy
+	}
 	updatedService.Spec.Type = desiredService.Spec.Type
 	updatedService.Spec.Selector = desiredService.Spec.Selector
 
+	// Only update the ports and target ports of the existing ports, because the other fields of the ports, like the
+	// node port of a LoadBalancer Service, may have been filled in by the API server.
+	if len(updatedService.Spec.Ports) == len(desiredService.Spec.Ports) {
+		for i := range desiredService.Spec.Ports {
+			updatedService.Spec.Ports[i].Port = desiredService.Spec.Ports[i].Port
+			updatedService.Spec.Ports[i].TargetPort = desiredService.Spec.Ports[i].TargetPort
+		}
+	} else {
+		updatedService.Spec.Ports = desiredService.Spec.Ports
+	}
+
 	// Do not simply overwrite the existing annotations with the desired annotations. Instead, merge-overwrite.
 	// Another actor in the system, like a human user or a non-Pinniped controller, might have updated the
 	// existing Service's annotations. If they did, then we do not want to overwrite those keys expect for
@@ -633,29 +1183,34 @@ func (c *impersonatorConfigController) createOrUpdateService(ctx context.Context
 	return err
 }
 
-func (c *impersonatorConfigController) ensureTLSSecret(ctx context.Context, nameInfo *certNameInfo, ca *certauthority.CA) error {
+func (c *impersonatorConfigController) ensureTLSSecret(ctx context.Context, config *v1alpha1.ImpersonationProxySpec, nameInfo *certNameInfo, ca *certauthority.CA) error {
 	secretFromInformer, err := c.secretsInformer.Lister().Secrets(c.namespace).Get(c.tlsSecretName)
 	notFound := k8serrors.IsNotFound(err)
 	if !notFound && err != nil {
 		return err
 	}
 
+	issuanceReason := tlsIssuedReasonMissing
 	if !notFound {
-		secretWasDeleted, err := c.deleteTLSSecretWhenCertificateDoesNotMatchDesiredState(ctx, nameInfo, ca, secretFromInformer)
+		deletionReason, err := c.deleteTLSSecretWhenCertificateDoesNotMatchDesiredState(ctx, nameInfo, ca, secretFromInformer)
 		if err != nil {
 			return err
 		}
 		// If it was deleted by the above call, then set it to nil. This allows us to avoid waiting
 		// for the informer cache to update before deciding to proceed to create the new Secret below.
-		if secretWasDeleted {
+		if deletionReason != "" {
 			secretFromInformer = nil
+			issuanceReason = deletionReason
+			c.syncDecision.tlsSecret = secretDeleted
 		}
 	}
 
-	return c.ensureTLSSecretIsCreatedAndLoaded(ctx, nameInfo, secretFromInformer, ca)
+	return c.ensureTLSSecretIsCreatedAndLoaded(ctx, config, nameInfo, secretFromInformer, ca, issuanceReason)
 }
 
-func (c *impersonatorConfigController) deleteTLSSecretWhenCertificateDoesNotMatchDesiredState(ctx context.Context, nameInfo *certNameInfo, ca *certauthority.CA, secret *v1.Secret) (bool, error) {
+// deleteTLSSecretWhenCertificateDoesNotMatchDesiredState returns the reason why the TLS Secret was deleted,
+// or an empty string when the Secret was left in place.
+func (c *impersonatorConfigController) deleteTLSSecretWhenCertificateDoesNotMatchDesiredState(ctx context.Context, nameInfo *certNameInfo, ca *certauthority.CA, secret *v1.Secret) (string, error) {
 	certPEM := secret.Data[v1.TLSCertKey]
 	block, _ := pem.Decode(certPEM)
 	if block == nil {
@@ -665,9 +1220,9 @@ func (c *impersonatorConfigController) deleteTLSSecretWhenCertificateDoesNotMatc
 		)
 		deleteErr := c.ensureTLSSecretIsRemoved(ctx)
 		if deleteErr != nil {
-			return false, fmt.Errorf("found missing or not PEM-encoded data in TLS Secret, but got error while deleting it: %w", deleteErr)
+			return "", fmt.Errorf("found missing or not PEM-encoded data in TLS Secret, but got error while deleting it: %w", deleteErr)
 		}
-		return true, nil
+		return tlsIssuedReasonInvalid, nil
 	}
 
 	actualCertFromSecret, err := x509.ParseCertificate(block.Bytes)
@@ -677,9 +1232,9 @@ func (c *impersonatorConfigController) deleteTLSSecretWhenCertificateDoesNotMatc
 			"secret", klog.KObj(secret),
 		)
 		if err = c.ensureTLSSecretIsRemoved(ctx); err != nil {
-			return false, fmt.Errorf("PEM data represented an invalid cert, but got error while deleting it: %w", err)
+			return "", fmt.Errorf("PEM data represented an invalid cert, but got error while deleting it: %w", err)
 		}
-		return true, nil
+		return tlsIssuedReasonInvalid, nil
 	}
 
 	keyPEM := secret.Data[v1.TLSPrivateKeyKey]
@@ -690,9 +1245,9 @@ func (c *impersonatorConfigController) deleteTLSSecretWhenCertificateDoesNotMatc
 			"secret", klog.KObj(secret),
 		)
 		if err = c.ensureTLSSecretIsRemoved(ctx); err != nil {
-			return false, fmt.Errorf("cert had an invalid private key, but got error while deleting it: %w", err)
+			return "", fmt.Errorf("cert had an invalid private key, but got error while deleting it: %w", err)
 		}
-		return true, nil
+		return tlsIssuedReasonInvalid, nil
 	}
 
 	opts := x509.VerifyOptions{Roots: ca.Pool()}
@@ -700,57 +1255,83 @@ func (c *impersonatorConfigController) deleteTLSSecretWhenCertificateDoesNotMatc
 		// The TLS cert was not signed by the current CA. Since they are mismatched, delete the TLS cert
 		// so we can recreate it using the current CA.
 		if err = c.ensureTLSSecretIsRemoved(ctx); err != nil {
-			return false, err
+			return "", err
 		}
-		return true, nil
+		return tlsIssuedReasonCAChanged, nil
+	}
+
+	if c.tlsCertificateNeedsRenewal(actualCertFromSecret) {
+		c.infoLog.Info("TLS certificate is approaching its expiration and will be reissued",
+			"notBefore", actualCertFromSecret.NotBefore,
+			"notAfter", actualCertFromSecret.NotAfter,
+			"secret", klog.KObj(secret),
+		)
+		if err = c.ensureTLSSecretIsRemoved(ctx); err != nil {
+			return "", err
+		}
+		return tlsIssuedReasonExpiring, nil
 	}
 
 	if !nameInfo.ready {
 		// We currently have a secret but we are waiting for a load balancer to be assigned an ingress, so
 		// our current secret must be old/unwanted.
 		if err = c.ensureTLSSecretIsRemoved(ctx); err != nil {
-			return false, err
+			return "", err
 		}
-		return true, nil
+		return tlsIssuedReasonNamesChanged, nil
 	}
 
 	actualIPs := actualCertFromSecret.IPAddresses
 	actualHostnames := actualCertFromSecret.DNSNames
+	actualCommonName := actualCertFromSecret.Subject.CommonName
 	c.infoLog.Info("checking TLS certificate names",
 		"desiredIPs", nameInfo.selectedIPs,
-		"desiredHostname", nameInfo.selectedHostname,
+		"desiredHostnames", nameInfo.selectedHostnames,
+		"desiredCommonName", nameInfo.commonName,
 		"actualIPs", actualIPs,
 		"actualHostnames", actualHostnames,
+		"actualCommonName", actualCommonName,
 		"secret", klog.KObj(secret),
 	)
 
-	if certHostnameAndIPMatchDesiredState(nameInfo.selectedIPs, actualIPs, nameInfo.selectedHostname, actualHostnames) {
+	if certHostnamesAndIPsMatchDesiredState(nameInfo.selectedIPs, actualIPs, nameInfo.selectedHostnames, actualHostnames) &&
+		nameInfo.commonName == actualCommonName {
 		// The cert already matches the desired state, so there is no need to delete/recreate it.
-		return false, nil
+		return "", nil
 	}
 
 	if err = c.ensureTLSSecretIsRemoved(ctx); err != nil {
-		return false, err
+		return "", err
 	}
-	return true, nil
+	return tlsIssuedReasonNamesChanged, nil
 }
 
-func certHostnameAndIPMatchDesiredState(desiredIPs []net.IP, actualIPs []net.IP, desiredHostname string, actualHostnames []string) bool {
-	if len(desiredIPs) > 0 && len(actualIPs) > 0 && len(actualIPs) == len(desiredIPs) && len(actualHostnames) == 0 {
-		for i := range desiredIPs {
-			if !actualIPs[i].Equal(desiredIPs[i]) {
-				return false
-			}
+// tlsCertificateNeedsRenewal returns true when the remaining lifetime of the TLS serving certificate has fallen
+// below a fixed fraction of its total lifetime.
+func (c *impersonatorConfigController) tlsCertificateNeedsRenewal(cert *x509.Certificate) bool {
+	totalLifetime := cert.NotAfter.Sub(cert.NotBefore)
+	remainingLifetime := cert.NotAfter.Sub(c.clock.Now())
+	return remainingLifetime < totalLifetime/tlsRenewalFraction
+}
+
+func certHostnamesAndIPsMatchDesiredState(desiredIPs []net.IP, actualIPs []net.IP, desiredHostnames []string, actualHostnames []string) bool {
+	if len(desiredIPs) != len(actualIPs) || len(desiredHostnames) != len(actualHostnames) {
+		return false
+	}
+	for i := range desiredIPs {
+		if !actualIPs[i].Equal(desiredIPs[i]) {
+			return false
 		}
-		return true
 	}
-	if desiredHostname != "" && len(actualHostnames) == 1 && desiredHostname == actualHostnames[0] && len(actualIPs) == 0 {
-		return true
+	for i := range desiredHostnames {
+		if actualHostnames[i] != desiredHostnames[i] {
+			return false
+		}
 	}
-	return false
+	return true
 }
 
-func (c *impersonatorConfigController) ensureTLSSecretIsCreatedAndLoaded(ctx context.Context, nameInfo *certNameInfo, secret *v1.Secret, ca *certauthority.CA) error {
+func (c *impersonatorConfigController) ensureTLSSecretIsCreatedAndLoaded(ctx context.Context, config *v1alpha1.ImpersonationProxySpec, nameInfo *certNameInfo, secret *v1.Secret, ca *certauthority.CA, issuanceReason string) error {
 	if secret != nil {
 		err := c.loadTLSCertFromSecret(secret)
 		if err != nil {
@@ -763,10 +1344,14 @@ func (c *impersonatorConfigController) ensureTLSSecretIsCreatedAndLoaded(ctx con
 		return nil
 	}
 
-	newTLSSecret, err := c.createNewTLSSecret(ctx, ca, nameInfo.selectedIPs, nameInfo.selectedHostname)
+	newTLSSecret, err := c.createNewTLSSecret(ctx, config, ca, nameInfo.commonName, nameInfo.selectedIPs, nameInfo.selectedHostnames, issuanceReason)
 	if err != nil {
 		return err
 	}
+	c.syncDecision.tlsSecret = secretCreated
+	if issuanceReason != tlsIssuedReasonMissing {
+		c.syncDecision.tlsSecret = secretReissued
+	}
 
 	err = c.loadTLSCertFromSecret(newTLSSecret)
 	if err != nil {
@@ -776,50 +1361,263 @@ func (c *impersonatorConfigController) ensureTLSSecretIsCreatedAndLoaded(ctx con
 	return nil
 }
 
-func (c *impersonatorConfigController) ensureCASecretIsCreated(ctx context.Context) (*certauthority.CA, error) {
+// ensureCASecretIsCreated returns the CA which issues the TLS serving certificate, after creating or renewing it when
+// needed. It also returns the CA certificate from before the latest CA renewal, while it should still be advertised.
+func (c *impersonatorConfigController) ensureCASecretIsCreated(ctx context.Context, config *v1alpha1.ImpersonationProxySpec, rotationRequest string) (*certauthority.CA, []byte, error) {
+	rotationRequestedAt, err := parseCARotationRequest(rotationRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	caSecret, err := c.secretsInformer.Lister().Secrets(c.namespace).Get(c.caSecretName)
 	if err != nil && !k8serrors.IsNotFound(err) {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var impersonationCA *certauthority.CA
+	var previousCACertPEM []byte
 	if k8serrors.IsNotFound(err) {
-		impersonationCA, err = c.createCASecret(ctx)
+		// A new CA also satisfies any pending rotation request.
+		impersonationCA, err = c.createCASecret(ctx, config, rotationRequest)
+		c.syncDecision.caSecret = secretCreated
 	} else {
 		crtBytes := caSecret.Data[caCrtKey]
 		keyBytes := caSecret.Data[caKeyKey]
+		externallyProvided := caSecret.Annotations[caExternallyProvidedAnnotationKey] == "true"
+		generated := !externallyProvided && caWasGenerated(caSecret)
 		impersonationCA, err = certauthority.Load(string(crtBytes), string(keyBytes))
+		loaded := err == nil
+		if loaded {
+			err = c.validateCACertificateCanSign(crtBytes, externallyProvided)
+		}
+		switch {
+		case err != nil && (!loaded || !generated):
+			// An unreadable, externally provided, or hand-edited CA which cannot be used is never replaced.
+		case externallyProvided:
+		case err != nil:
+			// A generated CA which can no longer be used, e.g. because it expired while the Concierge was not
+			// running, is replaced. The old CA is not advertised anymore, since clients could not use it either.
+			impersonationCA, err = c.renewCASecret(ctx, caSecret, config, "", nil)
+			c.syncDecision.caSecret = secretRenewed
+		case caRotationWasRequested(caSecret, rotationRequest, rotationRequestedAt):
+			// Replace the CA with a new one. The TLS serving cert which was issued by the old CA
+			// will be deleted and reissued by ensureTLSSecret because it no longer verifies against the CA.
+			// Any previous CA is not advertised to clients anymore, since it should no longer be trusted.
+			impersonationCA, err = c.renewCASecret(ctx, caSecret, config, rotationRequest, nil)
+			c.syncDecision.caSecret = secretRotated
+		case c.caCertificateNeedsRenewal(crtBytes, config):
+			// Keep advertising the old CA to clients until the new TLS serving cert is in use.
+			previousCACertPEM = crtBytes
+			impersonationCA, err = c.renewCASecret(ctx, caSecret, config, "", previousCACertPEM)
+			c.syncDecision.caSecret = secretRenewed
+		default:
+			previousCACertPEM = caSecret.Data[caPreviousCrtKey]
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return impersonationCA, previousCACertPEM, nil
+}
+
+// parseCARotationRequest parses the value of the CA rotation request annotation, which may be empty.
+func parseCARotationRequest(rotationRequest string) (time.Time, error) {
+	if rotationRequest == "" {
+		return time.Time{}, nil
+	}
+	requestedAt, err := time.Parse(time.RFC3339, rotationRequest)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s annotation %q (expected an RFC3339 timestamp)", caRotationRequestAnnotationKey, rotationRequest)
+	}
+	return requestedAt, nil
+}
+
+// caRotationWasRequested returns true when a CA rotation was requested after the CA Secret was created, and that
+// request has not already been processed.
+func caRotationWasRequested(caSecret *v1.Secret, rotationRequest string, rotationRequestedAt time.Time) bool {
+	return rotationRequest != "" &&
+		caSecret.Annotations[caLastRotationAnnotationKey] != rotationRequest &&
+		rotationRequestedAt.After(caSecret.CreationTimestamp.Time)
+}
+
+// validateCACertificateCanSign checks that the CA certificate is a CA certificate whose key usage allows it to sign the
+// TLS serving certificate, so that a CA Secret which was edited by hand is not blindly trusted. The CA must not have
+// expired. An externally provided CA must also be currently valid, since it will never be renewed by this controller.
+func (c *impersonatorConfigController) validateCACertificateCanSign(certPEM []byte, externallyProvided bool) error {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return constable.Error("could not load CA: failed to decode certificate PEM")
+	}
+	caCert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("could not load CA: %w", err)
+	}
+
+	if !caCert.BasicConstraintsValid || !caCert.IsCA {
+		return constable.Error("could not load CA: certificate is not a CA certificate")
+	}
+	if caCert.KeyUsage&x509.KeyUsageCertSign == 0 {
+		return constable.Error("could not load CA: certificate key usage does not allow signing certificates")
+	}
+
+	now := c.clock.Now()
+	if externallyProvided {
+		if now.Before(caCert.NotBefore) || now.After(caCert.NotAfter) {
+			return fmt.Errorf("could not load CA: externally provided certificate is only valid from %s to %s",
+				caCert.NotBefore.UTC().Format(time.RFC3339), caCert.NotAfter.UTC().Format(time.RFC3339))
+		}
+	} else if now.After(caCert.NotAfter) {
+		return fmt.Errorf("could not load CA: certificate expired at %s", caCert.NotAfter.UTC().Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// caWasGenerated returns true when the CA certificate in the CA Secret is still the one which the controller generated.
+// CA Secrets which were created before the fingerprint was recorded are also treated as generated.
+func caWasGenerated(caSecret *v1.Secret) bool {
+	fingerprint, ok := caSecret.Annotations[caGeneratedFingerprintAnnotationKey]
+	return !ok || fingerprint == caFingerprint(caSecret.Data[caCrtKey])
+}
+
+func caFingerprint(certPEM []byte) string {
+	sum := sha256.Sum256(certPEM)
+	return hex.EncodeToString(sum[:])
+}
+
+// trimPreviousCACertWhenTLSSecretWasReissued stops advertising the CA certificate from before the latest CA
+// renewal once the informer cache shows a TLS Secret which was issued by the current CA, by removing it from
+// the CA Secret. It returns the previous CA certificate which should still be advertised, if any.
+func (c *impersonatorConfigController) trimPreviousCACertWhenTLSSecretWasReissued(ctx context.Context, ca *certauthority.CA, previousCACertPEM []byte) ([]byte, error) {
+	if previousCACertPEM == nil {
+		return nil, nil
+	}
+
+	tlsSecret, err := c.secretsInformer.Lister().Secrets(c.namespace).Get(c.tlsSecretName)
+	if err != nil {
+		return previousCACertPEM, nil
+	}
+	block, _ := pem.Decode(tlsSecret.Data[v1.TLSCertKey])
+	if block == nil {
+		return previousCACertPEM, nil
+	}
+	tlsCert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return previousCACertPEM, nil
+	}
+	if _, err = tlsCert.Verify(x509.VerifyOptions{Roots: ca.Pool()}); err != nil {
+		return previousCACertPEM, nil
 	}
+
+	// Update the Secret from the informer cache, so the update will fail with a conflict if the cache is stale.
+	// The CA Secret was updated before the TLS Secret from the new CA was created, so the cache already has it.
+	caSecret, err := c.secretsInformer.Lister().Secrets(c.namespace).Get(c.caSecretName)
 	if err != nil {
 		return nil, err
 	}
+	if _, ok := caSecret.Data[caPreviousCrtKey]; ok {
+		updatedSecret := caSecret.DeepCopy()
+		delete(updatedSecret.Data, caPreviousCrtKey)
+		if _, err = c.k8sClient.CoreV1().Secrets(c.namespace).Update(ctx, updatedSecret, metav1.UpdateOptions{}); err != nil {
+			return nil, err
+		}
+	}
 
-	return impersonationCA, nil
+	c.infoLog.Info("no longer advertising previous CA certificate for impersonation proxy",
+		"secret", klog.KObj(tlsSecret),
+	)
+	return nil, nil
+}
+
+// caBundleForClients returns the current CA bundle, followed by the CA certificate from before the
+// latest CA renewal while that renewal is still rolling out.
+func caBundleForClients(ca *certauthority.CA, previousCACertPEM []byte) []byte {
+	bundle := ca.Bundle()
+	if previousCACertPEM == nil {
+		return bundle
+	}
+	return append(append([]byte{}, bundle...), previousCACertPEM...)
 }
 
-func (c *impersonatorConfigController) createCASecret(ctx context.Context) (*certauthority.CA, error) {
-	impersonationCA, err := certauthority.New(caCommonName, approximatelyOneHundredYears)
+// caCertificateNeedsRenewal returns true when the remaining lifetime of the CA certificate has fallen
+// below the configured percentage of its total lifetime.
+func (c *impersonatorConfigController) caCertificateNeedsRenewal(certPEM []byte, config *v1alpha1.ImpersonationProxySpec) bool {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return false
+	}
+	caCert, err := x509.ParseCertificate(block.Bytes)
 	if err != nil {
-		return nil, fmt.Errorf("could not create impersonation CA: %w", err)
+		return false
+	}
+
+	renewalPercent := int64(defaultCARenewalPercent)
+	if config.CACertificateRenewalThresholdPercent != nil {
+		renewalPercent = int64(*config.CACertificateRenewalThresholdPercent)
+	}
+
+	totalLifetime := caCert.NotAfter.Sub(caCert.NotBefore)
+	remainingLifetime := caCert.NotAfter.Sub(c.clock.Now())
+	return remainingLifetime < time.Duration(int64(totalLifetime)/100*renewalPercent)
+}
+
+func caCertificateLifetime(config *v1alpha1.ImpersonationProxySpec) time.Duration {
+	if config.CACertificateLifetime != nil {
+		return config.CACertificateLifetime.Duration
+	}
+	return approximatelyOneHundredYears
+}
+
+func caCertificateSubject(config *v1alpha1.ImpersonationProxySpec) pkix.Name {
+	if config.CACertificateSubject == nil {
+		return pkix.Name{CommonName: caCommonName}
+	}
+	subject := pkix.Name{CommonName: config.CACertificateSubject.CommonName}
+	if config.CACertificateSubject.Organization != "" {
+		subject.Organization = []string{config.CACertificateSubject.Organization}
+	}
+	return subject
+}
+
+func newCASecretData(config *v1alpha1.ImpersonationProxySpec) (*certauthority.CA, map[string][]byte, error) {
+	impersonationCA, err := certauthority.NewWithSubject(caCertificateSubject(config), caCertificateLifetime(config))
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create impersonation CA: %w", err)
 	}
 
 	caPrivateKeyPEM, err := impersonationCA.PrivateKeyToPEM()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return impersonationCA, map[string][]byte{
+		caCrtKey: impersonationCA.Bundle(),
+		caKeyKey: caPrivateKeyPEM,
+	}, nil
+}
+
+func (c *impersonatorConfigController) createCASecret(ctx context.Context, config *v1alpha1.ImpersonationProxySpec, rotationRequest string) (*certauthority.CA, error) {
+	impersonationCA, caSecretData, err := newCASecretData(config)
 	if err != nil {
 		return nil, err
 	}
 
 	secret := v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      c.caSecretName,
-			Namespace: c.namespace,
-			Labels:    c.labels,
-		},
-		Data: map[string][]byte{
-			caCrtKey: impersonationCA.Bundle(),
-			caKeyKey: caPrivateKeyPEM,
+			Name:            c.caSecretName,
+			Namespace:       c.namespace,
+			Labels:          c.labels,
+			OwnerReferences: c.ownerReferences,
 		},
+		Data: caSecretData,
 		Type: v1.SecretTypeOpaque,
 	}
+	secret.Annotations = map[string]string{caGeneratedFingerprintAnnotationKey: caFingerprint(caSecretData[caCrtKey])}
+	if rotationRequest != "" {
+		secret.Annotations[caLastRotationAnnotationKey] = rotationRequest
+	}
+	c.setIssuanceAnnotations(&secret, caIssuedReasonCreated)
 
 	c.infoLog.Info("creating CA certificates for impersonation proxy",
 		"secret", klog.KObj(&secret),
@@ -828,16 +1626,105 @@ func (c *impersonatorConfigController) createCASecret(ctx context.Context) (*cer
 		return nil, err
 	}
 
+	c.metrics.caIssued.WithLabelValues(caIssuedReasonCreated).Inc()
+	return impersonationCA, nil
+}
+
+// renewCASecret replaces the CA in the existing CA Secret. When the rotationRequest is not empty, the replacement was
+// requested by an operator and that request is recorded on the Secret. When previousCACertPEM is not nil, it is kept
+// in the Secret, so that it is still advertised to clients until the TLS serving cert from the new CA is in use.
+func (c *impersonatorConfigController) renewCASecret(ctx context.Context, caSecret *v1.Secret, config *v1alpha1.ImpersonationProxySpec, rotationRequest string, previousCACertPEM []byte) (*certauthority.CA, error) {
+	impersonationCA, caSecretData, err := newCASecretData(config)
+	if err != nil {
+		return nil, err
+	}
+	if previousCACertPEM != nil {
+		caSecretData[caPreviousCrtKey] = previousCACertPEM
+	}
+
+	// Update the Secret from the informer cache, so the update will fail with a conflict
+	// if the cache is stale, e.g. because another instance of the Concierge renewed it first.
+	updatedSecret := caSecret.DeepCopy()
+	updatedSecret.Data = caSecretData
+	if updatedSecret.Annotations == nil {
+		updatedSecret.Annotations = map[string]string{}
+	}
+	updatedSecret.Annotations[caGeneratedFingerprintAnnotationKey] = caFingerprint(caSecretData[caCrtKey])
+	reason := caIssuedReasonRenewed
+	if rotationRequest != "" {
+		updatedSecret.Annotations[caLastRotationAnnotationKey] = rotationRequest
+		reason = caIssuedReasonRotated
+	}
+	c.setIssuanceAnnotations(updatedSecret, reason)
+
+	c.infoLog.Info("renewing CA certificates for impersonation proxy",
+		"reason", reason,
+		"secret", klog.KObj(updatedSecret),
+	)
+	if _, err = c.k8sClient.CoreV1().Secrets(c.namespace).Update(ctx, updatedSecret, metav1.UpdateOptions{}); err != nil {
+		return nil, err
+	}
+
+	c.metrics.caIssued.WithLabelValues(reason).Inc()
 	return impersonationCA, nil
 }
 
 func (c *impersonatorConfigController) findDesiredTLSCertificateName(config *v1alpha1.ImpersonationProxySpec) (*certNameInfo, error) {
+	var nameInfo *certNameInfo
+	var err error
 	if config.ExternalEndpoint != "" {
-		return c.findTLSCertificateNameFromEndpointConfig(config), nil
+		nameInfo = c.findTLSCertificateNameFromEndpointConfig(config)
+	} else if config.Service.Type == v1alpha1.ImpersonationProxyServiceTypeExisting {
+		nameInfo, err = c.findTLSCertificateNameFromExistingService(config.Service.ExistingServiceName, config.Service.PreferredAddressType)
 	} else if config.Service.Type == v1alpha1.ImpersonationProxyServiceTypeClusterIP {
-		return c.findTLSCertificateNameFromClusterIPService()
+		nameInfo, err = c.findTLSCertificateNameFromClusterIPService(c.generatedClusterIPServiceName, config.Service.ClusterIP, config.Service.PreferredAddressType)
+	} else {
+		nameInfo, err = c.findTLSCertificateNameFromLoadBalancer(c.generatedLoadBalancerServiceName, config.Service.PreferredAddressType)
+	}
+	if err != nil || !nameInfo.ready {
+		return nameInfo, err
+	}
+	if config.ExternalEndpoint == "" {
+		port := desiredServicePort(config)
+		switch ip := net.ParseIP(nameInfo.clientEndpoint); {
+		case config.Service.Type != v1alpha1.ImpersonationProxyServiceTypeExisting && port != defaultHTTPSPort:
+			// Clients must connect to the port of the provisioned Service, which is only implied when it is the default.
+			nameInfo.clientEndpoint = net.JoinHostPort(nameInfo.clientEndpoint, strconv.Itoa(int(port)))
+		case ip != nil && ip.To4() == nil:
+			// IPv6 addresses must be bracketed in URLs, even without a port.
+			nameInfo.clientEndpoint = "[" + nameInfo.clientEndpoint + "]"
+		}
+	}
+	addAdditionalCertNames(nameInfo, config)
+	return nameInfo, nil
+}
+
+// addAdditionalCertNames merges the user-requested wildcard hostname and extra hostnames and IPs into the names
+// of the cert, after the names which were discovered from the endpoint, while skipping any duplicates.
+func addAdditionalCertNames(nameInfo *certNameInfo, config *v1alpha1.ImpersonationProxySpec) {
+	additionalHostnames := config.AdditionalHostnames
+	if config.WildcardHostname && len(nameInfo.selectedHostnames) > 0 {
+		// Validation ensures that the external endpoint is a hostname, so it is the first selected hostname.
+		additionalHostnames = append([]string{"*." + nameInfo.selectedHostnames[0]}, additionalHostnames...)
+	}
+	for _, hostname := range additionalHostnames {
+		if !sets.NewString(nameInfo.selectedHostnames...).Has(hostname) {
+			nameInfo.selectedHostnames = append(nameInfo.selectedHostnames, hostname)
+		}
+	}
+	for _, ip := range config.AdditionalIPs {
+		parsedIP := net.ParseIP(ip)
+		alreadySelected := false
+		for _, selectedIP := range nameInfo.selectedIPs {
+			if selectedIP.Equal(parsedIP) {
+				alreadySelected = true
+				break
+			}
+		}
+		if !alreadySelected {
+			nameInfo.selectedIPs = append(nameInfo.selectedIPs, parsedIP)
+		}
 	}
-	return c.findTLSCertificateNameFromLoadBalancer()
 }
 
 func (c *impersonatorConfigController) findTLSCertificateNameFromEndpointConfig(config *v1alpha1.ImpersonationProxySpec) *certNameInfo {
@@ -847,15 +1734,15 @@ func (c *impersonatorConfigController) findTLSCertificateNameFromEndpointConfig(
 	if ip := net.ParseIP(addr.Host); ip != nil {
 		return &certNameInfo{ready: true, selectedIPs: []net.IP{ip}, clientEndpoint: endpoint}
 	}
-	return &certNameInfo{ready: true, selectedHostname: addr.Host, clientEndpoint: endpoint}
+	return &certNameInfo{ready: true, selectedHostnames: []string{addr.Host}, clientEndpoint: endpoint}
 }
 
-func (c *impersonatorConfigController) findTLSCertificateNameFromLoadBalancer() (*certNameInfo, error) {
-	lb, err := c.servicesInformer.Lister().Services(c.namespace).Get(c.generatedLoadBalancerServiceName)
+func (c *impersonatorConfigController) findTLSCertificateNameFromLoadBalancer(serviceName string, preferredAddressType v1alpha1.ImpersonationProxyServiceAddressType) (*certNameInfo, error) {
+	lb, err := c.servicesInformer.Lister().Services(c.namespace).Get(serviceName)
 	notFound := k8serrors.IsNotFound(err)
 	if notFound {
 		// We aren't ready and will try again later in this case.
-		return &certNameInfo{ready: false}, nil
+		return &certNameInfo{ready: false, pendingMessage: pendingMessageWaitingForLoadBalancer}, nil
 	}
 	if err != nil {
 		return nil, err
@@ -865,59 +1752,101 @@ func (c *impersonatorConfigController) findTLSCertificateNameFromLoadBalancer()
 		c.infoLog.Info("load balancer for impersonation proxy does not have an ingress yet, so skipping tls cert generation while we wait",
 			"service", klog.KObj(lb),
 		)
-		return &certNameInfo{ready: false}, nil
+		return &certNameInfo{ready: false, pendingMessage: pendingMessageWaitingForLoadBalancer}, nil
+	}
+
+	endpoint, err := EndpointFromService(lb, preferredAddressType)
+	if err != nil {
+		return nil, err
 	}
+
+	// Put every hostname and valid IP of the load balancer into the cert, so clients may connect using any of them.
+	nameInfo := &certNameInfo{ready: true, clientEndpoint: endpoint}
 	for _, ingress := range ingresses {
 		hostname := ingress.Hostname
 		if hostname != "" {
-			return &certNameInfo{ready: true, selectedHostname: hostname, clientEndpoint: hostname}, nil
+			nameInfo.selectedHostnames = append(nameInfo.selectedHostnames, hostname)
 		}
 	}
 	for _, ingress := range ingresses {
-		ip := ingress.IP
-		parsedIP := net.ParseIP(ip)
-		if parsedIP != nil {
-			return &certNameInfo{ready: true, selectedIPs: []net.IP{parsedIP}, clientEndpoint: ip}, nil
+		if parsedIP := net.ParseIP(ingress.IP); parsedIP != nil {
+			nameInfo.selectedIPs = append(nameInfo.selectedIPs, parsedIP)
 		}
 	}
-
-	return nil, fmt.Errorf("could not find valid IP addresses or hostnames from load balancer %s/%s", c.namespace, lb.Name)
+	if preferredAddressType != "" {
+		nameInfo.commonName = nameInfo.clientEndpoint
+	}
+	return nameInfo, nil
 }
 
-func (c *impersonatorConfigController) findTLSCertificateNameFromClusterIPService() (*certNameInfo, error) {
-	clusterIP, err := c.servicesInformer.Lister().Services(c.namespace).Get(c.generatedClusterIPServiceName)
+func (c *impersonatorConfigController) findTLSCertificateNameFromClusterIPService(serviceName string, requestedClusterIP string, preferredAddressType v1alpha1.ImpersonationProxyServiceAddressType) (*certNameInfo, error) {
+	clusterIP, err := c.servicesInformer.Lister().Services(c.namespace).Get(serviceName)
 	notFound := k8serrors.IsNotFound(err)
 	if notFound {
 		// We aren't ready and will try again later in this case.
-		return &certNameInfo{ready: false}, nil
+		return &certNameInfo{ready: false, pendingMessage: pendingMessageWaitingForClusterIP}, nil
 	}
 	if err != nil {
 		return nil, err
 	}
 	ip := clusterIP.Spec.ClusterIP
 	ips := clusterIP.Spec.ClusterIPs
-	if ip != "" {
-		// clusterIP will always exist when clusterIPs does, but not vice versa
-		var parsedIPs []net.IP
-		if len(ips) > 0 {
-			for _, ipFromIPs := range ips {
-				parsedIPs = append(parsedIPs, net.ParseIP(ipFromIPs))
-			}
-		} else {
-			parsedIPs = []net.IP{net.ParseIP(ip)}
+	if ip == "" || (requestedClusterIP != "" && ip != requestedClusterIP) {
+		// The Service has not been assigned its cluster IP yet, or the informer has not seen it recreated yet.
+		return &certNameInfo{ready: false, pendingMessage: pendingMessageWaitingForClusterIP}, nil
+	}
+	endpoint, err := EndpointFromService(clusterIP, preferredAddressType)
+	if err != nil {
+		return nil, err
+	}
+	// clusterIP will always exist when clusterIPs does, but not vice versa
+	var parsedIPs []net.IP
+	if len(ips) > 0 {
+		for _, ipFromIPs := range ips {
+			parsedIPs = append(parsedIPs, net.ParseIP(ipFromIPs))
 		}
-		return &certNameInfo{ready: true, selectedIPs: parsedIPs, clientEndpoint: ip}, nil
+	} else {
+		parsedIPs = []net.IP{net.ParseIP(ip)}
+	}
+	return &certNameInfo{ready: true, selectedIPs: parsedIPs, clientEndpoint: endpoint}, nil
+}
+
+// findTLSCertificateNameFromExistingService reads the address of a Service which is not managed by the controller.
+// It is not ready until that Service exists and has been assigned a usable address.
+func (c *impersonatorConfigController) findTLSCertificateNameFromExistingService(serviceName string, preferredAddressType v1alpha1.ImpersonationProxyServiceAddressType) (*certNameInfo, error) {
+	service, err := c.servicesInformer.Lister().Services(c.namespace).Get(serviceName)
+	notFound := k8serrors.IsNotFound(err)
+	if notFound {
+		c.infoLog.Info("existing service for impersonation proxy was not found, so skipping tls cert generation while we wait",
+			"service", klog.KRef(c.namespace, serviceName),
+		)
+		return &certNameInfo{ready: false}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case service.Spec.Type == v1.ServiceTypeLoadBalancer:
+		return c.findTLSCertificateNameFromLoadBalancer(serviceName, preferredAddressType)
+	case service.Spec.ClusterIP == v1.ClusterIPNone:
+		// Headless Services do not have an address of their own.
+		return &certNameInfo{ready: false}, nil
+	default:
+		return c.findTLSCertificateNameFromClusterIPService(serviceName, "", preferredAddressType)
 	}
-	return &certNameInfo{ready: false}, nil
 }
 
-func (c *impersonatorConfigController) createNewTLSSecret(ctx context.Context, ca *certauthority.CA, ips []net.IP, hostname string) (*v1.Secret, error) {
-	var hostnames []string
-	if hostname != "" {
-		hostnames = []string{hostname}
+func (c *impersonatorConfigController) createNewTLSSecret(ctx context.Context, config *v1alpha1.ImpersonationProxySpec, ca *certauthority.CA, commonName string, ips []net.IP, hostnames []string, reason string) (*v1.Secret, error) {
+	if err := c.certIssuanceRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("could not wait to create impersonation cert: %w", err)
+	}
+
+	ttl, err := tlsCertificateLifetime(config, ca)
+	if err != nil {
+		return nil, fmt.Errorf("could not create impersonation cert: %w", err)
 	}
 
-	impersonationCert, err := ca.IssueServerCert(hostnames, ips, approximatelyOneHundredYears)
+	impersonationCert, err := ca.IssueServerCertWithCommonName(commonName, hostnames, ips, ttl)
 	if err != nil {
 		return nil, fmt.Errorf("could not create impersonation cert: %w", err)
 	}
@@ -929,9 +1858,10 @@ func (c *impersonatorConfigController) createNewTLSSecret(ctx context.Context, c
 
 	newTLSSecret := &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      c.tlsSecretName,
-			Namespace: c.namespace,
-			Labels:    c.labels,
+			Name:            c.tlsSecretName,
+			Namespace:       c.namespace,
+			Labels:          c.labels,
+			OwnerReferences: c.ownerReferences,
 		},
 		Data: map[string][]byte{
 			v1.TLSPrivateKeyKey: keyPEM,
@@ -939,13 +1869,60 @@ func (c *impersonatorConfigController) createNewTLSSecret(ctx context.Context, c
 		},
 		Type: v1.SecretTypeTLS,
 	}
+	c.setIssuanceAnnotations(newTLSSecret, reason)
 
 	c.infoLog.Info("creating TLS certificates for impersonation proxy",
 		"ips", ips,
 		"hostnames", hostnames,
+		"reason", reason,
 		"secret", klog.KObj(newTLSSecret),
 	)
-	return c.k8sClient.CoreV1().Secrets(c.namespace).Create(ctx, newTLSSecret, metav1.CreateOptions{})
+	createdSecret, err := c.k8sClient.CoreV1().Secrets(c.namespace).Create(ctx, newTLSSecret, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	c.metrics.tlsIssued.WithLabelValues(reason).Inc()
+	return createdSecret, nil
+}
+
+// tlsCertificateLifetime returns the lifetime of a new TLS serving certificate, which must never outlive the CA which
+// issues it, e.g. when the CA was configured with a short lifetime. The CA sets the lifetime of the certificates which
+// it issues relative to the current time, so the remaining lifetime of the CA is not measured with the controller's clock.
+func tlsCertificateLifetime(config *v1alpha1.ImpersonationProxySpec, ca *certauthority.CA) (time.Duration, error) {
+	block, _ := pem.Decode(ca.Bundle())
+	if block == nil {
+		return 0, constable.Error("failed to decode CA certificate PEM")
+	}
+	caCert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return 0, err
+	}
+
+	lifetime := approximatelyOneHundredYears
+	if config.TLSCertificateLifetime != nil {
+		lifetime = config.TLSCertificateLifetime.Duration
+	}
+	if remainingCALifetime := time.Until(caCert.NotAfter); remainingCALifetime < lifetime {
+		lifetime = remainingCALifetime
+	}
+	return lifetime, nil
+}
+
+// setIssuanceAnnotations records on a generated Secret which is about to be written when and why its certificate was
+// issued, or removes that record when it is disabled. The annotations are informational only, so they are never
+// compared to decide whether a certificate must be reissued.
+func (c *impersonatorConfigController) setIssuanceAnnotations(secret *v1.Secret, reason string) {
+	if !c.annotateIssuedSecrets {
+		delete(secret.Annotations, issuedAtAnnotationKey)
+		delete(secret.Annotations, issuanceReasonAnnotationKey)
+		return
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[issuedAtAnnotationKey] = c.clock.Now().UTC().Format(time.RFC3339)
+	secret.Annotations[issuanceReasonAnnotationKey] = reason
 }
 
 func (c *impersonatorConfigController) loadTLSCertFromSecret(tlsSecret *v1.Secret) error {
@@ -956,6 +1933,14 @@ func (c *impersonatorConfigController) loadTLSCertFromSecret(tlsSecret *v1.Secre
 		return fmt.Errorf("could not parse TLS cert PEM data from Secret: %w", err)
 	}
 
+	// SetCertKeyContent already validated the PEM data, so this cannot fail in practice.
+	if block, _ := pem.Decode(certPEM); block != nil {
+		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+			c.metrics.tlsCertificateExpiration.Set(float64(cert.NotAfter.Unix()))
+			c.tlsServingCertNotAfter = cert.NotAfter
+		}
+	}
+
 	c.infoLog.Info("loading TLS certificates for impersonation proxy",
 		"certPEM", string(certPEM),
 		"secret", klog.KObj(tlsSecret),
@@ -981,14 +1966,113 @@ func (c *impersonatorConfigController) ensureTLSSecretIsRemoved(ctx context.Cont
 			ResourceVersion: &secret.ResourceVersion,
 		},
 	})
+	c.syncDecision.tlsSecret = secretDeleted
 	// it is okay if we tried to delete and we got a not found error. This probably means
 	// another instance of the concierge got here first so there's nothing to delete.
 	return utilerrors.FilterOut(err, k8serrors.IsNotFound)
 }
 
+// ensureRenamedSecretsAreRemoved deletes the generated Secrets which were left behind under a name which is no longer
+// used, because the name was overridden by the CredentialIssuer or the override was changed or removed.
+func (c *impersonatorConfigController) ensureRenamedSecretsAreRemoved(ctx context.Context, config *v1alpha1.ImpersonationProxySpec) error {
+	staleNames := c.generatedSecretNames.Difference(sets.NewString(
+		c.tlsSecretName, c.caSecretName, c.impersonationSignerSecretName, config.TLSCertificateSecretName,
+	))
+	for _, name := range staleNames.List() {
+		secret, err := c.secretsInformer.Lister().Secrets(c.namespace).Get(name)
+		if k8serrors.IsNotFound(err) {
+			c.generatedSecretNames.Delete(name)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		c.infoLog.Info("deleting previously named Secret for impersonation proxy", "secret", klog.KObj(secret))
+		err = c.k8sClient.CoreV1().Secrets(c.namespace).Delete(ctx, name, metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{
+				UID:             &secret.UID,
+				ResourceVersion: &secret.ResourceVersion,
+			},
+		})
+		if err = utilerrors.FilterOut(err, k8serrors.IsNotFound); err != nil {
+			return err
+		}
+		c.generatedSecretNames.Delete(name)
+	}
+	return nil
+}
+
+// ensureOperatorTLSSecretIsLoaded serves the TLS certificate from the Secret which was provided by the operator, once
+// the endpoint which it must be valid for is known.
+func (c *impersonatorConfigController) ensureOperatorTLSSecretIsLoaded(config *v1alpha1.ImpersonationProxySpec, nameInfo *certNameInfo, caBundle []byte) error {
+	if !nameInfo.ready {
+		c.clearTLSSecret()
+		return nil
+	}
+
+	secret, err := c.secretsInformer.Lister().Secrets(c.namespace).Get(config.TLSCertificateSecretName)
+	if err == nil {
+		err = c.validateOperatorTLSSecret(secret, nameInfo.clientEndpoint, caBundle)
+	}
+	if err != nil {
+		c.clearTLSSecret()
+		return fmt.Errorf("could not load tlsCertificateSecretName %q: %w", config.TLSCertificateSecretName, err)
+	}
+
+	return c.loadTLSCertFromSecret(secret)
+}
+
+// validateOperatorTLSSecret checks that the TLS certificate from the Secret which was provided by the operator matches
+// its private key, is currently valid, is valid for the host of the client endpoint, and verifies against the CA bundle
+// which is advertised to clients.
+func (c *impersonatorConfigController) validateOperatorTLSSecret(secret *v1.Secret, clientEndpoint string, caBundle []byte) error {
+	if secret.Type != v1.SecretTypeTLS {
+		return fmt.Errorf("secret has wrong type %q (should be %q)", secret.Type, v1.SecretTypeTLS)
+	}
+
+	keyPair, err := tls.X509KeyPair(secret.Data[v1.TLSCertKey], secret.Data[v1.TLSPrivateKeyKey])
+	if err != nil {
+		return fmt.Errorf("invalid certificate and private key: %w", err)
+	}
+	leaf, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("invalid certificate: %w", err)
+	}
+
+	now := c.clock.Now()
+	if now.Before(leaf.NotBefore) || now.After(leaf.NotAfter) {
+		return fmt.Errorf("certificate is only valid from %s to %s",
+			leaf.NotBefore.UTC().Format(time.RFC3339), leaf.NotAfter.UTC().Format(time.RFC3339))
+	}
+
+	host := clientEndpoint
+	if h, _, err := net.SplitHostPort(clientEndpoint); err == nil {
+		host = h
+	}
+	if err := leaf.VerifyHostname(host); err != nil {
+		return fmt.Errorf("certificate does not cover the endpoint: %w", err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(caBundle)
+	intermediates := x509.NewCertPool()
+	for _, der := range keyPair.Certificate[1:] {
+		if cert, err := x509.ParseCertificate(der); err == nil {
+			intermediates.AddCert(cert)
+		}
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates, CurrentTime: now}); err != nil {
+		return fmt.Errorf("certificate does not verify against tlsCertificateAuthorityData: %w", err)
+	}
+
+	return nil
+}
+
 func (c *impersonatorConfigController) clearTLSSecret() {
 	c.debugLog.Info("clearing TLS serving certificate for impersonation proxy")
 	c.tlsServingCertDynamicCertProvider.UnsetCertKeyContent()
+	c.metrics.tlsCertificateExpiration.Set(0)
+	c.tlsServingCertNotAfter = time.Time{}
 }
 
 func (c *impersonatorConfigController) loadSignerCA() error {
@@ -1017,7 +2101,7 @@ func (c *impersonatorConfigController) clearSignerCA() {
 	c.impersonationSigningCertProvider.UnsetCertKeyContent()
 }
 
-func (c *impersonatorConfigController) doSyncResult(nameInfo *certNameInfo, config *v1alpha1.ImpersonationProxySpec, ca *certauthority.CA) *v1alpha1.CredentialIssuerStrategy {
+func (c *impersonatorConfigController) doSyncResult(nameInfo *certNameInfo, config *v1alpha1.ImpersonationProxySpec, caBundle []byte) *v1alpha1.CredentialIssuerStrategy {
 	switch {
 	case c.disabledExplicitly(config):
 		return &v1alpha1.CredentialIssuerStrategy{
@@ -1035,15 +2119,28 @@ func (c *impersonatorConfigController) doSyncResult(nameInfo *certNameInfo, conf
 			Message:        "automatically determined that impersonation proxy should be disabled",
 			LastUpdateTime: metav1.NewTime(c.clock.Now()),
 		}
+	case !nameInfo.ready && config.Service.Type == v1alpha1.ImpersonationProxyServiceTypeExisting:
+		return &v1alpha1.CredentialIssuerStrategy{
+			Type:           v1alpha1.ImpersonationProxyStrategyType,
+			Status:         v1alpha1.ErrorStrategyStatus,
+			Reason:         v1alpha1.PendingStrategyReason,
+			Message:        fmt.Sprintf("waiting for existing Service %q to exist and to have an IP or hostname", config.Service.ExistingServiceName),
+			LastUpdateTime: metav1.NewTime(c.clock.Now()),
+		}
 	case !nameInfo.ready:
 		return &v1alpha1.CredentialIssuerStrategy{
 			Type:           v1alpha1.ImpersonationProxyStrategyType,
 			Status:         v1alpha1.ErrorStrategyStatus,
 			Reason:         v1alpha1.PendingStrategyReason,
-			Message:        "waiting for load balancer Service to be assigned IP or hostname",
+			Message:        nameInfo.pendingMessage,
 			LastUpdateTime: metav1.NewTime(c.clock.Now()),
 		}
 	default:
+		var certificateNotAfter *metav1.Time
+		if !c.tlsServingCertNotAfter.IsZero() {
+			notAfter := metav1.NewTime(c.tlsServingCertNotAfter)
+			certificateNotAfter = &notAfter
+		}
 		return &v1alpha1.CredentialIssuerStrategy{
 			Type:           v1alpha1.ImpersonationProxyStrategyType,
 			Status:         v1alpha1.SuccessStrategyStatus,
@@ -1054,52 +2151,10 @@ func (c *impersonatorConfigController) doSyncResult(nameInfo *certNameInfo, conf
 				Type: v1alpha1.ImpersonationProxyFrontendType,
 				ImpersonationProxyInfo: &v1alpha1.ImpersonationProxyInfo{
 					Endpoint:                 "https://" + nameInfo.clientEndpoint,
-					CertificateAuthorityData: base64.StdEncoding.EncodeToString(ca.Bundle()),
+					CertificateAuthorityData: base64.StdEncoding.EncodeToString(caBundle),
+					CertificateNotAfter:      certificateNotAfter,
 				},
 			},
 		}
 	}
 }
-
-func validateCredentialIssuerSpec(spec *v1alpha1.ImpersonationProxySpec) error {
-	// Validate that the mode is one of our known values.
-	switch spec.Mode {
-	case v1alpha1.ImpersonationProxyModeDisabled:
-	case v1alpha1.ImpersonationProxyModeAuto:
-	case v1alpha1.ImpersonationProxyModeEnabled:
-	default:
-		return fmt.Errorf("invalid proxy mode %q (expected auto, disabled, or enabled)", spec.Mode)
-	}
-
-	// If disabled, ignore all other fields and consider the configuration valid.
-	if spec.Mode == v1alpha1.ImpersonationProxyModeDisabled {
-		return nil
-	}
-
-	// Validate that the service type is one of our known values.
-	switch spec.Service.Type {
-	case v1alpha1.ImpersonationProxyServiceTypeNone:
-	case v1alpha1.ImpersonationProxyServiceTypeLoadBalancer:
-	case v1alpha1.ImpersonationProxyServiceTypeClusterIP:
-	default:
-		return fmt.Errorf("invalid service type %q (expected None, LoadBalancer, or ClusterIP)", spec.Service.Type)
-	}
-
-	// If specified, validate that the LoadBalancerIP is a valid IPv4 or IPv6 address.
-	if ip := spec.Service.LoadBalancerIP; ip != "" && len(validation.IsValidIP(ip)) > 0 {
-		return fmt.Errorf("invalid LoadBalancerIP %q", spec.Service.LoadBalancerIP)
-	}
-
-	// If service is type "None", a non-empty external endpoint must be specified.
-	if spec.ExternalEndpoint == "" && spec.Service.Type == v1alpha1.ImpersonationProxyServiceTypeNone {
-		return fmt.Errorf("externalEndpoint must be set when service.type is None")
-	}
-
-	if spec.ExternalEndpoint != "" {
-		if _, err := endpointaddr.Parse(spec.ExternalEndpoint, 443); err != nil {
-			return fmt.Errorf("invalid ExternalEndpoint %q: %w", spec.ExternalEndpoint, err)
-		}
-	}
-
-	return nil
-}
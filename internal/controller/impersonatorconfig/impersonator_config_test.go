@@ -5,21 +5,31 @@ package impersonatorconfig
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/pem"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"net"
 	"net/http"
 	"reflect"
 	"regexp"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 	"github.com/stretchr/testify/assert"
@@ -27,17 +37,21 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	kubeinformers "k8s.io/client-go/informers"
 	kubernetesfake "k8s.io/client-go/kubernetes/fake"
 	coretesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/util/flowcontrol"
 	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/pointer"
 
 	"go.pinniped.dev/generated/latest/apis/concierge/config/v1alpha1"
 	pinnipedfake "go.pinniped.dev/generated/latest/client/concierge/clientset/versioned/fake"
 	pinnipedinformers "go.pinniped.dev/generated/latest/client/concierge/informers/externalversions"
+	conciergeconfiginformers "go.pinniped.dev/generated/latest/client/concierge/informers/externalversions/config/v1alpha1"
 	"go.pinniped.dev/internal/certauthority"
 	"go.pinniped.dev/internal/controller/apicerts"
 	"go.pinniped.dev/internal/controllerlib"
@@ -63,6 +77,7 @@ func TestImpersonatorConfigControllerOptions(t *testing.T) {
 		var credIssuerInformerFilter controllerlib.Filter
 		var servicesInformerFilter controllerlib.Filter
 		var secretsInformerFilter controllerlib.Filter
+		var credIssuerInformer conciergeconfiginformers.CredentialIssuerInformer
 		var testLog *testlogger.Logger
 
 		it.Before(func() {
@@ -70,7 +85,7 @@ func TestImpersonatorConfigControllerOptions(t *testing.T) {
 			observableWithInformerOption = testutil.NewObservableWithInformerOption()
 			pinnipedInformerFactory := pinnipedinformers.NewSharedInformerFactory(nil, 0)
 			sharedInformerFactory := kubeinformers.NewSharedInformerFactory(nil, 0)
-			credIssuerInformer := pinnipedInformerFactory.Config().V1alpha1().CredentialIssuers()
+			credIssuerInformer = pinnipedInformerFactory.Config().V1alpha1().CredentialIssuers()
 			servicesInformer := sharedInformerFactory.Core().V1().Services()
 			secretsInformer := sharedInformerFactory.Core().V1().Secrets()
 			testLog = testlogger.New(t)
@@ -85,15 +100,25 @@ func TestImpersonatorConfigControllerOptions(t *testing.T) {
 				secretsInformer,
 				observableWithInformerOption.WithInformer,
 				impersonationProxyPort,
+				nil,
+				nil,
+				DefaultShutdownTimeout,
 				generatedLoadBalancerServiceName,
 				generatedClusterIPServiceName,
 				tlsSecretName,
 				caSecretName,
 				nil,
+				false,
+				false,
+				nil,
 				nil,
 				nil,
 				caSignerName,
 				nil,
+				nil,
+				0,
+				0,
+				nil,
 				testLog.Logger,
 			)
 			credIssuerInformerFilter = observableWithInformerOption.GetFilterForInformer(credIssuerInformer)
@@ -157,6 +182,33 @@ func TestImpersonatorConfigControllerOptions(t *testing.T) {
 				})
 			})
 
+			when("the existing Service named by the CredentialIssuer changes", func() {
+				var existingService *corev1.Service
+
+				it.Before(func() {
+					existingService = &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "some-existing-service", Namespace: installedInNamespace}}
+					r.NoError(credIssuerInformer.Informer().GetIndexer().Add(&v1alpha1.CredentialIssuer{
+						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+						Spec: v1alpha1.CredentialIssuerSpec{
+							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+								Mode: v1alpha1.ImpersonationProxyModeEnabled,
+								Service: v1alpha1.ImpersonationProxyServiceSpec{
+									Type:                v1alpha1.ImpersonationProxyServiceTypeExisting,
+									ExistingServiceName: existingService.Name,
+								},
+							},
+						},
+					}))
+				})
+
+				it("returns true to trigger the sync method", func() {
+					r.True(subject.Add(existingService))
+					r.True(subject.Update(existingService, unrelated))
+					r.True(subject.Update(unrelated, existingService))
+					r.True(subject.Delete(existingService))
+				})
+			})
+
 			when("a Service from another namespace changes", func() {
 				it("returns false to avoid triggering the sync method", func() {
 					r.False(subject.Add(wrongNamespace))
@@ -214,6 +266,42 @@ func TestImpersonatorConfigControllerOptions(t *testing.T) {
 					r.True(subject.Update(unrelated, target3))
 					r.True(subject.Delete(target3))
 				})
+
+				it("enqueues the jittered sync key", func() {
+					r.Equal(jitteredSyncKey, subject.Parent(target1))
+					r.Equal(jitteredSyncKey, subject.Parent(target2))
+					r.Equal(jitteredSyncKey, subject.Parent(target3))
+				})
+			})
+
+			when("one of the Secrets named by the CredentialIssuer changes", func() {
+				var renamedCASecret, renamedTLSSecret *corev1.Secret
+
+				it.Before(func() {
+					renamedCASecret = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "some-renamed-ca-secret", Namespace: installedInNamespace}}
+					renamedTLSSecret = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "some-renamed-tls-secret", Namespace: installedInNamespace}}
+					r.NoError(credIssuerInformer.Informer().GetIndexer().Add(&v1alpha1.CredentialIssuer{
+						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+						Spec: v1alpha1.CredentialIssuerSpec{
+							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+								Mode:                   v1alpha1.ImpersonationProxyModeEnabled,
+								GeneratedCASecretName:  renamedCASecret.Name,
+								GeneratedTLSSecretName: renamedTLSSecret.Name,
+							},
+						},
+					}))
+				})
+
+				it("returns true to trigger the sync method", func() {
+					r.True(subject.Add(renamedCASecret))
+					r.True(subject.Update(renamedCASecret, unrelated))
+					r.True(subject.Update(unrelated, renamedCASecret))
+					r.True(subject.Delete(renamedCASecret))
+					r.True(subject.Add(renamedTLSSecret))
+					r.True(subject.Update(renamedTLSSecret, unrelated))
+					r.True(subject.Update(unrelated, renamedTLSSecret))
+					r.True(subject.Delete(renamedTLSSecret))
+				})
 			})
 
 			when("a Secret from another namespace changes", func() {
@@ -279,28 +367,44 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 		var syncContext *controllerlib.Context
 		var frozenNow time.Time
 		var tlsServingCertDynamicCertProvider dynamiccert.Private
+		var metricsRegistry *prometheus.Registry
 		var signingCertProvider dynamiccert.Provider
 		var signingCACertPEM, signingCAKeyPEM []byte
 		var signingCASecret *corev1.Secret
 		var impersonatorFuncWasCalled int
+		var impersonatorFuncExpectedPort int
+		var impersonationProxyBindAddress net.IP
 		var impersonatorFuncError error
 		var impersonatorFuncReturnedFuncError error
+		var readinessProbeErrors []error
+		var readinessProbeWasCalled int
 		var startedTLSListener net.Listener
 		var startedTLSListenerMutex sync.RWMutex
 		var testHTTPServer *http.Server
 		var testHTTPServerMutex sync.RWMutex
 		var testHTTPServerInterruptCh chan struct{}
 		var queue *testQueue
+		var maxSyncJitter time.Duration
+		var syncTimeout time.Duration
 		var validClientCert *tls.Certificate
 		var testLog *testlogger.Logger
+		var controlPlaneNodeSelectors []k8slabels.Selector
+		var setOwnerReferences bool
+		var annotateIssuedSecrets bool
+		var wantOwnerReferences []metav1.OwnerReference
+		var fakeClock *clocktesting.FakeClock
 
 		var impersonatorFunc = func(
 			port int,
+			bindAddress net.IP,
 			dynamicCertProvider dynamiccert.Private,
 			impersonationProxySignerCAProvider dynamiccert.Public,
+			shutdownTimeout time.Duration,
 		) (func(stopCh <-chan struct{}) error, error) {
 			impersonatorFuncWasCalled++
-			r.Equal(8444, port)
+			r.Equal(impersonatorFuncExpectedPort, port)
+			r.Equal(impersonationProxyBindAddress, bindAddress)
+			r.Equal(DefaultShutdownTimeout, shutdownTimeout)
 			r.NotNil(dynamicCertProvider)
 			r.NotNil(impersonationProxySignerCAProvider)
 
@@ -400,6 +504,21 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 			}, nil
 		}
 
+		// Unless the test says otherwise, the started server is immediately ready, because the fake server does not
+		// listen on the requested port.
+		var readinessProbe = func(ctx context.Context, bindAddress net.IP, port int) error {
+			readinessProbeWasCalled++
+			r.NotNil(ctx)
+			r.Equal(impersonationProxyBindAddress, bindAddress)
+			r.Equal(impersonatorFuncExpectedPort, port)
+			if len(readinessProbeErrors) == 0 {
+				return nil
+			}
+			err := readinessProbeErrors[0]
+			readinessProbeErrors = readinessProbeErrors[1:]
+			return err
+		}
+
 		var testServerAddr = func() string {
 			var listener net.Listener
 			require.Eventually(t, func() bool {
@@ -543,6 +662,9 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 		// Defer starting the informers until the last possible moment so that the
 		// nested Before's can keep adding things to the informer caches.
 		var startInformersAndController = func() {
+			fakeClock = clocktesting.NewFakeClock(frozenNow)
+			metricsRegistry = prometheus.NewRegistry()
+
 			// Set this at the last second to allow for injection of server override.
 			subject = NewImpersonatorConfigController(
 				installedInNamespace,
@@ -554,15 +676,25 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 				kubeInformers.Core().V1().Secrets(),
 				controllerlib.WithInformer,
 				impersonationProxyPort,
+				impersonationProxyBindAddress,
+				controlPlaneNodeSelectors,
+				DefaultShutdownTimeout,
 				loadBalancerServiceName,
 				clusterIPServiceName,
 				tlsSecretName,
 				caSecretName,
 				labels,
-				clocktesting.NewFakeClock(frozenNow),
+				setOwnerReferences,
+				annotateIssuedSecrets,
+				fakeClock,
 				impersonatorFunc,
+				readinessProbe,
 				caSignerName,
 				signingCertProvider,
+				flowcontrol.NewFakeAlwaysRateLimiter(),
+				maxSyncJitter,
+				syncTimeout,
+				metricsRegistry,
 				testLog.Logger,
 			)
 			controllerlib.TestWrap(t, subject, func(syncer controllerlib.Syncer) controllerlib.Syncer {
@@ -669,7 +801,8 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 							Protocol:   corev1.ProtocolTCP,
 						},
 					},
-					Selector: map[string]string{appLabelKey: labels[appLabelKey]},
+					Selector:              map[string]string{appLabelKey: labels[appLabelKey]},
+					ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeCluster, // defaulted by the API server
 				},
 				Status: status,
 			}
@@ -866,12 +999,17 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 		}
 
 		var requireNodesListed = func(action coretesting.Action) {
+			listOptions := metav1.ListOptions{}
+			if len(controlPlaneNodeSelectors) == 1 {
+				// Multiple selectors cannot be combined into one, so all nodes are listed in that case.
+				listOptions.LabelSelector = controlPlaneNodeSelectors[0].String()
+			}
 			r.Equal(
 				coretesting.NewListAction(
 					schema.GroupVersionResource{Version: "v1", Resource: "nodes"},
 					schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Node"},
 					"",
-					metav1.ListOptions{}),
+					listOptions),
 				action,
 			)
 		}
@@ -925,17 +1063,38 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 			return newPendingStrategy("waiting for load balancer Service to be assigned IP or hostname")
 		}
 
-		var newErrorStrategy = func(msg string) v1alpha1.CredentialIssuerStrategy {
+		var newPendingStrategyWaitingForClusterIP = func() v1alpha1.CredentialIssuerStrategy {
+			return newPendingStrategy("waiting for ClusterIP Service to be assigned a cluster IP")
+		}
+
+		var newErrorStrategy = func(reason v1alpha1.StrategyReason, msg string) v1alpha1.CredentialIssuerStrategy {
 			return v1alpha1.CredentialIssuerStrategy{
 				Type:           v1alpha1.ImpersonationProxyStrategyType,
 				Status:         v1alpha1.ErrorStrategyStatus,
-				Reason:         v1alpha1.ErrorDuringSetupStrategyReason,
+				Reason:         reason,
 				Message:        msg,
 				LastUpdateTime: metav1.NewTime(frozenNow),
 				Frontend:       nil,
 			}
 		}
 
+		// tlsServingCertNotAfter returns the expiration time of the certificate in the TLS Secret, preferring
+		// the one created by the controller over the one which was added to the informer by the test.
+		var tlsServingCertNotAfter = func() *metav1.Time {
+			secretsGVR := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+			secretObj, err := kubeAPIClient.Tracker().Get(secretsGVR, installedInNamespace, tlsSecretName)
+			if k8serrors.IsNotFound(err) {
+				secretObj, err = kubeInformerClient.Tracker().Get(secretsGVR, installedInNamespace, tlsSecretName)
+			}
+			r.NoError(err)
+			block, _ := pem.Decode(secretObj.(*corev1.Secret).Data[corev1.TLSCertKey])
+			r.NotNil(block)
+			cert, err := x509.ParseCertificate(block.Bytes)
+			r.NoError(err)
+			notAfter := metav1.NewTime(cert.NotAfter)
+			return &notAfter
+		}
+
 		var getCredentialIssuer = func() *v1alpha1.CredentialIssuer {
 			credentialIssuerObj, err := pinnipedAPIClient.Tracker().Get(
 				schema.GroupVersionResource{
@@ -959,6 +1118,12 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 			// As long as we get the final result that we wanted then we are happy for the purposes
 			// of this test.
 			credentialIssuer := getCredentialIssuer()
+			if expectedStrategy.Frontend != nil && expectedStrategy.Frontend.ImpersonationProxyInfo != nil &&
+				expectedStrategy.Frontend.ImpersonationProxyInfo.CertificateNotAfter == nil {
+				// Unless the test says otherwise, expect the expiration time of the TLS serving certificate.
+				expectedStrategy = *expectedStrategy.DeepCopy()
+				expectedStrategy.Frontend.ImpersonationProxyInfo.CertificateNotAfter = tlsServingCertNotAfter()
+			}
 			r.Equal([]v1alpha1.CredentialIssuerStrategy{expectedStrategy}, credentialIssuer.Status.Strategies)
 		}
 
@@ -980,6 +1145,7 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 			r.Equal(corev1.ServiceTypeLoadBalancer, createdLoadBalancerService.Spec.Type)
 			r.Equal("app-name", createdLoadBalancerService.Spec.Selector["app"])
 			r.Equal(labels, createdLoadBalancerService.Labels)
+			r.Equal(wantOwnerReferences, createdLoadBalancerService.OwnerReferences)
 			return createdLoadBalancerService
 		}
 
@@ -1005,6 +1171,7 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 			r.Equal(corev1.ServiceTypeClusterIP, createdClusterIPService.Spec.Type)
 			r.Equal("app-name", createdClusterIPService.Spec.Selector["app"])
 			r.Equal(labels, createdClusterIPService.Labels)
+			r.Equal(wantOwnerReferences, createdClusterIPService.OwnerReferences)
 			return createdClusterIPService
 		}
 
@@ -1032,7 +1199,7 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 			r.Equal(testutil.NewPreconditions("uid-1234", "rv-5678"), deleteAction.GetDeleteOptions())
 		}
 
-		var requireCASecretWasCreated = func(action coretesting.Action) []byte {
+		var requireCASecretWasCreatedWithLifetimeAndSubject = func(action coretesting.Action, lifetime time.Duration, subject pkix.Name) []byte {
 			createAction, ok := action.(coretesting.CreateAction)
 			r.True(ok, "should have been able to cast this action to CreateAction: %v", action)
 			r.Equal("create", createAction.GetVerb())
@@ -1041,6 +1208,7 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 			r.Equal(installedInNamespace, createdSecret.Namespace)
 			r.Equal(corev1.SecretTypeOpaque, createdSecret.Type)
 			r.Equal(labels, createdSecret.Labels)
+			r.Equal(wantOwnerReferences, createdSecret.OwnerReferences)
 			r.Len(createdSecret.Data, 2)
 			createdCertPEM := createdSecret.Data["ca.crt"]
 			createdKeyPEM := createdSecret.Data["ca.key"]
@@ -1053,13 +1221,55 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 			require.NotNil(t, block)
 			caCert, err := x509.ParseCertificate(block.Bytes)
 			require.NoError(t, err)
-			require.Equal(t, "Pinniped Impersonation Proxy Serving CA", caCert.Subject.CommonName)
+			require.Equal(t, subject.CommonName, caCert.Subject.CommonName)
+			require.Equal(t, subject.Organization, caCert.Subject.Organization)
 			require.WithinDuration(t, time.Now().Add(-5*time.Minute), caCert.NotBefore, 10*time.Second)
-			require.WithinDuration(t, time.Now().Add(100*time.Hour*24*365), caCert.NotAfter, 10*time.Second)
+			require.WithinDuration(t, time.Now().Add(lifetime), caCert.NotAfter, 10*time.Second)
 			return createdCertPEM
 		}
 
-		var requireTLSSecretWasCreated = func(action coretesting.Action, caCert []byte) {
+		var requireCASecretWasCreatedWithLifetime = func(action coretesting.Action, lifetime time.Duration) []byte {
+			return requireCASecretWasCreatedWithLifetimeAndSubject(action, lifetime, pkix.Name{CommonName: "Pinniped Impersonation Proxy Serving CA"})
+		}
+
+		var requireCASecretWasCreated = func(action coretesting.Action) []byte {
+			return requireCASecretWasCreatedWithLifetime(action, 100*time.Hour*24*365)
+		}
+
+		var requireCASecretWasUpdated = func(action coretesting.Action, oldCACert []byte, wantPreviousCACert []byte) []byte {
+			updateAction, ok := action.(coretesting.UpdateAction)
+			r.True(ok, "should have been able to cast this action to UpdateAction: %v", action)
+			r.Equal("update", updateAction.GetVerb())
+			updatedSecret := updateAction.GetObject().(*corev1.Secret)
+			r.Equal(caSecretName, updatedSecret.Name)
+			r.Equal(installedInNamespace, updatedSecret.Namespace)
+			if wantPreviousCACert == nil {
+				r.Len(updatedSecret.Data, 2)
+			} else {
+				r.Len(updatedSecret.Data, 3)
+				r.Equal(string(wantPreviousCACert), string(updatedSecret.Data["previous-ca.crt"]))
+			}
+			updatedCertPEM := updatedSecret.Data["ca.crt"]
+			updatedKeyPEM := updatedSecret.Data["ca.key"]
+			r.NotEqual(string(oldCACert), string(updatedCertPEM))
+			_, err := tls.X509KeyPair(updatedCertPEM, updatedKeyPEM)
+			r.NoError(err, "key does not match cert")
+			return updatedCertPEM
+		}
+
+		var requirePreviousCACertWasRemoved = func(action coretesting.Action, caCert []byte) {
+			updateAction, ok := action.(coretesting.UpdateAction)
+			r.True(ok, "should have been able to cast this action to UpdateAction: %v", action)
+			r.Equal("update", updateAction.GetVerb())
+			updatedSecret := updateAction.GetObject().(*corev1.Secret)
+			r.Equal(caSecretName, updatedSecret.Name)
+			r.Equal(installedInNamespace, updatedSecret.Namespace)
+			r.Len(updatedSecret.Data, 2)
+			r.Equal(string(caCert), string(updatedSecret.Data["ca.crt"]))
+			r.NotContains(updatedSecret.Data, "previous-ca.crt")
+		}
+
+		var requireTLSSecretWasCreatedWithLifetime = func(action coretesting.Action, caCert []byte, lifetime time.Duration) {
 			createAction, ok := action.(coretesting.CreateAction)
 			r.True(ok, "should have been able to cast this action to CreateAction: %v", action)
 			r.Equal("create", createAction.GetVerb())
@@ -1068,6 +1278,7 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 			r.Equal(installedInNamespace, createdSecret.Namespace)
 			r.Equal(corev1.SecretTypeTLS, createdSecret.Type)
 			r.Equal(labels, createdSecret.Labels)
+			r.Equal(wantOwnerReferences, createdSecret.OwnerReferences)
 			r.Len(createdSecret.Data, 2)
 			createdCertPEM := createdSecret.Data[corev1.TLSCertKey]
 			createdKeyPEM := createdSecret.Data[corev1.TLSPrivateKeyKey]
@@ -1075,7 +1286,86 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 			r.NotNil(createdCertPEM)
 			validCert := testutil.ValidateServerCertificate(t, string(caCert), string(createdCertPEM))
 			validCert.RequireMatchesPrivateKey(string(createdKeyPEM))
-			validCert.RequireLifetime(time.Now().Add(-5*time.Minute), time.Now().Add(100*time.Hour*24*365), 10*time.Second)
+			// The TLS serving cert never outlives the CA which issued it.
+			wantNotAfter := time.Now().Add(lifetime)
+			block, _ := pem.Decode(caCert)
+			r.NotNil(block)
+			parsedCACert, err := x509.ParseCertificate(block.Bytes)
+			r.NoError(err)
+			if parsedCACert.NotAfter.Before(wantNotAfter) {
+				wantNotAfter = parsedCACert.NotAfter
+			}
+			validCert.RequireLifetime(time.Now().Add(-5*time.Minute), wantNotAfter, 10*time.Second)
+		}
+
+		var requireTLSSecretWasCreated = func(action coretesting.Action, caCert []byte) {
+			requireTLSSecretWasCreatedWithLifetime(action, caCert, 100*time.Hour*24*365)
+		}
+
+		// gatherMetrics returns the values of the metrics in the metricsRegistry, keyed by metric name and then by
+		// the value of the "reason" label, which is empty for metrics without labels.
+		var gatherMetrics = func() map[string]map[string]float64 {
+			families, err := metricsRegistry.Gather()
+			r.NoError(err)
+			values := map[string]map[string]float64{}
+			for _, family := range families {
+				values[family.GetName()] = map[string]float64{}
+				for _, metric := range family.GetMetric() {
+					reason := ""
+					for _, label := range metric.GetLabel() {
+						if label.GetName() == "reason" {
+							reason = label.GetValue()
+						}
+					}
+					if metric.GetCounter() != nil {
+						values[family.GetName()][reason] = metric.GetCounter().GetValue()
+					} else {
+						values[family.GetName()][reason] = metric.GetGauge().GetValue()
+					}
+				}
+			}
+			return values
+		}
+
+		var requireCertificateIssuanceMetrics = func(wantCAIssued map[string]float64, wantTLSIssued map[string]float64) {
+			metrics := gatherMetrics()
+			r.Equal(wantCAIssued, metrics["pinniped_impersonation_ca_issued_total"])
+			r.Equal(wantTLSIssued, metrics["pinniped_impersonation_tls_issued_total"])
+		}
+
+		var requireTLSCertificateExpirationMetric = func(action coretesting.Action) {
+			createdSecret := action.(coretesting.CreateAction).GetObject().(*corev1.Secret)
+			block, _ := pem.Decode(createdSecret.Data[corev1.TLSCertKey])
+			r.NotNil(block)
+			cert, err := x509.ParseCertificate(block.Bytes)
+			r.NoError(err)
+			r.Equal(
+				map[string]float64{"": float64(cert.NotAfter.Unix())},
+				gatherMetrics()["pinniped_impersonation_tls_certificate_expiration_timestamp_seconds"],
+			)
+		}
+
+		var requireTLSSecretHasNames = func(action coretesting.Action, ips []string, hostnames []string) {
+			createdSecret := action.(coretesting.CreateAction).GetObject().(*corev1.Secret)
+			block, _ := pem.Decode(createdSecret.Data[corev1.TLSCertKey])
+			r.NotNil(block)
+			cert, err := x509.ParseCertificate(block.Bytes)
+			r.NoError(err)
+			actualIPs := make([]string, 0, len(cert.IPAddresses))
+			for _, ip := range cert.IPAddresses {
+				actualIPs = append(actualIPs, ip.String())
+			}
+			r.Equal(ips, actualIPs)
+			r.Equal(hostnames, append([]string{}, cert.DNSNames...))
+		}
+
+		var requireTLSSecretHasCommonName = func(action coretesting.Action, commonName string) {
+			createdSecret := action.(coretesting.CreateAction).GetObject().(*corev1.Secret)
+			block, _ := pem.Decode(createdSecret.Data[corev1.TLSCertKey])
+			r.NotNil(block)
+			cert, err := x509.ParseCertificate(block.Bytes)
+			r.NoError(err)
+			r.Equal(commonName, cert.Subject.CommonName)
 		}
 
 		var requireSigningCertProviderHasLoadedCerts = func(certPEM, keyPEM []byte) {
@@ -1098,6 +1388,16 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 		it.Before(func() {
 			r = require.New(t)
 			queue = &testQueue{}
+			maxSyncJitter = 0
+			syncTimeout = 0
+			readinessProbeErrors = nil
+			readinessProbeWasCalled = 0
+			impersonatorFuncExpectedPort = impersonationProxyPort
+			impersonationProxyBindAddress = nil
+			controlPlaneNodeSelectors = nil
+			setOwnerReferences = false
+			annotateIssuedSecrets = false
+			wantOwnerReferences = nil
 			cancelContext, cancelContextCancelFunc = context.WithCancel(context.Background())
 
 			pinnipedInformerClient = pinnipedfake.NewSimpleClientset()
@@ -1198,6 +1498,46 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 					requireTLSServerIsRunning(ca, testServerAddr(), nil)
 					requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
 					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+					requireCertificateIssuanceMetrics(map[string]float64{caIssuedReasonCreated: 1}, map[string]float64{tlsIssuedReasonMissing: 1})
+					requireTLSCertificateExpirationMetric(kubeAPIClient.Actions()[2])
+				})
+
+				when("the impersonator is slow to start accepting connections", func() {
+					it.Before(func() {
+						readinessProbeErrors = []error{errors.New("connection refused"), errors.New("connection refused")}
+					})
+
+					it("reports a pending strategy and retries until the impersonator is ready", func() {
+						startInformersAndController()
+						for attempt := 1; attempt <= 2; attempt++ {
+							r.NoError(runControllerSync())
+							r.Equal(attempt, readinessProbeWasCalled)
+							r.Len(queue.addedAfterKeys, attempt)
+							r.Equal(syncContext.Key, queue.addedAfterKeys[attempt-1])
+							r.Equal(readinessRetryInterval, queue.addedAfterDurations[attempt-1])
+							requireCredentialIssuer(newPendingStrategy("waiting for impersonation proxy to become ready"))
+							if attempt == 1 {
+								// Simulate the informer cache's background update from its watch.
+								addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Secrets())
+								addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+							}
+						}
+						r.Equal(1, impersonatorFuncWasCalled) // the server was started once and kept running
+
+						// Once the impersonator accepts connections, it is advertised to clients.
+						r.NoError(runControllerSync())
+						r.Equal(3, readinessProbeWasCalled)
+						r.Len(queue.addedAfterKeys, 2)
+						r.Len(kubeAPIClient.Actions(), 3)
+						ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
+						requireTLSServerIsRunning(ca, testServerAddr(), nil)
+						requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+
+						// A ready impersonator is not probed again by later syncs.
+						r.NoError(runControllerSync())
+						r.Equal(3, readinessProbeWasCalled)
+						requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+					})
 				})
 			})
 		})
@@ -1270,6 +1610,105 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 				})
 			})
 
+			when("a custom control plane node selector is configured", func() {
+				it.Before(func() {
+					controlPlaneNodeSelectors = []k8slabels.Selector{k8slabels.SelectorFromSet(k8slabels.Set{"example.com/role": "control"})}
+				})
+
+				when("there are nodes which match the selector", func() {
+					it.Before(func() {
+						r.NoError(kubeAPIClient.Tracker().Add(&corev1.Node{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:   "custom-control-plane-node",
+								Labels: map[string]string{"example.com/role": "control"},
+							},
+						}))
+					})
+
+					it("does not start the impersonator or load balancer", func() {
+						startInformersAndController()
+						r.NoError(runControllerSync())
+						requireTLSServerWasNeverStarted()
+						r.Len(kubeAPIClient.Actions(), 1)
+						requireNodesListed(kubeAPIClient.Actions()[0])
+						requireCredentialIssuer(newAutoDisabledStrategy())
+						requireSigningCertProviderIsEmpty()
+					})
+				})
+
+				when("there are only nodes with the default control plane role label, which do not match the selector", func() {
+					it.Before(func() {
+						addNodeWithRoleToTracker("control-plane", kubeAPIClient)
+					})
+
+					it("starts the load balancer automatically", func() {
+						startInformersAndController()
+						r.NoError(runControllerSync())
+						requireTLSServerIsRunningWithoutCerts()
+						r.Len(kubeAPIClient.Actions(), 3)
+						requireNodesListed(kubeAPIClient.Actions()[0])
+						requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
+						requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+						requireCredentialIssuer(newPendingStrategyWaitingForLB())
+						requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+					})
+				})
+			})
+
+			when("multiple custom control plane node selectors are configured", func() {
+				it.Before(func() {
+					controlPlaneNodeSelectors = []k8slabels.Selector{
+						k8slabels.SelectorFromSet(k8slabels.Set{"example.com/role": "control"}),
+						k8slabels.SelectorFromSet(k8slabels.Set{"node-role.kubernetes.io/master": ""}),
+					}
+				})
+
+				when("there are nodes with only the legacy master role label, which match one of the selectors", func() {
+					it.Before(func() {
+						r.NoError(kubeAPIClient.Tracker().Add(&corev1.Node{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:   "legacy-control-plane-node",
+								Labels: map[string]string{"node-role.kubernetes.io/master": ""},
+							},
+						}))
+						r.NoError(kubeAPIClient.Tracker().Add(&corev1.Node{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:   "worker-node",
+								Labels: map[string]string{"node-role.kubernetes.io/worker": ""},
+							},
+						}))
+					})
+
+					it("does not start the impersonator or load balancer", func() {
+						startInformersAndController()
+						r.NoError(runControllerSync())
+						requireTLSServerWasNeverStarted()
+						r.Len(kubeAPIClient.Actions(), 1)
+						requireNodesListed(kubeAPIClient.Actions()[0])
+						requireCredentialIssuer(newAutoDisabledStrategy())
+						requireSigningCertProviderIsEmpty()
+					})
+				})
+
+				when("there are no nodes which match any of the selectors", func() {
+					it.Before(func() {
+						addNodeWithRoleToTracker("worker", kubeAPIClient)
+					})
+
+					it("starts the load balancer automatically", func() {
+						startInformersAndController()
+						r.NoError(runControllerSync())
+						requireTLSServerIsRunningWithoutCerts()
+						r.Len(kubeAPIClient.Actions(), 3)
+						requireNodesListed(kubeAPIClient.Actions()[0])
+						requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
+						requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+						requireCredentialIssuer(newPendingStrategyWaitingForLB())
+						requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+					})
+				})
+			})
+
 			when("there are not visible control plane nodes and a load balancer already exists without an IP/hostname", func() {
 				it.Before(func() {
 					addNodeWithRoleToTracker("worker", kubeAPIClient)
@@ -1321,7 +1760,7 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 					requireTLSServerIsRunningWithoutCerts()
 					r.Len(kubeAPIClient.Actions(), 1)
 					requireNodesListed(kubeAPIClient.Actions()[0])
-					requireCredentialIssuer(newErrorStrategy("could not find valid IP addresses or hostnames from load balancer some-namespace/some-service-resource-name"))
+					requireCredentialIssuer(newErrorStrategy(v1alpha1.ServiceErrorStrategyReason, "could not find valid IP addresses or hostnames from load balancer some-namespace/some-service-resource-name"))
 					requireSigningCertProviderIsEmpty()
 				})
 			})
@@ -1336,11 +1775,12 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 					r.NoError(runControllerSync())
 				})
 
-				it("starts the impersonator with certs that match the first IP address", func() {
+				it("starts the impersonator with certs that match the valid IP addresses and advertises the first IP address", func() {
 					r.Len(kubeAPIClient.Actions(), 3)
 					requireNodesListed(kubeAPIClient.Actions()[0])
 					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
 					requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
+					requireTLSSecretHasNames(kubeAPIClient.Actions()[2], []string{fakeIP}, []string{})
 					requireTLSServerIsRunning(ca, fakeIP, map[string]string{fakeIP + ":443": testServerAddr()})
 					requireCredentialIssuer(newSuccessStrategy(fakeIP, ca))
 					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
@@ -1367,12 +1807,14 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 					r.NoError(runControllerSync())
 				})
 
-				it("starts the impersonator with certs that match the first hostname", func() {
+				it("starts the impersonator with certs that match all of the hostnames and advertises the first hostname", func() {
 					r.Len(kubeAPIClient.Actions(), 3)
 					requireNodesListed(kubeAPIClient.Actions()[0])
 					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
 					requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
+					requireTLSSecretHasNames(kubeAPIClient.Actions()[2], []string{}, []string{firstHostname, "fake-2.example.com"})
 					requireTLSServerIsRunning(ca, firstHostname, map[string]string{firstHostname + httpsPort: testServerAddr()})
+					requireTLSServerIsRunning(ca, "fake-2.example.com", map[string]string{"fake-2.example.com" + httpsPort: testServerAddr()})
 					requireCredentialIssuer(newSuccessStrategy(firstHostname, ca))
 					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
 
@@ -1385,6 +1827,21 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 					r.Len(kubeAPIClient.Actions(), 3) // nothing changed
 					requireCredentialIssuer(newSuccessStrategy(firstHostname, ca))
 					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+					// Add another ingress to the load balancer.
+					updateLoadBalancerServiceInInformerAndWait(loadBalancerServiceName, []corev1.LoadBalancerIngress{
+						{Hostname: firstHostname}, {Hostname: "fake-2.example.com"}, {Hostname: "fake-3.example.com"},
+					}, kubeInformers.Core().V1().Services())
+
+					// reissues the cert to include the new hostname, but still advertises the first hostname
+					r.NoError(runControllerSync())
+					r.Len(kubeAPIClient.Actions(), 5)
+					requireTLSSecretWasDeleted(kubeAPIClient.Actions()[3])
+					requireTLSSecretWasCreated(kubeAPIClient.Actions()[4], ca)
+					requireTLSSecretHasNames(kubeAPIClient.Actions()[4], []string{}, []string{firstHostname, "fake-2.example.com", "fake-3.example.com"})
+					requireTLSServerIsRunning(ca, "fake-3.example.com", map[string]string{"fake-3.example.com" + httpsPort: testServerAddr()})
+					requireCredentialIssuer(newSuccessStrategy(firstHostname, ca))
+					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
 				})
 			})
 
@@ -1398,11 +1855,13 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 					r.NoError(runControllerSync())
 				})
 
-				it("starts the impersonator with certs that match the first hostname", func() {
+				it("starts the impersonator with certs that match the hostnames and ips and advertises the first hostname", func() {
 					r.Len(kubeAPIClient.Actions(), 3)
 					requireNodesListed(kubeAPIClient.Actions()[0])
 					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
 					requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
+					requireTLSSecretHasNames(kubeAPIClient.Actions()[2], []string{"127.0.0.254"}, []string{firstHostname})
+					requireTLSServerIsRunning(ca, "127.0.0.254", map[string]string{"127.0.0.254" + httpsPort: testServerAddr()})
 					requireTLSServerIsRunning(ca, firstHostname, map[string]string{firstHostname + httpsPort: testServerAddr()})
 					requireCredentialIssuer(newSuccessStrategy(firstHostname, ca))
 					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
@@ -1465,7 +1924,7 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 					requireNodesListed(kubeAPIClient.Actions()[0])
 					requireTLSSecretWasDeleted(kubeAPIClient.Actions()[1])
 					requireTLSServerIsRunningWithoutCerts()
-					requireCredentialIssuer(newErrorStrategy("error on delete"))
+					requireCredentialIssuer(newErrorStrategy(v1alpha1.SecretErrorStrategyReason, "error on delete"))
 					requireSigningCertProviderIsEmpty()
 				})
 			})
@@ -1497,7 +1956,7 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 					r.EqualError(runControllerSync(), errString)
 					r.Len(kubeAPIClient.Actions(), 1)                       // no new actions
 					requireTLSServerIsRunning(caCrt, testServerAddr(), nil) // serving certificate is not unloaded in this case
-					requireCredentialIssuer(newErrorStrategy(errString))
+					requireCredentialIssuer(newErrorStrategy(v1alpha1.ServiceErrorStrategyReason, errString))
 					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
 				})
 			})
@@ -1561,7 +2020,7 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 					impersonatorFuncError = errors.New("impersonation server start error")
 					startInformersAndController()
 					r.EqualError(runControllerSync(), "impersonation server start error")
-					requireCredentialIssuer(newErrorStrategy("impersonation server start error"))
+					requireCredentialIssuer(newErrorStrategy(v1alpha1.ServerStartErrorStrategyReason, "impersonation server start error"))
 					requireSigningCertProviderIsEmpty()
 				})
 			})
@@ -1596,13 +2055,13 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 					impersonatorFuncError = errors.New("impersonation server start error")
 					startInformersAndController()
 					r.EqualError(runControllerSync(), "impersonation server start error")
-					requireCredentialIssuer(newErrorStrategy("impersonation server start error"))
+					requireCredentialIssuer(newErrorStrategy(v1alpha1.ServerStartErrorStrategyReason, "impersonation server start error"))
 					requireSigningCertProviderIsEmpty()
 				})
 			})
 
-			when("a clusterip already exists with ingress", func() {
-				const fakeIP = "127.0.0.123"
+			when("a loadbalancer already exists with an ip and a custom service port is configured", func() {
+				const fakeIP = "127.0.0.254"
 				it.Before(func() {
 					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
@@ -1610,32 +2069,37 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
 								Mode: v1alpha1.ImpersonationProxyModeEnabled,
 								Service: v1alpha1.ImpersonationProxyServiceSpec{
-									Type: v1alpha1.ImpersonationProxyServiceTypeClusterIP,
+									Type: v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+									Port: pointer.Int32Ptr(8443),
 								},
 							},
 						},
 					}, pinnipedInformerClient, pinnipedAPIClient)
 					addNodeWithRoleToTracker("worker", kubeAPIClient)
-					addClusterIPServiceToTracker(clusterIPServiceName, fakeIP, kubeInformerClient)
-					addClusterIPServiceToTracker(clusterIPServiceName, fakeIP, kubeAPIClient)
+					addLoadBalancerServiceWithIngressToTracker(loadBalancerServiceName, []corev1.LoadBalancerIngress{{IP: fakeIP}}, kubeInformerClient)
+					addLoadBalancerServiceWithIngressToTracker(loadBalancerServiceName, []corev1.LoadBalancerIngress{{IP: fakeIP}}, kubeAPIClient)
 				})
 
-				it("starts the impersonator without creating a clusterip", func() {
+				it("updates the port of the load balancer and advertises it in the endpoint", func() {
 					startInformersAndController()
 					r.NoError(runControllerSync())
-					r.Len(kubeAPIClient.Actions(), 3)
+					r.Len(kubeAPIClient.Actions(), 4)
 					requireNodesListed(kubeAPIClient.Actions()[0])
-					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
-					requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
-					requireTLSServerIsRunning(ca, fakeIP, map[string]string{fakeIP + ":443": testServerAddr()})
-					requireCredentialIssuer(newSuccessStrategy(fakeIP, ca))
-					// requireSigningCertProviderHasLoadedCerts()
+					updatedService := requireLoadBalancerWasUpdated(kubeAPIClient.Actions()[1])
+					r.Len(updatedService.Spec.Ports, 1)
+					r.Equal(int32(8443), updatedService.Spec.Ports[0].Port)
+					r.Equal(intstr.FromInt(impersonationProxyPort), updatedService.Spec.Ports[0].TargetPort)
+					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+					requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
+					requireTLSSecretHasNames(kubeAPIClient.Actions()[3], []string{fakeIP}, []string{})
+					requireTLSServerIsRunning(ca, fakeIP, map[string]string{fakeIP + httpsPort: testServerAddr()})
+					requireCredentialIssuer(newSuccessStrategy(fakeIP+":8443", ca))
+					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
 				})
 			})
 
-			when("a clusterip service exists with dual stack ips", func() {
-				const fakeIP1 = "127.0.0.123"
-				const fakeIP2 = "fd00::5118"
+			when("a loadbalancer already exists with hostnames and ips and ips are preferred", func() {
+				firstHostname := "fake-1.example.com"
 				it.Before(func() {
 					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
@@ -1643,63 +2107,97 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
 								Mode: v1alpha1.ImpersonationProxyModeEnabled,
 								Service: v1alpha1.ImpersonationProxyServiceSpec{
-									Type: v1alpha1.ImpersonationProxyServiceTypeClusterIP,
+									Type:                 v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+									PreferredAddressType: v1alpha1.ImpersonationProxyServiceAddressTypeIP,
 								},
 							},
 						},
 					}, pinnipedInformerClient, pinnipedAPIClient)
 					addNodeWithRoleToTracker("worker", kubeAPIClient)
-					addDualStackClusterIPServiceToTracker(clusterIPServiceName, fakeIP1, fakeIP2, kubeInformerClient)
-					addDualStackClusterIPServiceToTracker(clusterIPServiceName, fakeIP1, fakeIP2, kubeAPIClient)
-				})
-
-				it("certs are valid for both ip addresses", func() {
+					addLoadBalancerServiceWithIngressToTracker(loadBalancerServiceName, []corev1.LoadBalancerIngress{{Hostname: firstHostname}, {IP: "127.0.0.254"}}, kubeInformerClient)
+					addLoadBalancerServiceWithIngressToTracker(loadBalancerServiceName, []corev1.LoadBalancerIngress{{Hostname: firstHostname}, {IP: "127.0.0.254"}}, kubeAPIClient)
 					startInformersAndController()
 					r.NoError(runControllerSync())
+				})
+
+				it("advertises the first ip, and reissues the cert when the preference changes", func() {
 					r.Len(kubeAPIClient.Actions(), 3)
 					requireNodesListed(kubeAPIClient.Actions()[0])
 					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
 					requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
-					requireTLSServerIsRunning(ca, "["+fakeIP2+"]", map[string]string{"[fd00::5118]:443": testServerAddr()})
-					requireTLSServerIsRunning(ca, fakeIP1, map[string]string{fakeIP1 + ":443": testServerAddr()})
-					requireCredentialIssuer(newSuccessStrategy(fakeIP1, ca))
+					requireTLSSecretHasNames(kubeAPIClient.Actions()[2], []string{"127.0.0.254"}, []string{firstHostname})
+					requireTLSSecretHasCommonName(kubeAPIClient.Actions()[2], "127.0.0.254")
+					requireTLSServerIsRunning(ca, "127.0.0.254", map[string]string{"127.0.0.254" + httpsPort: testServerAddr()})
+					requireTLSServerIsRunning(ca, firstHostname, map[string]string{firstHostname + httpsPort: testServerAddr()})
+					requireCredentialIssuer(newSuccessStrategy("127.0.0.254", ca))
+					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+					// Simulate the informer cache's background update from its watch.
+					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Secrets())
+					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+
+					// keeps the secret around after resync
+					r.NoError(runControllerSync())
+					r.Len(kubeAPIClient.Actions(), 3) // nothing changed
+					requireCredentialIssuer(newSuccessStrategy("127.0.0.254", ca))
+
+					// Prefer hostnames instead.
+					updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode: v1alpha1.ImpersonationProxyModeEnabled,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type:                 v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+								PreferredAddressType: v1alpha1.ImpersonationProxyServiceAddressTypeHostname,
+							},
+						},
+					}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+
+					// reissues the cert with the same names but a new common name, and advertises the first hostname
+					r.NoError(runControllerSync())
+					r.Len(kubeAPIClient.Actions(), 5)
+					requireTLSSecretWasDeleted(kubeAPIClient.Actions()[3])
+					requireTLSSecretWasCreated(kubeAPIClient.Actions()[4], ca)
+					requireTLSSecretHasNames(kubeAPIClient.Actions()[4], []string{"127.0.0.254"}, []string{firstHostname})
+					requireTLSSecretHasCommonName(kubeAPIClient.Actions()[4], firstHostname)
+					requireTLSServerIsRunning(ca, firstHostname, map[string]string{firstHostname + httpsPort: testServerAddr()})
+					requireCredentialIssuer(newSuccessStrategy(firstHostname, ca))
+					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
 				})
 			})
 
-			when("a load balancer and a secret already exists", func() {
-				var caCrt []byte
+			when("a clusterip already exists with ingress", func() {
+				const fakeIP = "127.0.0.123"
 				it.Before(func() {
 					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 						Spec: v1alpha1.CredentialIssuerSpec{
 							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
 								Mode: v1alpha1.ImpersonationProxyModeEnabled,
+								Service: v1alpha1.ImpersonationProxyServiceSpec{
+									Type: v1alpha1.ImpersonationProxyServiceTypeClusterIP,
+								},
 							},
 						},
 					}, pinnipedInformerClient, pinnipedAPIClient)
 					addNodeWithRoleToTracker("worker", kubeAPIClient)
-					ca := newCA()
-					caSecret := newActualCASecret(ca, caSecretName)
-					caCrt = caSecret.Data["ca.crt"]
-					addSecretToTrackers(caSecret, kubeAPIClient, kubeInformerClient)
-					tlsSecret := newActualTLSSecret(ca, tlsSecretName, localhostIP)
-					addSecretToTrackers(tlsSecret, kubeAPIClient, kubeInformerClient)
-					addLoadBalancerServiceWithIngressToTracker(loadBalancerServiceName, []corev1.LoadBalancerIngress{{IP: localhostIP}}, kubeInformerClient)
-					addLoadBalancerServiceWithIngressToTracker(loadBalancerServiceName, []corev1.LoadBalancerIngress{{IP: localhostIP}}, kubeAPIClient)
+					addClusterIPServiceToTracker(clusterIPServiceName, fakeIP, kubeInformerClient)
+					addClusterIPServiceToTracker(clusterIPServiceName, fakeIP, kubeAPIClient)
 				})
 
-				it("starts the impersonator with the existing tls certs, does not start loadbalancer or make tls secret", func() {
+				it("starts the impersonator without creating a clusterip", func() {
 					startInformersAndController()
 					r.NoError(runControllerSync())
-					r.Len(kubeAPIClient.Actions(), 1)
+					r.Len(kubeAPIClient.Actions(), 3)
 					requireNodesListed(kubeAPIClient.Actions()[0])
-					requireTLSServerIsRunning(caCrt, testServerAddr(), nil)
-					requireCredentialIssuer(newSuccessStrategy(localhostIP, caCrt))
-					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
+					requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
+					requireTLSServerIsRunning(ca, fakeIP, map[string]string{fakeIP + ":443": testServerAddr()})
+					requireCredentialIssuer(newSuccessStrategy(fakeIP, ca))
+					// requireSigningCertProviderHasLoadedCerts()
 				})
 			})
 
-			when("credentialissuer has service type loadbalancer and custom annotations", func() {
+			when("a clusterip does not exist yet and a custom service port is configured", func() {
 				it.Before(func() {
 					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
@@ -1707,8 +2205,8 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
 								Mode: v1alpha1.ImpersonationProxyModeEnabled,
 								Service: v1alpha1.ImpersonationProxyServiceSpec{
-									Type:        v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
-									Annotations: map[string]string{"some-annotation-key": "some-annotation-value"},
+									Type: v1alpha1.ImpersonationProxyServiceTypeClusterIP,
+									Port: pointer.Int32Ptr(8443),
 								},
 							},
 						},
@@ -1716,34 +2214,45 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 					addNodeWithRoleToTracker("worker", kubeAPIClient)
 				})
 
-				it("starts the impersonator, generates a valid cert for the specified hostname, starts a loadbalancer", func() {
+				it("creates the clusterip with the custom port, then advertises it in the endpoint", func() {
+					const fakeIP = "127.0.0.123"
 					startInformersAndController()
 					r.NoError(runControllerSync())
 					r.Len(kubeAPIClient.Actions(), 3)
 					requireNodesListed(kubeAPIClient.Actions()[0])
-					lbService := requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
-					require.Equal(t, lbService.Annotations, map[string]string{
-						"some-annotation-key":                           "some-annotation-value",
-						"credentialissuer.pinniped.dev/annotation-keys": `["some-annotation-key"]`,
-					})
+					createdService := requireClusterIPWasCreated(kubeAPIClient.Actions()[1])
+					r.Len(createdService.Spec.Ports, 1)
+					r.Equal(int32(8443), createdService.Spec.Ports[0].Port)
+					r.Equal(intstr.FromInt(impersonationProxyPort), createdService.Spec.Ports[0].TargetPort)
 					requireCASecretWasCreated(kubeAPIClient.Actions()[2])
-					requireTLSServerIsRunningWithoutCerts()
-					requireCredentialIssuer(newPendingStrategyWaitingForLB())
-					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
-				})
-			})
+					requireCredentialIssuer(newPendingStrategyWaitingForClusterIP())
 
-			when("the CredentialIssuer has a hostname specified and service type none", func() {
-				const fakeHostname = "fake.example.com"
-				it.Before(func() {
-					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					// Simulate the API server assigning a cluster IP and the informer cache's background update from its watch.
+					createdService = createdService.DeepCopy()
+					createdService.Spec.ClusterIP = fakeIP
+					r.NoError(kubeInformerClient.Tracker().Add(createdService))
+					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+
+					r.NoError(runControllerSync())
+					r.Len(kubeAPIClient.Actions(), 4)
+					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+					requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
+					requireTLSServerIsRunning(ca, fakeIP, map[string]string{fakeIP + httpsPort: testServerAddr()})
+					requireCredentialIssuer(newSuccessStrategy(fakeIP+":8443", ca))
+				})
+			})
+
+			when("a static cluster ip is requested", func() {
+				const requestedIP = "127.0.0.42"
+				it.Before(func() {
+					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 						Spec: v1alpha1.CredentialIssuerSpec{
 							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-								Mode:             v1alpha1.ImpersonationProxyModeEnabled,
-								ExternalEndpoint: fakeHostname,
+								Mode: v1alpha1.ImpersonationProxyModeEnabled,
 								Service: v1alpha1.ImpersonationProxyServiceSpec{
-									Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+									Type:      v1alpha1.ImpersonationProxyServiceTypeClusterIP,
+									ClusterIP: requestedIP,
 								},
 							},
 						},
@@ -1751,54 +2260,87 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 					addNodeWithRoleToTracker("worker", kubeAPIClient)
 				})
 
-				it("starts the impersonator, generates a valid cert for the specified hostname", func() {
-					startInformersAndController()
-					r.NoError(runControllerSync())
-					r.Len(kubeAPIClient.Actions(), 3)
-					requireNodesListed(kubeAPIClient.Actions()[0])
-					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
-					requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
-					// Check that the server is running and that TLS certs that are being served are are for fakeHostname.
-					requireTLSServerIsRunning(ca, fakeHostname, map[string]string{fakeHostname + httpsPort: testServerAddr()})
-					requireCredentialIssuer(newSuccessStrategy(fakeHostname, ca))
-					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+				when("the clusterip does not exist yet", func() {
+					it("creates the clusterip with the requested ip, then advertises it", func() {
+						startInformersAndController()
+						r.NoError(runControllerSync())
+						r.Len(kubeAPIClient.Actions(), 3)
+						requireNodesListed(kubeAPIClient.Actions()[0])
+						createdService := requireClusterIPWasCreated(kubeAPIClient.Actions()[1])
+						r.Equal(requestedIP, createdService.Spec.ClusterIP)
+						requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+
+						// Simulate the informer cache's background update from its watch.
+						addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Services())
+						addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+
+						r.NoError(runControllerSync())
+						r.Len(kubeAPIClient.Actions(), 4)
+						ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+						requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
+						requireTLSSecretHasNames(kubeAPIClient.Actions()[3], []string{requestedIP}, []string{})
+						requireTLSServerIsRunning(ca, requestedIP, map[string]string{requestedIP + httpsPort: testServerAddr()})
+						requireCredentialIssuer(newSuccessStrategy(requestedIP, ca))
+					})
+				})
+
+				when("the clusterip already exists with a different ip", func() {
+					it.Before(func() {
+						addClusterIPServiceToTracker(clusterIPServiceName, "127.0.0.123", kubeInformerClient)
+						addClusterIPServiceToTracker(clusterIPServiceName, "127.0.0.123", kubeAPIClient)
+					})
+
+					it("recreates the clusterip with the requested ip", func() {
+						startInformersAndController()
+						r.NoError(runControllerSync())
+						r.Len(kubeAPIClient.Actions(), 4)
+						requireNodesListed(kubeAPIClient.Actions()[0])
+						requireServiceWasDeleted(kubeAPIClient.Actions()[1], clusterIPServiceName)
+						createdService := requireClusterIPWasCreated(kubeAPIClient.Actions()[2])
+						r.Equal(requestedIP, createdService.Spec.ClusterIP)
+						requireCASecretWasCreated(kubeAPIClient.Actions()[3])
+						// The informer has not seen the recreated Service yet, so no cert is issued for the old IP.
+						requireCredentialIssuer(newPendingStrategyWaitingForClusterIP())
+					})
 				})
 			})
 
-			when("the CredentialIssuer has a hostname specified and service type loadbalancer", func() {
-				const fakeHostname = "fake.example.com"
+			when("a clusterip service exists with dual stack ips", func() {
+				const fakeIP1 = "127.0.0.123"
+				const fakeIP2 = "fd00::5118"
 				it.Before(func() {
 					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 						Spec: v1alpha1.CredentialIssuerSpec{
 							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-								Mode:             v1alpha1.ImpersonationProxyModeEnabled,
-								ExternalEndpoint: fakeHostname,
+								Mode: v1alpha1.ImpersonationProxyModeEnabled,
 								Service: v1alpha1.ImpersonationProxyServiceSpec{
-									Type: v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+									Type: v1alpha1.ImpersonationProxyServiceTypeClusterIP,
 								},
 							},
 						},
 					}, pinnipedInformerClient, pinnipedAPIClient)
 					addNodeWithRoleToTracker("worker", kubeAPIClient)
+					addDualStackClusterIPServiceToTracker(clusterIPServiceName, fakeIP1, fakeIP2, kubeInformerClient)
+					addDualStackClusterIPServiceToTracker(clusterIPServiceName, fakeIP1, fakeIP2, kubeAPIClient)
 				})
 
-				it("starts the impersonator, generates a valid cert for the specified hostname, starts a loadbalancer", func() {
+				it("certs are valid for both ip addresses", func() {
 					startInformersAndController()
 					r.NoError(runControllerSync())
-					r.Len(kubeAPIClient.Actions(), 4)
+					r.Len(kubeAPIClient.Actions(), 3)
 					requireNodesListed(kubeAPIClient.Actions()[0])
-					requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
-					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
-					requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
-					// Check that the server is running and that TLS certs that are being served are are for fakeHostname.
-					requireTLSServerIsRunning(ca, fakeHostname, map[string]string{fakeHostname + httpsPort: testServerAddr()})
-					requireCredentialIssuer(newSuccessStrategy(fakeHostname, ca))
-					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
+					requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
+					requireTLSServerIsRunning(ca, "["+fakeIP2+"]", map[string]string{"[fd00::5118]:443": testServerAddr()})
+					requireTLSServerIsRunning(ca, fakeIP1, map[string]string{fakeIP1 + ":443": testServerAddr()})
+					requireCredentialIssuer(newSuccessStrategy(fakeIP1, ca))
 				})
 			})
 
-			when("the CredentialIssuer has a hostname specified and service type clusterip", func() {
+			when("a clusterip service exists with dual stack ips and ipv6 addresses are preferred", func() {
+				const fakeIP1 = "127.0.0.123"
+				const fakeIP2 = "fd00::5118"
 				it.Before(func() {
 					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
@@ -1806,104 +2348,108 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
 								Mode: v1alpha1.ImpersonationProxyModeEnabled,
 								Service: v1alpha1.ImpersonationProxyServiceSpec{
-									Type: v1alpha1.ImpersonationProxyServiceTypeClusterIP,
+									Type:                 v1alpha1.ImpersonationProxyServiceTypeClusterIP,
+									PreferredAddressType: v1alpha1.ImpersonationProxyServiceAddressTypeIPv6,
 								},
 							},
 						},
 					}, pinnipedInformerClient, pinnipedAPIClient)
 					addNodeWithRoleToTracker("worker", kubeAPIClient)
+					addDualStackClusterIPServiceToTracker(clusterIPServiceName, fakeIP1, fakeIP2, kubeInformerClient)
+					addDualStackClusterIPServiceToTracker(clusterIPServiceName, fakeIP1, fakeIP2, kubeAPIClient)
 				})
 
-				it("starts the impersonator and creates a clusterip service", func() {
+				it("advertises the ipv6 address while the certs are still valid for both ip addresses", func() {
 					startInformersAndController()
 					r.NoError(runControllerSync())
 					r.Len(kubeAPIClient.Actions(), 3)
 					requireNodesListed(kubeAPIClient.Actions()[0])
-					requireClusterIPWasCreated(kubeAPIClient.Actions()[1])
-					requireCASecretWasCreated(kubeAPIClient.Actions()[2])
-					// Check that the server is running without certs.
-					requireTLSServerIsRunningWithoutCerts()
-					requireCredentialIssuer(newPendingStrategyWaitingForLB())
-					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
+					requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
+					requireTLSSecretHasNames(kubeAPIClient.Actions()[2], []string{fakeIP1, fakeIP2}, []string{})
+					requireTLSServerIsRunning(ca, "["+fakeIP2+"]", map[string]string{"[fd00::5118]:443": testServerAddr()})
+					requireTLSServerIsRunning(ca, fakeIP1, map[string]string{fakeIP1 + ":443": testServerAddr()})
+					requireCredentialIssuer(newSuccessStrategy("["+fakeIP2+"]", ca))
 				})
 			})
 
-			when("the CredentialIssuer has a endpoint which is an IP address with a port", func() {
-				const fakeIPWithPort = "127.0.0.1:3000"
+			when("the service type is Existing and the existing service is a clusterip", func() {
+				const fakeIP = "127.0.0.123"
+				const existingServiceName = "some-existing-service"
 				it.Before(func() {
 					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 						Spec: v1alpha1.CredentialIssuerSpec{
 							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-								Mode:             v1alpha1.ImpersonationProxyModeEnabled,
-								ExternalEndpoint: fakeIPWithPort,
+								Mode: v1alpha1.ImpersonationProxyModeEnabled,
 								Service: v1alpha1.ImpersonationProxyServiceSpec{
-									Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+									Type:                v1alpha1.ImpersonationProxyServiceTypeExisting,
+									ExistingServiceName: existingServiceName,
 								},
 							},
 						},
 					}, pinnipedInformerClient, pinnipedAPIClient)
 					addNodeWithRoleToTracker("worker", kubeAPIClient)
+					addClusterIPServiceToTracker(existingServiceName, fakeIP, kubeInformerClient)
+					addClusterIPServiceToTracker(existingServiceName, fakeIP, kubeAPIClient)
 				})
 
-				it("starts the impersonator, generates a valid cert for the specified IP address", func() {
+				it("starts the impersonator using the address of the existing service without managing any service", func() {
 					startInformersAndController()
 					r.NoError(runControllerSync())
 					r.Len(kubeAPIClient.Actions(), 3)
 					requireNodesListed(kubeAPIClient.Actions()[0])
 					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
 					requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
-					// Check that the server is running and that TLS certs that are being served are are for fakeIPWithPort.
-					requireTLSServerIsRunning(ca, fakeIPWithPort, map[string]string{fakeIPWithPort: testServerAddr()})
-					requireCredentialIssuer(newSuccessStrategy(fakeIPWithPort, ca))
-					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+					requireTLSServerIsRunning(ca, fakeIP, map[string]string{fakeIP + ":443": testServerAddr()})
+					requireCredentialIssuer(newSuccessStrategy(fakeIP, ca))
 				})
 			})
 
-			when("the CredentialIssuer has a endpoint which is a hostname with a port, service type none", func() {
-				const fakeHostnameWithPort = "fake.example.com:3000"
+			when("the service type is Existing and the existing service is a load balancer with ingress", func() {
+				const fakeIP = "127.0.0.123"
+				const existingServiceName = "some-existing-service"
 				it.Before(func() {
 					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 						Spec: v1alpha1.CredentialIssuerSpec{
 							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-								Mode:             v1alpha1.ImpersonationProxyModeEnabled,
-								ExternalEndpoint: fakeHostnameWithPort,
+								Mode: v1alpha1.ImpersonationProxyModeEnabled,
 								Service: v1alpha1.ImpersonationProxyServiceSpec{
-									Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+									Type:                v1alpha1.ImpersonationProxyServiceTypeExisting,
+									ExistingServiceName: existingServiceName,
 								},
 							},
 						},
 					}, pinnipedInformerClient, pinnipedAPIClient)
 					addNodeWithRoleToTracker("worker", kubeAPIClient)
+					addLoadBalancerServiceWithIngressToTracker(existingServiceName, []corev1.LoadBalancerIngress{{IP: fakeIP}}, kubeInformerClient)
+					addLoadBalancerServiceWithIngressToTracker(existingServiceName, []corev1.LoadBalancerIngress{{IP: fakeIP}}, kubeAPIClient)
 				})
 
-				it("starts the impersonator, generates a valid cert for the specified hostname", func() {
+				it("starts the impersonator using the ingress of the existing service without managing any service", func() {
 					startInformersAndController()
 					r.NoError(runControllerSync())
 					r.Len(kubeAPIClient.Actions(), 3)
 					requireNodesListed(kubeAPIClient.Actions()[0])
 					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
 					requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
-					// Check that the server is running and that TLS certs that are being served are are for fakeHostnameWithPort.
-					requireTLSServerIsRunning(ca, fakeHostnameWithPort, map[string]string{fakeHostnameWithPort: testServerAddr()})
-					requireCredentialIssuer(newSuccessStrategy(fakeHostnameWithPort, ca))
-					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+					requireTLSServerIsRunning(ca, fakeIP, map[string]string{fakeIP + ":443": testServerAddr()})
+					requireCredentialIssuer(newSuccessStrategy(fakeIP, ca))
 				})
 			})
 
-			when("the CredentialIssuer has a endpoint which is a hostname with a port, service type loadbalancer with loadbalancerip", func() {
-				const fakeHostnameWithPort = "fake.example.com:3000"
+			when("the service type is Existing and the existing service does not exist yet", func() {
+				const existingServiceName = "some-existing-service"
 				it.Before(func() {
 					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 						Spec: v1alpha1.CredentialIssuerSpec{
 							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-								Mode:             v1alpha1.ImpersonationProxyModeEnabled,
-								ExternalEndpoint: fakeHostnameWithPort,
+								Mode: v1alpha1.ImpersonationProxyModeEnabled,
 								Service: v1alpha1.ImpersonationProxyServiceSpec{
-									Type:           v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
-									LoadBalancerIP: localhostIP,
+									Type:                v1alpha1.ImpersonationProxyServiceTypeExisting,
+									ExistingServiceName: existingServiceName,
 								},
 							},
 						},
@@ -1911,149 +2457,85 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 					addNodeWithRoleToTracker("worker", kubeAPIClient)
 				})
 
-				it("starts the impersonator, starts the loadbalancer, generates a valid cert for the specified hostname", func() {
+				it("waits for the existing service without creating any service", func() {
 					startInformersAndController()
 					r.NoError(runControllerSync())
-					r.Len(kubeAPIClient.Actions(), 4)
+					requireTLSServerIsRunningWithoutCerts()
+					r.Len(kubeAPIClient.Actions(), 2)
 					requireNodesListed(kubeAPIClient.Actions()[0])
-					lbService := requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
-					require.Equal(t, lbService.Spec.LoadBalancerIP, localhostIP)
-					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
-					requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
-					// Check that the server is running and that TLS certs that are being served are are for fakeHostnameWithPort.
-					requireTLSServerIsRunning(ca, fakeHostnameWithPort, map[string]string{fakeHostnameWithPort: testServerAddr()})
-					requireCredentialIssuer(newSuccessStrategy(fakeHostnameWithPort, ca))
-					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+					requireCASecretWasCreated(kubeAPIClient.Actions()[1])
+					requireCredentialIssuer(newPendingStrategy(`waiting for existing Service "some-existing-service" to exist and to have an IP or hostname`))
 				})
 			})
 
-			when("switching the CredentialIssuer from ip address endpoint to hostname endpoint and back to ip address", func() {
-				const fakeHostname = "fake.example.com"
-				const fakeIP = "127.0.0.42"
-
-				var hostnameConfig = v1alpha1.CredentialIssuerSpec{
-					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-						Mode:             v1alpha1.ImpersonationProxyModeEnabled,
-						ExternalEndpoint: fakeHostname,
-						Service: v1alpha1.ImpersonationProxyServiceSpec{
-							Type: v1alpha1.ImpersonationProxyServiceTypeNone,
-						},
-					},
-				}
-				var ipAddressConfig = v1alpha1.CredentialIssuerSpec{
-					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-						Mode:             v1alpha1.ImpersonationProxyModeEnabled,
-						ExternalEndpoint: fakeIP,
-						Service: v1alpha1.ImpersonationProxyServiceSpec{
-							Type: v1alpha1.ImpersonationProxyServiceTypeNone,
-						},
-					},
-				}
-
+			when("a load balancer and a secret already exists", func() {
+				var caCrt []byte
 				it.Before(func() {
 					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
-						Spec:       ipAddressConfig,
+						Spec: v1alpha1.CredentialIssuerSpec{
+							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+								Mode: v1alpha1.ImpersonationProxyModeEnabled,
+							},
+						},
 					}, pinnipedInformerClient, pinnipedAPIClient)
 					addNodeWithRoleToTracker("worker", kubeAPIClient)
+					ca := newCA()
+					caSecret := newActualCASecret(ca, caSecretName)
+					caCrt = caSecret.Data["ca.crt"]
+					addSecretToTrackers(caSecret, kubeAPIClient, kubeInformerClient)
+					tlsSecret := newActualTLSSecret(ca, tlsSecretName, localhostIP)
+					addSecretToTrackers(tlsSecret, kubeAPIClient, kubeInformerClient)
+					addLoadBalancerServiceWithIngressToTracker(loadBalancerServiceName, []corev1.LoadBalancerIngress{{IP: localhostIP}}, kubeInformerClient)
+					addLoadBalancerServiceWithIngressToTracker(loadBalancerServiceName, []corev1.LoadBalancerIngress{{IP: localhostIP}}, kubeAPIClient)
 				})
 
-				it("regenerates the cert for the hostname, then regenerates it for the IP again", func() {
+				it("starts the impersonator with the existing tls certs, does not start loadbalancer or make tls secret", func() {
 					startInformersAndController()
 					r.NoError(runControllerSync())
-					r.Len(kubeAPIClient.Actions(), 3)
+					r.Len(kubeAPIClient.Actions(), 1)
 					requireNodesListed(kubeAPIClient.Actions()[0])
-					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
-					requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
-					// Check that the server is running and that TLS certs that are being served are are for fakeIP.
-					requireTLSServerIsRunning(ca, fakeIP, map[string]string{fakeIP + httpsPort: testServerAddr()})
-					requireCredentialIssuer(newSuccessStrategy(fakeIP, ca))
-					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
-
-					// Simulate the informer cache's background update from its watch.
-					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Secrets())
-					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
-
-					// Switch the endpoint config to a hostname.
-					updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, hostnameConfig, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
-
-					r.NoError(runControllerSync())
-					r.Len(kubeAPIClient.Actions(), 5)
-					requireTLSSecretWasDeleted(kubeAPIClient.Actions()[3])
-					requireTLSSecretWasCreated(kubeAPIClient.Actions()[4], ca) // reuses the old CA
-					// Check that the server is running and that TLS certs that are being served are are for fakeHostname.
-					requireTLSServerIsRunning(ca, fakeHostname, map[string]string{fakeHostname + httpsPort: testServerAddr()})
-					requireCredentialIssuer(newSuccessStrategy(fakeHostname, ca))
-					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
-
-					// Simulate the informer cache's background update from its watch.
-					deleteSecretFromTracker(tlsSecretName, kubeInformerClient)
-					waitForObjectToBeDeletedFromInformer(tlsSecretName, kubeInformers.Core().V1().Secrets())
-					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[4], kubeInformers.Core().V1().Secrets())
-
-					// Switch the endpoint config back to an IP.
-					updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, ipAddressConfig, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
-
-					r.NoError(runControllerSync())
-					r.Len(kubeAPIClient.Actions(), 7)
-					requireTLSSecretWasDeleted(kubeAPIClient.Actions()[5])
-					requireTLSSecretWasCreated(kubeAPIClient.Actions()[6], ca) // reuses the old CA again
-					// Check that the server is running and that TLS certs that are being served are are for fakeIP.
-					requireTLSServerIsRunning(ca, fakeIP, map[string]string{fakeIP + httpsPort: testServerAddr()})
-					requireCredentialIssuer(newSuccessStrategy(fakeIP, ca))
+					requireTLSServerIsRunning(caCrt, testServerAddr(), nil)
+					requireCredentialIssuer(newSuccessStrategy(localhostIP, caCrt))
 					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
 				})
 			})
 
-			when("the TLS cert goes missing and needs to be recreated, e.g. when a user manually deleted it", func() {
-				const fakeHostname = "fake.example.com"
+			when("credentialissuer has service type loadbalancer and custom annotations", func() {
 				it.Before(func() {
 					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 						Spec: v1alpha1.CredentialIssuerSpec{
 							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-								Mode:             v1alpha1.ImpersonationProxyModeEnabled,
-								ExternalEndpoint: fakeHostname,
+								Mode: v1alpha1.ImpersonationProxyModeEnabled,
 								Service: v1alpha1.ImpersonationProxyServiceSpec{
-									Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+									Type:        v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+									Annotations: map[string]string{"some-annotation-key": "some-annotation-value"},
 								},
 							},
 						},
 					}, pinnipedInformerClient, pinnipedAPIClient)
 					addNodeWithRoleToTracker("worker", kubeAPIClient)
-					startInformersAndController()
 				})
 
-				it("uses the existing CA cert the make a new TLS cert", func() {
+				it("starts the impersonator, generates a valid cert for the specified hostname, starts a loadbalancer", func() {
+					startInformersAndController()
 					r.NoError(runControllerSync())
 					r.Len(kubeAPIClient.Actions(), 3)
 					requireNodesListed(kubeAPIClient.Actions()[0])
-					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
-					requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
-					// Check that the server is running and that TLS certs that are being served are are for fakeHostname.
-					requireTLSServerIsRunning(ca, fakeHostname, map[string]string{fakeHostname + httpsPort: testServerAddr()})
-					requireCredentialIssuer(newSuccessStrategy(fakeHostname, ca))
-					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
-
-					// Simulate the informer cache's background update from its watch.
-					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Secrets())
-
-					// Delete the TLS Secret that was just created from the Kube API server. Note that we never
-					// simulated it getting added to the informer cache, so we don't need to remove it from there.
-					deleteSecretFromTracker(tlsSecretName, kubeAPIClient)
-
-					// Run again. It should create a new TLS cert using the old CA cert.
-					r.NoError(runControllerSync())
-					r.Len(kubeAPIClient.Actions(), 4)
-					requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
-					// Check that the server is running and that TLS certs that are being served are are for fakeHostname.
-					requireTLSServerIsRunning(ca, fakeHostname, map[string]string{fakeHostname + httpsPort: testServerAddr()})
-					requireCredentialIssuer(newSuccessStrategy(fakeHostname, ca))
+					lbService := requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
+					require.Equal(t, lbService.Annotations, map[string]string{
+						"some-annotation-key":                           "some-annotation-value",
+						"credentialissuer.pinniped.dev/annotation-keys": `["some-annotation-key"]`,
+					})
+					requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+					requireTLSServerIsRunningWithoutCerts()
+					requireCredentialIssuer(newPendingStrategyWaitingForLB())
 					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
 				})
 			})
 
-			when("the CA cert goes missing and needs to be recreated, e.g. when a user manually deleted it", func() {
+			when("the CredentialIssuer has a hostname specified and service type none", func() {
 				const fakeHostname = "fake.example.com"
 				it.Before(func() {
 					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
@@ -2069,10 +2551,10 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 						},
 					}, pinnipedInformerClient, pinnipedAPIClient)
 					addNodeWithRoleToTracker("worker", kubeAPIClient)
-					startInformersAndController()
 				})
 
-				it("makes a new CA cert, deletes the old TLS cert, and makes a new TLS cert using the new CA", func() {
+				it("starts the impersonator, generates a valid cert for the specified hostname", func() {
+					startInformersAndController()
 					r.NoError(runControllerSync())
 					r.Len(kubeAPIClient.Actions(), 3)
 					requireNodesListed(kubeAPIClient.Actions()[0])
@@ -2082,20 +2564,35 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 					requireTLSServerIsRunning(ca, fakeHostname, map[string]string{fakeHostname + httpsPort: testServerAddr()})
 					requireCredentialIssuer(newSuccessStrategy(fakeHostname, ca))
 					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+				})
+			})
 
-					// Simulate the informer cache's background update from its watch.
-					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
-
-					// Delete the CA Secret that was just created from the Kube API server. Note that we never
-					// simulated it getting added to the informer cache, so we don't need to remove it from there.
-					deleteSecretFromTracker(caSecretName, kubeAPIClient)
+			when("the CredentialIssuer has a hostname specified and service type loadbalancer", func() {
+				const fakeHostname = "fake.example.com"
+				it.Before(func() {
+					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+						Spec: v1alpha1.CredentialIssuerSpec{
+							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+								Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+								ExternalEndpoint: fakeHostname,
+								Service: v1alpha1.ImpersonationProxyServiceSpec{
+									Type: v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+								},
+							},
+						},
+					}, pinnipedInformerClient, pinnipedAPIClient)
+					addNodeWithRoleToTracker("worker", kubeAPIClient)
+				})
 
-					// Run again. It should create both a new CA cert and a new TLS cert using the new CA cert.
+				it("starts the impersonator, generates a valid cert for the specified hostname, starts a loadbalancer", func() {
+					startInformersAndController()
 					r.NoError(runControllerSync())
-					r.Len(kubeAPIClient.Actions(), 6)
-					ca = requireCASecretWasCreated(kubeAPIClient.Actions()[3])
-					requireTLSSecretWasDeleted(kubeAPIClient.Actions()[4])
-					requireTLSSecretWasCreated(kubeAPIClient.Actions()[5], ca) // created using the new CA
+					r.Len(kubeAPIClient.Actions(), 4)
+					requireNodesListed(kubeAPIClient.Actions()[0])
+					requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
+					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+					requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
 					// Check that the server is running and that TLS certs that are being served are are for fakeHostname.
 					requireTLSServerIsRunning(ca, fakeHostname, map[string]string{fakeHostname + httpsPort: testServerAddr()})
 					requireCredentialIssuer(newSuccessStrategy(fakeHostname, ca))
@@ -2103,156 +2600,185 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 				})
 			})
 
-			when("the CA cert is overwritten by another valid CA cert", func() {
-				const fakeHostname = "fake.example.com"
-				var caCrt []byte
+			when("the controller is configured to annotate issued secrets", func() {
+				var requireIssuanceAnnotations = func(action coretesting.Action, wantReason string) {
+					var secret *corev1.Secret
+					switch a := action.(type) {
+					case coretesting.CreateAction:
+						secret = a.GetObject().(*corev1.Secret)
+					case coretesting.UpdateAction:
+						secret = a.GetObject().(*corev1.Secret)
+					default:
+						r.FailNow("should have been able to cast this action to CreateAction or UpdateAction", "%v", action)
+					}
+					r.Equal(frozenNow.UTC().Format(time.RFC3339), secret.Annotations["impersonation-proxy.concierge.pinniped.dev/issued-at"])
+					r.Equal(wantReason, secret.Annotations["impersonation-proxy.concierge.pinniped.dev/issuance-reason"])
+				}
+
 				it.Before(func() {
+					annotateIssuedSecrets = true
+					addNodeWithRoleToTracker("worker", kubeAPIClient)
 					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 						Spec: v1alpha1.CredentialIssuerSpec{
 							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
 								Mode:             v1alpha1.ImpersonationProxyModeEnabled,
-								ExternalEndpoint: fakeHostname,
+								ExternalEndpoint: localhostIP,
 								Service: v1alpha1.ImpersonationProxyServiceSpec{
 									Type: v1alpha1.ImpersonationProxyServiceTypeNone,
 								},
 							},
 						},
 					}, pinnipedInformerClient, pinnipedAPIClient)
-					addNodeWithRoleToTracker("worker", kubeAPIClient)
+				})
+
+				it("records when and why the certificates were issued, without reissuing them on the next sync", func() {
 					startInformersAndController()
 					r.NoError(runControllerSync())
 					r.Len(kubeAPIClient.Actions(), 3)
 					requireNodesListed(kubeAPIClient.Actions()[0])
 					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
+					requireIssuanceAnnotations(kubeAPIClient.Actions()[1], "created")
 					requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
-					// Check that the server is running and that TLS certs that are being served are are for fakeHostname.
-					requireTLSServerIsRunning(ca, fakeHostname, map[string]string{fakeHostname + httpsPort: testServerAddr()})
-					requireCredentialIssuer(newSuccessStrategy(fakeHostname, ca))
-					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+					requireIssuanceAnnotations(kubeAPIClient.Actions()[2], "missing")
+					requireTLSServerIsRunning(ca, testServerAddr(), nil)
+					requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
 
-					// Simulate the informer cache's background update from its watch.
+					// The annotations are not part of the desired state, so observing the Secrets changes nothing.
+					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Secrets())
 					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
-
-					// Simulate someone updating the CA Secret out of band, e.g. when a human edits it with kubectl.
-					// Delete the CA Secret that was just created from the Kube API server. Note that we never
-					// simulated it getting added to the informer cache, so we don't need to remove it from there.
-					// Then add a new one. Delete + new = update, since only the final state is observed.
-					deleteSecretFromTracker(caSecretName, kubeAPIClient)
-					anotherCA := newCA()
-					newCASecret := newActualCASecret(anotherCA, caSecretName)
-					caCrt = newCASecret.Data["ca.crt"]
-					addSecretToTrackers(newCASecret, kubeAPIClient)
-					addObjectToKubeInformerAndWait(newCASecret, kubeInformers.Core().V1().Secrets())
+					r.NoError(runControllerSync())
+					r.Len(kubeAPIClient.Actions(), 3)
+					requireTLSServerIsRunning(ca, testServerAddr(), nil)
 				})
 
-				it("deletes the old TLS cert and makes a new TLS cert using the new CA", func() {
-					// Run again. It should use the updated CA cert to create a new TLS cert.
+				it("records the reason when the TLS serving certificate is reissued for a different endpoint", func() {
+					ca := newCA()
+					addSecretToTrackers(newSecretWithData(caSecretName, newCACertSecretData(ca)), kubeAPIClient, kubeInformerClient)
+					addSecretToTrackers(newActualTLSSecret(ca, tlsSecretName, "127.0.0.2"), kubeAPIClient, kubeInformerClient)
+					startInformersAndController()
 					r.NoError(runControllerSync())
-					r.Len(kubeAPIClient.Actions(), 5)
-					requireTLSSecretWasDeleted(kubeAPIClient.Actions()[3])
-					requireTLSSecretWasCreated(kubeAPIClient.Actions()[4], caCrt) // created using the updated CA
-					// Check that the server is running and that TLS certs that are being served are are for fakeHostname.
-					requireTLSServerIsRunning(caCrt, fakeHostname, map[string]string{fakeHostname + httpsPort: testServerAddr()})
-					requireCredentialIssuer(newSuccessStrategy(fakeHostname, caCrt))
-					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+					r.Len(kubeAPIClient.Actions(), 3)
+					requireNodesListed(kubeAPIClient.Actions()[0])
+					requireTLSSecretWasDeleted(kubeAPIClient.Actions()[1])
+					requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca.Bundle())
+					requireIssuanceAnnotations(kubeAPIClient.Actions()[2], "names_changed")
 				})
+			})
 
-				when("deleting the TLS cert due to mismatched CA results in an error", func() {
+			when("the controller is configured to set owner references", func() {
+				const fakeHostname = "fake.example.com"
+				it.Before(func() {
+					setOwnerReferences = true
+					wantOwnerReferences = []metav1.OwnerReference{{
+						APIVersion: "config.concierge.pinniped.dev/v1alpha1",
+						Kind:       "CredentialIssuer",
+						Name:       credentialIssuerResourceName,
+						UID:        "some-credential-issuer-uid",
+					}}
+					addNodeWithRoleToTracker("worker", kubeAPIClient)
+				})
+
+				when("the service type is loadbalancer", func() {
 					it.Before(func() {
-						kubeAPIClient.PrependReactor("delete", "secrets", func(action coretesting.Action) (handled bool, ret runtime.Object, err error) {
-							if action.(coretesting.DeleteAction).GetName() == tlsSecretName {
-								return true, nil, fmt.Errorf("error on tls secret delete")
-							}
-							return false, nil, nil
-						})
+						addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+							ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName, UID: "some-credential-issuer-uid"},
+							Spec: v1alpha1.CredentialIssuerSpec{
+								ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+									Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+									ExternalEndpoint: fakeHostname,
+									Service: v1alpha1.ImpersonationProxyServiceSpec{
+										Type: v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+									},
+								},
+							},
+						}, pinnipedInformerClient, pinnipedAPIClient)
 					})
 
-					it("returns an error", func() {
-						r.Error(runControllerSync(), "error on tls secret delete")
+					it("makes the CredentialIssuer the owner of the created load balancer and secrets", func() {
+						startInformersAndController()
+						r.NoError(runControllerSync())
 						r.Len(kubeAPIClient.Actions(), 4)
-						requireTLSSecretWasDeleted(kubeAPIClient.Actions()[3]) // tried to delete cert but failed
-						requireCredentialIssuer(newErrorStrategy("error on tls secret delete"))
-						requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+						requireNodesListed(kubeAPIClient.Actions()[0])
+						requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
+						ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+						requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
+						requireTLSServerIsRunning(ca, fakeHostname, map[string]string{fakeHostname + httpsPort: testServerAddr()})
+						requireCredentialIssuer(newSuccessStrategy(fakeHostname, ca))
+					})
+				})
+
+				when("the service type is clusterip", func() {
+					it.Before(func() {
+						addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+							ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName, UID: "some-credential-issuer-uid"},
+							Spec: v1alpha1.CredentialIssuerSpec{
+								ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+									Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+									ExternalEndpoint: fakeHostname,
+									Service: v1alpha1.ImpersonationProxyServiceSpec{
+										Type: v1alpha1.ImpersonationProxyServiceTypeClusterIP,
+									},
+								},
+							},
+						}, pinnipedInformerClient, pinnipedAPIClient)
+					})
+
+					it("makes the CredentialIssuer the owner of the created cluster ip service and secrets", func() {
+						startInformersAndController()
+						r.NoError(runControllerSync())
+						r.Len(kubeAPIClient.Actions(), 4)
+						requireNodesListed(kubeAPIClient.Actions()[0])
+						requireClusterIPWasCreated(kubeAPIClient.Actions()[1])
+						ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+						requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
+						requireTLSServerIsRunning(ca, fakeHostname, map[string]string{fakeHostname + httpsPort: testServerAddr()})
+						requireCredentialIssuer(newSuccessStrategy(fakeHostname, ca))
 					})
 				})
 			})
-		})
 
-		when("the configuration switches from enabled to disabled mode", func() {
-			when("service type loadbalancer", func() {
+			when("the CredentialIssuer has a hostname specified and service type clusterip", func() {
 				it.Before(func() {
-					addSecretToTrackers(signingCASecret, kubeInformerClient)
 					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 						Spec: v1alpha1.CredentialIssuerSpec{
 							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
 								Mode: v1alpha1.ImpersonationProxyModeEnabled,
+								Service: v1alpha1.ImpersonationProxyServiceSpec{
+									Type: v1alpha1.ImpersonationProxyServiceTypeClusterIP,
+								},
 							},
 						},
 					}, pinnipedInformerClient, pinnipedAPIClient)
 					addNodeWithRoleToTracker("worker", kubeAPIClient)
 				})
 
-				it("starts the impersonator and loadbalancer, then shuts it down, then starts it again", func() {
+				it("starts the impersonator and creates a clusterip service", func() {
 					startInformersAndController()
-
 					r.NoError(runControllerSync())
-					requireTLSServerIsRunningWithoutCerts()
 					r.Len(kubeAPIClient.Actions(), 3)
 					requireNodesListed(kubeAPIClient.Actions()[0])
-					requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
+					requireClusterIPWasCreated(kubeAPIClient.Actions()[1])
 					requireCASecretWasCreated(kubeAPIClient.Actions()[2])
-					requireCredentialIssuer(newPendingStrategyWaitingForLB())
-					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM) // load when enabled
-
-					// Simulate the informer cache's background update from its watch.
-					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Services())
-					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
-
-					// Update the CredentialIssuer.
-					updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
-						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-							Mode: v1alpha1.ImpersonationProxyModeDisabled,
-						},
-					}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
-
-					r.NoError(runControllerSync())
-					requireTLSServerIsNoLongerRunning()
-					r.Len(kubeAPIClient.Actions(), 4)
-					requireServiceWasDeleted(kubeAPIClient.Actions()[3], loadBalancerServiceName)
-					requireCredentialIssuer(newManuallyDisabledStrategy())
-					requireSigningCertProviderIsEmpty() // only unload when disabled
-
-					deleteServiceFromTracker(loadBalancerServiceName, kubeInformerClient)
-					waitForObjectToBeDeletedFromInformer(loadBalancerServiceName, kubeInformers.Core().V1().Services())
-
-					// Update the CredentialIssuer again.
-					updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
-						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-							Mode: v1alpha1.ImpersonationProxyModeEnabled,
-						},
-					}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
-
-					r.NoError(runControllerSync())
+					// Check that the server is running without certs.
 					requireTLSServerIsRunningWithoutCerts()
-					r.Len(kubeAPIClient.Actions(), 5)
-					requireLoadBalancerWasCreated(kubeAPIClient.Actions()[4])
-					requireCredentialIssuer(newPendingStrategyWaitingForLB())
-					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM) // load again when enabled
+					requireCredentialIssuer(newPendingStrategyWaitingForClusterIP())
+					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
 				})
 			})
 
-			when("service type clusterip", func() {
+			when("the CredentialIssuer has a endpoint which is an IP address with a port", func() {
+				const fakeIPWithPort = "127.0.0.1:3000"
 				it.Before(func() {
-					addSecretToTrackers(signingCASecret, kubeInformerClient)
 					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 						Spec: v1alpha1.CredentialIssuerSpec{
 							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-								Mode: v1alpha1.ImpersonationProxyModeEnabled,
+								Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+								ExternalEndpoint: fakeIPWithPort,
 								Service: v1alpha1.ImpersonationProxyServiceSpec{
-									Type: v1alpha1.ImpersonationProxyServiceTypeClusterIP,
+									Type: v1alpha1.ImpersonationProxyServiceTypeNone,
 								},
 							},
 						},
@@ -2260,68 +2786,29 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 					addNodeWithRoleToTracker("worker", kubeAPIClient)
 				})
 
-				it("starts the impersonator and clusterip, then shuts it down, then starts it again", func() {
+				it("starts the impersonator, generates a valid cert for the specified IP address", func() {
 					startInformersAndController()
-
 					r.NoError(runControllerSync())
-					requireTLSServerIsRunningWithoutCerts()
 					r.Len(kubeAPIClient.Actions(), 3)
 					requireNodesListed(kubeAPIClient.Actions()[0])
-					requireClusterIPWasCreated(kubeAPIClient.Actions()[1])
-					requireCASecretWasCreated(kubeAPIClient.Actions()[2])
-					requireCredentialIssuer(newPendingStrategyWaitingForLB())
-					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM) // load when enabled
-
-					// Simulate the informer cache's background update from its watch.
-					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Services())
-					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
-
-					// Update the CredentialIssuer.
-					updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
-						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-							Mode: v1alpha1.ImpersonationProxyModeDisabled,
-						},
-					}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
-
-					r.NoError(runControllerSync())
-					requireTLSServerIsNoLongerRunning()
-					r.Len(kubeAPIClient.Actions(), 4)
-					requireServiceWasDeleted(kubeAPIClient.Actions()[3], clusterIPServiceName)
-					requireCredentialIssuer(newManuallyDisabledStrategy())
-					requireSigningCertProviderIsEmpty() // only unload when disabled
-
-					deleteServiceFromTracker(clusterIPServiceName, kubeInformerClient)
-					waitForObjectToBeDeletedFromInformer(clusterIPServiceName, kubeInformers.Core().V1().Services())
-
-					// Update the CredentialIssuer again.
-					updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
-						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-							Mode: v1alpha1.ImpersonationProxyModeEnabled,
-							Service: v1alpha1.ImpersonationProxyServiceSpec{
-								Type: v1alpha1.ImpersonationProxyServiceTypeClusterIP,
-							},
-						},
-					}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
-
-					r.NoError(runControllerSync())
-					requireTLSServerIsRunningWithoutCerts()
-					r.Len(kubeAPIClient.Actions(), 5)
-					requireClusterIPWasCreated(kubeAPIClient.Actions()[4])
-					requireCredentialIssuer(newPendingStrategyWaitingForLB())
-					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM) // load again when enabled
+					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
+					requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
+					// Check that the server is running and that TLS certs that are being served are are for fakeIPWithPort.
+					requireTLSServerIsRunning(ca, fakeIPWithPort, map[string]string{fakeIPWithPort: testServerAddr()})
+					requireCredentialIssuer(newSuccessStrategy(fakeIPWithPort, ca))
+					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
 				})
 			})
 
-			when("service type none with a hostname", func() {
-				const fakeHostname = "hello.com"
+			when("the CredentialIssuer has a endpoint which is a hostname with a port, service type none", func() {
+				const fakeHostnameWithPort = "fake.example.com:3000"
 				it.Before(func() {
-					addSecretToTrackers(signingCASecret, kubeInformerClient)
 					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 						Spec: v1alpha1.CredentialIssuerSpec{
 							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
 								Mode:             v1alpha1.ImpersonationProxyModeEnabled,
-								ExternalEndpoint: fakeHostname,
+								ExternalEndpoint: fakeHostnameWithPort,
 								Service: v1alpha1.ImpersonationProxyServiceSpec{
 									Type: v1alpha1.ImpersonationProxyServiceTypeNone,
 								},
@@ -2331,70 +2818,2752 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 					addNodeWithRoleToTracker("worker", kubeAPIClient)
 				})
 
-				it("starts the impersonator, then shuts it down, then starts it again", func() {
+				it("starts the impersonator, generates a valid cert for the specified hostname", func() {
 					startInformersAndController()
-
 					r.NoError(runControllerSync())
 					r.Len(kubeAPIClient.Actions(), 3)
 					requireNodesListed(kubeAPIClient.Actions()[0])
 					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
 					requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
-					requireTLSServerIsRunning(ca, fakeHostname, map[string]string{fakeHostname + httpsPort: testServerAddr()})
-					requireCredentialIssuer(newSuccessStrategy(fakeHostname, ca))
-
-					// load when enabled
+					// Check that the server is running and that TLS certs that are being served are are for fakeHostnameWithPort.
+					requireTLSServerIsRunning(ca, fakeHostnameWithPort, map[string]string{fakeHostnameWithPort: testServerAddr()})
+					requireCredentialIssuer(newSuccessStrategy(fakeHostnameWithPort, ca))
 					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
-					requireTLSSecretProviderHasLoadedCerts()
-
-					// Simulate the informer cache's background update from its watch.
-					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Secrets())
-					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+				})
+			})
 
-					// Update the CredentialIssuer.
-					updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
-						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+			when("the CredentialIssuer has a endpoint which is a hostname with a port, service type loadbalancer with loadbalancerip", func() {
+				const fakeHostnameWithPort = "fake.example.com:3000"
+				it.Before(func() {
+					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+						Spec: v1alpha1.CredentialIssuerSpec{
+							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+								Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+								ExternalEndpoint: fakeHostnameWithPort,
+								Service: v1alpha1.ImpersonationProxyServiceSpec{
+									Type:           v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+									LoadBalancerIP: localhostIP,
+								},
+							},
+						},
+					}, pinnipedInformerClient, pinnipedAPIClient)
+					addNodeWithRoleToTracker("worker", kubeAPIClient)
+				})
+
+				it("starts the impersonator, starts the loadbalancer, generates a valid cert for the specified hostname", func() {
+					startInformersAndController()
+					r.NoError(runControllerSync())
+					r.Len(kubeAPIClient.Actions(), 4)
+					requireNodesListed(kubeAPIClient.Actions()[0])
+					lbService := requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
+					require.Equal(t, lbService.Spec.LoadBalancerIP, localhostIP)
+					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+					requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
+					// Check that the server is running and that TLS certs that are being served are are for fakeHostnameWithPort.
+					requireTLSServerIsRunning(ca, fakeHostnameWithPort, map[string]string{fakeHostnameWithPort: testServerAddr()})
+					requireCredentialIssuer(newSuccessStrategy(fakeHostnameWithPort, ca))
+					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+				})
+			})
+
+			when("switching the CredentialIssuer from ip address endpoint to hostname endpoint and back to ip address", func() {
+				const fakeHostname = "fake.example.com"
+				const fakeIP = "127.0.0.42"
+
+				var hostnameConfig = v1alpha1.CredentialIssuerSpec{
+					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+						Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+						ExternalEndpoint: fakeHostname,
+						Service: v1alpha1.ImpersonationProxyServiceSpec{
+							Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+						},
+					},
+				}
+				var ipAddressConfig = v1alpha1.CredentialIssuerSpec{
+					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+						Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+						ExternalEndpoint: fakeIP,
+						Service: v1alpha1.ImpersonationProxyServiceSpec{
+							Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+						},
+					},
+				}
+
+				it.Before(func() {
+					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+						Spec:       ipAddressConfig,
+					}, pinnipedInformerClient, pinnipedAPIClient)
+					addNodeWithRoleToTracker("worker", kubeAPIClient)
+				})
+
+				it("regenerates the cert for the hostname, then regenerates it for the IP again", func() {
+					startInformersAndController()
+					r.NoError(runControllerSync())
+					r.Len(kubeAPIClient.Actions(), 3)
+					requireNodesListed(kubeAPIClient.Actions()[0])
+					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
+					requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
+					// Check that the server is running and that TLS certs that are being served are are for fakeIP.
+					requireTLSServerIsRunning(ca, fakeIP, map[string]string{fakeIP + httpsPort: testServerAddr()})
+					requireCredentialIssuer(newSuccessStrategy(fakeIP, ca))
+					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+					// Simulate the informer cache's background update from its watch.
+					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Secrets())
+					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+
+					// Switch the endpoint config to a hostname.
+					updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, hostnameConfig, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+
+					r.NoError(runControllerSync())
+					r.Len(kubeAPIClient.Actions(), 5)
+					requireTLSSecretWasDeleted(kubeAPIClient.Actions()[3])
+					requireTLSSecretWasCreated(kubeAPIClient.Actions()[4], ca) // reuses the old CA
+					// Check that the server is running and that TLS certs that are being served are are for fakeHostname.
+					requireTLSServerIsRunning(ca, fakeHostname, map[string]string{fakeHostname + httpsPort: testServerAddr()})
+					requireCredentialIssuer(newSuccessStrategy(fakeHostname, ca))
+					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+					// Simulate the informer cache's background update from its watch.
+					deleteSecretFromTracker(tlsSecretName, kubeInformerClient)
+					waitForObjectToBeDeletedFromInformer(tlsSecretName, kubeInformers.Core().V1().Secrets())
+					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[4], kubeInformers.Core().V1().Secrets())
+
+					// Switch the endpoint config back to an IP.
+					updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, ipAddressConfig, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+
+					r.NoError(runControllerSync())
+					r.Len(kubeAPIClient.Actions(), 7)
+					requireTLSSecretWasDeleted(kubeAPIClient.Actions()[5])
+					requireTLSSecretWasCreated(kubeAPIClient.Actions()[6], ca) // reuses the old CA again
+					// Check that the server is running and that TLS certs that are being served are are for fakeIP.
+					requireTLSServerIsRunning(ca, fakeIP, map[string]string{fakeIP + httpsPort: testServerAddr()})
+					requireCredentialIssuer(newSuccessStrategy(fakeIP, ca))
+					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+				})
+			})
+
+			when("the TLS cert goes missing and needs to be recreated, e.g. when a user manually deleted it", func() {
+				const fakeHostname = "fake.example.com"
+				it.Before(func() {
+					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+						Spec: v1alpha1.CredentialIssuerSpec{
+							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+								Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+								ExternalEndpoint: fakeHostname,
+								Service: v1alpha1.ImpersonationProxyServiceSpec{
+									Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+								},
+							},
+						},
+					}, pinnipedInformerClient, pinnipedAPIClient)
+					addNodeWithRoleToTracker("worker", kubeAPIClient)
+					startInformersAndController()
+				})
+
+				it("uses the existing CA cert the make a new TLS cert", func() {
+					r.NoError(runControllerSync())
+					r.Len(kubeAPIClient.Actions(), 3)
+					requireNodesListed(kubeAPIClient.Actions()[0])
+					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
+					requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
+					// Check that the server is running and that TLS certs that are being served are are for fakeHostname.
+					requireTLSServerIsRunning(ca, fakeHostname, map[string]string{fakeHostname + httpsPort: testServerAddr()})
+					requireCredentialIssuer(newSuccessStrategy(fakeHostname, ca))
+					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+					// Simulate the informer cache's background update from its watch.
+					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Secrets())
+
+					// Delete the TLS Secret that was just created from the Kube API server. Note that we never
+					// simulated it getting added to the informer cache, so we don't need to remove it from there.
+					deleteSecretFromTracker(tlsSecretName, kubeAPIClient)
+
+					// Run again. It should create a new TLS cert using the old CA cert.
+					r.NoError(runControllerSync())
+					r.Len(kubeAPIClient.Actions(), 4)
+					requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
+					// Check that the server is running and that TLS certs that are being served are are for fakeHostname.
+					requireTLSServerIsRunning(ca, fakeHostname, map[string]string{fakeHostname + httpsPort: testServerAddr()})
+					requireCredentialIssuer(newSuccessStrategy(fakeHostname, ca))
+					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+				})
+			})
+
+			when("the CA cert goes missing and needs to be recreated, e.g. when a user manually deleted it", func() {
+				const fakeHostname = "fake.example.com"
+				it.Before(func() {
+					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+						Spec: v1alpha1.CredentialIssuerSpec{
+							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+								Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+								ExternalEndpoint: fakeHostname,
+								Service: v1alpha1.ImpersonationProxyServiceSpec{
+									Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+								},
+							},
+						},
+					}, pinnipedInformerClient, pinnipedAPIClient)
+					addNodeWithRoleToTracker("worker", kubeAPIClient)
+					startInformersAndController()
+				})
+
+				it("makes a new CA cert, deletes the old TLS cert, and makes a new TLS cert using the new CA", func() {
+					r.NoError(runControllerSync())
+					r.Len(kubeAPIClient.Actions(), 3)
+					requireNodesListed(kubeAPIClient.Actions()[0])
+					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
+					requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
+					// Check that the server is running and that TLS certs that are being served are are for fakeHostname.
+					requireTLSServerIsRunning(ca, fakeHostname, map[string]string{fakeHostname + httpsPort: testServerAddr()})
+					requireCredentialIssuer(newSuccessStrategy(fakeHostname, ca))
+					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+					// Simulate the informer cache's background update from its watch.
+					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+
+					// Delete the CA Secret that was just created from the Kube API server. Note that we never
+					// simulated it getting added to the informer cache, so we don't need to remove it from there.
+					deleteSecretFromTracker(caSecretName, kubeAPIClient)
+
+					// Run again. It should create both a new CA cert and a new TLS cert using the new CA cert.
+					r.NoError(runControllerSync())
+					r.Len(kubeAPIClient.Actions(), 6)
+					ca = requireCASecretWasCreated(kubeAPIClient.Actions()[3])
+					requireTLSSecretWasDeleted(kubeAPIClient.Actions()[4])
+					requireTLSSecretWasCreated(kubeAPIClient.Actions()[5], ca) // created using the new CA
+					// Check that the server is running and that TLS certs that are being served are are for fakeHostname.
+					requireTLSServerIsRunning(ca, fakeHostname, map[string]string{fakeHostname + httpsPort: testServerAddr()})
+					requireCredentialIssuer(newSuccessStrategy(fakeHostname, ca))
+					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+				})
+			})
+
+			when("the CA cert is overwritten by another valid CA cert", func() {
+				const fakeHostname = "fake.example.com"
+				var caCrt []byte
+				it.Before(func() {
+					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+						Spec: v1alpha1.CredentialIssuerSpec{
+							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+								Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+								ExternalEndpoint: fakeHostname,
+								Service: v1alpha1.ImpersonationProxyServiceSpec{
+									Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+								},
+							},
+						},
+					}, pinnipedInformerClient, pinnipedAPIClient)
+					addNodeWithRoleToTracker("worker", kubeAPIClient)
+					startInformersAndController()
+					r.NoError(runControllerSync())
+					r.Len(kubeAPIClient.Actions(), 3)
+					requireNodesListed(kubeAPIClient.Actions()[0])
+					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
+					requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
+					// Check that the server is running and that TLS certs that are being served are are for fakeHostname.
+					requireTLSServerIsRunning(ca, fakeHostname, map[string]string{fakeHostname + httpsPort: testServerAddr()})
+					requireCredentialIssuer(newSuccessStrategy(fakeHostname, ca))
+					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+					// Simulate the informer cache's background update from its watch.
+					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+
+					// Simulate someone updating the CA Secret out of band, e.g. when a human edits it with kubectl.
+					// Delete the CA Secret that was just created from the Kube API server. Note that we never
+					// simulated it getting added to the informer cache, so we don't need to remove it from there.
+					// Then add a new one. Delete + new = update, since only the final state is observed.
+					deleteSecretFromTracker(caSecretName, kubeAPIClient)
+					anotherCA := newCA()
+					newCASecret := newActualCASecret(anotherCA, caSecretName)
+					caCrt = newCASecret.Data["ca.crt"]
+					addSecretToTrackers(newCASecret, kubeAPIClient)
+					addObjectToKubeInformerAndWait(newCASecret, kubeInformers.Core().V1().Secrets())
+				})
+
+				it("deletes the old TLS cert and makes a new TLS cert using the new CA", func() {
+					// Run again. It should use the updated CA cert to create a new TLS cert.
+					r.NoError(runControllerSync())
+					r.Len(kubeAPIClient.Actions(), 5)
+					requireTLSSecretWasDeleted(kubeAPIClient.Actions()[3])
+					requireTLSSecretWasCreated(kubeAPIClient.Actions()[4], caCrt) // created using the updated CA
+					// Check that the server is running and that TLS certs that are being served are are for fakeHostname.
+					requireTLSServerIsRunning(caCrt, fakeHostname, map[string]string{fakeHostname + httpsPort: testServerAddr()})
+					requireCredentialIssuer(newSuccessStrategy(fakeHostname, caCrt))
+					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+				})
+
+				when("deleting the TLS cert due to mismatched CA results in an error", func() {
+					it.Before(func() {
+						kubeAPIClient.PrependReactor("delete", "secrets", func(action coretesting.Action) (handled bool, ret runtime.Object, err error) {
+							if action.(coretesting.DeleteAction).GetName() == tlsSecretName {
+								return true, nil, fmt.Errorf("error on tls secret delete")
+							}
+							return false, nil, nil
+						})
+					})
+
+					it("returns an error", func() {
+						r.Error(runControllerSync(), "error on tls secret delete")
+						r.Len(kubeAPIClient.Actions(), 4)
+						requireTLSSecretWasDeleted(kubeAPIClient.Actions()[3]) // tried to delete cert but failed
+						requireCredentialIssuer(newErrorStrategy(v1alpha1.SecretErrorStrategyReason, "error on tls secret delete"))
+						requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+					})
+				})
+			})
+
+			when("the CA cert is overwritten by a cert which must not be trusted", func() {
+				const fakeHostname = "fake.example.com"
+
+				var newSelfSignedCASecretData = func(template *x509.Certificate) map[string][]byte {
+					key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+					r.NoError(err)
+					certDER, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+					r.NoError(err)
+					keyDER, err := x509.MarshalECPrivateKey(key)
+					r.NoError(err)
+					return map[string][]byte{
+						"ca.crt": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}),
+						"ca.key": pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+					}
+				}
+
+				var createdCASecret *corev1.Secret
+
+				var overwriteCASecret = func(data map[string][]byte) {
+					// Simulate someone updating the CA Secret out of band, e.g. when a human edits it with kubectl,
+					// which keeps the annotations of the generated CA Secret.
+					deleteSecretFromTracker(caSecretName, kubeAPIClient)
+					newCASecret := newSecretWithData(caSecretName, data)
+					newCASecret.Annotations = createdCASecret.Annotations
+					addSecretToTrackers(newCASecret, kubeAPIClient)
+					addObjectToKubeInformerAndWait(newCASecret, kubeInformers.Core().V1().Secrets())
+				}
+
+				it.Before(func() {
+					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+						Spec: v1alpha1.CredentialIssuerSpec{
+							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+								Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+								ExternalEndpoint: fakeHostname,
+								Service: v1alpha1.ImpersonationProxyServiceSpec{
+									Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+								},
+							},
+						},
+					}, pinnipedInformerClient, pinnipedAPIClient)
+					addNodeWithRoleToTracker("worker", kubeAPIClient)
+					startInformersAndController()
+					r.NoError(runControllerSync())
+					r.Len(kubeAPIClient.Actions(), 3)
+					requireNodesListed(kubeAPIClient.Actions()[0])
+					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
+					requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
+					requireCredentialIssuer(newSuccessStrategy(fakeHostname, ca))
+					createdCASecret = kubeAPIClient.Actions()[1].(coretesting.CreateAction).GetObject().(*corev1.Secret)
+
+					// Simulate the informer cache's background update from its watch.
+					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+				})
+
+				it("returns an error when the new CA cert has expired", func() {
+					notAfter := frozenNow.Add(-time.Hour).Truncate(time.Second)
+					overwriteCASecret(newSelfSignedCASecretData(&x509.Certificate{
+						SerialNumber:          big.NewInt(1),
+						Subject:               pkix.Name{CommonName: "expired CA"},
+						NotBefore:             frozenNow.Add(-48 * time.Hour),
+						NotAfter:              notAfter,
+						IsCA:                  true,
+						BasicConstraintsValid: true,
+						KeyUsage:              x509.KeyUsageCertSign,
+					}))
+
+					errString := "could not load CA: certificate expired at " + notAfter.UTC().Format(time.RFC3339)
+					r.EqualError(runControllerSync(), errString)
+					requireCredentialIssuer(newErrorStrategy(v1alpha1.CAErrorStrategyReason, errString))
+					r.Len(kubeAPIClient.Actions(), 3) // no TLS cert was issued from the expired CA
+				})
+
+				it("returns an error when the new cert is not a CA cert", func() {
+					overwriteCASecret(newSelfSignedCASecretData(&x509.Certificate{
+						SerialNumber:          big.NewInt(1),
+						Subject:               pkix.Name{CommonName: "leaf cert"},
+						NotBefore:             frozenNow.Add(-time.Hour),
+						NotAfter:              frozenNow.Add(time.Hour),
+						IsCA:                  false,
+						BasicConstraintsValid: true,
+						KeyUsage:              x509.KeyUsageDigitalSignature,
+					}))
+
+					errString := "invalid CA certificate: passed in key pair is not a CA"
+					r.EqualError(runControllerSync(), errString)
+					requireCredentialIssuer(newErrorStrategy(v1alpha1.CAErrorStrategyReason, errString))
+					r.Len(kubeAPIClient.Actions(), 3) // no TLS cert was issued from the leaf cert
+				})
+
+				it("returns an error when the new cert is not a CA cert and has no key usage", func() {
+					overwriteCASecret(newSelfSignedCASecretData(&x509.Certificate{
+						SerialNumber: big.NewInt(1),
+						Subject:      pkix.Name{CommonName: "leaf cert"},
+						NotBefore:    frozenNow.Add(-time.Hour),
+						NotAfter:     frozenNow.Add(time.Hour),
+					}))
+
+					errString := "invalid CA certificate: passed in key pair is not a CA"
+					r.EqualError(runControllerSync(), errString)
+					requireCredentialIssuer(newErrorStrategy(v1alpha1.CAErrorStrategyReason, errString))
+					r.Len(kubeAPIClient.Actions(), 3) // no TLS cert was issued from the leaf cert
+				})
+
+				it("returns an error when the new CA cert's key usage does not allow signing certificates", func() {
+					overwriteCASecret(newSelfSignedCASecretData(&x509.Certificate{
+						SerialNumber:          big.NewInt(1),
+						Subject:               pkix.Name{CommonName: "CA without key usage"},
+						NotBefore:             frozenNow.Add(-time.Hour),
+						NotAfter:              frozenNow.Add(time.Hour),
+						IsCA:                  true,
+						BasicConstraintsValid: true,
+					}))
+
+					errString := "could not load CA: certificate key usage does not allow signing certificates"
+					r.EqualError(runControllerSync(), errString)
+					requireCredentialIssuer(newErrorStrategy(v1alpha1.CAErrorStrategyReason, errString))
+					r.Len(kubeAPIClient.Actions(), 3) // no TLS cert was issued from the CA
+				})
+			})
+		})
+
+		when("the configuration switches from enabled to disabled mode", func() {
+			when("service type loadbalancer", func() {
+				it.Before(func() {
+					addSecretToTrackers(signingCASecret, kubeInformerClient)
+					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+						Spec: v1alpha1.CredentialIssuerSpec{
+							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+								Mode: v1alpha1.ImpersonationProxyModeEnabled,
+							},
+						},
+					}, pinnipedInformerClient, pinnipedAPIClient)
+					addNodeWithRoleToTracker("worker", kubeAPIClient)
+				})
+
+				it("starts the impersonator and loadbalancer, then shuts it down, then starts it again", func() {
+					startInformersAndController()
+
+					r.NoError(runControllerSync())
+					requireTLSServerIsRunningWithoutCerts()
+					r.Len(kubeAPIClient.Actions(), 3)
+					requireNodesListed(kubeAPIClient.Actions()[0])
+					requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
+					requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+					requireCredentialIssuer(newPendingStrategyWaitingForLB())
+					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM) // load when enabled
+
+					// Simulate the informer cache's background update from its watch.
+					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Services())
+					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+
+					// Update the CredentialIssuer.
+					updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode: v1alpha1.ImpersonationProxyModeDisabled,
+						},
+					}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+
+					r.NoError(runControllerSync())
+					requireTLSServerIsNoLongerRunning()
+					r.Len(kubeAPIClient.Actions(), 4)
+					requireServiceWasDeleted(kubeAPIClient.Actions()[3], loadBalancerServiceName)
+					requireCredentialIssuer(newManuallyDisabledStrategy())
+					requireSigningCertProviderIsEmpty() // only unload when disabled
+
+					deleteServiceFromTracker(loadBalancerServiceName, kubeInformerClient)
+					waitForObjectToBeDeletedFromInformer(loadBalancerServiceName, kubeInformers.Core().V1().Services())
+
+					// Update the CredentialIssuer again.
+					updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode: v1alpha1.ImpersonationProxyModeEnabled,
+						},
+					}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+
+					r.NoError(runControllerSync())
+					requireTLSServerIsRunningWithoutCerts()
+					r.Len(kubeAPIClient.Actions(), 5)
+					requireLoadBalancerWasCreated(kubeAPIClient.Actions()[4])
+					requireCredentialIssuer(newPendingStrategyWaitingForLB())
+					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM) // load again when enabled
+				})
+			})
+
+			when("service type clusterip", func() {
+				it.Before(func() {
+					addSecretToTrackers(signingCASecret, kubeInformerClient)
+					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+						Spec: v1alpha1.CredentialIssuerSpec{
+							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+								Mode: v1alpha1.ImpersonationProxyModeEnabled,
+								Service: v1alpha1.ImpersonationProxyServiceSpec{
+									Type: v1alpha1.ImpersonationProxyServiceTypeClusterIP,
+								},
+							},
+						},
+					}, pinnipedInformerClient, pinnipedAPIClient)
+					addNodeWithRoleToTracker("worker", kubeAPIClient)
+				})
+
+				it("starts the impersonator and clusterip, then shuts it down, then starts it again", func() {
+					startInformersAndController()
+
+					r.NoError(runControllerSync())
+					requireTLSServerIsRunningWithoutCerts()
+					r.Len(kubeAPIClient.Actions(), 3)
+					requireNodesListed(kubeAPIClient.Actions()[0])
+					requireClusterIPWasCreated(kubeAPIClient.Actions()[1])
+					requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+					requireCredentialIssuer(newPendingStrategyWaitingForClusterIP())
+					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM) // load when enabled
+
+					// Simulate the informer cache's background update from its watch.
+					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Services())
+					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+
+					// Update the CredentialIssuer.
+					updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode: v1alpha1.ImpersonationProxyModeDisabled,
+						},
+					}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+
+					r.NoError(runControllerSync())
+					requireTLSServerIsNoLongerRunning()
+					r.Len(kubeAPIClient.Actions(), 4)
+					requireServiceWasDeleted(kubeAPIClient.Actions()[3], clusterIPServiceName)
+					requireCredentialIssuer(newManuallyDisabledStrategy())
+					requireSigningCertProviderIsEmpty() // only unload when disabled
+
+					deleteServiceFromTracker(clusterIPServiceName, kubeInformerClient)
+					waitForObjectToBeDeletedFromInformer(clusterIPServiceName, kubeInformers.Core().V1().Services())
+
+					// Update the CredentialIssuer again.
+					updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode: v1alpha1.ImpersonationProxyModeEnabled,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type: v1alpha1.ImpersonationProxyServiceTypeClusterIP,
+							},
+						},
+					}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+
+					r.NoError(runControllerSync())
+					requireTLSServerIsRunningWithoutCerts()
+					r.Len(kubeAPIClient.Actions(), 5)
+					requireClusterIPWasCreated(kubeAPIClient.Actions()[4])
+					requireCredentialIssuer(newPendingStrategyWaitingForClusterIP())
+					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM) // load again when enabled
+				})
+			})
+
+			when("service type none with a hostname", func() {
+				const fakeHostname = "hello.com"
+				it.Before(func() {
+					addSecretToTrackers(signingCASecret, kubeInformerClient)
+					addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+						ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+						Spec: v1alpha1.CredentialIssuerSpec{
+							ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+								Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+								ExternalEndpoint: fakeHostname,
+								Service: v1alpha1.ImpersonationProxyServiceSpec{
+									Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+								},
+							},
+						},
+					}, pinnipedInformerClient, pinnipedAPIClient)
+					addNodeWithRoleToTracker("worker", kubeAPIClient)
+				})
+
+				it("starts the impersonator, then shuts it down, then starts it again", func() {
+					startInformersAndController()
+
+					r.NoError(runControllerSync())
+					r.Len(kubeAPIClient.Actions(), 3)
+					requireNodesListed(kubeAPIClient.Actions()[0])
+					ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
+					requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
+					requireTLSServerIsRunning(ca, fakeHostname, map[string]string{fakeHostname + httpsPort: testServerAddr()})
+					requireCredentialIssuer(newSuccessStrategy(fakeHostname, ca))
+
+					// load when enabled
+					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+					requireTLSSecretProviderHasLoadedCerts()
+
+					// Simulate the informer cache's background update from its watch.
+					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Secrets())
+					addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+
+					// Update the CredentialIssuer.
+					updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
 							Mode: v1alpha1.ImpersonationProxyModeDisabled,
 						},
-					}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+					}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+
+					r.NoError(runControllerSync())
+					requireTLSServerIsNoLongerRunning()
+					r.Len(kubeAPIClient.Actions(), 4)
+					requireTLSSecretWasDeleted(kubeAPIClient.Actions()[3])
+					requireCredentialIssuer(newManuallyDisabledStrategy())
+
+					// only unload when disabled
+					requireSigningCertProviderIsEmpty()
+					requireTLSSecretProviderIsEmpty()
+
+					deleteSecretFromTracker(tlsSecretName, kubeInformerClient)
+					waitForObjectToBeDeletedFromInformer(tlsSecretName, kubeInformers.Core().V1().Secrets())
+
+					// Update the CredentialIssuer again.
+					updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+							ExternalEndpoint: fakeHostname,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+							},
+						},
+					}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+
+					r.NoError(runControllerSync())
+					requireTLSServerIsRunning(ca, fakeHostname, map[string]string{fakeHostname + httpsPort: testServerAddr()})
+					r.Len(kubeAPIClient.Actions(), 5)
+					requireTLSSecretWasCreated(kubeAPIClient.Actions()[4], ca)
+					requireCredentialIssuer(newSuccessStrategy(fakeHostname, ca))
+
+					// load again when enabled
+					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+					requireTLSSecretProviderHasLoadedCerts()
+				})
+			})
+		})
+
+		when("the endpoint and mode switch from specified with no service, to not specified, to specified again", func() {
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+							ExternalEndpoint: localhostIP,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+							},
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+			})
+
+			it("doesn't create, then creates, then deletes the load balancer", func() {
+				startInformersAndController()
+
+				// Should have started in "enabled" mode with an "endpoint", so no load balancer is needed.
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 3)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1]) // created immediately because "endpoint" was specified
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+				// Simulate the informer cache's background update from its watch.
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Secrets())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+
+				// Switch to "enabled" mode without an "endpoint", so a load balancer is needed now.
+				updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
+					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+						Mode: v1alpha1.ImpersonationProxyModeEnabled,
+					},
+				}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 5)
+				requireLoadBalancerWasCreated(kubeAPIClient.Actions()[3])
+				requireTLSSecretWasDeleted(kubeAPIClient.Actions()[4]) // the Secret was deleted because it contained a cert with the wrong IP
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)   // serving certificate is not unloaded in this case
+				requireCredentialIssuer(newPendingStrategyWaitingForLB())
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+				// Simulate the informer cache's background update from its watch.
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[3], kubeInformers.Core().V1().Services())
+				deleteSecretFromTracker(tlsSecretName, kubeInformerClient)
+				waitForObjectToBeDeletedFromInformer(tlsSecretName, kubeInformers.Core().V1().Secrets())
+
+				// The controller should be waiting for the load balancer's ingress to become available.
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 5)                    // no new actions while it is waiting for the load balancer's ingress
+				requireTLSServerIsRunning(ca, testServerAddr(), nil) // serving certificate is not unloaded in this case
+				requireCredentialIssuer(newPendingStrategyWaitingForLB())
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+				// Update the ingress of the LB in the informer's client and run Sync again.
+				fakeIP := "127.0.0.123"
+				updateLoadBalancerServiceInInformerAndWait(loadBalancerServiceName, []corev1.LoadBalancerIngress{{IP: fakeIP}}, kubeInformers.Core().V1().Services())
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 6)
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[5], ca) // reuses the existing CA
+				// Check that the server is running and that TLS certs that are being served are are for fakeIP.
+				requireTLSServerIsRunning(ca, fakeIP, map[string]string{fakeIP + httpsPort: testServerAddr()})
+				requireCredentialIssuer(newSuccessStrategy(fakeIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+				// Simulate the informer cache's background update from its watch.
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[5], kubeInformers.Core().V1().Secrets())
+
+				// Now switch back to having the "endpoint" specified and explicitly saying that we don't want the load balancer service.
+				updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
+					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+						Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+						ExternalEndpoint: localhostIP,
+						Service: v1alpha1.ImpersonationProxyServiceSpec{
+							Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+						},
+					},
+				}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 9)
+				requireServiceWasDeleted(kubeAPIClient.Actions()[6], loadBalancerServiceName)
+				requireTLSSecretWasDeleted(kubeAPIClient.Actions()[7])
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[8], ca) // recreated because the endpoint was updated, reused the old CA
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+			})
+		})
+
+		when("requesting a load balancer via CredentialIssuer, then updating the annotations", func() {
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+							ExternalEndpoint: localhostIP,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type: v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+							},
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+			})
+
+			it("creates the load balancer without annotations, then adds them", func() {
+				startInformersAndController()
+
+				// Should have started in "enabled" mode with service type load balancer, so one is created.
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				lbService := requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
+				require.Equal(t, map[string]string(nil), lbService.Annotations) // there should be no annotations at first
+				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+				// Simulate another actor in the system, like a human user or a non-Pinniped controller,
+				// updating the new Service's annotations. The map was nil, so we can overwrite the whole thing,
+				lbService.Annotations = map[string]string{
+					"annotation-from-unrelated-controller-key": "annotation-from-unrelated-controller-val",
+					"my-annotation-key":                        "my-annotation-from-unrelated-controller-val",
+				}
+
+				// Simulate the informer cache's background update from its watch.
+				addObjectToKubeInformerAndWait(lbService, kubeInformers.Core().V1().Services())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[3], kubeInformers.Core().V1().Secrets())
+
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4) // no new actions because the controller decides there is nothing to update on the Service
+
+				// Add annotations to the CredentialIssuer spec.
+				credentialIssuerAnnotations := map[string]string{"my-annotation-key": "my-annotation-val"}
+				updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
+					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+						Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+						ExternalEndpoint: localhostIP,
+						Service: v1alpha1.ImpersonationProxyServiceSpec{
+							Type:        v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+							Annotations: credentialIssuerAnnotations,
+						},
+					},
+				}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 5) // one more item to update the loadbalancer
+				lbService = requireLoadBalancerWasUpdated(kubeAPIClient.Actions()[4])
+				require.Equal(t, map[string]string{
+					// Now the CredentialIssuer annotations should be merged on the load balancer.
+					// In the unlikely case where keys conflict, the CredentialIssuer value overwrites the other value.
+					// Otherwise the annotations from the other actor should not be modified.
+					"annotation-from-unrelated-controller-key":      "annotation-from-unrelated-controller-val",
+					"my-annotation-key":                             "my-annotation-val",
+					"credentialissuer.pinniped.dev/annotation-keys": `["my-annotation-key"]`,
+				}, lbService.Annotations)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+			})
+		})
+
+		when("requesting a cluster ip via CredentialIssuer, then updating the annotations", func() {
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+							ExternalEndpoint: localhostIP,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type: v1alpha1.ImpersonationProxyServiceTypeClusterIP,
+							},
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+			})
+
+			it("creates the cluster ip without annotations, then adds them", func() {
+				startInformersAndController()
+
+				// Should have started in "enabled" mode with service type load balancer, so one is created.
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				clusterIPService := requireClusterIPWasCreated(kubeAPIClient.Actions()[1])
+				require.Equal(t, map[string]string(nil), clusterIPService.Annotations) // there should be no annotations at first
+				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+				// Simulate another actor in the system, like a human user or a non-Pinniped controller,
+				// updating the new Service's annotations.
+				clusterIPService.Annotations = map[string]string{
+					"annotation-from-unrelated-controller-key": "annotation-from-unrelated-controller-val",
+					"my-annotation-key":                        "my-annotation-from-unrelated-controller-val",
+				}
+
+				// Simulate the informer cache's background update from its watch.
+				addObjectToKubeInformerAndWait(clusterIPService, kubeInformers.Core().V1().Services())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[3], kubeInformers.Core().V1().Secrets())
+
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4) // no new actions because the controller decides there is nothing to update on the Service
+
+				// Add annotations to the CredentialIssuer spec.
+				credentialIssuerAnnotations := map[string]string{"my-annotation-key": "my-annotation-val"}
+				updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
+					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+						Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+						ExternalEndpoint: localhostIP,
+						Service: v1alpha1.ImpersonationProxyServiceSpec{
+							Type:        v1alpha1.ImpersonationProxyServiceTypeClusterIP,
+							Annotations: credentialIssuerAnnotations,
+						},
+					},
+				}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 5) // one more item to update the loadbalancer
+				clusterIPService = requireClusterIPWasUpdated(kubeAPIClient.Actions()[4])
+				require.Equal(t, map[string]string{
+					// Now the CredentialIssuer annotations should be merged on the load balancer.
+					// In the unlikely case where keys conflict, the CredentialIssuer value overwrites the other value.
+					// Otherwise the annotations from the other actor should not be modified.
+					"annotation-from-unrelated-controller-key":      "annotation-from-unrelated-controller-val",
+					"my-annotation-key":                             "my-annotation-val",
+					"credentialissuer.pinniped.dev/annotation-keys": `["my-annotation-key"]`,
+				}, clusterIPService.Annotations)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+			})
+		})
+
+		when("requesting a load balancer via CredentialIssuer with annotations, then updating the CredentialIssuer annotations to remove one", func() {
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+							ExternalEndpoint: localhostIP,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type: v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+								Annotations: map[string]string{
+									"my-initial-annotation1-key": "my-initial-annotation1-val",
+									"my-initial-annotation2-key": "my-initial-annotation2-val",
+									"my-initial-annotation3-key": "my-initial-annotation3-val",
+								},
+							},
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+			})
+
+			it("creates the load balancer with annotations, then removes the removed annotation", func() {
+				startInformersAndController()
+
+				// Should have started in "enabled" mode with service type load balancer, so one is created.
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				lbService := requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
+				require.Equal(t, map[string]string{
+					"my-initial-annotation1-key":                    "my-initial-annotation1-val",
+					"my-initial-annotation2-key":                    "my-initial-annotation2-val",
+					"my-initial-annotation3-key":                    "my-initial-annotation3-val",
+					"credentialissuer.pinniped.dev/annotation-keys": `["my-initial-annotation1-key","my-initial-annotation2-key","my-initial-annotation3-key"]`,
+				}, lbService.Annotations) // there should be some annotations at first
+				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+				// Simulate another actor in the system, like a human user or a non-Pinniped controller,
+				// updating the new Service to add another annotation.
+				lbService.Annotations["annotation-from-unrelated-controller-key"] = "annotation-from-unrelated-controller-val"
+
+				// Simulate the informer cache's background update from its watch.
+				addObjectToKubeInformerAndWait(lbService, kubeInformers.Core().V1().Services())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[3], kubeInformers.Core().V1().Secrets())
+
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4) // no new actions because the controller decides there is nothing to update on the Service
+
+				// Remove one of the annotations from the CredentialIssuer spec.
+				updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
+					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+						Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+						ExternalEndpoint: localhostIP,
+						Service: v1alpha1.ImpersonationProxyServiceSpec{
+							Type: v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+							Annotations: map[string]string{
+								"my-initial-annotation1-key": "my-initial-annotation1-val",
+								"my-initial-annotation3-key": "my-initial-annotation3-val",
+							},
+						},
+					},
+				}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 5) // one more item to update the loadbalancer
+				lbService = requireLoadBalancerWasUpdated(kubeAPIClient.Actions()[4])
+				require.Equal(t, map[string]string{
+					// Now the CredentialIssuer annotations should be merged on the load balancer.
+					// Since the user removed the "my-initial-annotation2-key" key from the CredentialIssuer spec,
+					// it should be removed from the Service.
+					// The annotations from the other actor should not be modified.
+					"annotation-from-unrelated-controller-key":      "annotation-from-unrelated-controller-val",
+					"my-initial-annotation1-key":                    "my-initial-annotation1-val",
+					"my-initial-annotation3-key":                    "my-initial-annotation3-val",
+					"credentialissuer.pinniped.dev/annotation-keys": `["my-initial-annotation1-key","my-initial-annotation3-key"]`,
+				}, lbService.Annotations)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+				// Remove all the rest of the annotations from the CredentialIssuer spec so there are none remaining.
+				updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
+					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+						Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+						ExternalEndpoint: localhostIP,
+						Service: v1alpha1.ImpersonationProxyServiceSpec{
+							Type:        v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+							Annotations: map[string]string{},
+						},
+					},
+				}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 6) // one more item to update the loadbalancer
+				lbService = requireLoadBalancerWasUpdated(kubeAPIClient.Actions()[5])
+				require.Equal(t, map[string]string{
+					// Since the user removed all annotations from the CredentialIssuer spec,
+					// they should all be removed from the Service, along with the special bookkeeping annotation too.
+					// The annotations from the other actor should not be modified.
+					"annotation-from-unrelated-controller-key": "annotation-from-unrelated-controller-val",
+				}, lbService.Annotations)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+			})
+		})
+
+		when("requesting a load balancer via CredentialIssuer, but there is already a load balancer with an invalid bookkeeping annotation value", func() {
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+							ExternalEndpoint: localhostIP,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type:        v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+								Annotations: map[string]string{"some-annotation": "annotation-value"},
+							},
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+				// Add a Service with a messed up bookkeeping annotation.
+				loadBalancerService := newLoadBalancerService(loadBalancerServiceName, corev1.ServiceStatus{})
+				loadBalancerService.Annotations = map[string]string{
+					annotationKeysKey: `["this is not valid json`,
+				}
+				addServiceToTrackers(loadBalancerService, kubeInformerClient, kubeAPIClient)
+			})
+
+			it("just acts like the annotation wasn't present since that is better than becoming inoperable", func() {
+				startInformersAndController()
+
+				// Should have started in "enabled" mode with service type load balancer, so one is created.
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				lbService := requireLoadBalancerWasUpdated(kubeAPIClient.Actions()[1])
+				require.Equal(t, map[string]string{
+					"some-annotation": "annotation-value",
+					"credentialissuer.pinniped.dev/annotation-keys": `["some-annotation"]`,
+				}, lbService.Annotations)
+				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+			})
+		})
+
+		when("requesting an unmanaged load balancer via CredentialIssuer, then changing the Service by other means", func() {
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+							ExternalEndpoint: localhostIP,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type:      v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+								Unmanaged: true,
+							},
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+			})
+
+			it("creates the load balancer once and then does not revert the other changes to it", func() {
+				startInformersAndController()
+
+				// Should have started in "enabled" mode with service type load balancer, so one is created.
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
+				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+
+				// Simulate the informer cache's background update from its watch.
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Services())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[3], kubeInformers.Core().V1().Secrets())
+
+				// Another actor changes fields of the Service which are usually part of the desired state.
+				serviceObj, err := kubeInformerClient.Tracker().Get(
+					schema.GroupVersionResource{Version: "v1", Resource: "services"},
+					installedInNamespace,
+					loadBalancerServiceName,
+				)
+				r.NoError(err)
+				service := serviceObj.(*corev1.Service).DeepCopy()
+				service.Finalizers = []string{"example.com/some-finalizer"}
+				service.Labels = map[string]string{"some-other-label": "some-value"}
+				service.Spec.LoadBalancerSourceRanges = []string{"10.0.0.0/8"}
+				service.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyTypeLocal
+				r.NoError(kubeInformerClient.Tracker().Update(
+					schema.GroupVersionResource{Version: "v1", Resource: "services"},
+					service,
+					installedInNamespace,
+				))
+				waitForObjectToAppearInInformer(service, kubeInformers.Core().V1().Services())
+
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4) // nothing changed
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+
+				// Changes to the spec are also not applied to the existing Service.
+				updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
+					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+						Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+						ExternalEndpoint: localhostIP,
+						Service: v1alpha1.ImpersonationProxyServiceSpec{
+							Type:           v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+							LoadBalancerIP: "1.2.3.4",
+							Unmanaged:      true,
+						},
+					},
+				}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4) // nothing changed
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+
+				// When the Service becomes managed again, the desired state is restored.
+				updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
+					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+						Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+						ExternalEndpoint: localhostIP,
+						Service: v1alpha1.ImpersonationProxyServiceSpec{
+							Type: v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+						},
+					},
+				}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 5) // one more item to update the loadbalancer
+				lbService := requireLoadBalancerWasUpdated(kubeAPIClient.Actions()[4])
+				require.Equal(t, []string{"example.com/some-finalizer"}, lbService.Finalizers)
+				require.Equal(t, labels, lbService.Labels)
+				require.Nil(t, lbService.Spec.LoadBalancerSourceRanges)
+				require.Equal(t, corev1.ServiceExternalTrafficPolicyTypeCluster, lbService.Spec.ExternalTrafficPolicy)
+			})
+		})
+
+		when("requesting a load balancer via CredentialIssuer, then adding a static loadBalancerIP to the spec", func() {
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+							ExternalEndpoint: localhostIP,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type: v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+							},
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+			})
+
+			it("creates the load balancer without loadBalancerIP set, then adds it", func() {
+				startInformersAndController()
+
+				// Should have started in "enabled" mode with service type load balancer, so one is created.
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				lbService := requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
+				require.Equal(t, map[string]string(nil), lbService.Annotations) // there should be no annotations at first
+				require.Equal(t, "", lbService.Spec.LoadBalancerIP)
+				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+				// Simulate the informer cache's background update from its watch.
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Services())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[3], kubeInformers.Core().V1().Secrets())
+
+				// Add annotations to the spec.
+				loadBalancerIP := "1.2.3.4"
+				updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
+					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+						Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+						ExternalEndpoint: localhostIP,
+						Service: v1alpha1.ImpersonationProxyServiceSpec{
+							Type:           v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+							LoadBalancerIP: loadBalancerIP,
+						},
+					},
+				}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 5) // one more item to update the loadbalancer
+				lbService = requireLoadBalancerWasUpdated(kubeAPIClient.Actions()[4])
+				require.Equal(t, loadBalancerIP, lbService.Spec.LoadBalancerIP)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+			})
+		})
+
+		when("requesting a load balancer via CredentialIssuer with loadBalancerSourceRanges, then changing them in the spec", func() {
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+							ExternalEndpoint: localhostIP,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type:                     v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+								LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+							},
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+			})
+
+			it("creates the load balancer with loadBalancerSourceRanges set, then updates them", func() {
+				startInformersAndController()
+
+				// Should have started in "enabled" mode with service type load balancer, so one is created.
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				lbService := requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
+				require.Equal(t, []string{"10.0.0.0/8"}, lbService.Spec.LoadBalancerSourceRanges)
+				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+				// Simulate the informer cache's background update from its watch.
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Services())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[3], kubeInformers.Core().V1().Secrets())
+
+				// Nothing changed, so there should be no update.
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4)
+
+				// Change the source ranges in the spec.
+				updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
+					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+						Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+						ExternalEndpoint: localhostIP,
+						Service: v1alpha1.ImpersonationProxyServiceSpec{
+							Type:                     v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+							LoadBalancerSourceRanges: []string{"10.1.0.0/16", "2001:db8::/32"},
+						},
+					},
+				}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 5) // one more item to update the loadbalancer
+				lbService = requireLoadBalancerWasUpdated(kubeAPIClient.Actions()[4])
+				require.Equal(t, []string{"10.1.0.0/16", "2001:db8::/32"}, lbService.Spec.LoadBalancerSourceRanges)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+
+				// Remove the source ranges from the spec.
+				updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
+					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+						Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+						ExternalEndpoint: localhostIP,
+						Service: v1alpha1.ImpersonationProxyServiceSpec{
+							Type: v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+						},
+					},
+				}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 6) // one more item to update the loadbalancer
+				lbService = requireLoadBalancerWasUpdated(kubeAPIClient.Actions()[5])
+				require.Empty(t, lbService.Spec.LoadBalancerSourceRanges)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+			})
+		})
+
+		when("requesting a load balancer via CredentialIssuer with a Local externalTrafficPolicy, then changing it in the spec", func() {
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+							ExternalEndpoint: localhostIP,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type:                  v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+								ExternalTrafficPolicy: v1alpha1.ImpersonationProxyServiceExternalTrafficPolicyLocal,
+							},
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+			})
+
+			it("creates the load balancer with the Local policy, then updates it to the default policy", func() {
+				startInformersAndController()
+
+				// Should have started in "enabled" mode with service type load balancer, so one is created.
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				lbService := requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
+				require.Equal(t, corev1.ServiceExternalTrafficPolicyTypeLocal, lbService.Spec.ExternalTrafficPolicy)
+				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+				// Simulate the informer cache's background update from its watch.
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Services())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[3], kubeInformers.Core().V1().Secrets())
+
+				// Remove the policy from the spec, so it should go back to the default.
+				updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
+					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+						Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+						ExternalEndpoint: localhostIP,
+						Service: v1alpha1.ImpersonationProxyServiceSpec{
+							Type: v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+						},
+					},
+				}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 5) // one more item to update the loadbalancer
+				lbService = requireLoadBalancerWasUpdated(kubeAPIClient.Actions()[4])
+				require.Equal(t, corev1.ServiceExternalTrafficPolicyTypeCluster, lbService.Spec.ExternalTrafficPolicy)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+			})
+		})
+
+		when("requesting a load balancer via CredentialIssuer with a loadBalancerClass, then changing it in the spec", func() {
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+							ExternalEndpoint: localhostIP,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type:              v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+								LoadBalancerClass: pointer.String("example.com/some-class"),
+							},
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+			})
+
+			it("creates the load balancer with the class, then recreates it with the new class", func() {
+				startInformersAndController()
+
+				// Should have started in "enabled" mode with service type load balancer, so one is created.
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				lbService := requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
+				require.Equal(t, pointer.String("example.com/some-class"), lbService.Spec.LoadBalancerClass)
+				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+				// Simulate the informer cache's background update from its watch.
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Services())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[3], kubeInformers.Core().V1().Secrets())
+
+				// Sync again without changes, so nothing should happen.
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4)
+
+				// Change the class in the spec, which cannot be changed on the existing load balancer.
+				updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
+					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+						Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+						ExternalEndpoint: localhostIP,
+						Service: v1alpha1.ImpersonationProxyServiceSpec{
+							Type:              v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+							LoadBalancerClass: pointer.String("example.com/other-class"),
+						},
+					},
+				}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 6) // two more items to delete and recreate the loadbalancer
+				requireServiceWasDeleted(kubeAPIClient.Actions()[4], loadBalancerServiceName)
+				lbService = requireLoadBalancerWasCreated(kubeAPIClient.Actions()[5])
+				require.Equal(t, pointer.String("example.com/other-class"), lbService.Spec.LoadBalancerClass)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+			})
+		})
+
+		when("requesting a load balancer via CredentialIssuer with a custom port", func() {
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+							ExternalEndpoint: localhostIP,
+							Port:             pointer.Int32Ptr(9999),
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type: v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+							},
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+				impersonatorFuncExpectedPort = 9999
+			})
+
+			it("starts the impersonator on the custom port and uses it as the target port of the load balancer", func() {
+				startInformersAndController()
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				lbService := requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
+				require.Equal(t, []corev1.ServicePort{{TargetPort: intstr.FromInt(9999), Port: defaultHTTPSPort, Protocol: corev1.ProtocolTCP}}, lbService.Spec.Ports)
+				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+			})
+		})
+
+		when("the impersonation proxy is configured to bind to a specific address", func() {
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+							ExternalEndpoint: localhostIP,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+							},
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+				impersonationProxyBindAddress = net.ParseIP(localhostIP)
+			})
+
+			it("starts the impersonator on the configured address", func() {
+				startInformersAndController()
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 3)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				r.Equal(1, impersonatorFuncWasCalled)
+			})
+		})
+
+		when("requesting a load balancer via CredentialIssuer with a custom CA certificate lifetime", func() {
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:                  v1alpha1.ImpersonationProxyModeEnabled,
+							ExternalEndpoint:      localhostIP,
+							CACertificateLifetime: &metav1.Duration{Duration: 48 * time.Hour},
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type: v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+							},
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+			})
+
+			it("creates a CA with the requested lifetime", func() {
+				startInformersAndController()
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
+				ca := requireCASecretWasCreatedWithLifetime(kubeAPIClient.Actions()[2], 48*time.Hour)
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+			})
+		})
+
+		when("requesting a short CA certificate lifetime via CredentialIssuer", func() {
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:                  v1alpha1.ImpersonationProxyModeEnabled,
+							ExternalEndpoint:      localhostIP,
+							CACertificateLifetime: &metav1.Duration{Duration: time.Hour},
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+							},
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+			})
+
+			it("issues a TLS serving certificate which does not outlive the CA", func() {
+				startInformersAndController()
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 3)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				ca := requireCASecretWasCreatedWithLifetime(kubeAPIClient.Actions()[1], time.Hour)
+				createdTLSSecret := kubeAPIClient.Actions()[2].(coretesting.CreateAction).GetObject().(*corev1.Secret)
+				validCert := testutil.ValidateServerCertificate(t, string(ca), string(createdTLSSecret.Data[corev1.TLSCertKey]))
+				validCert.RequireLifetime(time.Now().Add(-5*time.Minute), time.Now().Add(time.Hour), 10*time.Second)
+
+				caBlock, _ := pem.Decode(ca)
+				r.NotNil(caBlock)
+				caCert, err := x509.ParseCertificate(caBlock.Bytes)
+				r.NoError(err)
+				tlsBlock, _ := pem.Decode(createdTLSSecret.Data[corev1.TLSCertKey])
+				r.NotNil(tlsBlock)
+				tlsCert, err := x509.ParseCertificate(tlsBlock.Bytes)
+				r.NoError(err)
+				r.False(tlsCert.NotAfter.After(caCert.NotAfter), "TLS serving certificate expires at %s, after its CA at %s", tlsCert.NotAfter, caCert.NotAfter)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+			})
+		})
+
+		when("requesting a custom CA certificate subject via CredentialIssuer", func() {
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+							ExternalEndpoint: localhostIP,
+							CACertificateSubject: &v1alpha1.ImpersonationProxyCASubject{
+								CommonName:   "Impersonation Proxy CA for some-cluster",
+								Organization: "Some Org",
+							},
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+							},
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+			})
+
+			it("creates a CA with the requested subject", func() {
+				startInformersAndController()
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 3)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				ca := requireCASecretWasCreatedWithLifetimeAndSubject(kubeAPIClient.Actions()[1], 100*time.Hour*24*365, pkix.Name{
+					CommonName:   "Impersonation Proxy CA for some-cluster",
+					Organization: []string{"Some Org"},
+				})
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+			})
+		})
+
+		when("the CA certificate has passed its renewal threshold", func() {
+			var oldCACrt []byte
+			var addCredentialIssuerWithRenewalThreshold = func(renewalThresholdPercent *int32) {
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:                                 v1alpha1.ImpersonationProxyModeEnabled,
+							ExternalEndpoint:                     localhostIP,
+							CACertificateRenewalThresholdPercent: renewalThresholdPercent,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+							},
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+			}
+
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+				ca := newCA() // valid for 24 hours
+				caSecret := newActualCASecret(ca, caSecretName)
+				oldCACrt = caSecret.Data["ca.crt"]
+				addSecretToTrackers(caSecret, kubeAPIClient, kubeInformerClient)
+				addSecretToTrackers(newActualTLSSecret(ca, tlsSecretName, localhostIP), kubeAPIClient, kubeInformerClient)
+				// Less than 25% of the CA's lifetime remains, but more than 1%.
+				frozenNow = time.Now().Add(23 * time.Hour)
+			})
+
+			it("replaces the CA and reissues the TLS serving certificate from the new CA, advertising both CAs until the new TLS Secret is observed", func() {
+				addCredentialIssuerWithRenewalThreshold(nil)
+				startInformersAndController()
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				ca := requireCASecretWasUpdated(kubeAPIClient.Actions()[1], oldCACrt, oldCACrt)
+				requireTLSSecretWasDeleted(kubeAPIClient.Actions()[2])
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, append(append([]byte{}, ca...), oldCACrt...)))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+				requireCertificateIssuanceMetrics(map[string]float64{caIssuedReasonRenewed: 1}, map[string]float64{tlsIssuedReasonCAChanged: 1})
+				requireTLSCertificateExpirationMetric(kubeAPIClient.Actions()[3])
+
+				// Simulate the informer cache's background update from its watch.
+				updatedCASecret := kubeAPIClient.Actions()[1].(coretesting.UpdateAction).GetObject().(*corev1.Secret)
+				r.NoError(kubeInformerClient.Tracker().Update(corev1.SchemeGroupVersion.WithResource("secrets"), updatedCASecret, installedInNamespace))
+				waitForObjectToAppearInInformer(updatedCASecret, kubeInformers.Core().V1().Secrets())
+				deleteSecretFromTracker(tlsSecretName, kubeInformerClient)
+				waitForObjectToBeDeletedFromInformer(tlsSecretName, kubeInformers.Core().V1().Secrets())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[3], kubeInformers.Core().V1().Secrets())
+
+				// Now that the new TLS Secret was observed, only the new CA is advertised.
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 5)
+				requirePreviousCACertWasRemoved(kubeAPIClient.Actions()[4], ca)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+			})
+
+			it("keeps advertising the previous CA after a restart during the renewal, until the new TLS Secret is observed", func() {
+				addCredentialIssuerWithRenewalThreshold(nil)
+				// The CA was already renewed by a previous instance of the controller, which stopped before reissuing
+				// the TLS serving certificate, so only the CA Secret remembers the previous CA.
+				renewedCA := newCA()
+				renewedCASecret := newActualCASecret(renewedCA, caSecretName)
+				renewedCASecret.Data["previous-ca.crt"] = oldCACrt
+				r.NoError(kubeAPIClient.Tracker().Update(corev1.SchemeGroupVersion.WithResource("secrets"), renewedCASecret, installedInNamespace))
+				r.NoError(kubeInformerClient.Tracker().Update(corev1.SchemeGroupVersion.WithResource("secrets"), renewedCASecret, installedInNamespace))
+				frozenNow = time.Now()
+				startInformersAndController()
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 3)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				requireTLSSecretWasDeleted(kubeAPIClient.Actions()[1])
+				ca := renewedCASecret.Data["ca.crt"]
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, append(append([]byte{}, ca...), oldCACrt...)))
+
+				// Simulate the informer cache's background update from its watch.
+				deleteSecretFromTracker(tlsSecretName, kubeInformerClient)
+				waitForObjectToBeDeletedFromInformer(tlsSecretName, kubeInformers.Core().V1().Secrets())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+
+				// Now that the new TLS Secret was observed, the previous CA is removed from the CA Secret.
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4)
+				requirePreviousCACertWasRemoved(kubeAPIClient.Actions()[3], ca)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+			})
+
+			it("replaces a generated CA which has already expired instead of failing", func() {
+				addCredentialIssuerWithRenewalThreshold(nil)
+				// Mark the CA Secret as generated by the controller, which then remained stopped until after the CA expired.
+				obj, err := kubeAPIClient.Tracker().Get(corev1.SchemeGroupVersion.WithResource("secrets"), installedInNamespace, caSecretName)
+				r.NoError(err)
+				generatedCASecret := obj.(*corev1.Secret).DeepCopy()
+				sum := sha256.Sum256(oldCACrt)
+				generatedCASecret.Annotations = map[string]string{"impersonation-proxy.concierge.pinniped.dev/generated-ca-sha256": hex.EncodeToString(sum[:])}
+				r.NoError(kubeAPIClient.Tracker().Update(corev1.SchemeGroupVersion.WithResource("secrets"), generatedCASecret, installedInNamespace))
+				r.NoError(kubeInformerClient.Tracker().Update(corev1.SchemeGroupVersion.WithResource("secrets"), generatedCASecret, installedInNamespace))
+				frozenNow = time.Now().Add(25 * time.Hour)
+				startInformersAndController()
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				ca := requireCASecretWasUpdated(kubeAPIClient.Actions()[1], oldCACrt, nil)
+				requireTLSSecretWasDeleted(kubeAPIClient.Actions()[2])
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireCertificateIssuanceMetrics(map[string]float64{caIssuedReasonRenewed: 1}, map[string]float64{tlsIssuedReasonCAChanged: 1})
+			})
+
+			it("keeps using the existing CA when the renewal threshold is configured lower", func() {
+				addCredentialIssuerWithRenewalThreshold(pointer.Int32Ptr(1))
+				startInformersAndController()
+				r.NoError(runControllerSync())
+				// The TLS serving certificate was issued from the same 24 hour CA, so less than a third of its lifetime
+				// remains after 23 hours. It is reissued from the existing CA, which is kept.
+				r.Len(kubeAPIClient.Actions(), 3)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				requireTLSSecretWasDeleted(kubeAPIClient.Actions()[1])
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], oldCACrt)
+				requireTLSServerIsRunning(oldCACrt, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, oldCACrt))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+				requireCertificateIssuanceMetrics(nil, map[string]float64{tlsIssuedReasonExpiring: 1})
+			})
+		})
+
+		when("syncs are jittered", func() {
+			it.Before(func() {
+				maxSyncJitter = 10 * time.Second
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode: v1alpha1.ImpersonationProxyModeAuto,
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+			})
+
+			var requireAddedAfter = func(wantKeys ...controllerlib.Key) {
+				queue.mutex.RLock() // this is to satisfy the race detector
+				defer queue.mutex.RUnlock()
+				r.Equal(wantKeys, queue.addedAfterKeys)
+				for _, duration := range queue.addedAfterDurations {
+					r.GreaterOrEqual(duration, maxSyncJitter/2)
+					r.LessOrEqual(duration, maxSyncJitter)
+				}
+			}
+
+			it("delays the initial sync and coalesces near-simultaneous Secret changes into one delayed sync", func() {
+				startInformersAndController()
+
+				// The initial sync does nothing but enqueue itself again after a delay.
+				r.NoError(runControllerSync())
+				r.Empty(kubeAPIClient.Actions())
+				requireAddedAfter(syncContext.Key)
+
+				// The delayed initial sync does the real work.
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 3)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
+				requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+				requireCredentialIssuer(newPendingStrategyWaitingForLB())
+
+				// Simulate the informer cache's background update from its watch.
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Services())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+
+				// Two Secret changes while the delay of the initial sync has not yet passed are coalesced into it.
+				syncContext.Key = jitteredSyncKey
+				r.NoError(runControllerSync())
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 3)
+				requireAddedAfter(controllerlib.Key{Name: credentialIssuerResourceName})
+
+				// After the delay has passed, the first of two near-simultaneous Secret changes enqueues a delayed
+				// sync of the singleton key, and the second one is coalesced into that delayed sync.
+				fakeClock.Step(maxSyncJitter)
+				frozenNow = fakeClock.Now() // the status timestamps come from the clock, which was moved forward
+				r.NoError(runControllerSync())
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 3)
+				requireAddedAfter(controllerlib.Key{Name: credentialIssuerResourceName}, controllerlib.Key{})
+
+				// Changes to the CredentialIssuer are never delayed.
+				updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
+					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+						Mode: v1alpha1.ImpersonationProxyModeDisabled,
+					},
+				}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+				syncContext.Key = controllerlib.Key{Name: credentialIssuerResourceName}
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4)
+				requireServiceWasDeleted(kubeAPIClient.Actions()[3], loadBalancerServiceName)
+				requireCredentialIssuer(newManuallyDisabledStrategy())
+				requireAddedAfter(controllerlib.Key{Name: credentialIssuerResourceName}, controllerlib.Key{})
+			})
+		})
+
+		when("a CA rotation is requested by an annotation on the CredentialIssuer", func() {
+			var oldCACrt []byte
+			var caSecretCreationTime time.Time
+			var addCredentialIssuerWithRotationRequest = func(rotationRequest string) {
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        credentialIssuerResourceName,
+						Annotations: map[string]string{"impersonation-proxy.concierge.pinniped.dev/rotate-ca": rotationRequest},
+					},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+							ExternalEndpoint: localhostIP,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+							},
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+			}
+
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+				ca := newCA()
+				caSecret := newActualCASecret(ca, caSecretName)
+				caSecretCreationTime = frozenNow.Add(-time.Hour).Truncate(time.Second)
+				caSecret.CreationTimestamp = metav1.NewTime(caSecretCreationTime)
+				oldCACrt = caSecret.Data["ca.crt"]
+				addSecretToTrackers(caSecret, kubeAPIClient, kubeInformerClient)
+				addSecretToTrackers(newActualTLSSecret(ca, tlsSecretName, localhostIP), kubeAPIClient, kubeInformerClient)
+			})
+
+			it("replaces the CA and reissues the TLS serving certificate exactly once, advertising only the new CA", func() {
+				rotationRequest := frozenNow.UTC().Format(time.RFC3339)
+				addCredentialIssuerWithRotationRequest(rotationRequest)
+				startInformersAndController()
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				ca := requireCASecretWasUpdated(kubeAPIClient.Actions()[1], oldCACrt, nil)
+				updatedCASecret := kubeAPIClient.Actions()[1].(coretesting.UpdateAction).GetObject().(*corev1.Secret)
+				r.Equal(rotationRequest, updatedCASecret.Annotations["impersonation-proxy.concierge.pinniped.dev/last-ca-rotation"])
+				requireTLSSecretWasDeleted(kubeAPIClient.Actions()[2])
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+				requireCertificateIssuanceMetrics(map[string]float64{caIssuedReasonRotated: 1}, map[string]float64{tlsIssuedReasonCAChanged: 1})
+
+				// Simulate the informer cache's background update from its watch.
+				r.NoError(kubeInformerClient.Tracker().Update(corev1.SchemeGroupVersion.WithResource("secrets"), updatedCASecret, installedInNamespace))
+				waitForObjectToAppearInInformer(updatedCASecret, kubeInformers.Core().V1().Secrets())
+				deleteSecretFromTracker(tlsSecretName, kubeInformerClient)
+				waitForObjectToBeDeletedFromInformer(tlsSecretName, kubeInformers.Core().V1().Secrets())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[3], kubeInformers.Core().V1().Secrets())
+
+				// The same request is not processed again.
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4) // nothing changed
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireCertificateIssuanceMetrics(map[string]float64{caIssuedReasonRotated: 1}, map[string]float64{tlsIssuedReasonCAChanged: 1})
+			})
+
+			it("keeps using the existing CA when the rotation was requested before the CA Secret was created", func() {
+				addCredentialIssuerWithRotationRequest(caSecretCreationTime.Add(-time.Minute).UTC().Format(time.RFC3339))
+				startInformersAndController()
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 1)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				requireTLSServerIsRunning(oldCACrt, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, oldCACrt))
+				requireCertificateIssuanceMetrics(nil, nil)
+			})
+
+			it("returns an error when the rotation request is not a timestamp", func() {
+				addCredentialIssuerWithRotationRequest("yesterday")
+				startInformersAndController()
+				errString := `invalid impersonation-proxy.concierge.pinniped.dev/rotate-ca annotation "yesterday" (expected an RFC3339 timestamp)`
+				r.EqualError(runControllerSync(), errString)
+				r.Len(kubeAPIClient.Actions(), 1)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.CAErrorStrategyReason, errString))
+			})
+		})
+
+		when("the TLS serving certificate approaches its expiration", func() {
+			var caCrt []byte
+			it.Before(func() {
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:                   v1alpha1.ImpersonationProxyModeEnabled,
+							ExternalEndpoint:       localhostIP,
+							TLSCertificateLifetime: &metav1.Duration{Duration: 24 * time.Hour},
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+							},
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+				ca, err := certauthority.New("test CA", 365*24*time.Hour) // long enough to never need renewal in this test
+				r.NoError(err)
+				caSecret := newActualCASecret(ca, caSecretName)
+				caCrt = caSecret.Data["ca.crt"]
+				addSecretToTrackers(caSecret, kubeAPIClient, kubeInformerClient)
+				frozenNow = time.Now()
+			})
+
+			it("issues the TLS serving certificate with the configured lifetime, then reissues it from the same CA when less than a third of its lifetime remains", func() {
+				startInformersAndController()
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 2)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				requireTLSSecretWasCreatedWithLifetime(kubeAPIClient.Actions()[1], caCrt, 24*time.Hour)
+				requireTLSServerIsRunning(caCrt, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, caCrt))
+				requireCertificateIssuanceMetrics(nil, map[string]float64{tlsIssuedReasonMissing: 1})
+
+				// Simulate the informer cache's background update from its watch.
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Secrets())
+
+				// More than a third of the TLS certificate's lifetime still remains.
+				fakeClock.SetTime(frozenNow.Add(15 * time.Hour))
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 2) // nothing changed
+				requireCertificateIssuanceMetrics(nil, map[string]float64{tlsIssuedReasonMissing: 1})
+
+				// Less than a third of the TLS certificate's lifetime remains.
+				fakeClock.SetTime(frozenNow.Add(17 * time.Hour))
+				frozenNow = fakeClock.Now() // the status timestamps come from the clock, which was moved forward
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4)
+				requireTLSSecretWasDeleted(kubeAPIClient.Actions()[2])
+				requireTLSSecretWasCreatedWithLifetime(kubeAPIClient.Actions()[3], caCrt, 24*time.Hour)
+				requireTLSServerIsRunning(caCrt, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, caCrt))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+				requireCertificateIssuanceMetrics(nil, map[string]float64{tlsIssuedReasonMissing: 1, tlsIssuedReasonExpiring: 1})
+				requireTLSCertificateExpirationMetric(kubeAPIClient.Actions()[3])
+
+				// The status advertises the expiration time of the reissued certificate.
+				reissuedSecret := kubeAPIClient.Actions()[3].(coretesting.CreateAction).GetObject().(*corev1.Secret)
+				block, _ := pem.Decode(reissuedSecret.Data[corev1.TLSCertKey])
+				r.NotNil(block)
+				reissuedCert, err := x509.ParseCertificate(block.Bytes)
+				r.NoError(err)
+				reissuedNotAfter := getCredentialIssuer().Status.Strategies[0].Frontend.ImpersonationProxyInfo.CertificateNotAfter
+				r.NotNil(reissuedNotAfter)
+				r.True(reissuedNotAfter.Time.Equal(reissuedCert.NotAfter))
+			})
+		})
+
+		when("the CA secret was provided externally by an operator", func() {
+			var externalCACrt []byte
+			var externalCANotBefore, externalCANotAfter time.Time
+
+			var addExternallyProvidedCASecret = func(data map[string][]byte) {
+				caSecret := newSecretWithData(caSecretName, data)
+				caSecret.Annotations = map[string]string{"impersonation-proxy.concierge.pinniped.dev/externally-provided-ca": "true"}
+				externalCACrt = caSecret.Data["ca.crt"]
+				block, _ := pem.Decode(externalCACrt)
+				caCert, err := x509.ParseCertificate(block.Bytes)
+				r.NoError(err)
+				externalCANotBefore, externalCANotAfter = caCert.NotBefore, caCert.NotAfter
+				addSecretToTrackers(caSecret, kubeAPIClient, kubeInformerClient)
+			}
+
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+							ExternalEndpoint: localhostIP,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+							},
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+			})
+
+			it("issues the TLS serving certificate from the provided CA and never renews it", func() {
+				addExternallyProvidedCASecret(newCACertSecretData(newCA())) // valid for 24 hours
+				// Less than 25% of the CA's lifetime remains, which would cause a self-generated CA to be renewed.
+				frozenNow = time.Now().Add(23 * time.Hour)
+				startInformersAndController()
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 2)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[1], externalCACrt)
+				requireTLSServerIsRunning(externalCACrt, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, externalCACrt))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+			})
+
+			it("returns an error when the provided CA has expired", func() {
+				addExternallyProvidedCASecret(newCACertSecretData(newCA())) // valid for 24 hours
+				frozenNow = time.Now().Add(25 * time.Hour)
+				startInformersAndController()
+				errString := fmt.Sprintf("could not load CA: externally provided certificate is only valid from %s to %s",
+					externalCANotBefore.UTC().Format(time.RFC3339), externalCANotAfter.UTC().Format(time.RFC3339))
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.CAErrorStrategyReason, errString))
+				requireTLSServerIsRunningWithoutCerts()
+				r.Len(kubeAPIClient.Actions(), 1)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+			})
+
+			it("returns an error when the provided CA is not allowed to sign certificates", func() {
+				caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				r.NoError(err)
+				caTemplate := &x509.Certificate{
+					SerialNumber:          big.NewInt(1),
+					Subject:               pkix.Name{CommonName: "CA without cert sign key usage"},
+					NotBefore:             time.Now().Add(-time.Hour),
+					NotAfter:              time.Now().Add(time.Hour),
+					IsCA:                  true,
+					BasicConstraintsValid: true,
+					KeyUsage:              x509.KeyUsageDigitalSignature,
+				}
+				caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, caKey.Public(), caKey)
+				r.NoError(err)
+				caKeyDER, err := x509.MarshalECPrivateKey(caKey)
+				r.NoError(err)
+				addExternallyProvidedCASecret(map[string][]byte{
+					"ca.crt": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+					"ca.key": pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: caKeyDER}),
+				})
+				startInformersAndController()
+				errString := "could not load CA: certificate key usage does not allow signing certificates"
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.CAErrorStrategyReason, errString))
+				requireTLSServerIsRunningWithoutCerts()
+				r.Len(kubeAPIClient.Actions(), 1)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+			})
+		})
+
+		when("the TLS serving certificate was provided by an operator", func() {
+			const operatorTLSSecretName = "some-operator-tls-secret-name"
+			var operatorCA *certauthority.CA
+
+			var addOperatorTLSSecret = func(issuer *certauthority.CA, ip string) *x509.Certificate {
+				secret := newSecretWithData(operatorTLSSecretName, newTLSCertSecretData(issuer, nil, ip))
+				secret.Type = corev1.SecretTypeTLS
+				addSecretToTrackers(secret, kubeAPIClient, kubeInformerClient)
+				block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+				cert, err := x509.ParseCertificate(block.Bytes)
+				r.NoError(err)
+				return cert
+			}
+
+			var addCredentialIssuerWithOperatorTLSSecret = func(secretName string, caData string) {
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+							ExternalEndpoint: localhostIP,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+							},
+							TLSCertificateSecretName:    secretName,
+							TLSCertificateAuthorityData: caData,
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+			}
+
+			it.Before(func() {
+				frozenNow = time.Now() // the certificates are only valid around the current time
+				operatorCA = newCA()
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+			})
+
+			when("the CredentialIssuer references a valid Secret", func() {
+				it.Before(func() {
+					addCredentialIssuerWithOperatorTLSSecret(operatorTLSSecretName, base64.StdEncoding.EncodeToString(operatorCA.Bundle()))
+				})
+
+				it("serves the provided certificate without generating a CA or serving certificate", func() {
+					cert := addOperatorTLSSecret(operatorCA, localhostIP)
+					startInformersAndController()
+					r.NoError(runControllerSync())
+					r.Len(kubeAPIClient.Actions(), 1)
+					requireNodesListed(kubeAPIClient.Actions()[0])
+					requireTLSServerIsRunning(operatorCA.Bundle(), testServerAddr(), nil)
+					wantStrategy := newSuccessStrategy(localhostIP, operatorCA.Bundle())
+					notAfter := metav1.NewTime(cert.NotAfter)
+					wantStrategy.Frontend.ImpersonationProxyInfo.CertificateNotAfter = &notAfter
+					requireCredentialIssuer(wantStrategy)
+					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+				})
+
+				it("deletes a previously generated serving certificate", func() {
+					addOperatorTLSSecret(operatorCA, localhostIP)
+					addSecretToTrackers(newActualTLSSecret(newCA(), tlsSecretName, localhostIP), kubeAPIClient, kubeInformerClient)
+					startInformersAndController()
+					r.NoError(runControllerSync())
+					r.Len(kubeAPIClient.Actions(), 2)
+					requireNodesListed(kubeAPIClient.Actions()[0])
+					requireTLSSecretWasDeleted(kubeAPIClient.Actions()[1])
+					requireTLSServerIsRunning(operatorCA.Bundle(), testServerAddr(), nil)
+				})
+
+				it("returns an error when the Secret does not exist", func() {
+					startInformersAndController()
+					errString := `could not load tlsCertificateSecretName "some-operator-tls-secret-name": secret "some-operator-tls-secret-name" not found`
+					r.EqualError(runControllerSync(), errString)
+					requireCredentialIssuer(newErrorStrategy(v1alpha1.SecretErrorStrategyReason, errString))
+					requireTLSServerIsRunningWithoutCerts()
+				})
+
+				it("returns an error when the certificate does not cover the endpoint", func() {
+					addOperatorTLSSecret(operatorCA, "127.0.0.2")
+					startInformersAndController()
+					errString := `could not load tlsCertificateSecretName "some-operator-tls-secret-name": certificate does not cover the endpoint: x509: certificate is valid for 127.0.0.2, not 127.0.0.1`
+					r.EqualError(runControllerSync(), errString)
+					requireCredentialIssuer(newErrorStrategy(v1alpha1.SecretErrorStrategyReason, errString))
+					requireTLSServerIsRunningWithoutCerts()
+				})
+
+				it("returns an error when the certificate was not issued by the provided CA", func() {
+					addOperatorTLSSecret(newCA(), localhostIP)
+					startInformersAndController()
+					errString := `could not load tlsCertificateSecretName "some-operator-tls-secret-name": certificate does not verify against tlsCertificateAuthorityData: x509: certificate signed by unknown authority (possibly because of "x509: ECDSA verification failure" while trying to verify candidate authority certificate "test CA")`
+					r.EqualError(runControllerSync(), errString)
+					requireCredentialIssuer(newErrorStrategy(v1alpha1.SecretErrorStrategyReason, errString))
+					requireTLSServerIsRunningWithoutCerts()
+				})
+			})
+
+			it("returns an error when the CA data is missing", func() {
+				addCredentialIssuerWithOperatorTLSSecret(operatorTLSSecretName, "")
+				startInformersAndController()
+				errString := "could not load CredentialIssuer spec.impersonationProxy: tlsCertificateAuthorityData must be set when tlsCertificateSecretName is set"
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
+			})
+
+			it("returns an error when the CA data does not contain certificates", func() {
+				addCredentialIssuerWithOperatorTLSSecret(operatorTLSSecretName, base64.StdEncoding.EncodeToString([]byte("not a certificate")))
+				startInformersAndController()
+				errString := "could not load CredentialIssuer spec.impersonationProxy: invalid tlsCertificateAuthorityData: no certificates found"
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
+			})
+
+			it("returns an error when the Secret is one which is managed by the Concierge", func() {
+				addCredentialIssuerWithOperatorTLSSecret(tlsSecretName, base64.StdEncoding.EncodeToString(operatorCA.Bundle()))
+				startInformersAndController()
+				errString := `could not load CredentialIssuer spec.impersonationProxy: invalid tlsCertificateSecretName "some-tls-secret-name" (must not be the name of a Secret which is managed by the Concierge)`
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
+			})
+		})
+
+		when("the names of the generated Secrets are overridden by the CredentialIssuer", func() {
+			const renamedCASecretName = "some-renamed-ca-secret-name"
+			const renamedTLSSecretName = "some-renamed-tls-secret-name"
+
+			var addCredentialIssuerWithGeneratedSecretNames = func(generatedCASecretName, generatedTLSSecretName string) {
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+							ExternalEndpoint: localhostIP,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+							},
+							GeneratedCASecretName:  generatedCASecretName,
+							GeneratedTLSSecretName: generatedTLSSecretName,
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+			}
+
+			var requireSecretWasCreatedWithName = func(action coretesting.Action, name string) *corev1.Secret {
+				createAction, ok := action.(coretesting.CreateAction)
+				r.True(ok, "should have been able to cast this action to CreateAction: %v", action)
+				r.Equal("create", createAction.GetVerb())
+				createdSecret := createAction.GetObject().(*corev1.Secret)
+				r.Equal(name, createdSecret.Name)
+				r.Equal(installedInNamespace, createdSecret.Namespace)
+				return createdSecret
+			}
+
+			var requireSecretWasDeletedWithName = func(action coretesting.Action, name string) {
+				deleteAction, ok := action.(coretesting.DeleteAction)
+				r.True(ok, "should have been able to cast this action to DeleteAction: %v", action)
+				r.Equal("delete", deleteAction.GetVerb())
+				r.Equal(name, deleteAction.GetName())
+				r.Equal("secrets", deleteAction.GetResource().Resource)
+				r.Equal(testutil.NewPreconditions("uid-1234", "rv-5678"), deleteAction.GetDeleteOptions())
+			}
+
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+			})
+
+			when("the Secrets with the default names were already generated", func() {
+				it.Before(func() {
+					ca := newCA()
+					addSecretToTrackers(newSecretWithData(caSecretName, newCACertSecretData(ca)), kubeAPIClient, kubeInformerClient)
+					addSecretToTrackers(newActualTLSSecret(ca, tlsSecretName, localhostIP), kubeAPIClient, kubeInformerClient)
+					addCredentialIssuerWithGeneratedSecretNames(renamedCASecretName, renamedTLSSecretName)
+				})
+
+				it("generates the Secrets with the overridden names and deletes the previously named ones", func() {
+					startInformersAndController()
+					r.NoError(runControllerSync())
+					r.Len(kubeAPIClient.Actions(), 5)
+					requireNodesListed(kubeAPIClient.Actions()[0])
+					caCrt := requireSecretWasCreatedWithName(kubeAPIClient.Actions()[1], renamedCASecretName).Data["ca.crt"]
+					tlsSecret := requireSecretWasCreatedWithName(kubeAPIClient.Actions()[2], renamedTLSSecretName)
+					requireSecretWasDeletedWithName(kubeAPIClient.Actions()[3], caSecretName)
+					requireSecretWasDeletedWithName(kubeAPIClient.Actions()[4], tlsSecretName)
+					requireTLSServerIsRunning(caCrt, testServerAddr(), nil)
+
+					block, _ := pem.Decode(tlsSecret.Data[corev1.TLSCertKey])
+					cert, err := x509.ParseCertificate(block.Bytes)
+					r.NoError(err)
+					wantStrategy := newSuccessStrategy(localhostIP, caCrt)
+					notAfter := metav1.NewTime(cert.NotAfter)
+					wantStrategy.Frontend.ImpersonationProxyInfo.CertificateNotAfter = &notAfter
+					requireCredentialIssuer(wantStrategy)
+					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+				})
+			})
+
+			it("returns an error when an overridden name is not a valid name for a Secret", func() {
+				addCredentialIssuerWithGeneratedSecretNames("Not_A_Valid_Name", "")
+				startInformersAndController()
+				errString := `could not load CredentialIssuer spec.impersonationProxy: invalid generatedCASecretName "Not_A_Valid_Name" (expected a DNS-1123 subdomain)`
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
+			})
+
+			it("returns an error when the generated Secrets would have the same name", func() {
+				addCredentialIssuerWithGeneratedSecretNames("", caSecretName)
+				startInformersAndController()
+				errString := `could not load CredentialIssuer spec.impersonationProxy: the generated CA Secret "some-ca-secret-name", the generated TLS Secret "some-ca-secret-name", and the signer Secret "some-ca-signer-name" must have different names`
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
+			})
+		})
+
+		when("requesting a load balancer via CredentialIssuer, then changing the port in the spec", func() {
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+							ExternalEndpoint: localhostIP,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type: v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+							},
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+			})
+
+			it("restarts the impersonator on the new port and updates the target port of the load balancer", func() {
+				startInformersAndController()
+
+				// Should have started on the default port.
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 4)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				lbService := requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
+				require.Equal(t, intstr.FromInt(impersonationProxyPort), lbService.Spec.Ports[0].TargetPort)
+				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				r.Equal(1, impersonatorFuncWasCalled)
+
+				// Simulate the informer cache's background update from its watch.
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Services())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[3], kubeInformers.Core().V1().Secrets())
+
+				// Change the port in the spec.
+				impersonatorFuncExpectedPort = 9999
+				updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
+					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+						Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+						ExternalEndpoint: localhostIP,
+						Port:             pointer.Int32Ptr(9999),
+						Service: v1alpha1.ImpersonationProxyServiceSpec{
+							Type: v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+						},
+					},
+				}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+
+				r.NoError(runControllerSync())
+				r.Equal(2, impersonatorFuncWasCalled) // was restarted on the new port
+				r.Len(kubeAPIClient.Actions(), 5)     // one more item to update the loadbalancer
+				lbService = requireLoadBalancerWasUpdated(kubeAPIClient.Actions()[4])
+				require.Equal(t, intstr.FromInt(9999), lbService.Spec.Ports[0].TargetPort)
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+			})
+		})
+
+		when("sync is called more than once", func() {
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode: v1alpha1.ImpersonationProxyModeAuto,
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+			})
+
+			it("only starts the impersonator once and only lists the cluster's nodes once", func() {
+				startInformersAndController()
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 3)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
+				requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+				requireTLSServerIsRunningWithoutCerts()
+				requireCredentialIssuer(newPendingStrategyWaitingForLB())
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+				// Simulate the informer cache's background update from its watch.
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Services())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+
+				r.NoError(runControllerSync())
+				r.Equal(1, impersonatorFuncWasCalled)   // wasn't started a second time
+				requireTLSServerIsRunningWithoutCerts() // still running
+				requireCredentialIssuer(newPendingStrategyWaitingForLB())
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+				r.Len(kubeAPIClient.Actions(), 3) // no new API calls
+			})
+
+			it("creates certs from the ip address listed on the load balancer", func() {
+				startInformersAndController()
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 3)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
+				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+				requireTLSServerIsRunningWithoutCerts()
+				requireCredentialIssuer(newPendingStrategyWaitingForLB())
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+				// Simulate the informer cache's background update from its watch.
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Services())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+
+				updateLoadBalancerServiceInInformerAndWait(loadBalancerServiceName, []corev1.LoadBalancerIngress{{IP: localhostIP}}, kubeInformers.Core().V1().Services())
+
+				r.NoError(runControllerSync())
+				r.Equal(1, impersonatorFuncWasCalled) // wasn't started a second time
+				r.Len(kubeAPIClient.Actions(), 4)
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca) // uses the ca from last time
+				requireTLSServerIsRunning(ca, testServerAddr(), nil)       // running with certs now
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+				// Simulate the informer cache's background update from its watch.
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[3], kubeInformers.Core().V1().Secrets())
+
+				r.NoError(runControllerSync())
+				r.Equal(1, impersonatorFuncWasCalled)                // wasn't started again
+				r.Len(kubeAPIClient.Actions(), 4)                    // no more actions
+				requireTLSServerIsRunning(ca, testServerAddr(), nil) // still running
+				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+			})
+
+			it("creates certs from the hostname listed on the load balancer", func() {
+				hostname := "fake.example.com"
+				startInformersAndController()
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 3)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
+				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+				requireTLSServerIsRunningWithoutCerts()
+				requireCredentialIssuer(newPendingStrategyWaitingForLB())
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+				// Simulate the informer cache's background update from its watch.
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Services())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+
+				updateLoadBalancerServiceInInformerAndWait(loadBalancerServiceName, []corev1.LoadBalancerIngress{{IP: localhostIP, Hostname: hostname}}, kubeInformers.Core().V1().Services())
+
+				r.NoError(runControllerSync())
+				r.Equal(1, impersonatorFuncWasCalled) // wasn't started a second time
+				r.Len(kubeAPIClient.Actions(), 4)
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)                                         // uses the ca from last time
+				requireTLSServerIsRunning(ca, hostname, map[string]string{hostname + httpsPort: testServerAddr()}) // running with certs now
+				requireCredentialIssuer(newSuccessStrategy(hostname, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+				// Simulate the informer cache's background update from its watch.
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[3], kubeInformers.Core().V1().Secrets())
+
+				r.NoError(runControllerSync())
+				r.Equal(1, impersonatorFuncWasCalled)                                                              // wasn't started a third time
+				r.Len(kubeAPIClient.Actions(), 4)                                                                  // no more actions
+				requireTLSServerIsRunning(ca, hostname, map[string]string{hostname + httpsPort: testServerAddr()}) // still running
+				requireCredentialIssuer(newSuccessStrategy(hostname, ca))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+			})
+		})
+
+		when("there is already a CredentialIssuer", func() {
+			preExistingStrategy := v1alpha1.CredentialIssuerStrategy{
+				Type:           v1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         v1alpha1.SuccessStrategyStatus,
+				Reason:         v1alpha1.FetchedKeyStrategyReason,
+				Message:        "happy other unrelated strategy",
+				LastUpdateTime: metav1.NewTime(frozenNow),
+				Frontend: &v1alpha1.CredentialIssuerFrontend{
+					Type: v1alpha1.TokenCredentialRequestAPIFrontendType,
+				},
+			}
+
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode: v1alpha1.ImpersonationProxyModeAuto,
+						},
+					},
+					Status: v1alpha1.CredentialIssuerStatus{
+						Strategies: []v1alpha1.CredentialIssuerStrategy{
+							preExistingStrategy,
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+			})
+
+			it("merges into the existing strategy array on the CredentialIssuer", func() {
+				startInformersAndController()
+				r.NoError(runControllerSync())
+				requireTLSServerIsRunningWithoutCerts()
+				r.Len(kubeAPIClient.Actions(), 3)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
+				requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+				credentialIssuer := getCredentialIssuer()
+				r.Equal([]v1alpha1.CredentialIssuerStrategy{preExistingStrategy, newPendingStrategyWaitingForLB()}, credentialIssuer.Status.Strategies)
+			})
+		})
+
+		when("getting the control plane nodes returns an error, e.g. when there are no nodes", func() {
+			it("returns an error", func() {
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode: v1alpha1.ImpersonationProxyModeAuto,
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+				startInformersAndController()
+				r.EqualError(runControllerSync(), "no nodes found")
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ErrorDuringSetupStrategyReason, "no nodes found"))
+				requireSigningCertProviderIsEmpty()
+				requireTLSServerWasNeverStarted()
+			})
+		})
+
+		when("the impersonator start function returned by the impersonatorFunc returns an error immediately", func() {
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+				impersonatorFuncReturnedFuncError = errors.New("some immediate impersonator startup error")
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode: v1alpha1.ImpersonationProxyModeAuto,
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+			})
+
+			it("causes an immediate resync, returns an error on that next sync, and then restarts the server in a following sync", func() {
+				startInformersAndController()
+				// The failure happens in a background goroutine, so the first sync succeeds.
+				r.NoError(runControllerSync())
+				// The imperonatorFunc was called to construct an impersonator.
+				r.Equal(impersonatorFuncWasCalled, 1)
+				// Without waiting too long because we don't want the test to be slow, check if it seems like the
+				// server never started.
+				r.Never(func() bool {
+					testHTTPServerMutex.RLock() // this is to satisfy the race detector
+					defer testHTTPServerMutex.RUnlock()
+					return testHTTPServer != nil
+				}, 2*time.Second, 50*time.Millisecond)
+				r.Len(kubeAPIClient.Actions(), 3)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
+				requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+				requireCredentialIssuer(newPendingStrategyWaitingForLB())
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+				// Simulate the informer cache's background update from its watch.
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Services())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+
+				// The controller's first sync should have started a background routine which, when the server dies,
+				// requests to re-enqueue the original sync key to cause its sync method to get called again in the near future.
+				r.Eventually(func() bool {
+					queue.mutex.RLock() // this is to satisfy the race detector
+					defer queue.mutex.RUnlock()
+					return syncContext.Key == queue.key
+				}, 10*time.Second, 10*time.Millisecond)
+
+				// The next sync should error because the server died in the background. This second
+				// sync should be able to detect the error and return it.
+				r.EqualError(runControllerSync(), "some immediate impersonator startup error")
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ServerStartErrorStrategyReason, "some immediate impersonator startup error"))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+				// Next time the controller starts the server, the server will start successfully.
+				impersonatorFuncReturnedFuncError = nil
+
+				// One more sync and the controller should try to restart the server.
+				// Now everything should be working correctly.
+				r.NoError(runControllerSync())
+				requireTLSServerIsRunningWithoutCerts()
+				requireCredentialIssuer(newPendingStrategyWaitingForLB())
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+			})
+		})
+
+		when("the impersonator server dies for no apparent reason after running for a while", func() {
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode: v1alpha1.ImpersonationProxyModeAuto,
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+			})
 
-					r.NoError(runControllerSync())
-					requireTLSServerIsNoLongerRunning()
-					r.Len(kubeAPIClient.Actions(), 4)
-					requireTLSSecretWasDeleted(kubeAPIClient.Actions()[3])
-					requireCredentialIssuer(newManuallyDisabledStrategy())
+			it("causes an immediate resync, returns an error on that next sync, and then restarts the server in a following sync", func() {
+				// Prepare to be able to cause the server to die for no apparent reason.
+				testHTTPServerInterruptCh = make(chan struct{})
 
-					// only unload when disabled
-					requireSigningCertProviderIsEmpty()
-					requireTLSSecretProviderIsEmpty()
+				startInformersAndController()
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 3)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
+				requireCASecretWasCreated(kubeAPIClient.Actions()[2])
+				requireCredentialIssuer(newPendingStrategyWaitingForLB())
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+				requireTLSServerIsRunningWithoutCerts()
 
-					deleteSecretFromTracker(tlsSecretName, kubeInformerClient)
-					waitForObjectToBeDeletedFromInformer(tlsSecretName, kubeInformers.Core().V1().Secrets())
+				// Simulate the informer cache's background update from its watch.
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Services())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
 
-					// Update the CredentialIssuer again.
-					updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
-						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
-							ExternalEndpoint: fakeHostname,
-							Service: v1alpha1.ImpersonationProxyServiceSpec{
-								Type: v1alpha1.ImpersonationProxyServiceTypeNone,
-							},
-						},
-					}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+				// Simulate that impersonation server dies for no apparent reason.
+				close(testHTTPServerInterruptCh)
 
-					r.NoError(runControllerSync())
-					requireTLSServerIsRunning(ca, fakeHostname, map[string]string{fakeHostname + httpsPort: testServerAddr()})
-					r.Len(kubeAPIClient.Actions(), 5)
-					requireTLSSecretWasCreated(kubeAPIClient.Actions()[4], ca)
-					requireCredentialIssuer(newSuccessStrategy(fakeHostname, ca))
+				// The controller's first sync should have started a background routine which, when the server dies,
+				// requests to re-enqueue the original sync key to cause its sync method to get called again in the near future.
+				r.Eventually(func() bool {
+					queue.mutex.RLock() // this is to satisfy the race detector
+					defer queue.mutex.RUnlock()
+					return syncContext.Key == queue.key
+				}, 10*time.Second, 10*time.Millisecond)
 
-					// load again when enabled
-					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
-					requireTLSSecretProviderHasLoadedCerts()
-				})
+				// The next sync should error because the server died in the background. This second
+				// sync should be able to detect the error and return it.
+				r.EqualError(runControllerSync(), "unexpected shutdown of proxy server")
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ServerStartErrorStrategyReason, "unexpected shutdown of proxy server"))
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+
+				// Next time the controller starts the server, the server should behave as normal.
+				testHTTPServerInterruptCh = nil
+
+				// One more sync and the controller should try to restart the server.
+				// Now everything should be working correctly.
+				r.NoError(runControllerSync())
+				requireTLSServerIsRunningWithoutCerts()
+				requireCredentialIssuer(newPendingStrategyWaitingForLB())
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
 			})
 		})
 
-		when("the endpoint and mode switch from specified with no service, to not specified, to specified again", func() {
+		when("additional hostnames and IPs are configured for the TLS certificate", func() {
+			const fakeHostname = "fake.example.com"
+			var impersonationSpec *v1alpha1.ImpersonationProxySpec
+
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				impersonationSpec = &v1alpha1.ImpersonationProxySpec{
+					Mode:                v1alpha1.ImpersonationProxyModeEnabled,
+					ExternalEndpoint:    fakeHostname,
+					AdditionalHostnames: []string{"proxy.example.com", fakeHostname},
+					AdditionalIPs:       []string{"127.0.0.42"},
+					Service: v1alpha1.ImpersonationProxyServiceSpec{
+						Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+					},
+				}
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec:       v1alpha1.CredentialIssuerSpec{ImpersonationProxy: impersonationSpec},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+			})
+
+			it("includes them in the cert without duplicates and reissues the cert when they change", func() {
+				startInformersAndController()
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 3)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
+				requireTLSSecretHasNames(kubeAPIClient.Actions()[2], []string{"127.0.0.42"}, []string{fakeHostname, "proxy.example.com"})
+				requireTLSServerIsRunning(ca, "proxy.example.com", map[string]string{"proxy.example.com" + httpsPort: testServerAddr()})
+				requireCredentialIssuer(newSuccessStrategy(fakeHostname, ca))
+
+				// Simulate the informer cache's background update from its watch.
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Secrets())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+
+				// keeps the secret around after resync
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 3) // nothing changed
+
+				// Change the additional names.
+				impersonationSpec.AdditionalHostnames = []string{"other-proxy.example.com"}
+				impersonationSpec.AdditionalIPs = nil
+				updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
+					ImpersonationProxy: impersonationSpec,
+				}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+
+				// reissues the cert with the new names, but still advertises the same endpoint
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 5)
+				requireTLSSecretWasDeleted(kubeAPIClient.Actions()[3])
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[4], ca)
+				requireTLSSecretHasNames(kubeAPIClient.Actions()[4], []string{}, []string{fakeHostname, "other-proxy.example.com"})
+				requireCredentialIssuer(newSuccessStrategy(fakeHostname, ca))
+			})
+		})
+
+		when("a wildcard hostname is configured for the TLS certificate", func() {
+			const fakeHostname = "fake.example.com"
+			var impersonationSpec *v1alpha1.ImpersonationProxySpec
+
+			it.Before(func() {
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				impersonationSpec = &v1alpha1.ImpersonationProxySpec{
+					Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+					ExternalEndpoint: fakeHostname + ":8443",
+					WildcardHostname: true,
+					Service: v1alpha1.ImpersonationProxyServiceSpec{
+						Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+					},
+				}
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec:       v1alpha1.CredentialIssuerSpec{ImpersonationProxy: impersonationSpec},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+			})
+
+			it("includes the wildcard hostname in the cert and reissues the cert when it is toggled", func() {
+				startInformersAndController()
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 3)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
+				requireTLSSecretHasNames(kubeAPIClient.Actions()[2], []string{}, []string{fakeHostname, "*." + fakeHostname})
+				requireTLSServerIsRunning(ca, "regional."+fakeHostname, map[string]string{"regional." + fakeHostname + httpsPort: testServerAddr()})
+				requireCredentialIssuer(newSuccessStrategy(fakeHostname+":8443", ca))
+
+				// Simulate the informer cache's background update from its watch.
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Secrets())
+				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+
+				// keeps the secret around after resync
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 3) // nothing changed
+
+				// Stop requesting the wildcard hostname.
+				impersonationSpec.WildcardHostname = false
+				updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
+					ImpersonationProxy: impersonationSpec,
+				}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+
+				// reissues the cert without the wildcard hostname
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 5)
+				requireTLSSecretWasDeleted(kubeAPIClient.Actions()[3])
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[4], ca)
+				requireTLSSecretHasNames(kubeAPIClient.Actions()[4], []string{}, []string{fakeHostname})
+				requireCredentialIssuer(newSuccessStrategy(fakeHostname+":8443", ca))
+			})
+		})
+
+		when("the impersonator does not stop within the shutdown timeout", func() {
 			it.Before(func() {
 				addSecretToTrackers(signingCASecret, kubeInformerClient)
 				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
@@ -2412,788 +5581,533 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 				addNodeWithRoleToTracker("worker", kubeAPIClient)
 			})
 
-			it("doesn't create, then creates, then deletes the load balancer", func() {
-				startInformersAndController()
+			it("gives up waiting for it to stop, returns an error, and then finishes disabling on the next sync", func() {
+				// Prepare to be able to cause the server to ignore the request to stop.
+				testHTTPServerInterruptCh = make(chan struct{})
 
-				// Should have started in "enabled" mode with an "endpoint", so no load balancer is needed.
+				startInformersAndController()
 				r.NoError(runControllerSync())
 				r.Len(kubeAPIClient.Actions(), 3)
 				requireNodesListed(kubeAPIClient.Actions()[0])
-				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1]) // created immediately because "endpoint" was specified
+				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
 				requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
 				requireTLSServerIsRunning(ca, testServerAddr(), nil)
-				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
 
 				// Simulate the informer cache's background update from its watch.
 				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Secrets())
 				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
 
-				// Switch to "enabled" mode without an "endpoint", so a load balancer is needed now.
 				updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
 					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-						Mode: v1alpha1.ImpersonationProxyModeEnabled,
+						Mode: v1alpha1.ImpersonationProxyModeDisabled,
 					},
 				}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
 
-				r.NoError(runControllerSync())
-				r.Len(kubeAPIClient.Actions(), 5)
-				requireLoadBalancerWasCreated(kubeAPIClient.Actions()[3])
-				requireTLSSecretWasDeleted(kubeAPIClient.Actions()[4]) // the Secret was deleted because it contained a cert with the wrong IP
-				requireTLSServerIsRunning(ca, testServerAddr(), nil)   // serving certificate is not unloaded in this case
-				requireCredentialIssuer(newPendingStrategyWaitingForLB())
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+				// The hung server will never stop, so move the clock forward once the controller starts waiting for it.
+				go func() {
+					assert.Eventually(t, fakeClock.HasWaiters, 10*time.Second, 10*time.Millisecond)
+					fakeClock.Step(DefaultShutdownTimeout + stopGracePeriod)
+				}()
 
-				// Simulate the informer cache's background update from its watch.
-				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[3], kubeInformers.Core().V1().Services())
-				deleteSecretFromTracker(tlsSecretName, kubeInformerClient)
-				waitForObjectToBeDeletedFromInformer(tlsSecretName, kubeInformers.Core().V1().Secrets())
+				errString := "timed out waiting for impersonation proxy to stop after 15s"
+				r.EqualError(runControllerSync(), errString)
+				frozenNow = fakeClock.Now() // the status timestamps come from the clock, which was moved forward
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ServerStartErrorStrategyReason, errString))
+				r.Len(kubeAPIClient.Actions(), 3)
 
-				// The controller should be waiting for the load balancer's ingress to become available.
-				r.NoError(runControllerSync())
-				r.Len(kubeAPIClient.Actions(), 5)                    // no new actions while it is waiting for the load balancer's ingress
-				requireTLSServerIsRunning(ca, testServerAddr(), nil) // serving certificate is not unloaded in this case
-				requireCredentialIssuer(newPendingStrategyWaitingForLB())
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+				// Let the hung server finally stop in the background.
+				close(testHTTPServerInterruptCh)
+				testHTTPServerInterruptCh = nil
 
-				// Update the ingress of the LB in the informer's client and run Sync again.
-				fakeIP := "127.0.0.123"
-				updateLoadBalancerServiceInInformerAndWait(loadBalancerServiceName, []corev1.LoadBalancerIngress{{IP: fakeIP}}, kubeInformers.Core().V1().Services())
+				// The next sync should finish disabling the impersonator without waiting on the old server again.
 				r.NoError(runControllerSync())
-				r.Len(kubeAPIClient.Actions(), 6)
-				requireTLSSecretWasCreated(kubeAPIClient.Actions()[5], ca) // reuses the existing CA
-				// Check that the server is running and that TLS certs that are being served are are for fakeIP.
-				requireTLSServerIsRunning(ca, fakeIP, map[string]string{fakeIP + httpsPort: testServerAddr()})
-				requireCredentialIssuer(newSuccessStrategy(fakeIP, ca))
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+				requireTLSServerIsNoLongerRunning()
+				r.Len(kubeAPIClient.Actions(), 4)
+				requireTLSSecretWasDeleted(kubeAPIClient.Actions()[3])
+				requireCredentialIssuer(newManuallyDisabledStrategy())
+			})
+		})
 
-				// Simulate the informer cache's background update from its watch.
-				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[5], kubeInformers.Core().V1().Secrets())
+		when("the CredentialIssuer has nil impersonation spec", func() {
+			it.Before(func() {
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: nil,
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+			})
 
-				// Now switch back to having the "endpoint" specified and explicitly saying that we don't want the load balancer service.
-				updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
-					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-						Mode:             v1alpha1.ImpersonationProxyModeEnabled,
-						ExternalEndpoint: localhostIP,
-						Service: v1alpha1.ImpersonationProxyServiceSpec{
-							Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+			it("returns an error", func() {
+				startInformersAndController()
+				errString := `could not load CredentialIssuer: spec.impersonationProxy is nil`
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
+				requireSigningCertProviderIsEmpty()
+				requireTLSServerWasNeverStarted()
+			})
+		})
+
+		when("the CredentialIssuer has invalid mode", func() {
+			it.Before(func() {
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode: "not-valid",
 						},
 					},
-				}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+				}, pinnipedInformerClient, pinnipedAPIClient)
+			})
 
-				r.NoError(runControllerSync())
-				r.Len(kubeAPIClient.Actions(), 9)
-				requireServiceWasDeleted(kubeAPIClient.Actions()[6], loadBalancerServiceName)
-				requireTLSSecretWasDeleted(kubeAPIClient.Actions()[7])
-				requireTLSSecretWasCreated(kubeAPIClient.Actions()[8], ca) // recreated because the endpoint was updated, reused the old CA
-				requireTLSServerIsRunning(ca, testServerAddr(), nil)
-				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+			it("returns an error", func() {
+				startInformersAndController()
+				errString := `could not load CredentialIssuer spec.impersonationProxy: invalid proxy mode "not-valid" (expected auto, disabled, or enabled)`
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
+				requireSigningCertProviderIsEmpty()
+				requireTLSServerWasNeverStarted()
+			})
+		})
+
+		when("the CredentialIssuer has invalid service type", func() {
+			it.Before(func() {
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode: v1alpha1.ImpersonationProxyModeEnabled,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type: "not-valid",
+							},
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+			})
+
+			it("returns an error", func() {
+				startInformersAndController()
+				errString := `could not load CredentialIssuer spec.impersonationProxy: invalid service type "not-valid" (expected None, LoadBalancer, ClusterIP, or Existing)`
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
+				requireSigningCertProviderIsEmpty()
+				requireTLSServerWasNeverStarted()
 			})
 		})
 
-		when("requesting a load balancer via CredentialIssuer, then updating the annotations", func() {
+		when("the CredentialIssuer has service type Existing without an existingServiceName", func() {
 			it.Before(func() {
-				addSecretToTrackers(signingCASecret, kubeInformerClient)
 				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 					Spec: v1alpha1.CredentialIssuerSpec{
 						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
-							ExternalEndpoint: localhostIP,
+							Mode: v1alpha1.ImpersonationProxyModeEnabled,
 							Service: v1alpha1.ImpersonationProxyServiceSpec{
-								Type: v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+								Type: v1alpha1.ImpersonationProxyServiceTypeExisting,
 							},
 						},
 					},
 				}, pinnipedInformerClient, pinnipedAPIClient)
-				addNodeWithRoleToTracker("worker", kubeAPIClient)
 			})
 
-			it("creates the load balancer without annotations, then adds them", func() {
+			it("returns an error", func() {
 				startInformersAndController()
-
-				// Should have started in "enabled" mode with service type load balancer, so one is created.
-				r.NoError(runControllerSync())
-				r.Len(kubeAPIClient.Actions(), 4)
-				requireNodesListed(kubeAPIClient.Actions()[0])
-				lbService := requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
-				require.Equal(t, map[string]string(nil), lbService.Annotations) // there should be no annotations at first
-				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
-				requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
-				requireTLSServerIsRunning(ca, testServerAddr(), nil)
-				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
-
-				// Simulate another actor in the system, like a human user or a non-Pinniped controller,
-				// updating the new Service's annotations. The map was nil, so we can overwrite the whole thing,
-				lbService.Annotations = map[string]string{
-					"annotation-from-unrelated-controller-key": "annotation-from-unrelated-controller-val",
-					"my-annotation-key":                        "my-annotation-from-unrelated-controller-val",
-				}
-
-				// Simulate the informer cache's background update from its watch.
-				addObjectToKubeInformerAndWait(lbService, kubeInformers.Core().V1().Services())
-				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
-				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[3], kubeInformers.Core().V1().Secrets())
-
-				r.NoError(runControllerSync())
-				r.Len(kubeAPIClient.Actions(), 4) // no new actions because the controller decides there is nothing to update on the Service
-
-				// Add annotations to the CredentialIssuer spec.
-				credentialIssuerAnnotations := map[string]string{"my-annotation-key": "my-annotation-val"}
-				updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
-					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-						Mode:             v1alpha1.ImpersonationProxyModeEnabled,
-						ExternalEndpoint: localhostIP,
-						Service: v1alpha1.ImpersonationProxyServiceSpec{
-							Type:        v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
-							Annotations: credentialIssuerAnnotations,
-						},
-					},
-				}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
-
-				r.NoError(runControllerSync())
-				r.Len(kubeAPIClient.Actions(), 5) // one more item to update the loadbalancer
-				lbService = requireLoadBalancerWasUpdated(kubeAPIClient.Actions()[4])
-				require.Equal(t, map[string]string{
-					// Now the CredentialIssuer annotations should be merged on the load balancer.
-					// In the unlikely case where keys conflict, the CredentialIssuer value overwrites the other value.
-					// Otherwise the annotations from the other actor should not be modified.
-					"annotation-from-unrelated-controller-key":      "annotation-from-unrelated-controller-val",
-					"my-annotation-key":                             "my-annotation-val",
-					"credentialissuer.pinniped.dev/annotation-keys": `["my-annotation-key"]`,
-				}, lbService.Annotations)
-				requireTLSServerIsRunning(ca, testServerAddr(), nil)
-				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+				errString := `could not load CredentialIssuer spec.impersonationProxy: invalid service existingServiceName "" (expected the name of a Service when the service type is Existing)`
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
+				requireSigningCertProviderIsEmpty()
+				requireTLSServerWasNeverStarted()
 			})
 		})
 
-		when("requesting a cluster ip via CredentialIssuer, then updating the annotations", func() {
+		when("the CredentialIssuer has an existingServiceName which names a Service managed by the Concierge", func() {
 			it.Before(func() {
-				addSecretToTrackers(signingCASecret, kubeInformerClient)
 				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 					Spec: v1alpha1.CredentialIssuerSpec{
 						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
-							ExternalEndpoint: localhostIP,
+							Mode: v1alpha1.ImpersonationProxyModeEnabled,
 							Service: v1alpha1.ImpersonationProxyServiceSpec{
-								Type: v1alpha1.ImpersonationProxyServiceTypeClusterIP,
+								Type:                v1alpha1.ImpersonationProxyServiceTypeExisting,
+								ExistingServiceName: loadBalancerServiceName,
 							},
 						},
 					},
 				}, pinnipedInformerClient, pinnipedAPIClient)
-				addNodeWithRoleToTracker("worker", kubeAPIClient)
 			})
 
-			it("creates the cluster ip without annotations, then adds them", func() {
+			it("returns an error", func() {
 				startInformersAndController()
-
-				// Should have started in "enabled" mode with service type load balancer, so one is created.
-				r.NoError(runControllerSync())
-				r.Len(kubeAPIClient.Actions(), 4)
-				requireNodesListed(kubeAPIClient.Actions()[0])
-				clusterIPService := requireClusterIPWasCreated(kubeAPIClient.Actions()[1])
-				require.Equal(t, map[string]string(nil), clusterIPService.Annotations) // there should be no annotations at first
-				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
-				requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
-				requireTLSServerIsRunning(ca, testServerAddr(), nil)
-				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
-
-				// Simulate another actor in the system, like a human user or a non-Pinniped controller,
-				// updating the new Service's annotations.
-				clusterIPService.Annotations = map[string]string{
-					"annotation-from-unrelated-controller-key": "annotation-from-unrelated-controller-val",
-					"my-annotation-key":                        "my-annotation-from-unrelated-controller-val",
-				}
-
-				// Simulate the informer cache's background update from its watch.
-				addObjectToKubeInformerAndWait(clusterIPService, kubeInformers.Core().V1().Services())
-				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
-				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[3], kubeInformers.Core().V1().Secrets())
-
-				r.NoError(runControllerSync())
-				r.Len(kubeAPIClient.Actions(), 4) // no new actions because the controller decides there is nothing to update on the Service
-
-				// Add annotations to the CredentialIssuer spec.
-				credentialIssuerAnnotations := map[string]string{"my-annotation-key": "my-annotation-val"}
-				updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
-					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-						Mode:             v1alpha1.ImpersonationProxyModeEnabled,
-						ExternalEndpoint: localhostIP,
-						Service: v1alpha1.ImpersonationProxyServiceSpec{
-							Type:        v1alpha1.ImpersonationProxyServiceTypeClusterIP,
-							Annotations: credentialIssuerAnnotations,
-						},
-					},
-				}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
-
-				r.NoError(runControllerSync())
-				r.Len(kubeAPIClient.Actions(), 5) // one more item to update the loadbalancer
-				clusterIPService = requireClusterIPWasUpdated(kubeAPIClient.Actions()[4])
-				require.Equal(t, map[string]string{
-					// Now the CredentialIssuer annotations should be merged on the load balancer.
-					// In the unlikely case where keys conflict, the CredentialIssuer value overwrites the other value.
-					// Otherwise the annotations from the other actor should not be modified.
-					"annotation-from-unrelated-controller-key":      "annotation-from-unrelated-controller-val",
-					"my-annotation-key":                             "my-annotation-val",
-					"credentialissuer.pinniped.dev/annotation-keys": `["my-annotation-key"]`,
-				}, clusterIPService.Annotations)
-				requireTLSServerIsRunning(ca, testServerAddr(), nil)
-				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+				errString := `could not load CredentialIssuer spec.impersonationProxy: invalid service existingServiceName "` + loadBalancerServiceName + `" (must not be the name of a Service which is managed by the Concierge)`
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
+				requireSigningCertProviderIsEmpty()
+				requireTLSServerWasNeverStarted()
 			})
 		})
 
-		when("requesting a load balancer via CredentialIssuer with annotations, then updating the CredentialIssuer annotations to remove one", func() {
+		when("the CredentialIssuer has invalid LoadBalancerIP", func() {
 			it.Before(func() {
-				addSecretToTrackers(signingCASecret, kubeInformerClient)
 				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 					Spec: v1alpha1.CredentialIssuerSpec{
 						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
-							ExternalEndpoint: localhostIP,
+							Mode: v1alpha1.ImpersonationProxyModeEnabled,
 							Service: v1alpha1.ImpersonationProxyServiceSpec{
-								Type: v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
-								Annotations: map[string]string{
-									"my-initial-annotation1-key": "my-initial-annotation1-val",
-									"my-initial-annotation2-key": "my-initial-annotation2-val",
-									"my-initial-annotation3-key": "my-initial-annotation3-val",
-								},
+								LoadBalancerIP: "invalid-ip-address",
 							},
 						},
 					},
 				}, pinnipedInformerClient, pinnipedAPIClient)
-				addNodeWithRoleToTracker("worker", kubeAPIClient)
 			})
 
-			it("creates the load balancer with annotations, then removes the removed annotation", func() {
+			it("returns an error", func() {
 				startInformersAndController()
+				errString := `could not load CredentialIssuer spec.impersonationProxy: invalid LoadBalancerIP "invalid-ip-address"`
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
+				requireSigningCertProviderIsEmpty()
+				requireTLSServerWasNeverStarted()
+			})
+		})
 
-				// Should have started in "enabled" mode with service type load balancer, so one is created.
-				r.NoError(runControllerSync())
-				r.Len(kubeAPIClient.Actions(), 4)
-				requireNodesListed(kubeAPIClient.Actions()[0])
-				lbService := requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
-				require.Equal(t, map[string]string{
-					"my-initial-annotation1-key":                    "my-initial-annotation1-val",
-					"my-initial-annotation2-key":                    "my-initial-annotation2-val",
-					"my-initial-annotation3-key":                    "my-initial-annotation3-val",
-					"credentialissuer.pinniped.dev/annotation-keys": `["my-initial-annotation1-key","my-initial-annotation2-key","my-initial-annotation3-key"]`,
-				}, lbService.Annotations) // there should be some annotations at first
-				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
-				requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
-				requireTLSServerIsRunning(ca, testServerAddr(), nil)
-				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
-
-				// Simulate another actor in the system, like a human user or a non-Pinniped controller,
-				// updating the new Service to add another annotation.
-				lbService.Annotations["annotation-from-unrelated-controller-key"] = "annotation-from-unrelated-controller-val"
-
-				// Simulate the informer cache's background update from its watch.
-				addObjectToKubeInformerAndWait(lbService, kubeInformers.Core().V1().Services())
-				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
-				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[3], kubeInformers.Core().V1().Secrets())
-
-				r.NoError(runControllerSync())
-				r.Len(kubeAPIClient.Actions(), 4) // no new actions because the controller decides there is nothing to update on the Service
-
-				// Remove one of the annotations from the CredentialIssuer spec.
-				updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
-					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-						Mode:             v1alpha1.ImpersonationProxyModeEnabled,
-						ExternalEndpoint: localhostIP,
-						Service: v1alpha1.ImpersonationProxyServiceSpec{
-							Type: v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
-							Annotations: map[string]string{
-								"my-initial-annotation1-key": "my-initial-annotation1-val",
-								"my-initial-annotation3-key": "my-initial-annotation3-val",
+		when("the CredentialIssuer has invalid LoadBalancerSourceRanges", func() {
+			it.Before(func() {
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode: v1alpha1.ImpersonationProxyModeEnabled,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								LoadBalancerSourceRanges: []string{"10.0.0.0/8", "not-a-cidr"},
 							},
 						},
 					},
-				}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
-
-				r.NoError(runControllerSync())
-				r.Len(kubeAPIClient.Actions(), 5) // one more item to update the loadbalancer
-				lbService = requireLoadBalancerWasUpdated(kubeAPIClient.Actions()[4])
-				require.Equal(t, map[string]string{
-					// Now the CredentialIssuer annotations should be merged on the load balancer.
-					// Since the user removed the "my-initial-annotation2-key" key from the CredentialIssuer spec,
-					// it should be removed from the Service.
-					// The annotations from the other actor should not be modified.
-					"annotation-from-unrelated-controller-key":      "annotation-from-unrelated-controller-val",
-					"my-initial-annotation1-key":                    "my-initial-annotation1-val",
-					"my-initial-annotation3-key":                    "my-initial-annotation3-val",
-					"credentialissuer.pinniped.dev/annotation-keys": `["my-initial-annotation1-key","my-initial-annotation3-key"]`,
-				}, lbService.Annotations)
-				requireTLSServerIsRunning(ca, testServerAddr(), nil)
-				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+				}, pinnipedInformerClient, pinnipedAPIClient)
+			})
 
-				// Remove all the rest of the annotations from the CredentialIssuer spec so there are none remaining.
-				updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
-					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-						Mode:             v1alpha1.ImpersonationProxyModeEnabled,
-						ExternalEndpoint: localhostIP,
-						Service: v1alpha1.ImpersonationProxyServiceSpec{
-							Type:        v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
-							Annotations: map[string]string{},
-						},
-					},
-				}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+			it("returns an error", func() {
+				startInformersAndController()
+				errString := `could not load CredentialIssuer spec.impersonationProxy: invalid LoadBalancerSourceRanges entry "not-a-cidr": invalid CIDR address: not-a-cidr`
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
+				requireSigningCertProviderIsEmpty()
+				requireTLSServerWasNeverStarted()
+			})
+		})
 
-				r.NoError(runControllerSync())
-				r.Len(kubeAPIClient.Actions(), 6) // one more item to update the loadbalancer
-				lbService = requireLoadBalancerWasUpdated(kubeAPIClient.Actions()[5])
-				require.Equal(t, map[string]string{
-					// Since the user removed all annotations from the CredentialIssuer spec,
-					// they should all be removed from the Service, along with the special bookkeeping annotation too.
-					// The annotations from the other actor should not be modified.
-					"annotation-from-unrelated-controller-key": "annotation-from-unrelated-controller-val",
-				}, lbService.Annotations)
-				requireTLSServerIsRunning(ca, testServerAddr(), nil)
-				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+		when("the CredentialIssuer has invalid additionalHostnames", func() {
+			it.Before(func() {
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:                v1alpha1.ImpersonationProxyModeEnabled,
+							AdditionalHostnames: []string{"proxy.example.com", "Not_A_Hostname"},
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+			})
+
+			it("returns an error", func() {
+				startInformersAndController()
+				errString := `could not load CredentialIssuer spec.impersonationProxy: invalid additionalHostnames entry "Not_A_Hostname" (expected a DNS-1123 subdomain)`
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
+				requireSigningCertProviderIsEmpty()
+				requireTLSServerWasNeverStarted()
 			})
 		})
 
-		when("requesting a load balancer via CredentialIssuer, but there is already a load balancer with an invalid bookkeeping annotation value", func() {
+		when("the CredentialIssuer requests a wildcard hostname for an IP address endpoint", func() {
 			it.Before(func() {
-				addSecretToTrackers(signingCASecret, kubeInformerClient)
 				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 					Spec: v1alpha1.CredentialIssuerSpec{
 						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
 							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
 							ExternalEndpoint: localhostIP,
-							Service: v1alpha1.ImpersonationProxyServiceSpec{
-								Type:        v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
-								Annotations: map[string]string{"some-annotation": "annotation-value"},
-							},
+							WildcardHostname: true,
 						},
 					},
 				}, pinnipedInformerClient, pinnipedAPIClient)
-				addNodeWithRoleToTracker("worker", kubeAPIClient)
-				// Add a Service with a messed up bookkeeping annotation.
-				loadBalancerService := newLoadBalancerService(loadBalancerServiceName, corev1.ServiceStatus{})
-				loadBalancerService.Annotations = map[string]string{
-					annotationKeysKey: `["this is not valid json`,
-				}
-				addServiceToTrackers(loadBalancerService, kubeInformerClient, kubeAPIClient)
 			})
 
-			it("just acts like the annotation wasn't present since that is better than becoming inoperable", func() {
+			it("returns an error", func() {
 				startInformersAndController()
-
-				// Should have started in "enabled" mode with service type load balancer, so one is created.
-				r.NoError(runControllerSync())
-				r.Len(kubeAPIClient.Actions(), 4)
-				requireNodesListed(kubeAPIClient.Actions()[0])
-				lbService := requireLoadBalancerWasUpdated(kubeAPIClient.Actions()[1])
-				require.Equal(t, map[string]string{
-					"some-annotation": "annotation-value",
-					"credentialissuer.pinniped.dev/annotation-keys": `["some-annotation"]`,
-				}, lbService.Annotations)
-				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
-				requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
-				requireTLSServerIsRunning(ca, testServerAddr(), nil)
-				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+				errString := `could not load CredentialIssuer spec.impersonationProxy: wildcardHostname requires externalEndpoint to be a hostname rather than an IP address`
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
+				requireSigningCertProviderIsEmpty()
+				requireTLSServerWasNeverStarted()
 			})
 		})
 
-		when("requesting a load balancer via CredentialIssuer, then adding a static loadBalancerIP to the spec", func() {
+		when("the CredentialIssuer has invalid additionalIPs", func() {
 			it.Before(func() {
-				addSecretToTrackers(signingCASecret, kubeInformerClient)
 				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 					Spec: v1alpha1.CredentialIssuerSpec{
 						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
-							ExternalEndpoint: localhostIP,
-							Service: v1alpha1.ImpersonationProxyServiceSpec{
-								Type: v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
-							},
+							Mode:          v1alpha1.ImpersonationProxyModeEnabled,
+							AdditionalIPs: []string{"127.0.0.1", "not-an-ip"},
 						},
 					},
 				}, pinnipedInformerClient, pinnipedAPIClient)
-				addNodeWithRoleToTracker("worker", kubeAPIClient)
 			})
 
-			it("creates the load balancer without loadBalancerIP set, then adds it", func() {
+			it("returns an error", func() {
 				startInformersAndController()
+				errString := `could not load CredentialIssuer spec.impersonationProxy: invalid additionalIPs entry "not-an-ip" (expected an IPv4 or IPv6 address)`
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
+				requireSigningCertProviderIsEmpty()
+				requireTLSServerWasNeverStarted()
+			})
+		})
 
-				// Should have started in "enabled" mode with service type load balancer, so one is created.
-				r.NoError(runControllerSync())
-				r.Len(kubeAPIClient.Actions(), 4)
-				requireNodesListed(kubeAPIClient.Actions()[0])
-				lbService := requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
-				require.Equal(t, map[string]string(nil), lbService.Annotations) // there should be no annotations at first
-				require.Equal(t, "", lbService.Spec.LoadBalancerIP)
-				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
-				requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)
-				requireTLSServerIsRunning(ca, testServerAddr(), nil)
-				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
-
-				// Simulate the informer cache's background update from its watch.
-				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Services())
-				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
-				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[3], kubeInformers.Core().V1().Secrets())
-
-				// Add annotations to the spec.
-				loadBalancerIP := "1.2.3.4"
-				updateCredentialIssuerInInformerAndWait(credentialIssuerResourceName, v1alpha1.CredentialIssuerSpec{
-					ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-						Mode:             v1alpha1.ImpersonationProxyModeEnabled,
-						ExternalEndpoint: localhostIP,
-						Service: v1alpha1.ImpersonationProxyServiceSpec{
-							Type:           v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
-							LoadBalancerIP: loadBalancerIP,
+		when("the CredentialIssuer has invalid externalTrafficPolicy", func() {
+			it.Before(func() {
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode: v1alpha1.ImpersonationProxyModeEnabled,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								ExternalTrafficPolicy: "Everywhere",
+							},
 						},
 					},
-				}, pinnipedInformers.Config().V1alpha1().CredentialIssuers())
+				}, pinnipedInformerClient, pinnipedAPIClient)
+			})
 
-				r.NoError(runControllerSync())
-				r.Len(kubeAPIClient.Actions(), 5) // one more item to update the loadbalancer
-				lbService = requireLoadBalancerWasUpdated(kubeAPIClient.Actions()[4])
-				require.Equal(t, loadBalancerIP, lbService.Spec.LoadBalancerIP)
-				requireTLSServerIsRunning(ca, testServerAddr(), nil)
-				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+			it("returns an error", func() {
+				startInformersAndController()
+				errString := `could not load CredentialIssuer spec.impersonationProxy: invalid service externalTrafficPolicy "Everywhere" (expected Cluster or Local)`
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
+				requireSigningCertProviderIsEmpty()
+				requireTLSServerWasNeverStarted()
 			})
 		})
 
-		when("sync is called more than once", func() {
+		when("the CredentialIssuer has invalid preferredAddressType", func() {
 			it.Before(func() {
-				addSecretToTrackers(signingCASecret, kubeInformerClient)
-				addNodeWithRoleToTracker("worker", kubeAPIClient)
 				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 					Spec: v1alpha1.CredentialIssuerSpec{
 						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-							Mode: v1alpha1.ImpersonationProxyModeAuto,
+							Mode: v1alpha1.ImpersonationProxyModeEnabled,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								PreferredAddressType: "Both",
+							},
 						},
 					},
 				}, pinnipedInformerClient, pinnipedAPIClient)
 			})
 
-			it("only starts the impersonator once and only lists the cluster's nodes once", func() {
+			it("returns an error", func() {
 				startInformersAndController()
-				r.NoError(runControllerSync())
-				r.Len(kubeAPIClient.Actions(), 3)
-				requireNodesListed(kubeAPIClient.Actions()[0])
-				requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
-				requireCASecretWasCreated(kubeAPIClient.Actions()[2])
-				requireTLSServerIsRunningWithoutCerts()
-				requireCredentialIssuer(newPendingStrategyWaitingForLB())
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
-
-				// Simulate the informer cache's background update from its watch.
-				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Services())
-				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
+				errString := `could not load CredentialIssuer spec.impersonationProxy: invalid service preferredAddressType "Both" (expected Hostname, IP, IPv4, or IPv6)`
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
+				requireSigningCertProviderIsEmpty()
+				requireTLSServerWasNeverStarted()
+			})
+		})
 
-				r.NoError(runControllerSync())
-				r.Equal(1, impersonatorFuncWasCalled)   // wasn't started a second time
-				requireTLSServerIsRunningWithoutCerts() // still running
-				requireCredentialIssuer(newPendingStrategyWaitingForLB())
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
-				r.Len(kubeAPIClient.Actions(), 3) // no new API calls
+		when("the CredentialIssuer has invalid port", func() {
+			it.Before(func() {
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode: v1alpha1.ImpersonationProxyModeEnabled,
+							Port: pointer.Int32Ptr(0),
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
 			})
 
-			it("creates certs from the ip address listed on the load balancer", func() {
+			it("returns an error", func() {
 				startInformersAndController()
-				r.NoError(runControllerSync())
-				r.Len(kubeAPIClient.Actions(), 3)
-				requireNodesListed(kubeAPIClient.Actions()[0])
-				requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
-				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
-				requireTLSServerIsRunningWithoutCerts()
-				requireCredentialIssuer(newPendingStrategyWaitingForLB())
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
-
-				// Simulate the informer cache's background update from its watch.
-				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Services())
-				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
-
-				updateLoadBalancerServiceInInformerAndWait(loadBalancerServiceName, []corev1.LoadBalancerIngress{{IP: localhostIP}}, kubeInformers.Core().V1().Services())
-
-				r.NoError(runControllerSync())
-				r.Equal(1, impersonatorFuncWasCalled) // wasn't started a second time
-				r.Len(kubeAPIClient.Actions(), 4)
-				requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca) // uses the ca from last time
-				requireTLSServerIsRunning(ca, testServerAddr(), nil)       // running with certs now
-				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
-
-				// Simulate the informer cache's background update from its watch.
-				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[3], kubeInformers.Core().V1().Secrets())
+				errString := `could not load CredentialIssuer spec.impersonationProxy: invalid port 0 (expected a value between 1 and 65535)`
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
+				requireSigningCertProviderIsEmpty()
+				requireTLSServerWasNeverStarted()
+			})
+		})
 
-				r.NoError(runControllerSync())
-				r.Equal(1, impersonatorFuncWasCalled)                // wasn't started again
-				r.Len(kubeAPIClient.Actions(), 4)                    // no more actions
-				requireTLSServerIsRunning(ca, testServerAddr(), nil) // still running
-				requireCredentialIssuer(newSuccessStrategy(localhostIP, ca))
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+		when("the CredentialIssuer has invalid service port", func() {
+			it.Before(func() {
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:    v1alpha1.ImpersonationProxyModeEnabled,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{Type: v1alpha1.ImpersonationProxyServiceTypeLoadBalancer, Port: pointer.Int32Ptr(65536)},
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
 			})
 
-			it("creates certs from the hostname listed on the load balancer", func() {
-				hostname := "fake.example.com"
+			it("returns an error", func() {
 				startInformersAndController()
-				r.NoError(runControllerSync())
-				r.Len(kubeAPIClient.Actions(), 3)
-				requireNodesListed(kubeAPIClient.Actions()[0])
-				requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
-				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[2])
-				requireTLSServerIsRunningWithoutCerts()
-				requireCredentialIssuer(newPendingStrategyWaitingForLB())
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
-
-				// Simulate the informer cache's background update from its watch.
-				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Services())
-				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
-
-				updateLoadBalancerServiceInInformerAndWait(loadBalancerServiceName, []corev1.LoadBalancerIngress{{IP: localhostIP, Hostname: hostname}}, kubeInformers.Core().V1().Services())
-
-				r.NoError(runControllerSync())
-				r.Equal(1, impersonatorFuncWasCalled) // wasn't started a second time
-				r.Len(kubeAPIClient.Actions(), 4)
-				requireTLSSecretWasCreated(kubeAPIClient.Actions()[3], ca)                                         // uses the ca from last time
-				requireTLSServerIsRunning(ca, hostname, map[string]string{hostname + httpsPort: testServerAddr()}) // running with certs now
-				requireCredentialIssuer(newSuccessStrategy(hostname, ca))
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
-
-				// Simulate the informer cache's background update from its watch.
-				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[3], kubeInformers.Core().V1().Secrets())
-
-				r.NoError(runControllerSync())
-				r.Equal(1, impersonatorFuncWasCalled)                                                              // wasn't started a third time
-				r.Len(kubeAPIClient.Actions(), 4)                                                                  // no more actions
-				requireTLSServerIsRunning(ca, hostname, map[string]string{hostname + httpsPort: testServerAddr()}) // still running
-				requireCredentialIssuer(newSuccessStrategy(hostname, ca))
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+				errString := `could not load CredentialIssuer spec.impersonationProxy: invalid service port 65536 (expected a value between 1 and 65535)`
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
+				requireSigningCertProviderIsEmpty()
+				requireTLSServerWasNeverStarted()
 			})
 		})
 
-		when("there is already a CredentialIssuer", func() {
-			preExistingStrategy := v1alpha1.CredentialIssuerStrategy{
-				Type:           v1alpha1.KubeClusterSigningCertificateStrategyType,
-				Status:         v1alpha1.SuccessStrategyStatus,
-				Reason:         v1alpha1.FetchedKeyStrategyReason,
-				Message:        "happy other unrelated strategy",
-				LastUpdateTime: metav1.NewTime(frozenNow),
-				Frontend: &v1alpha1.CredentialIssuerFrontend{
-					Type: v1alpha1.TokenCredentialRequestAPIFrontendType,
-				},
-			}
+		when("the CredentialIssuer has invalid service clusterIP", func() {
+			it.Before(func() {
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:    v1alpha1.ImpersonationProxyModeEnabled,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{Type: v1alpha1.ImpersonationProxyServiceTypeClusterIP, ClusterIP: "None"},
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+			})
+
+			it("returns an error", func() {
+				startInformersAndController()
+				errString := `could not load CredentialIssuer spec.impersonationProxy: invalid service clusterIP "None" (expected an IPv4 or IPv6 address)`
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
+				requireSigningCertProviderIsEmpty()
+				requireTLSServerWasNeverStarted()
+			})
+		})
 
+		when("the CredentialIssuer has a CA certificate lifetime which is too short", func() {
 			it.Before(func() {
-				addSecretToTrackers(signingCASecret, kubeInformerClient)
 				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 					Spec: v1alpha1.CredentialIssuerSpec{
 						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-							Mode: v1alpha1.ImpersonationProxyModeAuto,
-						},
-					},
-					Status: v1alpha1.CredentialIssuerStatus{
-						Strategies: []v1alpha1.CredentialIssuerStrategy{
-							preExistingStrategy,
+							Mode:                  v1alpha1.ImpersonationProxyModeEnabled,
+							CACertificateLifetime: &metav1.Duration{Duration: 30 * time.Minute},
 						},
 					},
 				}, pinnipedInformerClient, pinnipedAPIClient)
-				addNodeWithRoleToTracker("worker", kubeAPIClient)
 			})
 
-			it("merges into the existing strategy array on the CredentialIssuer", func() {
+			it("returns an error", func() {
 				startInformersAndController()
-				r.NoError(runControllerSync())
-				requireTLSServerIsRunningWithoutCerts()
-				r.Len(kubeAPIClient.Actions(), 3)
-				requireNodesListed(kubeAPIClient.Actions()[0])
-				requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
-				requireCASecretWasCreated(kubeAPIClient.Actions()[2])
-				credentialIssuer := getCredentialIssuer()
-				r.Equal([]v1alpha1.CredentialIssuerStrategy{preExistingStrategy, newPendingStrategyWaitingForLB()}, credentialIssuer.Status.Strategies)
+				errString := `could not load CredentialIssuer spec.impersonationProxy: invalid caCertificateLifetime "30m0s" (expected at least 1h0m0s)`
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
+				requireSigningCertProviderIsEmpty()
+				requireTLSServerWasNeverStarted()
 			})
 		})
 
-		when("getting the control plane nodes returns an error, e.g. when there are no nodes", func() {
-			it("returns an error", func() {
+		when("the CredentialIssuer has a CA certificate subject with an empty commonName", func() {
+			it.Before(func() {
 				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 					Spec: v1alpha1.CredentialIssuerSpec{
 						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-							Mode: v1alpha1.ImpersonationProxyModeAuto,
+							Mode:                 v1alpha1.ImpersonationProxyModeEnabled,
+							CACertificateSubject: &v1alpha1.ImpersonationProxyCASubject{CommonName: "  ", Organization: "Some Org"},
 						},
 					},
 				}, pinnipedInformerClient, pinnipedAPIClient)
+			})
+
+			it("returns an error", func() {
 				startInformersAndController()
-				r.EqualError(runControllerSync(), "no nodes found")
-				requireCredentialIssuer(newErrorStrategy("no nodes found"))
+				errString := `could not load CredentialIssuer spec.impersonationProxy: invalid caCertificateSubject commonName "  " (expected a non-empty value of at most 64 characters)`
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
 				requireSigningCertProviderIsEmpty()
 				requireTLSServerWasNeverStarted()
 			})
 		})
 
-		when("the impersonator start function returned by the impersonatorFunc returns an error immediately", func() {
+		when("the CredentialIssuer has a CA certificate subject with a commonName which is too long", func() {
 			it.Before(func() {
-				addSecretToTrackers(signingCASecret, kubeInformerClient)
-				addNodeWithRoleToTracker("worker", kubeAPIClient)
-				impersonatorFuncReturnedFuncError = errors.New("some immediate impersonator startup error")
 				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 					Spec: v1alpha1.CredentialIssuerSpec{
 						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-							Mode: v1alpha1.ImpersonationProxyModeAuto,
+							Mode:                 v1alpha1.ImpersonationProxyModeEnabled,
+							CACertificateSubject: &v1alpha1.ImpersonationProxyCASubject{CommonName: strings.Repeat("x", 65)},
 						},
 					},
 				}, pinnipedInformerClient, pinnipedAPIClient)
 			})
 
-			it("causes an immediate resync, returns an error on that next sync, and then restarts the server in a following sync", func() {
+			it("returns an error", func() {
 				startInformersAndController()
-				// The failure happens in a background goroutine, so the first sync succeeds.
-				r.NoError(runControllerSync())
-				// The imperonatorFunc was called to construct an impersonator.
-				r.Equal(impersonatorFuncWasCalled, 1)
-				// Without waiting too long because we don't want the test to be slow, check if it seems like the
-				// server never started.
-				r.Never(func() bool {
-					testHTTPServerMutex.RLock() // this is to satisfy the race detector
-					defer testHTTPServerMutex.RUnlock()
-					return testHTTPServer != nil
-				}, 2*time.Second, 50*time.Millisecond)
-				r.Len(kubeAPIClient.Actions(), 3)
-				requireNodesListed(kubeAPIClient.Actions()[0])
-				requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
-				requireCASecretWasCreated(kubeAPIClient.Actions()[2])
-				requireCredentialIssuer(newPendingStrategyWaitingForLB())
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
-
-				// Simulate the informer cache's background update from its watch.
-				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Services())
-				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
-
-				// The controller's first sync should have started a background routine which, when the server dies,
-				// requests to re-enqueue the original sync key to cause its sync method to get called again in the near future.
-				r.Eventually(func() bool {
-					queue.mutex.RLock() // this is to satisfy the race detector
-					defer queue.mutex.RUnlock()
-					return syncContext.Key == queue.key
-				}, 10*time.Second, 10*time.Millisecond)
-
-				// The next sync should error because the server died in the background. This second
-				// sync should be able to detect the error and return it.
-				r.EqualError(runControllerSync(), "some immediate impersonator startup error")
-				requireCredentialIssuer(newErrorStrategy("some immediate impersonator startup error"))
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
-
-				// Next time the controller starts the server, the server will start successfully.
-				impersonatorFuncReturnedFuncError = nil
-
-				// One more sync and the controller should try to restart the server.
-				// Now everything should be working correctly.
-				r.NoError(runControllerSync())
-				requireTLSServerIsRunningWithoutCerts()
-				requireCredentialIssuer(newPendingStrategyWaitingForLB())
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+				errString := `could not load CredentialIssuer spec.impersonationProxy: invalid caCertificateSubject commonName "` + strings.Repeat("x", 65) + `" (expected a non-empty value of at most 64 characters)`
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
+				requireSigningCertProviderIsEmpty()
+				requireTLSServerWasNeverStarted()
 			})
 		})
 
-		when("the impersonator server dies for no apparent reason after running for a while", func() {
+		when("the CredentialIssuer has a CA certificate subject with an organization which is too long", func() {
 			it.Before(func() {
-				addSecretToTrackers(signingCASecret, kubeInformerClient)
-				addNodeWithRoleToTracker("worker", kubeAPIClient)
 				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 					Spec: v1alpha1.CredentialIssuerSpec{
 						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-							Mode: v1alpha1.ImpersonationProxyModeAuto,
+							Mode:                 v1alpha1.ImpersonationProxyModeEnabled,
+							CACertificateSubject: &v1alpha1.ImpersonationProxyCASubject{CommonName: "Some CA", Organization: strings.Repeat("x", 65)},
 						},
 					},
 				}, pinnipedInformerClient, pinnipedAPIClient)
 			})
 
-			it("causes an immediate resync, returns an error on that next sync, and then restarts the server in a following sync", func() {
-				// Prepare to be able to cause the server to die for no apparent reason.
-				testHTTPServerInterruptCh = make(chan struct{})
-
+			it("returns an error", func() {
 				startInformersAndController()
-				r.NoError(runControllerSync())
-				r.Len(kubeAPIClient.Actions(), 3)
-				requireNodesListed(kubeAPIClient.Actions()[0])
-				requireLoadBalancerWasCreated(kubeAPIClient.Actions()[1])
-				requireCASecretWasCreated(kubeAPIClient.Actions()[2])
-				requireCredentialIssuer(newPendingStrategyWaitingForLB())
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
-				requireTLSServerIsRunningWithoutCerts()
-
-				// Simulate the informer cache's background update from its watch.
-				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[1], kubeInformers.Core().V1().Services())
-				addObjectFromCreateActionToInformerAndWait(kubeAPIClient.Actions()[2], kubeInformers.Core().V1().Secrets())
-
-				// Simulate that impersonation server dies for no apparent reason.
-				close(testHTTPServerInterruptCh)
-
-				// The controller's first sync should have started a background routine which, when the server dies,
-				// requests to re-enqueue the original sync key to cause its sync method to get called again in the near future.
-				r.Eventually(func() bool {
-					queue.mutex.RLock() // this is to satisfy the race detector
-					defer queue.mutex.RUnlock()
-					return syncContext.Key == queue.key
-				}, 10*time.Second, 10*time.Millisecond)
-
-				// The next sync should error because the server died in the background. This second
-				// sync should be able to detect the error and return it.
-				r.EqualError(runControllerSync(), "unexpected shutdown of proxy server")
-				requireCredentialIssuer(newErrorStrategy("unexpected shutdown of proxy server"))
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
-
-				// Next time the controller starts the server, the server should behave as normal.
-				testHTTPServerInterruptCh = nil
-
-				// One more sync and the controller should try to restart the server.
-				// Now everything should be working correctly.
-				r.NoError(runControllerSync())
-				requireTLSServerIsRunningWithoutCerts()
-				requireCredentialIssuer(newPendingStrategyWaitingForLB())
-				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
+				errString := `could not load CredentialIssuer spec.impersonationProxy: invalid caCertificateSubject organization "` + strings.Repeat("x", 65) + `" (expected at most 64 characters)`
+				r.EqualError(runControllerSync(), errString)
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
+				requireSigningCertProviderIsEmpty()
+				requireTLSServerWasNeverStarted()
 			})
 		})
 
-		when("the CredentialIssuer has nil impersonation spec", func() {
+		when("the CredentialIssuer has invalid CA certificate renewal threshold", func() {
 			it.Before(func() {
 				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 					Spec: v1alpha1.CredentialIssuerSpec{
-						ImpersonationProxy: nil,
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode:                                 v1alpha1.ImpersonationProxyModeEnabled,
+							CACertificateRenewalThresholdPercent: pointer.Int32Ptr(100),
+						},
 					},
 				}, pinnipedInformerClient, pinnipedAPIClient)
 			})
 
 			it("returns an error", func() {
 				startInformersAndController()
-				errString := `could not load CredentialIssuer: spec.impersonationProxy is nil`
+				errString := `could not load CredentialIssuer spec.impersonationProxy: invalid caCertificateRenewalThresholdPercent 100 (expected a value between 1 and 99)`
 				r.EqualError(runControllerSync(), errString)
-				requireCredentialIssuer(newErrorStrategy(errString))
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
 				requireSigningCertProviderIsEmpty()
 				requireTLSServerWasNeverStarted()
 			})
 		})
 
-		when("the CredentialIssuer has invalid mode", func() {
+		when("the CredentialIssuer has invalid ExternalEndpoint", func() {
 			it.Before(func() {
 				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 					Spec: v1alpha1.CredentialIssuerSpec{
 						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-							Mode: "not-valid",
+							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
+							ExternalEndpoint: "[invalid",
 						},
 					},
 				}, pinnipedInformerClient, pinnipedAPIClient)
@@ -3201,94 +6115,122 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 
 			it("returns an error", func() {
 				startInformersAndController()
-				errString := `could not load CredentialIssuer spec.impersonationProxy: invalid proxy mode "not-valid" (expected auto, disabled, or enabled)`
+				errString := `could not load CredentialIssuer spec.impersonationProxy: invalid ExternalEndpoint "[invalid": address [invalid:443: missing ']' in address`
 				r.EqualError(runControllerSync(), errString)
-				requireCredentialIssuer(newErrorStrategy(errString))
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ValidationErrorStrategyReason, errString))
 				requireSigningCertProviderIsEmpty()
 				requireTLSServerWasNeverStarted()
 			})
 		})
 
-		when("the CredentialIssuer has invalid service type", func() {
+		when("the load balancer already exists but is not yet in the informer cache", func() {
 			it.Before(func() {
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+				kubeAPIClient.PrependReactor("create", "services", func(action coretesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, nil, k8serrors.NewAlreadyExists(
+						action.GetResource().GroupResource(),
+						action.(coretesting.CreateAction).GetObject().(*corev1.Service).Name,
+					)
+				})
 				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 					Spec: v1alpha1.CredentialIssuerSpec{
 						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-							Mode: v1alpha1.ImpersonationProxyModeEnabled,
-							Service: v1alpha1.ImpersonationProxyServiceSpec{
-								Type: "not-valid",
-							},
+							Mode: v1alpha1.ImpersonationProxyModeAuto,
 						},
 					},
 				}, pinnipedInformerClient, pinnipedAPIClient)
 			})
 
-			it("returns an error", func() {
+			it("requeues and says that it is waiting for the Service to propagate", func() {
 				startInformersAndController()
-				errString := `could not load CredentialIssuer spec.impersonationProxy: invalid service type "not-valid" (expected None, LoadBalancer, or ClusterIP)`
-				r.EqualError(runControllerSync(), errString)
-				requireCredentialIssuer(newErrorStrategy(errString))
+				r.Equal(controllerlib.ErrSyntheticRequeue, runControllerSync())
+				requireCredentialIssuer(newPendingStrategy(`waiting for Service to propagate to the informer cache: services "some-service-resource-name" already exists`))
 				requireSigningCertProviderIsEmpty()
-				requireTLSServerWasNeverStarted()
+				requireTLSServerIsRunningWithoutCerts()
 			})
 		})
 
-		when("the CredentialIssuer has invalid LoadBalancerIP", func() {
+		when("the cluster ip already exists but is not yet in the informer cache", func() {
 			it.Before(func() {
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+				kubeAPIClient.PrependReactor("create", "services", func(action coretesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, nil, k8serrors.NewAlreadyExists(
+						action.GetResource().GroupResource(),
+						action.(coretesting.CreateAction).GetObject().(*corev1.Service).Name,
+					)
+				})
 				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 					Spec: v1alpha1.CredentialIssuerSpec{
 						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
-							Mode: v1alpha1.ImpersonationProxyModeEnabled,
+							Mode: v1alpha1.ImpersonationProxyModeAuto,
 							Service: v1alpha1.ImpersonationProxyServiceSpec{
-								LoadBalancerIP: "invalid-ip-address",
+								Type: v1alpha1.ImpersonationProxyServiceTypeClusterIP,
 							},
 						},
 					},
 				}, pinnipedInformerClient, pinnipedAPIClient)
 			})
 
-			it("returns an error", func() {
+			it("requeues and says that it is waiting for the Service to propagate", func() {
 				startInformersAndController()
-				errString := `could not load CredentialIssuer spec.impersonationProxy: invalid LoadBalancerIP "invalid-ip-address"`
-				r.EqualError(runControllerSync(), errString)
-				requireCredentialIssuer(newErrorStrategy(errString))
+				r.Equal(controllerlib.ErrSyntheticRequeue, runControllerSync())
+				requireCredentialIssuer(newPendingStrategy(`waiting for Service to propagate to the informer cache: services "some-cluster-ip-resource-name" already exists`))
 				requireSigningCertProviderIsEmpty()
-				requireTLSServerWasNeverStarted()
+				requireTLSServerIsRunningWithoutCerts()
 			})
 		})
 
-		when("the CredentialIssuer has invalid ExternalEndpoint", func() {
+		when("the sync runs out of time while waiting for the API server", func() {
 			it.Before(func() {
+				syncTimeout = 100 * time.Millisecond
+				addSecretToTrackers(signingCASecret, kubeInformerClient)
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+				kubeAPIClient.PrependReactor("list", "nodes", func(action coretesting.Action) (handled bool, ret runtime.Object, err error) {
+					time.Sleep(2 * syncTimeout) // simulate an unresponsive API server
+					return false, nil, nil
+				})
 				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
 					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
 					Spec: v1alpha1.CredentialIssuerSpec{
 						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
 							Mode:             v1alpha1.ImpersonationProxyModeEnabled,
-							ExternalEndpoint: "[invalid",
+							ExternalEndpoint: localhostIP,
+							Service: v1alpha1.ImpersonationProxyServiceSpec{
+								Type: v1alpha1.ImpersonationProxyServiceTypeNone,
+							},
 						},
 					},
 				}, pinnipedInformerClient, pinnipedAPIClient)
 			})
 
-			it("returns an error", func() {
+			it("returns an error without generating certificates, and finishes on the next sync", func() {
 				startInformersAndController()
-				errString := `could not load CredentialIssuer spec.impersonationProxy: invalid ExternalEndpoint "[invalid": address [invalid:443: missing ']' in address`
+				errString := "sync did not finish within 100ms: context deadline exceeded"
 				r.EqualError(runControllerSync(), errString)
-				requireCredentialIssuer(newErrorStrategy(errString))
+				r.Len(kubeAPIClient.Actions(), 1)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ErrorDuringSetupStrategyReason, errString))
 				requireSigningCertProviderIsEmpty()
-				requireTLSServerWasNeverStarted()
+				requireTLSServerIsRunningWithoutCerts()
+
+				// The nodes were already listed, so the next sync does not need to wait for the API server.
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 3)
+				ca := requireCASecretWasCreated(kubeAPIClient.Actions()[1])
+				requireTLSSecretWasCreated(kubeAPIClient.Actions()[2], ca)
+				requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
 			})
 		})
 
-		when("there is an error creating the load balancer", func() {
+		when("the CA Secret already exists but is not yet in the informer cache", func() {
 			it.Before(func() {
 				addNodeWithRoleToTracker("worker", kubeAPIClient)
-				kubeAPIClient.PrependReactor("create", "services", func(action coretesting.Action) (handled bool, ret runtime.Object, err error) {
+				kubeAPIClient.PrependReactor("create", "secrets", func(action coretesting.Action) (handled bool, ret runtime.Object, err error) {
 					return true, nil, k8serrors.NewAlreadyExists(
 						action.GetResource().GroupResource(),
-						action.(coretesting.CreateAction).GetObject().(*corev1.Service).Name,
+						action.(coretesting.CreateAction).GetObject().(*corev1.Secret).Name,
 					)
 				})
 				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
@@ -3301,15 +6243,44 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 				}, pinnipedInformerClient, pinnipedAPIClient)
 			})
 
-			it("returns an error", func() {
+			it("requeues and says that it is waiting for the Secret to propagate", func() {
 				startInformersAndController()
-				r.EqualError(runControllerSync(), `services "some-service-resource-name" already exists`)
-				requireCredentialIssuer(newPendingStrategy(`services "some-service-resource-name" already exists`))
+				errString := `secrets "some-ca-secret-name" already exists`
+				r.Equal(controllerlib.ErrSyntheticRequeue, runControllerSync())
+				requireCredentialIssuer(newPendingStrategy("waiting for Secret to propagate to the informer cache: " + errString))
 				requireSigningCertProviderIsEmpty()
 				requireTLSServerIsRunningWithoutCerts()
 			})
 		})
 
+		when("the load balancer was already deleted but is still in the informer cache", func() {
+			it.Before(func() {
+				addNodeWithRoleToTracker("worker", kubeAPIClient)
+				kubeAPIClient.PrependReactor("delete", "services", func(action coretesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, nil, k8serrors.NewNotFound(action.GetResource().GroupResource(), action.(coretesting.DeleteAction).GetName())
+				})
+				addCredentialIssuerToTrackers(v1alpha1.CredentialIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: credentialIssuerResourceName},
+					Spec: v1alpha1.CredentialIssuerSpec{
+						ImpersonationProxy: &v1alpha1.ImpersonationProxySpec{
+							Mode: v1alpha1.ImpersonationProxyModeDisabled,
+						},
+					},
+				}, pinnipedInformerClient, pinnipedAPIClient)
+				addLoadBalancerServiceToTracker(loadBalancerServiceName, kubeInformerClient)
+			})
+
+			it("treats the deletion as successful", func() {
+				startInformersAndController()
+				r.NoError(runControllerSync())
+				r.Len(kubeAPIClient.Actions(), 2)
+				requireNodesListed(kubeAPIClient.Actions()[0])
+				requireServiceWasDeleted(kubeAPIClient.Actions()[1], loadBalancerServiceName)
+				requireCredentialIssuer(newManuallyDisabledStrategy())
+				requireSigningCertProviderIsEmpty()
+			})
+		})
+
 		when("there is an error deleting the load balancer", func() {
 			it.Before(func() {
 				addNodeWithRoleToTracker("worker", kubeAPIClient)
@@ -3331,7 +6302,7 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 			it("returns an error", func() {
 				startInformersAndController()
 				r.EqualError(runControllerSync(), "error on delete")
-				requireCredentialIssuer(newErrorStrategy("error on delete"))
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ServiceErrorStrategyReason, "error on delete"))
 				requireSigningCertProviderIsEmpty()
 			})
 		})
@@ -3358,7 +6329,7 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 			it("returns an error", func() {
 				startInformersAndController()
 				r.EqualError(runControllerSync(), "error on create")
-				requireCredentialIssuer(newErrorStrategy("error on create"))
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ServiceErrorStrategyReason, "error on create"))
 				requireSigningCertProviderIsEmpty()
 				requireTLSServerIsRunningWithoutCerts()
 			})
@@ -3389,7 +6360,7 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 			it("returns an error", func() {
 				startInformersAndController()
 				r.EqualError(runControllerSync(), "error on update")
-				requireCredentialIssuer(newErrorStrategy("error on update"))
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ServiceErrorStrategyReason, "error on update"))
 				requireSigningCertProviderIsEmpty()
 				requireTLSServerIsRunningWithoutCerts()
 			})
@@ -3416,7 +6387,7 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 			it("returns an error", func() {
 				startInformersAndController()
 				r.EqualError(runControllerSync(), "error on delete")
-				requireCredentialIssuer(newErrorStrategy("error on delete"))
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.ServiceErrorStrategyReason, "error on delete"))
 				requireSigningCertProviderIsEmpty()
 			})
 		})
@@ -3448,7 +6419,7 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 			it("starts the impersonator without certs and returns an error", func() {
 				startInformersAndController()
 				r.EqualError(runControllerSync(), "error on tls secret create")
-				requireCredentialIssuer(newErrorStrategy("error on tls secret create"))
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.SecretErrorStrategyReason, "error on tls secret create"))
 				requireSigningCertProviderIsEmpty()
 				requireTLSServerIsRunningWithoutCerts()
 				r.Len(kubeAPIClient.Actions(), 3)
@@ -3485,7 +6456,7 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 			it("starts the impersonator without certs and returns an error", func() {
 				startInformersAndController()
 				r.EqualError(runControllerSync(), "error on ca secret create")
-				requireCredentialIssuer(newErrorStrategy("error on ca secret create"))
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.CAErrorStrategyReason, "error on ca secret create"))
 				requireSigningCertProviderIsEmpty()
 				requireTLSServerIsRunningWithoutCerts()
 				r.Len(kubeAPIClient.Actions(), 2)
@@ -3516,7 +6487,7 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 				startInformersAndController()
 				errString := "could not load CA: tls: failed to find any PEM data in certificate input"
 				r.EqualError(runControllerSync(), errString)
-				requireCredentialIssuer(newErrorStrategy(errString))
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.CAErrorStrategyReason, errString))
 				requireSigningCertProviderIsEmpty()
 				requireTLSServerIsRunningWithoutCerts()
 				r.Len(kubeAPIClient.Actions(), 1)
@@ -3546,7 +6517,7 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 
 			it("does not start the impersonator, deletes the loadbalancer, returns an error", func() {
 				r.EqualError(runControllerSync(), "error on delete")
-				requireCredentialIssuer(newErrorStrategy("error on delete"))
+				requireCredentialIssuer(newErrorStrategy(v1alpha1.SecretErrorStrategyReason, "error on delete"))
 				requireSigningCertProviderIsEmpty()
 				requireTLSServerWasNeverStarted()
 				r.Len(kubeAPIClient.Actions(), 3)
@@ -3629,7 +6600,7 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 					startInformersAndController()
 					errString := "PEM data represented an invalid cert, but got error while deleting it: error on delete"
 					r.EqualError(runControllerSync(), errString)
-					requireCredentialIssuer(newErrorStrategy(errString))
+					requireCredentialIssuer(newErrorStrategy(v1alpha1.SecretErrorStrategyReason, errString))
 					requireSigningCertProviderIsEmpty()
 					requireTLSServerIsRunningWithoutCerts()
 					r.Len(kubeAPIClient.Actions(), 3)
@@ -3686,7 +6657,7 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 					startInformersAndController()
 					errString := "found missing or not PEM-encoded data in TLS Secret, but got error while deleting it: error on delete"
 					r.EqualError(runControllerSync(), errString)
-					requireCredentialIssuer(newErrorStrategy(errString))
+					requireCredentialIssuer(newErrorStrategy(v1alpha1.SecretErrorStrategyReason, errString))
 					requireSigningCertProviderIsEmpty()
 					requireTLSServerIsRunningWithoutCerts()
 					r.Len(kubeAPIClient.Actions(), 2)
@@ -3744,7 +6715,7 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 					startInformersAndController()
 					errString := "cert had an invalid private key, but got error while deleting it: error on delete"
 					r.EqualError(runControllerSync(), errString)
-					requireCredentialIssuer(newErrorStrategy(errString))
+					requireCredentialIssuer(newErrorStrategy(v1alpha1.SecretErrorStrategyReason, errString))
 					requireSigningCertProviderIsEmpty()
 					requireTLSServerIsRunningWithoutCerts()
 					r.Len(kubeAPIClient.Actions(), 2)
@@ -3814,7 +6785,7 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 					startInformersAndController()
 					errString := `could not load the impersonator's credential signing secret: secret "some-ca-signer-name" not found`
 					r.EqualError(runControllerSync(), errString)
-					requireCredentialIssuer(newErrorStrategy(errString))
+					requireCredentialIssuer(newErrorStrategy(v1alpha1.CAErrorStrategyReason, errString))
 					requireSigningCertProviderIsEmpty()
 				})
 			})
@@ -3828,7 +6799,7 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 					startInformersAndController()
 					errString := `could not set the impersonator's credential signing secret: TestImpersonatorConfigControllerSync: attempt to set invalid key pair: tls: failed to find any PEM data in certificate input`
 					r.EqualError(runControllerSync(), errString)
-					requireCredentialIssuer(newErrorStrategy(errString))
+					requireCredentialIssuer(newErrorStrategy(v1alpha1.CAErrorStrategyReason, errString))
 					requireSigningCertProviderIsEmpty()
 				})
 			})
@@ -3843,7 +6814,7 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 					startInformersAndController()
 					errString := `could not set the impersonator's credential signing secret: TestImpersonatorConfigControllerSync: attempt to set invalid key pair: tls: failed to find any PEM data in certificate input`
 					r.EqualError(runControllerSync(), errString)
-					requireCredentialIssuer(newErrorStrategy(errString))
+					requireCredentialIssuer(newErrorStrategy(v1alpha1.CAErrorStrategyReason, errString))
 					requireSigningCertProviderIsEmpty()
 				})
 			})
@@ -3879,7 +6850,7 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 
 					errString := `could not set the impersonator's credential signing secret: TestImpersonatorConfigControllerSync: attempt to set invalid key pair: tls: failed to find any PEM data in certificate input`
 					r.EqualError(runControllerSync(), errString)
-					requireCredentialIssuer(newErrorStrategy(errString))
+					requireCredentialIssuer(newErrorStrategy(v1alpha1.CAErrorStrategyReason, errString))
 					requireSigningCertProviderHasLoadedCerts(signingCACertPEM, signingCAKeyPEM)
 				})
 			})
@@ -3914,13 +6885,106 @@ func TestImpersonatorConfigControllerSync(t *testing.T) {
 	}, spec.Parallel(), spec.Report(report.Terminal{}))
 }
 
+// BenchmarkCreateNewTLSSecret shows that the rate limiter bounds how many serving certificates can be issued per
+// second, no matter how many syncs want to issue one. Compare the certs/s metric of the sub-benchmarks.
+func TestLogSyncDecisionWhenChanged(t *testing.T) {
+	testLog := testlogger.New(t)
+	c := &impersonatorConfigController{infoLog: testLog.Logger.WithName("impersonator-config-controller")}
+	credIssuer := &v1alpha1.CredentialIssuer{ObjectMeta: metav1.ObjectMeta{Name: "some-credential-issuer"}}
+	successStrategy := &v1alpha1.CredentialIssuerStrategy{
+		Status:  v1alpha1.SuccessStrategyStatus,
+		Reason:  v1alpha1.ListeningStrategyReason,
+		Message: "impersonation proxy is ready to accept client connections",
+	}
+	sync := func(caSecret, tlsSecret string, strategy *v1alpha1.CredentialIssuerStrategy) {
+		c.syncDecision = syncDecision{
+			mode:          v1alpha1.ImpersonationProxyModeAuto,
+			effectiveMode: v1alpha1.ImpersonationProxyModeEnabled,
+			serviceType:   v1alpha1.ImpersonationProxyServiceTypeLoadBalancer,
+			caSecret:      caSecret,
+			tlsSecret:     tlsSecret,
+		}
+		c.logSyncDecisionWhenChanged(credIssuer, strategy)
+	}
+	wantLine := func(caSecret, tlsSecret string, strategy *v1alpha1.CredentialIssuerStrategy) string {
+		return fmt.Sprintf(`impersonator-config-controller: "level"=0 "msg"="impersonation proxy sync decisions changed" `+
+			`"credentialIssuer"={"name":"some-credential-issuer"} "mode"="auto" "effectiveMode"="enabled" "serviceType"="LoadBalancer" `+
+			`"caSecret"=%q "tlsSecret"=%q "strategyStatus"=%q "strategyReason"=%q "strategyMessage"=%q`,
+			caSecret, tlsSecret, strategy.Status, strategy.Reason, strategy.Message)
+	}
+	// The first sync is always logged.
+	sync(secretCreated, secretCreated, successStrategy)
+	// Nothing changed, so nothing is logged.
+	sync(secretUnchanged, secretUnchanged, successStrategy)
+	sync(secretUnchanged, secretUnchanged, successStrategy)
+	// Changing a Secret is always logged, even when everything else stays the same.
+	sync(secretUnchanged, secretReissued, successStrategy)
+	sync(secretUnchanged, secretUnchanged, successStrategy)
+	// A change in the resulting strategy is logged.
+	errorStrategy := &v1alpha1.CredentialIssuerStrategy{
+		Status:  v1alpha1.ErrorStrategyStatus,
+		Reason:  v1alpha1.ErrorDuringSetupStrategyReason,
+		Message: "some error",
+	}
+	sync(secretUnchanged, secretUnchanged, errorStrategy)
+	sync(secretUnchanged, secretUnchanged, errorStrategy)
+
+	testLog.Expect([]string{
+		wantLine(secretCreated, secretCreated, successStrategy),
+		wantLine(secretUnchanged, secretReissued, successStrategy),
+		wantLine(secretUnchanged, secretUnchanged, errorStrategy),
+	})
+}
+
+func BenchmarkCreateNewTLSSecret(b *testing.B) {
+	ca, err := certauthority.New("test CA", 24*time.Hour)
+	require.NoError(b, err)
+
+	for _, bm := range []struct {
+		name    string
+		limiter flowcontrol.RateLimiter
+	}{
+		{name: "unlimited", limiter: flowcontrol.NewFakeAlwaysRateLimiter()},
+		{name: "limited to 100 per second", limiter: flowcontrol.NewTokenBucketRateLimiter(100, 1)},
+	} {
+		bm := bm
+		b.Run(bm.name, func(b *testing.B) {
+			c := &impersonatorConfigController{
+				namespace:               "some-namespace",
+				k8sClient:               kubernetesfake.NewSimpleClientset(),
+				certIssuanceRateLimiter: bm.limiter,
+				metrics:                 newImpersonatorMetrics(nil),
+				infoLog:                 logr.Discard(),
+			}
+			start := time.Now()
+			for i := 0; i < b.N; i++ {
+				c.tlsSecretName = fmt.Sprintf("some-tls-secret-%d", i)
+				_, err := c.createNewTLSSecret(context.Background(), &v1alpha1.ImpersonationProxySpec{}, ca, "", []net.IP{net.ParseIP("127.0.0.1")}, []string{"example.com"}, tlsIssuedReasonMissing)
+				require.NoError(b, err)
+			}
+			b.ReportMetric(float64(b.N)/time.Since(start).Seconds(), "certs/s")
+		})
+	}
+}
+
 type testQueue struct {
 	key   controllerlib.Key
 	mutex sync.RWMutex
 
+	addedAfterKeys      []controllerlib.Key
+	addedAfterDurations []time.Duration
+
 	controllerlib.Queue
 }
 
+func (q *testQueue) AddAfter(key controllerlib.Key, duration time.Duration) {
+	q.mutex.Lock() // this is to satisfy the race detector
+	defer q.mutex.Unlock()
+
+	q.addedAfterKeys = append(q.addedAfterKeys, key)
+	q.addedAfterDurations = append(q.addedAfterDurations, duration)
+}
+
 func (q *testQueue) AddRateLimited(key controllerlib.Key) {
 	q.mutex.Lock() // this is to satisfy the race detector
 	defer q.mutex.Unlock()
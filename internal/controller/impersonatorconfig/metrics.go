@@ -0,0 +1,59 @@
+// Copyright 2022 the Pinniped contributors. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package impersonatorconfig
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	metricsNamespace = "pinniped"
+	metricsSubsystem = "impersonation"
+
+	caIssuedReasonCreated = "created"
+	caIssuedReasonRenewed = "renewed"
+	caIssuedReasonRotated = "rotated"
+
+	tlsIssuedReasonMissing      = "missing"
+	tlsIssuedReasonInvalid      = "invalid"
+	tlsIssuedReasonCAChanged    = "ca_changed"
+	tlsIssuedReasonNamesChanged = "names_changed"
+	tlsIssuedReasonExpiring     = "expiring"
+)
+
+// impersonatorMetrics holds the Prometheus metrics which describe the certificates issued by the controller.
+type impersonatorMetrics struct {
+	caIssued                 *prometheus.CounterVec
+	tlsIssued                *prometheus.CounterVec
+	tlsCertificateExpiration prometheus.Gauge
+}
+
+// newImpersonatorMetrics creates the metrics and registers them with the given registerer.
+// When the registerer is nil, the metrics are still tracked but are not exposed anywhere.
+func newImpersonatorMetrics(registerer prometheus.Registerer) *impersonatorMetrics {
+	m := &impersonatorMetrics{
+		caIssued: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "ca_issued_total",
+			Help:      "Number of CA certificates generated for the impersonation proxy, by reason.",
+		}, []string{"reason"}),
+		tlsIssued: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "tls_issued_total",
+			Help:      "Number of TLS serving certificates issued for the impersonation proxy, by reason.",
+		}, []string{"reason"}),
+		tlsCertificateExpiration: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "tls_certificate_expiration_timestamp_seconds",
+			Help:      "Unix time at which the currently loaded impersonation proxy TLS serving certificate expires, or zero when none is loaded.",
+		}),
+	}
+	if registerer != nil {
+		registerer.MustRegister(m.caIssued, m.tlsIssued, m.tlsCertificateExpiration)
+	}
+	return m
+}
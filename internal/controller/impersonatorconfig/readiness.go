@@ -0,0 +1,48 @@
+// Copyright 2022 the Pinniped contributors. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package impersonatorconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"strconv"
+	"time"
+
+	"go.pinniped.dev/internal/crypto/ptls"
+)
+
+const (
+	// readinessProbeTimeout is how long a single readiness probe of the impersonation proxy may take.
+	readinessProbeTimeout = time.Second
+
+	// readinessRetryInterval is how long to wait before syncing again when the impersonation proxy was not ready.
+	readinessRetryInterval = time.Second
+)
+
+// ReadinessProbeFunc returns nil when the impersonation proxy which was started on the given bind address and port
+// accepts client connections, or an error explaining why it does not.
+type ReadinessProbeFunc func(ctx context.Context, bindAddress net.IP, port int) error
+
+// DialReadinessProbe is a ReadinessProbeFunc which completes a TLS handshake with the impersonation proxy over the
+// loopback interface, or over the bind address when the proxy only listens on a specific address. A completed
+// handshake means that the proxy is serving with its TLS certificate, so the certificate is not verified.
+func DialReadinessProbe(ctx context.Context, bindAddress net.IP, port int) error {
+	host := bindAddress
+	switch {
+	case host == nil, host.Equal(net.IPv4zero):
+		host = net.IPv4(127, 0, 0, 1)
+	case host.IsUnspecified():
+		host = net.IPv6loopback
+	}
+
+	tlsConfig := ptls.Default(nil)
+	tlsConfig.InsecureSkipVerify = true //nolint:gosec // this only checks that the server is serving, no data is sent
+	dialer := &tls.Dialer{Config: tlsConfig}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host.String(), strconv.Itoa(port)))
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
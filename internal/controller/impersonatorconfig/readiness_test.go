@@ -0,0 +1,79 @@
+// Copyright 2022 the Pinniped contributors. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package impersonatorconfig
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDialReadinessProbe(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(server.Close)
+	port := server.Listener.Addr().(*net.TCPAddr).Port
+
+	// Reserve a port which nothing listens on.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedPort := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	// Accept connections on a plain TCP listener which never completes a TLS handshake.
+	hungListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = hungListener.Close() })
+	hungPort := hungListener.Addr().(*net.TCPAddr).Port
+
+	tests := []struct {
+		name        string
+		bindAddress net.IP
+		port        int
+		wantErr     string
+	}{
+		{
+			name: "all interfaces",
+			port: port,
+		},
+		{
+			name:        "unspecified IPv4 address",
+			bindAddress: net.IPv4zero,
+			port:        port,
+		},
+		{
+			name:        "specific address",
+			bindAddress: net.IPv4(127, 0, 0, 1),
+			port:        port,
+		},
+		{
+			name:    "nothing listening",
+			port:    closedPort,
+			wantErr: "dial tcp 127.0.0.1:" + strconv.Itoa(closedPort) + ": connect: connection refused",
+		},
+		{
+			name:    "listening without serving TLS",
+			port:    hungPort,
+			wantErr: "context deadline exceeded",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			err := DialReadinessProbe(ctx, tt.bindAddress, tt.port)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2022 the Pinniped contributors. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package impersonatorconfig
+
+import (
+	"go.pinniped.dev/generated/latest/apis/concierge/config/v1alpha1"
+)
+
+// SetupErrorCategory enumerates the parts of the impersonation proxy setup which can fail.
+type SetupErrorCategory string
+
+const (
+	// ServiceError means that a Service of the impersonation proxy could not be created, updated, deleted, or read.
+	ServiceError = SetupErrorCategory("ServiceError")
+	// SecretError means that a TLS serving certificate Secret could not be created, updated, deleted, or loaded.
+	SecretError = SetupErrorCategory("SecretError")
+	// CAError means that the impersonation proxy's CA or the credential signing CA could not be created or loaded.
+	CAError = SetupErrorCategory("CAError")
+	// ValidationError means that the CredentialIssuer's spec.impersonationProxy is not a valid configuration.
+	ValidationError = SetupErrorCategory("ValidationError")
+	// ServerStartError means that the impersonation proxy server could not be started or stopped.
+	ServerStartError = SetupErrorCategory("ServerStartError")
+)
+
+// SetupError is an error which happened while setting up the impersonation proxy. Its message is the message of the
+// underlying error, and its Category is used as the reason of the impersonation proxy's CredentialIssuer strategy.
+type SetupError struct {
+	Category SetupErrorCategory
+	Err      error
+}
+
+func (e *SetupError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *SetupError) Unwrap() error {
+	return e.Err
+}
+
+// strategyReason returns the v1alpha1.StrategyReason which corresponds to the category of the error.
+func (e *SetupError) strategyReason() v1alpha1.StrategyReason {
+	switch e.Category {
+	case ServiceError:
+		return v1alpha1.ServiceErrorStrategyReason
+	case SecretError:
+		return v1alpha1.SecretErrorStrategyReason
+	case CAError:
+		return v1alpha1.CAErrorStrategyReason
+	case ValidationError:
+		return v1alpha1.ValidationErrorStrategyReason
+	case ServerStartError:
+		return v1alpha1.ServerStartErrorStrategyReason
+	default:
+		return v1alpha1.ErrorDuringSetupStrategyReason
+	}
+}
+
+// newSetupError wraps the error in a SetupError of the given category. It returns nil when the error is nil.
+func newSetupError(category SetupErrorCategory, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &SetupError{Category: category, Err: err}
+}
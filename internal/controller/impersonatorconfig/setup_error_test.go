@@ -0,0 +1,96 @@
+// Copyright 2022 the Pinniped contributors. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package impersonatorconfig
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"go.pinniped.dev/generated/latest/apis/concierge/config/v1alpha1"
+)
+
+func TestStrategyReasonForError(t *testing.T) {
+	secrets := schema.GroupResource{Resource: "secrets"}
+
+	tests := []struct {
+		name       string
+		err        error
+		wantReason v1alpha1.StrategyReason
+	}{
+		{
+			name:       "uncategorized error",
+			err:        errors.New("some error"),
+			wantReason: v1alpha1.ErrorDuringSetupStrategyReason,
+		},
+		{
+			name:       "service error",
+			err:        newSetupError(ServiceError, errors.New("error on create")),
+			wantReason: v1alpha1.ServiceErrorStrategyReason,
+		},
+		{
+			name:       "secret error",
+			err:        newSetupError(SecretError, errors.New("error on delete")),
+			wantReason: v1alpha1.SecretErrorStrategyReason,
+		},
+		{
+			name:       "CA error",
+			err:        newSetupError(CAError, errors.New("could not load CA")),
+			wantReason: v1alpha1.CAErrorStrategyReason,
+		},
+		{
+			name:       "validation error",
+			err:        newSetupError(ValidationError, errors.New("invalid proxy mode")),
+			wantReason: v1alpha1.ValidationErrorStrategyReason,
+		},
+		{
+			name:       "server start error",
+			err:        newSetupError(ServerStartError, errors.New("could not listen")),
+			wantReason: v1alpha1.ServerStartErrorStrategyReason,
+		},
+		{
+			name:       "wrapped setup error",
+			err:        fmt.Errorf("sync did not finish within 1m0s: %w", newSetupError(SecretError, errors.New("some error"))),
+			wantReason: v1alpha1.SecretErrorStrategyReason,
+		},
+		{
+			name:       "setup error with an unknown category",
+			err:        newSetupError("some-category", errors.New("some error")),
+			wantReason: v1alpha1.ErrorDuringSetupStrategyReason,
+		},
+		{
+			name:       "already exists setup error is pending",
+			err:        newSetupError(SecretError, k8serrors.NewAlreadyExists(secrets, "some-secret")),
+			wantReason: v1alpha1.PendingStrategyReason,
+		},
+		{
+			name:       "conflict setup error is pending",
+			err:        newSetupError(CAError, k8serrors.NewConflict(secrets, "some-secret", errors.New("some conflict"))),
+			wantReason: v1alpha1.PendingStrategyReason,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.wantReason, strategyReasonForError(tt.err))
+		})
+	}
+}
+
+func TestNewSetupError(t *testing.T) {
+	require.NoError(t, newSetupError(ServiceError, nil))
+
+	cause := errors.New("error on create")
+	err := newSetupError(ServiceError, cause)
+	require.EqualError(t, err, "error on create")
+	require.ErrorIs(t, err, cause)
+
+	var setupErr *SetupError
+	require.ErrorAs(t, err, &setupErr)
+	require.Equal(t, ServiceError, setupErr.Category)
+}
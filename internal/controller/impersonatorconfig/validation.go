@@ -0,0 +1,191 @@
+// Copyright 2022 the Pinniped contributors. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package impersonatorconfig
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"go.pinniped.dev/generated/latest/apis/concierge/config/v1alpha1"
+	"go.pinniped.dev/internal/endpointaddr"
+)
+
+// ValidateImpersonationProxySpec returns an error when the given spec.impersonationProxy of a CredentialIssuer is not
+// a valid configuration for the impersonation proxy, so that it can be checked, e.g. by an admission webhook or a CLI,
+// before this controller is asked to load it. The errors are the same as those which this controller reports in the
+// status of the CredentialIssuer, without the "could not load CredentialIssuer" prefix.
+//
+// An empty service type is treated as LoadBalancer, since that is the default which is applied by the CRD. Checks
+// which depend on the configuration of the controller, such as whether the names of the generated Secrets collide
+// with those of other Secrets managed by the Concierge, are not performed.
+func ValidateImpersonationProxySpec(spec *v1alpha1.ImpersonationProxySpec) error {
+	// Validate that the mode is one of our known values.
+	switch spec.Mode {
+	case v1alpha1.ImpersonationProxyModeDisabled:
+	case v1alpha1.ImpersonationProxyModeAuto:
+	case v1alpha1.ImpersonationProxyModeEnabled:
+	default:
+		return fmt.Errorf("invalid proxy mode %q (expected auto, disabled, or enabled)", spec.Mode)
+	}
+
+	// If disabled, ignore all other fields and consider the configuration valid.
+	if spec.Mode == v1alpha1.ImpersonationProxyModeDisabled {
+		return nil
+	}
+
+	// Validate that the service type is one of our known values.
+	switch spec.Service.Type {
+	case v1alpha1.ImpersonationProxyServiceTypeNone:
+	case "", v1alpha1.ImpersonationProxyServiceTypeLoadBalancer:
+	case v1alpha1.ImpersonationProxyServiceTypeClusterIP:
+	case v1alpha1.ImpersonationProxyServiceTypeExisting:
+		if len(validation.IsDNS1035Label(spec.Service.ExistingServiceName)) > 0 {
+			return fmt.Errorf("invalid service existingServiceName %q (expected the name of a Service when the service type is Existing)", spec.Service.ExistingServiceName)
+		}
+	default:
+		return fmt.Errorf("invalid service type %q (expected None, LoadBalancer, ClusterIP, or Existing)", spec.Service.Type)
+	}
+
+	// Validate that the preferred address type is one of our known values.
+	switch spec.Service.PreferredAddressType {
+	case "":
+	case v1alpha1.ImpersonationProxyServiceAddressTypeHostname:
+	case v1alpha1.ImpersonationProxyServiceAddressTypeIP:
+	case v1alpha1.ImpersonationProxyServiceAddressTypeIPv4:
+	case v1alpha1.ImpersonationProxyServiceAddressTypeIPv6:
+	default:
+		return fmt.Errorf("invalid service preferredAddressType %q (expected Hostname, IP, IPv4, or IPv6)", spec.Service.PreferredAddressType)
+	}
+
+	// Validate that the external traffic policy is one of our known values.
+	switch spec.Service.ExternalTrafficPolicy {
+	case "":
+	case v1alpha1.ImpersonationProxyServiceExternalTrafficPolicyCluster:
+	case v1alpha1.ImpersonationProxyServiceExternalTrafficPolicyLocal:
+	default:
+		return fmt.Errorf("invalid service externalTrafficPolicy %q (expected Cluster or Local)", spec.Service.ExternalTrafficPolicy)
+	}
+
+	// If specified, validate that the port is a valid TCP port number.
+	if port := spec.Port; port != nil && (*port < 1 || *port > 65535) {
+		return fmt.Errorf("invalid port %d (expected a value between 1 and 65535)", *port)
+	}
+
+	// If specified, validate that the service port is a valid TCP port number.
+	if port := spec.Service.Port; port != nil && (*port < 1 || *port > 65535) {
+		return fmt.Errorf("invalid service port %d (expected a value between 1 and 65535)", *port)
+	}
+
+	// If specified, validate that the CA lifetime is long enough to be practical.
+	if lifetime := spec.CACertificateLifetime; lifetime != nil && lifetime.Duration < minimumCACertificateLifetime {
+		return fmt.Errorf("invalid caCertificateLifetime %q (expected at least %s)", lifetime.Duration, minimumCACertificateLifetime)
+	}
+
+	// If specified, validate that the TLS serving certificate lifetime is long enough to be practical.
+	if lifetime := spec.TLSCertificateLifetime; lifetime != nil && lifetime.Duration < minimumTLSCertificateLifetime {
+		return fmt.Errorf("invalid tlsCertificateLifetime %q (expected at least %s)", lifetime.Duration, minimumTLSCertificateLifetime)
+	}
+
+	// If specified, validate that the CA subject fields fit within the upper bounds of RFC 5280.
+	if subject := spec.CACertificateSubject; subject != nil {
+		if strings.TrimSpace(subject.CommonName) == "" || len(subject.CommonName) > maxCASubjectFieldLength {
+			return fmt.Errorf("invalid caCertificateSubject commonName %q (expected a non-empty value of at most %d characters)", subject.CommonName, maxCASubjectFieldLength)
+		}
+		if len(subject.Organization) > maxCASubjectFieldLength {
+			return fmt.Errorf("invalid caCertificateSubject organization %q (expected at most %d characters)", subject.Organization, maxCASubjectFieldLength)
+		}
+	}
+
+	// If specified, validate that the CA renewal threshold is a sensible percentage.
+	if percent := spec.CACertificateRenewalThresholdPercent; percent != nil && (*percent < 1 || *percent > 99) {
+		return fmt.Errorf("invalid caCertificateRenewalThresholdPercent %d (expected a value between 1 and 99)", *percent)
+	}
+
+	// If specified, validate that the LoadBalancerIP is a valid IPv4 or IPv6 address.
+	if ip := spec.Service.LoadBalancerIP; ip != "" && len(validation.IsValidIP(ip)) > 0 {
+		return fmt.Errorf("invalid LoadBalancerIP %q", spec.Service.LoadBalancerIP)
+	}
+
+	// If specified, validate that the ClusterIP is a valid IPv4 or IPv6 address. Whether it is within the service CIDR
+	// of the cluster can only be checked by the API server when the Service is created.
+	if ip := spec.Service.ClusterIP; ip != "" && len(validation.IsValidIP(ip)) > 0 {
+		return fmt.Errorf("invalid service clusterIP %q (expected an IPv4 or IPv6 address)", ip)
+	}
+
+	// If specified, validate that the LoadBalancerClass is a label-style name, as Kubernetes requires.
+	if class := spec.Service.LoadBalancerClass; class != nil && len(validation.IsQualifiedName(*class)) > 0 {
+		return fmt.Errorf("invalid service loadBalancerClass %q (expected a label-style name, e.g. example.com/some-class)", *class)
+	}
+
+	// If specified, validate that each of the LoadBalancerSourceRanges is a valid CIDR.
+	for _, sourceRange := range spec.Service.LoadBalancerSourceRanges {
+		if _, _, err := net.ParseCIDR(sourceRange); err != nil {
+			return fmt.Errorf("invalid LoadBalancerSourceRanges entry %q: %w", sourceRange, err)
+		}
+	}
+
+	// If specified, validate that each of the AdditionalHostnames is a valid DNS name.
+	for _, hostname := range spec.AdditionalHostnames {
+		if len(validation.IsDNS1123Subdomain(hostname)) > 0 {
+			return fmt.Errorf("invalid additionalHostnames entry %q (expected a DNS-1123 subdomain)", hostname)
+		}
+	}
+
+	// If specified, validate that the overridden names of the generated Secrets are valid names for Secrets.
+	if spec.GeneratedCASecretName != "" && len(validation.IsDNS1123Subdomain(spec.GeneratedCASecretName)) > 0 {
+		return fmt.Errorf("invalid generatedCASecretName %q (expected a DNS-1123 subdomain)", spec.GeneratedCASecretName)
+	}
+	if spec.GeneratedTLSSecretName != "" && len(validation.IsDNS1123Subdomain(spec.GeneratedTLSSecretName)) > 0 {
+		return fmt.Errorf("invalid generatedTLSSecretName %q (expected a DNS-1123 subdomain)", spec.GeneratedTLSSecretName)
+	}
+
+	// If specified, validate that each of the AdditionalIPs is a valid IPv4 or IPv6 address.
+	for _, ip := range spec.AdditionalIPs {
+		if net.ParseIP(ip) == nil {
+			return fmt.Errorf("invalid additionalIPs entry %q (expected an IPv4 or IPv6 address)", ip)
+		}
+	}
+
+	// If service is type "None", a non-empty external endpoint must be specified.
+	if spec.ExternalEndpoint == "" && spec.Service.Type == v1alpha1.ImpersonationProxyServiceTypeNone {
+		return fmt.Errorf("externalEndpoint must be set when service.type is None")
+	}
+
+	if spec.ExternalEndpoint != "" {
+		if _, err := endpointaddr.Parse(spec.ExternalEndpoint, 443); err != nil {
+			return fmt.Errorf("invalid ExternalEndpoint %q: %w", spec.ExternalEndpoint, err)
+		}
+	}
+
+	// If specified, validate that the operator-provided serving certificate comes with the CA bundle for clients.
+	if spec.TLSCertificateSecretName != "" {
+		if spec.TLSCertificateAuthorityData == "" {
+			return fmt.Errorf("tlsCertificateAuthorityData must be set when tlsCertificateSecretName is set")
+		}
+		caBundle, err := base64.StdEncoding.DecodeString(spec.TLSCertificateAuthorityData)
+		if err != nil {
+			return fmt.Errorf("invalid tlsCertificateAuthorityData: %w", err)
+		}
+		if !x509.NewCertPool().AppendCertsFromPEM(caBundle) {
+			return fmt.Errorf("invalid tlsCertificateAuthorityData: no certificates found")
+		}
+	} else if spec.TLSCertificateAuthorityData != "" {
+		return fmt.Errorf("tlsCertificateAuthorityData may only be set when tlsCertificateSecretName is set")
+	}
+
+	// If a wildcard hostname is requested, the external endpoint must be a hostname from which it can be derived.
+	if spec.WildcardHostname {
+		addr, _ := endpointaddr.Parse(spec.ExternalEndpoint, 443)
+		if spec.ExternalEndpoint == "" || net.ParseIP(addr.Host) != nil {
+			return fmt.Errorf("wildcardHostname requires externalEndpoint to be a hostname rather than an IP address")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,237 @@
+// Copyright 2022 the Pinniped contributors. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package impersonatorconfig
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"go.pinniped.dev/generated/latest/apis/concierge/config/v1alpha1"
+	"go.pinniped.dev/internal/certauthority"
+)
+
+func TestValidateImpersonationProxySpec(t *testing.T) {
+	ca, err := certauthority.New("test CA", time.Hour)
+	require.NoError(t, err)
+
+	enabled := func(modify func(spec *v1alpha1.ImpersonationProxySpec)) *v1alpha1.ImpersonationProxySpec {
+		spec := &v1alpha1.ImpersonationProxySpec{Mode: v1alpha1.ImpersonationProxyModeEnabled}
+		if modify != nil {
+			modify(spec)
+		}
+		return spec
+	}
+
+	tests := []struct {
+		name    string
+		spec    *v1alpha1.ImpersonationProxySpec
+		wantErr string
+	}{
+		{
+			name: "enabled with the defaults",
+			spec: enabled(nil),
+		},
+		{
+			name: "auto with a load balancer",
+			spec: &v1alpha1.ImpersonationProxySpec{
+				Mode:    v1alpha1.ImpersonationProxyModeAuto,
+				Service: v1alpha1.ImpersonationProxyServiceSpec{Type: v1alpha1.ImpersonationProxyServiceTypeLoadBalancer, LoadBalancerIP: "127.0.0.1"},
+			},
+		},
+		{
+			name: "enabled with service type None and an external endpoint",
+			spec: enabled(func(spec *v1alpha1.ImpersonationProxySpec) {
+				spec.Service.Type = v1alpha1.ImpersonationProxyServiceTypeNone
+				spec.ExternalEndpoint = "proxy.example.com:8443"
+			}),
+		},
+		{
+			name: "disabled ignores all other fields",
+			spec: &v1alpha1.ImpersonationProxySpec{
+				Mode:    v1alpha1.ImpersonationProxyModeDisabled,
+				Service: v1alpha1.ImpersonationProxyServiceSpec{Type: "not-valid", LoadBalancerIP: "invalid-ip-address"},
+			},
+		},
+		{
+			name:    "invalid mode",
+			spec:    &v1alpha1.ImpersonationProxySpec{Mode: "not-valid"},
+			wantErr: `invalid proxy mode "not-valid" (expected auto, disabled, or enabled)`,
+		},
+		{
+			name:    "invalid service type",
+			spec:    enabled(func(spec *v1alpha1.ImpersonationProxySpec) { spec.Service.Type = "not-valid" }),
+			wantErr: `invalid service type "not-valid" (expected None, LoadBalancer, ClusterIP, or Existing)`,
+		},
+		{
+			name: "service type Existing without an existingServiceName",
+			spec: enabled(func(spec *v1alpha1.ImpersonationProxySpec) {
+				spec.Service.Type = v1alpha1.ImpersonationProxyServiceTypeExisting
+			}),
+			wantErr: `invalid service existingServiceName "" (expected the name of a Service when the service type is Existing)`,
+		},
+		{
+			name:    "invalid preferredAddressType",
+			spec:    enabled(func(spec *v1alpha1.ImpersonationProxySpec) { spec.Service.PreferredAddressType = "not-valid" }),
+			wantErr: `invalid service preferredAddressType "not-valid" (expected Hostname, IP, IPv4, or IPv6)`,
+		},
+		{
+			name:    "invalid externalTrafficPolicy",
+			spec:    enabled(func(spec *v1alpha1.ImpersonationProxySpec) { spec.Service.ExternalTrafficPolicy = "not-valid" }),
+			wantErr: `invalid service externalTrafficPolicy "not-valid" (expected Cluster or Local)`,
+		},
+		{
+			name:    "invalid port",
+			spec:    enabled(func(spec *v1alpha1.ImpersonationProxySpec) { spec.Port = pointer.Int32(65536) }),
+			wantErr: `invalid port 65536 (expected a value between 1 and 65535)`,
+		},
+		{
+			name:    "invalid service port",
+			spec:    enabled(func(spec *v1alpha1.ImpersonationProxySpec) { spec.Service.Port = pointer.Int32(0) }),
+			wantErr: `invalid service port 0 (expected a value between 1 and 65535)`,
+		},
+		{
+			name: "CA certificate lifetime which is too short",
+			spec: enabled(func(spec *v1alpha1.ImpersonationProxySpec) {
+				spec.CACertificateLifetime = &metav1.Duration{Duration: time.Minute}
+			}),
+			wantErr: `invalid caCertificateLifetime "1m0s" (expected at least 1h0m0s)`,
+		},
+		{
+			name: "TLS certificate lifetime which is too short",
+			spec: enabled(func(spec *v1alpha1.ImpersonationProxySpec) {
+				spec.TLSCertificateLifetime = &metav1.Duration{Duration: time.Minute}
+			}),
+			wantErr: `invalid tlsCertificateLifetime "1m0s" (expected at least 1h0m0s)`,
+		},
+		{
+			name: "CA certificate subject with an empty commonName",
+			spec: enabled(func(spec *v1alpha1.ImpersonationProxySpec) {
+				spec.CACertificateSubject = &v1alpha1.ImpersonationProxyCASubject{CommonName: " "}
+			}),
+			wantErr: `invalid caCertificateSubject commonName " " (expected a non-empty value of at most 64 characters)`,
+		},
+		{
+			name: "CA certificate subject with an organization which is too long",
+			spec: enabled(func(spec *v1alpha1.ImpersonationProxySpec) {
+				spec.CACertificateSubject = &v1alpha1.ImpersonationProxyCASubject{CommonName: "some CA", Organization: strings.Repeat("o", 65)}
+			}),
+			wantErr: `invalid caCertificateSubject organization "` + strings.Repeat("o", 65) + `" (expected at most 64 characters)`,
+		},
+		{
+			name: "invalid CA certificate renewal threshold",
+			spec: enabled(func(spec *v1alpha1.ImpersonationProxySpec) {
+				spec.CACertificateRenewalThresholdPercent = pointer.Int32(100)
+			}),
+			wantErr: `invalid caCertificateRenewalThresholdPercent 100 (expected a value between 1 and 99)`,
+		},
+		{
+			name:    "invalid LoadBalancerIP",
+			spec:    enabled(func(spec *v1alpha1.ImpersonationProxySpec) { spec.Service.LoadBalancerIP = "invalid-ip-address" }),
+			wantErr: `invalid LoadBalancerIP "invalid-ip-address"`,
+		},
+		{
+			name:    "invalid service clusterIP",
+			spec:    enabled(func(spec *v1alpha1.ImpersonationProxySpec) { spec.Service.ClusterIP = "invalid-ip-address" }),
+			wantErr: `invalid service clusterIP "invalid-ip-address" (expected an IPv4 or IPv6 address)`,
+		},
+		{
+			name: "invalid service loadBalancerClass",
+			spec: enabled(func(spec *v1alpha1.ImpersonationProxySpec) {
+				spec.Service.LoadBalancerClass = pointer.String("not a class")
+			}),
+			wantErr: `invalid service loadBalancerClass "not a class" (expected a label-style name, e.g. example.com/some-class)`,
+		},
+		{
+			name: "invalid LoadBalancerSourceRanges",
+			spec: enabled(func(spec *v1alpha1.ImpersonationProxySpec) {
+				spec.Service.LoadBalancerSourceRanges = []string{"10.0.0.0/8", "not-a-cidr"}
+			}),
+			wantErr: `invalid LoadBalancerSourceRanges entry "not-a-cidr": invalid CIDR address: not-a-cidr`,
+		},
+		{
+			name:    "invalid additionalHostnames",
+			spec:    enabled(func(spec *v1alpha1.ImpersonationProxySpec) { spec.AdditionalHostnames = []string{"Not_A_Hostname"} }),
+			wantErr: `invalid additionalHostnames entry "Not_A_Hostname" (expected a DNS-1123 subdomain)`,
+		},
+		{
+			name:    "invalid generatedCASecretName",
+			spec:    enabled(func(spec *v1alpha1.ImpersonationProxySpec) { spec.GeneratedCASecretName = "Not_A_Name" }),
+			wantErr: `invalid generatedCASecretName "Not_A_Name" (expected a DNS-1123 subdomain)`,
+		},
+		{
+			name:    "invalid generatedTLSSecretName",
+			spec:    enabled(func(spec *v1alpha1.ImpersonationProxySpec) { spec.GeneratedTLSSecretName = "Not_A_Name" }),
+			wantErr: `invalid generatedTLSSecretName "Not_A_Name" (expected a DNS-1123 subdomain)`,
+		},
+		{
+			name:    "invalid additionalIPs",
+			spec:    enabled(func(spec *v1alpha1.ImpersonationProxySpec) { spec.AdditionalIPs = []string{"not-an-ip"} }),
+			wantErr: `invalid additionalIPs entry "not-an-ip" (expected an IPv4 or IPv6 address)`,
+		},
+		{
+			name: "service type None without an externalEndpoint",
+			spec: enabled(func(spec *v1alpha1.ImpersonationProxySpec) {
+				spec.Service.Type = v1alpha1.ImpersonationProxyServiceTypeNone
+			}),
+			wantErr: `externalEndpoint must be set when service.type is None`,
+		},
+		{
+			name:    "invalid ExternalEndpoint",
+			spec:    enabled(func(spec *v1alpha1.ImpersonationProxySpec) { spec.ExternalEndpoint = "[invalid" }),
+			wantErr: `invalid ExternalEndpoint "[invalid": address [invalid:443: missing ']' in address`,
+		},
+		{
+			name: "tlsCertificateSecretName with a CA bundle",
+			spec: enabled(func(spec *v1alpha1.ImpersonationProxySpec) {
+				spec.TLSCertificateSecretName = "some-operator-tls-secret"
+				spec.TLSCertificateAuthorityData = base64.StdEncoding.EncodeToString(ca.Bundle())
+			}),
+		},
+		{
+			name: "tlsCertificateSecretName without tlsCertificateAuthorityData",
+			spec: enabled(func(spec *v1alpha1.ImpersonationProxySpec) {
+				spec.TLSCertificateSecretName = "some-operator-tls-secret"
+			}),
+			wantErr: `tlsCertificateAuthorityData must be set when tlsCertificateSecretName is set`,
+		},
+		{
+			name: "tlsCertificateAuthorityData which does not contain certificates",
+			spec: enabled(func(spec *v1alpha1.ImpersonationProxySpec) {
+				spec.TLSCertificateSecretName = "some-operator-tls-secret"
+				spec.TLSCertificateAuthorityData = base64.StdEncoding.EncodeToString([]byte("not a certificate"))
+			}),
+			wantErr: `invalid tlsCertificateAuthorityData: no certificates found`,
+		},
+		{
+			name:    "tlsCertificateAuthorityData without tlsCertificateSecretName",
+			spec:    enabled(func(spec *v1alpha1.ImpersonationProxySpec) { spec.TLSCertificateAuthorityData = "c29tZS1jYQ==" }),
+			wantErr: `tlsCertificateAuthorityData may only be set when tlsCertificateSecretName is set`,
+		},
+		{
+			name: "wildcardHostname with an IP address externalEndpoint",
+			spec: enabled(func(spec *v1alpha1.ImpersonationProxySpec) {
+				spec.WildcardHostname = true
+				spec.ExternalEndpoint = "127.0.0.1:8443"
+			}),
+			wantErr: `wildcardHostname requires externalEndpoint to be a hostname rather than an IP address`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateImpersonationProxySpec(tt.spec)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
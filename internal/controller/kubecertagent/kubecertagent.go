@@ -10,6 +10,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -42,7 +43,7 @@ import (
 )
 
 const (
-	// ControllerManagerNamespace is the assumed namespace of the kube-controller-manager pod(s).
+	// ControllerManagerNamespace is the default namespace of the kube-controller-manager pod(s).
 	ControllerManagerNamespace = "kube-system"
 
 	// agentPodLabelKey is used to identify which pods are created by the kube-cert-agent
@@ -79,6 +80,11 @@ type AgentConfig struct {
 	// ContainerImage specifies the container image used for the agent pods.
 	ContainerImage string
 
+	// ContainerCommand overrides the default command of the agent container, which sleeps forever using the
+	// pinniped-concierge-kube-cert-agent binary. The container image must still contain that binary, because
+	// it is used to read the cluster keypair.
+	ContainerCommand []string
+
 	// NamePrefix will be prefixed to all agent pod names.
 	NamePrefix string
 
@@ -98,6 +104,54 @@ type AgentConfig struct {
 	// DiscoveryURLOverride is the Kubernetes server endpoint to report in the CredentialIssuer, overriding any
 	// value discovered in the kube-public/cluster-info ConfigMap.
 	DiscoveryURLOverride *string
+
+	// AdditionalTolerations are added to the tolerations which are copied from the kube-controller-manager pod onto
+	// the agent pods. This helps when the kube-controller-manager pod is a static pod, which does not need tolerations
+	// for all the taints of the node on which it runs.
+	AdditionalTolerations []corev1.Toleration
+
+	// AdditionalNodeSelector entries are merged on top of the node selector which is copied from the
+	// kube-controller-manager pod onto the agent pods.
+	AdditionalNodeSelector map[string]string
+
+	// PriorityClassName is the name of the PriorityClass of the agent pods. When empty, the agent pods get the
+	// default priority of the cluster.
+	PriorityClassName string
+
+	// ImagePullPolicy is the image pull policy of the agent container. When empty, IfNotPresent is used.
+	ImagePullPolicy corev1.PullPolicy
+
+	// ControllerManagerNamespace is the namespace of the kube-controller-manager pod(s). When empty, the
+	// ControllerManagerNamespace constant is used.
+	ControllerManagerNamespace string
+
+	// PreferredControllerManagerPodName is the name of the kube-controller-manager pod which the agent pods should be
+	// co-located with whenever it is running. When empty, or when that pod is not running, a pod is chosen automatically.
+	PreferredControllerManagerPodName string
+
+	// PodSecurityContext overrides the default pod-level security context of the agent pods.
+	PodSecurityContext *corev1.PodSecurityContext
+
+	// ContainerSecurityContext overrides the default container-level security context of the agent pods.
+	ContainerSecurityContext *corev1.SecurityContext
+
+	// ReadinessProbe overrides the default readiness probe of the agent pods, which checks that the
+	// cluster signing certificate and key files are readable.
+	ReadinessProbe *corev1.Probe
+
+	// AdditionalPodLabels are applied only to the agent pods, in addition to the Labels. They can never
+	// replace the unique label which is used to select the agent pods.
+	AdditionalPodLabels map[string]string
+
+	// AdditionalPodAnnotations are applied only to the agent pods.
+	AdditionalPodAnnotations map[string]string
+}
+
+func (a *AgentConfig) controllerManagerNamespace() string {
+	if a.ControllerManagerNamespace == "" {
+		return ControllerManagerNamespace
+	}
+	return a.ControllerManagerNamespace
 }
 
 // Only select using the unique label which will not match the pods of any other Deployment.
@@ -108,7 +162,12 @@ func (a *AgentConfig) agentPodSelectorLabels() map[string]string {
 
 // Label the agent pod using the configured labels plus the unique label which we will use in the selector.
 func (a *AgentConfig) agentPodLabels() map[string]string {
-	allLabels := map[string]string{agentPodLabelKey: agentPodLabelValue}
+	allLabels := map[string]string{}
+	for k, v := range a.AdditionalPodLabels {
+		if k != conciergeDefaultLabelKeyName {
+			allLabels[k] = v
+		}
+	}
 	for k, v := range a.Labels {
 		// Never label the agent pod with any label whose key is "app" because that could unfortunately match
 		// the selector of the main Concierge Deployment. This is sadly inconsistent because all other resources
@@ -121,9 +180,127 @@ func (a *AgentConfig) agentPodLabels() map[string]string {
 			allLabels[k] = v
 		}
 	}
+	allLabels[agentPodLabelKey] = agentPodLabelValue
 	return allLabels
 }
 
+// Annotate the agent pod using only the configured pod annotations.
+func (a *AgentConfig) agentPodAnnotations() map[string]string {
+	if len(a.AdditionalPodAnnotations) == 0 {
+		return nil
+	}
+	allAnnotations := make(map[string]string, len(a.AdditionalPodAnnotations))
+	for k, v := range a.AdditionalPodAnnotations {
+		allAnnotations[k] = v
+	}
+	return allAnnotations
+}
+
+// Select nodes for the agent pod using the node selector of the kube-controller-manager pod plus the configured entries.
+func (a *AgentConfig) agentPodNodeSelector(controllerManagerPod *corev1.Pod) map[string]string {
+	if len(a.AdditionalNodeSelector) == 0 {
+		return controllerManagerPod.Spec.NodeSelector
+	}
+	nodeSelector := make(map[string]string, len(controllerManagerPod.Spec.NodeSelector)+len(a.AdditionalNodeSelector))
+	for k, v := range controllerManagerPod.Spec.NodeSelector {
+		nodeSelector[k] = v
+	}
+	for k, v := range a.AdditionalNodeSelector {
+		nodeSelector[k] = v
+	}
+	return nodeSelector
+}
+
+// Tolerate the taints tolerated by the kube-controller-manager pod plus the configured tolerations, without duplicates.
+func (a *AgentConfig) agentPodTolerations(controllerManagerPod *corev1.Pod) []corev1.Toleration {
+	if len(a.AdditionalTolerations) == 0 {
+		return controllerManagerPod.Spec.Tolerations
+	}
+	tolerations := make([]corev1.Toleration, 0, len(controllerManagerPod.Spec.Tolerations)+len(a.AdditionalTolerations))
+	tolerations = append(tolerations, controllerManagerPod.Spec.Tolerations...)
+	for _, additional := range a.AdditionalTolerations {
+		duplicate := false
+		for _, toleration := range tolerations {
+			if apiequality.Semantic.DeepEqual(additional, toleration) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			tolerations = append(tolerations, additional)
+		}
+	}
+	return tolerations
+}
+
+// The agent pod runs as root by default, since the file permissions on the cluster keypair usually restrict
+// access to only root. Otherwise, it is as restricted as possible.
+func (a *AgentConfig) agentPodSecurityContext() *corev1.PodSecurityContext {
+	if a.PodSecurityContext != nil {
+		return a.PodSecurityContext
+	}
+	return &corev1.PodSecurityContext{
+		RunAsUser:      pointer.Int64Ptr(0),
+		RunAsGroup:     pointer.Int64Ptr(0),
+		SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+	}
+}
+
+// The agent container only needs to read the cluster keypair from the volumes of the kube-controller-manager pod,
+// so by default it gets no capabilities and cannot write to its root filesystem.
+func (a *AgentConfig) agentContainerSecurityContext() *corev1.SecurityContext {
+	if a.ContainerSecurityContext != nil {
+		return a.ContainerSecurityContext
+	}
+	return &corev1.SecurityContext{
+		AllowPrivilegeEscalation: pointer.BoolPtr(false),
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+		ReadOnlyRootFilesystem:   pointer.BoolPtr(true),
+	}
+}
+
+// The agent pod is only ready when it can read the mounted cluster keypair. Any unset fields of the probe get the
+// same defaults that the API server would give them, so the probe can be compared with the existing Deployment.
+func (a *AgentConfig) agentPodReadinessProbe() *corev1.Probe {
+	probe := &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			Exec: &corev1.ExecAction{Command: []string{"pinniped-concierge-kube-cert-agent", "check"}},
+		},
+	}
+	if a.ReadinessProbe != nil {
+		probe = a.ReadinessProbe.DeepCopy()
+	}
+	if probe.TimeoutSeconds == 0 {
+		probe.TimeoutSeconds = 1
+	}
+	if probe.PeriodSeconds == 0 {
+		probe.PeriodSeconds = 10
+	}
+	if probe.SuccessThreshold == 0 {
+		probe.SuccessThreshold = 1
+	}
+	if probe.FailureThreshold == 0 {
+		probe.FailureThreshold = 3
+	}
+	return probe
+}
+
+func (a *AgentConfig) agentContainerCommand() []string {
+	if len(a.ContainerCommand) > 0 {
+		return a.ContainerCommand
+	}
+	return []string{"pinniped-concierge-kube-cert-agent", "sleep"}
+}
+
+func (a *AgentConfig) agentContainerImagePullPolicy() corev1.PullPolicy {
+	if a.ImagePullPolicy != "" {
+		return a.ImagePullPolicy
+	}
+	return corev1.PullIfNotPresent
+}
+
+// deploymentName is the name of the agent Deployment. The agent pods get their names from the Deployment, and both
+// live in the install Namespace, so their names are already unique per Pinniped install without any hashing.
 func (a *AgentConfig) deploymentName() string {
 	return strings.TrimSuffix(a.NamePrefix, "-")
 }
@@ -218,7 +395,7 @@ func newAgentController(
 		controllerlib.WithInformer(
 			kubeSystemPods,
 			pinnipedcontroller.SimpleFilterWithSingletonQueue(func(obj metav1.Object) bool {
-				return controllerManagerLabels.Matches(labels.Set(obj.GetLabels()))
+				return obj.GetNamespace() == cfg.controllerManagerNamespace() && controllerManagerLabels.Matches(labels.Set(obj.GetLabels()))
 			}),
 			controllerlib.InformerOption{},
 		),
@@ -265,13 +442,13 @@ func (c *agentController) Sync(ctx controllerlib.Context) error {
 		return fmt.Errorf("could not get CredentialIssuer to update: %w", err)
 	}
 
-	// Find the latest healthy kube-controller-manager Pod in kube-system.
-	controllerManagerPods, err := c.kubeSystemPods.Lister().Pods(ControllerManagerNamespace).List(controllerManagerLabels)
+	// Choose a healthy kube-controller-manager Pod in the controller manager namespace.
+	controllerManagerPods, err := c.kubeSystemPods.Lister().Pods(c.cfg.controllerManagerNamespace()).List(controllerManagerLabels)
 	if err != nil {
 		err := fmt.Errorf("could not list controller manager pods: %w", err)
 		return c.failStrategyAndErr(ctx.Context, credIssuer, err, configv1alpha1.CouldNotFetchKeyStrategyReason)
 	}
-	newestControllerManager := newestRunningPod(controllerManagerPods)
+	newestControllerManager := c.selectControllerManagerPod(controllerManagerPods)
 
 	// If there are no healthy controller manager pods, we alert the user that we can't find the keypair via
 	// the CredentialIssuer.
@@ -300,6 +477,9 @@ func (c *agentController) Sync(ctx controllerlib.Context) error {
 	// the CredentialIssuer.
 	if newestAgentPod == nil {
 		err := fmt.Errorf("could not find a healthy agent pod (%s)", pluralize(agentPods))
+		if problems := describeAgentPodProblems(agentPods); problems != "" {
+			err = fmt.Errorf("could not find a healthy agent pod (%s): %s", pluralize(agentPods), problems)
+		}
 		return c.failStrategyAndErr(ctx.Context, credIssuer, firstErr(depErr, err), configv1alpha1.CouldNotFetchKeyStrategyReason)
 	}
 
@@ -318,6 +498,10 @@ func (c *agentController) Sync(ctx controllerlib.Context) error {
 
 	// Load the certificate and key from the agent pod into our in-memory signer.
 	if err := c.loadSigningKey(newestAgentPod); err != nil {
+		if problems := describeAgentPodProblems([]*corev1.Pod{newestAgentPod}); problems != "" {
+			// A running agent pod whose container keeps crashing cannot be exec'ed into, so explain why.
+			err = fmt.Errorf("%w: %s", err, problems)
+		}
 		return c.failStrategyAndErr(ctx.Context, credIssuer, firstErr(depErr, err), configv1alpha1.CouldNotFetchKeyStrategyReason)
 	}
 
@@ -410,12 +594,20 @@ func (c *agentController) createOrUpdateDeployment(ctx controllerlib.Context, ne
 	updatedDeployment.ObjectMeta = mergeLabelsAndAnnotations(updatedDeployment.ObjectMeta, expectedDeployment.ObjectMeta)
 	desireSelectorUpdate := !apiequality.Semantic.DeepEqual(updatedDeployment.Spec.Selector, existingDeployment.Spec.Selector)
 	desireTemplateLabelsUpdate := !apiequality.Semantic.DeepEqual(updatedDeployment.Spec.Template.Labels, existingDeployment.Spec.Template.Labels)
+	desireTemplateAnnotationsUpdate := !apiequality.Semantic.DeepEqual(updatedDeployment.Spec.Template.Annotations, existingDeployment.Spec.Template.Annotations)
+	desireNodeSelectorUpdate := !apiequality.Semantic.DeepEqual(updatedDeployment.Spec.Template.Spec.NodeSelector, existingDeployment.Spec.Template.Spec.NodeSelector)
+	desirePriorityClassNameUpdate := updatedDeployment.Spec.Template.Spec.PriorityClassName != existingDeployment.Spec.Template.Spec.PriorityClassName
+	desireSecurityContextUpdate := !securityContextsEqual(updatedDeployment, existingDeployment)
+	desireImagePullSecretsUpdate := !apiequality.Semantic.DeepEqual(updatedDeployment.Spec.Template.Spec.ImagePullSecrets, existingDeployment.Spec.Template.Spec.ImagePullSecrets)
+	desireReadinessProbeUpdate := !readinessProbesEqual(updatedDeployment, existingDeployment)
+	desireCommandUpdate := !containerCommandsEqual(updatedDeployment, existingDeployment)
+	desireImagePullPolicyUpdate := !imagePullPoliciesEqual(updatedDeployment, existingDeployment)
 
 	// If the existing Deployment already matches our desired spec, we're done.
 	if apiequality.Semantic.DeepDerivative(updatedDeployment, existingDeployment) {
 		// DeepDerivative allows the map fields of updatedDeployment to be a subset of existingDeployment,
 		// but we want to check that certain of those map fields are exactly equal before deciding to skip the update.
-		if !desireSelectorUpdate && !desireTemplateLabelsUpdate {
+		if !desireSelectorUpdate && !desireTemplateLabelsUpdate && !desireTemplateAnnotationsUpdate && !desireNodeSelectorUpdate && !desirePriorityClassNameUpdate && !desireSecurityContextUpdate && !desireImagePullSecretsUpdate && !desireReadinessProbeUpdate && !desireCommandUpdate && !desireImagePullPolicyUpdate {
 			return nil // already equal enough, so skip update
 		}
 	}
@@ -481,6 +673,36 @@ func (c *agentController) extractAPIInfo(configMap *corev1.ConfigMap) (*configv1
 	return nil, fmt.Errorf("kubeconfig in key %q does not contain any clusters", clusterInfoConfigMapKey)
 }
 
+// selectControllerManagerPod chooses the kube-controller-manager pod which the agent pod should be co-located with.
+// When a preferred pod is configured and running, it is always chosen. Otherwise, on clusters with several
+// kube-controller-manager pods (e.g. HA control planes), it prefers the newest running pod on the node where the
+// existing agent Deployment is already scheduled, so that the agent pod does not move between nodes whenever any
+// kube-controller-manager pod restarts. When no running pod remains on that node (e.g. the node has gone away), it
+// falls back to the newest running pod on any node, which causes the Deployment to be moved to that node.
+func (c *agentController) selectControllerManagerPod(pods []*corev1.Pod) *corev1.Pod {
+	if c.cfg.PreferredControllerManagerPodName != "" {
+		for _, pod := range pods {
+			if pod.Name == c.cfg.PreferredControllerManagerPodName && pod.Status.Phase == corev1.PodRunning {
+				return pod
+			}
+		}
+	}
+
+	existingDeployment, err := c.agentDeployments.Lister().Deployments(c.cfg.Namespace).Get(c.cfg.deploymentName())
+	if err == nil && existingDeployment.Spec.Template.Spec.NodeName != "" {
+		var podsOnCurrentNode []*corev1.Pod
+		for _, pod := range pods {
+			if pod.Spec.NodeName == existingDeployment.Spec.Template.Spec.NodeName {
+				podsOnCurrentNode = append(podsOnCurrentNode, pod)
+			}
+		}
+		if pod := newestRunningPod(podsOnCurrentNode); pod != nil {
+			return pod
+		}
+	}
+	return newestRunningPod(pods)
+}
+
 // newestRunningPod takes a list of pods and returns the newest one with status.phase == "Running".
 func newestRunningPod(pods []*corev1.Pod) *corev1.Pod {
 	// Compare two pods based on creation timestamp, breaking ties by name
@@ -500,6 +722,46 @@ func newestRunningPod(pods []*corev1.Pod) *corev1.Pod {
 	return result
 }
 
+// describeAgentPodProblems explains why the containers of the agent pods are not healthy, e.g. when the container
+// image cannot be pulled or when the container keeps crashing, so that the root cause can be seen on the
+// CredentialIssuer. It returns an empty string when none of the pods has a known problem.
+func describeAgentPodProblems(pods []*corev1.Pod) string {
+	sorted := make([]*corev1.Pod, len(pods))
+	copy(sorted, pods)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	var problems []string
+	for _, pod := range sorted {
+		for _, status := range pod.Status.ContainerStatuses {
+			if problem := describeContainerProblem(status); problem != "" {
+				problems = append(problems, fmt.Sprintf("agent pod %s/%s container %q %s", pod.Namespace, pod.Name, status.Name, problem))
+			}
+		}
+	}
+	return strings.Join(problems, "; ")
+}
+
+func describeContainerProblem(status corev1.ContainerStatus) string {
+	var restarts string
+	if status.RestartCount > 0 {
+		restarts = fmt.Sprintf(" (restarted %d times)", status.RestartCount)
+	}
+
+	if waiting := status.State.Waiting; waiting != nil && waiting.Reason != "" && waiting.Reason != "ContainerCreating" {
+		problem := "is waiting: " + waiting.Reason
+		if waiting.Message != "" {
+			problem += ": " + waiting.Message
+		}
+		return problem + restarts
+	}
+
+	if terminated := status.LastTerminationState.Terminated; terminated != nil && status.RestartCount > 0 {
+		return fmt.Sprintf("last terminated: %s (exit code %d)%s", terminated.Reason, terminated.ExitCode, restarts)
+	}
+
+	return ""
+}
+
 func (c *agentController) newAgentDeployment(controllerManagerPod *corev1.Pod) *appsv1.Deployment {
 	var volumeMounts []corev1.VolumeMount
 	if len(controllerManagerPod.Spec.Containers) > 0 {
@@ -525,7 +787,8 @@ func (c *agentController) newAgentDeployment(controllerManagerPod *corev1.Pod) *
 			Selector: metav1.SetAsLabelSelector(c.cfg.agentPodSelectorLabels()),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: c.cfg.agentPodLabels(),
+					Labels:      c.cfg.agentPodLabels(),
+					Annotations: c.cfg.agentPodAnnotations(),
 				},
 				Spec: corev1.PodSpec{
 					TerminationGracePeriodSeconds: pointer.Int64Ptr(0),
@@ -534,9 +797,11 @@ func (c *agentController) newAgentDeployment(controllerManagerPod *corev1.Pod) *
 						{
 							Name:            "sleeper",
 							Image:           c.cfg.ContainerImage,
-							ImagePullPolicy: corev1.PullIfNotPresent,
-							Command:         []string{"pinniped-concierge-kube-cert-agent", "sleep"},
+							ImagePullPolicy: c.cfg.agentContainerImagePullPolicy(),
+							Command:         c.cfg.agentContainerCommand(),
 							VolumeMounts:    volumeMounts,
+							SecurityContext: c.cfg.agentContainerSecurityContext(),
+							ReadinessProbe:  c.cfg.agentPodReadinessProbe(),
 							Env: []corev1.EnvVar{
 								{Name: "CERT_PATH", Value: getContainerArgByName(controllerManagerPod, "cluster-signing-cert-file", "/etc/kubernetes/ca/ca.pem")},
 								{Name: "KEY_PATH", Value: getContainerArgByName(controllerManagerPod, "cluster-signing-key-file", "/etc/kubernetes/ca/ca.key")},
@@ -555,18 +820,14 @@ func (c *agentController) newAgentDeployment(controllerManagerPod *corev1.Pod) *
 					},
 					Volumes:                      controllerManagerPod.Spec.Volumes,
 					RestartPolicy:                corev1.RestartPolicyAlways,
-					NodeSelector:                 controllerManagerPod.Spec.NodeSelector,
+					NodeSelector:                 c.cfg.agentPodNodeSelector(controllerManagerPod),
 					AutomountServiceAccountToken: pointer.BoolPtr(false),
 					ServiceAccountName:           c.cfg.ServiceAccountName,
 					NodeName:                     controllerManagerPod.Spec.NodeName,
-					Tolerations:                  controllerManagerPod.Spec.Tolerations,
-					// We need to run the agent pod as root since the file permissions
-					// on the cluster keypair usually restricts access to only root.
-					SecurityContext: &corev1.PodSecurityContext{
-						RunAsUser:  pointer.Int64Ptr(0),
-						RunAsGroup: pointer.Int64Ptr(0),
-					},
-					HostNetwork: controllerManagerPod.Spec.HostNetwork,
+					Tolerations:                  c.cfg.agentPodTolerations(controllerManagerPod),
+					PriorityClassName:            c.cfg.PriorityClassName,
+					SecurityContext:              c.cfg.agentPodSecurityContext(),
+					HostNetwork:                  controllerManagerPod.Spec.HostNetwork,
 				},
 			},
 
@@ -576,6 +837,64 @@ func (c *agentController) newAgentDeployment(controllerManagerPod *corev1.Pod) *
 	}
 }
 
+// securityContextsEqual returns true when the pod and container security contexts of the Deployments are exactly equal.
+// DeepDerivative is not enough here, because it would ignore any field which is no longer desired.
+func securityContextsEqual(a, b *appsv1.Deployment) bool {
+	aSpec, bSpec := a.Spec.Template.Spec, b.Spec.Template.Spec
+	if !apiequality.Semantic.DeepEqual(aSpec.SecurityContext, bSpec.SecurityContext) || len(aSpec.Containers) != len(bSpec.Containers) {
+		return false
+	}
+	for i := range aSpec.Containers {
+		if !apiequality.Semantic.DeepEqual(aSpec.Containers[i].SecurityContext, bSpec.Containers[i].SecurityContext) {
+			return false
+		}
+	}
+	return true
+}
+
+// readinessProbesEqual returns true when the container readiness probes of the Deployments are exactly equal.
+func readinessProbesEqual(a, b *appsv1.Deployment) bool {
+	aSpec, bSpec := a.Spec.Template.Spec, b.Spec.Template.Spec
+	if len(aSpec.Containers) != len(bSpec.Containers) {
+		return false
+	}
+	for i := range aSpec.Containers {
+		if !apiequality.Semantic.DeepEqual(aSpec.Containers[i].ReadinessProbe, bSpec.Containers[i].ReadinessProbe) {
+			return false
+		}
+	}
+	return true
+}
+
+// containerCommandsEqual returns true when the container commands of the Deployments are exactly equal.
+// DeepDerivative is not enough here, because it would allow the desired command to be a prefix of the existing command.
+func containerCommandsEqual(a, b *appsv1.Deployment) bool {
+	aSpec, bSpec := a.Spec.Template.Spec, b.Spec.Template.Spec
+	if len(aSpec.Containers) != len(bSpec.Containers) {
+		return false
+	}
+	for i := range aSpec.Containers {
+		if !apiequality.Semantic.DeepEqual(aSpec.Containers[i].Command, bSpec.Containers[i].Command) {
+			return false
+		}
+	}
+	return true
+}
+
+// imagePullPoliciesEqual returns true when the container image pull policies of the Deployments are exactly equal.
+func imagePullPoliciesEqual(a, b *appsv1.Deployment) bool {
+	aSpec, bSpec := a.Spec.Template.Spec, b.Spec.Template.Spec
+	if len(aSpec.Containers) != len(bSpec.Containers) {
+		return false
+	}
+	for i := range aSpec.Containers {
+		if aSpec.Containers[i].ImagePullPolicy != bSpec.Containers[i].ImagePullPolicy {
+			return false
+		}
+	}
+	return true
+}
+
 func mergeLabelsAndAnnotations(existing metav1.ObjectMeta, desired metav1.ObjectMeta) metav1.ObjectMeta {
 	result := existing.DeepCopy()
 	for k, v := range desired.Labels {
@@ -593,6 +912,9 @@ func mergeLabelsAndAnnotations(existing metav1.ObjectMeta, desired metav1.Object
 	return *result
 }
 
+// getContainerArgByName returns the non-empty value of the named flag from the command and args of the first container
+// of the pod which sets it, e.g. the --cluster-signing-cert-file flag of kube-controller-manager. It returns the
+// fallback value when no container sets the flag.
 func getContainerArgByName(pod *corev1.Pod, name, fallbackValue string) string {
 	for _, container := range pod.Spec.Containers {
 		flagset := pflag.NewFlagSet("", pflag.ContinueOnError)
@@ -129,14 +129,29 @@ func TestAgentController(t *testing.T) {
 							},
 						},
 						ImagePullPolicy: corev1.PullIfNotPresent,
+						SecurityContext: &corev1.SecurityContext{
+							AllowPrivilegeEscalation: pointer.BoolPtr(false),
+							Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+							ReadOnlyRootFilesystem:   pointer.BoolPtr(true),
+						},
+						ReadinessProbe: &corev1.Probe{
+							ProbeHandler: corev1.ProbeHandler{
+								Exec: &corev1.ExecAction{Command: []string{"pinniped-concierge-kube-cert-agent", "check"}},
+							},
+							TimeoutSeconds:   1,
+							PeriodSeconds:    10,
+							SuccessThreshold: 1,
+							FailureThreshold: 3,
+						},
 					}},
 					RestartPolicy:                 corev1.RestartPolicyAlways,
 					TerminationGracePeriodSeconds: pointer.Int64Ptr(0),
 					ServiceAccountName:            "test-service-account-name",
 					AutomountServiceAccountToken:  pointer.BoolPtr(false),
 					SecurityContext: &corev1.PodSecurityContext{
-						RunAsUser:  pointer.Int64Ptr(0),
-						RunAsGroup: pointer.Int64Ptr(0),
+						RunAsUser:      pointer.Int64Ptr(0),
+						RunAsGroup:     pointer.Int64Ptr(0),
+						SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
 					},
 					ImagePullSecrets: []corev1.LocalObjectReference{{
 						Name: "pinniped-image-pull-secret",
@@ -188,6 +203,26 @@ func TestAgentController(t *testing.T) {
 		{Name: "KEY_PATH", Value: "/etc/kubernetes/ca/ca.key"},
 	}
 
+	// Make another kube-controller-manager pod which passes custom signing cert and key paths as container args
+	// instead of in its command. The agent should be given those paths.
+	healthyKubeControllerManagerPodWithCustomPathArgs := healthyKubeControllerManagerPod.DeepCopy()
+	healthyKubeControllerManagerPodWithCustomPathArgs.Spec.Containers[0].Command = []string{"kube-controller-manager"}
+	healthyKubeControllerManagerPodWithCustomPathArgs.Spec.Containers[0].Args = []string{
+		"--cluster-signing-cert-file=/custom/path/to/ca.crt",
+		"--cluster-signing-key-file", "/custom/path/to/ca.key",
+	}
+	healthyAgentDeploymentWithCustomPaths := healthyAgentDeployment.DeepCopy()
+	healthyAgentDeploymentWithCustomPaths.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{
+		{Name: "CERT_PATH", Value: "/custom/path/to/ca.crt"},
+		{Name: "KEY_PATH", Value: "/custom/path/to/ca.key"},
+	}
+
+	// Make copies of the kube-controller-manager pods which run in a namespace other than kube-system.
+	healthyKubeControllerManagerPodInOtherNamespace := healthyKubeControllerManagerPod.DeepCopy()
+	healthyKubeControllerManagerPodInOtherNamespace.Namespace = "some-controller-manager-namespace"
+	healthyKubeControllerManagerPodWithoutArgsInOtherNamespace := healthyKubeControllerManagerPodWithoutArgs.DeepCopy()
+	healthyKubeControllerManagerPodWithoutArgsInOtherNamespace.Namespace = "some-controller-manager-namespace"
+
 	// If an admission controller sets extra labels or annotations, that's okay.
 	// We test this by ensuring that if a Deployment exists with extra labels, we don't try to delete them.
 	healthyAgentDeploymentWithExtraLabels := healthyAgentDeployment.DeepCopy()
@@ -198,6 +233,108 @@ func TestAgentController(t *testing.T) {
 	agentDeploymentWithExtraLabelsAndWrongImage := healthyAgentDeploymentWithExtraLabels.DeepCopy()
 	agentDeploymentWithExtraLabelsAndWrongImage.Spec.Template.Spec.Containers[0].Image = "wrong-image"
 
+	// The tolerations and node selector from the kube-controller-manager pod should be applied on the deployment,
+	// along with any additional tolerations and node selector entries from the configuration.
+	healthyKubeControllerManagerPodWithScheduling := healthyKubeControllerManagerPod.DeepCopy()
+	healthyKubeControllerManagerPodWithScheduling.Spec.NodeSelector = map[string]string{"kubernetes.io/os": "linux"}
+	healthyKubeControllerManagerPodWithScheduling.Spec.Tolerations = []corev1.Toleration{
+		{Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+	}
+	additionalTolerations := []corev1.Toleration{
+		{Key: "node-role.kubernetes.io/control-plane", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+		{Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute}, // duplicates the controller manager's
+	}
+	additionalNodeSelector := map[string]string{"node-role.kubernetes.io/control-plane": ""}
+	healthyAgentDeploymentWithMergedScheduling := healthyAgentDeployment.DeepCopy()
+	healthyAgentDeploymentWithMergedScheduling.Spec.Template.Spec.NodeSelector = map[string]string{
+		"kubernetes.io/os":                      "linux",
+		"node-role.kubernetes.io/control-plane": "",
+	}
+	healthyAgentDeploymentWithMergedScheduling.Spec.Template.Spec.Tolerations = []corev1.Toleration{
+		{Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+		{Key: "node-role.kubernetes.io/control-plane", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	}
+
+	// A Deployment which selects more nodes than desired must be updated, even though its node selector is a superset.
+	agentDeploymentWithStaleNodeSelector := healthyAgentDeploymentWithMergedScheduling.DeepCopy()
+	agentDeploymentWithStaleNodeSelector.Spec.Template.Spec.NodeSelector["some-stale-label"] = "some-value"
+
+	// A Deployment with the wrong priority class should be changed, including when no priority class is desired.
+	healthyAgentDeploymentWithPriorityClass := healthyAgentDeployment.DeepCopy()
+	healthyAgentDeploymentWithPriorityClass.Spec.Template.Spec.PriorityClassName = "some-priority-class"
+
+	// A Deployment from an older version without the seccomp profile and container security context should be changed.
+	agentDeploymentWithoutSecurityContexts := healthyAgentDeployment.DeepCopy()
+	agentDeploymentWithoutSecurityContexts.Spec.Template.Spec.SecurityContext.SeccompProfile = nil
+	agentDeploymentWithoutSecurityContexts.Spec.Template.Spec.Containers[0].SecurityContext = nil
+
+	// Additional pod labels and annotations are applied only to the pod template, and can never replace the selector label.
+	healthyAgentDeploymentWithPodLabelsAndAnnotations := healthyAgentDeployment.DeepCopy()
+	healthyAgentDeploymentWithPodLabelsAndAnnotations.Spec.Template.Labels["some-pod-label"] = "some-pod-label-value"
+	healthyAgentDeploymentWithPodLabelsAndAnnotations.Spec.Template.Annotations = map[string]string{"some-pod-annotation": "some-pod-annotation-value"}
+
+	// A Deployment with pod annotations which are no longer configured should be changed.
+	agentDeploymentWithStalePodAnnotations := healthyAgentDeployment.DeepCopy()
+	agentDeploymentWithStalePodAnnotations.Spec.Template.Annotations = map[string]string{"some-removed-annotation": "some-value"}
+
+	// The container command can be overridden, which should change the Deployment even when the default command
+	// is a prefix of the existing command.
+	healthyAgentDeploymentWithCustomCommand := healthyAgentDeployment.DeepCopy()
+	healthyAgentDeploymentWithCustomCommand.Spec.Template.Spec.Containers[0].Command = []string{"/custom/sleep", "forever"}
+	healthyAgentDeploymentWithAlwaysPullPolicy := healthyAgentDeployment.DeepCopy()
+	healthyAgentDeploymentWithAlwaysPullPolicy.Spec.Template.Spec.Containers[0].ImagePullPolicy = corev1.PullAlways
+	agentDeploymentWithLongerCommand := healthyAgentDeployment.DeepCopy()
+	agentDeploymentWithLongerCommand.Spec.Template.Spec.Containers[0].Command = []string{"pinniped-concierge-kube-cert-agent", "sleep", "extra-arg"}
+
+	// A Deployment from an older version without a readiness probe should be changed.
+	agentDeploymentWithoutReadinessProbe := healthyAgentDeployment.DeepCopy()
+	agentDeploymentWithoutReadinessProbe.Spec.Template.Spec.Containers[0].ReadinessProbe = nil
+
+	// The readiness probe can be overridden, and any unset fields get the same defaults as the API server would give them.
+	customReadinessProbe := &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			Exec: &corev1.ExecAction{Command: []string{"some-command"}},
+		},
+		PeriodSeconds: 30,
+	}
+	healthyAgentDeploymentWithCustomReadinessProbe := healthyAgentDeployment.DeepCopy()
+	healthyAgentDeploymentWithCustomReadinessProbe.Spec.Template.Spec.Containers[0].ReadinessProbe = &corev1.Probe{
+		ProbeHandler:     customReadinessProbe.ProbeHandler,
+		TimeoutSeconds:   1,
+		PeriodSeconds:    30,
+		SuccessThreshold: 1,
+		FailureThreshold: 3,
+	}
+
+	// A Deployment which still references an image pull secret that was removed from the configuration should be changed.
+	agentDeploymentWithStaleImagePullSecrets := healthyAgentDeployment.DeepCopy()
+	agentDeploymentWithStaleImagePullSecrets.Spec.Template.Spec.ImagePullSecrets = append(
+		agentDeploymentWithStaleImagePullSecrets.Spec.Template.Spec.ImagePullSecrets,
+		corev1.LocalObjectReference{Name: "some-removed-image-pull-secret"},
+	)
+
+	// The security contexts can be overridden, in which case the defaults are not used at all.
+	customPodSecurityContext := &corev1.PodSecurityContext{RunAsUser: pointer.Int64Ptr(0)}
+	customContainerSecurityContext := &corev1.SecurityContext{ReadOnlyRootFilesystem: pointer.BoolPtr(true)}
+	healthyAgentDeploymentWithCustomSecurityContexts := healthyAgentDeployment.DeepCopy()
+	healthyAgentDeploymentWithCustomSecurityContexts.Spec.Template.Spec.SecurityContext = customPodSecurityContext
+	healthyAgentDeploymentWithCustomSecurityContexts.Spec.Template.Spec.Containers[0].SecurityContext = customContainerSecurityContext
+
+	// On HA control planes, there is one kube-controller-manager pod per control plane node.
+	kubeControllerManagerPodOnNode := func(podName, nodeName string, age time.Duration, phase corev1.PodPhase) *corev1.Pod {
+		pod := healthyKubeControllerManagerPod.DeepCopy()
+		pod.Name = podName
+		pod.CreationTimestamp = metav1.NewTime(now.Add(-age))
+		pod.Spec.NodeName = nodeName
+		pod.Status.Phase = phase
+		return pod
+	}
+	healthyAgentDeploymentOnNode := func(nodeName string) *appsv1.Deployment {
+		deployment := healthyAgentDeployment.DeepCopy()
+		deployment.Spec.Template.Spec.NodeName = nodeName
+		return deployment
+	}
+
 	healthyAgentPod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:         "concierge",
@@ -212,6 +349,30 @@ func TestAgentController(t *testing.T) {
 	pendingAgentPod := healthyAgentPod.DeepCopy()
 	pendingAgentPod.Status.Phase = corev1.PodPending
 
+	// An agent pod which is stuck because its container image cannot be pulled.
+	imagePullBackOffAgentPod := pendingAgentPod.DeepCopy()
+	imagePullBackOffAgentPod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name: "sleeper",
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+			Reason:  "ImagePullBackOff",
+			Message: `Back-off pulling image "pinniped-server-image"`,
+		}},
+	}}
+
+	// An agent pod which is still running but whose container keeps crashing.
+	crashLoopBackOffAgentPod := healthyAgentPod.DeepCopy()
+	crashLoopBackOffAgentPod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:         "sleeper",
+		RestartCount: 5,
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+			Reason: "CrashLoopBackOff",
+		}},
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			Reason:   "Error",
+			ExitCode: 1,
+		}},
+	}}
+
 	validClusterInfoConfigMap := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-public", Name: "cluster-info"},
 		Data: map[string]string{"kubeconfig": here.Docf(`
@@ -236,6 +397,18 @@ func TestAgentController(t *testing.T) {
 	tests := []struct {
 		name                             string
 		discoveryURLOverride             *string
+		additionalTolerations            []corev1.Toleration
+		additionalNodeSelector           map[string]string
+		priorityClassName                string
+		podSecurityContext               *corev1.PodSecurityContext
+		containerSecurityContext         *corev1.SecurityContext
+		readinessProbe                   *corev1.Probe
+		containerCommand                 []string
+		imagePullPolicy                  corev1.PullPolicy
+		controllerManagerNamespace       string
+		preferredControllerManagerPod    string
+		additionalPodLabels              map[string]string
+		additionalPodAnnotations         map[string]string
 		pinnipedObjects                  []runtime.Object
 		kubeObjects                      []runtime.Object
 		addKubeReactions                 func(*kubefake.Clientset)
@@ -322,6 +495,78 @@ func TestAgentController(t *testing.T) {
 				LastUpdateTime: metav1.NewTime(now),
 			},
 		},
+		{
+			name: "kube-controller-manager pods only in a namespace other than the default controller manager namespace",
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPodInOtherNamespace,
+			},
+			wantDistinctErrors: []string{
+				"could not find a healthy kube-controller-manager pod (0 candidates)",
+			},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotFetchKeyStrategyReason,
+				Message:        "could not find a healthy kube-controller-manager pod (0 candidates)",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+		},
+		{
+			name:                       "deployment exists, uses the kube-controller-manager pod from the configured controller manager namespace",
+			controllerManagerNamespace: "some-controller-manager-namespace",
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPodWithoutArgs,
+				healthyKubeControllerManagerPodInOtherNamespace,
+				healthyAgentDeployment,
+				healthyAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+			},
+			wantAgentDeployment:       healthyAgentDeployment,
+			wantDeploymentActionVerbs: []string{"list", "watch"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+		},
+		{
+			name:                       "deployment exists, updates it for the kube-controller-manager pod from the configured controller manager namespace",
+			controllerManagerNamespace: "some-controller-manager-namespace",
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPod,
+				healthyKubeControllerManagerPodWithoutArgsInOtherNamespace,
+				healthyAgentDeployment,
+				healthyAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+			},
+			wantAgentDeployment:       healthyAgentDeploymentWithDefaultedPaths,
+			wantDeploymentActionVerbs: []string{"list", "watch", "update"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+			wantDistinctLogs: []string{
+				`kube-cert-agent-controller "level"=0 "msg"="updating existing deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"some-controller-manager-namespace"}`,
+			},
+		},
 		{
 			name: "failed to created new deployment",
 			pinnipedObjects: []runtime.Object{
@@ -437,180 +682,900 @@ func TestAgentController(t *testing.T) {
 			wantDistinctLogs: []string{
 				`kube-cert-agent-controller "level"=0 "msg"="creating new deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
 			},
-			wantAgentDeployment:       healthyAgentDeploymentWithDefaultedPaths,
-			wantDeploymentActionVerbs: []string{"list", "watch", "create"},
+			wantAgentDeployment:       healthyAgentDeploymentWithDefaultedPaths,
+			wantDeploymentActionVerbs: []string{"list", "watch", "create"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotFetchKeyStrategyReason,
+				Message:        "could not find a healthy agent pod (1 candidate)",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+		},
+		{
+			name: "created new deployment with custom paths from the kube-controller-manager args, no agent pods running yet",
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:         "kube-system",
+						Name:              "kube-controller-manager-3",
+						Labels:            map[string]string{"component": "kube-controller-manager"},
+						CreationTimestamp: metav1.NewTime(now.Add(-1 * time.Hour)),
+					},
+					Spec:   corev1.PodSpec{},
+					Status: corev1.PodStatus{Phase: corev1.PodRunning},
+				},
+				healthyKubeControllerManagerPodWithCustomPathArgs,
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:         "kube-system",
+						Name:              "kube-controller-manager-2",
+						Labels:            map[string]string{"component": "kube-controller-manager"},
+						CreationTimestamp: metav1.NewTime(now.Add(-2 * time.Hour)),
+					},
+					Spec:   corev1.PodSpec{},
+					Status: corev1.PodStatus{Phase: corev1.PodRunning},
+				},
+				pendingAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"could not find a healthy agent pod (1 candidate)",
+			},
+			alsoAllowUndesiredDistinctErrors: []string{
+				// due to the high amount of nondeterminism in this test, this error will sometimes also happen, but is not required to happen
+				`could not ensure agent deployment: deployments.apps "pinniped-concierge-kube-cert-agent" already exists`,
+			},
+			wantDistinctLogs: []string{
+				`kube-cert-agent-controller "level"=0 "msg"="creating new deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+			},
+			wantAgentDeployment:       healthyAgentDeploymentWithCustomPaths,
+			wantDeploymentActionVerbs: []string{"list", "watch", "create"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotFetchKeyStrategyReason,
+				Message:        "could not find a healthy agent pod (1 candidate)",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+		},
+		{
+			name: "to support upgrade from old versions, update to immutable selector field of existing deployment causes delete and recreate, no running agent pods yet",
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPod,
+				healthyAgentDeploymentWithOldStyleSelector,
+				pendingAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"could not find a healthy agent pod (1 candidate)",
+			},
+			wantDistinctLogs: []string{
+				`kube-cert-agent-controller "level"=0 "msg"="deleting deployment to update immutable Selector field" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+				`kube-cert-agent-controller "level"=0 "msg"="creating new deployment to update immutable Selector field" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+			},
+			wantAgentDeployment:       healthyAgentDeployment,
+			wantDeploymentActionVerbs: []string{"list", "watch", "delete", "create"}, // must recreate deployment when Selector field changes
+			wantDeploymentDeleteActionOpts: []metav1.DeleteOptions{
+				testutil.NewPreconditions(healthyAgentDeploymentWithOldStyleSelector.UID, healthyAgentDeploymentWithOldStyleSelector.ResourceVersion),
+			},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotFetchKeyStrategyReason,
+				Message:        "could not find a healthy agent pod (1 candidate)",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+		},
+		{
+			name: "to support upgrade from old versions, update to immutable selector field of existing deployment causes delete and recreate, when delete fails",
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPod,
+				healthyAgentDeploymentWithOldStyleSelector,
+				pendingAgentPod,
+			},
+			addKubeReactions: func(clientset *kubefake.Clientset) {
+				clientset.PrependReactor("delete", "deployments", func(action coretesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, nil, fmt.Errorf("some delete error")
+				})
+			},
+			wantDistinctErrors: []string{
+				"could not ensure agent deployment: some delete error",
+			},
+			wantDistinctLogs: []string{
+				`kube-cert-agent-controller "level"=0 "msg"="deleting deployment to update immutable Selector field" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+			},
+			wantAgentDeployment: healthyAgentDeploymentWithOldStyleSelector, // couldn't be deleted, so it didn't change
+			// delete to try to recreate deployment when Selector field changes, but delete always fails, so keeps trying to delete
+			wantDeploymentActionVerbs: []string{"list", "watch", "delete", "delete"},
+			wantDeploymentDeleteActionOpts: []metav1.DeleteOptions{
+				testutil.NewPreconditions(healthyAgentDeploymentWithOldStyleSelector.UID, healthyAgentDeploymentWithOldStyleSelector.ResourceVersion),
+				testutil.NewPreconditions(healthyAgentDeploymentWithOldStyleSelector.UID, healthyAgentDeploymentWithOldStyleSelector.ResourceVersion),
+			},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotFetchKeyStrategyReason,
+				Message:        "could not ensure agent deployment: some delete error",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+		},
+		{
+			name: "to support upgrade from old versions, update to immutable selector field of existing deployment causes delete and recreate, when delete succeeds but create fails",
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPod,
+				healthyAgentDeploymentWithOldStyleSelector,
+				pendingAgentPod,
+			},
+			addKubeReactions: func(clientset *kubefake.Clientset) {
+				clientset.PrependReactor("create", "deployments", func(action coretesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, nil, fmt.Errorf("some create error")
+				})
+			},
+			wantDistinctErrors: []string{
+				"could not ensure agent deployment: some create error",
+			},
+			wantDistinctLogs: []string{
+				`kube-cert-agent-controller "level"=0 "msg"="deleting deployment to update immutable Selector field" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+				`kube-cert-agent-controller "level"=0 "msg"="creating new deployment to update immutable Selector field" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+				`kube-cert-agent-controller "level"=0 "msg"="creating new deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+			},
+			wantAgentDeployment: nil, // was deleted, but couldn't be recreated
+			// delete to try to recreate deployment when Selector field changes, but create always fails, so keeps trying to recreate
+			wantDeploymentActionVerbs: []string{"list", "watch", "delete", "create", "create"},
+			wantDeploymentDeleteActionOpts: []metav1.DeleteOptions{
+				testutil.NewPreconditions(healthyAgentDeploymentWithOldStyleSelector.UID, healthyAgentDeploymentWithOldStyleSelector.ResourceVersion),
+			},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotFetchKeyStrategyReason,
+				Message:        "could not ensure agent deployment: some create error",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+		},
+		{
+			name: "update to existing deployment, no running agent pods yet",
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPod,
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:         "kube-system",
+						Name:              "kube-controller-manager-3",
+						Labels:            map[string]string{"component": "kube-controller-manager"},
+						CreationTimestamp: metav1.NewTime(now.Add(-1 * time.Hour)),
+					},
+					Spec:   corev1.PodSpec{},
+					Status: corev1.PodStatus{Phase: corev1.PodRunning},
+				},
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:         "kube-system",
+						Name:              "kube-controller-manager-2",
+						Labels:            map[string]string{"component": "kube-controller-manager"},
+						CreationTimestamp: metav1.NewTime(now.Add(-2 * time.Hour)),
+					},
+					Spec:   corev1.PodSpec{},
+					Status: corev1.PodStatus{Phase: corev1.PodRunning},
+				},
+				agentDeploymentWithExtraLabelsAndWrongImage,
+				pendingAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"could not find a healthy agent pod (1 candidate)",
+			},
+			wantDistinctLogs: []string{
+				`kube-cert-agent-controller "level"=0 "msg"="updating existing deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+			},
+			wantAgentDeployment:       healthyAgentDeploymentWithExtraLabels,
+			wantDeploymentActionVerbs: []string{"list", "watch", "update"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotFetchKeyStrategyReason,
+				Message:        "could not find a healthy agent pod (1 candidate)",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+		},
+		{
+			name: "deployment exists, but missing host network from kube-controller-manager",
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPodWithHostNetwork,
+				healthyAgentDeployment,
+				healthyAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+			},
+			wantAgentDeployment:       healthyAgentDeploymentWithHostNetwork,
+			wantDeploymentActionVerbs: []string{"list", "watch", "update"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+			wantDistinctLogs: []string{
+				`kube-cert-agent-controller "level"=0 "msg"="updating existing deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+			},
+		},
+		{
+			name:                   "deployment exists, but missing additional tolerations and node selector",
+			additionalTolerations:  additionalTolerations,
+			additionalNodeSelector: additionalNodeSelector,
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPodWithScheduling,
+				healthyAgentDeployment,
+				healthyAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+			},
+			wantAgentDeployment:       healthyAgentDeploymentWithMergedScheduling,
+			wantDeploymentActionVerbs: []string{"list", "watch", "update"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+			wantDistinctLogs: []string{
+				`kube-cert-agent-controller "level"=0 "msg"="updating existing deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+			},
+		},
+		{
+			name:                   "deployment exists with merged tolerations and node selector",
+			additionalTolerations:  additionalTolerations,
+			additionalNodeSelector: additionalNodeSelector,
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPodWithScheduling,
+				healthyAgentDeploymentWithMergedScheduling,
+				healthyAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+			},
+			wantAgentDeployment:       healthyAgentDeploymentWithMergedScheduling,
+			wantDeploymentActionVerbs: []string{"list", "watch"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+		},
+		{
+			name:                   "deployment exists, but has a stale node selector entry",
+			additionalTolerations:  additionalTolerations,
+			additionalNodeSelector: additionalNodeSelector,
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPodWithScheduling,
+				agentDeploymentWithStaleNodeSelector,
+				healthyAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+			},
+			wantAgentDeployment:       healthyAgentDeploymentWithMergedScheduling,
+			wantDeploymentActionVerbs: []string{"list", "watch", "update"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+			wantDistinctLogs: []string{
+				`kube-cert-agent-controller "level"=0 "msg"="updating existing deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+			},
+		},
+		{
+			name:              "deployment exists, but missing the configured priority class",
+			priorityClassName: "some-priority-class",
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPod,
+				healthyAgentDeployment,
+				healthyAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+			},
+			wantAgentDeployment:       healthyAgentDeploymentWithPriorityClass,
+			wantDeploymentActionVerbs: []string{"list", "watch", "update"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+			wantDistinctLogs: []string{
+				`kube-cert-agent-controller "level"=0 "msg"="updating existing deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+			},
+		},
+		{
+			name: "deployment exists, but has stale cert and key paths after the kube-controller-manager flags changed",
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPodWithCustomPathArgs,
+				healthyAgentDeployment,
+				healthyAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+			},
+			wantAgentDeployment:       healthyAgentDeploymentWithCustomPaths,
+			wantDeploymentActionVerbs: []string{"list", "watch", "update"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+			wantDistinctLogs: []string{
+				`kube-cert-agent-controller "level"=0 "msg"="updating existing deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+			},
+		},
+		{
+			name: "deployment exists, but has a priority class when none is configured",
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPod,
+				healthyAgentDeploymentWithPriorityClass,
+				healthyAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+			},
+			wantAgentDeployment:       healthyAgentDeployment,
+			wantDeploymentActionVerbs: []string{"list", "watch", "update"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+			wantDistinctLogs: []string{
+				`kube-cert-agent-controller "level"=0 "msg"="updating existing deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+			},
+		},
+		{
+			name: "deployment exists, but missing the default security contexts",
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPod,
+				agentDeploymentWithoutSecurityContexts,
+				healthyAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+			},
+			wantAgentDeployment:       healthyAgentDeployment,
+			wantDeploymentActionVerbs: []string{"list", "watch", "update"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+			wantDistinctLogs: []string{
+				`kube-cert-agent-controller "level"=0 "msg"="updating existing deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+			},
+		},
+		{
+			name:             "deployment exists, but the container command was overridden",
+			containerCommand: []string{"/custom/sleep", "forever"},
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPod,
+				healthyAgentDeployment,
+				healthyAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+			},
+			wantAgentDeployment:       healthyAgentDeploymentWithCustomCommand,
+			wantDeploymentActionVerbs: []string{"list", "watch", "update"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+			wantDistinctLogs: []string{
+				`kube-cert-agent-controller "level"=0 "msg"="updating existing deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+			},
+		},
+		{
+			name:            "deployment exists, but the image pull policy was overridden",
+			imagePullPolicy: corev1.PullAlways,
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPod,
+				healthyAgentDeployment,
+				healthyAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+			},
+			wantAgentDeployment:       healthyAgentDeploymentWithAlwaysPullPolicy,
+			wantDeploymentActionVerbs: []string{"list", "watch", "update"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+			wantDistinctLogs: []string{
+				`kube-cert-agent-controller "level"=0 "msg"="updating existing deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+			},
+		},
+		{
+			name: "deployment exists, but has a stale image pull policy",
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPod,
+				healthyAgentDeploymentWithAlwaysPullPolicy,
+				healthyAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+			},
+			wantAgentDeployment:       healthyAgentDeployment,
+			wantDeploymentActionVerbs: []string{"list", "watch", "update"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+			wantDistinctLogs: []string{
+				`kube-cert-agent-controller "level"=0 "msg"="updating existing deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+			},
+		},
+		{
+			name: "deployment exists, but has a stale container command which starts with the default command",
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPod,
+				agentDeploymentWithLongerCommand,
+				healthyAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+			},
+			wantAgentDeployment:       healthyAgentDeployment,
+			wantDeploymentActionVerbs: []string{"list", "watch", "update"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+			wantDistinctLogs: []string{
+				`kube-cert-agent-controller "level"=0 "msg"="updating existing deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+			},
+		},
+		{
+			name: "deployment exists, but missing the configured pod labels and annotations",
+			additionalPodLabels: map[string]string{
+				"some-pod-label":               "some-pod-label-value",
+				"kube-cert-agent.pinniped.dev": "not-allowed-to-replace-the-selector-label",
+				"app":                          "not-allowed-either",
+			},
+			additionalPodAnnotations: map[string]string{"some-pod-annotation": "some-pod-annotation-value"},
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPod,
+				healthyAgentDeployment,
+				healthyAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+			},
+			wantAgentDeployment:       healthyAgentDeploymentWithPodLabelsAndAnnotations,
+			wantDeploymentActionVerbs: []string{"list", "watch", "update"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+			wantDistinctLogs: []string{
+				`kube-cert-agent-controller "level"=0 "msg"="updating existing deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+			},
+		},
+		{
+			name: "deployment exists, but has stale pod annotations",
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPod,
+				agentDeploymentWithStalePodAnnotations,
+				healthyAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+			},
+			wantAgentDeployment:       healthyAgentDeployment,
+			wantDeploymentActionVerbs: []string{"list", "watch", "update"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+			wantDistinctLogs: []string{
+				`kube-cert-agent-controller "level"=0 "msg"="updating existing deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+			},
+		},
+		{
+			name: "deployment exists, but missing the default readiness probe",
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPod,
+				agentDeploymentWithoutReadinessProbe,
+				healthyAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+			},
+			wantAgentDeployment:       healthyAgentDeployment,
+			wantDeploymentActionVerbs: []string{"list", "watch", "update"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+			wantDistinctLogs: []string{
+				`kube-cert-agent-controller "level"=0 "msg"="updating existing deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+			},
+		},
+		{
+			name:           "deployment exists, but the readiness probe was overridden",
+			readinessProbe: customReadinessProbe,
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPod,
+				healthyAgentDeployment,
+				healthyAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+			},
+			wantAgentDeployment:       healthyAgentDeploymentWithCustomReadinessProbe,
+			wantDeploymentActionVerbs: []string{"list", "watch", "update"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+			wantDistinctLogs: []string{
+				`kube-cert-agent-controller "level"=0 "msg"="updating existing deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+			},
+		},
+		{
+			name: "deployment exists, but has a stale image pull secret",
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPod,
+				agentDeploymentWithStaleImagePullSecrets,
+				healthyAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+			},
+			wantAgentDeployment:       healthyAgentDeployment,
+			wantDeploymentActionVerbs: []string{"list", "watch", "update"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+			wantDistinctLogs: []string{
+				`kube-cert-agent-controller "level"=0 "msg"="updating existing deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+			},
+		},
+		{
+			name:                     "deployment exists, but the security contexts were overridden",
+			podSecurityContext:       customPodSecurityContext,
+			containerSecurityContext: customContainerSecurityContext,
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPod,
+				healthyAgentDeployment,
+				healthyAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+			},
+			wantAgentDeployment:       healthyAgentDeploymentWithCustomSecurityContexts,
+			wantDeploymentActionVerbs: []string{"list", "watch", "update"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+			wantDistinctLogs: []string{
+				`kube-cert-agent-controller "level"=0 "msg"="updating existing deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+			},
+		},
+		{
+			name: "multiple kube-controller-manager pods, deployment exists on the node of an older running pod, keeps the deployment on that node",
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				kubeControllerManagerPodOnNode("kube-controller-manager-node-1", "node-1", 1*time.Hour, corev1.PodRunning),
+				kubeControllerManagerPodOnNode("kube-controller-manager-node-2", "node-2", 2*time.Hour, corev1.PodRunning),
+				kubeControllerManagerPodOnNode("kube-controller-manager-node-3", "node-3", 3*time.Hour, corev1.PodRunning),
+				healthyAgentDeploymentOnNode("node-2"),
+				healthyAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+			},
+			wantAgentDeployment:       healthyAgentDeploymentOnNode("node-2"),
+			wantDeploymentActionVerbs: []string{"list", "watch"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+		},
+		{
+			name: "multiple kube-controller-manager pods, deployment exists on a node whose pod is no longer running, moves the deployment to the newest running pod",
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				kubeControllerManagerPodOnNode("kube-controller-manager-node-1", "node-1", 1*time.Hour, corev1.PodRunning),
+				kubeControllerManagerPodOnNode("kube-controller-manager-node-2", "node-2", 2*time.Hour, corev1.PodFailed),
+				kubeControllerManagerPodOnNode("kube-controller-manager-node-3", "node-3", 3*time.Hour, corev1.PodRunning),
+				healthyAgentDeploymentOnNode("node-2"),
+				healthyAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+			},
+			wantAgentDeployment:       healthyAgentDeploymentOnNode("node-1"),
+			wantDeploymentActionVerbs: []string{"list", "watch", "update"},
 			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
 				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
 				Status:         configv1alpha1.ErrorStrategyStatus,
-				Reason:         configv1alpha1.CouldNotFetchKeyStrategyReason,
-				Message:        "could not find a healthy agent pod (1 candidate)",
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
 				LastUpdateTime: metav1.NewTime(now),
 			},
+			wantDistinctLogs: []string{
+				`kube-cert-agent-controller "level"=0 "msg"="updating existing deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-node-1","namespace":"kube-system"}`,
+			},
 		},
 		{
-			name: "to support upgrade from old versions, update to immutable selector field of existing deployment causes delete and recreate, no running agent pods yet",
+			name: "multiple kube-controller-manager pods, deployment exists on a node which has gone away, moves the deployment to the newest running pod",
 			pinnipedObjects: []runtime.Object{
 				initialCredentialIssuer,
 			},
 			kubeObjects: []runtime.Object{
-				healthyKubeControllerManagerPod,
-				healthyAgentDeploymentWithOldStyleSelector,
-				pendingAgentPod,
+				kubeControllerManagerPodOnNode("kube-controller-manager-node-1", "node-1", 1*time.Hour, corev1.PodRunning),
+				kubeControllerManagerPodOnNode("kube-controller-manager-node-3", "node-3", 3*time.Hour, corev1.PodRunning),
+				healthyAgentDeploymentOnNode("node-2"),
+				healthyAgentPod,
 			},
 			wantDistinctErrors: []string{
-				"could not find a healthy agent pod (1 candidate)",
-			},
-			wantDistinctLogs: []string{
-				`kube-cert-agent-controller "level"=0 "msg"="deleting deployment to update immutable Selector field" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
-				`kube-cert-agent-controller "level"=0 "msg"="creating new deployment to update immutable Selector field" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
-			},
-			wantAgentDeployment:       healthyAgentDeployment,
-			wantDeploymentActionVerbs: []string{"list", "watch", "delete", "create"}, // must recreate deployment when Selector field changes
-			wantDeploymentDeleteActionOpts: []metav1.DeleteOptions{
-				testutil.NewPreconditions(healthyAgentDeploymentWithOldStyleSelector.UID, healthyAgentDeploymentWithOldStyleSelector.ResourceVersion),
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
 			},
+			wantAgentDeployment:       healthyAgentDeploymentOnNode("node-1"),
+			wantDeploymentActionVerbs: []string{"list", "watch", "update"},
 			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
 				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
 				Status:         configv1alpha1.ErrorStrategyStatus,
-				Reason:         configv1alpha1.CouldNotFetchKeyStrategyReason,
-				Message:        "could not find a healthy agent pod (1 candidate)",
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
 				LastUpdateTime: metav1.NewTime(now),
 			},
+			wantDistinctLogs: []string{
+				`kube-cert-agent-controller "level"=0 "msg"="updating existing deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-node-1","namespace":"kube-system"}`,
+			},
 		},
 		{
-			name: "to support upgrade from old versions, update to immutable selector field of existing deployment causes delete and recreate, when delete fails",
+			name:                          "multiple kube-controller-manager pods, preferred pod is running, moves the deployment to the preferred pod",
+			preferredControllerManagerPod: "kube-controller-manager-node-3",
 			pinnipedObjects: []runtime.Object{
 				initialCredentialIssuer,
 			},
 			kubeObjects: []runtime.Object{
-				healthyKubeControllerManagerPod,
-				healthyAgentDeploymentWithOldStyleSelector,
-				pendingAgentPod,
-			},
-			addKubeReactions: func(clientset *kubefake.Clientset) {
-				clientset.PrependReactor("delete", "deployments", func(action coretesting.Action) (handled bool, ret runtime.Object, err error) {
-					return true, nil, fmt.Errorf("some delete error")
-				})
+				kubeControllerManagerPodOnNode("kube-controller-manager-node-1", "node-1", 1*time.Hour, corev1.PodRunning),
+				kubeControllerManagerPodOnNode("kube-controller-manager-node-2", "node-2", 2*time.Hour, corev1.PodRunning),
+				kubeControllerManagerPodOnNode("kube-controller-manager-node-3", "node-3", 3*time.Hour, corev1.PodRunning),
+				healthyAgentDeploymentOnNode("node-2"),
+				healthyAgentPod,
 			},
 			wantDistinctErrors: []string{
-				"could not ensure agent deployment: some delete error",
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+			},
+			wantAgentDeployment:       healthyAgentDeploymentOnNode("node-3"),
+			wantDeploymentActionVerbs: []string{"list", "watch", "update"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+				LastUpdateTime: metav1.NewTime(now),
 			},
 			wantDistinctLogs: []string{
-				`kube-cert-agent-controller "level"=0 "msg"="deleting deployment to update immutable Selector field" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+				`kube-cert-agent-controller "level"=0 "msg"="updating existing deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-node-3","namespace":"kube-system"}`,
 			},
-			wantAgentDeployment: healthyAgentDeploymentWithOldStyleSelector, // couldn't be deleted, so it didn't change
-			// delete to try to recreate deployment when Selector field changes, but delete always fails, so keeps trying to delete
-			wantDeploymentActionVerbs: []string{"list", "watch", "delete", "delete"},
-			wantDeploymentDeleteActionOpts: []metav1.DeleteOptions{
-				testutil.NewPreconditions(healthyAgentDeploymentWithOldStyleSelector.UID, healthyAgentDeploymentWithOldStyleSelector.ResourceVersion),
-				testutil.NewPreconditions(healthyAgentDeploymentWithOldStyleSelector.UID, healthyAgentDeploymentWithOldStyleSelector.ResourceVersion),
+		},
+		{
+			name:                          "multiple kube-controller-manager pods, deployment exists on the node of the running preferred pod, keeps the deployment on that node",
+			preferredControllerManagerPod: "kube-controller-manager-node-3",
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
 			},
+			kubeObjects: []runtime.Object{
+				kubeControllerManagerPodOnNode("kube-controller-manager-node-1", "node-1", 1*time.Hour, corev1.PodRunning),
+				kubeControllerManagerPodOnNode("kube-controller-manager-node-2", "node-2", 2*time.Hour, corev1.PodRunning),
+				kubeControllerManagerPodOnNode("kube-controller-manager-node-3", "node-3", 3*time.Hour, corev1.PodRunning),
+				healthyAgentDeploymentOnNode("node-3"),
+				healthyAgentPod,
+			},
+			wantDistinctErrors: []string{
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
+			},
+			wantAgentDeployment:       healthyAgentDeploymentOnNode("node-3"),
+			wantDeploymentActionVerbs: []string{"list", "watch"},
 			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
 				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
 				Status:         configv1alpha1.ErrorStrategyStatus,
-				Reason:         configv1alpha1.CouldNotFetchKeyStrategyReason,
-				Message:        "could not ensure agent deployment: some delete error",
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
 				LastUpdateTime: metav1.NewTime(now),
 			},
 		},
 		{
-			name: "to support upgrade from old versions, update to immutable selector field of existing deployment causes delete and recreate, when delete succeeds but create fails",
+			name:                          "multiple kube-controller-manager pods, preferred pod does not exist, keeps the deployment on its current node",
+			preferredControllerManagerPod: "kube-controller-manager-does-not-exist",
 			pinnipedObjects: []runtime.Object{
 				initialCredentialIssuer,
 			},
 			kubeObjects: []runtime.Object{
-				healthyKubeControllerManagerPod,
-				healthyAgentDeploymentWithOldStyleSelector,
-				pendingAgentPod,
-			},
-			addKubeReactions: func(clientset *kubefake.Clientset) {
-				clientset.PrependReactor("create", "deployments", func(action coretesting.Action) (handled bool, ret runtime.Object, err error) {
-					return true, nil, fmt.Errorf("some create error")
-				})
+				kubeControllerManagerPodOnNode("kube-controller-manager-node-1", "node-1", 1*time.Hour, corev1.PodRunning),
+				kubeControllerManagerPodOnNode("kube-controller-manager-node-2", "node-2", 2*time.Hour, corev1.PodRunning),
+				kubeControllerManagerPodOnNode("kube-controller-manager-node-3", "node-3", 3*time.Hour, corev1.PodRunning),
+				healthyAgentDeploymentOnNode("node-2"),
+				healthyAgentPod,
 			},
 			wantDistinctErrors: []string{
-				"could not ensure agent deployment: some create error",
-			},
-			wantDistinctLogs: []string{
-				`kube-cert-agent-controller "level"=0 "msg"="deleting deployment to update immutable Selector field" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
-				`kube-cert-agent-controller "level"=0 "msg"="creating new deployment to update immutable Selector field" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
-				`kube-cert-agent-controller "level"=0 "msg"="creating new deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
-			},
-			wantAgentDeployment: nil, // was deleted, but couldn't be recreated
-			// delete to try to recreate deployment when Selector field changes, but create always fails, so keeps trying to recreate
-			wantDeploymentActionVerbs: []string{"list", "watch", "delete", "create", "create"},
-			wantDeploymentDeleteActionOpts: []metav1.DeleteOptions{
-				testutil.NewPreconditions(healthyAgentDeploymentWithOldStyleSelector.UID, healthyAgentDeploymentWithOldStyleSelector.ResourceVersion),
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
 			},
+			wantAgentDeployment:       healthyAgentDeploymentOnNode("node-2"),
+			wantDeploymentActionVerbs: []string{"list", "watch"},
 			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
 				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
 				Status:         configv1alpha1.ErrorStrategyStatus,
-				Reason:         configv1alpha1.CouldNotFetchKeyStrategyReason,
-				Message:        "could not ensure agent deployment: some create error",
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
 				LastUpdateTime: metav1.NewTime(now),
 			},
 		},
 		{
-			name: "update to existing deployment, no running agent pods yet",
+			name:                          "multiple kube-controller-manager pods, preferred pod is not running, keeps the deployment on its current node",
+			preferredControllerManagerPod: "kube-controller-manager-node-3",
 			pinnipedObjects: []runtime.Object{
 				initialCredentialIssuer,
 			},
 			kubeObjects: []runtime.Object{
-				healthyKubeControllerManagerPod,
-				&corev1.Pod{
-					ObjectMeta: metav1.ObjectMeta{
-						Namespace:         "kube-system",
-						Name:              "kube-controller-manager-3",
-						Labels:            map[string]string{"component": "kube-controller-manager"},
-						CreationTimestamp: metav1.NewTime(now.Add(-1 * time.Hour)),
-					},
-					Spec:   corev1.PodSpec{},
-					Status: corev1.PodStatus{Phase: corev1.PodRunning},
-				},
-				&corev1.Pod{
-					ObjectMeta: metav1.ObjectMeta{
-						Namespace:         "kube-system",
-						Name:              "kube-controller-manager-2",
-						Labels:            map[string]string{"component": "kube-controller-manager"},
-						CreationTimestamp: metav1.NewTime(now.Add(-2 * time.Hour)),
-					},
-					Spec:   corev1.PodSpec{},
-					Status: corev1.PodStatus{Phase: corev1.PodRunning},
-				},
-				agentDeploymentWithExtraLabelsAndWrongImage,
-				pendingAgentPod,
+				kubeControllerManagerPodOnNode("kube-controller-manager-node-1", "node-1", 1*time.Hour, corev1.PodRunning),
+				kubeControllerManagerPodOnNode("kube-controller-manager-node-2", "node-2", 2*time.Hour, corev1.PodRunning),
+				kubeControllerManagerPodOnNode("kube-controller-manager-node-3", "node-3", 3*time.Hour, corev1.PodFailed),
+				healthyAgentDeploymentOnNode("node-2"),
+				healthyAgentPod,
 			},
 			wantDistinctErrors: []string{
-				"could not find a healthy agent pod (1 candidate)",
-			},
-			wantDistinctLogs: []string{
-				`kube-cert-agent-controller "level"=0 "msg"="updating existing deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
 			},
-			wantAgentDeployment:       healthyAgentDeploymentWithExtraLabels,
-			wantDeploymentActionVerbs: []string{"list", "watch", "update"},
+			wantAgentDeployment:       healthyAgentDeploymentOnNode("node-2"),
+			wantDeploymentActionVerbs: []string{"list", "watch"},
 			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
 				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
 				Status:         configv1alpha1.ErrorStrategyStatus,
-				Reason:         configv1alpha1.CouldNotFetchKeyStrategyReason,
-				Message:        "could not find a healthy agent pod (1 candidate)",
+				Reason:         configv1alpha1.CouldNotGetClusterInfoStrategyReason,
+				Message:        "failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
 				LastUpdateTime: metav1.NewTime(now),
 			},
 		},
 		{
-			name: "deployment exists, but missing host network from kube-controller-manager",
+			name: "multiple kube-controller-manager pods, no deployment exists yet, creates the deployment for the newest running pod",
 			pinnipedObjects: []runtime.Object{
 				initialCredentialIssuer,
 			},
 			kubeObjects: []runtime.Object{
-				healthyKubeControllerManagerPodWithHostNetwork,
-				healthyAgentDeployment,
+				kubeControllerManagerPodOnNode("kube-controller-manager-node-1", "node-1", 1*time.Hour, corev1.PodRunning),
+				kubeControllerManagerPodOnNode("kube-controller-manager-node-2", "node-2", 2*time.Hour, corev1.PodRunning),
+				kubeControllerManagerPodOnNode("kube-controller-manager-node-3", "node-3", 3*time.Hour, corev1.PodRunning),
 				healthyAgentPod,
 			},
 			wantDistinctErrors: []string{
 				"failed to get kube-public/cluster-info configmap: configmap \"cluster-info\" not found",
 			},
-			wantAgentDeployment:       healthyAgentDeploymentWithHostNetwork,
-			wantDeploymentActionVerbs: []string{"list", "watch", "update"},
+			wantAgentDeployment:       healthyAgentDeploymentOnNode("node-1"),
+			wantDeploymentActionVerbs: []string{"list", "watch", "create"},
 			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
 				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
 				Status:         configv1alpha1.ErrorStrategyStatus,
@@ -619,7 +1584,7 @@ func TestAgentController(t *testing.T) {
 				LastUpdateTime: metav1.NewTime(now),
 			},
 			wantDistinctLogs: []string{
-				`kube-cert-agent-controller "level"=0 "msg"="updating existing deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-1","namespace":"kube-system"}`,
+				`kube-cert-agent-controller "level"=0 "msg"="creating new deployment" "deployment"={"name":"pinniped-concierge-kube-cert-agent","namespace":"concierge"} "templatePod"={"name":"kube-controller-manager-node-1","namespace":"kube-system"}`,
 			},
 		},
 		{
@@ -755,6 +1720,59 @@ func TestAgentController(t *testing.T) {
 				LastUpdateTime: metav1.NewTime(now),
 			},
 		},
+		{
+			name: "deployment exists, agent pod is stuck in ImagePullBackOff",
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPod,
+				healthyAgentDeployment,
+				imagePullBackOffAgentPod,
+				validClusterInfoConfigMap,
+			},
+			wantDistinctErrors: []string{
+				"could not find a healthy agent pod (1 candidate): agent pod concierge/pinniped-concierge-kube-cert-agent-xyz-1234 container \"sleeper\" is waiting: ImagePullBackOff: Back-off pulling image \"pinniped-server-image\"",
+			},
+			wantAgentDeployment:       healthyAgentDeployment,
+			wantDeploymentActionVerbs: []string{"list", "watch"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotFetchKeyStrategyReason,
+				Message:        "could not find a healthy agent pod (1 candidate): agent pod concierge/pinniped-concierge-kube-cert-agent-xyz-1234 container \"sleeper\" is waiting: ImagePullBackOff: Back-off pulling image \"pinniped-server-image\"",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+		},
+		{
+			name: "deployment exists, configmap is valid, agent pod container is in CrashLoopBackOff",
+			pinnipedObjects: []runtime.Object{
+				initialCredentialIssuer,
+			},
+			kubeObjects: []runtime.Object{
+				healthyKubeControllerManagerPod,
+				healthyAgentDeployment,
+				crashLoopBackOffAgentPod,
+				validClusterInfoConfigMap,
+			},
+			mocks: func(t *testing.T, executor *mocks.MockPodCommandExecutorMockRecorder, dynamicCert *mocks.MockDynamicCertPrivateMockRecorder, execCache *cache.Expiring) {
+				executor.Exec("concierge", "pinniped-concierge-kube-cert-agent-xyz-1234", "pinniped-concierge-kube-cert-agent", "print").
+					Return("", fmt.Errorf("some exec error")).
+					AnyTimes()
+			},
+			wantDistinctErrors: []string{
+				"could not exec into agent pod concierge/pinniped-concierge-kube-cert-agent-xyz-1234: some exec error: agent pod concierge/pinniped-concierge-kube-cert-agent-xyz-1234 container \"sleeper\" is waiting: CrashLoopBackOff (restarted 5 times)",
+			},
+			wantAgentDeployment:       healthyAgentDeployment,
+			wantDeploymentActionVerbs: []string{"list", "watch"},
+			wantStrategy: &configv1alpha1.CredentialIssuerStrategy{
+				Type:           configv1alpha1.KubeClusterSigningCertificateStrategyType,
+				Status:         configv1alpha1.ErrorStrategyStatus,
+				Reason:         configv1alpha1.CouldNotFetchKeyStrategyReason,
+				Message:        "could not exec into agent pod concierge/pinniped-concierge-kube-cert-agent-xyz-1234: some exec error: agent pod concierge/pinniped-concierge-kube-cert-agent-xyz-1234 container \"sleeper\" is waiting: CrashLoopBackOff (restarted 5 times)",
+				LastUpdateTime: metav1.NewTime(now),
+			},
+		},
 		{
 			name: "deployment exists, configmap is valid, exec into agent pod returns invalid JSON",
 			pinnipedObjects: []runtime.Object{
@@ -1054,7 +2072,20 @@ func TestAgentController(t *testing.T) {
 						// Concierge Deployment, so we do not want it to exist on the Kube cert agent pods.
 						"app": "anything",
 					},
-					DiscoveryURLOverride: tt.discoveryURLOverride,
+					DiscoveryURLOverride:     tt.discoveryURLOverride,
+					AdditionalTolerations:    tt.additionalTolerations,
+					AdditionalNodeSelector:   tt.additionalNodeSelector,
+					PriorityClassName:        tt.priorityClassName,
+					PodSecurityContext:       tt.podSecurityContext,
+					ContainerSecurityContext: tt.containerSecurityContext,
+					ReadinessProbe:           tt.readinessProbe,
+					ContainerCommand:         tt.containerCommand,
+					ImagePullPolicy:          tt.imagePullPolicy,
+					AdditionalPodLabels:      tt.additionalPodLabels,
+					AdditionalPodAnnotations: tt.additionalPodAnnotations,
+
+					ControllerManagerNamespace:        tt.controllerManagerNamespace,
+					PreferredControllerManagerPodName: tt.preferredControllerManagerPod,
 				},
 				&kubeclient.Client{Kubernetes: kubeClientset, PinnipedConcierge: conciergeClientset},
 				kubeInformers.Core().V1().Pods(),
@@ -1185,6 +2216,139 @@ func TestMergeLabelsAndAnnotations(t *testing.T) {
 	}
 }
 
+func TestDescribeAgentPodProblems(t *testing.T) {
+	t.Parallel()
+
+	podWithStatuses := func(name string, statuses ...corev1.ContainerStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "concierge", Name: name},
+			Status:     corev1.PodStatus{ContainerStatuses: statuses},
+		}
+	}
+	waiting := func(reason, message string, restarts int32) corev1.ContainerStatus {
+		return corev1.ContainerStatus{
+			Name:         "sleeper",
+			RestartCount: restarts,
+			State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason, Message: message}},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		pods     []*corev1.Pod
+		expected string
+	}{
+		{
+			name:     "no pods",
+			expected: "",
+		},
+		{
+			name:     "pod without container statuses",
+			pods:     []*corev1.Pod{podWithStatuses("agent-1")},
+			expected: "",
+		},
+		{
+			name:     "container is still being created",
+			pods:     []*corev1.Pod{podWithStatuses("agent-1", waiting("ContainerCreating", "", 0))},
+			expected: "",
+		},
+		{
+			name:     "container image cannot be pulled",
+			pods:     []*corev1.Pod{podWithStatuses("agent-1", waiting("ErrImagePull", "some pull error", 0))},
+			expected: `agent pod concierge/agent-1 container "sleeper" is waiting: ErrImagePull: some pull error`,
+		},
+		{
+			name: "container has restarted after terminating",
+			pods: []*corev1.Pod{podWithStatuses("agent-1", corev1.ContainerStatus{
+				Name:                 "sleeper",
+				RestartCount:         2,
+				State:                corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+			})},
+			expected: `agent pod concierge/agent-1 container "sleeper" last terminated: OOMKilled (exit code 137) (restarted 2 times)`,
+		},
+		{
+			name: "multiple pods with problems are sorted by name",
+			pods: []*corev1.Pod{
+				podWithStatuses("agent-2", waiting("CrashLoopBackOff", "", 3)),
+				podWithStatuses("agent-1", waiting("ImagePullBackOff", "", 0)),
+			},
+			expected: `agent pod concierge/agent-1 container "sleeper" is waiting: ImagePullBackOff; ` +
+				`agent pod concierge/agent-2 container "sleeper" is waiting: CrashLoopBackOff (restarted 3 times)`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expected, describeAgentPodProblems(tt.pods))
+		})
+	}
+}
+
+func TestGetContainerArgByName(t *testing.T) {
+	t.Parallel()
+
+	podWithContainers := func(containers ...corev1.Container) *corev1.Pod {
+		return &corev1.Pod{Spec: corev1.PodSpec{Containers: containers}}
+	}
+
+	tests := []struct {
+		name     string
+		pod      *corev1.Pod
+		expected string
+	}{
+		{
+			name:     "no containers",
+			pod:      podWithContainers(),
+			expected: "/fallback/path",
+		},
+		{
+			name:     "flag is absent",
+			pod:      podWithContainers(corev1.Container{Command: []string{"kube-controller-manager", "--some-flag=some-value"}}),
+			expected: "/fallback/path",
+		},
+		{
+			name:     "flag has an empty value",
+			pod:      podWithContainers(corev1.Container{Command: []string{"kube-controller-manager", "--cluster-signing-cert-file="}}),
+			expected: "/fallback/path",
+		},
+		{
+			name:     "flag and value in the command separated by an equals sign",
+			pod:      podWithContainers(corev1.Container{Command: []string{"kube-controller-manager", "--cluster-signing-cert-file=/custom/ca.crt"}}),
+			expected: "/custom/ca.crt",
+		},
+		{
+			name:     "flag and value in the command as separate arguments",
+			pod:      podWithContainers(corev1.Container{Command: []string{"kube-controller-manager", "--cluster-signing-cert-file", "/custom/ca.crt"}}),
+			expected: "/custom/ca.crt",
+		},
+		{
+			name: "flag in the container args",
+			pod: podWithContainers(corev1.Container{
+				Command: []string{"kube-controller-manager"},
+				Args:    []string{"--unknown-flag", "--cluster-signing-cert-file=/custom/ca.crt"},
+			}),
+			expected: "/custom/ca.crt",
+		},
+		{
+			name: "flag in a later container",
+			pod: podWithContainers(
+				corev1.Container{Command: []string{"some-sidecar"}},
+				corev1.Container{Command: []string{"kube-controller-manager", "--cluster-signing-cert-file=/custom/ca.crt"}},
+			),
+			expected: "/custom/ca.crt",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expected, getContainerArgByName(tt.pod, "cluster-signing-cert-file", "/fallback/path"))
+		})
+	}
+}
+
 func deduplicate(strings []string) []string {
 	if strings == nil {
 		return nil
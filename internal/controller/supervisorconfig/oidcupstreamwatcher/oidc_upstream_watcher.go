@@ -6,25 +6,38 @@ package oidcupstreamwatcher
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
 	"crypto/x509"
 	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/go-logr/logr"
 	"golang.org/x/oauth2"
+	"gopkg.in/square/go-jose.v2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/apimachinery/pkg/util/wait"
 	corev1informers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/utils/clock"
 
 	"go.pinniped.dev/generated/latest/apis/supervisor/idp/v1alpha1"
 	pinnipedclientset "go.pinniped.dev/generated/latest/client/supervisor/clientset/versioned"
@@ -49,18 +62,100 @@ const (
 	clientIDDataKey     = "clientID"
 	clientSecretDataKey = "clientSecret"
 
+	// The alternative key of the client credentials Secret which holds both credentials as a JSON object with
+	// "clientID" and "clientSecret" fields, as injected by some secret management tools.
+	combinedCredentialsDataKey = "oidc.json"
+
+	// The optional annotations of the client credentials Secret with which teams who rotate their client secrets can
+	// record when the client secret expires (an RFC 3339 timestamp), and how long before that the status of the
+	// OIDCIdentityProvider should start warning about it (a Go duration, defaulting to a week).
+	clientSecretExpiresAtAnnotation     = "secrets.pinniped.dev/client-secret-expires-at"
+	clientSecretExpiryWarningAnnotation = "secrets.pinniped.dev/client-secret-expiry-warning-window"
+	defaultClientSecretExpiryWarning    = 7 * 24 * time.Hour
+
 	// Constants related to the OIDC provider discovery cache. These do not affect the cache of JWKS.
 	oidcValidatorCacheTTL = 15 * time.Minute
 
+	// The maximum number of entries in each of the OIDC provider discovery cache and the JWKS cache. When a cache is
+	// full, its least recently used entry is evicted to make room for a new one.
+	oidcValidatorCacheMaxSize = 100
+
+	// Constants related to the HTTP client used for OIDC provider discovery and JWKS requests.
+	defaultRequestTimeout = time.Minute
+	maxRequestTimeout     = 10 * time.Minute
+
+	// Constants related to backing off from repeatedly failing OIDC provider discovery.
+	discoveryBackoffInitialDelay = time.Second
+	discoveryBackoffMaxDelay     = time.Minute
+	discoveryBackoffJitter       = 0.5
+
+	// The default number of consecutive failed OIDC discovery attempts after which the issuer is reported as
+	// persistently unreachable rather than transiently unreachable.
+	defaultDiscoveryFailureThreshold = 10
+
 	// Constants related to conditions.
 	typeClientCredentialsValid             = "ClientCredentialsValid"
 	typeAdditionalAuthorizeParametersValid = "AdditionalAuthorizeParametersValid"
+	typeAdditionalTokenParametersValid     = "AdditionalTokenParametersValid"
 	typeOIDCDiscoverySucceeded             = "OIDCDiscoverySucceeded"
+	typeJWKSFetchSucceeded                 = "JWKSFetchSucceeded"
+	typeScopesSupported                    = "ScopesSupported"
+	typeRefreshTokenSupported              = "RefreshTokenSupported"
+	typeClaimsValid                        = "ClaimsValid"
+	typeNamespaceAllowed                   = "NamespaceAllowed"
+	typeClientSecretNotExpiring            = "ClientSecretNotExpiring"
+	typePasswordGrantSupported             = "PasswordGrantSupported"
+	typeEnabled                            = "Enabled"
 
 	reasonUnreachable             = "Unreachable"
+	reasonPersistentlyUnreachable = "PersistentlyUnreachable"
 	reasonInvalidResponse         = "InvalidResponse"
 	reasonDisallowedParameterName = "DisallowedParameterName"
+	reasonDisallowedParamValue    = "DisallowedParameterValue"
+	reasonInvalidParameterValue   = "InvalidParameterValue"
+	reasonInvalidAuthMethod       = "InvalidAuthMethod"
+	reasonOIDCDiscoveryFailed     = "OIDCDiscoveryFailed"
+	reasonInvalidProxyConfig      = "InvalidProxyConfig"
+	reasonUnadvertisedScopes      = "UnadvertisedScopes"
+	reasonInvalidScopes           = "InvalidScopes"
+	reasonInvalidEndpointOverride = "InvalidEndpointOverride"
+	reasonInvalidRequestTimeout   = "InvalidRequestTimeout"
+	reasonInvalidClaims           = "InvalidClaims"
+	reasonRefreshNotAdvertised    = "RefreshNotAdvertised"
+	reasonNamespaceNotAllowed     = "NamespaceNotAllowed"
+	reasonDisallowedIssuer        = "DisallowedIssuer"
+	reasonInvalidExpiryAnnotation = "InvalidExpiryAnnotation"
+	reasonClientSecretExpiring    = "ClientSecretExpiring"
+	reasonClientSecretExpired     = "ClientSecretExpired"
+	reasonPasswordNotAdvertised   = "PasswordGrantNotAdvertised"
+	reasonDisabled                = "Disabled"
 	allParamNamesAllowedMsg       = "additionalAuthorizeParameters parameter names are allowed"
+	allTokenParamNamesAllowedMsg  = "additionalTokenParameters parameter names are allowed"
+
+	// The maximum length, in characters, of the value of an additionalAuthorizeParameter.
+	maxAdditionalAuthorizeParameterValueLength = 1024
+
+	// The authorize request parameter used by Google's OIDC provider to request a hosted domain.
+	hostedDomainParamName = "hd"
+
+	// The authorize request parameter defined by the OIDC spec to control re-authentication and consent prompts.
+	promptParamName = "prompt"
+
+	// The authorize request parameters defined by the OIDC spec to request authentication context classes and the
+	// languages of the login UI, and the maximum length of their values, which are space-separated lists.
+	acrValuesParamName          = "acr_values"
+	uiLocalesParamName          = "ui_locales"
+	maxListParameterValueLength = 256
+
+	// The standard claim which holds the email address of the user, whose email_verified claim may be ignored.
+	emailClaimName = "email"
+
+	// The grant type which an issuer advertises in its discovery document when it supports refresh tokens.
+	refreshTokenGrantType = "refresh_token"
+
+	// The grant type which an issuer advertises in its discovery document when it supports the resource owner
+	// password credentials grant.
+	passwordGrantType = "password"
 
 	// Errors that are generated by our reconcile process.
 	errOIDCFailureStatus = constable.Error("OIDCIdentityProvider has a failing condition")
@@ -81,11 +176,44 @@ var (
 		"code_challenge":        true,
 		"code_challenge_method": true,
 		"redirect_uri":          true,
+	}
+
+	disallowedAdditionalTokenParameters = map[string]bool{ //nolint: gochecknoglobals
+		// Reject these AdditionalTokenParameters to avoid allowing the user's config to overwrite the parameters that
+		// are always used by Pinniped in token requests, or to turn the authcode exchange into another grant type.
+		// This map should be treated as read-only since it is a global variable.
+		"grant_type":    true,
+		"code":          true,
+		"redirect_uri":  true,
+		"code_verifier": true,
+		"client_id":     true,
+		"client_secret": true,
+		"refresh_token": true,
+		"username":      true,
+		"password":      true,
+		"scope":         true,
+	}
 
-		// Reject "hd" for now because it is not safe to use with Google's OIDC provider until Pinniped also
-		// performs the corresponding validation on the ID token.
-		"hd": true,
+	// The values of the "prompt" parameter which are defined by
+	// https://openid.net/specs/openid-connect-core-1_0.html#AuthRequest. This map should be treated as read-only
+	// since it is a global variable.
+	allowedPromptParameterValues = sets.NewString("none", "login", "consent", "select_account") //nolint: gochecknoglobals
+
+	// The ID token signing algorithms which are supported by the oidc package. This set should be treated as read-only
+	// since it is a global variable.
+	supportedSigningAlgorithms = sets.NewString( //nolint: gochecknoglobals
+		oidc.RS256, oidc.RS384, oidc.RS512, oidc.ES256, oidc.ES384, oidc.ES512, oidc.PS256, oidc.PS384, oidc.PS512,
+	)
+
+	// The descriptions of the items of the standard parameters whose values are space-separated lists, for status
+	// messages. This map should be treated as read-only since it is a global variable.
+	listParameterItemDescriptions = map[string]string{ //nolint: gochecknoglobals
+		acrValuesParamName: "authentication context class references",
+		uiLocalesParamName: "BCP47 language tags",
 	}
+
+	// A loose match for a BCP47 language tag, e.g. "en", "fr-CA", or "zh-Hant-TW".
+	languageTagPattern = regexp.MustCompile(`^[A-Za-z]{1,8}(-[A-Za-z0-9]{1,8})*$`) //nolint: gochecknoglobals
 )
 
 // UpstreamOIDCIdentityProviderICache is a thread safe cache that holds a list of validated upstream OIDC IDP configurations.
@@ -94,34 +222,205 @@ type UpstreamOIDCIdentityProviderICache interface {
 }
 
 // lruValidatorCache caches the *oidc.Provider associated with a particular issuer/TLS configuration.
-type lruValidatorCache struct{ cache *cache.Expiring }
+type lruValidatorCache struct{ cache *cache.LRUExpireCache }
+
+// newLRUValidatorCache returns an lruValidatorCache which holds at most maxSize entries.
+func newLRUValidatorCache(maxSize int, clock clock.Clock) *lruValidatorCache {
+	return &lruValidatorCache{cache: cache.NewLRUExpireCacheWithClock(maxSize, clock)}
+}
 
 type lruValidatorCacheEntry struct {
-	provider *oidc.Provider
-	client   *http.Client
+	provider          *oidc.Provider
+	client            *http.Client
+	clientCertVersion string
+
+	// The provider and client for a JWKS which is served with a separate CA bundle, along with the JWKS URL and the
+	// hash of the CA bundle which they were built for. These are only set when the JWKS has a separate CA bundle.
+	jwksProvider     *separateJWKSProvider
+	jwksClient       *http.Client
+	jwksURL          string
+	jwksCABundleHash [sha256.Size]byte
+}
+
+// getProvider returns the cached provider and client, unless the client was built with a different version of the
+// client certificate Secret, in which case the client needs to be built again with the current client certificate.
+func (c *lruValidatorCache) getProvider(spec *v1alpha1.OIDCIdentityProviderSpec, clientCertVersion string) (*oidc.Provider, *http.Client) {
+	if result, ok := c.cache.Get(cacheKey(spec)); ok {
+		entry := result.(*lruValidatorCacheEntry)
+		if entry.clientCertVersion == clientCertVersion {
+			return entry.provider, entry.client
+		}
+	}
+	return nil, nil
+}
+
+func (c *lruValidatorCache) putProvider(spec *v1alpha1.OIDCIdentityProviderSpec, provider *oidc.Provider, client *http.Client, clientCertVersion string) {
+	c.cache.Add(cacheKey(spec), &lruValidatorCacheEntry{provider: provider, client: client, clientCertVersion: clientCertVersion}, oidcValidatorCacheTTL)
 }
 
-func (c *lruValidatorCache) getProvider(spec *v1alpha1.OIDCIdentityProviderSpec) (*oidc.Provider, *http.Client) {
-	if result, ok := c.cache.Get(c.cacheKey(spec)); ok {
+// getJWKSProvider returns the cached provider and client for a JWKS which is served with a separate CA bundle, unless
+// they were built for a different JWKS URL, JWKS CA bundle, or version of the client certificate Secret.
+func (c *lruValidatorCache) getJWKSProvider(spec *v1alpha1.OIDCIdentityProviderSpec, clientCertVersion, jwksURL string) (*separateJWKSProvider, *http.Client) {
+	if result, ok := c.cache.Get(cacheKey(spec)); ok {
 		entry := result.(*lruValidatorCacheEntry)
-		return entry.provider, entry.client
+		if entry.jwksProvider != nil &&
+			entry.clientCertVersion == clientCertVersion &&
+			entry.jwksURL == jwksURL &&
+			entry.jwksCABundleHash == jwksCABundleHash(spec) {
+			return entry.jwksProvider, entry.jwksClient
+		}
 	}
 	return nil, nil
 }
 
-func (c *lruValidatorCache) putProvider(spec *v1alpha1.OIDCIdentityProviderSpec, provider *oidc.Provider, client *http.Client) {
-	c.cache.Set(c.cacheKey(spec), &lruValidatorCacheEntry{provider: provider, client: client}, oidcValidatorCacheTTL)
+// putJWKSProvider adds the provider and client for a JWKS which is served with a separate CA bundle to the cached entry
+// of the discovered provider which it wraps. It does nothing when that entry is no longer cached.
+func (c *lruValidatorCache) putJWKSProvider(spec *v1alpha1.OIDCIdentityProviderSpec, clientCertVersion, jwksURL string, provider *separateJWKSProvider, client *http.Client) {
+	result, ok := c.cache.Get(cacheKey(spec))
+	if !ok {
+		return
+	}
+	entry := *result.(*lruValidatorCacheEntry)
+	if entry.clientCertVersion != clientCertVersion || provider.upstreamProvider != upstreamProvider(entry.provider) {
+		return
+	}
+	entry.jwksProvider = provider
+	entry.jwksClient = client
+	entry.jwksURL = jwksURL
+	entry.jwksCABundleHash = jwksCABundleHash(spec)
+	c.cache.Add(cacheKey(spec), &entry, oidcValidatorCacheTTL)
 }
 
-func (c *lruValidatorCache) cacheKey(spec *v1alpha1.OIDCIdentityProviderSpec) interface{} {
-	var key struct{ issuer, caBundle string }
+func jwksCABundleHash(spec *v1alpha1.OIDCIdentityProviderSpec) [sha256.Size]byte {
+	if spec.TLS == nil {
+		return sha256.Sum256(nil)
+	}
+	return sha256.Sum256([]byte(spec.TLS.JWKSCertificateAuthorityData))
+}
+
+func cacheKey(spec *v1alpha1.OIDCIdentityProviderSpec) interface{} {
+	var key struct {
+		issuer, caBundle, clientCertSecretName, proxyURL string
+		requestTimeout                                   time.Duration
+	}
 	key.issuer = spec.Issuer
+	key.proxyURL = spec.ProxyURL
+	if spec.RequestTimeout != nil {
+		key.requestTimeout = spec.RequestTimeout.Duration
+	}
 	if spec.TLS != nil {
 		key.caBundle = spec.TLS.CertificateAuthorityData
+		key.clientCertSecretName = spec.TLS.ClientCertificateSecretName
+	}
+	return key
+}
+
+// jwksCache remembers which JWKS endpoints were recently found to contain usable signing keys, so that they do not
+// need to be fetched again on every sync. Only successful fetches are cached.
+type jwksCache struct{ cache *cache.LRUExpireCache }
+
+// newJWKSCache returns a jwksCache which holds at most maxSize entries.
+func newJWKSCache(maxSize int, clock clock.Clock) *jwksCache {
+	return &jwksCache{cache: cache.NewLRUExpireCacheWithClock(maxSize, clock)}
+}
+
+func jwksCacheKey(spec *v1alpha1.OIDCIdentityProviderSpec, jwksURL string) interface{} {
+	var key struct {
+		issuerKey         interface{}
+		jwksURL, caBundle string
+	}
+	key.issuerKey = cacheKey(spec)
+	key.jwksURL = jwksURL
+	if spec.TLS != nil {
+		key.caBundle = spec.TLS.JWKSCertificateAuthorityData
 	}
 	return key
 }
 
+func (c *jwksCache) hasUsableKeys(spec *v1alpha1.OIDCIdentityProviderSpec, jwksURL string) bool {
+	_, ok := c.cache.Get(jwksCacheKey(spec, jwksURL))
+	return ok
+}
+
+func (c *jwksCache) putUsableKeys(spec *v1alpha1.OIDCIdentityProviderSpec, jwksURL string) {
+	c.cache.Add(jwksCacheKey(spec, jwksURL), struct{}{}, oidcValidatorCacheTTL)
+}
+
+// discoveryBackoffCache remembers failed OIDC discovery attempts for a particular issuer/TLS configuration, so that
+// discovery is retried with an increasing delay instead of on every sync. It uses the same keys as lruValidatorCache.
+type discoveryBackoffCache struct {
+	cache *cache.Expiring
+	clock clock.Clock
+}
+
+type discoveryBackoffCacheEntry struct {
+	backoff   wait.Backoff
+	nextRetry time.Time
+	condition *v1alpha1.Condition
+	failures  int
+}
+
+func newDiscoveryBackoffCache(clock clock.Clock) *discoveryBackoffCache {
+	return &discoveryBackoffCache{cache: cache.NewExpiringWithClock(clock), clock: clock}
+}
+
+// getFailure returns the condition of the most recent failed discovery attempt when it is not yet time to retry.
+func (c *discoveryBackoffCache) getFailure(spec *v1alpha1.OIDCIdentityProviderSpec) *v1alpha1.Condition {
+	entry := c.get(spec)
+	if entry == nil || !c.clock.Now().Before(entry.nextRetry) {
+		return nil
+	}
+	return entry.condition.DeepCopy()
+}
+
+// putFailure records a failed discovery attempt and computes the next time at which discovery may be retried.
+func (c *discoveryBackoffCache) putFailure(spec *v1alpha1.OIDCIdentityProviderSpec, condition *v1alpha1.Condition) {
+	entry := c.get(spec)
+	if entry == nil {
+		entry = &discoveryBackoffCacheEntry{backoff: wait.Backoff{
+			Duration: discoveryBackoffInitialDelay,
+			Factor:   2,
+			Jitter:   discoveryBackoffJitter,
+			Steps:    math.MaxInt32,
+			Cap:      discoveryBackoffMaxDelay,
+		}}
+	}
+	entry.nextRetry = c.clock.Now().Add(entry.backoff.Step())
+	entry.condition = condition
+	entry.failures++
+	c.cache.Set(cacheKey(spec), entry, oidcValidatorCacheTTL)
+}
+
+// retryAfter returns how long to wait before discovery may be retried, or false when discovery has not failed.
+func (c *discoveryBackoffCache) retryAfter(spec *v1alpha1.OIDCIdentityProviderSpec) (time.Duration, bool) {
+	entry := c.get(spec)
+	if entry == nil {
+		return 0, false
+	}
+	return entry.nextRetry.Sub(c.clock.Now()), true
+}
+
+// consecutiveFailures returns how many discovery attempts have failed since the last success.
+func (c *discoveryBackoffCache) consecutiveFailures(spec *v1alpha1.OIDCIdentityProviderSpec) int {
+	entry := c.get(spec)
+	if entry == nil {
+		return 0
+	}
+	return entry.failures
+}
+
+// reset forgets all failed discovery attempts, so that the next failure starts backing off from the initial delay.
+func (c *discoveryBackoffCache) reset(spec *v1alpha1.OIDCIdentityProviderSpec) {
+	c.cache.Delete(cacheKey(spec))
+}
+
+func (c *discoveryBackoffCache) get(spec *v1alpha1.OIDCIdentityProviderSpec) *discoveryBackoffCacheEntry {
+	if result, ok := c.cache.Get(cacheKey(spec)); ok {
+		return result.(*discoveryBackoffCacheEntry)
+	}
+	return nil
+}
+
 type oidcWatcherController struct {
 	cache                        UpstreamOIDCIdentityProviderICache
 	log                          logr.Logger
@@ -129,27 +428,53 @@ type oidcWatcherController struct {
 	oidcIdentityProviderInformer idpinformers.OIDCIdentityProviderInformer
 	secretInformer               corev1informers.SecretInformer
 	validatorCache               interface {
-		getProvider(*v1alpha1.OIDCIdentityProviderSpec) (*oidc.Provider, *http.Client)
-		putProvider(*v1alpha1.OIDCIdentityProviderSpec, *oidc.Provider, *http.Client)
+		getProvider(*v1alpha1.OIDCIdentityProviderSpec, string) (*oidc.Provider, *http.Client)
+		putProvider(*v1alpha1.OIDCIdentityProviderSpec, *oidc.Provider, *http.Client, string)
+		getJWKSProvider(*v1alpha1.OIDCIdentityProviderSpec, string, string) (*separateJWKSProvider, *http.Client)
+		putJWKSProvider(*v1alpha1.OIDCIdentityProviderSpec, string, string, *separateJWKSProvider, *http.Client)
 	}
+	discoveryBackoff          *discoveryBackoffCache
+	discoveryFailureThreshold int
+	allowedIssuerPattern      *regexp.Regexp
+	jwksCache                 *jwksCache
+	allowedNamespaces         sets.String
+	clock                     clock.Clock
 }
 
 // New instantiates a new controllerlib.Controller which will populate the provided UpstreamOIDCIdentityProviderICache.
+// When allowedNamespaces is not empty, OIDCIdentityProviders in any other namespace are never loaded into the cache,
+// even when the informer watches them, and are given a failing status instead. After discoveryFailureThreshold
+// consecutive failed discovery attempts, an issuer is reported as persistently unreachable. When it is not positive,
+// a default threshold is used. When allowedIssuerPattern is not nil, OIDCIdentityProviders with an issuer which it does
+// not match are never loaded into the cache, and no requests are made to their issuers.
 func New(
 	idpCache UpstreamOIDCIdentityProviderICache,
 	client pinnipedclientset.Interface,
 	oidcIdentityProviderInformer idpinformers.OIDCIdentityProviderInformer,
 	secretInformer corev1informers.SecretInformer,
+	allowedNamespaces []string,
+	discoveryFailureThreshold int,
+	allowedIssuerPattern *regexp.Regexp,
+	clock clock.Clock,
 	log logr.Logger,
 	withInformer pinnipedcontroller.WithInformerOptionFunc,
 ) controllerlib.Controller {
+	if discoveryFailureThreshold <= 0 {
+		discoveryFailureThreshold = defaultDiscoveryFailureThreshold
+	}
 	c := oidcWatcherController{
 		cache:                        idpCache,
 		log:                          log.WithName(oidcControllerName),
 		client:                       client,
 		oidcIdentityProviderInformer: oidcIdentityProviderInformer,
 		secretInformer:               secretInformer,
-		validatorCache:               &lruValidatorCache{cache: cache.NewExpiring()},
+		validatorCache:               newLRUValidatorCache(oidcValidatorCacheMaxSize, clock),
+		discoveryBackoff:             newDiscoveryBackoffCache(clock),
+		discoveryFailureThreshold:    discoveryFailureThreshold,
+		allowedIssuerPattern:         allowedIssuerPattern,
+		jwksCache:                    newJWKSCache(oidcValidatorCacheMaxSize, clock),
+		allowedNamespaces:            sets.NewString(allowedNamespaces...),
+		clock:                        clock,
 	}
 	return controllerlib.New(
 		controllerlib.Config{Name: oidcControllerName, Syncer: &c},
@@ -160,7 +485,10 @@ func New(
 		),
 		withInformer(
 			secretInformer,
-			pinnipedcontroller.MatchAnySecretOfTypeFilter(oidcClientSecretType, pinnipedcontroller.SingletonQueue()),
+			pinnipedcontroller.MatchAnySecretOfTypesFilter(
+				[]corev1.SecretType{oidcClientSecretType, corev1.SecretTypeTLS},
+				pinnipedcontroller.SingletonQueue(),
+			),
 			controllerlib.InformerOption{},
 		),
 	)
@@ -174,22 +502,78 @@ func (c *oidcWatcherController) Sync(ctx controllerlib.Context) error {
 	}
 
 	requeue := false
+	var requeueAfter time.Duration
 	validatedUpstreams := make([]provider.UpstreamOIDCIdentityProviderI, 0, len(actualUpstreams))
 	for _, upstream := range actualUpstreams {
+		if !c.namespaceAllowed(upstream.Namespace) {
+			c.rejectUpstreamOutsideAllowedNamespaces(ctx.Context, upstream)
+			continue
+		}
+		if upstream.Spec.Disabled {
+			c.skipDisabledUpstream(ctx.Context, upstream)
+			continue
+		}
 		valid := c.validateUpstream(ctx, upstream)
-		if valid == nil {
-			requeue = true
-		} else {
+		if valid != nil {
 			validatedUpstreams = append(validatedUpstreams, provider.UpstreamOIDCIdentityProviderI(valid))
+			continue
+		}
+		// When discovery is failing, wait until it may be retried instead of retrying immediately.
+		delay, backingOff := c.discoveryBackoff.retryAfter(&upstream.Spec)
+		if !backingOff || delay <= 0 {
+			requeue = true
+		} else if requeueAfter == 0 || delay < requeueAfter {
+			requeueAfter = delay
 		}
 	}
 	c.cache.SetOIDCIdentityProviders(validatedUpstreams)
 	if requeue {
 		return controllerlib.ErrSyntheticRequeue
 	}
+	if requeueAfter > 0 {
+		ctx.Queue.AddAfter(ctx.Key, requeueAfter)
+	}
 	return nil
 }
 
+// namespaceAllowed returns true when no namespace allowlist is configured or when the namespace is on it.
+func (c *oidcWatcherController) namespaceAllowed(namespace string) bool {
+	return c.allowedNamespaces.Len() == 0 || c.allowedNamespaces.Has(namespace)
+}
+
+// rejectUpstreamOutsideAllowedNamespaces updates the status of an OIDCIdentityProvider which is outside the configured
+// tenancy scope without validating anything else about it, so that no requests are made to its issuer.
+func (c *oidcWatcherController) rejectUpstreamOutsideAllowedNamespaces(ctx context.Context, upstream *v1alpha1.OIDCIdentityProvider) {
+	condition := &v1alpha1.Condition{
+		Type:   typeNamespaceAllowed,
+		Status: v1alpha1.ConditionFalse,
+		Reason: reasonNamespaceNotAllowed,
+		Message: fmt.Sprintf("namespace %q is outside the configured tenancy scope (allowed namespaces: %s)",
+			upstream.Namespace, strings.Join(c.allowedNamespaces.List(), ", ")),
+	}
+	c.updateStatus(ctx, upstream, []*v1alpha1.Condition{condition}, "", nil)
+	c.log.WithValues(
+		"namespace", upstream.Namespace,
+		"name", upstream.Name,
+		"type", condition.Type,
+		"reason", condition.Reason,
+		"message", condition.Message,
+	).Error(errOIDCFailureStatus, "found failing condition")
+}
+
+// skipDisabledUpstream updates the status of an OIDCIdentityProvider which is disabled by its spec without validating
+// anything else about it, so that no requests are made to its issuer.
+func (c *oidcWatcherController) skipDisabledUpstream(ctx context.Context, upstream *v1alpha1.OIDCIdentityProvider) {
+	condition := &v1alpha1.Condition{
+		Type:    typeEnabled,
+		Status:  v1alpha1.ConditionFalse,
+		Reason:  reasonDisabled,
+		Message: "disabled by spec.disabled, so it cannot be used to log in",
+	}
+	c.updateStatus(ctx, upstream, []*v1alpha1.Condition{condition}, "", upstream.Status.CertificateAuthority)
+	c.log.WithValues("namespace", upstream.Namespace, "name", upstream.Name).Info("skipping disabled OIDCIdentityProvider")
+}
+
 // validateUpstream validates the provided v1alpha1.OIDCIdentityProvider and returns the validated configuration as a
 // provider.UpstreamOIDCIdentityProvider. As a side effect, it also updates the status of the v1alpha1.OIDCIdentityProvider.
 func (c *oidcWatcherController) validateUpstream(ctx controllerlib.Context, upstream *v1alpha1.OIDCIdentityProvider) *upstreamoidc.ProviderConfig {
@@ -197,31 +581,76 @@ func (c *oidcWatcherController) validateUpstream(ctx controllerlib.Context, upst
 
 	additionalAuthcodeAuthorizeParameters := map[string]string{}
 	var rejectedAuthcodeAuthorizeParameters []string
+	var invalidPromptValue *string
+	var invalidListParameter *v1alpha1.Parameter
+	var disallowedValueAuthcodeAuthorizeParameters []string
 	for _, p := range authorizationConfig.AdditionalAuthorizeParameters {
-		if disallowedAdditionalAuthorizeParameters[p.Name] {
+		p := p
+		switch {
+		case disallowedAdditionalAuthorizeParameters[p.Name]:
+			rejectedAuthcodeAuthorizeParameters = append(rejectedAuthcodeAuthorizeParameters, p.Name)
+		case p.Name == hostedDomainParamName && p.Value != authorizationConfig.HostedDomain:
+			// The "hd" param is only safe to send when the same value is also validated in the ID token.
 			rejectedAuthcodeAuthorizeParameters = append(rejectedAuthcodeAuthorizeParameters, p.Name)
-		} else {
+		case p.Name == promptParamName && !validPromptValue(p.Value):
+			invalidPromptValue = &p.Value
+		case listParameterItemDescriptions[p.Name] != "" && !validListParameterValue(p.Name, p.Value):
+			invalidListParameter = &p
+		case !allowedAdditionalAuthorizeParameterValue(p.Value):
+			disallowedValueAuthcodeAuthorizeParameters = append(disallowedValueAuthcodeAuthorizeParameters, p.Name)
+		default:
 			additionalAuthcodeAuthorizeParameters[p.Name] = p.Value
 		}
 	}
+	if authorizationConfig.HostedDomain != "" {
+		additionalAuthcodeAuthorizeParameters[hostedDomainParamName] = authorizationConfig.HostedDomain
+	}
+
+	var additionalTokenParameters map[string]string
+	var rejectedTokenParameters []string
+	var disallowedValueTokenParameters []string
+	for _, p := range authorizationConfig.AdditionalTokenParameters {
+		switch {
+		case disallowedAdditionalTokenParameters[p.Name]:
+			rejectedTokenParameters = append(rejectedTokenParameters, p.Name)
+		case !allowedAdditionalAuthorizeParameterValue(p.Value):
+			disallowedValueTokenParameters = append(disallowedValueTokenParameters, p.Name)
+		default:
+			if additionalTokenParameters == nil {
+				additionalTokenParameters = map[string]string{}
+			}
+			additionalTokenParameters[p.Name] = p.Value
+		}
+	}
 
 	result := upstreamoidc.ProviderConfig{
 		Name: upstream.Name,
 		Config: &oauth2.Config{
-			Scopes: computeScopes(authorizationConfig.AdditionalScopes),
+			Scopes: computeScopes(authorizationConfig.AdditionalScopes, authorizationConfig.ReplaceScopes),
 		},
 		UsernameClaim:            upstream.Spec.Claims.Username,
 		GroupsClaim:              upstream.Spec.Claims.Groups,
+		GroupsDelimiter:          upstream.Spec.Claims.GroupsDelimiter,
+		IgnoreEmailVerified:      upstream.Spec.Claims.IgnoreEmailVerified,
 		AllowPasswordGrant:       authorizationConfig.AllowPasswordGrant,
 		AdditionalAuthcodeParams: additionalAuthcodeAuthorizeParameters,
+		AdditionalTokenParams:    additionalTokenParameters,
+		HostedDomain:             authorizationConfig.HostedDomain,
 		ResourceUID:              upstream.UID,
 	}
 
+	clientCredentialsCondition, clientSecretExpiryCondition := c.validateSecret(upstream, &result)
 	conditions := []*v1alpha1.Condition{
-		c.validateSecret(upstream, &result),
+		clientCredentialsCondition,
 		c.validateIssuer(ctx.Context, upstream, &result),
 	}
-	if len(rejectedAuthcodeAuthorizeParameters) > 0 {
+	conditions = append(conditions,
+		c.validateJWKS(ctx.Context, upstream, &result),
+		validateScopes(upstream, &result),
+		validateRefreshTokenSupport(&result),
+	)
+	switch {
+	case len(rejectedAuthcodeAuthorizeParameters) > 0:
 		conditions = append(conditions, &v1alpha1.Condition{
 			Type:   typeAdditionalAuthorizeParametersValid,
 			Status: v1alpha1.ConditionFalse,
@@ -229,7 +658,35 @@ func (c *oidcWatcherController) validateUpstream(ctx controllerlib.Context, upst
 			Message: fmt.Sprintf("the following additionalAuthorizeParameters are not allowed: %s",
 				strings.Join(rejectedAuthcodeAuthorizeParameters, ",")),
 		})
-	} else {
+	case invalidPromptValue != nil:
+		conditions = append(conditions, &v1alpha1.Condition{
+			Type:   typeAdditionalAuthorizeParametersValid,
+			Status: v1alpha1.ConditionFalse,
+			Reason: reasonInvalidParameterValue,
+			Message: fmt.Sprintf(`additionalAuthorizeParameters parameter "prompt" has invalid value %q `+
+				`(expected a space-separated list of %q, where "none" may not be combined with other values)`,
+				*invalidPromptValue, allowedPromptParameterValues.List()),
+		})
+	case invalidListParameter != nil:
+		conditions = append(conditions, &v1alpha1.Condition{
+			Type:   typeAdditionalAuthorizeParametersValid,
+			Status: v1alpha1.ConditionFalse,
+			Reason: reasonInvalidParameterValue,
+			Message: fmt.Sprintf(`additionalAuthorizeParameters parameter %q has invalid value %q `+
+				`(expected a space-separated list of %s, at most %d characters long)`,
+				invalidListParameter.Name, invalidListParameter.Value,
+				listParameterItemDescriptions[invalidListParameter.Name], maxListParameterValueLength),
+		})
+	case len(disallowedValueAuthcodeAuthorizeParameters) > 0:
+		conditions = append(conditions, &v1alpha1.Condition{
+			Type:   typeAdditionalAuthorizeParametersValid,
+			Status: v1alpha1.ConditionFalse,
+			Reason: reasonDisallowedParamValue,
+			Message: fmt.Sprintf("the following additionalAuthorizeParameters have values which are not allowed: %s "+
+				"(expected valid UTF-8 without control characters and at most %d characters long)",
+				strings.Join(disallowedValueAuthcodeAuthorizeParameters, ","), maxAdditionalAuthorizeParameterValueLength),
+		})
+	default:
 		conditions = append(conditions, &v1alpha1.Condition{
 			Type:    typeAdditionalAuthorizeParametersValid,
 			Status:  v1alpha1.ConditionTrue,
@@ -238,13 +695,64 @@ func (c *oidcWatcherController) validateUpstream(ctx controllerlib.Context, upst
 		})
 	}
 
-	c.updateStatus(ctx.Context, upstream, conditions)
+	// Only report this condition when the setting is used, like the PasswordGrantSupported condition.
+	switch {
+	case len(rejectedTokenParameters) > 0:
+		conditions = append(conditions, &v1alpha1.Condition{
+			Type:   typeAdditionalTokenParametersValid,
+			Status: v1alpha1.ConditionFalse,
+			Reason: reasonDisallowedParameterName,
+			Message: fmt.Sprintf("the following additionalTokenParameters are not allowed: %s",
+				strings.Join(rejectedTokenParameters, ",")),
+		})
+	case len(disallowedValueTokenParameters) > 0:
+		conditions = append(conditions, &v1alpha1.Condition{
+			Type:   typeAdditionalTokenParametersValid,
+			Status: v1alpha1.ConditionFalse,
+			Reason: reasonDisallowedParamValue,
+			Message: fmt.Sprintf("the following additionalTokenParameters have values which are not allowed: %s "+
+				"(expected valid UTF-8 without control characters and at most %d characters long)",
+				strings.Join(disallowedValueTokenParameters, ","), maxAdditionalAuthorizeParameterValueLength),
+		})
+	case len(authorizationConfig.AdditionalTokenParameters) > 0:
+		conditions = append(conditions, &v1alpha1.Condition{
+			Type:    typeAdditionalTokenParametersValid,
+			Status:  v1alpha1.ConditionTrue,
+			Reason:  upstreamwatchers.ReasonSuccess,
+			Message: allTokenParamNamesAllowedMsg,
+		})
+	}
+
+	conditions = append(conditions, validateClaims(upstream))
+	if passwordGrantCondition := validatePasswordGrantSupport(upstream, &result); passwordGrantCondition != nil {
+		conditions = append(conditions, passwordGrantCondition)
+	}
+	if enabledCondition := validateEnabled(upstream); enabledCondition != nil {
+		conditions = append(conditions, enabledCondition)
+	}
+	if clientSecretExpiryCondition != nil {
+		conditions = append(conditions, clientSecretExpiryCondition)
+	}
+	if c.allowedNamespaces.Len() > 0 {
+		conditions = append(conditions, &v1alpha1.Condition{
+			Type:    typeNamespaceAllowed,
+			Status:  v1alpha1.ConditionTrue,
+			Reason:  upstreamwatchers.ReasonSuccess,
+			Message: "namespace is within the configured tenancy scope",
+		})
+	}
+
+	c.updateStatus(ctx.Context, upstream, conditions, discoveredConfigHash(result.Provider), certificateAuthorityStatus(upstream))
 
 	valid := true
 	log := c.log.WithValues("namespace", upstream.Namespace, "name", upstream.Name)
 	for _, condition := range conditions {
 		if condition.Status == v1alpha1.ConditionFalse {
-			valid = false
+			// The password grant stays enabled as configured even when the issuer does not advertise it, so that
+			// issuers which support it without advertising it keep working. The condition only reports the mismatch.
+			if condition.Type != typePasswordGrantSupported {
+				valid = false
+			}
 			log.WithValues(
 				"type", condition.Type,
 				"reason", condition.Reason,
@@ -258,10 +766,101 @@ func (c *oidcWatcherController) validateUpstream(ctx controllerlib.Context, upst
 	return nil
 }
 
+// validPromptValue returns true when the value is a space-separated list of the prompt values defined by the OIDC
+// spec. The spec does not allow "none" to be combined with any other value.
+func validPromptValue(value string) bool {
+	values := strings.Split(value, " ")
+	for _, v := range values {
+		if !allowedPromptParameterValues.Has(v) {
+			return false
+		}
+	}
+	return len(values) == 1 || !sets.NewString(values...).Has("none")
+}
+
+// validListParameterValue returns whether the value of one of the standard parameters whose values are space-separated
+// lists, such as "acr_values" or "ui_locales", is a non-empty list of valid items which is not overly long.
+func validListParameterValue(name string, value string) bool {
+	if value == "" || len(value) > maxListParameterValueLength || !allowedAdditionalAuthorizeParameterValue(value) {
+		return false
+	}
+	for _, item := range strings.Split(value, " ") {
+		if item == "" || strings.TrimSpace(item) != item {
+			return false
+		}
+		if name == uiLocalesParamName && !languageTagPattern.MatchString(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// allowedAdditionalAuthorizeParameterValue returns whether the value is safe to send as an additionalAuthorizeParameter.
+// Overly long values and values containing control characters, such as newlines, are rejected.
+func allowedAdditionalAuthorizeParameterValue(value string) bool {
+	if !utf8.ValidString(value) || utf8.RuneCountInString(value) > maxAdditionalAuthorizeParameterValueLength {
+		return false
+	}
+	for _, r := range value {
+		if unicode.IsControl(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateClaims validates the .spec.claims field and returns the appropriate ClaimsValid condition.
+func validateClaims(upstream *v1alpha1.OIDCIdentityProvider) *v1alpha1.Condition {
+	if err := upstreamoidc.ValidateUsernameClaim(upstream.Spec.Claims.Username); err != nil {
+		return &v1alpha1.Condition{
+			Type:    typeClaimsValid,
+			Status:  v1alpha1.ConditionFalse,
+			Reason:  reasonInvalidClaims,
+			Message: fmt.Sprintf("claims.username template %q is invalid: %s", upstream.Spec.Claims.Username, err.Error()),
+		}
+	}
+	if delimiter := upstream.Spec.Claims.GroupsDelimiter; delimiter != "" && utf8.RuneCountInString(delimiter) != 1 {
+		return &v1alpha1.Condition{
+			Type:    typeClaimsValid,
+			Status:  v1alpha1.ConditionFalse,
+			Reason:  reasonInvalidClaims,
+			Message: fmt.Sprintf("claims.groupsDelimiter %q is invalid (expected a single character)", delimiter),
+		}
+	}
+	// The email_verified claim is only enforced when the username is mapped from the email claim.
+	if upstream.Spec.Claims.IgnoreEmailVerified && !upstreamoidc.UsernameClaimReferences(upstream.Spec.Claims.Username, emailClaimName) {
+		return &v1alpha1.Condition{
+			Type:   typeClaimsValid,
+			Status: v1alpha1.ConditionFalse,
+			Reason: reasonInvalidClaims,
+			Message: fmt.Sprintf(`claims.ignoreEmailVerified may only be set when claims.username is %q or references it, but claims.username is %q`,
+				emailClaimName, upstream.Spec.Claims.Username),
+		}
+	}
+	return &v1alpha1.Condition{
+		Type:    typeClaimsValid,
+		Status:  v1alpha1.ConditionTrue,
+		Reason:  upstreamwatchers.ReasonSuccess,
+		Message: "claims are valid",
+	}
+}
+
 // validateSecret validates the .spec.client.secretName field and returns the appropriate ClientCredentialsValid condition.
-func (c *oidcWatcherController) validateSecret(upstream *v1alpha1.OIDCIdentityProvider, result *upstreamoidc.ProviderConfig) *v1alpha1.Condition {
+// It also returns the informational ClientSecretNotExpiring condition, or nil when there is nothing to report.
+func (c *oidcWatcherController) validateSecret(upstream *v1alpha1.OIDCIdentityProvider, result *upstreamoidc.ProviderConfig) (*v1alpha1.Condition, *v1alpha1.Condition) {
 	secretName := upstream.Spec.Client.SecretName
 
+	// Validate the .spec.client.authMethod field.
+	if _, ok := authStyleForAuthMethod(upstream.Spec.Client.AuthMethod); !ok {
+		return &v1alpha1.Condition{
+			Type:   typeClientCredentialsValid,
+			Status: v1alpha1.ConditionFalse,
+			Reason: reasonInvalidAuthMethod,
+			Message: fmt.Sprintf("invalid authMethod %q (expected %q or %q)",
+				upstream.Spec.Client.AuthMethod, v1alpha1.OIDCClientAuthMethodBasic, v1alpha1.OIDCClientAuthMethodPost),
+		}, nil
+	}
+
 	// Fetch the Secret from informer cache.
 	secret, err := c.secretInformer.Lister().Secrets(upstream.Namespace).Get(secretName)
 	if err != nil {
@@ -270,7 +869,7 @@ func (c *oidcWatcherController) validateSecret(upstream *v1alpha1.OIDCIdentityPr
 			Status:  v1alpha1.ConditionFalse,
 			Reason:  upstreamwatchers.ReasonNotFound,
 			Message: err.Error(),
-		}
+		}, nil
 	}
 
 	// Validate the secret .type field.
@@ -280,19 +879,37 @@ func (c *oidcWatcherController) validateSecret(upstream *v1alpha1.OIDCIdentityPr
 			Status:  v1alpha1.ConditionFalse,
 			Reason:  upstreamwatchers.ReasonWrongType,
 			Message: fmt.Sprintf("referenced Secret %q has wrong type %q (should be %q)", secretName, secret.Type, oidcClientSecretType),
-		}
+		}, nil
 	}
 
-	// Validate the secret .data field.
+	// Validate the secret .data field. The credentials are read from the combined JSON key only when neither of the
+	// separate keys is present, so the separate keys remain the default layout.
 	clientID := secret.Data[clientIDDataKey]
 	clientSecret := secret.Data[clientSecretDataKey]
+	if combined, ok := secret.Data[combinedCredentialsDataKey]; ok && len(clientID) == 0 && len(clientSecret) == 0 {
+		var credentials struct {
+			ClientID     string `json:"clientID"`
+			ClientSecret string `json:"clientSecret"`
+		}
+		if err := json.Unmarshal(combined, &credentials); err != nil {
+			return &v1alpha1.Condition{
+				Type:   typeClientCredentialsValid,
+				Status: v1alpha1.ConditionFalse,
+				Reason: upstreamwatchers.ReasonMissingKeys,
+				Message: fmt.Sprintf("referenced Secret %q has invalid key %q (expected a JSON object with %q and %q fields): %s",
+					secretName, combinedCredentialsDataKey, clientIDDataKey, clientSecretDataKey, err.Error()),
+			}, nil
+		}
+		clientID, clientSecret = []byte(credentials.ClientID), []byte(credentials.ClientSecret)
+	}
 	if len(clientID) == 0 || len(clientSecret) == 0 {
 		return &v1alpha1.Condition{
-			Type:    typeClientCredentialsValid,
-			Status:  v1alpha1.ConditionFalse,
-			Reason:  upstreamwatchers.ReasonMissingKeys,
-			Message: fmt.Sprintf("referenced Secret %q is missing required keys %q", secretName, []string{clientIDDataKey, clientSecretDataKey}),
-		}
+			Type:   typeClientCredentialsValid,
+			Status: v1alpha1.ConditionFalse,
+			Reason: upstreamwatchers.ReasonMissingKeys,
+			Message: fmt.Sprintf("referenced Secret %q is missing required keys %q (or key %q holding both as JSON)",
+				secretName, []string{clientIDDataKey, clientSecretDataKey}, combinedCredentialsDataKey),
+		}, nil
 	}
 
 	// If everything is valid, update the result and set the condition to true.
@@ -303,25 +920,137 @@ func (c *oidcWatcherController) validateSecret(upstream *v1alpha1.OIDCIdentityPr
 		Status:  v1alpha1.ConditionTrue,
 		Reason:  upstreamwatchers.ReasonSuccess,
 		Message: "loaded client credentials",
+	}, c.validateClientSecretExpiry(upstream, secret)
+}
+
+// validateEnabled returns the Enabled condition of an OIDCIdentityProvider which is not disabled. It returns nil unless
+// the OIDCIdentityProvider was previously disabled, in which case the previous condition is replaced.
+func validateEnabled(upstream *v1alpha1.OIDCIdentityProvider) *v1alpha1.Condition {
+	for _, existing := range upstream.Status.Conditions {
+		if existing.Type == typeEnabled {
+			return &v1alpha1.Condition{
+				Type:    typeEnabled,
+				Status:  v1alpha1.ConditionTrue,
+				Reason:  upstreamwatchers.ReasonSuccess,
+				Message: "not disabled by spec.disabled",
+			}
+		}
+	}
+	return nil
+}
+
+// validateClientSecretExpiry checks the optional expiry annotation of the client credentials Secret and returns the
+// appropriate ClientSecretNotExpiring condition. An approaching expiry is only informational, so the returned condition
+// never has a status of False. It returns nil when the Secret is not annotated, unless the annotation was removed since
+// the last sync, in which case the previous condition is replaced.
+func (c *oidcWatcherController) validateClientSecretExpiry(upstream *v1alpha1.OIDCIdentityProvider, secret *corev1.Secret) *v1alpha1.Condition {
+	expiresAtValue, ok := secret.Annotations[clientSecretExpiresAtAnnotation]
+	if !ok {
+		for _, existing := range upstream.Status.Conditions {
+			if existing.Type == typeClientSecretNotExpiring {
+				return &v1alpha1.Condition{
+					Type:    typeClientSecretNotExpiring,
+					Status:  v1alpha1.ConditionTrue,
+					Reason:  upstreamwatchers.ReasonSuccess,
+					Message: fmt.Sprintf("referenced Secret %q does not have a %q annotation", secret.Name, clientSecretExpiresAtAnnotation),
+				}
+			}
+		}
+		return nil
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, expiresAtValue)
+	if err != nil {
+		return &v1alpha1.Condition{
+			Type:   typeClientSecretNotExpiring,
+			Status: v1alpha1.ConditionUnknown,
+			Reason: reasonInvalidExpiryAnnotation,
+			Message: fmt.Sprintf("referenced Secret %q has invalid %q annotation %q (expected an RFC 3339 timestamp)",
+				secret.Name, clientSecretExpiresAtAnnotation, expiresAtValue),
+		}
+	}
+
+	warningWindow := defaultClientSecretExpiryWarning
+	if windowValue, ok := secret.Annotations[clientSecretExpiryWarningAnnotation]; ok {
+		if warningWindow, err = time.ParseDuration(windowValue); err != nil || warningWindow < 0 {
+			return &v1alpha1.Condition{
+				Type:   typeClientSecretNotExpiring,
+				Status: v1alpha1.ConditionUnknown,
+				Reason: reasonInvalidExpiryAnnotation,
+				Message: fmt.Sprintf("referenced Secret %q has invalid %q annotation %q (expected a non-negative duration, e.g. 168h)",
+					secret.Name, clientSecretExpiryWarningAnnotation, windowValue),
+			}
+		}
+	}
+
+	remaining := expiresAt.Sub(c.clock.Now())
+	switch {
+	case remaining <= 0:
+		return &v1alpha1.Condition{
+			Type:    typeClientSecretNotExpiring,
+			Status:  v1alpha1.ConditionUnknown,
+			Reason:  reasonClientSecretExpired,
+			Message: fmt.Sprintf("client secret expired at %s, so logins will fail once the OIDC provider rejects it", expiresAt.UTC().Format(time.RFC3339)),
+		}
+	case remaining <= warningWindow:
+		return &v1alpha1.Condition{
+			Type:    typeClientSecretNotExpiring,
+			Status:  v1alpha1.ConditionUnknown,
+			Reason:  reasonClientSecretExpiring,
+			Message: fmt.Sprintf("client secret expires at %s, so it should be rotated soon", expiresAt.UTC().Format(time.RFC3339)),
+		}
+	default:
+		return &v1alpha1.Condition{
+			Type:    typeClientSecretNotExpiring,
+			Status:  v1alpha1.ConditionTrue,
+			Reason:  upstreamwatchers.ReasonSuccess,
+			Message: fmt.Sprintf("client secret expires at %s", expiresAt.UTC().Format(time.RFC3339)),
+		}
 	}
 }
 
 // validateIssuer validates the .spec.issuer field, performs OIDC discovery, and returns the appropriate OIDCDiscoverySucceeded condition.
 func (c *oidcWatcherController) validateIssuer(ctx context.Context, upstream *v1alpha1.OIDCIdentityProvider, result *upstreamoidc.ProviderConfig) *v1alpha1.Condition {
-	// Get the provider and HTTP Client from cache if possible.
-	discoveredProvider, httpClient := c.validatorCache.getProvider(&upstream.Spec)
+	// Never contact an issuer which the operator does not allow tenants to configure.
+	if c.allowedIssuerPattern != nil && !c.allowedIssuerPattern.MatchString(upstream.Spec.Issuer) {
+		return &v1alpha1.Condition{
+			Type:   typeOIDCDiscoverySucceeded,
+			Status: v1alpha1.ConditionFalse,
+			Reason: reasonDisallowedIssuer,
+			Message: fmt.Sprintf("issuer %q is not allowed by the configured issuer allowlist (allowed issuer pattern: %s)",
+				upstream.Spec.Issuer, c.allowedIssuerPattern),
+		}
+	}
+
+	// Load the client certificate on every sync, so that a missing or rotated Secret is noticed even when cached.
+	clientCerts, clientCertVersion, err := c.loadClientCertificate(upstream)
+	if err != nil {
+		return &v1alpha1.Condition{
+			Type:    typeOIDCDiscoverySucceeded,
+			Status:  v1alpha1.ConditionFalse,
+			Reason:  upstreamwatchers.ReasonInvalidTLSConfig,
+			Message: err.Error(),
+		}
+	}
+
+	// Skip OIDC discovery entirely when the user has configured the endpoints of the provider by hand.
+	if hasManualEndpoints(&upstream.Spec.AuthorizationConfig) {
+		return validateManualEndpoints(upstream, clientCerts, result)
+	}
+
+	// Get the provider and HTTP Client from cache if possible, unless the user asked to always perform discovery.
+	var discoveredProvider *oidc.Provider
+	var httpClient *http.Client
+	if !upstream.Spec.DisableDiscoveryCache {
+		discoveredProvider, httpClient = c.validatorCache.getProvider(&upstream.Spec, clientCertVersion)
+	}
 
 	// If the provider does not exist in the cache, do a fresh discovery lookup and save to the cache.
 	if discoveredProvider == nil {
-		var err error
-		httpClient, err = getClient(upstream)
-		if err != nil {
-			return &v1alpha1.Condition{
-				Type:    typeOIDCDiscoverySucceeded,
-				Status:  v1alpha1.ConditionFalse,
-				Reason:  upstreamwatchers.ReasonInvalidTLSConfig,
-				Message: err.Error(),
-			}
+		var clientCondition *v1alpha1.Condition
+		httpClient, clientCondition = buildClient(upstream, clientCerts)
+		if clientCondition != nil {
+			return clientCondition
 		}
 
 		_, issuerURLCondition := validateHTTPSURL(upstream.Spec.Issuer, "issuer", reasonUnreachable)
@@ -329,6 +1058,11 @@ func (c *oidcWatcherController) validateIssuer(ctx context.Context, upstream *v1
 			return issuerURLCondition
 		}
 
+		// Don't try discovery again until the backoff from the previous failures has elapsed.
+		if failedCondition := c.discoveryBackoff.getFailure(&upstream.Spec); failedCondition != nil {
+			return failedCondition
+		}
+
 		discoveredProvider, err = oidc.NewProvider(oidc.ClientContext(ctx, httpClient), upstream.Spec.Issuer)
 		if err != nil {
 			const klogLevelTrace = 6
@@ -337,16 +1071,27 @@ func (c *oidcWatcherController) validateIssuer(ctx context.Context, upstream *v1
 				"name", upstream.Name,
 				"issuer", upstream.Spec.Issuer,
 			).Error(err, "failed to perform OIDC discovery")
-			return &v1alpha1.Condition{
+			failedCondition := &v1alpha1.Condition{
 				Type:    typeOIDCDiscoverySucceeded,
 				Status:  v1alpha1.ConditionFalse,
 				Reason:  reasonUnreachable,
 				Message: fmt.Sprintf("failed to perform OIDC discovery against %q:\n%s", upstream.Spec.Issuer, truncateMostLongErr(err)),
 			}
+			// Distinguish an issuer which keeps failing, e.g. because it is misconfigured, from a flaky one.
+			if failures := c.discoveryBackoff.consecutiveFailures(&upstream.Spec) + 1; failures >= c.discoveryFailureThreshold {
+				failedCondition.Reason = reasonPersistentlyUnreachable
+				failedCondition.Message = fmt.Sprintf("failed to perform OIDC discovery against %q %d consecutive times:\n%s",
+					upstream.Spec.Issuer, failures, truncateMostLongErr(err))
+			}
+			c.discoveryBackoff.putFailure(&upstream.Spec, failedCondition)
+			return failedCondition
 		}
 
-		// Update the cache with the newly discovered value.
-		c.validatorCache.putProvider(&upstream.Spec, discoveredProvider, httpClient)
+		// Update the cache with the newly discovered value, and forget about any previous failures.
+		if !upstream.Spec.DisableDiscoveryCache {
+			c.validatorCache.putProvider(&upstream.Spec, discoveredProvider, httpClient, clientCertVersion)
+		}
+		c.discoveryBackoff.reset(&upstream.Spec)
 	}
 
 	// Get the revocation endpoint, if there is one. Many providers do not offer a revocation endpoint.
@@ -397,65 +1142,765 @@ func (c *oidcWatcherController) validateIssuer(ctx context.Context, upstream *v1
 
 	// If everything is valid, update the result and set the condition to true.
 	result.Config.Endpoint = discoveredProvider.Endpoint()
+	result.Config.Endpoint.AuthStyle, _ = authStyleForAuthMethod(upstream.Spec.Client.AuthMethod)
 	result.Provider = discoveredProvider
 	result.Client = httpClient
 	return &v1alpha1.Condition{
 		Type:    typeOIDCDiscoverySucceeded,
 		Status:  v1alpha1.ConditionTrue,
 		Reason:  upstreamwatchers.ReasonSuccess,
-		Message: "discovered issuer configuration",
+		Message: "discovered issuer configuration" + skippedCertificateAuthorityBlocksNote(upstream),
 	}
 }
 
-func (c *oidcWatcherController) updateStatus(ctx context.Context, upstream *v1alpha1.OIDCIdentityProvider, conditions []*v1alpha1.Condition) {
-	log := c.log.WithValues("namespace", upstream.Namespace, "name", upstream.Name)
-	updated := upstream.DeepCopy()
+// hasManualEndpoints returns true when any of the endpoint overrides of the authorization config are set.
+func hasManualEndpoints(config *v1alpha1.OIDCAuthorizationConfig) bool {
+	return config.AuthorizationEndpoint != "" || config.TokenEndpoint != "" || config.JWKSURI != ""
+}
 
-	hadErrorCondition := conditionsutil.Merge(conditions, upstream.Generation, &updated.Status.Conditions, log)
+// validateManualEndpoints validates the endpoint overrides of the .spec.authorizationConfig field in place of OIDC
+// discovery, and returns the appropriate OIDCDiscoverySucceeded condition.
+func validateManualEndpoints(upstream *v1alpha1.OIDCIdentityProvider, clientCerts []tls.Certificate, result *upstreamoidc.ProviderConfig) *v1alpha1.Condition {
+	config := &upstream.Spec.AuthorizationConfig
+	if config.AuthorizationEndpoint == "" || config.TokenEndpoint == "" || config.JWKSURI == "" {
+		return &v1alpha1.Condition{
+			Type:    typeOIDCDiscoverySucceeded,
+			Status:  v1alpha1.ConditionFalse,
+			Reason:  reasonInvalidEndpointOverride,
+			Message: "spec.authorizationConfig.authorizationEndpoint, tokenEndpoint, and jwksURI must all be set when any of them are set",
+		}
+	}
 
-	updated.Status.Phase = v1alpha1.PhaseReady
-	if hadErrorCondition {
-		updated.Status.Phase = v1alpha1.PhaseError
+	httpClient, clientCondition := buildClient(upstream, clientCerts)
+	if clientCondition != nil {
+		return clientCondition
 	}
 
-	if equality.Semantic.DeepEqual(upstream, updated) {
-		return
+	_, issuerURLCondition := validateHTTPSURL(upstream.Spec.Issuer, "issuer", reasonUnreachable)
+	if issuerURLCondition != nil {
+		return issuerURLCondition
 	}
 
-	_, err := c.client.
-		IDPV1alpha1().
-		OIDCIdentityProviders(upstream.Namespace).
+	for _, endpoint := range []struct{ url, endpointType string }{
+		{config.AuthorizationEndpoint, "authorization endpoint"},
+		{config.TokenEndpoint, "token endpoint"},
+		{config.JWKSURI, "JWKS"},
+	} {
+		_, urlCondition := validateHTTPSURL(endpoint.url, endpoint.endpointType, reasonInvalidEndpointOverride)
+		if urlCondition != nil {
+			return urlCondition
+		}
+	}
+
+	claims, err := json.Marshal(map[string]string{
+		"issuer":                 upstream.Spec.Issuer,
+		"authorization_endpoint": config.AuthorizationEndpoint,
+		"token_endpoint":         config.TokenEndpoint,
+		"jwks_uri":               config.JWKSURI,
+	})
+	if err != nil {
+		// This shouldn't actually happen because a map of strings can always be marshaled.
+		return &v1alpha1.Condition{
+			Type:    typeOIDCDiscoverySucceeded,
+			Status:  v1alpha1.ConditionFalse,
+			Reason:  reasonInvalidEndpointOverride,
+			Message: fmt.Sprintf("failed to marshal manually configured endpoints: %s", truncateMostLongErr(err)),
+		}
+	}
+
+	// If everything is valid, update the result and set the condition to true.
+	result.Config.Endpoint = oauth2.Endpoint{
+		AuthURL:  config.AuthorizationEndpoint,
+		TokenURL: config.TokenEndpoint,
+	}
+	result.Config.Endpoint.AuthStyle, _ = authStyleForAuthMethod(upstream.Spec.Client.AuthMethod)
+	result.Provider = &manualProvider{
+		issuer:    upstream.Spec.Issuer,
+		rawClaims: claims,
+		keySet:    oidc.NewRemoteKeySet(oidc.ClientContext(context.Background(), httpClient), config.JWKSURI),
+	}
+	result.Client = httpClient
+	return &v1alpha1.Condition{
+		Type:    typeOIDCDiscoverySucceeded,
+		Status:  v1alpha1.ConditionTrue,
+		Reason:  upstreamwatchers.ReasonSuccess,
+		Message: "using manually configured endpoints instead of OIDC discovery" + skippedCertificateAuthorityBlocksNote(upstream),
+	}
+}
+
+// manualProvider stands in for an *oidc.Provider when the endpoints of an issuer were configured by hand instead of
+// being discovered. Its claims only contain the configured endpoints, so it does not offer a userinfo endpoint.
+type manualProvider struct {
+	issuer    string
+	rawClaims []byte
+	keySet    oidc.KeySet
+}
+
+func (p *manualProvider) Verifier(config *oidc.Config) *oidc.IDTokenVerifier {
+	return oidc.NewVerifier(p.issuer, p.keySet, config)
+}
+
+func (p *manualProvider) Claims(v interface{}) error {
+	return json.Unmarshal(p.rawClaims, v)
+}
+
+func (p *manualProvider) UserInfo(_ context.Context, _ oauth2.TokenSource) (*oidc.UserInfo, error) {
+	return nil, constable.Error("oidc: user info endpoint is not supported by this provider")
+}
+
+// validateJWKS fetches the JWKS of a successfully discovered issuer and returns the appropriate JWKSFetchSucceeded condition.
+func (c *oidcWatcherController) validateJWKS(ctx context.Context, upstream *v1alpha1.OIDCIdentityProvider, result *upstreamoidc.ProviderConfig) *v1alpha1.Condition {
+	// Validate the JWKS CA bundle before anything else, so that it is reported even when OIDC discovery fails.
+	jwksRootCAs, err := parseJWKSCertificateAuthorityData(upstream)
+	if err != nil {
+		return &v1alpha1.Condition{
+			Type:    typeJWKSFetchSucceeded,
+			Status:  v1alpha1.ConditionFalse,
+			Reason:  upstreamwatchers.ReasonInvalidTLSConfig,
+			Message: err.Error(),
+		}
+	}
+
+	// The location of the JWKS is only known after OIDC discovery has succeeded.
+	if result.Provider == nil {
+		return &v1alpha1.Condition{
+			Type:    typeJWKSFetchSucceeded,
+			Status:  v1alpha1.ConditionUnknown,
+			Reason:  reasonOIDCDiscoveryFailed,
+			Message: "cannot fetch JWKS until OIDC discovery succeeds",
+		}
+	}
+
+	var jwksDiscoveryClaims struct {
+		JWKSURL string `json:"jwks_uri"`
+	}
+	if err := result.Provider.Claims(&jwksDiscoveryClaims); err != nil || jwksDiscoveryClaims.JWKSURL == "" {
+		return &v1alpha1.Condition{
+			Type:    typeJWKSFetchSucceeded,
+			Status:  v1alpha1.ConditionFalse,
+			Reason:  reasonInvalidResponse,
+			Message: fmt.Sprintf("OIDC discovery response from %q does not contain a jwks_uri", upstream.Spec.Issuer),
+		}
+	}
+	jwksURL := jwksDiscoveryClaims.JWKSURL
+
+	// Without a separate JWKS CA bundle, the signing keys are fetched by the discovered provider with its own client.
+	jwksClient := result.Client
+	var jwksProvider *separateJWKSProvider
+	if jwksRootCAs != nil {
+		clientCerts, clientCertVersion, err := c.loadClientCertificate(upstream)
+		if err != nil {
+			return &v1alpha1.Condition{
+				Type:    typeJWKSFetchSucceeded,
+				Status:  v1alpha1.ConditionFalse,
+				Reason:  upstreamwatchers.ReasonInvalidTLSConfig,
+				Message: err.Error(),
+			}
+		}
+
+		// Reuse the cached client and key set when possible, so that the fetched signing keys are not thrown away.
+		if !upstream.Spec.DisableDiscoveryCache {
+			jwksProvider, jwksClient = c.validatorCache.getJWKSProvider(&upstream.Spec, clientCertVersion, jwksURL)
+		}
+		if jwksProvider == nil {
+			jwksClient = buildJWKSClient(upstream, jwksRootCAs, clientCerts)
+			jwksProvider = newSeparateJWKSProvider(upstream.Spec.Issuer, result.Provider, jwksClient, jwksURL)
+			if !upstream.Spec.DisableDiscoveryCache {
+				c.validatorCache.putJWKSProvider(&upstream.Spec, clientCertVersion, jwksURL, jwksProvider, jwksClient)
+			}
+		}
+	}
+
+	if !c.jwksCache.hasUsableKeys(&upstream.Spec, jwksURL) {
+		rawJWKS, err := fetchJWKS(ctx, jwksClient, jwksURL)
+		if err != nil {
+			return &v1alpha1.Condition{
+				Type:    typeJWKSFetchSucceeded,
+				Status:  v1alpha1.ConditionFalse,
+				Reason:  reasonUnreachable,
+				Message: fmt.Sprintf("failed to fetch JWKS from %q:\n%s", jwksURL, truncateMostLongErr(err)),
+			}
+		}
+
+		var keySet jose.JSONWebKeySet
+		if err := json.Unmarshal(rawJWKS, &keySet); err != nil {
+			return &v1alpha1.Condition{
+				Type:    typeJWKSFetchSucceeded,
+				Status:  v1alpha1.ConditionFalse,
+				Reason:  reasonInvalidResponse,
+				Message: fmt.Sprintf("failed to parse JWKS from %q:\n%s", jwksURL, truncateMostLongErr(err)),
+			}
+		}
+		if !hasUsableSigningKey(&keySet) {
+			return &v1alpha1.Condition{
+				Type:    typeJWKSFetchSucceeded,
+				Status:  v1alpha1.ConditionFalse,
+				Reason:  reasonInvalidResponse,
+				Message: fmt.Sprintf("JWKS from %q does not contain any usable signing keys", jwksURL),
+			}
+		}
+
+		c.jwksCache.putUsableKeys(&upstream.Spec, jwksURL)
+	}
+
+	// Make sure that the signing keys for ID tokens are also fetched with the client which trusts the JWKS CA bundle.
+	if jwksProvider != nil {
+		result.Provider = jwksProvider
+	}
+
+	return &v1alpha1.Condition{
+		Type:    typeJWKSFetchSucceeded,
+		Status:  v1alpha1.ConditionTrue,
+		Reason:  upstreamwatchers.ReasonSuccess,
+		Message: "fetched JWKS with usable signing keys",
+	}
+}
+
+// parseJWKSCertificateAuthorityData returns the root CAs of the .spec.tls.jwksCertificateAuthorityData field, or nil
+// when it is not set.
+func parseJWKSCertificateAuthorityData(upstream *v1alpha1.OIDCIdentityProvider) (*x509.CertPool, error) {
+	if upstream.Spec.TLS == nil || upstream.Spec.TLS.JWKSCertificateAuthorityData == "" {
+		return nil, nil
+	}
+	rootCAs, _, _, err := parseCertificateAuthorityData("spec.tls.jwksCertificateAuthorityData", upstream.Spec.TLS.JWKSCertificateAuthorityData)
+	return rootCAs, err
+}
+
+// buildJWKSClient returns the HTTP client to use for requests to the JWKS URL of a successfully discovered issuer
+// whose JWKS is served with a separate CA bundle.
+func buildJWKSClient(upstream *v1alpha1.OIDCIdentityProvider, rootCAs *x509.CertPool, clientCerts []tls.Certificate) *http.Client {
+	// The proxy URL and the request timeout were already validated by OIDC discovery.
+	proxyURL, _ := validateProxyURL(upstream.Spec.ProxyURL)
+	timeout, _ := validateRequestTimeout(upstream.Spec.RequestTimeout)
+	return defaultClientShortTimeout(rootCAs, proxyURL, clientCerts, timeout)
+}
+
+// upstreamProvider is the provider of an upstreamoidc.ProviderConfig.
+type upstreamProvider interface {
+	Verifier(config *oidc.Config) *oidc.IDTokenVerifier
+	Claims(v interface{}) error
+	UserInfo(ctx context.Context, tokenSource oauth2.TokenSource) (*oidc.UserInfo, error)
+}
+
+// separateJWKSProvider wraps the provider of an issuer whose JWKS is served with a different CA bundle than its other
+// endpoints, so that ID tokens are verified with signing keys which are fetched with a client that trusts that bundle.
+type separateJWKSProvider struct {
+	upstreamProvider
+	issuer     string
+	algorithms []string
+	keySet     oidc.KeySet
+}
+
+func newSeparateJWKSProvider(issuer string, provider upstreamProvider, jwksClient *http.Client, jwksURL string) *separateJWKSProvider {
+	// Like an *oidc.Provider, only allow the advertised signing algorithms which the oidc package supports.
+	var algorithmClaims struct {
+		Algorithms []string `json:"id_token_signing_alg_values_supported"`
+	}
+	_ = provider.Claims(&algorithmClaims) // the claims were already successfully parsed during OIDC discovery
+	var algorithms []string
+	for _, algorithm := range algorithmClaims.Algorithms {
+		if supportedSigningAlgorithms.Has(algorithm) {
+			algorithms = append(algorithms, algorithm)
+		}
+	}
+
+	return &separateJWKSProvider{
+		upstreamProvider: provider,
+		issuer:           issuer,
+		algorithms:       algorithms,
+		keySet:           oidc.NewRemoteKeySet(oidc.ClientContext(context.Background(), jwksClient), jwksURL),
+	}
+}
+
+func (p *separateJWKSProvider) Verifier(config *oidc.Config) *oidc.IDTokenVerifier {
+	if len(config.SupportedSigningAlgs) == 0 && len(p.algorithms) > 0 {
+		configCopy := *config
+		configCopy.SupportedSigningAlgs = p.algorithms
+		config = &configCopy
+	}
+	return oidc.NewVerifier(p.issuer, p.keySet, config)
+}
+
+// validateScopes compares the requested scopes to the scopes advertised in the discovery document of a successfully
+// discovered issuer and returns the appropriate ScopesSupported condition.
+func validateScopes(upstream *v1alpha1.OIDCIdentityProvider, result *upstreamoidc.ProviderConfig) *v1alpha1.Condition {
+	if replaceScopes := upstream.Spec.AuthorizationConfig.ReplaceScopes; replaceScopes != nil && len(replaceScopes) == 0 {
+		return &v1alpha1.Condition{
+			Type:    typeScopesSupported,
+			Status:  v1alpha1.ConditionFalse,
+			Reason:  reasonInvalidScopes,
+			Message: "replaceScopes must not be empty when it is set",
+		}
+	}
+
+	if result.Provider == nil {
+		return &v1alpha1.Condition{
+			Type:    typeScopesSupported,
+			Status:  v1alpha1.ConditionUnknown,
+			Reason:  reasonOIDCDiscoveryFailed,
+			Message: "cannot check scopes until OIDC discovery succeeds",
+		}
+	}
+
+	var scopesDiscoveryClaims struct {
+		// "scopes_supported" is recommended, but not required, by the OIDC discovery spec.
+		ScopesSupported []string `json:"scopes_supported"`
+	}
+	if err := result.Provider.Claims(&scopesDiscoveryClaims); err != nil || len(scopesDiscoveryClaims.ScopesSupported) == 0 {
+		return &v1alpha1.Condition{
+			Type:    typeScopesSupported,
+			Status:  v1alpha1.ConditionTrue,
+			Reason:  upstreamwatchers.ReasonSuccess,
+			Message: "OIDC provider does not advertise its supported scopes",
+		}
+	}
+
+	supported := sets.NewString(scopesDiscoveryClaims.ScopesSupported...)
+	var unadvertised []string
+	for _, scope := range result.Config.Scopes {
+		if !supported.Has(scope) {
+			unadvertised = append(unadvertised, scope)
+		}
+	}
+
+	switch {
+	case len(unadvertised) == 0:
+		return &v1alpha1.Condition{
+			Type:    typeScopesSupported,
+			Status:  v1alpha1.ConditionTrue,
+			Reason:  upstreamwatchers.ReasonSuccess,
+			Message: "all requested scopes are advertised by the OIDC provider",
+		}
+	case upstream.Spec.AuthorizationConfig.AllowUnadvertisedScopes:
+		return &v1alpha1.Condition{
+			Type:   typeScopesSupported,
+			Status: v1alpha1.ConditionTrue,
+			Reason: reasonUnadvertisedScopes,
+			Message: fmt.Sprintf("the following requested scopes are not advertised by the OIDC provider, but are allowed by allowUnadvertisedScopes: %s",
+				strings.Join(unadvertised, ",")),
+		}
+	default:
+		return &v1alpha1.Condition{
+			Type:   typeScopesSupported,
+			Status: v1alpha1.ConditionFalse,
+			Reason: reasonUnadvertisedScopes,
+			Message: fmt.Sprintf("the following requested scopes are not advertised by the OIDC provider: %s",
+				strings.Join(unadvertised, ",")),
+		}
+	}
+}
+
+// validateRefreshTokenSupport checks whether the discovery document of a successfully discovered issuer advertises
+// support for refresh tokens and returns the appropriate RefreshTokenSupported condition. A lack of advertised support
+// is only informational, so the returned condition never has a status of False.
+func validateRefreshTokenSupport(result *upstreamoidc.ProviderConfig) *v1alpha1.Condition {
+	if result.Provider == nil {
+		return &v1alpha1.Condition{
+			Type:    typeRefreshTokenSupported,
+			Status:  v1alpha1.ConditionUnknown,
+			Reason:  reasonOIDCDiscoveryFailed,
+			Message: "cannot check refresh token support until OIDC discovery succeeds",
+		}
+	}
+
+	var refreshDiscoveryClaims struct {
+		// Both of these are optional in the OIDC discovery spec.
+		GrantTypesSupported []string `json:"grant_types_supported"`
+		ScopesSupported     []string `json:"scopes_supported"`
+	}
+	_ = result.Provider.Claims(&refreshDiscoveryClaims)
+
+	var unadvertised []string
+	if len(refreshDiscoveryClaims.GrantTypesSupported) > 0 &&
+		!sets.NewString(refreshDiscoveryClaims.GrantTypesSupported...).Has(refreshTokenGrantType) {
+		unadvertised = append(unadvertised, fmt.Sprintf("grant_types_supported does not include %q", refreshTokenGrantType))
+	}
+	if len(refreshDiscoveryClaims.ScopesSupported) > 0 &&
+		sets.NewString(result.Config.Scopes...).Has(oidc.ScopeOfflineAccess) &&
+		!sets.NewString(refreshDiscoveryClaims.ScopesSupported...).Has(oidc.ScopeOfflineAccess) {
+		unadvertised = append(unadvertised, fmt.Sprintf("scopes_supported does not include %q", oidc.ScopeOfflineAccess))
+	}
+
+	switch {
+	case len(unadvertised) > 0:
+		return &v1alpha1.Condition{
+			Type:   typeRefreshTokenSupported,
+			Status: v1alpha1.ConditionUnknown,
+			Reason: reasonRefreshNotAdvertised,
+			Message: fmt.Sprintf("OIDC provider does not advertise support for refresh tokens, so users may need to log in "+
+				"again whenever their session expires: %s", strings.Join(unadvertised, "; ")),
+		}
+	case len(refreshDiscoveryClaims.GrantTypesSupported) == 0:
+		return &v1alpha1.Condition{
+			Type:    typeRefreshTokenSupported,
+			Status:  v1alpha1.ConditionTrue,
+			Reason:  upstreamwatchers.ReasonSuccess,
+			Message: "OIDC provider does not advertise its supported grant types",
+		}
+	default:
+		return &v1alpha1.Condition{
+			Type:    typeRefreshTokenSupported,
+			Status:  v1alpha1.ConditionTrue,
+			Reason:  upstreamwatchers.ReasonSuccess,
+			Message: "OIDC provider advertises support for refresh tokens",
+		}
+	}
+}
+
+// validatePasswordGrantSupport checks whether the discovery document of a successfully discovered issuer advertises
+// support for the resource owner password credentials grant when .spec.authorizationConfig.allowPasswordGrant is true,
+// and returns the appropriate PasswordGrantSupported condition. It returns nil when the password grant is not allowed,
+// unless it was allowed since the last sync, in which case the previous condition is replaced.
+func validatePasswordGrantSupport(upstream *v1alpha1.OIDCIdentityProvider, result *upstreamoidc.ProviderConfig) *v1alpha1.Condition {
+	if !result.AllowPasswordGrant {
+		for _, existing := range upstream.Status.Conditions {
+			if existing.Type == typePasswordGrantSupported {
+				return &v1alpha1.Condition{
+					Type:    typePasswordGrantSupported,
+					Status:  v1alpha1.ConditionTrue,
+					Reason:  upstreamwatchers.ReasonSuccess,
+					Message: "password grant is not allowed by allowPasswordGrant",
+				}
+			}
+		}
+		return nil
+	}
+
+	if result.Provider == nil {
+		return &v1alpha1.Condition{
+			Type:    typePasswordGrantSupported,
+			Status:  v1alpha1.ConditionUnknown,
+			Reason:  reasonOIDCDiscoveryFailed,
+			Message: "cannot check password grant support until OIDC discovery succeeds",
+		}
+	}
+
+	var passwordDiscoveryClaims struct {
+		// This is optional in the OIDC discovery spec.
+		GrantTypesSupported []string `json:"grant_types_supported"`
+	}
+	_ = result.Provider.Claims(&passwordDiscoveryClaims)
+
+	switch {
+	case len(passwordDiscoveryClaims.GrantTypesSupported) == 0:
+		return &v1alpha1.Condition{
+			Type:    typePasswordGrantSupported,
+			Status:  v1alpha1.ConditionTrue,
+			Reason:  upstreamwatchers.ReasonSuccess,
+			Message: "OIDC provider does not advertise its supported grant types",
+		}
+	case !sets.NewString(passwordDiscoveryClaims.GrantTypesSupported...).Has(passwordGrantType):
+		return &v1alpha1.Condition{
+			Type:   typePasswordGrantSupported,
+			Status: v1alpha1.ConditionFalse,
+			Reason: reasonPasswordNotAdvertised,
+			Message: fmt.Sprintf("allowPasswordGrant is true but the OIDC provider does not advertise support for the password grant "+
+				"(grant_types_supported does not include %q), so password logins may be rejected by the OIDC provider", passwordGrantType),
+		}
+	default:
+		return &v1alpha1.Condition{
+			Type:    typePasswordGrantSupported,
+			Status:  v1alpha1.ConditionTrue,
+			Reason:  upstreamwatchers.ReasonSuccess,
+			Message: "OIDC provider advertises support for the password grant",
+		}
+	}
+}
+
+func fetchJWKS(ctx context.Context, client *http.Client, jwksURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %q", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// hasUsableSigningKey returns true when the key set contains at least one public key which may be used to verify
+// ID token signatures.
+func hasUsableSigningKey(keySet *jose.JSONWebKeySet) bool {
+	for i := range keySet.Keys {
+		key := &keySet.Keys[i]
+		if key.Valid() && key.IsPublic() && (key.Use == "" || key.Use == "sig") {
+			return true
+		}
+	}
+	return false
+}
+
+// discoveredConfigHash returns a stable hash of the metadata of a validated provider, or an empty string when
+// there is no validated provider. Marshaling the claims as a map sorts their keys, so the hash does not depend
+// on how the provider happened to order the fields of its discovery document.
+func discoveredConfigHash(p interface{ Claims(v interface{}) error }) string {
+	if p == nil {
+		return ""
+	}
+	var claims map[string]interface{}
+	if err := p.Claims(&claims); err != nil {
+		return ""
+	}
+	canonicalClaims, err := json.Marshal(claims)
+	if err != nil {
+		return ""
+	}
+	hash := sha256.Sum256(canonicalClaims)
+	return hex.EncodeToString(hash[:])
+}
+
+func (c *oidcWatcherController) updateStatus(ctx context.Context, upstream *v1alpha1.OIDCIdentityProvider, conditions []*v1alpha1.Condition, discoveredConfigHash string, certificateAuthority *v1alpha1.OIDCCertificateAuthorityStatus) {
+	log := c.log.WithValues("namespace", upstream.Namespace, "name", upstream.Name)
+	updated := upstream.DeepCopy()
+
+	hadErrorCondition := conditionsutil.Merge(conditions, upstream.Generation, &updated.Status.Conditions, log)
+
+	switch {
+	case upstream.Spec.Disabled:
+		updated.Status.Phase = v1alpha1.PhaseDisabled
+	case hadErrorCondition:
+		updated.Status.Phase = v1alpha1.PhaseError
+	default:
+		updated.Status.Phase = v1alpha1.PhaseReady
+	}
+
+	// Keep the hash of the last successfully observed metadata when discovery fails.
+	if discoveredConfigHash != "" && discoveredConfigHash != updated.Status.DiscoveredConfigHash {
+		updated.Status.DiscoveredConfigHash = discoveredConfigHash
+	}
+
+	updated.Status.CertificateAuthority = certificateAuthority
+
+	if equality.Semantic.DeepEqual(upstream, updated) {
+		return
+	}
+
+	_, err := c.client.
+		IDPV1alpha1().
+		OIDCIdentityProviders(upstream.Namespace).
 		UpdateStatus(ctx, updated, metav1.UpdateOptions{})
 	if err != nil {
 		log.Error(err, "failed to update status")
 	}
 }
 
-func getClient(upstream *v1alpha1.OIDCIdentityProvider) (*http.Client, error) {
+// buildClient validates the .spec.proxyURL, .spec.requestTimeout, and .spec.tls fields and returns the HTTP client to
+// use for requests to the issuer, or the appropriate failing OIDCDiscoverySucceeded condition.
+func buildClient(upstream *v1alpha1.OIDCIdentityProvider, clientCerts []tls.Certificate) (*http.Client, *v1alpha1.Condition) {
+	proxyURL, proxyURLCondition := validateProxyURL(upstream.Spec.ProxyURL)
+	if proxyURLCondition != nil {
+		return nil, proxyURLCondition
+	}
+
+	timeout, timeoutCondition := validateRequestTimeout(upstream.Spec.RequestTimeout)
+	if timeoutCondition != nil {
+		return nil, timeoutCondition
+	}
+
+	httpClient, err := getClient(upstream, proxyURL, clientCerts, timeout)
+	if err != nil {
+		return nil, &v1alpha1.Condition{
+			Type:    typeOIDCDiscoverySucceeded,
+			Status:  v1alpha1.ConditionFalse,
+			Reason:  upstreamwatchers.ReasonInvalidTLSConfig,
+			Message: err.Error(),
+		}
+	}
+	return httpClient, nil
+}
+
+// validateRequestTimeout validates the .spec.requestTimeout field, which may be nil. It returns the default timeout when it is nil.
+func validateRequestTimeout(requestTimeout *metav1.Duration) (time.Duration, *v1alpha1.Condition) {
+	if requestTimeout == nil {
+		return defaultRequestTimeout, nil
+	}
+	if requestTimeout.Duration <= 0 || requestTimeout.Duration > maxRequestTimeout {
+		return 0, &v1alpha1.Condition{
+			Type:    typeOIDCDiscoverySucceeded,
+			Status:  v1alpha1.ConditionFalse,
+			Reason:  reasonInvalidRequestTimeout,
+			Message: fmt.Sprintf("spec.requestTimeout %q is invalid (expected greater than 0s and at most %s)", requestTimeout.Duration, maxRequestTimeout),
+		}
+	}
+	return requestTimeout.Duration, nil
+}
+
+// validateProxyURL validates the .spec.proxyURL field, which may be empty. It returns a nil URL when it is empty.
+func validateProxyURL(maybeProxyURL string) (*url.URL, *v1alpha1.Condition) {
+	if maybeProxyURL == "" {
+		return nil, nil
+	}
+	parsedURL, err := url.Parse(maybeProxyURL)
+	if err != nil {
+		return nil, &v1alpha1.Condition{
+			Type:    typeOIDCDiscoverySucceeded,
+			Status:  v1alpha1.ConditionFalse,
+			Reason:  reasonInvalidProxyConfig,
+			Message: fmt.Sprintf("spec.proxyURL is invalid: %v", truncateMostLongErr(err)),
+		}
+	}
+	switch parsedURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, &v1alpha1.Condition{
+			Type:    typeOIDCDiscoverySucceeded,
+			Status:  v1alpha1.ConditionFalse,
+			Reason:  reasonInvalidProxyConfig,
+			Message: fmt.Sprintf(`spec.proxyURL '%s' must have "http", "https", or "socks5" scheme, not %q`, maybeProxyURL, parsedURL.Scheme),
+		}
+	}
+	if parsedURL.Host == "" {
+		return nil, &v1alpha1.Condition{
+			Type:    typeOIDCDiscoverySucceeded,
+			Status:  v1alpha1.ConditionFalse,
+			Reason:  reasonInvalidProxyConfig,
+			Message: fmt.Sprintf("spec.proxyURL '%s' must have a host", maybeProxyURL),
+		}
+	}
+	return parsedURL, nil
+}
+
+// loadClientCertificate loads the client certificate referenced by the .spec.tls.clientCertificateSecretName field,
+// if there is one. It also returns the resource version of the Secret, which changes whenever the Secret changes.
+func (c *oidcWatcherController) loadClientCertificate(upstream *v1alpha1.OIDCIdentityProvider) ([]tls.Certificate, string, error) {
+	if upstream.Spec.TLS == nil || upstream.Spec.TLS.ClientCertificateSecretName == "" {
+		return nil, "", nil
+	}
+	secretName := upstream.Spec.TLS.ClientCertificateSecretName
+
+	secret, err := c.secretInformer.Lister().Secrets(upstream.Namespace).Get(secretName)
+	if err != nil {
+		return nil, "", fmt.Errorf("spec.tls.clientCertificateSecretName is invalid: %w", err)
+	}
+	if secret.Type != corev1.SecretTypeTLS {
+		return nil, "", fmt.Errorf("spec.tls.clientCertificateSecretName is invalid: referenced Secret %q has wrong type %q (should be %q)",
+			secretName, secret.Type, corev1.SecretTypeTLS)
+	}
+	clientCert, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, "", fmt.Errorf("spec.tls.clientCertificateSecretName is invalid: referenced Secret %q does not contain a valid certificate and key: %w",
+			secretName, err)
+	}
+	return []tls.Certificate{clientCert}, secret.ResourceVersion, nil
+}
+
+func getClient(upstream *v1alpha1.OIDCIdentityProvider, proxyURL *url.URL, clientCerts []tls.Certificate, timeout time.Duration) (*http.Client, error) {
 	if upstream.Spec.TLS == nil || upstream.Spec.TLS.CertificateAuthorityData == "" {
-		return defaultClientShortTimeout(nil), nil
+		return defaultClientShortTimeout(nil, proxyURL, clientCerts, timeout), nil
 	}
 
-	bundle, err := base64.StdEncoding.DecodeString(upstream.Spec.TLS.CertificateAuthorityData)
+	rootCAs, _, _, err := parseCertificateAuthorityData("spec.certificateAuthorityData", upstream.Spec.TLS.CertificateAuthorityData)
 	if err != nil {
-		return nil, fmt.Errorf("spec.certificateAuthorityData is invalid: %w", err)
+		return nil, err
+	}
+
+	return defaultClientShortTimeout(rootCAs, proxyURL, clientCerts, timeout), nil
+}
+
+// parseCertificateAuthorityData decodes a CA bundle field of .spec.tls, whose name is used in errors, into a pool of
+// trusted certificates and summarizes the first certificate of the bundle for the status of the OIDCIdentityProvider.
+// The bundle is parsed block by block, so that a bundle of several CAs is usable even when some of its PEM blocks are
+// not valid certificates. It also returns the 1-based positions of those skipped PEM blocks.
+func parseCertificateAuthorityData(fieldName, certificateAuthorityData string) (*x509.CertPool, *v1alpha1.OIDCCertificateAuthorityStatus, []int, error) {
+	bundle, err := base64.StdEncoding.DecodeString(certificateAuthorityData)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%s is invalid: %w", fieldName, err)
 	}
 
 	rootCAs := x509.NewCertPool()
-	if !rootCAs.AppendCertsFromPEM(bundle) {
-		return nil, fmt.Errorf("spec.certificateAuthorityData is invalid: %w", upstreamwatchers.ErrNoCertificates)
+	var summary *v1alpha1.OIDCCertificateAuthorityStatus
+	var certificates int32
+	var skippedBlocks []int
+	blocks := 0
+	for rest := bundle; len(rest) > 0; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		blocks++
+		// Skip the same blocks as x509.CertPool.AppendCertsFromPEM would.
+		if block.Type != "CERTIFICATE" || len(block.Headers) != 0 {
+			skippedBlocks = append(skippedBlocks, blocks)
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			skippedBlocks = append(skippedBlocks, blocks)
+			continue
+		}
+		rootCAs.AddCert(cert)
+		certificates++
+		if summary == nil {
+			summary = &v1alpha1.OIDCCertificateAuthorityStatus{
+				Subject:  cert.Subject.String(),
+				Issuer:   cert.Issuer.String(),
+				NotAfter: metav1.NewTime(cert.NotAfter),
+			}
+		}
 	}
+	if summary == nil {
+		return nil, nil, nil, fmt.Errorf("%s is invalid: %w (found %d PEM blocks)",
+			fieldName, upstreamwatchers.ErrNoCertificates, blocks)
+	}
+	summary.Certificates = certificates
 
-	return defaultClientShortTimeout(rootCAs), nil
+	return rootCAs, summary, skippedBlocks, nil
 }
 
-func defaultClientShortTimeout(rootCAs *x509.CertPool) *http.Client {
-	c := phttp.Default(rootCAs)
-	c.Timeout = time.Minute
+// skippedCertificateAuthorityBlocksNote returns a note for the OIDCDiscoverySucceeded condition about the PEM blocks
+// of the CA bundle of the upstream which are not valid certificates, or an empty string when there are none.
+func skippedCertificateAuthorityBlocksNote(upstream *v1alpha1.OIDCIdentityProvider) string {
+	if upstream.Spec.TLS == nil || upstream.Spec.TLS.CertificateAuthorityData == "" {
+		return ""
+	}
+	_, summary, skippedBlocks, err := parseCertificateAuthorityData("spec.certificateAuthorityData", upstream.Spec.TLS.CertificateAuthorityData)
+	if err != nil || len(skippedBlocks) == 0 {
+		return ""
+	}
+	positions := make([]string, 0, len(skippedBlocks))
+	for _, position := range skippedBlocks {
+		positions = append(positions, fmt.Sprintf("%d", position))
+	}
+	return fmt.Sprintf(" (spec.certificateAuthorityData: loaded %d of %d PEM blocks, skipped PEM blocks which are not valid certificates at positions %s)",
+		summary.Certificates, int(summary.Certificates)+len(skippedBlocks), strings.Join(positions, ", "))
+}
+
+// certificateAuthorityStatus returns the summary of the CA bundle of the upstream for its status, or nil when it has
+// no valid CA bundle. Errors are reported by the OIDCDiscoverySucceeded condition instead.
+func certificateAuthorityStatus(upstream *v1alpha1.OIDCIdentityProvider) *v1alpha1.OIDCCertificateAuthorityStatus {
+	if upstream.Spec.TLS == nil || upstream.Spec.TLS.CertificateAuthorityData == "" {
+		return nil
+	}
+	_, summary, _, err := parseCertificateAuthorityData("spec.certificateAuthorityData", upstream.Spec.TLS.CertificateAuthorityData)
+	if err != nil {
+		return nil
+	}
+	return summary
+}
+
+func defaultClientShortTimeout(rootCAs *x509.CertPool, proxyURL *url.URL, clientCerts []tls.Certificate, timeout time.Duration) *http.Client {
+	c := phttp.DefaultWithClientCertificates(rootCAs, proxyURL, clientCerts)
+	c.Timeout = timeout
 	return c
 }
 
-func computeScopes(additionalScopes []string) []string {
+func computeScopes(additionalScopes []string, replaceScopes []string) []string {
+	// If the scopes are replaced, then use them verbatim, but always request "openid" first when it is missing.
+	if len(replaceScopes) > 0 {
+		for _, s := range replaceScopes {
+			if s == "openid" {
+				return append([]string(nil), replaceScopes...)
+			}
+		}
+		return append([]string{"openid"}, replaceScopes...)
+	}
+
 	// If none are set then provide a reasonable default which only tries to use scopes defined in the OIDC spec.
 	if len(additionalScopes) == 0 {
 		return []string{"openid", "offline_access", "email", "profile"}
@@ -484,6 +1929,21 @@ func truncateMostLongErr(err error) string {
 	return msg[:max] + fmt.Sprintf(" [truncated %d chars]", len(msg)-max)
 }
 
+// authStyleForAuthMethod returns the oauth2.AuthStyle to use for the given .spec.client.authMethod, or false if the
+// method is not supported. When no method is configured, the oauth2 library auto-detects the method to use.
+func authStyleForAuthMethod(method v1alpha1.OIDCClientAuthMethod) (oauth2.AuthStyle, bool) {
+	switch method {
+	case "":
+		return oauth2.AuthStyleAutoDetect, true
+	case v1alpha1.OIDCClientAuthMethodBasic:
+		return oauth2.AuthStyleInHeader, true
+	case v1alpha1.OIDCClientAuthMethodPost:
+		return oauth2.AuthStyleInParams, true
+	default:
+		return oauth2.AuthStyleAutoDetect, false
+	}
+}
+
 func validateHTTPSURL(maybeHTTPSURL, endpointType, reason string) (*url.URL, *v1alpha1.Condition) {
 	parsedURL, err := url.Parse(maybeHTTPSURL)
 	if err != nil {
@@ -4,17 +4,31 @@
 package oidcupstreamwatcher
 
 import (
+	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
 	"net/http"
 	"net/url"
 	"reflect"
+	"regexp"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	"gopkg.in/square/go-jose.v2"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -22,6 +36,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/net"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/clock"
+	clocktesting "k8s.io/utils/clock/testing"
 
 	"go.pinniped.dev/generated/latest/apis/supervisor/idp/v1alpha1"
 	pinnipedfake "go.pinniped.dev/generated/latest/client/supervisor/clientset/versioned/fake"
@@ -55,6 +71,16 @@ func TestOIDCUpstreamWatcherControllerFilterSecret(t *testing.T) {
 			wantUpdate: true,
 			wantDelete: true,
 		},
+		{
+			name: "a TLS secret, which may contain a client certificate",
+			secret: &corev1.Secret{
+				Type:       "kubernetes.io/tls",
+				ObjectMeta: metav1.ObjectMeta{Name: "some-name", Namespace: "some-namespace"},
+			},
+			wantAdd:    true,
+			wantUpdate: true,
+			wantDelete: true,
+		},
 		{
 			name: "a secret of the wrong type",
 			secret: &corev1.Secret{
@@ -91,6 +117,10 @@ func TestOIDCUpstreamWatcherControllerFilterSecret(t *testing.T) {
 				nil,
 				pinnipedInformers.IDP().V1alpha1().OIDCIdentityProviders(),
 				secretInformer,
+				nil,
+				0,
+				nil,
+				clock.RealClock{},
 				testLog.Logger,
 				withInformer.WithInformer,
 			)
@@ -113,14 +143,55 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 	// Start another test server that answers discovery successfully.
 	testIssuerCA, testIssuerURL := newTestIssuer(t)
 	testIssuerCABase64 := base64.StdEncoding.EncodeToString([]byte(testIssuerCA))
+	testIssuerCABlock, _ := pem.Decode([]byte(testIssuerCA))
+	testIssuerCACert, err := x509.ParseCertificate(testIssuerCABlock.Bytes)
+	require.NoError(t, err)
+	testIssuerCAStatus := &v1alpha1.OIDCCertificateAuthorityStatus{
+		Subject:      testIssuerCACert.Subject.String(),
+		Issuer:       testIssuerCACert.Issuer.String(),
+		NotAfter:     metav1.NewTime(testIssuerCACert.NotAfter),
+		Certificates: 1,
+	}
 	testIssuerAuthorizeURL, err := url.Parse("https://example.com/authorize")
 	require.NoError(t, err)
 	testIssuerRevocationURL, err := url.Parse("https://example.com/revoke")
 	require.NoError(t, err)
 
+	// wantDiscoveredConfigHash fetches the discovery document which the test issuer serves for the given issuer URL
+	// and returns the hash of its canonical JSON encoding, which is what the controller publishes in the status.
+	testIssuerCAPool := x509.NewCertPool()
+	require.True(t, testIssuerCAPool.AppendCertsFromPEM([]byte(testIssuerCA)))
+	testIssuerClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: testIssuerCAPool}}} //nolint:gosec // this is only a test client
+	wantDiscoveredConfigHash := func(issuer string) string {
+		resp, err := testIssuerClient.Get(strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration")
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		var discoveryDocument map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&discoveryDocument))
+		return sha256Hex(t, discoveryDocument)
+	}
+	manualEndpointsDiscoveredConfigHash := sha256Hex(t, map[string]interface{}{
+		"issuer":                 testIssuerURL + "/broken-discovery",
+		"authorization_endpoint": testIssuerAuthorizeURL.String(),
+		"token_endpoint":         testIssuerURL + "/token",
+		"jwks_uri":               testIssuerURL + "/jwks.json",
+	})
+
 	wrongCA, err := certauthority.New("foo", time.Hour)
 	require.NoError(t, err)
 	wrongCABase64 := base64.StdEncoding.EncodeToString(wrongCA.Bundle())
+	wrongCABlock, _ := pem.Decode(wrongCA.Bundle())
+	wrongCACert, err := x509.ParseCertificate(wrongCABlock.Bytes)
+	require.NoError(t, err)
+	wrongCAStatus := &v1alpha1.OIDCCertificateAuthorityStatus{
+		Subject:      "CN=foo",
+		Issuer:       "CN=foo",
+		NotAfter:     metav1.NewTime(wrongCACert.NotAfter),
+		Certificates: 1,
+	}
+
+	clientCertPEM, clientKeyPEM, err := wrongCA.IssueClientCertPEM("test-client", nil, time.Hour)
+	require.NoError(t, err)
 
 	happyAdditionalAuthorizeParametersValidCondition := v1alpha1.Condition{
 		Type:               "AdditionalAuthorizeParametersValid",
@@ -131,11 +202,19 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 	}
 	happyAdditionalAuthorizeParametersValidConditionEarlier := happyAdditionalAuthorizeParametersValidCondition
 	happyAdditionalAuthorizeParametersValidConditionEarlier.LastTransitionTime = earlier
+	happyClaimsValidCondition := v1alpha1.Condition{
+		Type:               "ClaimsValid",
+		Status:             "True",
+		Reason:             "Success",
+		Message:            "claims are valid",
+		LastTransitionTime: now,
+	}
 
 	var (
 		testNamespace                = "test-namespace"
 		testName                     = "test-name"
 		testSecretName               = "test-client-secret"
+		testClientCertSecretName     = "test-client-cert"
 		testAdditionalScopes         = []string{"scope1", "scope2", "scope3"}
 		testExpectedScopes           = []string{"openid", "scope1", "scope2", "scope3"}
 		testDefaultExpectedScopes    = []string{"openid", "offline_access", "email", "profile"}
@@ -153,6 +232,12 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 		inputUpstreams         []runtime.Object
 		inputSecrets           []runtime.Object
 		wantErr                string
+		wantRequeueAfter       time.Duration
+		wantAuthStyle          oauth2.AuthStyle
+		wantHostedDomain       string
+		wantTokenParams        map[string]string
+		wantClientCertificates int
+		wantClientTimeout      time.Duration
 		wantLogs               []string
 		wantResultingCache     []*oidctestutil.TestUpstreamOIDCIdentityProvider
 		wantResultingUpstreams []v1alpha1.OIDCIdentityProvider
@@ -166,7 +251,7 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Spec: v1alpha1.OIDCIdentityProviderSpec{
 					Issuer: testIssuerURL,
-					TLS:    &v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64},
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
 					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
 				},
 			}},
@@ -175,16 +260,23 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 			wantLogs: []string{
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="secret \"test-client-secret\" not found" "reason"="SecretNotFound" "status"="False" "type"="ClientCredentialsValid"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
 				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="secret \"test-client-secret\" not found" "name"="test-name" "namespace"="test-namespace" "reason"="SecretNotFound" "type"="ClientCredentialsValid"`,
 			},
 			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
 			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Status: v1alpha1.OIDCIdentityProviderStatus{
-					Phase: "Error",
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
 					Conditions: []v1alpha1.Condition{
 						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
 						{
 							Type:               "ClientCredentialsValid",
 							Status:             "False",
@@ -192,6 +284,13 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 							Reason:             "SecretNotFound",
 							Message:            `secret "test-client-secret" not found`,
 						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "fetched JWKS with usable signing keys",
+						},
 						{
 							Type:               "OIDCDiscoverySucceeded",
 							Status:             "True",
@@ -199,6 +298,20 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 							Reason:             "Success",
 							Message:            "discovered issuer configuration",
 						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "OIDC provider does not advertise its supported grant types",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "OIDC provider does not advertise its supported scopes",
+						},
 					},
 				},
 			}},
@@ -209,7 +322,7 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Spec: v1alpha1.OIDCIdentityProviderSpec{
 					Issuer: testIssuerURL,
-					TLS:    &v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64},
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
 					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
 				},
 			}},
@@ -222,16 +335,23 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 			wantLogs: []string{
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="referenced Secret \"test-client-secret\" has wrong type \"some-other-type\" (should be \"secrets.pinniped.dev/oidc-client\")" "reason"="SecretWrongType" "status"="False" "type"="ClientCredentialsValid"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
 				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="referenced Secret \"test-client-secret\" has wrong type \"some-other-type\" (should be \"secrets.pinniped.dev/oidc-client\")" "name"="test-name" "namespace"="test-namespace" "reason"="SecretWrongType" "type"="ClientCredentialsValid"`,
 			},
 			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
 			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Status: v1alpha1.OIDCIdentityProviderStatus{
-					Phase: "Error",
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
 					Conditions: []v1alpha1.Condition{
 						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
 						{
 							Type:               "ClientCredentialsValid",
 							Status:             "False",
@@ -239,6 +359,13 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 							Reason:             "SecretWrongType",
 							Message:            `referenced Secret "test-client-secret" has wrong type "some-other-type" (should be "secrets.pinniped.dev/oidc-client")`,
 						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "fetched JWKS with usable signing keys",
+						},
 						{
 							Type:               "OIDCDiscoverySucceeded",
 							Status:             "True",
@@ -246,6 +373,20 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 							Reason:             "Success",
 							Message:            "discovered issuer configuration",
 						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "OIDC provider does not advertise its supported grant types",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "OIDC provider does not advertise its supported scopes",
+						},
 					},
 				},
 			}},
@@ -256,7 +397,7 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Spec: v1alpha1.OIDCIdentityProviderSpec{
 					Issuer: testIssuerURL,
-					TLS:    &v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64},
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
 					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
 				},
 			}},
@@ -266,24 +407,38 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 			}},
 			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
 			wantLogs: []string{
-				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="referenced Secret \"test-client-secret\" is missing required keys [\"clientID\" \"clientSecret\"]" "reason"="SecretMissingKeys" "status"="False" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="referenced Secret \"test-client-secret\" is missing required keys [\"clientID\" \"clientSecret\"] (or key \"oidc.json\" holding both as JSON)" "reason"="SecretMissingKeys" "status"="False" "type"="ClientCredentialsValid"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
-				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="referenced Secret \"test-client-secret\" is missing required keys [\"clientID\" \"clientSecret\"]" "name"="test-name" "namespace"="test-namespace" "reason"="SecretMissingKeys" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="referenced Secret \"test-client-secret\" is missing required keys [\"clientID\" \"clientSecret\"] (or key \"oidc.json\" holding both as JSON)" "name"="test-name" "namespace"="test-namespace" "reason"="SecretMissingKeys" "type"="ClientCredentialsValid"`,
 			},
 			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
 			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Status: v1alpha1.OIDCIdentityProviderStatus{
-					Phase: "Error",
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
 					Conditions: []v1alpha1.Condition{
 						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
 						{
 							Type:               "ClientCredentialsValid",
 							Status:             "False",
 							LastTransitionTime: now,
 							Reason:             "SecretMissingKeys",
-							Message:            `referenced Secret "test-client-secret" is missing required keys ["clientID" "clientSecret"]`,
+							Message:            `referenced Secret "test-client-secret" is missing required keys ["clientID" "clientSecret"] (or key "oidc.json" holding both as JSON)`,
+						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "fetched JWKS with usable signing keys",
 						},
 						{
 							Type:               "OIDCDiscoverySucceeded",
@@ -292,67 +447,109 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 							Reason:             "Success",
 							Message:            "discovered issuer configuration",
 						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "OIDC provider does not advertise its supported grant types",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "OIDC provider does not advertise its supported scopes",
+						},
 					},
 				},
 			}},
 		},
 		{
-			name: "TLS CA bundle is invalid base64",
+			name: "secret has a combined JSON key which is invalid",
 			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
-				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "test-name"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Spec: v1alpha1.OIDCIdentityProviderSpec{
 					Issuer: testIssuerURL,
-					TLS: &v1alpha1.TLSSpec{
-						CertificateAuthorityData: "invalid-base64",
-					},
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
 					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
 				},
 			}},
 			inputSecrets: []runtime.Object{&corev1.Secret{
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
 				Type:       "secrets.pinniped.dev/oidc-client",
-				Data:       testValidSecretData,
+				Data:       map[string][]byte{"oidc.json": []byte(`{"clientID":`)},
 			}},
 			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
 			wantLogs: []string{
-				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
-				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="spec.certificateAuthorityData is invalid: illegal base64 data at input byte 7" "reason"="InvalidTLSConfig" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="referenced Secret \"test-client-secret\" has invalid key \"oidc.json\" (expected a JSON object with \"clientID\" and \"clientSecret\" fields): unexpected end of JSON input" "reason"="SecretMissingKeys" "status"="False" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
-				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="spec.certificateAuthorityData is invalid: illegal base64 data at input byte 7" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidTLSConfig" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="referenced Secret \"test-client-secret\" has invalid key \"oidc.json\" (expected a JSON object with \"clientID\" and \"clientSecret\" fields): unexpected end of JSON input" "name"="test-name" "namespace"="test-namespace" "reason"="SecretMissingKeys" "type"="ClientCredentialsValid"`,
 			},
 			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
 			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Status: v1alpha1.OIDCIdentityProviderStatus{
-					Phase: "Error",
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
 					Conditions: []v1alpha1.Condition{
 						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
 						{
 							Type:               "ClientCredentialsValid",
+							Status:             "False",
+							LastTransitionTime: now,
+							Reason:             "SecretMissingKeys",
+							Message:            `referenced Secret "test-client-secret" has invalid key "oidc.json" (expected a JSON object with "clientID" and "clientSecret" fields): unexpected end of JSON input`,
+						},
+						{
+							Type:               "JWKSFetchSucceeded",
 							Status:             "True",
 							LastTransitionTime: now,
 							Reason:             "Success",
-							Message:            "loaded client credentials",
+							Message:            "fetched JWKS with usable signing keys",
 						},
 						{
 							Type:               "OIDCDiscoverySucceeded",
-							Status:             "False",
+							Status:             "True",
 							LastTransitionTime: now,
-							Reason:             "InvalidTLSConfig",
-							Message:            `spec.certificateAuthorityData is invalid: illegal base64 data at input byte 7`,
+							Reason:             "Success",
+							Message:            "discovered issuer configuration",
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "OIDC provider does not advertise its supported grant types",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "OIDC provider does not advertise its supported scopes",
 						},
 					},
 				},
 			}},
 		},
 		{
-			name: "TLS CA bundle does not have any certificates",
+			name: "TLS CA bundle is invalid base64",
 			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "test-name"},
 				Spec: v1alpha1.OIDCIdentityProviderSpec{
 					Issuer: testIssuerURL,
-					TLS: &v1alpha1.TLSSpec{
-						CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte("not-a-pem-ca-bundle")),
+					TLS: &v1alpha1.OIDCTLSSpec{
+						TLSSpec: v1alpha1.TLSSpec{
+							CertificateAuthorityData: "invalid-base64",
+						},
 					},
 					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
 				},
@@ -365,9 +562,13 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
 			wantLogs: []string{
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
-				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="spec.certificateAuthorityData is invalid: no certificates found" "reason"="InvalidTLSConfig" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="spec.certificateAuthorityData is invalid: illegal base64 data at input byte 7" "reason"="InvalidTLSConfig" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot fetch JWKS until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
-				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="spec.certificateAuthorityData is invalid: no certificates found" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidTLSConfig" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="spec.certificateAuthorityData is invalid: illegal base64 data at input byte 7" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidTLSConfig" "type"="OIDCDiscoverySucceeded"`,
 			},
 			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
 			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
@@ -376,6 +577,7 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 					Phase: "Error",
 					Conditions: []v1alpha1.Condition{
 						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
 						{
 							Type:               "ClientCredentialsValid",
 							Status:             "True",
@@ -383,24 +585,47 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 							Reason:             "Success",
 							Message:            "loaded client credentials",
 						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot fetch JWKS until OIDC discovery succeeds",
+						},
 						{
 							Type:               "OIDCDiscoverySucceeded",
 							Status:             "False",
 							LastTransitionTime: now,
 							Reason:             "InvalidTLSConfig",
-							Message:            `spec.certificateAuthorityData is invalid: no certificates found`,
+							Message:            `spec.certificateAuthorityData is invalid: illegal base64 data at input byte 7`,
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
 						},
 					},
 				},
 			}},
 		},
 		{
-			name: "issuer is invalid URL",
+			name: "proxy URL has an unsupported scheme",
 			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
-				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "test-name"},
 				Spec: v1alpha1.OIDCIdentityProviderSpec{
-					Issuer: "%invalid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee",
-					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					Issuer:   testIssuerURL,
+					TLS:      &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					ProxyURL: "ftp://proxy.example.com:3128",
+					Client:   v1alpha1.OIDCClient{SecretName: testSecretName},
 				},
 			}},
 			inputSecrets: []runtime.Object{&corev1.Secret{
@@ -411,17 +636,23 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
 			wantLogs: []string{
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
-				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="failed to parse issuer URL: parse \"%invalid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee\": invalid URL escape \"%in\"" "reason"="Unreachable" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="spec.proxyURL 'ftp://proxy.example.com:3128' must have \"http\", \"https\", or \"socks5\" scheme, not \"ftp\"" "reason"="InvalidProxyConfig" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot fetch JWKS until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
-				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="failed to parse issuer URL: parse \"%invalid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee\": invalid URL escape \"%in\"" "name"="test-name" "namespace"="test-namespace" "reason"="Unreachable" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="spec.proxyURL 'ftp://proxy.example.com:3128' must have \"http\", \"https\", or \"socks5\" scheme, not \"ftp\"" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidProxyConfig" "type"="OIDCDiscoverySucceeded"`,
 			},
 			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
 			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Status: v1alpha1.OIDCIdentityProviderStatus{
-					Phase: "Error",
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
 					Conditions: []v1alpha1.Condition{
 						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
 						{
 							Type:               "ClientCredentialsValid",
 							Status:             "True",
@@ -429,24 +660,47 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 							Reason:             "Success",
 							Message:            "loaded client credentials",
 						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot fetch JWKS until OIDC discovery succeeds",
+						},
 						{
 							Type:               "OIDCDiscoverySucceeded",
 							Status:             "False",
 							LastTransitionTime: now,
-							Reason:             "Unreachable",
-							Message:            `failed to parse issuer URL: parse "%invalid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee": invalid URL escape "%in"`,
+							Reason:             "InvalidProxyConfig",
+							Message:            `spec.proxyURL 'ftp://proxy.example.com:3128' must have "http", "https", or "socks5" scheme, not "ftp"`,
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
 						},
 					},
 				},
 			}},
 		},
 		{
-			name: "issuer is insecure http URL",
+			name: "request timeout is not positive",
 			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
-				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "test-name"},
 				Spec: v1alpha1.OIDCIdentityProviderSpec{
-					Issuer: strings.Replace(testIssuerURL, "https", "http", 1),
-					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					Issuer:         testIssuerURL,
+					TLS:            &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					RequestTimeout: &metav1.Duration{Duration: 0},
+					Client:         v1alpha1.OIDCClient{SecretName: testSecretName},
 				},
 			}},
 			inputSecrets: []runtime.Object{&corev1.Secret{
@@ -457,17 +711,23 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
 			wantLogs: []string{
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
-				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="issuer URL '` + strings.Replace(testIssuerURL, "https", "http", 1) + `' must have \"https\" scheme, not \"http\"" "reason"="Unreachable" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="spec.requestTimeout \"0s\" is invalid (expected greater than 0s and at most 10m0s)" "reason"="InvalidRequestTimeout" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot fetch JWKS until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
-				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="issuer URL '` + strings.Replace(testIssuerURL, "https", "http", 1) + `' must have \"https\" scheme, not \"http\"" "name"="test-name" "namespace"="test-namespace" "reason"="Unreachable" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="spec.requestTimeout \"0s\" is invalid (expected greater than 0s and at most 10m0s)" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidRequestTimeout" "type"="OIDCDiscoverySucceeded"`,
 			},
 			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
 			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Status: v1alpha1.OIDCIdentityProviderStatus{
-					Phase: "Error",
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
 					Conditions: []v1alpha1.Condition{
 						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
 						{
 							Type:               "ClientCredentialsValid",
 							Status:             "True",
@@ -475,24 +735,47 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 							Reason:             "Success",
 							Message:            "loaded client credentials",
 						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot fetch JWKS until OIDC discovery succeeds",
+						},
 						{
 							Type:               "OIDCDiscoverySucceeded",
 							Status:             "False",
 							LastTransitionTime: now,
-							Reason:             "Unreachable",
-							Message:            `issuer URL '` + strings.Replace(testIssuerURL, "https", "http", 1) + `' must have "https" scheme, not "http"`,
+							Reason:             "InvalidRequestTimeout",
+							Message:            `spec.requestTimeout "0s" is invalid (expected greater than 0s and at most 10m0s)`,
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
 						},
 					},
 				},
 			}},
 		},
 		{
-			name: "issuer contains a query param",
+			name: "request timeout is too large",
 			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
-				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "test-name"},
 				Spec: v1alpha1.OIDCIdentityProviderSpec{
-					Issuer: testIssuerURL + "?sub=foo",
-					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					Issuer:         testIssuerURL,
+					TLS:            &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					RequestTimeout: &metav1.Duration{Duration: time.Hour},
+					Client:         v1alpha1.OIDCClient{SecretName: testSecretName},
 				},
 			}},
 			inputSecrets: []runtime.Object{&corev1.Secret{
@@ -503,17 +786,23 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
 			wantLogs: []string{
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
-				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="issuer URL '` + testIssuerURL + "?sub=foo" + `' cannot contain query or fragment component" "reason"="Unreachable" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="spec.requestTimeout \"1h0m0s\" is invalid (expected greater than 0s and at most 10m0s)" "reason"="InvalidRequestTimeout" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot fetch JWKS until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
-				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="issuer URL '` + testIssuerURL + "?sub=foo" + `' cannot contain query or fragment component" "name"="test-name" "namespace"="test-namespace" "reason"="Unreachable" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="spec.requestTimeout \"1h0m0s\" is invalid (expected greater than 0s and at most 10m0s)" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidRequestTimeout" "type"="OIDCDiscoverySucceeded"`,
 			},
 			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
 			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Status: v1alpha1.OIDCIdentityProviderStatus{
-					Phase: "Error",
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
 					Conditions: []v1alpha1.Condition{
 						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
 						{
 							Type:               "ClientCredentialsValid",
 							Status:             "True",
@@ -521,23 +810,49 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 							Reason:             "Success",
 							Message:            "loaded client credentials",
 						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot fetch JWKS until OIDC discovery succeeds",
+						},
 						{
 							Type:               "OIDCDiscoverySucceeded",
 							Status:             "False",
 							LastTransitionTime: now,
-							Reason:             "Unreachable",
-							Message:            `issuer URL '` + testIssuerURL + "?sub=foo" + `' cannot contain query or fragment component`,
+							Reason:             "InvalidRequestTimeout",
+							Message:            `spec.requestTimeout "1h0m0s" is invalid (expected greater than 0s and at most 10m0s)`,
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
 						},
 					},
 				},
 			}},
 		},
 		{
-			name: "issuer contains a fragment",
+			name: "TLS CA bundle does not have any certificates",
 			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
-				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "test-name"},
 				Spec: v1alpha1.OIDCIdentityProviderSpec{
-					Issuer: testIssuerURL + "#fragment",
+					Issuer: testIssuerURL,
+					TLS: &v1alpha1.OIDCTLSSpec{
+						TLSSpec: v1alpha1.TLSSpec{
+							CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte("not-a-pem-ca-bundle")),
+						},
+					},
 					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
 				},
 			}},
@@ -549,9 +864,13 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
 			wantLogs: []string{
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
-				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="issuer URL '` + testIssuerURL + "#fragment" + `' cannot contain query or fragment component" "reason"="Unreachable" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="spec.certificateAuthorityData is invalid: no certificates found (found 0 PEM blocks)" "reason"="InvalidTLSConfig" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot fetch JWKS until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
-				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="issuer URL '` + testIssuerURL + "#fragment" + `' cannot contain query or fragment component" "name"="test-name" "namespace"="test-namespace" "reason"="Unreachable" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="spec.certificateAuthorityData is invalid: no certificates found (found 0 PEM blocks)" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidTLSConfig" "type"="OIDCDiscoverySucceeded"`,
 			},
 			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
 			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
@@ -560,6 +879,7 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 					Phase: "Error",
 					Conditions: []v1alpha1.Condition{
 						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
 						{
 							Type:               "ClientCredentialsValid",
 							Status:             "True",
@@ -567,24 +887,50 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 							Reason:             "Success",
 							Message:            "loaded client credentials",
 						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot fetch JWKS until OIDC discovery succeeds",
+						},
 						{
 							Type:               "OIDCDiscoverySucceeded",
 							Status:             "False",
 							LastTransitionTime: now,
-							Reason:             "Unreachable",
-							Message:            `issuer URL '` + testIssuerURL + "#fragment" + `' cannot contain query or fragment component`,
+							Reason:             "InvalidTLSConfig",
+							Message:            `spec.certificateAuthorityData is invalid: no certificates found (found 0 PEM blocks)`,
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
 						},
 					},
 				},
 			}},
 		},
 		{
-			name: "really long issuer with invalid CA bundle",
+			name: "client certificate Secret is missing",
 			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Spec: v1alpha1.OIDCIdentityProviderSpec{
-					Issuer: testIssuerURL + "/valid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee",
-					TLS:    &v1alpha1.TLSSpec{CertificateAuthorityData: wrongCABase64},
+					Issuer: testIssuerURL,
+					TLS: &v1alpha1.OIDCTLSSpec{
+						TLSSpec: v1alpha1.TLSSpec{
+							CertificateAuthorityData: testIssuerCABase64,
+						},
+						ClientCertificateSecretName: testClientCertSecretName,
+					},
 					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
 				},
 			}},
@@ -595,19 +941,24 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 			}},
 			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
 			wantLogs: []string{
-				`oidc-upstream-observer "msg"="failed to perform OIDC discovery" "error"="Get \"` + testIssuerURL + `/valid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee/.well-known/openid-configuration\": x509: certificate signed by unknown authority" "issuer"="` + testIssuerURL + `/valid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee" "name"="test-name" "namespace"="test-namespace"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
-				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="failed to perform OIDC discovery against \"` + testIssuerURL + `/valid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee\":\nGet \"` + testIssuerURL + `/valid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee/.well-known/openid-configuration\": x509: certificate signed by unknown authority" "reason"="Unreachable" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="spec.tls.clientCertificateSecretName is invalid: secret \"test-client-cert\" not found" "reason"="InvalidTLSConfig" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot fetch JWKS until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
-				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="failed to perform OIDC discovery against \"` + testIssuerURL + `/valid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee\":\nGet \"` + testIssuerURL + `/valid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee/.well-known/openid-configuration\": x509: certificate signed by unknown authority" "name"="test-name" "namespace"="test-namespace" "reason"="Unreachable" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="spec.tls.clientCertificateSecretName is invalid: secret \"test-client-cert\" not found" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidTLSConfig" "type"="OIDCDiscoverySucceeded"`,
 			},
 			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
 			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Status: v1alpha1.OIDCIdentityProviderStatus{
-					Phase: "Error",
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
 					Conditions: []v1alpha1.Condition{
 						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
 						{
 							Type:               "ClientCredentialsValid",
 							Status:             "True",
@@ -615,25 +966,50 @@ func TestOIDCUpstreamWatcherControllerSync(t *testing.T) {
 							Reason:             "Success",
 							Message:            "loaded client credentials",
 						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot fetch JWKS until OIDC discovery succeeds",
+						},
 						{
 							Type:               "OIDCDiscoverySucceeded",
 							Status:             "False",
 							LastTransitionTime: now,
-							Reason:             "Unreachable",
-							Message: `failed to perform OIDC discovery against "` + testIssuerURL + `/valid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee":
-Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee/.well-known/openid-configuration": x509: certificate signed by unknown authority`,
+							Reason:             "InvalidTLSConfig",
+							Message:            `spec.tls.clientCertificateSecretName is invalid: secret "test-client-cert" not found`,
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
 						},
 					},
 				},
 			}},
 		},
 		{
-			name: "issuer returns invalid authorize URL",
+			name: "client certificate Secret has wrong type",
 			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Spec: v1alpha1.OIDCIdentityProviderSpec{
-					Issuer: testIssuerURL + "/invalid",
-					TLS:    &v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64},
+					Issuer: testIssuerURL,
+					TLS: &v1alpha1.OIDCTLSSpec{
+						TLSSpec: v1alpha1.TLSSpec{
+							CertificateAuthorityData: testIssuerCABase64,
+						},
+						ClientCertificateSecretName: testClientCertSecretName,
+					},
 					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
 				},
 			}},
@@ -641,21 +1017,31 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
 				Type:       "secrets.pinniped.dev/oidc-client",
 				Data:       testValidSecretData,
+			}, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testClientCertSecretName},
+				Type:       "Opaque",
+				Data:       map[string][]byte{"tls.crt": clientCertPEM, "tls.key": clientKeyPEM},
 			}},
 			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
 			wantLogs: []string{
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
-				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="failed to parse authorization endpoint URL: parse \"%\": invalid URL escape \"%\"" "reason"="InvalidResponse" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="spec.tls.clientCertificateSecretName is invalid: referenced Secret \"test-client-cert\" has wrong type \"Opaque\" (should be \"kubernetes.io/tls\")" "reason"="InvalidTLSConfig" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot fetch JWKS until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
-				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="failed to parse authorization endpoint URL: parse \"%\": invalid URL escape \"%\"" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidResponse" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="spec.tls.clientCertificateSecretName is invalid: referenced Secret \"test-client-cert\" has wrong type \"Opaque\" (should be \"kubernetes.io/tls\")" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidTLSConfig" "type"="OIDCDiscoverySucceeded"`,
 			},
 			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
 			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Status: v1alpha1.OIDCIdentityProviderStatus{
-					Phase: "Error",
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
 					Conditions: []v1alpha1.Condition{
 						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
 						{
 							Type:               "ClientCredentialsValid",
 							Status:             "True",
@@ -663,24 +1049,50 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 							Reason:             "Success",
 							Message:            "loaded client credentials",
 						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot fetch JWKS until OIDC discovery succeeds",
+						},
 						{
 							Type:               "OIDCDiscoverySucceeded",
 							Status:             "False",
 							LastTransitionTime: now,
-							Reason:             "InvalidResponse",
-							Message:            `failed to parse authorization endpoint URL: parse "%": invalid URL escape "%"`,
+							Reason:             "InvalidTLSConfig",
+							Message:            `spec.tls.clientCertificateSecretName is invalid: referenced Secret "test-client-cert" has wrong type "Opaque" (should be "kubernetes.io/tls")`,
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
 						},
 					},
 				},
 			}},
 		},
 		{
-			name: "issuer returns invalid revocation URL",
+			name: "client certificate Secret does not contain a valid certificate",
 			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Spec: v1alpha1.OIDCIdentityProviderSpec{
-					Issuer: testIssuerURL + "/invalid-revocation-url",
-					TLS:    &v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64},
+					Issuer: testIssuerURL,
+					TLS: &v1alpha1.OIDCTLSSpec{
+						TLSSpec: v1alpha1.TLSSpec{
+							CertificateAuthorityData: testIssuerCABase64,
+						},
+						ClientCertificateSecretName: testClientCertSecretName,
+					},
 					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
 				},
 			}},
@@ -688,21 +1100,31 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
 				Type:       "secrets.pinniped.dev/oidc-client",
 				Data:       testValidSecretData,
+			}, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testClientCertSecretName},
+				Type:       "kubernetes.io/tls",
+				Data:       map[string][]byte{"tls.crt": []byte("not-a-cert"), "tls.key": clientKeyPEM},
 			}},
 			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
 			wantLogs: []string{
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
-				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="failed to parse revocation endpoint URL: parse \"%\": invalid URL escape \"%\"" "reason"="InvalidResponse" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="spec.tls.clientCertificateSecretName is invalid: referenced Secret \"test-client-cert\" does not contain a valid certificate and key: tls: failed to find any PEM data in certificate input" "reason"="InvalidTLSConfig" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot fetch JWKS until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
-				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="failed to parse revocation endpoint URL: parse \"%\": invalid URL escape \"%\"" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidResponse" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="spec.tls.clientCertificateSecretName is invalid: referenced Secret \"test-client-cert\" does not contain a valid certificate and key: tls: failed to find any PEM data in certificate input" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidTLSConfig" "type"="OIDCDiscoverySucceeded"`,
 			},
 			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
 			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Status: v1alpha1.OIDCIdentityProviderStatus{
-					Phase: "Error",
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
 					Conditions: []v1alpha1.Condition{
 						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
 						{
 							Type:               "ClientCredentialsValid",
 							Status:             "True",
@@ -710,24 +1132,44 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 							Reason:             "Success",
 							Message:            "loaded client credentials",
 						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot fetch JWKS until OIDC discovery succeeds",
+						},
 						{
 							Type:               "OIDCDiscoverySucceeded",
 							Status:             "False",
 							LastTransitionTime: now,
-							Reason:             "InvalidResponse",
-							Message:            `failed to parse revocation endpoint URL: parse "%": invalid URL escape "%"`,
+							Reason:             "InvalidTLSConfig",
+							Message:            `spec.tls.clientCertificateSecretName is invalid: referenced Secret "test-client-cert" does not contain a valid certificate and key: tls: failed to find any PEM data in certificate input`,
 						},
-					},
-				},
-			}},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
+						},
+					},
+				},
+			}},
 		},
 		{
-			name: "issuer returns insecure authorize URL",
+			name: "issuer is invalid URL",
 			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Spec: v1alpha1.OIDCIdentityProviderSpec{
-					Issuer: testIssuerURL + "/insecure",
-					TLS:    &v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64},
+					Issuer: "%invalid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee",
 					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
 				},
 			}},
@@ -739,9 +1181,13 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
 			wantLogs: []string{
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
-				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="authorization endpoint URL 'http://example.com/authorize' must have \"https\" scheme, not \"http\"" "reason"="InvalidResponse" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="failed to parse issuer URL: parse \"%invalid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee\": invalid URL escape \"%in\"" "reason"="Unreachable" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot fetch JWKS until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
-				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="authorization endpoint URL 'http://example.com/authorize' must have \"https\" scheme, not \"http\"" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidResponse" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="failed to parse issuer URL: parse \"%invalid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee\": invalid URL escape \"%in\"" "name"="test-name" "namespace"="test-namespace" "reason"="Unreachable" "type"="OIDCDiscoverySucceeded"`,
 			},
 			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
 			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
@@ -750,6 +1196,7 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 					Phase: "Error",
 					Conditions: []v1alpha1.Condition{
 						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
 						{
 							Type:               "ClientCredentialsValid",
 							Status:             "True",
@@ -757,24 +1204,44 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 							Reason:             "Success",
 							Message:            "loaded client credentials",
 						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot fetch JWKS until OIDC discovery succeeds",
+						},
 						{
 							Type:               "OIDCDiscoverySucceeded",
 							Status:             "False",
 							LastTransitionTime: now,
-							Reason:             "InvalidResponse",
-							Message:            `authorization endpoint URL 'http://example.com/authorize' must have "https" scheme, not "http"`,
+							Reason:             "Unreachable",
+							Message:            `failed to parse issuer URL: parse "%invalid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee": invalid URL escape "%in"`,
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
 						},
 					},
 				},
 			}},
 		},
 		{
-			name: "issuer returns insecure revocation URL",
+			name: "issuer is insecure http URL",
 			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Spec: v1alpha1.OIDCIdentityProviderSpec{
-					Issuer: testIssuerURL + "/insecure-revocation-url",
-					TLS:    &v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64},
+					Issuer: strings.Replace(testIssuerURL, "https", "http", 1),
 					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
 				},
 			}},
@@ -786,9 +1253,13 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
 			wantLogs: []string{
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
-				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="revocation endpoint URL 'http://example.com/revoke' must have \"https\" scheme, not \"http\"" "reason"="InvalidResponse" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="issuer URL '` + strings.Replace(testIssuerURL, "https", "http", 1) + `' must have \"https\" scheme, not \"http\"" "reason"="Unreachable" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot fetch JWKS until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
-				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="revocation endpoint URL 'http://example.com/revoke' must have \"https\" scheme, not \"http\"" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidResponse" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="issuer URL '` + strings.Replace(testIssuerURL, "https", "http", 1) + `' must have \"https\" scheme, not \"http\"" "name"="test-name" "namespace"="test-namespace" "reason"="Unreachable" "type"="OIDCDiscoverySucceeded"`,
 			},
 			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
 			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
@@ -797,6 +1268,7 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 					Phase: "Error",
 					Conditions: []v1alpha1.Condition{
 						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
 						{
 							Type:               "ClientCredentialsValid",
 							Status:             "True",
@@ -804,24 +1276,44 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 							Reason:             "Success",
 							Message:            "loaded client credentials",
 						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot fetch JWKS until OIDC discovery succeeds",
+						},
 						{
 							Type:               "OIDCDiscoverySucceeded",
 							Status:             "False",
 							LastTransitionTime: now,
-							Reason:             "InvalidResponse",
-							Message:            `revocation endpoint URL 'http://example.com/revoke' must have "https" scheme, not "http"`,
+							Reason:             "Unreachable",
+							Message:            `issuer URL '` + strings.Replace(testIssuerURL, "https", "http", 1) + `' must have "https" scheme, not "http"`,
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
 						},
 					},
 				},
 			}},
 		},
 		{
-			name: "issuer returns insecure token URL",
+			name: "issuer contains a query param",
 			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Spec: v1alpha1.OIDCIdentityProviderSpec{
-					Issuer: testIssuerURL + "/insecure-token-url",
-					TLS:    &v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64},
+					Issuer: testIssuerURL + "?sub=foo",
 					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
 				},
 			}},
@@ -833,9 +1325,13 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
 			wantLogs: []string{
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
-				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="token endpoint URL 'http://example.com/token' must have \"https\" scheme, not \"http\"" "reason"="InvalidResponse" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="issuer URL '` + testIssuerURL + "?sub=foo" + `' cannot contain query or fragment component" "reason"="Unreachable" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot fetch JWKS until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
-				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="token endpoint URL 'http://example.com/token' must have \"https\" scheme, not \"http\"" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidResponse" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="issuer URL '` + testIssuerURL + "?sub=foo" + `' cannot contain query or fragment component" "name"="test-name" "namespace"="test-namespace" "reason"="Unreachable" "type"="OIDCDiscoverySucceeded"`,
 			},
 			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
 			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
@@ -844,6 +1340,7 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 					Phase: "Error",
 					Conditions: []v1alpha1.Condition{
 						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
 						{
 							Type:               "ClientCredentialsValid",
 							Status:             "True",
@@ -851,24 +1348,44 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 							Reason:             "Success",
 							Message:            "loaded client credentials",
 						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot fetch JWKS until OIDC discovery succeeds",
+						},
 						{
 							Type:               "OIDCDiscoverySucceeded",
 							Status:             "False",
 							LastTransitionTime: now,
-							Reason:             "InvalidResponse",
-							Message:            `token endpoint URL 'http://example.com/token' must have "https" scheme, not "http"`,
+							Reason:             "Unreachable",
+							Message:            `issuer URL '` + testIssuerURL + "?sub=foo" + `' cannot contain query or fragment component`,
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
 						},
 					},
 				},
 			}},
 		},
 		{
-			name: "issuer returns no token URL",
+			name: "issuer contains a fragment",
 			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Spec: v1alpha1.OIDCIdentityProviderSpec{
-					Issuer: testIssuerURL + "/missing-token-url",
-					TLS:    &v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64},
+					Issuer: testIssuerURL + "#fragment",
 					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
 				},
 			}},
@@ -880,9 +1397,13 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
 			wantLogs: []string{
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
-				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="token endpoint URL '' must have \"https\" scheme, not \"\"" "reason"="InvalidResponse" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="issuer URL '` + testIssuerURL + "#fragment" + `' cannot contain query or fragment component" "reason"="Unreachable" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot fetch JWKS until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
-				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="token endpoint URL '' must have \"https\" scheme, not \"\"" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidResponse" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="issuer URL '` + testIssuerURL + "#fragment" + `' cannot contain query or fragment component" "name"="test-name" "namespace"="test-namespace" "reason"="Unreachable" "type"="OIDCDiscoverySucceeded"`,
 			},
 			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
 			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
@@ -891,6 +1412,7 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 					Phase: "Error",
 					Conditions: []v1alpha1.Condition{
 						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
 						{
 							Type:               "ClientCredentialsValid",
 							Status:             "True",
@@ -898,24 +1420,45 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 							Reason:             "Success",
 							Message:            "loaded client credentials",
 						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot fetch JWKS until OIDC discovery succeeds",
+						},
 						{
 							Type:               "OIDCDiscoverySucceeded",
 							Status:             "False",
 							LastTransitionTime: now,
-							Reason:             "InvalidResponse",
-							Message:            `token endpoint URL '' must have "https" scheme, not ""`,
+							Reason:             "Unreachable",
+							Message:            `issuer URL '` + testIssuerURL + "#fragment" + `' cannot contain query or fragment component`,
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
 						},
 					},
 				},
 			}},
 		},
 		{
-			name: "issuer returns no auth URL",
+			name: "really long issuer with invalid CA bundle",
 			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Spec: v1alpha1.OIDCIdentityProviderSpec{
-					Issuer: testIssuerURL + "/missing-auth-url",
-					TLS:    &v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64},
+					Issuer: testIssuerURL + "/valid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee",
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: wrongCABase64}},
 					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
 				},
 			}},
@@ -924,20 +1467,27 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 				Type:       "secrets.pinniped.dev/oidc-client",
 				Data:       testValidSecretData,
 			}},
-			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
+			wantRequeueAfter: discoveryBackoffInitialDelay,
 			wantLogs: []string{
+				`oidc-upstream-observer "msg"="failed to perform OIDC discovery" "error"="Get \"` + testIssuerURL + `/valid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee/.well-known/openid-configuration\": x509: certificate signed by unknown authority" "issuer"="` + testIssuerURL + `/valid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee" "name"="test-name" "namespace"="test-namespace"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
-				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="authorization endpoint URL '' must have \"https\" scheme, not \"\"" "reason"="InvalidResponse" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="failed to perform OIDC discovery against \"` + testIssuerURL + `/valid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee\":\nGet \"` + testIssuerURL + `/valid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee/.well-known/openid-configuration\": x509: certificate signed by unknown authority" "reason"="Unreachable" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot fetch JWKS until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
-				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="authorization endpoint URL '' must have \"https\" scheme, not \"\"" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidResponse" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="failed to perform OIDC discovery against \"` + testIssuerURL + `/valid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee\":\nGet \"` + testIssuerURL + `/valid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee/.well-known/openid-configuration\": x509: certificate signed by unknown authority" "name"="test-name" "namespace"="test-namespace" "reason"="Unreachable" "type"="OIDCDiscoverySucceeded"`,
 			},
 			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
 			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Status: v1alpha1.OIDCIdentityProviderStatus{
-					Phase: "Error",
+					Phase:                "Error",
+					CertificateAuthority: wrongCAStatus,
 					Conditions: []v1alpha1.Condition{
 						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
 						{
 							Type:               "ClientCredentialsValid",
 							Status:             "True",
@@ -945,37 +1495,47 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 							Reason:             "Success",
 							Message:            "loaded client credentials",
 						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot fetch JWKS until OIDC discovery succeeds",
+						},
 						{
 							Type:               "OIDCDiscoverySucceeded",
 							Status:             "False",
 							LastTransitionTime: now,
-							Reason:             "InvalidResponse",
-							Message:            `authorization endpoint URL '' must have "https" scheme, not ""`,
+							Reason:             "Unreachable",
+							Message: `failed to perform OIDC discovery against "` + testIssuerURL + `/valid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee":
+Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee/.well-known/openid-configuration": x509: certificate signed by unknown authority`,
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
 						},
 					},
 				},
 			}},
 		},
 		{
-			name: "upstream with error becomes valid",
+			name: "issuer returns invalid authorize URL",
 			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
-				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "test-name", UID: testUID},
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Spec: v1alpha1.OIDCIdentityProviderSpec{
-					Issuer: testIssuerURL,
-					TLS:    &v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64},
+					Issuer: testIssuerURL + "/invalid",
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
 					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
-					AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{
-						AdditionalScopes:   append(testAdditionalScopes, "xyz", "openid"), // adds openid unnecessarily
-						AllowPasswordGrant: true,
-					},
-					Claims: v1alpha1.OIDCClaims{Groups: testGroupsClaim, Username: testUsernameClaim},
-				},
-				Status: v1alpha1.OIDCIdentityProviderStatus{
-					Phase: "Error",
-					Conditions: []v1alpha1.Condition{
-						{Type: "ClientCredentialsValid", Status: "False", LastTransitionTime: earlier, Reason: "SomeError1", Message: "some previous error 1"},
-						{Type: "OIDCDiscoverySucceeded", Status: "False", LastTransitionTime: earlier, Reason: "SomeError2", Message: "some previous error 2"},
-					},
 				},
 			}},
 			inputSecrets: []runtime.Object{&corev1.Secret{
@@ -983,54 +1543,73 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 				Type:       "secrets.pinniped.dev/oidc-client",
 				Data:       testValidSecretData,
 			}},
+			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
 			wantLogs: []string{
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
-				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="failed to parse authorization endpoint URL: parse \"%\": invalid URL escape \"%\"" "reason"="InvalidResponse" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot fetch JWKS until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="failed to parse authorization endpoint URL: parse \"%\": invalid URL escape \"%\"" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidResponse" "type"="OIDCDiscoverySucceeded"`,
 			},
-			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
-				{
-					Name:                     testName,
-					ClientID:                 testClientID,
-					AuthorizationURL:         *testIssuerAuthorizeURL,
-					RevocationURL:            testIssuerRevocationURL,
-					Scopes:                   append(testExpectedScopes, "xyz"), // includes openid only once
-					UsernameClaim:            testUsernameClaim,
-					GroupsClaim:              testGroupsClaim,
-					AllowPasswordGrant:       true,
-					AdditionalAuthcodeParams: map[string]string{},
-					ResourceUID:              testUID,
-				},
-			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
 			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
-				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, UID: testUID},
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Status: v1alpha1.OIDCIdentityProviderStatus{
-					Phase: "Ready",
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
 					Conditions: []v1alpha1.Condition{
 						happyAdditionalAuthorizeParametersValidCondition,
-						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "loaded client credentials"},
-						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "discovered issuer configuration"},
+						happyClaimsValidCondition,
+						{
+							Type:               "ClientCredentialsValid",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "loaded client credentials",
+						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot fetch JWKS until OIDC discovery succeeds",
+						},
+						{
+							Type:               "OIDCDiscoverySucceeded",
+							Status:             "False",
+							LastTransitionTime: now,
+							Reason:             "InvalidResponse",
+							Message:            `failed to parse authorization endpoint URL: parse "%": invalid URL escape "%"`,
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
+						},
 					},
 				},
 			}},
 		},
 		{
-			name: "existing valid upstream with default authorizationConfig",
+			name: "issuer returns invalid revocation URL",
 			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
-				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Spec: v1alpha1.OIDCIdentityProviderSpec{
-					Issuer: testIssuerURL,
-					TLS:    &v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64},
+					Issuer: testIssuerURL + "/invalid-revocation-url",
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
 					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
-					Claims: v1alpha1.OIDCClaims{Groups: testGroupsClaim, Username: testUsernameClaim},
-				},
-				Status: v1alpha1.OIDCIdentityProviderStatus{
-					Phase: "Ready",
-					Conditions: []v1alpha1.Condition{
-						happyAdditionalAuthorizeParametersValidConditionEarlier,
-						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials"},
-						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration"},
-					},
 				},
 			}},
 			inputSecrets: []runtime.Object{&corev1.Secret{
@@ -1038,54 +1617,73 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 				Type:       "secrets.pinniped.dev/oidc-client",
 				Data:       testValidSecretData,
 			}},
+			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
 			wantLogs: []string{
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
-				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="failed to parse revocation endpoint URL: parse \"%\": invalid URL escape \"%\"" "reason"="InvalidResponse" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot fetch JWKS until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="failed to parse revocation endpoint URL: parse \"%\": invalid URL escape \"%\"" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidResponse" "type"="OIDCDiscoverySucceeded"`,
 			},
-			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
-				{
-					Name:                     testName,
-					ClientID:                 testClientID,
-					AuthorizationURL:         *testIssuerAuthorizeURL,
-					RevocationURL:            testIssuerRevocationURL,
-					Scopes:                   testDefaultExpectedScopes,
-					UsernameClaim:            testUsernameClaim,
-					GroupsClaim:              testGroupsClaim,
-					AllowPasswordGrant:       false,
-					AdditionalAuthcodeParams: map[string]string{},
-					ResourceUID:              testUID,
-				},
-			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
 			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
-				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Status: v1alpha1.OIDCIdentityProviderStatus{
-					Phase: "Ready",
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
 					Conditions: []v1alpha1.Condition{
-						{Type: "AdditionalAuthorizeParametersValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "additionalAuthorizeParameters parameter names are allowed", ObservedGeneration: 1234},
-						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
-						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
+						{
+							Type:               "ClientCredentialsValid",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "loaded client credentials",
+						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot fetch JWKS until OIDC discovery succeeds",
+						},
+						{
+							Type:               "OIDCDiscoverySucceeded",
+							Status:             "False",
+							LastTransitionTime: now,
+							Reason:             "InvalidResponse",
+							Message:            `failed to parse revocation endpoint URL: parse "%": invalid URL escape "%"`,
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
+						},
 					},
 				},
 			}},
 		},
 		{
-			name: "existing valid upstream with no revocation endpoint in the discovery document",
+			name: "issuer returns insecure authorize URL",
 			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
-				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Spec: v1alpha1.OIDCIdentityProviderSpec{
-					Issuer: testIssuerURL + "/valid-without-revocation",
-					TLS:    &v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64},
+					Issuer: testIssuerURL + "/insecure",
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
 					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
-					Claims: v1alpha1.OIDCClaims{Groups: testGroupsClaim, Username: testUsernameClaim},
-				},
-				Status: v1alpha1.OIDCIdentityProviderStatus{
-					Phase: "Ready",
-					Conditions: []v1alpha1.Condition{
-						happyAdditionalAuthorizeParametersValidConditionEarlier,
-						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials"},
-						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration"},
-					},
 				},
 			}},
 			inputSecrets: []runtime.Object{&corev1.Secret{
@@ -1093,57 +1691,73 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 				Type:       "secrets.pinniped.dev/oidc-client",
 				Data:       testValidSecretData,
 			}},
+			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
 			wantLogs: []string{
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
-				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="authorization endpoint URL 'http://example.com/authorize' must have \"https\" scheme, not \"http\"" "reason"="InvalidResponse" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot fetch JWKS until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="authorization endpoint URL 'http://example.com/authorize' must have \"https\" scheme, not \"http\"" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidResponse" "type"="OIDCDiscoverySucceeded"`,
 			},
-			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
-				{
-					Name:                     testName,
-					ClientID:                 testClientID,
-					AuthorizationURL:         *testIssuerAuthorizeURL,
-					RevocationURL:            nil, // no revocation URL is set in the cached provider because none was returned by discovery
-					Scopes:                   testDefaultExpectedScopes,
-					UsernameClaim:            testUsernameClaim,
-					GroupsClaim:              testGroupsClaim,
-					AllowPasswordGrant:       false,
-					AdditionalAuthcodeParams: map[string]string{},
-					ResourceUID:              testUID,
-				},
-			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
 			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
-				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Status: v1alpha1.OIDCIdentityProviderStatus{
-					Phase: "Ready",
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
 					Conditions: []v1alpha1.Condition{
-						{Type: "AdditionalAuthorizeParametersValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "additionalAuthorizeParameters parameter names are allowed", ObservedGeneration: 1234},
-						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
-						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
+						{
+							Type:               "ClientCredentialsValid",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "loaded client credentials",
+						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot fetch JWKS until OIDC discovery succeeds",
+						},
+						{
+							Type:               "OIDCDiscoverySucceeded",
+							Status:             "False",
+							LastTransitionTime: now,
+							Reason:             "InvalidResponse",
+							Message:            `authorization endpoint URL 'http://example.com/authorize' must have "https" scheme, not "http"`,
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
+						},
 					},
 				},
 			}},
 		},
 		{
-			name: "existing valid upstream with additionalScopes set to override the default",
+			name: "issuer returns insecure revocation URL",
 			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
-				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Spec: v1alpha1.OIDCIdentityProviderSpec{
-					Issuer: testIssuerURL,
-					TLS:    &v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64},
+					Issuer: testIssuerURL + "/insecure-revocation-url",
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
 					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
-					Claims: v1alpha1.OIDCClaims{Groups: testGroupsClaim, Username: testUsernameClaim},
-					AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{
-						AdditionalScopes: testAdditionalScopes,
-					},
-				},
-				Status: v1alpha1.OIDCIdentityProviderStatus{
-					Phase: "Ready",
-					Conditions: []v1alpha1.Condition{
-						happyAdditionalAuthorizeParametersValidConditionEarlier,
-						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials"},
-						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration"},
-					},
 				},
 			}},
 			inputSecrets: []runtime.Object{&corev1.Secret{
@@ -1151,59 +1765,73 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 				Type:       "secrets.pinniped.dev/oidc-client",
 				Data:       testValidSecretData,
 			}},
+			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
 			wantLogs: []string{
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
-				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="revocation endpoint URL 'http://example.com/revoke' must have \"https\" scheme, not \"http\"" "reason"="InvalidResponse" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot fetch JWKS until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="revocation endpoint URL 'http://example.com/revoke' must have \"https\" scheme, not \"http\"" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidResponse" "type"="OIDCDiscoverySucceeded"`,
 			},
-			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
-				{
-					Name:                     testName,
-					ClientID:                 testClientID,
-					AuthorizationURL:         *testIssuerAuthorizeURL,
-					RevocationURL:            testIssuerRevocationURL,
-					Scopes:                   testExpectedScopes,
-					UsernameClaim:            testUsernameClaim,
-					GroupsClaim:              testGroupsClaim,
-					AllowPasswordGrant:       false,
-					AdditionalAuthcodeParams: map[string]string{},
-					ResourceUID:              testUID,
-				},
-			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
 			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
-				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Status: v1alpha1.OIDCIdentityProviderStatus{
-					Phase: "Ready",
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
 					Conditions: []v1alpha1.Condition{
-						{Type: "AdditionalAuthorizeParametersValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "additionalAuthorizeParameters parameter names are allowed", ObservedGeneration: 1234},
-						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
-						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
+						{
+							Type:               "ClientCredentialsValid",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "loaded client credentials",
+						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot fetch JWKS until OIDC discovery succeeds",
+						},
+						{
+							Type:               "OIDCDiscoverySucceeded",
+							Status:             "False",
+							LastTransitionTime: now,
+							Reason:             "InvalidResponse",
+							Message:            `revocation endpoint URL 'http://example.com/revoke' must have "https" scheme, not "http"`,
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
+						},
 					},
 				},
 			}},
 		},
 		{
-			name: "existing valid upstream with trailing slash and more optional settings",
+			name: "issuer returns insecure token URL",
 			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
-				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Spec: v1alpha1.OIDCIdentityProviderSpec{
-					Issuer: testIssuerURL + "/ends-with-slash/",
-					TLS:    &v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64},
+					Issuer: testIssuerURL + "/insecure-token-url",
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
 					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
-					AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{
-						AdditionalScopes:              testAdditionalScopes,
-						AdditionalAuthorizeParameters: testAdditionalParams,
-						AllowPasswordGrant:            true,
-					},
-					Claims: v1alpha1.OIDCClaims{Groups: testGroupsClaim, Username: testUsernameClaim},
-				},
-				Status: v1alpha1.OIDCIdentityProviderStatus{
-					Phase: "Ready",
-					Conditions: []v1alpha1.Condition{
-						happyAdditionalAuthorizeParametersValidConditionEarlier,
-						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials"},
-						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration"},
-					},
 				},
 			}},
 			inputSecrets: []runtime.Object{&corev1.Secret{
@@ -1211,59 +1839,73 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 				Type:       "secrets.pinniped.dev/oidc-client",
 				Data:       testValidSecretData,
 			}},
+			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
 			wantLogs: []string{
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
-				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="token endpoint URL 'http://example.com/token' must have \"https\" scheme, not \"http\"" "reason"="InvalidResponse" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot fetch JWKS until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="token endpoint URL 'http://example.com/token' must have \"https\" scheme, not \"http\"" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidResponse" "type"="OIDCDiscoverySucceeded"`,
 			},
-			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
-				{
-					Name:                     testName,
-					ClientID:                 testClientID,
-					AuthorizationURL:         *testIssuerAuthorizeURL,
-					RevocationURL:            testIssuerRevocationURL,
-					Scopes:                   testExpectedScopes, // does not include the default scopes
-					UsernameClaim:            testUsernameClaim,
-					GroupsClaim:              testGroupsClaim,
-					AllowPasswordGrant:       true,
-					AdditionalAuthcodeParams: testExpectedAdditionalParams,
-					ResourceUID:              testUID,
-				},
-			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
 			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
-				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Status: v1alpha1.OIDCIdentityProviderStatus{
-					Phase: "Ready",
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
 					Conditions: []v1alpha1.Condition{
-						{Type: "AdditionalAuthorizeParametersValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "additionalAuthorizeParameters parameter names are allowed", ObservedGeneration: 1234},
-						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
-						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
+						{
+							Type:               "ClientCredentialsValid",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "loaded client credentials",
+						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot fetch JWKS until OIDC discovery succeeds",
+						},
+						{
+							Type:               "OIDCDiscoverySucceeded",
+							Status:             "False",
+							LastTransitionTime: now,
+							Reason:             "InvalidResponse",
+							Message:            `token endpoint URL 'http://example.com/token' must have "https" scheme, not "http"`,
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
+						},
 					},
 				},
 			}},
 		},
 		{
-			name: "has disallowed additionalAuthorizeParams keys",
+			name: "issuer returns no token URL",
 			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
-				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Spec: v1alpha1.OIDCIdentityProviderSpec{
-					Issuer: testIssuerURL,
-					TLS:    &v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64},
+					Issuer: testIssuerURL + "/missing-token-url",
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
 					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
-					AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{
-						AdditionalAuthorizeParameters: []v1alpha1.Parameter{
-							{Name: "response_type", Value: "foo"},
-							{Name: "scope", Value: "foo"},
-							{Name: "client_id", Value: "foo"},
-							{Name: "state", Value: "foo"},
-							{Name: "nonce", Value: "foo"},
-							{Name: "code_challenge", Value: "foo"},
-							{Name: "code_challenge_method", Value: "foo"},
-							{Name: "redirect_uri", Value: "foo"},
-							{Name: "hd", Value: "foo"},
-							{Name: "this_one_is_allowed", Value: "foo"},
-						},
-					},
 				},
 			}},
 			inputSecrets: []runtime.Object{&corev1.Secret{
@@ -1274,32 +1916,69 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
 			wantLogs: []string{
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
-				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
-				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="the following additionalAuthorizeParameters are not allowed: response_type,scope,client_id,state,nonce,code_challenge,code_challenge_method,redirect_uri,hd" "reason"="DisallowedParameterName" "status"="False" "type"="AdditionalAuthorizeParametersValid"`,
-				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="the following additionalAuthorizeParameters are not allowed: response_type,scope,client_id,state,nonce,code_challenge,code_challenge_method,redirect_uri,hd" "name"="test-name" "namespace"="test-namespace" "reason"="DisallowedParameterName" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="token endpoint URL '' must have \"https\" scheme, not \"\"" "reason"="InvalidResponse" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot fetch JWKS until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="token endpoint URL '' must have \"https\" scheme, not \"\"" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidResponse" "type"="OIDCDiscoverySucceeded"`,
 			},
 			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
 			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
-				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Status: v1alpha1.OIDCIdentityProviderStatus{
-					Phase: "Error",
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
 					Conditions: []v1alpha1.Condition{
-						{Type: "AdditionalAuthorizeParametersValid", Status: "False", LastTransitionTime: now, Reason: "DisallowedParameterName",
-							Message: "the following additionalAuthorizeParameters are not allowed: " +
-								"response_type,scope,client_id,state,nonce,code_challenge,code_challenge_method,redirect_uri,hd", ObservedGeneration: 1234},
-						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
-						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
+						{
+							Type:               "ClientCredentialsValid",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "loaded client credentials",
+						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot fetch JWKS until OIDC discovery succeeds",
+						},
+						{
+							Type:               "OIDCDiscoverySucceeded",
+							Status:             "False",
+							LastTransitionTime: now,
+							Reason:             "InvalidResponse",
+							Message:            `token endpoint URL '' must have "https" scheme, not ""`,
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
+						},
 					},
 				},
 			}},
 		},
 		{
-			name: "issuer is invalid URL, missing trailing slash when the OIDC discovery endpoint returns the URL with a trailing slash",
+			name: "issuer returns no auth URL",
 			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Spec: v1alpha1.OIDCIdentityProviderSpec{
-					Issuer: testIssuerURL + "/ends-with-slash", // this does not end with slash when it should, thus this is an error case
-					TLS:    &v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64},
+					Issuer: testIssuerURL + "/missing-auth-url",
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
 					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
 				},
 			}},
@@ -1310,19 +1989,102 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 			}},
 			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
 			wantLogs: []string{
-				`oidc-upstream-observer "msg"="failed to perform OIDC discovery" "error"="oidc: issuer did not match the issuer returned by provider, expected \"` + testIssuerURL + `/ends-with-slash\" got \"` + testIssuerURL + `/ends-with-slash/\"" "issuer"="` + testIssuerURL + `/ends-with-slash" "name"="test-name" "namespace"="test-namespace"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
-				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="failed to perform OIDC discovery against \"` + testIssuerURL + `/ends-with-slash\":\noidc: issuer did not match the issuer returned by provider, expected \"` + testIssuerURL + `/ends-with-slash\" got \"` + testIssuerURL + `/ends-with-slash/\"" "reason"="Unreachable" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="authorization endpoint URL '' must have \"https\" scheme, not \"\"" "reason"="InvalidResponse" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot fetch JWKS until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
-				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="failed to perform OIDC discovery against \"` + testIssuerURL + `/ends-with-slash\":\noidc: issuer did not match the issuer returned by provider, expected \"` + testIssuerURL + `/ends-with-slash\" got \"` + testIssuerURL + `/ends-with-slash/\"" "name"="test-name" "namespace"="test-namespace" "reason"="Unreachable" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="authorization endpoint URL '' must have \"https\" scheme, not \"\"" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidResponse" "type"="OIDCDiscoverySucceeded"`,
 			},
 			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
 			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Status: v1alpha1.OIDCIdentityProviderStatus{
-					Phase: "Error",
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
+						{
+							Type:               "ClientCredentialsValid",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "loaded client credentials",
+						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot fetch JWKS until OIDC discovery succeeds",
+						},
+						{
+							Type:               "OIDCDiscoverySucceeded",
+							Status:             "False",
+							LastTransitionTime: now,
+							Reason:             "InvalidResponse",
+							Message:            `authorization endpoint URL '' must have "https" scheme, not ""`,
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
+						},
+					},
+				},
+			}},
+		},
+		{
+			name: "issuer returns no auth URL after a previous discovery succeeded keeps the previous discovered config hash",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL + "/missing-auth-url",
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+				},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					DiscoveredConfigHash: "some-previous-hash",
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="authorization endpoint URL '' must have \"https\" scheme, not \"\"" "reason"="InvalidResponse" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot fetch JWKS until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="authorization endpoint URL '' must have \"https\" scheme, not \"\"" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidResponse" "type"="OIDCDiscoverySucceeded"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: "some-previous-hash",
 					Conditions: []v1alpha1.Condition{
 						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
 						{
 							Type:               "ClientCredentialsValid",
 							Status:             "True",
@@ -1330,25 +2092,123 @@ Get "` + testIssuerURL + `/valid-url-that-is-really-really-long-nananananananana
 							Reason:             "Success",
 							Message:            "loaded client credentials",
 						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot fetch JWKS until OIDC discovery succeeds",
+						},
 						{
 							Type:               "OIDCDiscoverySucceeded",
 							Status:             "False",
 							LastTransitionTime: now,
+							Reason:             "InvalidResponse",
+							Message:            `authorization endpoint URL '' must have "https" scheme, not ""`,
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
+						},
+					},
+				},
+			}},
+		},
+		{
+			name: "issuer JWKS endpoint is not found",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL + "/jwks-not-found",
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="failed to fetch JWKS from \"` + testIssuerURL + `/jwks-not-found/jwks.json\":\nunexpected response status \"404 Not Found\"" "reason"="Unreachable" "status"="False" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="failed to fetch JWKS from \"` + testIssuerURL + `/jwks-not-found/jwks.json\":\nunexpected response status \"404 Not Found\"" "name"="test-name" "namespace"="test-namespace" "reason"="Unreachable" "type"="JWKSFetchSucceeded"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL + "/jwks-not-found"),
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
+						{
+							Type:               "ClientCredentialsValid",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "loaded client credentials",
+						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "False",
+							LastTransitionTime: now,
 							Reason:             "Unreachable",
-							Message: `failed to perform OIDC discovery against "` + testIssuerURL + `/ends-with-slash":
-oidc: issuer did not match the issuer returned by provider, expected "` + testIssuerURL + `/ends-with-slash" got "` + testIssuerURL + `/ends-with-slash/"`,
+							Message:            `failed to fetch JWKS from "` + testIssuerURL + `/jwks-not-found/jwks.json":` + "\n" + `unexpected response status "404 Not Found"`,
+						},
+						{
+							Type:               "OIDCDiscoverySucceeded",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "discovered issuer configuration",
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "OIDC provider does not advertise its supported grant types",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "OIDC provider does not advertise its supported scopes",
 						},
 					},
 				},
 			}},
 		},
 		{
-			name: "issuer is invalid URL, extra trailing slash",
+			name: "JWKS CA bundle is invalid base64",
 			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
 				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
 				Spec: v1alpha1.OIDCIdentityProviderSpec{
-					Issuer: testIssuerURL + "/",
-					TLS:    &v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64},
+					Issuer: testIssuerURL,
+					TLS: &v1alpha1.OIDCTLSSpec{
+						TLSSpec:                      v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64},
+						JWKSCertificateAuthorityData: "invalid-base64",
+					},
 					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
 				},
 			}},
@@ -1359,119 +2219,3618 @@ oidc: issuer did not match the issuer returned by provider, expected "` + testIs
 			}},
 			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
 			wantLogs: []string{
-				`oidc-upstream-observer "msg"="failed to perform OIDC discovery" "error"="oidc: issuer did not match the issuer returned by provider, expected \"` + testIssuerURL + `/\" got \"` + testIssuerURL + `\"" "issuer"="` + testIssuerURL + `/" "name"="test-name" "namespace"="test-namespace"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
-				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="failed to perform OIDC discovery against \"` + testIssuerURL + `/\":\noidc: issuer did not match the issuer returned by provider, expected \"` + testIssuerURL + `/\" got \"` + testIssuerURL + `\"" "reason"="Unreachable" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="spec.tls.jwksCertificateAuthorityData is invalid: illegal base64 data at input byte 7" "reason"="InvalidTLSConfig" "status"="False" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
 				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
-				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="failed to perform OIDC discovery against \"` + testIssuerURL + `/\":\noidc: issuer did not match the issuer returned by provider, expected \"` + testIssuerURL + `/\" got \"` + testIssuerURL + `\"" "name"="test-name" "namespace"="test-namespace" "reason"="Unreachable" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="spec.tls.jwksCertificateAuthorityData is invalid: illegal base64 data at input byte 7" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidTLSConfig" "type"="JWKSFetchSucceeded"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
+						{
+							Type:               "ClientCredentialsValid",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "loaded client credentials",
+						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "False",
+							LastTransitionTime: now,
+							Reason:             "InvalidTLSConfig",
+							Message:            "spec.tls.jwksCertificateAuthorityData is invalid: illegal base64 data at input byte 7",
+						},
+						{
+							Type:               "OIDCDiscoverySucceeded",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "discovered issuer configuration",
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "OIDC provider does not advertise its supported grant types",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "OIDC provider does not advertise its supported scopes",
+						},
+					},
+				},
+			}},
+		},
+		{
+			name: "JWKS CA bundle is invalid base64 while OIDC discovery fails",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL,
+					TLS: &v1alpha1.OIDCTLSSpec{
+						TLSSpec:                      v1alpha1.TLSSpec{CertificateAuthorityData: "invalid-base64"},
+						JWKSCertificateAuthorityData: "invalid-base64",
+					},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="spec.certificateAuthorityData is invalid: illegal base64 data at input byte 7" "reason"="InvalidTLSConfig" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="spec.tls.jwksCertificateAuthorityData is invalid: illegal base64 data at input byte 7" "reason"="InvalidTLSConfig" "status"="False" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="spec.certificateAuthorityData is invalid: illegal base64 data at input byte 7" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidTLSConfig" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="spec.tls.jwksCertificateAuthorityData is invalid: illegal base64 data at input byte 7" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidTLSConfig" "type"="JWKSFetchSucceeded"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase: "Error",
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
+						{
+							Type:               "ClientCredentialsValid",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "loaded client credentials",
+						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "False",
+							LastTransitionTime: now,
+							Reason:             "InvalidTLSConfig",
+							Message:            "spec.tls.jwksCertificateAuthorityData is invalid: illegal base64 data at input byte 7",
+						},
+						{
+							Type:               "OIDCDiscoverySucceeded",
+							Status:             "False",
+							LastTransitionTime: now,
+							Reason:             "InvalidTLSConfig",
+							Message:            "spec.certificateAuthorityData is invalid: illegal base64 data at input byte 7",
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
+						},
+					},
+				},
+			}},
+		},
+		{
+			name: "JWKS CA bundle does not trust the JWKS endpoint",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL,
+					TLS: &v1alpha1.OIDCTLSSpec{
+						TLSSpec:                      v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64},
+						JWKSCertificateAuthorityData: wrongCABase64,
+					},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="failed to fetch JWKS from \"` + testIssuerURL + `/jwks.json\":\nGet \"` + testIssuerURL + `/jwks.json\": tls: failed to verify certificate: x509: certificate signed by unknown authority" "reason"="Unreachable" "status"="False" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="failed to fetch JWKS from \"` + testIssuerURL + `/jwks.json\":\nGet \"` + testIssuerURL + `/jwks.json\": tls: failed to verify certificate: x509: certificate signed by unknown authority" "name"="test-name" "namespace"="test-namespace" "reason"="Unreachable" "type"="JWKSFetchSucceeded"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
+						{
+							Type:               "ClientCredentialsValid",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "loaded client credentials",
+						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "False",
+							LastTransitionTime: now,
+							Reason:             "Unreachable",
+							Message:            `failed to fetch JWKS from "` + testIssuerURL + `/jwks.json":` + "\n" + `Get "` + testIssuerURL + `/jwks.json": tls: failed to verify certificate: x509: certificate signed by unknown authority`,
+						},
+						{
+							Type:               "OIDCDiscoverySucceeded",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "discovered issuer configuration",
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "OIDC provider does not advertise its supported grant types",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "OIDC provider does not advertise its supported scopes",
+						},
+					},
+				},
+			}},
+		},
+		{
+			name: "issuer JWKS does not contain any usable signing keys",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL + "/jwks-without-signing-keys",
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="JWKS from \"` + testIssuerURL + `/encryption-jwks.json\" does not contain any usable signing keys" "reason"="InvalidResponse" "status"="False" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="JWKS from \"` + testIssuerURL + `/encryption-jwks.json\" does not contain any usable signing keys" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidResponse" "type"="JWKSFetchSucceeded"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL + "/jwks-without-signing-keys"),
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
+						{
+							Type:               "ClientCredentialsValid",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "loaded client credentials",
+						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "False",
+							LastTransitionTime: now,
+							Reason:             "InvalidResponse",
+							Message:            `JWKS from "` + testIssuerURL + `/encryption-jwks.json" does not contain any usable signing keys`,
+						},
+						{
+							Type:               "OIDCDiscoverySucceeded",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "discovered issuer configuration",
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "OIDC provider does not advertise its supported grant types",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "OIDC provider does not advertise its supported scopes",
+						},
+					},
+				},
+			}},
+		},
+		{
+			name: "requested scopes are not advertised by the issuer",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer:              testIssuerURL + "/scopes-supported",
+					TLS:                 &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client:              v1alpha1.OIDCClient{SecretName: testSecretName},
+					AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="the following requested scopes are not advertised by the OIDC provider: offline_access" "reason"="UnadvertisedScopes" "status"="False" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise support for refresh tokens, so users may need to log in again whenever their session expires: scopes_supported does not include \"offline_access\"" "reason"="RefreshNotAdvertised" "status"="Unknown" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="the following requested scopes are not advertised by the OIDC provider: offline_access" "name"="test-name" "namespace"="test-namespace" "reason"="UnadvertisedScopes" "type"="ScopesSupported"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL + "/scopes-supported"),
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidCondition,
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid"},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "loaded client credentials"},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "fetched JWKS with usable signing keys"},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "discovered issuer configuration"},
+						{Type: "RefreshTokenSupported", Status: "Unknown", LastTransitionTime: now, Reason: "RefreshNotAdvertised", Message: `OIDC provider does not advertise support for refresh tokens, so users may need to log in again whenever their session expires: scopes_supported does not include "offline_access"`},
+						{Type: "ScopesSupported", Status: "False", LastTransitionTime: now, Reason: "UnadvertisedScopes", Message: "the following requested scopes are not advertised by the OIDC provider: offline_access"},
+					},
+				},
+			}},
+		},
+		{
+			name: "requested scopes are not advertised by the issuer, but are allowed by allowUnadvertisedScopes",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer:              testIssuerURL + "/scopes-supported",
+					TLS:                 &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client:              v1alpha1.OIDCClient{SecretName: testSecretName},
+					AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{AllowUnadvertisedScopes: true},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="the following requested scopes are not advertised by the OIDC provider, but are allowed by allowUnadvertisedScopes: offline_access" "reason"="UnadvertisedScopes" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise support for refresh tokens, so users may need to log in again whenever their session expires: scopes_supported does not include \"offline_access\"" "reason"="RefreshNotAdvertised" "status"="Unknown" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
+				{
+					Name:                     testName,
+					ClientID:                 testClientID,
+					AuthorizationURL:         *testIssuerAuthorizeURL,
+					Scopes:                   testDefaultExpectedScopes,
+					AdditionalAuthcodeParams: map[string]string{},
+					ResourceUID:              testUID,
+				},
+			},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Ready",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL + "/scopes-supported"),
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidCondition,
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid"},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "loaded client credentials"},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "fetched JWKS with usable signing keys"},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "discovered issuer configuration"},
+						{Type: "RefreshTokenSupported", Status: "Unknown", LastTransitionTime: now, Reason: "RefreshNotAdvertised", Message: `OIDC provider does not advertise support for refresh tokens, so users may need to log in again whenever their session expires: scopes_supported does not include "offline_access"`},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: now, Reason: "UnadvertisedScopes", Message: "the following requested scopes are not advertised by the OIDC provider, but are allowed by allowUnadvertisedScopes: offline_access"},
+					},
+				},
+			}},
+		},
+		{
+			name: "all requested scopes are advertised by the issuer",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer:              testIssuerURL + "/scopes-supported",
+					TLS:                 &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client:              v1alpha1.OIDCClient{SecretName: testSecretName},
+					AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{AdditionalScopes: []string{"email"}},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="all requested scopes are advertised by the OIDC provider" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
+				{
+					Name:                     testName,
+					ClientID:                 testClientID,
+					AuthorizationURL:         *testIssuerAuthorizeURL,
+					Scopes:                   []string{"openid", "email"},
+					AdditionalAuthcodeParams: map[string]string{},
+					ResourceUID:              testUID,
+				},
+			},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Ready",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL + "/scopes-supported"),
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidCondition,
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid"},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "loaded client credentials"},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "fetched JWKS with usable signing keys"},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "discovered issuer configuration"},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types"},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "all requested scopes are advertised by the OIDC provider"},
+					},
+				},
+			}},
+		},
+		{
+			name: "the issuer advertises support for refresh tokens",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL + "/refresh-supported",
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="all requested scopes are advertised by the OIDC provider" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider advertises support for refresh tokens" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
+				{
+					Name:                     testName,
+					ClientID:                 testClientID,
+					AuthorizationURL:         *testIssuerAuthorizeURL,
+					Scopes:                   testDefaultExpectedScopes,
+					AdditionalAuthcodeParams: map[string]string{},
+					ResourceUID:              testUID,
+				},
+			},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Ready",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL + "/refresh-supported"),
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidCondition,
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid"},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "loaded client credentials"},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "fetched JWKS with usable signing keys"},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "discovered issuer configuration"},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider advertises support for refresh tokens"},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "all requested scopes are advertised by the OIDC provider"},
+					},
+				},
+			}},
+		},
+		{
+			name: "the issuer does not advertise support for refresh tokens, which is not a failing condition",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer:              testIssuerURL + "/refresh-not-supported",
+					TLS:                 &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client:              v1alpha1.OIDCClient{SecretName: testSecretName},
+					AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{AllowUnadvertisedScopes: true},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="the following requested scopes are not advertised by the OIDC provider, but are allowed by allowUnadvertisedScopes: offline_access" "reason"="UnadvertisedScopes" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise support for refresh tokens, so users may need to log in again whenever their session expires: grant_types_supported does not include \"refresh_token\"; scopes_supported does not include \"offline_access\"" "reason"="RefreshNotAdvertised" "status"="Unknown" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
+				{
+					Name:                     testName,
+					ClientID:                 testClientID,
+					AuthorizationURL:         *testIssuerAuthorizeURL,
+					Scopes:                   testDefaultExpectedScopes,
+					AdditionalAuthcodeParams: map[string]string{},
+					ResourceUID:              testUID,
+				},
+			},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Ready",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL + "/refresh-not-supported"),
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidCondition,
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid"},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "loaded client credentials"},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "fetched JWKS with usable signing keys"},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "discovered issuer configuration"},
+						{Type: "RefreshTokenSupported", Status: "Unknown", LastTransitionTime: now, Reason: "RefreshNotAdvertised",
+							Message: `OIDC provider does not advertise support for refresh tokens, so users may need to log in again whenever their session expires: grant_types_supported does not include "refresh_token"; scopes_supported does not include "offline_access"`},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: now, Reason: "UnadvertisedScopes",
+							Message: "the following requested scopes are not advertised by the OIDC provider, but are allowed by allowUnadvertisedScopes: offline_access"},
+					},
+				},
+			}},
+		},
+		{
+			name: "the issuer does not advertise support for the password grant, which fails the condition but keeps the password grant enabled",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer:              testIssuerURL + "/refresh-supported",
+					TLS:                 &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client:              v1alpha1.OIDCClient{SecretName: testSecretName},
+					AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{AllowPasswordGrant: true},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="all requested scopes are advertised by the OIDC provider" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider advertises support for refresh tokens" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="allowPasswordGrant is true but the OIDC provider does not advertise support for the password grant (grant_types_supported does not include \"password\"), so password logins may be rejected by the OIDC provider" "reason"="PasswordGrantNotAdvertised" "status"="False" "type"="PasswordGrantSupported"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="allowPasswordGrant is true but the OIDC provider does not advertise support for the password grant (grant_types_supported does not include \"password\"), so password logins may be rejected by the OIDC provider" "name"="test-name" "namespace"="test-namespace" "reason"="PasswordGrantNotAdvertised" "type"="PasswordGrantSupported"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
+				{
+					Name:                     testName,
+					ClientID:                 testClientID,
+					AuthorizationURL:         *testIssuerAuthorizeURL,
+					Scopes:                   testDefaultExpectedScopes,
+					AllowPasswordGrant:       true,
+					AdditionalAuthcodeParams: map[string]string{},
+					ResourceUID:              testUID,
+				},
+			},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL + "/refresh-supported"),
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidCondition,
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid"},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "loaded client credentials"},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "fetched JWKS with usable signing keys"},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "discovered issuer configuration"},
+						{Type: "PasswordGrantSupported", Status: "False", LastTransitionTime: now, Reason: "PasswordGrantNotAdvertised",
+							Message: `allowPasswordGrant is true but the OIDC provider does not advertise support for the password grant (grant_types_supported does not include "password"), so password logins may be rejected by the OIDC provider`},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider advertises support for refresh tokens"},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "all requested scopes are advertised by the OIDC provider"},
+					},
+				},
+			}},
+		},
+		{
+			name: "upstream with error becomes valid",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "test-name", UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL,
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{
+						AdditionalScopes:   append(testAdditionalScopes, "xyz", "openid"), // adds openid unnecessarily
+						AllowPasswordGrant: true,
+					},
+					Claims: v1alpha1.OIDCClaims{Groups: testGroupsClaim, Username: testUsernameClaim},
+				},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase: "Error",
+					Conditions: []v1alpha1.Condition{
+						{Type: "ClientCredentialsValid", Status: "False", LastTransitionTime: earlier, Reason: "SomeError1", Message: "some previous error 1"},
+						{Type: "OIDCDiscoverySucceeded", Status: "False", LastTransitionTime: earlier, Reason: "SomeError2", Message: "some previous error 2"},
+					},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="PasswordGrantSupported"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
+				{
+					Name:                     testName,
+					ClientID:                 testClientID,
+					AuthorizationURL:         *testIssuerAuthorizeURL,
+					RevocationURL:            testIssuerRevocationURL,
+					Scopes:                   append(testExpectedScopes, "xyz"), // includes openid only once
+					UsernameClaim:            testUsernameClaim,
+					GroupsClaim:              testGroupsClaim,
+					AllowPasswordGrant:       true,
+					AdditionalAuthcodeParams: map[string]string{},
+					ResourceUID:              testUID,
+				},
+			},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Ready",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidCondition,
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid"},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "loaded client credentials"},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "fetched JWKS with usable signing keys"},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "discovered issuer configuration"},
+						{Type: "PasswordGrantSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types"},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types"},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes"},
+					},
+				},
+			}},
+		},
+		{
+			name: "upstream with replaced scopes",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "test-name", UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL,
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{
+						AdditionalScopes:   testAdditionalScopes, // ignored because the scopes are replaced
+						ReplaceScopes:      []string{"xyz", "groups"},
+						AllowPasswordGrant: true,
+					},
+					Claims: v1alpha1.OIDCClaims{Groups: testGroupsClaim, Username: testUsernameClaim},
+				},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase: "Error",
+					Conditions: []v1alpha1.Condition{
+						{Type: "ClientCredentialsValid", Status: "False", LastTransitionTime: earlier, Reason: "SomeError1", Message: "some previous error 1"},
+						{Type: "OIDCDiscoverySucceeded", Status: "False", LastTransitionTime: earlier, Reason: "SomeError2", Message: "some previous error 2"},
+					},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="PasswordGrantSupported"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
+				{
+					Name:                     testName,
+					ClientID:                 testClientID,
+					AuthorizationURL:         *testIssuerAuthorizeURL,
+					RevocationURL:            testIssuerRevocationURL,
+					Scopes:                   []string{"openid", "xyz", "groups"}, // includes openid first because it is always required
+					UsernameClaim:            testUsernameClaim,
+					GroupsClaim:              testGroupsClaim,
+					AllowPasswordGrant:       true,
+					AdditionalAuthcodeParams: map[string]string{},
+					ResourceUID:              testUID,
+				},
+			},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Ready",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidCondition,
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid"},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "loaded client credentials"},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "fetched JWKS with usable signing keys"},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "discovered issuer configuration"},
+						{Type: "PasswordGrantSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types"},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types"},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes"},
+					},
+				},
+			}},
+		},
+		{
+			name: "upstream with a client secret which expires soon",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "test-name", UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL,
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					Claims: v1alpha1.OIDCClaims{Groups: testGroupsClaim, Username: testUsernameClaim},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   testNamespace,
+					Name:        testSecretName,
+					Annotations: map[string]string{"secrets.pinniped.dev/client-secret-expires-at": now.Add(24 * time.Hour).Format(time.RFC3339)},
+				},
+				Type: "secrets.pinniped.dev/oidc-client",
+				Data: testValidSecretData,
+			}},
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="client secret expires at ` + now.Add(24*time.Hour).Format(time.RFC3339) + `, so it should be rotated soon" "reason"="ClientSecretExpiring" "status"="Unknown" "type"="ClientSecretNotExpiring"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
+				{
+					Name:                     testName,
+					ClientID:                 testClientID,
+					AuthorizationURL:         *testIssuerAuthorizeURL,
+					RevocationURL:            testIssuerRevocationURL,
+					Scopes:                   testDefaultExpectedScopes,
+					UsernameClaim:            testUsernameClaim,
+					GroupsClaim:              testGroupsClaim,
+					AdditionalAuthcodeParams: map[string]string{},
+					ResourceUID:              testUID,
+				},
+			},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Ready",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidCondition,
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid"},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "loaded client credentials"},
+						{Type: "ClientSecretNotExpiring", Status: "Unknown", LastTransitionTime: now, Reason: "ClientSecretExpiring", Message: "client secret expires at " + now.Add(24*time.Hour).Format(time.RFC3339) + ", so it should be rotated soon"},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "fetched JWKS with usable signing keys"},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "discovered issuer configuration"},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types"},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes"},
+					},
+				},
+			}},
+		},
+		{
+			name: "existing valid upstream with default authorizationConfig",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL,
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					Claims: v1alpha1.OIDCClaims{Groups: testGroupsClaim, Username: testUsernameClaim},
+				},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase: "Ready",
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidConditionEarlier,
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials"},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys"},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration"},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types"},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes"},
+					},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
+				{
+					Name:                     testName,
+					ClientID:                 testClientID,
+					AuthorizationURL:         *testIssuerAuthorizeURL,
+					RevocationURL:            testIssuerRevocationURL,
+					Scopes:                   testDefaultExpectedScopes,
+					UsernameClaim:            testUsernameClaim,
+					GroupsClaim:              testGroupsClaim,
+					AllowPasswordGrant:       false,
+					AdditionalAuthcodeParams: map[string]string{},
+					ResourceUID:              testUID,
+				},
+			},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Ready",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						{Type: "AdditionalAuthorizeParametersValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "additionalAuthorizeParameters parameter names are allowed", ObservedGeneration: 1234},
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid", ObservedGeneration: 1234},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys", ObservedGeneration: 1234},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types", ObservedGeneration: 1234},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes", ObservedGeneration: 1234},
+					},
+				},
+			}},
+		},
+		{
+			name: "existing valid upstream with a separate JWKS CA bundle",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL,
+					TLS: &v1alpha1.OIDCTLSSpec{
+						TLSSpec:                      v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64},
+						JWKSCertificateAuthorityData: testIssuerCABase64,
+					},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					Claims: v1alpha1.OIDCClaims{Groups: testGroupsClaim, Username: testUsernameClaim},
+				},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase: "Ready",
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidConditionEarlier,
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials"},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys"},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration"},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types"},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes"},
+					},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
+				{
+					Name:                     testName,
+					ClientID:                 testClientID,
+					AuthorizationURL:         *testIssuerAuthorizeURL,
+					RevocationURL:            testIssuerRevocationURL,
+					Scopes:                   testDefaultExpectedScopes,
+					UsernameClaim:            testUsernameClaim,
+					GroupsClaim:              testGroupsClaim,
+					AllowPasswordGrant:       false,
+					AdditionalAuthcodeParams: map[string]string{},
+					ResourceUID:              testUID,
+				},
+			},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Ready",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						{Type: "AdditionalAuthorizeParametersValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "additionalAuthorizeParameters parameter names are allowed", ObservedGeneration: 1234},
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid", ObservedGeneration: 1234},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys", ObservedGeneration: 1234},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types", ObservedGeneration: 1234},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes", ObservedGeneration: 1234},
+					},
+				},
+			}},
+		},
+		{
+			name: "existing valid upstream with a CA bundle which has PEM blocks that are not certificates",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL,
+					TLS: &v1alpha1.OIDCTLSSpec{
+						TLSSpec: v1alpha1.TLSSpec{
+							CertificateAuthorityData: base64.StdEncoding.EncodeToString(bytes.Join([][]byte{
+								pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("not-a-certificate")}),
+								[]byte(testIssuerCA),
+								pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("not-a-certificate")}),
+							}, nil)),
+						},
+					},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					Claims: v1alpha1.OIDCClaims{Groups: testGroupsClaim, Username: testUsernameClaim},
+				},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase: "Ready",
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidConditionEarlier,
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials"},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys"},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration"},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types"},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes"},
+					},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration (spec.certificateAuthorityData: loaded 1 of 3 PEM blocks, skipped PEM blocks which are not valid certificates at positions 1, 3)" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
+				{
+					Name:                     testName,
+					ClientID:                 testClientID,
+					AuthorizationURL:         *testIssuerAuthorizeURL,
+					RevocationURL:            testIssuerRevocationURL,
+					Scopes:                   testDefaultExpectedScopes,
+					UsernameClaim:            testUsernameClaim,
+					GroupsClaim:              testGroupsClaim,
+					AllowPasswordGrant:       false,
+					AdditionalAuthcodeParams: map[string]string{},
+					ResourceUID:              testUID,
+				},
+			},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Ready",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						{Type: "AdditionalAuthorizeParametersValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "additionalAuthorizeParameters parameter names are allowed", ObservedGeneration: 1234},
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid", ObservedGeneration: 1234},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys", ObservedGeneration: 1234},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", ObservedGeneration: 1234,
+							Message: "discovered issuer configuration (spec.certificateAuthorityData: loaded 1 of 3 PEM blocks, skipped PEM blocks which are not valid certificates at positions 1, 3)"},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types", ObservedGeneration: 1234},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes", ObservedGeneration: 1234},
+					},
+				},
+			}},
+		},
+		{
+			name: "existing valid upstream with client credentials in a combined JSON key",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL,
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					Claims: v1alpha1.OIDCClaims{Groups: testGroupsClaim, Username: testUsernameClaim},
+				},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase: "Ready",
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidConditionEarlier,
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials"},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys"},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration"},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types"},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes"},
+					},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data: map[string][]byte{
+					"oidc.json": []byte(`{"clientID":"` + testClientID + `","clientSecret":"` + testClientSecret + `"}`),
+				},
+			}},
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
+				{
+					Name:                     testName,
+					ClientID:                 testClientID,
+					AuthorizationURL:         *testIssuerAuthorizeURL,
+					RevocationURL:            testIssuerRevocationURL,
+					Scopes:                   testDefaultExpectedScopes,
+					UsernameClaim:            testUsernameClaim,
+					GroupsClaim:              testGroupsClaim,
+					AllowPasswordGrant:       false,
+					AdditionalAuthcodeParams: map[string]string{},
+					ResourceUID:              testUID,
+				},
+			},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Ready",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						{Type: "AdditionalAuthorizeParametersValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "additionalAuthorizeParameters parameter names are allowed", ObservedGeneration: 1234},
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid", ObservedGeneration: 1234},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys", ObservedGeneration: 1234},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types", ObservedGeneration: 1234},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes", ObservedGeneration: 1234},
+					},
+				},
+			}},
+		},
+		{
+			name: "existing valid upstream with requestTimeout",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer:         testIssuerURL,
+					TLS:            &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					RequestTimeout: &metav1.Duration{Duration: 5 * time.Second},
+					Client:         v1alpha1.OIDCClient{SecretName: testSecretName},
+					Claims:         v1alpha1.OIDCClaims{Groups: testGroupsClaim, Username: testUsernameClaim},
+				},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase: "Ready",
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidConditionEarlier,
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials"},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys"},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration"},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types"},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes"},
+					},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantClientTimeout: 5 * time.Second,
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
+				{
+					Name:                     testName,
+					ClientID:                 testClientID,
+					AuthorizationURL:         *testIssuerAuthorizeURL,
+					RevocationURL:            testIssuerRevocationURL,
+					Scopes:                   testDefaultExpectedScopes,
+					UsernameClaim:            testUsernameClaim,
+					GroupsClaim:              testGroupsClaim,
+					AllowPasswordGrant:       false,
+					AdditionalAuthcodeParams: map[string]string{},
+					ResourceUID:              testUID,
+				},
+			},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Ready",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						{Type: "AdditionalAuthorizeParametersValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "additionalAuthorizeParameters parameter names are allowed", ObservedGeneration: 1234},
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid", ObservedGeneration: 1234},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys", ObservedGeneration: 1234},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types", ObservedGeneration: 1234},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes", ObservedGeneration: 1234},
+					},
+				},
+			}},
+		},
+		{
+			name: "existing valid upstream with a client certificate",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL,
+					TLS: &v1alpha1.OIDCTLSSpec{
+						TLSSpec: v1alpha1.TLSSpec{
+							CertificateAuthorityData: testIssuerCABase64,
+						},
+						ClientCertificateSecretName: testClientCertSecretName,
+					},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					Claims: v1alpha1.OIDCClaims{Groups: testGroupsClaim, Username: testUsernameClaim},
+				},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase: "Ready",
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidConditionEarlier,
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials"},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys"},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration"},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types"},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes"},
+					},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testClientCertSecretName},
+				Type:       "kubernetes.io/tls",
+				Data:       map[string][]byte{"tls.crt": clientCertPEM, "tls.key": clientKeyPEM},
+			}},
+			wantClientCertificates: 1,
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
+				{
+					Name:                     testName,
+					ClientID:                 testClientID,
+					AuthorizationURL:         *testIssuerAuthorizeURL,
+					RevocationURL:            testIssuerRevocationURL,
+					Scopes:                   testDefaultExpectedScopes,
+					UsernameClaim:            testUsernameClaim,
+					GroupsClaim:              testGroupsClaim,
+					AllowPasswordGrant:       false,
+					AdditionalAuthcodeParams: map[string]string{},
+					ResourceUID:              testUID,
+				},
+			},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Ready",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						{Type: "AdditionalAuthorizeParametersValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "additionalAuthorizeParameters parameter names are allowed", ObservedGeneration: 1234},
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid", ObservedGeneration: 1234},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys", ObservedGeneration: 1234},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types", ObservedGeneration: 1234},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes", ObservedGeneration: 1234},
+					},
+				},
+			}},
+		},
+		{
+			name: "existing valid upstream with authMethod set to post",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL,
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName, AuthMethod: v1alpha1.OIDCClientAuthMethodPost},
+					Claims: v1alpha1.OIDCClaims{Groups: testGroupsClaim, Username: testUsernameClaim},
+				},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase: "Ready",
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidConditionEarlier,
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials"},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys"},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration"},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types"},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes"},
+					},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantAuthStyle: oauth2.AuthStyleInParams,
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
+				{
+					Name:                     testName,
+					ClientID:                 testClientID,
+					AuthorizationURL:         *testIssuerAuthorizeURL,
+					RevocationURL:            testIssuerRevocationURL,
+					Scopes:                   testDefaultExpectedScopes,
+					UsernameClaim:            testUsernameClaim,
+					GroupsClaim:              testGroupsClaim,
+					AllowPasswordGrant:       false,
+					AdditionalAuthcodeParams: map[string]string{},
+					ResourceUID:              testUID,
+				},
+			},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Ready",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						{Type: "AdditionalAuthorizeParametersValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "additionalAuthorizeParameters parameter names are allowed", ObservedGeneration: 1234},
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid", ObservedGeneration: 1234},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys", ObservedGeneration: 1234},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types", ObservedGeneration: 1234},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes", ObservedGeneration: 1234},
+					},
+				},
+			}},
+		},
+		{
+			name: "existing valid upstream with hostedDomain",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL,
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{
+						AdditionalAuthorizeParameters: []v1alpha1.Parameter{{Name: "hd", Value: "example.com"}},
+						HostedDomain:                  "example.com",
+					},
+					Claims: v1alpha1.OIDCClaims{Groups: testGroupsClaim, Username: testUsernameClaim},
+				},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase: "Ready",
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidConditionEarlier,
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials"},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys"},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration"},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types"},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes"},
+					},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantHostedDomain: "example.com",
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
+				{
+					Name:                     testName,
+					ClientID:                 testClientID,
+					AuthorizationURL:         *testIssuerAuthorizeURL,
+					RevocationURL:            testIssuerRevocationURL,
+					Scopes:                   testDefaultExpectedScopes,
+					UsernameClaim:            testUsernameClaim,
+					GroupsClaim:              testGroupsClaim,
+					AllowPasswordGrant:       false,
+					AdditionalAuthcodeParams: map[string]string{"hd": "example.com"},
+					ResourceUID:              testUID,
+				},
+			},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Ready",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						{Type: "AdditionalAuthorizeParametersValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "additionalAuthorizeParameters parameter names are allowed", ObservedGeneration: 1234},
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid", ObservedGeneration: 1234},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys", ObservedGeneration: 1234},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types", ObservedGeneration: 1234},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes", ObservedGeneration: 1234},
+					},
+				},
+			}},
+		},
+		{
+			name: "existing valid upstream with acr_values and ui_locales additionalAuthorizeParams",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL,
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{
+						AdditionalAuthorizeParameters: []v1alpha1.Parameter{
+							{Name: "acr_values", Value: "urn:mace:incommon:iap:silver phr"},
+							{Name: "ui_locales", Value: "fr-CA en"},
+						},
+					},
+					Claims: v1alpha1.OIDCClaims{Groups: testGroupsClaim, Username: testUsernameClaim},
+				},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase: "Ready",
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidConditionEarlier,
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials"},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys"},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration"},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types"},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes"},
+					},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
+				{
+					Name:                     testName,
+					ClientID:                 testClientID,
+					AuthorizationURL:         *testIssuerAuthorizeURL,
+					RevocationURL:            testIssuerRevocationURL,
+					Scopes:                   testDefaultExpectedScopes,
+					UsernameClaim:            testUsernameClaim,
+					GroupsClaim:              testGroupsClaim,
+					AllowPasswordGrant:       false,
+					AdditionalAuthcodeParams: map[string]string{"acr_values": "urn:mace:incommon:iap:silver phr", "ui_locales": "fr-CA en"},
+					ResourceUID:              testUID,
+				},
+			},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Ready",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						{Type: "AdditionalAuthorizeParametersValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "additionalAuthorizeParameters parameter names are allowed", ObservedGeneration: 1234},
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid", ObservedGeneration: 1234},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys", ObservedGeneration: 1234},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types", ObservedGeneration: 1234},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes", ObservedGeneration: 1234},
+					},
+				},
+			}},
+		},
+		{
+			name: "existing valid upstream which ignores email_verified for an email username claim",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL,
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					Claims: v1alpha1.OIDCClaims{Groups: testGroupsClaim, Username: "email", IgnoreEmailVerified: true},
+				},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase: "Ready",
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidConditionEarlier,
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials"},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys"},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration"},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types"},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes"},
+					},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
+				{
+					Name:                     testName,
+					ClientID:                 testClientID,
+					AuthorizationURL:         *testIssuerAuthorizeURL,
+					RevocationURL:            testIssuerRevocationURL,
+					Scopes:                   testDefaultExpectedScopes,
+					UsernameClaim:            "email",
+					GroupsClaim:              testGroupsClaim,
+					IgnoreEmailVerified:      true,
+					AllowPasswordGrant:       false,
+					AdditionalAuthcodeParams: map[string]string{},
+					ResourceUID:              testUID,
+				},
+			},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Ready",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						{Type: "AdditionalAuthorizeParametersValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "additionalAuthorizeParameters parameter names are allowed", ObservedGeneration: 1234},
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid", ObservedGeneration: 1234},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys", ObservedGeneration: 1234},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types", ObservedGeneration: 1234},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes", ObservedGeneration: 1234},
+					},
+				},
+			}},
+		},
+		{
+			name: "authMethod is invalid",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL,
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName, AuthMethod: "jwt"},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="invalid authMethod \"jwt\" (expected \"basic\" or \"post\")" "reason"="InvalidAuthMethod" "status"="False" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="invalid authMethod \"jwt\" (expected \"basic\" or \"post\")" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidAuthMethod" "type"="ClientCredentialsValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
+						{
+							Type:               "ClientCredentialsValid",
+							Status:             "False",
+							LastTransitionTime: now,
+							Reason:             "InvalidAuthMethod",
+							Message:            `invalid authMethod "jwt" (expected "basic" or "post")`,
+						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "fetched JWKS with usable signing keys",
+						},
+						{
+							Type:               "OIDCDiscoverySucceeded",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "discovered issuer configuration",
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "OIDC provider does not advertise its supported grant types",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "OIDC provider does not advertise its supported scopes",
+						},
+					},
+				},
+			}},
+		},
+		{
+			name: "existing valid upstream with no revocation endpoint in the discovery document",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL + "/valid-without-revocation",
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					Claims: v1alpha1.OIDCClaims{Groups: testGroupsClaim, Username: testUsernameClaim},
+				},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase: "Ready",
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidConditionEarlier,
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials"},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys"},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration"},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types"},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes"},
+					},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
+				{
+					Name:                     testName,
+					ClientID:                 testClientID,
+					AuthorizationURL:         *testIssuerAuthorizeURL,
+					RevocationURL:            nil, // no revocation URL is set in the cached provider because none was returned by discovery
+					Scopes:                   testDefaultExpectedScopes,
+					UsernameClaim:            testUsernameClaim,
+					GroupsClaim:              testGroupsClaim,
+					AllowPasswordGrant:       false,
+					AdditionalAuthcodeParams: map[string]string{},
+					ResourceUID:              testUID,
+				},
+			},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Ready",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL + "/valid-without-revocation"),
+					Conditions: []v1alpha1.Condition{
+						{Type: "AdditionalAuthorizeParametersValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "additionalAuthorizeParameters parameter names are allowed", ObservedGeneration: 1234},
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid", ObservedGeneration: 1234},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys", ObservedGeneration: 1234},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types", ObservedGeneration: 1234},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes", ObservedGeneration: 1234},
+					},
+				},
+			}},
+		},
+		{
+			name: "valid upstream with manually configured endpoints skips OIDC discovery",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL + "/broken-discovery",
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					Claims: v1alpha1.OIDCClaims{Groups: testGroupsClaim, Username: testUsernameClaim},
+					AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{
+						AuthorizationEndpoint: testIssuerAuthorizeURL.String(),
+						TokenEndpoint:         testIssuerURL + "/token",
+						JWKSURI:               testIssuerURL + "/jwks.json",
+					},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="using manually configured endpoints instead of OIDC discovery" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
+				{
+					Name:                     testName,
+					ClientID:                 testClientID,
+					AuthorizationURL:         *testIssuerAuthorizeURL,
+					RevocationURL:            nil, // no revocation URL can be configured manually
+					Scopes:                   testDefaultExpectedScopes,
+					UsernameClaim:            testUsernameClaim,
+					GroupsClaim:              testGroupsClaim,
+					AllowPasswordGrant:       false,
+					AdditionalAuthcodeParams: map[string]string{},
+					ResourceUID:              testUID,
+				},
+			},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Ready",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: manualEndpointsDiscoveredConfigHash,
+					Conditions: []v1alpha1.Condition{
+						{Type: "AdditionalAuthorizeParametersValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "additionalAuthorizeParameters parameter names are allowed", ObservedGeneration: 1234},
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid", ObservedGeneration: 1234},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "fetched JWKS with usable signing keys", ObservedGeneration: 1234},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "using manually configured endpoints instead of OIDC discovery", ObservedGeneration: 1234},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types", ObservedGeneration: 1234},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes", ObservedGeneration: 1234},
+					},
+				},
+			}},
+		},
+		{
+			name: "manually configured endpoints are incomplete",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL + "/broken-discovery",
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{
+						AuthorizationEndpoint: testIssuerURL + "/authorize",
+						JWKSURI:               testIssuerURL + "/jwks.json",
+					},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="spec.authorizationConfig.authorizationEndpoint, tokenEndpoint, and jwksURI must all be set when any of them are set" "reason"="InvalidEndpointOverride" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot fetch JWKS until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="spec.authorizationConfig.authorizationEndpoint, tokenEndpoint, and jwksURI must all be set when any of them are set" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidEndpointOverride" "type"="OIDCDiscoverySucceeded"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
+						{
+							Type:               "ClientCredentialsValid",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "loaded client credentials",
+						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot fetch JWKS until OIDC discovery succeeds",
+						},
+						{
+							Type:               "OIDCDiscoverySucceeded",
+							Status:             "False",
+							LastTransitionTime: now,
+							Reason:             "InvalidEndpointOverride",
+							Message:            "spec.authorizationConfig.authorizationEndpoint, tokenEndpoint, and jwksURI must all be set when any of them are set",
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
+						},
+					},
+				},
+			}},
+		},
+		{
+			name: "manually configured authorization endpoint is insecure http URL",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL + "/broken-discovery",
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{
+						AuthorizationEndpoint: "http://example.com/authorize",
+						TokenEndpoint:         testIssuerURL + "/token",
+						JWKSURI:               testIssuerURL + "/jwks.json",
+					},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="authorization endpoint URL 'http://example.com/authorize' must have \"https\" scheme, not \"http\"" "reason"="InvalidEndpointOverride" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot fetch JWKS until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="authorization endpoint URL 'http://example.com/authorize' must have \"https\" scheme, not \"http\"" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidEndpointOverride" "type"="OIDCDiscoverySucceeded"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
+						{
+							Type:               "ClientCredentialsValid",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "loaded client credentials",
+						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot fetch JWKS until OIDC discovery succeeds",
+						},
+						{
+							Type:               "OIDCDiscoverySucceeded",
+							Status:             "False",
+							LastTransitionTime: now,
+							Reason:             "InvalidEndpointOverride",
+							Message:            `authorization endpoint URL 'http://example.com/authorize' must have "https" scheme, not "http"`,
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
+						},
+					},
+				},
+			}},
+		},
+		{
+			name: "existing valid upstream with additionalScopes set to override the default",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL,
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					Claims: v1alpha1.OIDCClaims{Groups: testGroupsClaim, Username: testUsernameClaim},
+					AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{
+						AdditionalScopes: testAdditionalScopes,
+					},
+				},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase: "Ready",
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidConditionEarlier,
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials"},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys"},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration"},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types"},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes"},
+					},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
+				{
+					Name:                     testName,
+					ClientID:                 testClientID,
+					AuthorizationURL:         *testIssuerAuthorizeURL,
+					RevocationURL:            testIssuerRevocationURL,
+					Scopes:                   testExpectedScopes,
+					UsernameClaim:            testUsernameClaim,
+					GroupsClaim:              testGroupsClaim,
+					AllowPasswordGrant:       false,
+					AdditionalAuthcodeParams: map[string]string{},
+					ResourceUID:              testUID,
+				},
+			},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Ready",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						{Type: "AdditionalAuthorizeParametersValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "additionalAuthorizeParameters parameter names are allowed", ObservedGeneration: 1234},
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid", ObservedGeneration: 1234},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys", ObservedGeneration: 1234},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types", ObservedGeneration: 1234},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes", ObservedGeneration: 1234},
+					},
+				},
+			}},
+		},
+		{
+			name: "existing valid upstream with trailing slash and more optional settings",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL + "/ends-with-slash/",
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{
+						AdditionalScopes:              testAdditionalScopes,
+						AdditionalAuthorizeParameters: testAdditionalParams,
+						AllowPasswordGrant:            true,
+					},
+					Claims: v1alpha1.OIDCClaims{Groups: testGroupsClaim, Username: testUsernameClaim, GroupsDelimiter: ","},
+				},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase: "Ready",
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidConditionEarlier,
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials"},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys"},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration"},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types"},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes"},
+					},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="PasswordGrantSupported"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
+				{
+					Name:                     testName,
+					ClientID:                 testClientID,
+					AuthorizationURL:         *testIssuerAuthorizeURL,
+					RevocationURL:            testIssuerRevocationURL,
+					Scopes:                   testExpectedScopes, // does not include the default scopes
+					UsernameClaim:            testUsernameClaim,
+					GroupsClaim:              testGroupsClaim,
+					GroupsDelimiter:          ",",
+					AllowPasswordGrant:       true,
+					AdditionalAuthcodeParams: testExpectedAdditionalParams,
+					ResourceUID:              testUID,
+				},
+			},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Ready",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL + "/ends-with-slash/"),
+					Conditions: []v1alpha1.Condition{
+						{Type: "AdditionalAuthorizeParametersValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "additionalAuthorizeParameters parameter names are allowed", ObservedGeneration: 1234},
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid", ObservedGeneration: 1234},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "fetched JWKS with usable signing keys", ObservedGeneration: 1234},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						{Type: "PasswordGrantSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types", ObservedGeneration: 1234},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types", ObservedGeneration: 1234},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: earlier, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes", ObservedGeneration: 1234},
+					},
+				},
+			}},
+		},
+		{
+			name: "has disallowed additionalAuthorizeParams keys",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL,
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{
+						AdditionalAuthorizeParameters: []v1alpha1.Parameter{
+							{Name: "response_type", Value: "foo"},
+							{Name: "scope", Value: "foo"},
+							{Name: "client_id", Value: "foo"},
+							{Name: "state", Value: "foo"},
+							{Name: "nonce", Value: "foo"},
+							{Name: "code_challenge", Value: "foo"},
+							{Name: "code_challenge_method", Value: "foo"},
+							{Name: "redirect_uri", Value: "foo"},
+							{Name: "hd", Value: "foo"},
+							{Name: "this_one_is_allowed", Value: "foo"},
+						},
+					},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="the following additionalAuthorizeParameters are not allowed: response_type,scope,client_id,state,nonce,code_challenge,code_challenge_method,redirect_uri,hd" "reason"="DisallowedParameterName" "status"="False" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="the following additionalAuthorizeParameters are not allowed: response_type,scope,client_id,state,nonce,code_challenge,code_challenge_method,redirect_uri,hd" "name"="test-name" "namespace"="test-namespace" "reason"="DisallowedParameterName" "type"="AdditionalAuthorizeParametersValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						{Type: "AdditionalAuthorizeParametersValid", Status: "False", LastTransitionTime: now, Reason: "DisallowedParameterName",
+							Message: "the following additionalAuthorizeParameters are not allowed: " +
+								"response_type,scope,client_id,state,nonce,code_challenge,code_challenge_method,redirect_uri,hd", ObservedGeneration: 1234},
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid", ObservedGeneration: 1234},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "fetched JWKS with usable signing keys", ObservedGeneration: 1234},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types", ObservedGeneration: 1234},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes", ObservedGeneration: 1234},
+					},
+				},
+			}},
+		},
+		{
+			name: "has disallowed additionalTokenParameters keys",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL,
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{
+						AdditionalTokenParameters: []v1alpha1.Parameter{
+							{Name: "grant_type", Value: "foo"},
+							{Name: "code", Value: "foo"},
+							{Name: "redirect_uri", Value: "foo"},
+							{Name: "code_verifier", Value: "foo"},
+							{Name: "client_id", Value: "foo"},
+							{Name: "client_secret", Value: "foo"},
+							{Name: "refresh_token", Value: "foo"},
+							{Name: "username", Value: "foo"},
+							{Name: "password", Value: "foo"},
+							{Name: "scope", Value: "foo"},
+							{Name: "audience", Value: "foo"},
+						},
+					},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="the following additionalTokenParameters are not allowed: grant_type,code,redirect_uri,code_verifier,client_id,client_secret,refresh_token,username,password,scope" "reason"="DisallowedParameterName" "status"="False" "type"="AdditionalTokenParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="the following additionalTokenParameters are not allowed: grant_type,code,redirect_uri,code_verifier,client_id,client_secret,refresh_token,username,password,scope" "name"="test-name" "namespace"="test-namespace" "reason"="DisallowedParameterName" "type"="AdditionalTokenParametersValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						{Type: "AdditionalAuthorizeParametersValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "additionalAuthorizeParameters parameter names are allowed", ObservedGeneration: 1234},
+						{Type: "AdditionalTokenParametersValid", Status: "False", LastTransitionTime: now, Reason: "DisallowedParameterName",
+							Message: "the following additionalTokenParameters are not allowed: " +
+								"grant_type,code,redirect_uri,code_verifier,client_id,client_secret,refresh_token,username,password,scope", ObservedGeneration: 1234},
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid", ObservedGeneration: 1234},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "fetched JWKS with usable signing keys", ObservedGeneration: 1234},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types", ObservedGeneration: 1234},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes", ObservedGeneration: 1234},
+					},
+				},
+			}},
+		},
+		{
+			name: "existing valid upstream with additionalTokenParameters",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL,
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{
+						AdditionalTokenParameters: []v1alpha1.Parameter{
+							{Name: "audience", Value: "test-audience"},
+							{Name: "resource", Value: "https://example.com/api"},
+						},
+					},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantTokenParams: map[string]string{"audience": "test-audience", "resource": "https://example.com/api"},
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalTokenParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalTokenParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{
+				{
+					Name:                     testName,
+					ClientID:                 testClientID,
+					AuthorizationURL:         *testIssuerAuthorizeURL,
+					RevocationURL:            testIssuerRevocationURL,
+					Scopes:                   testDefaultExpectedScopes,
+					AdditionalAuthcodeParams: map[string]string{},
+					ResourceUID:              testUID,
+				},
+			},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Ready",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						{Type: "AdditionalAuthorizeParametersValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "additionalAuthorizeParameters parameter names are allowed", ObservedGeneration: 1234},
+						{Type: "AdditionalTokenParametersValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "additionalTokenParameters parameter names are allowed", ObservedGeneration: 1234},
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid", ObservedGeneration: 1234},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "fetched JWKS with usable signing keys", ObservedGeneration: 1234},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types", ObservedGeneration: 1234},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes", ObservedGeneration: 1234},
+					},
+				},
+			}},
+		},
+		{
+			name: "has an invalid username claim template",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL,
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					Claims: v1alpha1.OIDCClaims{Username: "{preferred_username}@{iss"},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims.username template \"{preferred_username}@{iss\" is invalid: unclosed \"{\" at position 21" "reason"="InvalidClaims" "status"="False" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="claims.username template \"{preferred_username}@{iss\" is invalid: unclosed \"{\" at position 21" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidClaims" "type"="ClaimsValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						{Type: "AdditionalAuthorizeParametersValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "additionalAuthorizeParameters parameter names are allowed", ObservedGeneration: 1234},
+						{Type: "ClaimsValid", Status: "False", LastTransitionTime: now, Reason: "InvalidClaims",
+							Message: `claims.username template "{preferred_username}@{iss" is invalid: unclosed "{" at position 21`, ObservedGeneration: 1234},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "fetched JWKS with usable signing keys", ObservedGeneration: 1234},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types", ObservedGeneration: 1234},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes", ObservedGeneration: 1234},
+					},
+				},
+			}},
+		},
+		{
+			name: "has an invalid groups delimiter",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL,
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					Claims: v1alpha1.OIDCClaims{Groups: testGroupsClaim, GroupsDelimiter: "::"},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims.groupsDelimiter \"::\" is invalid (expected a single character)" "reason"="InvalidClaims" "status"="False" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="claims.groupsDelimiter \"::\" is invalid (expected a single character)" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidClaims" "type"="ClaimsValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						{Type: "AdditionalAuthorizeParametersValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "additionalAuthorizeParameters parameter names are allowed", ObservedGeneration: 1234},
+						{Type: "ClaimsValid", Status: "False", LastTransitionTime: now, Reason: "InvalidClaims",
+							Message: `claims.groupsDelimiter "::" is invalid (expected a single character)`, ObservedGeneration: 1234},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "fetched JWKS with usable signing keys", ObservedGeneration: 1234},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types", ObservedGeneration: 1234},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes", ObservedGeneration: 1234},
+					},
+				},
+			}},
+		},
+		{
+			name: "ignores email_verified without an email username claim",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL,
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					Claims: v1alpha1.OIDCClaims{Groups: testGroupsClaim, Username: testUsernameClaim, IgnoreEmailVerified: true},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims.ignoreEmailVerified may only be set when claims.username is \"email\" or references it, but claims.username is \"test-username-claim\"" "reason"="InvalidClaims" "status"="False" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="claims.ignoreEmailVerified may only be set when claims.username is \"email\" or references it, but claims.username is \"test-username-claim\"" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidClaims" "type"="ClaimsValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						{Type: "AdditionalAuthorizeParametersValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "additionalAuthorizeParameters parameter names are allowed", ObservedGeneration: 1234},
+						{Type: "ClaimsValid", Status: "False", LastTransitionTime: now, Reason: "InvalidClaims",
+							Message: `claims.ignoreEmailVerified may only be set when claims.username is "email" or references it, but claims.username is "test-username-claim"`, ObservedGeneration: 1234},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "fetched JWKS with usable signing keys", ObservedGeneration: 1234},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types", ObservedGeneration: 1234},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes", ObservedGeneration: 1234},
+					},
+				},
+			}},
+		},
+		{
+			name: "has an invalid prompt additionalAuthorizeParams value",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL,
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{
+						AdditionalAuthorizeParameters: []v1alpha1.Parameter{
+							{Name: "prompt", Value: "loginn"},
+							{Name: "this_one_is_allowed", Value: "foo"},
+						},
+					},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter \"prompt\" has invalid value \"loginn\" (expected a space-separated list of [\"consent\" \"login\" \"none\" \"select_account\"], where \"none\" may not be combined with other values)" "reason"="InvalidParameterValue" "status"="False" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="additionalAuthorizeParameters parameter \"prompt\" has invalid value \"loginn\" (expected a space-separated list of [\"consent\" \"login\" \"none\" \"select_account\"], where \"none\" may not be combined with other values)" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidParameterValue" "type"="AdditionalAuthorizeParametersValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						{Type: "AdditionalAuthorizeParametersValid", Status: "False", LastTransitionTime: now, Reason: "InvalidParameterValue",
+							Message: `additionalAuthorizeParameters parameter "prompt" has invalid value "loginn" ` +
+								`(expected a space-separated list of ["consent" "login" "none" "select_account"], where "none" may not be combined with other values)`, ObservedGeneration: 1234},
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid", ObservedGeneration: 1234},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "fetched JWKS with usable signing keys", ObservedGeneration: 1234},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types", ObservedGeneration: 1234},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes", ObservedGeneration: 1234},
+					},
+				},
+			}},
+		},
+		{
+			name: "has an invalid ui_locales additionalAuthorizeParams value",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL,
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{
+						AdditionalAuthorizeParameters: []v1alpha1.Parameter{
+							{Name: "ui_locales", Value: "fr-CA  en_US"},
+							{Name: "this_one_is_allowed", Value: "foo"},
+						},
+					},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter \"ui_locales\" has invalid value \"fr-CA  en_US\" (expected a space-separated list of BCP47 language tags, at most 256 characters long)" "reason"="InvalidParameterValue" "status"="False" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="additionalAuthorizeParameters parameter \"ui_locales\" has invalid value \"fr-CA  en_US\" (expected a space-separated list of BCP47 language tags, at most 256 characters long)" "name"="test-name" "namespace"="test-namespace" "reason"="InvalidParameterValue" "type"="AdditionalAuthorizeParametersValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						{Type: "AdditionalAuthorizeParametersValid", Status: "False", LastTransitionTime: now, Reason: "InvalidParameterValue",
+							Message: `additionalAuthorizeParameters parameter "ui_locales" has invalid value "fr-CA  en_US" ` +
+								`(expected a space-separated list of BCP47 language tags, at most 256 characters long)`, ObservedGeneration: 1234},
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid", ObservedGeneration: 1234},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "fetched JWKS with usable signing keys", ObservedGeneration: 1234},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types", ObservedGeneration: 1234},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes", ObservedGeneration: 1234},
+					},
+				},
+			}},
+		},
+		{
+			name: "has additionalAuthorizeParams with disallowed values",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL,
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+					AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{
+						AdditionalAuthorizeParameters: []v1alpha1.Parameter{
+							{Name: "login_hint", Value: "user@example.com\r\nX-Injected: true"},
+							{Name: "this_one_is_allowed", Value: "foo"},
+							{Name: "too_long", Value: strings.Repeat("a", 1025)},
+							{Name: "invalid_utf8", Value: "\xff"},
+						},
+					},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantErr: controllerlib.ErrSyntheticRequeue.Error(),
+			wantLogs: []string{
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="discovered issuer configuration" "reason"="Success" "status"="True" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="fetched JWKS with usable signing keys" "reason"="Success" "status"="True" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported scopes" "reason"="Success" "status"="True" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="OIDC provider does not advertise its supported grant types" "reason"="Success" "status"="True" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="the following additionalAuthorizeParameters have values which are not allowed: login_hint,too_long,invalid_utf8 (expected valid UTF-8 without control characters and at most 1024 characters long)" "reason"="DisallowedParameterValue" "status"="False" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="the following additionalAuthorizeParameters have values which are not allowed: login_hint,too_long,invalid_utf8 (expected valid UTF-8 without control characters and at most 1024 characters long)" "name"="test-name" "namespace"="test-namespace" "reason"="DisallowedParameterValue" "type"="AdditionalAuthorizeParametersValid"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName, Generation: 1234, UID: testUID},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
+					DiscoveredConfigHash: wantDiscoveredConfigHash(testIssuerURL),
+					Conditions: []v1alpha1.Condition{
+						{Type: "AdditionalAuthorizeParametersValid", Status: "False", LastTransitionTime: now, Reason: "DisallowedParameterValue",
+							Message: "the following additionalAuthorizeParameters have values which are not allowed: login_hint,too_long,invalid_utf8 " +
+								"(expected valid UTF-8 without control characters and at most 1024 characters long)", ObservedGeneration: 1234},
+						{Type: "ClaimsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "claims are valid", ObservedGeneration: 1234},
+						{Type: "ClientCredentialsValid", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "loaded client credentials", ObservedGeneration: 1234},
+						{Type: "JWKSFetchSucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "fetched JWKS with usable signing keys", ObservedGeneration: 1234},
+						{Type: "OIDCDiscoverySucceeded", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "discovered issuer configuration", ObservedGeneration: 1234},
+						{Type: "RefreshTokenSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported grant types", ObservedGeneration: 1234},
+						{Type: "ScopesSupported", Status: "True", LastTransitionTime: now, Reason: "Success", Message: "OIDC provider does not advertise its supported scopes", ObservedGeneration: 1234},
+					},
+				},
+			}},
+		},
+		{
+			name: "issuer is invalid URL, missing trailing slash when the OIDC discovery endpoint returns the URL with a trailing slash",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL + "/ends-with-slash", // this does not end with slash when it should, thus this is an error case
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantRequeueAfter: discoveryBackoffInitialDelay,
+			wantLogs: []string{
+				`oidc-upstream-observer "msg"="failed to perform OIDC discovery" "error"="oidc: issuer did not match the issuer returned by provider, expected \"` + testIssuerURL + `/ends-with-slash\" got \"` + testIssuerURL + `/ends-with-slash/\"" "issuer"="` + testIssuerURL + `/ends-with-slash" "name"="test-name" "namespace"="test-namespace"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="failed to perform OIDC discovery against \"` + testIssuerURL + `/ends-with-slash\":\noidc: issuer did not match the issuer returned by provider, expected \"` + testIssuerURL + `/ends-with-slash\" got \"` + testIssuerURL + `/ends-with-slash/\"" "reason"="Unreachable" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot fetch JWKS until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="failed to perform OIDC discovery against \"` + testIssuerURL + `/ends-with-slash\":\noidc: issuer did not match the issuer returned by provider, expected \"` + testIssuerURL + `/ends-with-slash\" got \"` + testIssuerURL + `/ends-with-slash/\"" "name"="test-name" "namespace"="test-namespace" "reason"="Unreachable" "type"="OIDCDiscoverySucceeded"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
+						{
+							Type:               "ClientCredentialsValid",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "loaded client credentials",
+						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot fetch JWKS until OIDC discovery succeeds",
+						},
+						{
+							Type:               "OIDCDiscoverySucceeded",
+							Status:             "False",
+							LastTransitionTime: now,
+							Reason:             "Unreachable",
+							Message: `failed to perform OIDC discovery against "` + testIssuerURL + `/ends-with-slash":
+oidc: issuer did not match the issuer returned by provider, expected "` + testIssuerURL + `/ends-with-slash" got "` + testIssuerURL + `/ends-with-slash/"`,
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
+						},
+					},
+				},
+			}},
+		},
+		{
+			name: "issuer is invalid URL, extra trailing slash",
+			inputUpstreams: []runtime.Object{&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testIssuerURL + "/",
+					TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: testIssuerCABase64}},
+					Client: v1alpha1.OIDCClient{SecretName: testSecretName},
+				},
+			}},
+			inputSecrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       testValidSecretData,
+			}},
+			wantRequeueAfter: discoveryBackoffInitialDelay,
+			wantLogs: []string{
+				`oidc-upstream-observer "msg"="failed to perform OIDC discovery" "error"="oidc: issuer did not match the issuer returned by provider, expected \"` + testIssuerURL + `/\" got \"` + testIssuerURL + `\"" "issuer"="` + testIssuerURL + `/" "name"="test-name" "namespace"="test-namespace"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="loaded client credentials" "reason"="Success" "status"="True" "type"="ClientCredentialsValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="failed to perform OIDC discovery against \"` + testIssuerURL + `/\":\noidc: issuer did not match the issuer returned by provider, expected \"` + testIssuerURL + `/\" got \"` + testIssuerURL + `\"" "reason"="Unreachable" "status"="False" "type"="OIDCDiscoverySucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot fetch JWKS until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="JWKSFetchSucceeded"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check scopes until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="ScopesSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="cannot check refresh token support until OIDC discovery succeeds" "reason"="OIDCDiscoveryFailed" "status"="Unknown" "type"="RefreshTokenSupported"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="additionalAuthorizeParameters parameter names are allowed" "reason"="Success" "status"="True" "type"="AdditionalAuthorizeParametersValid"`,
+				`oidc-upstream-observer "level"=0 "msg"="updated condition" "name"="test-name" "namespace"="test-namespace" "message"="claims are valid" "reason"="Success" "status"="True" "type"="ClaimsValid"`,
+				`oidc-upstream-observer "msg"="found failing condition" "error"="OIDCIdentityProvider has a failing condition" "message"="failed to perform OIDC discovery against \"` + testIssuerURL + `/\":\noidc: issuer did not match the issuer returned by provider, expected \"` + testIssuerURL + `/\" got \"` + testIssuerURL + `\"" "name"="test-name" "namespace"="test-namespace" "reason"="Unreachable" "type"="OIDCDiscoverySucceeded"`,
+			},
+			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
+			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				Status: v1alpha1.OIDCIdentityProviderStatus{
+					Phase:                "Error",
+					CertificateAuthority: testIssuerCAStatus,
+					Conditions: []v1alpha1.Condition{
+						happyAdditionalAuthorizeParametersValidCondition,
+						happyClaimsValidCondition,
+						{
+							Type:               "ClientCredentialsValid",
+							Status:             "True",
+							LastTransitionTime: now,
+							Reason:             "Success",
+							Message:            "loaded client credentials",
+						},
+						{
+							Type:               "JWKSFetchSucceeded",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot fetch JWKS until OIDC discovery succeeds",
+						},
+						{
+							Type:               "OIDCDiscoverySucceeded",
+							Status:             "False",
+							LastTransitionTime: now,
+							Reason:             "Unreachable",
+							Message: `failed to perform OIDC discovery against "` + testIssuerURL + `/":
+oidc: issuer did not match the issuer returned by provider, expected "` + testIssuerURL + `/" got "` + testIssuerURL + `"`,
+						},
+						{
+							Type:               "RefreshTokenSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check refresh token support until OIDC discovery succeeds",
+						},
+						{
+							Type:               "ScopesSupported",
+							Status:             "Unknown",
+							LastTransitionTime: now,
+							Reason:             "OIDCDiscoveryFailed",
+							Message:            "cannot check scopes until OIDC discovery succeeds",
+						},
+					},
+				},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			fakePinnipedClient := pinnipedfake.NewSimpleClientset(tt.inputUpstreams...)
+			pinnipedInformers := pinnipedinformers.NewSharedInformerFactory(fakePinnipedClient, 0)
+			fakeKubeClient := fake.NewSimpleClientset(tt.inputSecrets...)
+			kubeInformers := informers.NewSharedInformerFactory(fakeKubeClient, 0)
+			testLog := testlogger.NewLegacy(t) //nolint: staticcheck  // old test with lots of log statements
+			cache := provider.NewDynamicUpstreamIDPProvider()
+			cache.SetOIDCIdentityProviders([]provider.UpstreamOIDCIdentityProviderI{
+				&upstreamoidc.ProviderConfig{Name: "initial-entry"},
+			})
+
+			controller := New(
+				cache,
+				fakePinnipedClient,
+				pinnipedInformers.IDP().V1alpha1().OIDCIdentityProviders(),
+				kubeInformers.Core().V1().Secrets(),
+				nil,
+				0,
+				nil,
+				clocktesting.NewFakeClock(now.Time),
+				testLog.Logger,
+				controllerlib.WithInformer,
+			)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			pinnipedInformers.Start(ctx.Done())
+			kubeInformers.Start(ctx.Done())
+			controllerlib.TestRunSynchronously(t, controller)
+
+			queue := &testQueue{t: t}
+			syncCtx := controllerlib.Context{Context: ctx, Key: controllerlib.Key{}, Queue: queue}
+
+			if err := controllerlib.TestSync(t, controller, syncCtx); tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			requireRequeuedAfter(t, queue, tt.wantRequeueAfter)
+			firstRequeueDuration := queue.duration
+			require.Equal(t, strings.Join(tt.wantLogs, "\n"), strings.Join(testLog.Lines(), "\n"))
+
+			actualIDPList := cache.GetOIDCIdentityProviders()
+			require.Equal(t, len(tt.wantResultingCache), len(actualIDPList))
+			for i := range actualIDPList {
+				actualIDP := actualIDPList[i].(*upstreamoidc.ProviderConfig)
+				require.Equal(t, tt.wantResultingCache[i].GetName(), actualIDP.GetName())
+				require.Equal(t, tt.wantResultingCache[i].GetClientID(), actualIDP.GetClientID())
+				require.Equal(t, tt.wantResultingCache[i].GetAuthorizationURL().String(), actualIDP.GetAuthorizationURL().String())
+				require.Equal(t, tt.wantResultingCache[i].GetUsernameClaim(), actualIDP.GetUsernameClaim())
+				require.Equal(t, tt.wantResultingCache[i].GetGroupsClaim(), actualIDP.GetGroupsClaim())
+				require.Equal(t, tt.wantResultingCache[i].GetGroupsDelimiter(), actualIDP.GetGroupsDelimiter())
+				require.Equal(t, tt.wantResultingCache[i].IgnoresEmailVerified(), actualIDP.IgnoresEmailVerified())
+				require.Equal(t, tt.wantResultingCache[i].AllowsPasswordGrant(), actualIDP.AllowsPasswordGrant())
+				require.Equal(t, tt.wantResultingCache[i].GetAdditionalAuthcodeParams(), actualIDP.GetAdditionalAuthcodeParams())
+				require.Equal(t, tt.wantResultingCache[i].GetResourceUID(), actualIDP.GetResourceUID())
+				require.Equal(t, tt.wantResultingCache[i].GetRevocationURL(), actualIDP.GetRevocationURL())
+				require.ElementsMatch(t, tt.wantResultingCache[i].GetScopes(), actualIDP.GetScopes())
+				require.Equal(t, tt.wantAuthStyle, actualIDP.Config.Endpoint.AuthStyle)
+				require.Equal(t, tt.wantHostedDomain, actualIDP.HostedDomain)
+				require.Equal(t, tt.wantTokenParams, actualIDP.AdditionalTokenParams)
+				require.Len(t, unwrapTransport(t, actualIDP.Client.Transport).TLSClientConfig.Certificates, tt.wantClientCertificates)
+
+				// We always want to use the proxy from env on these clients, so although the following assertions
+				// are a little hacky, this is a cheap way to test that we are using it.
+				actualTransport := unwrapTransport(t, actualIDP.Client.Transport)
+				httpProxyFromEnvFunction := reflect.ValueOf(http.ProxyFromEnvironment).Pointer()
+				actualTransportProxyFunction := reflect.ValueOf(actualTransport.Proxy).Pointer()
+				require.Equal(t, httpProxyFromEnvFunction, actualTransportProxyFunction,
+					"Transport should have used http.ProxyFromEnvironment as its Proxy func")
+				// We also want a reasonable timeout on each request/response cycle for OIDC discovery and JWKS.
+				wantClientTimeout := time.Minute
+				if tt.wantClientTimeout != 0 {
+					wantClientTimeout = tt.wantClientTimeout
+				}
+				require.Equal(t, wantClientTimeout, actualIDP.Client.Timeout)
+			}
+
+			actualUpstreams, err := fakePinnipedClient.IDPV1alpha1().OIDCIdentityProviders(testNamespace).List(ctx, metav1.ListOptions{})
+			require.NoError(t, err)
+
+			// Assert on the expected Status of the upstreams. Preprocess the upstreams a bit so that they're easier to assert against.
+			require.ElementsMatch(t, tt.wantResultingUpstreams, normalizeOIDCUpstreams(actualUpstreams.Items, now))
+
+			// Running the sync() a second time should be idempotent except for logs, and should return the same error.
+			// This also helps exercise code paths where the OIDC provider discovery hits cache.
+			*queue = testQueue{t: t}
+			if err := controllerlib.TestSync(t, controller, syncCtx); tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			// Time has not passed, so the second sync should still be waiting for the same retry time.
+			requireRequeuedAfter(t, queue, tt.wantRequeueAfter)
+			require.Equal(t, firstRequeueDuration, queue.duration)
+		})
+	}
+}
+
+func TestOIDCUpstreamWatcherControllerDiscoveryBackoff(t *testing.T) {
+	t.Parallel()
+
+	// Serve an issuer which fails discovery until it is told to start working.
+	var discoveryRequests int32
+	failDiscovery := int32(1)
+	signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	var caBundlePEM, testURL string
+	caBundlePEM, testURL = testutil.TLSTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/jwks.json" {
+			w.Header().Set("content-type", "application/json")
+			_ = json.NewEncoder(w).Encode(&jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+				{Key: signingKey.Public(), KeyID: "test-kid", Algorithm: "ES256", Use: "sig"},
+			}})
+			return
+		}
+		atomic.AddInt32(&discoveryRequests, 1)
+		if atomic.LoadInt32(&failDiscovery) == 1 {
+			http.Error(w, "try again later", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("content-type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 testURL,
+			"authorization_endpoint": "https://example.com/authorize",
+			"token_endpoint":         "https://example.com/token",
+			"jwks_uri":               testURL + "/jwks.json",
+		})
+	})
+
+	fakePinnipedClient := pinnipedfake.NewSimpleClientset(&v1alpha1.OIDCIdentityProvider{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test-namespace", Name: "test-name"},
+		Spec: v1alpha1.OIDCIdentityProviderSpec{
+			Issuer: testURL,
+			TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(caBundlePEM))}},
+			Client: v1alpha1.OIDCClient{SecretName: "test-client-secret"},
+		},
+	})
+	pinnipedInformers := pinnipedinformers.NewSharedInformerFactory(fakePinnipedClient, 0)
+	fakeKubeClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test-namespace", Name: "test-client-secret"},
+		Type:       "secrets.pinniped.dev/oidc-client",
+		Data:       map[string][]byte{"clientID": []byte("test-client-id"), "clientSecret": []byte("test-client-secret")},
+	})
+	kubeInformers := informers.NewSharedInformerFactory(fakeKubeClient, 0)
+	cache := provider.NewDynamicUpstreamIDPProvider()
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+
+	controller := New(
+		cache,
+		fakePinnipedClient,
+		pinnipedInformers.IDP().V1alpha1().OIDCIdentityProviders(),
+		kubeInformers.Core().V1().Secrets(),
+		nil,
+		5,
+		nil,
+		fakeClock,
+		testlogger.New(t).Logger,
+		controllerlib.WithInformer,
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pinnipedInformers.Start(ctx.Done())
+	kubeInformers.Start(ctx.Done())
+	controllerlib.TestRunSynchronously(t, controller)
+
+	queue := &testQueue{t: t}
+	syncCtx := controllerlib.Context{Context: ctx, Key: controllerlib.Key{}, Queue: queue}
+	sync := func() {
+		t.Helper()
+		*queue = testQueue{t: t}
+		require.NoError(t, controllerlib.TestSync(t, controller, syncCtx))
+	}
+
+	requireDiscoveryCondition := func(wantStatus v1alpha1.ConditionStatus, wantReason string) {
+		t.Helper()
+		upstream, err := fakePinnipedClient.IDPV1alpha1().OIDCIdentityProviders("test-namespace").Get(ctx, "test-name", metav1.GetOptions{})
+		require.NoError(t, err)
+		for _, condition := range upstream.Status.Conditions {
+			if condition.Type == typeOIDCDiscoverySucceeded {
+				require.Equal(t, wantStatus, condition.Status)
+				require.Equal(t, wantReason, condition.Reason)
+				return
+			}
+		}
+		require.Fail(t, "missing OIDCDiscoverySucceeded condition")
+	}
+
+	// Each failure doubles the delay before the next attempt, up to the maximum delay. After the failure threshold
+	// is reached, the issuer is reported as persistently unreachable.
+	wantDelay := discoveryBackoffInitialDelay
+	for attempt := 1; attempt <= 8; attempt++ {
+		sync()
+		require.Equal(t, int32(attempt), atomic.LoadInt32(&discoveryRequests))
+		requireRequeuedAfter(t, queue, wantDelay)
+		require.Empty(t, cache.GetOIDCIdentityProviders())
+		if attempt < 5 {
+			requireDiscoveryCondition(v1alpha1.ConditionFalse, "Unreachable")
+		} else {
+			requireDiscoveryCondition(v1alpha1.ConditionFalse, "PersistentlyUnreachable")
+		}
+
+		// Syncing again before the delay has elapsed does not retry discovery.
+		fakeClock.Step(queue.duration / 2)
+		sync()
+		require.Equal(t, int32(attempt), atomic.LoadInt32(&discoveryRequests))
+		require.True(t, queue.called)
+		require.Greater(t, queue.duration, time.Duration(0))
+
+		fakeClock.Step(queue.duration)
+		wantDelay *= 2
+		if wantDelay > discoveryBackoffMaxDelay {
+			wantDelay = discoveryBackoffMaxDelay
+		}
+	}
+
+	// Once the upstream comes back, the next attempt succeeds and does not requeue.
+	atomic.StoreInt32(&failDiscovery, 0)
+	sync()
+	require.Equal(t, int32(9), atomic.LoadInt32(&discoveryRequests))
+	require.False(t, queue.called)
+	require.Len(t, cache.GetOIDCIdentityProviders(), 1)
+	requireDiscoveryCondition(v1alpha1.ConditionTrue, "Success")
+
+	// The count of consecutive failures starts over after a success, so the next failure is transient again
+	// once the discovered provider expires from the cache.
+	atomic.StoreInt32(&failDiscovery, 1)
+	fakeClock.Step(oidcValidatorCacheTTL + time.Second)
+	sync()
+	require.Equal(t, int32(10), atomic.LoadInt32(&discoveryRequests))
+	requireRequeuedAfter(t, queue, discoveryBackoffInitialDelay)
+	requireDiscoveryCondition(v1alpha1.ConditionFalse, "Unreachable")
+}
+
+func TestOIDCUpstreamWatcherControllerDisableDiscoveryCache(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                  string
+		disableDiscoveryCache bool
+		wantDiscoveryRequests int32
+	}{
+		{
+			name:                  "discovery cache enabled",
+			wantDiscoveryRequests: 1,
+		},
+		{
+			name:                  "discovery cache disabled",
+			disableDiscoveryCache: true,
+			wantDiscoveryRequests: 2,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Serve an issuer which counts how many times discovery is performed against it.
+			var discoveryRequests int32
+			signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			require.NoError(t, err)
+
+			var caBundlePEM, testURL string
+			caBundlePEM, testURL = testutil.TLSTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/jwks.json" {
+					w.Header().Set("content-type", "application/json")
+					_ = json.NewEncoder(w).Encode(&jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+						{Key: signingKey.Public(), KeyID: "test-kid", Algorithm: "ES256", Use: "sig"},
+					}})
+					return
+				}
+				atomic.AddInt32(&discoveryRequests, 1)
+				w.Header().Set("content-type", "application/json")
+				_ = json.NewEncoder(w).Encode(map[string]string{
+					"issuer":                 testURL,
+					"authorization_endpoint": "https://example.com/authorize",
+					"token_endpoint":         "https://example.com/token",
+					"jwks_uri":               testURL + "/jwks.json",
+				})
+			})
+
+			caBundleBase64 := base64.StdEncoding.EncodeToString([]byte(caBundlePEM))
+			fakePinnipedClient := pinnipedfake.NewSimpleClientset(&v1alpha1.OIDCIdentityProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test-namespace", Name: "test-name"},
+				Spec: v1alpha1.OIDCIdentityProviderSpec{
+					Issuer: testURL,
+					TLS: &v1alpha1.OIDCTLSSpec{
+						TLSSpec:                      v1alpha1.TLSSpec{CertificateAuthorityData: caBundleBase64},
+						JWKSCertificateAuthorityData: caBundleBase64,
+					},
+					DisableDiscoveryCache: tt.disableDiscoveryCache,
+					Client:                v1alpha1.OIDCClient{SecretName: "test-client-secret"},
+				},
+			})
+			pinnipedInformers := pinnipedinformers.NewSharedInformerFactory(fakePinnipedClient, 0)
+			fakeKubeClient := fake.NewSimpleClientset(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test-namespace", Name: "test-client-secret"},
+				Type:       "secrets.pinniped.dev/oidc-client",
+				Data:       map[string][]byte{"clientID": []byte("test-client-id"), "clientSecret": []byte("test-client-secret")},
+			})
+			kubeInformers := informers.NewSharedInformerFactory(fakeKubeClient, 0)
+			cache := provider.NewDynamicUpstreamIDPProvider()
+
+			controller := New(
+				cache,
+				fakePinnipedClient,
+				pinnipedInformers.IDP().V1alpha1().OIDCIdentityProviders(),
+				kubeInformers.Core().V1().Secrets(),
+				nil,
+				0,
+				nil,
+				clocktesting.NewFakeClock(time.Now()),
+				testlogger.New(t).Logger,
+				controllerlib.WithInformer,
+			)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			pinnipedInformers.Start(ctx.Done())
+			kubeInformers.Start(ctx.Done())
+			controllerlib.TestRunSynchronously(t, controller)
+
+			syncCtx := controllerlib.Context{Context: ctx, Key: controllerlib.Key{}, Queue: &testQueue{t: t}}
+			var providers []interface{}
+			for i := 0; i < 2; i++ {
+				require.NoError(t, controllerlib.TestSync(t, controller, syncCtx))
+				require.Len(t, cache.GetOIDCIdentityProviders(), 1)
+				providers = append(providers, cache.GetOIDCIdentityProviders()[0].(*upstreamoidc.ProviderConfig).Provider)
+			}
+			require.Equal(t, tt.wantDiscoveryRequests, atomic.LoadInt32(&discoveryRequests))
+
+			// The provider which fetches the signing keys with the separate JWKS CA bundle is also cached, so that the
+			// signing keys which it already fetched are not thrown away on every sync.
+			require.IsType(t, &separateJWKSProvider{}, providers[0])
+			if tt.disableDiscoveryCache {
+				require.NotSame(t, providers[0], providers[1])
+			} else {
+				require.Same(t, providers[0], providers[1])
+			}
+		})
+	}
+}
+
+func TestOIDCUpstreamWatcherControllerNamespaceAllowlist(t *testing.T) {
+	t.Parallel()
+
+	testIssuerCA, testIssuerURL := newTestIssuer(t)
+	newUpstream := func(namespace, name string) *v1alpha1.OIDCIdentityProvider {
+		return &v1alpha1.OIDCIdentityProvider{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Generation: 1234},
+			Spec: v1alpha1.OIDCIdentityProviderSpec{
+				Issuer: testIssuerURL,
+				TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(testIssuerCA))}},
+				Client: v1alpha1.OIDCClient{SecretName: "test-client-secret"},
+			},
+		}
+	}
+	newSecret := func(namespace string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "test-client-secret"},
+			Type:       "secrets.pinniped.dev/oidc-client",
+			Data:       map[string][]byte{"clientID": []byte("test-client-id"), "clientSecret": []byte("test-client-secret")},
+		}
+	}
+
+	fakePinnipedClient := pinnipedfake.NewSimpleClientset(
+		newUpstream("tenant-a", "allowed-idp"),
+		newUpstream("tenant-b", "also-allowed-idp"),
+		newUpstream("tenant-c", "disallowed-idp"),
+	)
+	pinnipedInformers := pinnipedinformers.NewSharedInformerFactory(fakePinnipedClient, 0)
+	fakeKubeClient := fake.NewSimpleClientset(newSecret("tenant-a"), newSecret("tenant-b"), newSecret("tenant-c"))
+	kubeInformers := informers.NewSharedInformerFactory(fakeKubeClient, 0)
+	cache := provider.NewDynamicUpstreamIDPProvider()
+	testLog := testlogger.New(t)
+
+	controller := New(
+		cache,
+		fakePinnipedClient,
+		pinnipedInformers.IDP().V1alpha1().OIDCIdentityProviders(),
+		kubeInformers.Core().V1().Secrets(),
+		[]string{"tenant-b", "tenant-a"},
+		0,
+		nil,
+		clocktesting.NewFakeClock(time.Now()),
+		testLog.Logger,
+		controllerlib.WithInformer,
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pinnipedInformers.Start(ctx.Done())
+	kubeInformers.Start(ctx.Done())
+	controllerlib.TestRunSynchronously(t, controller)
+
+	queue := &testQueue{t: t}
+	syncCtx := controllerlib.Context{Context: ctx, Key: controllerlib.Key{}, Queue: queue}
+	require.NoError(t, controllerlib.TestSync(t, controller, syncCtx))
+	require.False(t, queue.called)
+
+	// Only the upstreams in allowed namespaces are loaded into the cache.
+	var cachedNames []string
+	for _, idp := range cache.GetOIDCIdentityProviders() {
+		cachedNames = append(cachedNames, idp.GetName())
+	}
+	require.ElementsMatch(t, []string{"allowed-idp", "also-allowed-idp"}, cachedNames)
+
+	for _, namespace := range []string{"tenant-a", "tenant-b"} {
+		upstreams, err := fakePinnipedClient.IDPV1alpha1().OIDCIdentityProviders(namespace).List(ctx, metav1.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, upstreams.Items, 1)
+		upstream := upstreams.Items[0]
+		require.Equal(t, v1alpha1.PhaseReady, upstream.Status.Phase)
+		allowedCondition := findCondition(upstream.Status.Conditions, "NamespaceAllowed")
+		require.NotNil(t, allowedCondition)
+		require.Equal(t, v1alpha1.ConditionTrue, allowedCondition.Status)
+		require.Equal(t, "Success", allowedCondition.Reason)
+		require.Equal(t, "namespace is within the configured tenancy scope", allowedCondition.Message)
+	}
+
+	// The upstream in the disallowed namespace gets only a failing condition, without being validated further.
+	upstreams, err := fakePinnipedClient.IDPV1alpha1().OIDCIdentityProviders("tenant-c").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, upstreams.Items, 1)
+	upstream := upstreams.Items[0]
+	require.Equal(t, v1alpha1.PhaseError, upstream.Status.Phase)
+	require.Empty(t, upstream.Status.DiscoveredConfigHash)
+	require.Len(t, upstream.Status.Conditions, 1)
+	require.Equal(t, "NamespaceAllowed", upstream.Status.Conditions[0].Type)
+	require.Equal(t, v1alpha1.ConditionFalse, upstream.Status.Conditions[0].Status)
+	require.Equal(t, "NamespaceNotAllowed", upstream.Status.Conditions[0].Reason)
+	require.Equal(t, `namespace "tenant-c" is outside the configured tenancy scope (allowed namespaces: tenant-a, tenant-b)`,
+		upstream.Status.Conditions[0].Message)
+	require.Equal(t, int64(1234), upstream.Status.Conditions[0].ObservedGeneration)
+	require.Contains(t, testLog.Lines(), `oidc-upstream-observer: "msg"="found failing condition" `+
+		`"error"="OIDCIdentityProvider has a failing condition" "namespace"="tenant-c" "name"="disallowed-idp" `+
+		`"type"="NamespaceAllowed" "reason"="NamespaceNotAllowed" `+
+		`"message"="namespace \"tenant-c\" is outside the configured tenancy scope (allowed namespaces: tenant-a, tenant-b)"`)
+}
+
+func TestOIDCUpstreamWatcherControllerDisabled(t *testing.T) {
+	t.Parallel()
+
+	testIssuerCA, testIssuerURL := newTestIssuer(t)
+	newUpstream := func(name string) *v1alpha1.OIDCIdentityProvider {
+		return &v1alpha1.OIDCIdentityProvider{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test-namespace", Name: name, Generation: 1234},
+			Spec: v1alpha1.OIDCIdentityProviderSpec{
+				Issuer: testIssuerURL,
+				TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(testIssuerCA))}},
+				Client: v1alpha1.OIDCClient{SecretName: "test-client-secret"},
+			},
+		}
+	}
+
+	// The disabled upstream would fail validation and cause a requeue if it were validated.
+	disabledUpstream := newUpstream("disabled-idp")
+	disabledUpstream.Spec.Disabled = true
+	disabledUpstream.Spec.Issuer = "https://127.0.0.1:1/unreachable"
+	disabledUpstream.Spec.Client.SecretName = "missing-client-secret"
+	disabledUpstream.Status.CertificateAuthority = &v1alpha1.OIDCCertificateAuthorityStatus{Subject: "CN=test"}
+
+	reenabledUpstream := newUpstream("re-enabled-idp")
+	reenabledUpstream.Status = v1alpha1.OIDCIdentityProviderStatus{
+		Phase: v1alpha1.PhaseDisabled,
+		Conditions: []v1alpha1.Condition{
+			{Type: "Enabled", Status: "False", Reason: "Disabled", Message: "disabled by spec.disabled, so it cannot be used to log in"},
+		},
+	}
+
+	fakePinnipedClient := pinnipedfake.NewSimpleClientset(newUpstream("enabled-idp"), disabledUpstream, reenabledUpstream)
+	pinnipedInformers := pinnipedinformers.NewSharedInformerFactory(fakePinnipedClient, 0)
+	fakeKubeClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test-namespace", Name: "test-client-secret"},
+		Type:       "secrets.pinniped.dev/oidc-client",
+		Data:       map[string][]byte{"clientID": []byte("test-client-id"), "clientSecret": []byte("test-client-secret")},
+	})
+	kubeInformers := informers.NewSharedInformerFactory(fakeKubeClient, 0)
+	cache := provider.NewDynamicUpstreamIDPProvider()
+	testLog := testlogger.New(t)
+
+	controller := New(
+		cache,
+		fakePinnipedClient,
+		pinnipedInformers.IDP().V1alpha1().OIDCIdentityProviders(),
+		kubeInformers.Core().V1().Secrets(),
+		nil,
+		0,
+		nil,
+		clocktesting.NewFakeClock(time.Now()),
+		testLog.Logger,
+		controllerlib.WithInformer,
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pinnipedInformers.Start(ctx.Done())
+	kubeInformers.Start(ctx.Done())
+	controllerlib.TestRunSynchronously(t, controller)
+
+	// The disabled upstream does not cause a requeue.
+	queue := &testQueue{t: t}
+	syncCtx := controllerlib.Context{Context: ctx, Key: controllerlib.Key{}, Queue: queue}
+	require.NoError(t, controllerlib.TestSync(t, controller, syncCtx))
+	require.False(t, queue.called)
+
+	// Only the enabled upstreams are loaded into the cache.
+	var cachedNames []string
+	for _, idp := range cache.GetOIDCIdentityProviders() {
+		cachedNames = append(cachedNames, idp.GetName())
+	}
+	require.ElementsMatch(t, []string{"enabled-idp", "re-enabled-idp"}, cachedNames)
+
+	getUpstream := func(name string) *v1alpha1.OIDCIdentityProvider {
+		upstream, err := fakePinnipedClient.IDPV1alpha1().OIDCIdentityProviders("test-namespace").Get(ctx, name, metav1.GetOptions{})
+		require.NoError(t, err)
+		return upstream
+	}
+
+	// The disabled upstream gets only the Enabled condition, without being validated further.
+	upstream := getUpstream("disabled-idp")
+	require.Equal(t, v1alpha1.PhaseDisabled, upstream.Status.Phase)
+	require.Empty(t, upstream.Status.DiscoveredConfigHash)
+	require.Equal(t, &v1alpha1.OIDCCertificateAuthorityStatus{Subject: "CN=test"}, upstream.Status.CertificateAuthority)
+	require.Len(t, upstream.Status.Conditions, 1)
+	require.Equal(t, "Enabled", upstream.Status.Conditions[0].Type)
+	require.Equal(t, v1alpha1.ConditionFalse, upstream.Status.Conditions[0].Status)
+	require.Equal(t, "Disabled", upstream.Status.Conditions[0].Reason)
+	require.Equal(t, "disabled by spec.disabled, so it cannot be used to log in", upstream.Status.Conditions[0].Message)
+	require.Equal(t, int64(1234), upstream.Status.Conditions[0].ObservedGeneration)
+	require.Contains(t, testLog.Lines(), `oidc-upstream-observer: "level"=0 "msg"="skipping disabled OIDCIdentityProvider" `+
+		`"namespace"="test-namespace" "name"="disabled-idp"`)
+
+	// The upstream which was never disabled does not get an Enabled condition.
+	upstream = getUpstream("enabled-idp")
+	require.Equal(t, v1alpha1.PhaseReady, upstream.Status.Phase)
+	require.Nil(t, findCondition(upstream.Status.Conditions, "Enabled"))
+
+	// The upstream which was disabled before has its Enabled condition replaced.
+	upstream = getUpstream("re-enabled-idp")
+	require.Equal(t, v1alpha1.PhaseReady, upstream.Status.Phase)
+	enabledCondition := findCondition(upstream.Status.Conditions, "Enabled")
+	require.NotNil(t, enabledCondition)
+	require.Equal(t, v1alpha1.ConditionTrue, enabledCondition.Status)
+	require.Equal(t, "Success", enabledCondition.Reason)
+	require.Equal(t, "not disabled by spec.disabled", enabledCondition.Message)
+}
+
+func TestOIDCUpstreamWatcherControllerIssuerAllowlist(t *testing.T) {
+	t.Parallel()
+
+	testIssuerCA, testIssuerURL := newTestIssuer(t)
+	newUpstream := func(name, issuer string) *v1alpha1.OIDCIdentityProvider {
+		return &v1alpha1.OIDCIdentityProvider{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test-namespace", Name: name, Generation: 1234},
+			Spec: v1alpha1.OIDCIdentityProviderSpec{
+				Issuer: issuer,
+				TLS:    &v1alpha1.OIDCTLSSpec{TLSSpec: v1alpha1.TLSSpec{CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(testIssuerCA))}},
+				Client: v1alpha1.OIDCClient{SecretName: "test-client-secret"},
+			},
+		}
+	}
+
+	fakePinnipedClient := pinnipedfake.NewSimpleClientset(
+		newUpstream("allowed-idp", testIssuerURL),
+		newUpstream("disallowed-idp", "https://login.other.example.com"),
+	)
+	pinnipedInformers := pinnipedinformers.NewSharedInformerFactory(fakePinnipedClient, 0)
+	fakeKubeClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test-namespace", Name: "test-client-secret"},
+		Type:       "secrets.pinniped.dev/oidc-client",
+		Data:       map[string][]byte{"clientID": []byte("test-client-id"), "clientSecret": []byte("test-client-secret")},
+	})
+	kubeInformers := informers.NewSharedInformerFactory(fakeKubeClient, 0)
+	cache := provider.NewDynamicUpstreamIDPProvider()
+
+	controller := New(
+		cache,
+		fakePinnipedClient,
+		pinnipedInformers.IDP().V1alpha1().OIDCIdentityProviders(),
+		kubeInformers.Core().V1().Secrets(),
+		nil,
+		0,
+		regexp.MustCompile(`^` + regexp.QuoteMeta(testIssuerURL) + `(/.*)?$`),
+		clocktesting.NewFakeClock(time.Now()),
+		testlogger.New(t).Logger,
+		controllerlib.WithInformer,
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pinnipedInformers.Start(ctx.Done())
+	kubeInformers.Start(ctx.Done())
+	controllerlib.TestRunSynchronously(t, controller)
+
+	queue := &testQueue{t: t}
+	syncCtx := controllerlib.Context{Context: ctx, Key: controllerlib.Key{}, Queue: queue}
+	// Like any other invalid upstream, the upstream with a disallowed issuer causes a requeue.
+	require.ErrorIs(t, controllerlib.TestSync(t, controller, syncCtx), controllerlib.ErrSyntheticRequeue)
+
+	// Only the upstream with an allowed issuer is loaded into the cache.
+	require.Len(t, cache.GetOIDCIdentityProviders(), 1)
+	require.Equal(t, "allowed-idp", cache.GetOIDCIdentityProviders()[0].GetName())
+
+	allowed, err := fakePinnipedClient.IDPV1alpha1().OIDCIdentityProviders("test-namespace").Get(ctx, "allowed-idp", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, v1alpha1.PhaseReady, allowed.Status.Phase)
+
+	// The upstream with a disallowed issuer fails discovery without any request being made to its issuer.
+	disallowed, err := fakePinnipedClient.IDPV1alpha1().OIDCIdentityProviders("test-namespace").Get(ctx, "disallowed-idp", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, v1alpha1.PhaseError, disallowed.Status.Phase)
+	discoveryCondition := findCondition(disallowed.Status.Conditions, "OIDCDiscoverySucceeded")
+	require.NotNil(t, discoveryCondition)
+	require.Equal(t, v1alpha1.ConditionFalse, discoveryCondition.Status)
+	require.Equal(t, "DisallowedIssuer", discoveryCondition.Reason)
+	require.Equal(t, `issuer "https://login.other.example.com" is not allowed by the configured issuer allowlist `+
+		`(allowed issuer pattern: ^`+regexp.QuoteMeta(testIssuerURL)+`(/.*)?$)`, discoveryCondition.Message)
+	jwksCondition := findCondition(disallowed.Status.Conditions, "JWKSFetchSucceeded")
+	require.NotNil(t, jwksCondition)
+	require.Equal(t, v1alpha1.ConditionUnknown, jwksCondition.Status)
+}
+
+func findCondition(conditions []v1alpha1.Condition, conditionType string) *v1alpha1.Condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+func TestValidPromptValue(t *testing.T) {
+	t.Parallel()
+
+	for _, value := range []string{"none", "login", "consent", "select_account", "login consent", "consent select_account login"} {
+		require.True(t, validPromptValue(value), "expected %q to be valid", value)
+	}
+	for _, value := range []string{"", "loginn", "Login", "login,consent", "login  consent", " login", "none login", "consent none"} {
+		require.False(t, validPromptValue(value), "expected %q to be invalid", value)
+	}
+}
+
+func TestParseCertificateAuthorityData(t *testing.T) {
+	t.Parallel()
+
+	ca1, err := certauthority.New("first-ca", time.Hour)
+	require.NoError(t, err)
+	ca2, err := certauthority.New("second-ca", time.Hour)
+	require.NoError(t, err)
+	privateKeyPEM, err := ca1.PrivateKeyToPEM()
+	require.NoError(t, err)
+	ca1Block, _ := pem.Decode(ca1.Bundle())
+	ca1Cert, err := x509.ParseCertificate(ca1Block.Bytes)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		pemData     []byte
+		wantStatus  *v1alpha1.OIDCCertificateAuthorityStatus
+		wantSkipped []int
+		wantErr     string
+	}{
+		{
+			name:    "bundle with multiple certificates",
+			pemData: bytes.Join([][]byte{ca1.Bundle(), ca2.Bundle()}, nil),
+			wantStatus: &v1alpha1.OIDCCertificateAuthorityStatus{
+				Subject:      "CN=first-ca",
+				Issuer:       "CN=first-ca",
+				NotAfter:     metav1.NewTime(ca1Cert.NotAfter),
+				Certificates: 2,
 			},
-			wantResultingCache: []*oidctestutil.TestUpstreamOIDCIdentityProvider{},
-			wantResultingUpstreams: []v1alpha1.OIDCIdentityProvider{{
-				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
-				Status: v1alpha1.OIDCIdentityProviderStatus{
-					Phase: "Error",
-					Conditions: []v1alpha1.Condition{
-						happyAdditionalAuthorizeParametersValidCondition,
-						{
-							Type:               "ClientCredentialsValid",
-							Status:             "True",
-							LastTransitionTime: now,
-							Reason:             "Success",
-							Message:            "loaded client credentials",
-						},
-						{
-							Type:               "OIDCDiscoverySucceeded",
-							Status:             "False",
-							LastTransitionTime: now,
-							Reason:             "Unreachable",
-							Message: `failed to perform OIDC discovery against "` + testIssuerURL + `/":
-oidc: issuer did not match the issuer returned by provider, expected "` + testIssuerURL + `/" got "` + testIssuerURL + `"`,
-						},
-					},
-				},
-			}},
+		},
+		{
+			name:    "bundle with multiple certificates and another PEM block",
+			pemData: bytes.Join([][]byte{privateKeyPEM, ca1.Bundle(), ca2.Bundle()}, nil),
+			wantStatus: &v1alpha1.OIDCCertificateAuthorityStatus{
+				Subject:      "CN=first-ca",
+				Issuer:       "CN=first-ca",
+				NotAfter:     metav1.NewTime(ca1Cert.NotAfter),
+				Certificates: 2,
+			},
+			wantSkipped: []int{1},
+		},
+		{
+			name: "bundle with an invalid certificate between valid certificates",
+			pemData: bytes.Join([][]byte{
+				ca1.Bundle(),
+				pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("not-a-certificate")}),
+				ca2.Bundle(),
+				pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Headers: map[string]string{"foo": "bar"}, Bytes: ca1Block.Bytes}),
+			}, nil),
+			wantStatus: &v1alpha1.OIDCCertificateAuthorityStatus{
+				Subject:      "CN=first-ca",
+				Issuer:       "CN=first-ca",
+				NotAfter:     metav1.NewTime(ca1Cert.NotAfter),
+				Certificates: 2,
+			},
+			wantSkipped: []int{2, 4},
+		},
+		{
+			name:    "bundle without any PEM blocks",
+			pemData: []byte("not-a-pem-ca-bundle"),
+			wantErr: "spec.certificateAuthorityData is invalid: no certificates found (found 0 PEM blocks)",
+		},
+		{
+			name:    "bundle without any certificates",
+			pemData: bytes.Join([][]byte{privateKeyPEM, privateKeyPEM}, nil),
+			wantErr: "spec.certificateAuthorityData is invalid: no certificates found (found 2 PEM blocks)",
 		},
 	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			fakePinnipedClient := pinnipedfake.NewSimpleClientset(tt.inputUpstreams...)
-			pinnipedInformers := pinnipedinformers.NewSharedInformerFactory(fakePinnipedClient, 0)
-			fakeKubeClient := fake.NewSimpleClientset(tt.inputSecrets...)
-			kubeInformers := informers.NewSharedInformerFactory(fakeKubeClient, 0)
-			testLog := testlogger.NewLegacy(t) //nolint: staticcheck  // old test with lots of log statements
-			cache := provider.NewDynamicUpstreamIDPProvider()
-			cache.SetOIDCIdentityProviders([]provider.UpstreamOIDCIdentityProviderI{
-				&upstreamoidc.ProviderConfig{Name: "initial-entry"},
-			})
+			rootCAs, status, skipped, err := parseCertificateAuthorityData("spec.certificateAuthorityData", base64.StdEncoding.EncodeToString(tt.pemData))
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				require.Nil(t, rootCAs)
+				require.Nil(t, status)
+				require.Nil(t, skipped)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, rootCAs)
+			require.Equal(t, tt.wantStatus, status)
+			require.Equal(t, tt.wantSkipped, skipped)
+		})
+	}
+}
 
-			controller := New(
-				cache,
-				fakePinnipedClient,
-				pinnipedInformers.IDP().V1alpha1().OIDCIdentityProviders(),
-				kubeInformers.Core().V1().Secrets(),
-				testLog.Logger,
-				controllerlib.WithInformer,
-			)
+func TestComputeScopes(t *testing.T) {
+	t.Parallel()
 
-			ctx, cancel := context.WithCancel(context.Background())
-			defer cancel()
+	tests := []struct {
+		name             string
+		additionalScopes []string
+		replaceScopes    []string
+		want             []string
+	}{
+		{name: "defaults", want: []string{"openid", "offline_access", "email", "profile"}},
+		{name: "additional scopes", additionalScopes: []string{"groups", "email"}, want: []string{"email", "groups", "openid"}},
+		{name: "replaced scopes", replaceScopes: []string{"groups", "email"}, want: []string{"openid", "groups", "email"}},
+		{name: "replaced scopes including openid", replaceScopes: []string{"email", "openid"}, want: []string{"email", "openid"}},
+		{name: "replaced scopes take precedence", additionalScopes: []string{"profile"}, replaceScopes: []string{"email"}, want: []string{"openid", "email"}},
+		{name: "empty replaced scopes", additionalScopes: []string{"profile"}, replaceScopes: []string{}, want: []string{"openid", "profile"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, computeScopes(tt.additionalScopes, tt.replaceScopes))
+		})
+	}
+}
 
-			pinnipedInformers.Start(ctx.Done())
-			kubeInformers.Start(ctx.Done())
-			controllerlib.TestRunSynchronously(t, controller)
+func TestValidateScopesRejectsEmptyReplaceScopes(t *testing.T) {
+	t.Parallel()
 
-			syncCtx := controllerlib.Context{Context: ctx, Key: controllerlib.Key{}}
+	upstream := &v1alpha1.OIDCIdentityProvider{Spec: v1alpha1.OIDCIdentityProviderSpec{
+		AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{ReplaceScopes: []string{}},
+	}}
+	require.Equal(t, &v1alpha1.Condition{
+		Type:    "ScopesSupported",
+		Status:  "False",
+		Reason:  "InvalidScopes",
+		Message: "replaceScopes must not be empty when it is set",
+	}, validateScopes(upstream, &upstreamoidc.ProviderConfig{}))
+}
 
-			if err := controllerlib.TestSync(t, controller, syncCtx); tt.wantErr != "" {
-				require.EqualError(t, err, tt.wantErr)
-			} else {
-				require.NoError(t, err)
-			}
-			require.Equal(t, strings.Join(tt.wantLogs, "\n"), strings.Join(testLog.Lines(), "\n"))
+func TestLRUValidatorCacheEviction(t *testing.T) {
+	t.Parallel()
 
-			actualIDPList := cache.GetOIDCIdentityProviders()
-			require.Equal(t, len(tt.wantResultingCache), len(actualIDPList))
-			for i := range actualIDPList {
-				actualIDP := actualIDPList[i].(*upstreamoidc.ProviderConfig)
-				require.Equal(t, tt.wantResultingCache[i].GetName(), actualIDP.GetName())
-				require.Equal(t, tt.wantResultingCache[i].GetClientID(), actualIDP.GetClientID())
-				require.Equal(t, tt.wantResultingCache[i].GetAuthorizationURL().String(), actualIDP.GetAuthorizationURL().String())
-				require.Equal(t, tt.wantResultingCache[i].GetUsernameClaim(), actualIDP.GetUsernameClaim())
-				require.Equal(t, tt.wantResultingCache[i].GetGroupsClaim(), actualIDP.GetGroupsClaim())
-				require.Equal(t, tt.wantResultingCache[i].AllowsPasswordGrant(), actualIDP.AllowsPasswordGrant())
-				require.Equal(t, tt.wantResultingCache[i].GetAdditionalAuthcodeParams(), actualIDP.GetAdditionalAuthcodeParams())
-				require.Equal(t, tt.wantResultingCache[i].GetResourceUID(), actualIDP.GetResourceUID())
-				require.Equal(t, tt.wantResultingCache[i].GetRevocationURL(), actualIDP.GetRevocationURL())
-				require.ElementsMatch(t, tt.wantResultingCache[i].GetScopes(), actualIDP.GetScopes())
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	validatorCache := newLRUValidatorCache(2, fakeClock)
 
-				// We always want to use the proxy from env on these clients, so although the following assertions
-				// are a little hacky, this is a cheap way to test that we are using it.
-				actualTransport := unwrapTransport(t, actualIDP.Client.Transport)
-				httpProxyFromEnvFunction := reflect.ValueOf(http.ProxyFromEnvironment).Pointer()
-				actualTransportProxyFunction := reflect.ValueOf(actualTransport.Proxy).Pointer()
-				require.Equal(t, httpProxyFromEnvFunction, actualTransportProxyFunction,
-					"Transport should have used http.ProxyFromEnvironment as its Proxy func")
-				// We also want a reasonable timeout on each request/response cycle for OIDC discovery and JWKS.
-				require.Equal(t, time.Minute, actualIDP.Client.Timeout)
-			}
+	spec1 := &v1alpha1.OIDCIdentityProviderSpec{Issuer: "https://issuer1.example.com"}
+	spec2 := &v1alpha1.OIDCIdentityProviderSpec{Issuer: "https://issuer2.example.com"}
+	spec3 := &v1alpha1.OIDCIdentityProviderSpec{Issuer: "https://issuer3.example.com"}
+	provider1, provider2, provider3 := &oidc.Provider{}, &oidc.Provider{}, &oidc.Provider{}
+	client1, client2, client3 := &http.Client{}, &http.Client{}, &http.Client{}
 
-			actualUpstreams, err := fakePinnipedClient.IDPV1alpha1().OIDCIdentityProviders(testNamespace).List(ctx, metav1.ListOptions{})
-			require.NoError(t, err)
+	requireCached := func(spec *v1alpha1.OIDCIdentityProviderSpec, wantProvider *oidc.Provider, wantClient *http.Client) {
+		t.Helper()
+		gotProvider, gotClient := validatorCache.getProvider(spec, "")
+		require.Same(t, wantProvider, gotProvider)
+		require.Same(t, wantClient, gotClient)
+	}
+	requireNotCached := func(spec *v1alpha1.OIDCIdentityProviderSpec) {
+		t.Helper()
+		gotProvider, gotClient := validatorCache.getProvider(spec, "")
+		require.Nil(t, gotProvider)
+		require.Nil(t, gotClient)
+	}
 
-			// Assert on the expected Status of the upstreams. Preprocess the upstreams a bit so that they're easier to assert against.
-			require.ElementsMatch(t, tt.wantResultingUpstreams, normalizeOIDCUpstreams(actualUpstreams.Items, now))
+	validatorCache.putProvider(spec1, provider1, client1, "")
+	validatorCache.putProvider(spec2, provider2, client2, "")
+	requireCached(spec1, provider1, client1)
+	requireCached(spec2, provider2, client2)
 
-			// Running the sync() a second time should be idempotent except for logs, and should return the same error.
-			// This also helps exercise code paths where the OIDC provider discovery hits cache.
-			if err := controllerlib.TestSync(t, controller, syncCtx); tt.wantErr != "" {
-				require.EqualError(t, err, tt.wantErr)
-			} else {
-				require.NoError(t, err)
-			}
-		})
+	// Using spec1 makes spec2 the least recently used entry, so it is the one evicted when the cache is full.
+	requireCached(spec1, provider1, client1)
+	validatorCache.putProvider(spec3, provider3, client3, "")
+	requireNotCached(spec2)
+	requireCached(spec1, provider1, client1)
+	requireCached(spec3, provider3, client3)
+
+	// An entry which was built with a different version of the client certificate Secret is not returned.
+	gotProvider, gotClient := validatorCache.getProvider(spec1, "some-other-version")
+	require.Nil(t, gotProvider)
+	require.Nil(t, gotClient)
+
+	// Entries still expire after the TTL, even when the cache is not full.
+	fakeClock.Step(oidcValidatorCacheTTL + time.Second)
+	requireNotCached(spec1)
+	requireNotCached(spec3)
+}
+
+func TestLRUValidatorCacheJWKSProvider(t *testing.T) {
+	t.Parallel()
+
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	validatorCache := newLRUValidatorCache(2, fakeClock)
+
+	spec := &v1alpha1.OIDCIdentityProviderSpec{
+		Issuer: "https://issuer.example.com",
+		TLS:    &v1alpha1.OIDCTLSSpec{JWKSCertificateAuthorityData: "some-jwks-ca-bundle"},
+	}
+	const jwksURL = "https://jwks.example.com/jwks.json"
+	discoveredProvider, client := &oidc.Provider{}, &http.Client{}
+	jwksProvider, jwksClient := &separateJWKSProvider{upstreamProvider: discoveredProvider}, &http.Client{}
+
+	requireNotCached := func(spec *v1alpha1.OIDCIdentityProviderSpec, clientCertVersion, jwksURL string) {
+		t.Helper()
+		gotProvider, gotClient := validatorCache.getJWKSProvider(spec, clientCertVersion, jwksURL)
+		require.Nil(t, gotProvider)
+		require.Nil(t, gotClient)
+	}
+
+	// Nothing is cached without a cached entry for the discovered provider.
+	validatorCache.putJWKSProvider(spec, "", jwksURL, jwksProvider, jwksClient)
+	requireNotCached(spec, "", jwksURL)
+
+	// Nothing is cached for a JWKS provider which wraps a different discovered provider.
+	validatorCache.putProvider(spec, discoveredProvider, client, "")
+	validatorCache.putJWKSProvider(spec, "", jwksURL, &separateJWKSProvider{upstreamProvider: &oidc.Provider{}}, jwksClient)
+	requireNotCached(spec, "", jwksURL)
+
+	validatorCache.putJWKSProvider(spec, "", jwksURL, jwksProvider, jwksClient)
+	gotProvider, gotClient := validatorCache.getJWKSProvider(spec, "", jwksURL)
+	require.Same(t, jwksProvider, gotProvider)
+	require.Same(t, jwksClient, gotClient)
+
+	// The discovered provider is still cached alongside it.
+	gotDiscoveredProvider, gotDiscoveredClient := validatorCache.getProvider(spec, "")
+	require.Same(t, discoveredProvider, gotDiscoveredProvider)
+	require.Same(t, client, gotDiscoveredClient)
+
+	// A different JWKS URL, JWKS CA bundle, or version of the client certificate Secret is not a cache hit.
+	requireNotCached(spec, "", "https://jwks.example.com/other-jwks.json")
+	requireNotCached(spec, "some-other-version", jwksURL)
+	otherSpec := spec.DeepCopy()
+	otherSpec.TLS.JWKSCertificateAuthorityData = "some-other-jwks-ca-bundle"
+	requireNotCached(otherSpec, "", jwksURL)
+
+	// The JWKS provider expires with the discovered provider.
+	fakeClock.Step(oidcValidatorCacheTTL + time.Second)
+	requireNotCached(spec, "", jwksURL)
+}
+
+func TestJWKSCacheEviction(t *testing.T) {
+	t.Parallel()
+
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	keysCache := newJWKSCache(2, fakeClock)
+	spec := &v1alpha1.OIDCIdentityProviderSpec{Issuer: "https://issuer.example.com"}
+
+	keysCache.putUsableKeys(spec, "https://issuer.example.com/jwks1.json")
+	keysCache.putUsableKeys(spec, "https://issuer.example.com/jwks2.json")
+	require.True(t, keysCache.hasUsableKeys(spec, "https://issuer.example.com/jwks1.json"))
+
+	keysCache.putUsableKeys(spec, "https://issuer.example.com/jwks3.json")
+	require.True(t, keysCache.hasUsableKeys(spec, "https://issuer.example.com/jwks1.json"))
+	require.False(t, keysCache.hasUsableKeys(spec, "https://issuer.example.com/jwks2.json"))
+	require.True(t, keysCache.hasUsableKeys(spec, "https://issuer.example.com/jwks3.json"))
+
+	fakeClock.Step(oidcValidatorCacheTTL + time.Second)
+	require.False(t, keysCache.hasUsableKeys(spec, "https://issuer.example.com/jwks1.json"))
+	require.False(t, keysCache.hasUsableKeys(spec, "https://issuer.example.com/jwks3.json"))
+}
+
+// requireRequeuedAfter asserts that the sync asked to be requeued after the given delay plus some jitter,
+// or that it did not ask to be requeued when the given delay is zero.
+func requireRequeuedAfter(t *testing.T, queue *testQueue, delay time.Duration) {
+	t.Helper()
+	if delay == 0 {
+		require.False(t, queue.called)
+		return
 	}
+	require.True(t, queue.called)
+	require.Equal(t, controllerlib.Key{}, queue.key)
+	require.GreaterOrEqual(t, queue.duration, delay)
+	require.LessOrEqual(t, queue.duration, time.Duration(float64(delay)*(1+discoveryBackoffJitter)))
+}
+
+type testQueue struct {
+	t *testing.T
+
+	called   bool
+	key      controllerlib.Key
+	duration time.Duration
+
+	controllerlib.Queue // panic if any other methods called
+}
+
+func (q *testQueue) AddAfter(key controllerlib.Key, duration time.Duration) {
+	q.t.Helper()
+
+	require.False(q.t, q.called, "AddAfter should only be called once")
+
+	q.called = true
+	q.key = key
+	q.duration = duration
 }
 
 func unwrapTransport(t *testing.T, rt http.RoundTripper) *http.Transport {
@@ -1490,6 +5849,14 @@ func unwrapTransport(t *testing.T, rt http.RoundTripper) *http.Transport {
 	}
 }
 
+func sha256Hex(t *testing.T, v interface{}) string {
+	t.Helper()
+	canonicalJSON, err := json.Marshal(v)
+	require.NoError(t, err)
+	hash := sha256.Sum256(canonicalJSON)
+	return hex.EncodeToString(hash[:])
+}
+
 func normalizeOIDCUpstreams(upstreams []v1alpha1.OIDCIdentityProvider, now metav1.Time) []v1alpha1.OIDCIdentityProvider {
 	result := make([]v1alpha1.OIDCIdentityProvider, 0, len(upstreams))
 	for _, u := range upstreams {
@@ -1516,13 +5883,30 @@ func newTestIssuer(t *testing.T) (string, string) {
 	caBundlePEM, testURL := testutil.TLSTestServer(t, mux.ServeHTTP)
 
 	type providerJSON struct {
-		Issuer        string `json:"issuer"`
-		AuthURL       string `json:"authorization_endpoint"`
-		TokenURL      string `json:"token_endpoint"`
-		RevocationURL string `json:"revocation_endpoint,omitempty"`
-		JWKSURL       string `json:"jwks_uri"`
+		Issuer        string   `json:"issuer"`
+		AuthURL       string   `json:"authorization_endpoint"`
+		TokenURL      string   `json:"token_endpoint"`
+		RevocationURL string   `json:"revocation_endpoint,omitempty"`
+		JWKSURL       string   `json:"jwks_uri"`
+		Scopes        []string `json:"scopes_supported,omitempty"`
+		GrantTypes    []string `json:"grant_types_supported,omitempty"`
+	}
+
+	signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serveJWKS := func(path string, key jose.JSONWebKey) {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("content-type", "application/json")
+			_ = json.NewEncoder(w).Encode(&jose.JSONWebKeySet{Keys: []jose.JSONWebKey{key}})
+		})
 	}
 
+	// At "/jwks.json", serve a JWKS with a usable signing key.
+	serveJWKS("/jwks.json", jose.JSONWebKey{Key: signingKey.Public(), KeyID: "test-kid", Algorithm: "ES256", Use: "sig"})
+
+	// At "/encryption-jwks.json", serve a JWKS which only contains a key which cannot be used for signatures.
+	serveJWKS("/encryption-jwks.json", jose.JSONWebKey{Key: signingKey.Public(), KeyID: "test-kid", Algorithm: "ES256", Use: "enc"})
+
 	// At the root of the server, serve an issuer with a valid discovery response.
 	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("content-type", "application/json")
@@ -1531,6 +5915,68 @@ func newTestIssuer(t *testing.T) (string, string) {
 			AuthURL:       "https://example.com/authorize",
 			RevocationURL: "https://example.com/revoke",
 			TokenURL:      "https://example.com/token",
+			JWKSURL:       testURL + "/jwks.json",
+		})
+	})
+
+	// At "/scopes-supported", serve an issuer which advertises the scopes that it supports, not including "offline_access".
+	mux.HandleFunc("/scopes-supported/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		_ = json.NewEncoder(w).Encode(&providerJSON{
+			Issuer:   testURL + "/scopes-supported",
+			AuthURL:  "https://example.com/authorize",
+			TokenURL: "https://example.com/token",
+			JWKSURL:  testURL + "/jwks.json",
+			Scopes:   []string{"openid", "email", "profile"},
+		})
+	})
+
+	// At "/refresh-supported", serve an issuer which advertises support for refresh tokens.
+	mux.HandleFunc("/refresh-supported/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		_ = json.NewEncoder(w).Encode(&providerJSON{
+			Issuer:     testURL + "/refresh-supported",
+			AuthURL:    "https://example.com/authorize",
+			TokenURL:   "https://example.com/token",
+			JWKSURL:    testURL + "/jwks.json",
+			Scopes:     []string{"openid", "offline_access", "email", "profile"},
+			GrantTypes: []string{"authorization_code", "refresh_token"},
+		})
+	})
+
+	// At "/refresh-not-supported", serve an issuer which advertises neither the "refresh_token" grant type nor the
+	// "offline_access" scope.
+	mux.HandleFunc("/refresh-not-supported/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		_ = json.NewEncoder(w).Encode(&providerJSON{
+			Issuer:     testURL + "/refresh-not-supported",
+			AuthURL:    "https://example.com/authorize",
+			TokenURL:   "https://example.com/token",
+			JWKSURL:    testURL + "/jwks.json",
+			Scopes:     []string{"openid", "email", "profile"},
+			GrantTypes: []string{"authorization_code"},
+		})
+	})
+
+	// At "/jwks-not-found", serve an issuer whose JWKS endpoint does not exist.
+	mux.HandleFunc("/jwks-not-found/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		_ = json.NewEncoder(w).Encode(&providerJSON{
+			Issuer:   testURL + "/jwks-not-found",
+			AuthURL:  "https://example.com/authorize",
+			TokenURL: "https://example.com/token",
+			JWKSURL:  testURL + "/jwks-not-found/jwks.json",
+		})
+	})
+
+	// At "/jwks-without-signing-keys", serve an issuer whose JWKS does not contain any usable signing keys.
+	mux.HandleFunc("/jwks-without-signing-keys/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		_ = json.NewEncoder(w).Encode(&providerJSON{
+			Issuer:   testURL + "/jwks-without-signing-keys",
+			AuthURL:  "https://example.com/authorize",
+			TokenURL: "https://example.com/token",
+			JWKSURL:  testURL + "/encryption-jwks.json",
 		})
 	})
 
@@ -1542,6 +5988,7 @@ func newTestIssuer(t *testing.T) (string, string) {
 			AuthURL:       "https://example.com/authorize",
 			RevocationURL: "", // none
 			TokenURL:      "https://example.com/token",
+			JWKSURL:       testURL + "/jwks.json",
 		})
 	})
 
@@ -1632,6 +6079,7 @@ func newTestIssuer(t *testing.T) (string, string) {
 			AuthURL:       "https://example.com/authorize",
 			RevocationURL: "https://example.com/revoke",
 			TokenURL:      "https://example.com/token",
+			JWKSURL:       testURL + "/jwks.json",
 		})
 	})
 
@@ -1643,3 +6091,132 @@ func newTestIssuer(t *testing.T) (string, string) {
 
 	return caBundlePEM, testURL
 }
+
+func TestValidateClientSecretExpiry(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := &oidcWatcherController{clock: clocktesting.NewFakeClock(now)}
+
+	tests := []struct {
+		name               string
+		annotations        map[string]string
+		existingConditions []v1alpha1.Condition
+		want               *v1alpha1.Condition
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name:               "annotation was removed since the last sync",
+			existingConditions: []v1alpha1.Condition{{Type: "ClientSecretNotExpiring", Status: "Unknown", Reason: "ClientSecretExpiring"}},
+			want: &v1alpha1.Condition{Type: "ClientSecretNotExpiring", Status: "True", Reason: "Success",
+				Message: `referenced Secret "some-secret" does not have a "secrets.pinniped.dev/client-secret-expires-at" annotation`},
+		},
+		{
+			name:        "expires outside of the default warning window",
+			annotations: map[string]string{"secrets.pinniped.dev/client-secret-expires-at": "2022-03-09T12:00:00Z"},
+			want:        &v1alpha1.Condition{Type: "ClientSecretNotExpiring", Status: "True", Reason: "Success", Message: "client secret expires at 2022-03-09T12:00:00Z"},
+		},
+		{
+			name:        "expires within the default warning window",
+			annotations: map[string]string{"secrets.pinniped.dev/client-secret-expires-at": "2022-03-08T12:00:00Z"},
+			want: &v1alpha1.Condition{Type: "ClientSecretNotExpiring", Status: "Unknown", Reason: "ClientSecretExpiring",
+				Message: "client secret expires at 2022-03-08T12:00:00Z, so it should be rotated soon"},
+		},
+		{
+			name: "expires outside of a custom warning window",
+			annotations: map[string]string{
+				"secrets.pinniped.dev/client-secret-expires-at":            "2022-03-02T12:00:00+02:00",
+				"secrets.pinniped.dev/client-secret-expiry-warning-window": "12h",
+			},
+			want: &v1alpha1.Condition{Type: "ClientSecretNotExpiring", Status: "True", Reason: "Success", Message: "client secret expires at 2022-03-02T10:00:00Z"},
+		},
+		{
+			name:        "already expired",
+			annotations: map[string]string{"secrets.pinniped.dev/client-secret-expires-at": "2022-03-01T11:00:00Z"},
+			want: &v1alpha1.Condition{Type: "ClientSecretNotExpiring", Status: "Unknown", Reason: "ClientSecretExpired",
+				Message: "client secret expired at 2022-03-01T11:00:00Z, so logins will fail once the OIDC provider rejects it"},
+		},
+		{
+			name:        "invalid expiry timestamp",
+			annotations: map[string]string{"secrets.pinniped.dev/client-secret-expires-at": "next tuesday"},
+			want: &v1alpha1.Condition{Type: "ClientSecretNotExpiring", Status: "Unknown", Reason: "InvalidExpiryAnnotation",
+				Message: `referenced Secret "some-secret" has invalid "secrets.pinniped.dev/client-secret-expires-at" annotation "next tuesday" (expected an RFC 3339 timestamp)`},
+		},
+		{
+			name: "invalid warning window",
+			annotations: map[string]string{
+				"secrets.pinniped.dev/client-secret-expires-at":            "2022-03-09T12:00:00Z",
+				"secrets.pinniped.dev/client-secret-expiry-warning-window": "-1h",
+			},
+			want: &v1alpha1.Condition{Type: "ClientSecretNotExpiring", Status: "Unknown", Reason: "InvalidExpiryAnnotation",
+				Message: `referenced Secret "some-secret" has invalid "secrets.pinniped.dev/client-secret-expiry-warning-window" annotation "-1h" (expected a non-negative duration, e.g. 168h)`},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			upstream := &v1alpha1.OIDCIdentityProvider{Status: v1alpha1.OIDCIdentityProviderStatus{Conditions: tt.existingConditions}}
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "some-secret", Annotations: tt.annotations}}
+			require.Equal(t, tt.want, c.validateClientSecretExpiry(upstream, secret))
+		})
+	}
+}
+
+func TestValidatePasswordGrantSupport(t *testing.T) {
+	t.Parallel()
+	discovered := func(grantTypes ...string) *upstreamoidc.ProviderConfig {
+		rawClaims, err := json.Marshal(map[string]interface{}{"grant_types_supported": grantTypes})
+		require.NoError(t, err)
+		return &upstreamoidc.ProviderConfig{AllowPasswordGrant: true, Provider: &manualProvider{rawClaims: rawClaims}}
+	}
+
+	tests := []struct {
+		name               string
+		result             *upstreamoidc.ProviderConfig
+		existingConditions []v1alpha1.Condition
+		want               *v1alpha1.Condition
+	}{
+		{
+			name:   "password grant is not allowed",
+			result: &upstreamoidc.ProviderConfig{},
+		},
+		{
+			name:               "password grant was disallowed since the last sync",
+			result:             &upstreamoidc.ProviderConfig{},
+			existingConditions: []v1alpha1.Condition{{Type: "PasswordGrantSupported", Status: "False", Reason: "PasswordGrantNotAdvertised"}},
+			want:               &v1alpha1.Condition{Type: "PasswordGrantSupported", Status: "True", Reason: "Success", Message: "password grant is not allowed by allowPasswordGrant"},
+		},
+		{
+			name:   "discovery failed",
+			result: &upstreamoidc.ProviderConfig{AllowPasswordGrant: true},
+			want: &v1alpha1.Condition{Type: "PasswordGrantSupported", Status: "Unknown", Reason: "OIDCDiscoveryFailed",
+				Message: "cannot check password grant support until OIDC discovery succeeds"},
+		},
+		{
+			name:   "grant types are not advertised",
+			result: discovered(),
+			want:   &v1alpha1.Condition{Type: "PasswordGrantSupported", Status: "True", Reason: "Success", Message: "OIDC provider does not advertise its supported grant types"},
+		},
+		{
+			name:   "password grant is advertised",
+			result: discovered("authorization_code", "password"),
+			want:   &v1alpha1.Condition{Type: "PasswordGrantSupported", Status: "True", Reason: "Success", Message: "OIDC provider advertises support for the password grant"},
+		},
+		{
+			name:   "password grant is not advertised",
+			result: discovered("authorization_code", "refresh_token"),
+			want: &v1alpha1.Condition{Type: "PasswordGrantSupported", Status: "False", Reason: "PasswordGrantNotAdvertised",
+				Message: `allowPasswordGrant is true but the OIDC provider does not advertise support for the password grant (grant_types_supported does not include "password"), so password logins may be rejected by the OIDC provider`},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			upstream := &v1alpha1.OIDCIdentityProvider{Status: v1alpha1.OIDCIdentityProviderStatus{Conditions: tt.existingConditions}}
+			require.Equal(t, tt.want, validatePasswordGrantSupport(upstream, tt.result))
+		})
+	}
+}
@@ -5,6 +5,7 @@ package supervisorconfig
 
 import (
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net/url"
 	"strings"
@@ -12,6 +13,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	corev1informers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/utils/clock"
 
 	"go.pinniped.dev/generated/latest/client/supervisor/informers/externalversions/config/v1alpha1"
 	pinnipedcontroller "go.pinniped.dev/internal/controller"
@@ -20,10 +22,11 @@ import (
 )
 
 type tlsCertObserverController struct {
-	issuerTLSCertSetter             IssuerTLSCertSetter
-	defaultTLSCertificateSecretName string
-	federationDomainInformer        v1alpha1.FederationDomainInformer
-	secretInformer                  corev1informers.SecretInformer
+	issuerTLSCertSetter              IssuerTLSCertSetter
+	defaultTLSCertificateSecretNames []string
+	clock                            clock.Clock
+	federationDomainInformer         v1alpha1.FederationDomainInformer
+	secretInformer                   corev1informers.SecretInformer
 }
 
 type IssuerTLSCertSetter interface {
@@ -33,7 +36,8 @@ type IssuerTLSCertSetter interface {
 
 func NewTLSCertObserverController(
 	issuerTLSCertSetter IssuerTLSCertSetter,
-	defaultTLSCertificateSecretName string,
+	defaultTLSCertificateSecretNames []string,
+	clock clock.Clock,
 	secretInformer corev1informers.SecretInformer,
 	federationDomainInformer v1alpha1.FederationDomainInformer,
 	withInformer pinnipedcontroller.WithInformerOptionFunc,
@@ -42,10 +46,11 @@ func NewTLSCertObserverController(
 		controllerlib.Config{
 			Name: "tls-certs-observer-controller",
 			Syncer: &tlsCertObserverController{
-				issuerTLSCertSetter:             issuerTLSCertSetter,
-				defaultTLSCertificateSecretName: defaultTLSCertificateSecretName,
-				federationDomainInformer:        federationDomainInformer,
-				secretInformer:                  secretInformer,
+				issuerTLSCertSetter:              issuerTLSCertSetter,
+				defaultTLSCertificateSecretNames: defaultTLSCertificateSecretNames,
+				clock:                            clock,
+				federationDomainInformer:         federationDomainInformer,
+				secretInformer:                   secretInformer,
 			},
 		},
 		withInformer(
@@ -93,12 +98,22 @@ func (c *tlsCertObserverController) Sync(ctx controllerlib.Context) error {
 	plog.Debug("tlsCertObserverController Sync updated the TLS cert cache", "issuerHostCount", len(issuerHostToTLSCertMap))
 	c.issuerTLSCertSetter.SetIssuerHostToTLSCertMap(issuerHostToTLSCertMap)
 
-	defaultCert, err := c.certFromSecret(ns, c.defaultTLSCertificateSecretName)
-	if err != nil {
-		c.issuerTLSCertSetter.SetDefaultTLSCert(nil)
-	} else {
-		c.issuerTLSCertSetter.SetDefaultTLSCert(defaultCert)
+	// Use the first of the default TLS cert secrets which holds a currently valid cert, so a fallback can take
+	// over while the primary secret is missing, invalid, or expired, e.g. during a cert migration.
+	var defaultCert *tls.Certificate
+	for _, secretName := range c.defaultTLSCertificateSecretNames {
+		cert, err := c.certFromSecret(ns, secretName)
+		if err != nil {
+			continue
+		}
+		if err := c.validateCertIsCurrentlyValid(cert); err != nil {
+			plog.Debug("tlsCertObserverController Sync skipped a default TLS secret", "namespace", ns, "secretName", secretName, "reason", err.Error())
+			continue
+		}
+		defaultCert = cert
+		break
 	}
+	c.issuerTLSCertSetter.SetDefaultTLSCert(defaultCert)
 
 	return nil
 }
@@ -117,6 +132,21 @@ func (c *tlsCertObserverController) certFromSecret(ns string, secretName string)
 	return &certFromSecret, nil
 }
 
+func (c *tlsCertObserverController) validateCertIsCurrentlyValid(cert *tls.Certificate) error {
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return err
+	}
+	now := c.clock.Now()
+	if now.Before(leaf.NotBefore) {
+		return fmt.Errorf("certificate is not valid until %s", leaf.NotBefore)
+	}
+	if now.After(leaf.NotAfter) {
+		return fmt.Errorf("certificate expired at %s", leaf.NotAfter)
+	}
+	return nil
+}
+
 func lowercaseHostWithoutPort(issuerURL *url.URL) string {
 	lowercaseHost := strings.ToLower(issuerURL.Hostname())
 	return lowercaseHost
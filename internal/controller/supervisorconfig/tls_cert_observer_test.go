@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
@@ -17,6 +18,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubeinformers "k8s.io/client-go/informers"
 	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+	clocktesting "k8s.io/utils/clock/testing"
 
 	"go.pinniped.dev/generated/latest/apis/supervisor/config/v1alpha1"
 	pinnipedfake "go.pinniped.dev/generated/latest/client/supervisor/clientset/versioned/fake"
@@ -41,7 +43,8 @@ func TestTLSCertObserverControllerInformerFilters(t *testing.T) {
 			federationDomainInformer := pinnipedinformers.NewSharedInformerFactory(nil, 0).Config().V1alpha1().FederationDomains()
 			_ = NewTLSCertObserverController(
 				nil,
-				"", // don't care about the secret name for this test
+				nil, // don't care about the secret names for this test
+				nil, // don't care about the clock for this test
 				secretsInformer,
 				federationDomainInformer,
 				observableWithInformerOption.WithInformer, // make it possible to observe the behavior of the Filters
@@ -124,8 +127,9 @@ func (f *fakeIssuerTLSCertSetter) SetDefaultTLSCert(certificate *tls.Certificate
 func TestTLSCertObserverControllerSync(t *testing.T) {
 	spec.Run(t, "Sync", func(t *testing.T, when spec.G, it spec.S) {
 		const (
-			installedInNamespace = "some-namespace"
-			defaultTLSSecretName = "some-default-secret-name"
+			installedInNamespace  = "some-namespace"
+			defaultTLSSecretName  = "some-default-secret-name"
+			fallbackTLSSecretName = "some-fallback-secret-name"
 		)
 
 		var (
@@ -139,6 +143,7 @@ func TestTLSCertObserverControllerSync(t *testing.T) {
 			cancelContextCancelFunc context.CancelFunc
 			syncContext             *controllerlib.Context
 			issuerTLSCertSetter     *fakeIssuerTLSCertSetter
+			fakeClock               *clocktesting.FakeClock
 		)
 
 		// Defer starting the informers until the last possible moment so that the
@@ -147,7 +152,8 @@ func TestTLSCertObserverControllerSync(t *testing.T) {
 			// Set this at the last second to allow for injection of server override.
 			subject = NewTLSCertObserverController(
 				issuerTLSCertSetter,
-				defaultTLSSecretName,
+				[]string{defaultTLSSecretName, fallbackTLSSecretName},
+				fakeClock,
 				kubeInformers.Core().V1().Secrets(),
 				pinnipedInformers.Config().V1alpha1().FederationDomains(),
 				controllerlib.WithInformer,
@@ -185,6 +191,7 @@ func TestTLSCertObserverControllerSync(t *testing.T) {
 			pinnipedInformerClient = pinnipedfake.NewSimpleClientset()
 			pinnipedInformers = pinnipedinformers.NewSharedInformerFactory(pinnipedInformerClient, 0)
 			issuerTLSCertSetter = &fakeIssuerTLSCertSetter{}
+			fakeClock = clocktesting.NewFakeClock(time.Now())
 
 			unrelatedSecret := &corev1.Secret{
 				ObjectMeta: metav1.ObjectMeta{
@@ -384,6 +391,116 @@ func TestTLSCertObserverControllerSync(t *testing.T) {
 					r.True(issuerTLSCertSetter.setIssuerHostToTLSCertMapWasCalled)
 					r.Len(issuerTLSCertSetter.issuerHostToTLSCertMapReceived, 3)
 				})
+
+				when("there is also a fallback default TLS cert secret", func() {
+					it.Before(func() {
+						fallbackTLSCertSecret := &corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{Name: fallbackTLSSecretName, Namespace: installedInNamespace},
+							Data:       map[string][]byte{"tls.crt": readTestFile("testdata/test.crt"), "tls.key": readTestFile("testdata/test.key")},
+						}
+						r.NoError(kubeInformerClient.Tracker().Add(fallbackTLSCertSecret))
+					})
+
+					it("prefers the primary default certificate", func() {
+						startInformersAndController()
+						r.NoError(controllerlib.TestSync(t, subject, *syncContext))
+
+						r.True(issuerTLSCertSetter.setDefaultTLSCertWasCalled)
+						actualDefaultCertificate := issuerTLSCertSetter.setDefaultTLSCertReceived
+						r.NotNil(actualDefaultCertificate)
+						r.Equal(expectedDefaultCertificate, *actualDefaultCertificate)
+					})
+				})
+			})
+
+			when("there is only a fallback default TLS cert secret", func() {
+				var (
+					expectedFallbackCertificate tls.Certificate
+				)
+
+				it.Before(func() {
+					var err error
+					testCrt := readTestFile("testdata/test3.crt")
+					testKey := readTestFile("testdata/test3.key")
+					expectedFallbackCertificate, err = tls.X509KeyPair(testCrt, testKey)
+					r.NoError(err)
+					fallbackTLSCertSecret := &corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{Name: fallbackTLSSecretName, Namespace: installedInNamespace},
+						Data:       map[string][]byte{"tls.crt": testCrt, "tls.key": testKey},
+					}
+					r.NoError(kubeInformerClient.Tracker().Add(fallbackTLSCertSecret))
+				})
+
+				it("uses the fallback as the default certificate", func() {
+					startInformersAndController()
+					r.NoError(controllerlib.TestSync(t, subject, *syncContext))
+
+					r.True(issuerTLSCertSetter.setDefaultTLSCertWasCalled)
+					actualDefaultCertificate := issuerTLSCertSetter.setDefaultTLSCertReceived
+					r.NotNil(actualDefaultCertificate)
+					r.Equal(expectedFallbackCertificate, *actualDefaultCertificate)
+				})
+			})
+
+			when("the primary default TLS cert secret holds a cert which is not currently valid", func() {
+				var (
+					expectedFallbackCertificate tls.Certificate
+				)
+
+				it.Before(func() {
+					var err error
+					defaultTLSCertSecret := &corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{Name: defaultTLSSecretName, Namespace: installedInNamespace},
+						// This cert is valid from 2020-10-26 until 2021-10-26.
+						Data: map[string][]byte{"tls.crt": readTestFile("testdata/test.crt"), "tls.key": readTestFile("testdata/test.key")},
+					}
+					r.NoError(kubeInformerClient.Tracker().Add(defaultTLSCertSecret))
+					testCrt := readTestFile("testdata/test3.crt")
+					testKey := readTestFile("testdata/test3.key")
+					expectedFallbackCertificate, err = tls.X509KeyPair(testCrt, testKey)
+					r.NoError(err)
+					fallbackTLSCertSecret := &corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{Name: fallbackTLSSecretName, Namespace: installedInNamespace},
+						// This cert is valid from 2020-07-25 until 2030-07-23.
+						Data: map[string][]byte{"tls.crt": testCrt, "tls.key": testKey},
+					}
+					r.NoError(kubeInformerClient.Tracker().Add(fallbackTLSCertSecret))
+				})
+
+				it("uses the valid fallback as the default certificate when the primary cert has expired", func() {
+					fakeClock.SetTime(time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC))
+					startInformersAndController()
+					r.NoError(controllerlib.TestSync(t, subject, *syncContext))
+
+					r.True(issuerTLSCertSetter.setDefaultTLSCertWasCalled)
+					actualDefaultCertificate := issuerTLSCertSetter.setDefaultTLSCertReceived
+					r.NotNil(actualDefaultCertificate)
+					r.Equal(expectedFallbackCertificate, *actualDefaultCertificate)
+				})
+
+				it("uses the valid fallback as the default certificate when the primary cert is not valid yet", func() {
+					fakeClock.SetTime(time.Date(2020, time.September, 1, 0, 0, 0, 0, time.UTC))
+					startInformersAndController()
+					r.NoError(controllerlib.TestSync(t, subject, *syncContext))
+
+					r.True(issuerTLSCertSetter.setDefaultTLSCertWasCalled)
+					actualDefaultCertificate := issuerTLSCertSetter.setDefaultTLSCertReceived
+					r.NotNil(actualDefaultCertificate)
+					r.Equal(expectedFallbackCertificate, *actualDefaultCertificate)
+				})
+
+				it("uses the primary cert while it is valid", func() {
+					fakeClock.SetTime(time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC))
+					startInformersAndController()
+					r.NoError(controllerlib.TestSync(t, subject, *syncContext))
+
+					expectedDefaultCertificate, err := tls.X509KeyPair(readTestFile("testdata/test.crt"), readTestFile("testdata/test.key"))
+					r.NoError(err)
+					r.True(issuerTLSCertSetter.setDefaultTLSCertWasCalled)
+					actualDefaultCertificate := issuerTLSCertSetter.setDefaultTLSCertReceived
+					r.NotNil(actualDefaultCertificate)
+					r.Equal(expectedDefaultCertificate, *actualDefaultCertificate)
+				})
 			})
 		})
 	}, spec.Parallel(), spec.Report(report.Terminal{}))
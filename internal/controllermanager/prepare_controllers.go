@@ -7,10 +7,15 @@ package controllermanager
 
 import (
 	"fmt"
+	"net"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/labels"
 	k8sinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/component-base/metrics/legacyregistry"
 	"k8s.io/klog/v2/klogr"
 	"k8s.io/utils/clock"
 
@@ -62,6 +67,32 @@ type Config struct {
 	// ImpersonationProxyServerPort decides which port the impersonation proxy should bind.
 	ImpersonationProxyServerPort int
 
+	// ImpersonationProxyBindAddress optionally decides which address the impersonation proxy should bind.
+	// When nil, it listens on all interfaces.
+	ImpersonationProxyBindAddress net.IP
+
+	// ImpersonationProxyControlPlaneNodeSelectors optionally decide which nodes are considered to be control
+	// plane nodes when the impersonation proxy is in auto mode. A node is a control plane node when it matches
+	// any of them. When empty, the well-known node role labels are used.
+	ImpersonationProxyControlPlaneNodeSelectors []labels.Selector
+
+	// ImpersonationProxyHealthCheckPath decides on which path the impersonation proxy answers unauthenticated
+	// health checks, e.g. from cloud load balancers.
+	ImpersonationProxyHealthCheckPath string
+
+	// ImpersonationProxySetOwnerReferences decides whether the Services and Secrets created for the impersonation
+	// proxy are owned by the CredentialIssuer instead of by the Concierge Deployment, so that they are garbage
+	// collected when the CredentialIssuer is deleted.
+	ImpersonationProxySetOwnerReferences bool
+
+	// ImpersonationProxyAnnotateIssuedSecrets decides whether the CA and TLS Secrets generated for the impersonation
+	// proxy are annotated with when and why their certificates were issued, for auditing.
+	ImpersonationProxyAnnotateIssuedSecrets bool
+
+	// ImpersonationProxyListenerOptions decides how many client connections the impersonation proxy accepts at the
+	// same time, the keep-alive period of those connections, and the TLS versions and cipher suites which they may use.
+	ImpersonationProxyListenerOptions impersonator.ListenerOptions
+
 	// DiscoveryURLOverride allows a caller to inject a hardcoded discovery URL into Pinniped
 	// discovery document.
 	DiscoveryURLOverride *string
@@ -123,17 +154,34 @@ func PrepareControllers(c *Config) (controllerinit.RunnerBuilder, error) {
 	}
 
 	// Create informers. Don't forget to make sure they get started in the function returned below.
-	informers := createInformers(c.ServerInstallationInfo.Namespace, client.Kubernetes, client.PinnipedConcierge)
+	informers := createInformers(
+		c.ServerInstallationInfo.Namespace,
+		*c.KubeCertAgentConfig.ControllerManagerNamespace,
+		client.Kubernetes,
+		client.PinnipedConcierge,
+	)
 
 	agentConfig := kubecertagent.AgentConfig{
-		Namespace:                 c.ServerInstallationInfo.Namespace,
-		ServiceAccountName:        c.NamesConfig.AgentServiceAccount,
-		ContainerImage:            *c.KubeCertAgentConfig.Image,
-		NamePrefix:                *c.KubeCertAgentConfig.NamePrefix,
-		ContainerImagePullSecrets: c.KubeCertAgentConfig.ImagePullSecrets,
-		Labels:                    c.Labels,
-		CredentialIssuerName:      c.NamesConfig.CredentialIssuer,
-		DiscoveryURLOverride:      c.DiscoveryURLOverride,
+		Namespace:                         c.ServerInstallationInfo.Namespace,
+		ServiceAccountName:                c.NamesConfig.AgentServiceAccount,
+		ContainerImage:                    *c.KubeCertAgentConfig.Image,
+		NamePrefix:                        *c.KubeCertAgentConfig.NamePrefix,
+		ContainerImagePullSecrets:         c.KubeCertAgentConfig.ImagePullSecrets,
+		Labels:                            c.Labels,
+		CredentialIssuerName:              c.NamesConfig.CredentialIssuer,
+		DiscoveryURLOverride:              c.DiscoveryURLOverride,
+		AdditionalTolerations:             c.KubeCertAgentConfig.Tolerations,
+		AdditionalNodeSelector:            c.KubeCertAgentConfig.NodeSelector,
+		PriorityClassName:                 c.KubeCertAgentConfig.PriorityClassName,
+		ImagePullPolicy:                   c.KubeCertAgentConfig.ImagePullPolicy,
+		ControllerManagerNamespace:        *c.KubeCertAgentConfig.ControllerManagerNamespace,
+		PreferredControllerManagerPodName: c.KubeCertAgentConfig.PreferredControllerManagerPod,
+		PodSecurityContext:                c.KubeCertAgentConfig.PodSecurityContext,
+		ContainerSecurityContext:          c.KubeCertAgentConfig.ContainerSecurityContext,
+		ReadinessProbe:                    c.KubeCertAgentConfig.ReadinessProbe,
+		ContainerCommand:                  c.KubeCertAgentConfig.Command,
+		AdditionalPodLabels:               c.KubeCertAgentConfig.PodLabels,
+		AdditionalPodAnnotations:          c.KubeCertAgentConfig.PodAnnotations,
 	}
 
 	// Create controller manager.
@@ -206,7 +254,7 @@ func PrepareControllers(c *Config) (controllerinit.RunnerBuilder, error) {
 			kubecertagent.NewAgentController(
 				agentConfig,
 				client,
-				informers.kubeSystemNamespaceK8s.Core().V1().Pods(),
+				informers.controllerManagerNamespaceK8s.Core().V1().Pods(),
 				informers.installationNamespaceK8s.Apps().V1().Deployments(),
 				informers.installationNamespaceK8s.Core().V1().Pods(),
 				informers.kubePublicNamespaceK8s.Core().V1().ConfigMaps(),
@@ -266,15 +314,25 @@ func PrepareControllers(c *Config) (controllerinit.RunnerBuilder, error) {
 				informers.installationNamespaceK8s.Core().V1().Secrets(),
 				controllerlib.WithInformer,
 				c.ImpersonationProxyServerPort,
+				c.ImpersonationProxyBindAddress,
+				c.ImpersonationProxyControlPlaneNodeSelectors,
+				impersonatorconfig.DefaultShutdownTimeout,
 				c.NamesConfig.ImpersonationLoadBalancerService,
 				c.NamesConfig.ImpersonationClusterIPService,
 				c.NamesConfig.ImpersonationTLSCertificateSecret,
 				c.NamesConfig.ImpersonationCACertificateSecret,
 				c.Labels,
+				c.ImpersonationProxySetOwnerReferences,
+				c.ImpersonationProxyAnnotateIssuedSecrets,
 				clock.RealClock{},
-				impersonator.New,
+				impersonator.NewWithOptions(c.ImpersonationProxyHealthCheckPath, c.ImpersonationProxyListenerOptions),
+				impersonatorconfig.DialReadinessProbe,
 				c.NamesConfig.ImpersonationSignerSecret,
 				c.ImpersonationSigningCertProvider,
+				flowcontrol.NewTokenBucketRateLimiter(impersonatorconfig.DefaultCertIssuanceQPS, impersonatorconfig.DefaultCertIssuanceBurst),
+				impersonatorconfig.DefaultMaxSyncJitter,
+				impersonatorconfig.DefaultSyncTimeout,
+				legacyRegistryRegisterer{},
 				klogr.New(),
 			),
 			singletonWorker,
@@ -309,22 +367,23 @@ func PrepareControllers(c *Config) (controllerinit.RunnerBuilder, error) {
 
 	return controllerinit.Prepare(controllerManager.Start, leaderElector,
 		informers.kubePublicNamespaceK8s,
-		informers.kubeSystemNamespaceK8s,
+		informers.controllerManagerNamespaceK8s,
 		informers.installationNamespaceK8s,
 		informers.pinniped,
 	), nil
 }
 
 type informers struct {
-	kubePublicNamespaceK8s   k8sinformers.SharedInformerFactory
-	kubeSystemNamespaceK8s   k8sinformers.SharedInformerFactory
-	installationNamespaceK8s k8sinformers.SharedInformerFactory
-	pinniped                 pinnipedinformers.SharedInformerFactory
+	kubePublicNamespaceK8s        k8sinformers.SharedInformerFactory
+	controllerManagerNamespaceK8s k8sinformers.SharedInformerFactory
+	installationNamespaceK8s      k8sinformers.SharedInformerFactory
+	pinniped                      pinnipedinformers.SharedInformerFactory
 }
 
 // Create the informers that will be used by the controllers.
 func createInformers(
 	serverInstallationNamespace string,
+	controllerManagerNamespace string,
 	k8sClient kubernetes.Interface,
 	pinnipedClient pinnipedclientset.Interface,
 ) *informers {
@@ -334,10 +393,10 @@ func createInformers(
 			defaultResyncInterval,
 			k8sinformers.WithNamespace(kubecertagent.ClusterInfoNamespace),
 		),
-		kubeSystemNamespaceK8s: k8sinformers.NewSharedInformerFactoryWithOptions(
+		controllerManagerNamespaceK8s: k8sinformers.NewSharedInformerFactoryWithOptions(
 			k8sClient,
 			defaultResyncInterval,
-			k8sinformers.WithNamespace(kubecertagent.ControllerManagerNamespace),
+			k8sinformers.WithNamespace(controllerManagerNamespace),
 		),
 		installationNamespaceK8s: k8sinformers.NewSharedInformerFactoryWithOptions(
 			k8sClient,
@@ -350,3 +409,27 @@ func createInformers(
 		),
 	}
 }
+
+// legacyRegistryRegisterer adapts the global registry of the Kubernetes component-base metrics library, which is
+// served by the aggregated API server on its /metrics endpoint, to the prometheus.Registerer interface.
+type legacyRegistryRegisterer struct{}
+
+var _ prometheus.Registerer = legacyRegistryRegisterer{}
+
+func (legacyRegistryRegisterer) Register(c prometheus.Collector) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("could not register metrics collector: %v", r)
+		}
+	}()
+	legacyregistry.RawMustRegister(c)
+	return nil
+}
+
+func (legacyRegistryRegisterer) MustRegister(cs ...prometheus.Collector) {
+	legacyregistry.RawMustRegister(cs...)
+}
+
+func (legacyRegistryRegisterer) Unregister(prometheus.Collector) bool {
+	return false // the global registry does not support unregistering raw collectors
+}
@@ -130,6 +130,20 @@ func (mr *MockUpstreamOIDCIdentityProviderIMockRecorder) GetGroupsClaim() *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupsClaim", reflect.TypeOf((*MockUpstreamOIDCIdentityProviderI)(nil).GetGroupsClaim))
 }
 
+// GetGroupsDelimiter mocks base method.
+func (m *MockUpstreamOIDCIdentityProviderI) GetGroupsDelimiter() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupsDelimiter")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetGroupsDelimiter indicates an expected call of GetGroupsDelimiter.
+func (mr *MockUpstreamOIDCIdentityProviderIMockRecorder) GetGroupsDelimiter() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupsDelimiter", reflect.TypeOf((*MockUpstreamOIDCIdentityProviderI)(nil).GetGroupsDelimiter))
+}
+
 // GetName mocks base method.
 func (m *MockUpstreamOIDCIdentityProviderI) GetName() string {
 	m.ctrl.T.Helper()
@@ -200,6 +214,20 @@ func (mr *MockUpstreamOIDCIdentityProviderIMockRecorder) HasUserInfoURL() *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasUserInfoURL", reflect.TypeOf((*MockUpstreamOIDCIdentityProviderI)(nil).HasUserInfoURL))
 }
 
+// IgnoresEmailVerified mocks base method.
+func (m *MockUpstreamOIDCIdentityProviderI) IgnoresEmailVerified() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IgnoresEmailVerified")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IgnoresEmailVerified indicates an expected call of IgnoresEmailVerified.
+func (mr *MockUpstreamOIDCIdentityProviderIMockRecorder) IgnoresEmailVerified() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IgnoresEmailVerified", reflect.TypeOf((*MockUpstreamOIDCIdentityProviderI)(nil).IgnoresEmailVerified))
+}
+
 // PasswordCredentialsGrantAndValidateTokens mocks base method.
 func (m *MockUpstreamOIDCIdentityProviderI) PasswordCredentialsGrantAndValidateTokens(arg0 context.Context, arg1, arg2 string) (*oidctypes.Token, error) {
 	m.ctrl.T.Helper()
@@ -4,8 +4,10 @@
 package phttp
 
 import (
+	"crypto/tls"
 	"crypto/x509"
 	"net/http"
+	"net/url"
 	"time"
 
 	"k8s.io/apimachinery/pkg/util/net"
@@ -16,17 +18,29 @@ import (
 	"go.pinniped.dev/internal/plog"
 )
 
-func Default(rootCAs *x509.CertPool) *http.Client {
-	return buildClient(ptls.Default, rootCAs)
+// Default returns a client which uses ptls.Default. Requests are sent through proxyURL when it is not nil,
+// otherwise the proxy is determined by the environment (see http.ProxyFromEnvironment).
+func Default(rootCAs *x509.CertPool, proxyURL *url.URL) *http.Client {
+	return buildClient(ptls.Default, rootCAs, proxyURL, nil)
+}
+
+// DefaultWithClientCertificates is like Default, but it also presents the given client certificates to servers which
+// request one during the TLS handshake.
+func DefaultWithClientCertificates(rootCAs *x509.CertPool, proxyURL *url.URL, clientCerts []tls.Certificate) *http.Client {
+	return buildClient(ptls.Default, rootCAs, proxyURL, clientCerts)
 }
 
 func Secure(rootCAs *x509.CertPool) *http.Client {
-	return buildClient(ptls.Secure, rootCAs)
+	return buildClient(ptls.Secure, rootCAs, nil, nil)
 }
 
-func buildClient(tlsConfigFunc ptls.ConfigFunc, rootCAs *x509.CertPool) *http.Client {
+func buildClient(tlsConfigFunc ptls.ConfigFunc, rootCAs *x509.CertPool, proxyURL *url.URL, clientCerts []tls.Certificate) *http.Client {
 	baseRT := defaultTransport()
 	baseRT.TLSClientConfig = tlsConfigFunc(rootCAs)
+	baseRT.TLSClientConfig.Certificates = clientCerts
+	if proxyURL != nil {
+		baseRT.Proxy = http.ProxyURL(proxyURL)
+	}
 
 	return &http.Client{
 		Transport: defaultWrap(baseRT),
@@ -8,13 +8,17 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/util/net"
 	"k8s.io/client-go/util/cert"
 
+	"go.pinniped.dev/internal/certauthority"
 	"go.pinniped.dev/internal/crypto/ptls"
 	"go.pinniped.dev/internal/testutil/tlsserver"
 )
@@ -30,7 +34,7 @@ func TestUnwrap(t *testing.T) {
 	}{
 		{
 			name: "default",
-			f:    Default,
+			f:    func(rootCAs *x509.CertPool) *http.Client { return Default(rootCAs, nil) },
 		},
 		{
 			name: "secure",
@@ -68,7 +72,7 @@ func TestClient(t *testing.T) {
 	}{
 		{
 			name:       "default",
-			clientFunc: Default,
+			clientFunc: func(rootCAs *x509.CertPool) *http.Client { return Default(rootCAs, nil) },
 			configFunc: ptls.Default,
 		},
 		{
@@ -106,6 +110,61 @@ func TestClient(t *testing.T) {
 	}
 }
 
+func TestProxy(t *testing.T) {
+	t.Parallel()
+
+	var sawProxyRequestForHost string
+	proxyServer := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		sawProxyRequestForHost = r.Host
+	}))
+	t.Cleanup(proxyServer.Close)
+
+	proxyURL, err := url.Parse(proxyServer.URL)
+	require.NoError(t, err)
+
+	c := Default(nil, proxyURL)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://pinniped.dev.invalid/some/path", nil)
+	require.NoError(t, err)
+
+	resp, err := c.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	require.Equal(t, "pinniped.dev.invalid", sawProxyRequestForHost)
+}
+
+func TestClientCertificates(t *testing.T) {
+	t.Parallel()
+
+	ca, err := certauthority.New("test client CA", time.Hour)
+	require.NoError(t, err)
+	clientCert, err := ca.IssueClientCert("test-user", nil, time.Hour)
+	require.NoError(t, err)
+
+	var sawPeerCertificates []*x509.Certificate
+	server := tlsserver.TLSTestServer(t, http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		sawPeerCertificates = r.TLS.PeerCertificates
+	}), func(server *httptest.Server) {
+		server.TLS.ClientAuth = tls.RequireAnyClientCert
+	})
+
+	rootCAs, err := cert.NewPoolFromBytes(tlsserver.TLSTestServerCA(server))
+	require.NoError(t, err)
+
+	c := DefaultWithClientCertificates(rootCAs, nil, []tls.Certificate{*clientCert})
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := c.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	require.Len(t, sawPeerCertificates, 1)
+	require.Equal(t, "test-user", sawPeerCertificates[0].Subject.CommonName)
+}
+
 func assertUserAgent(t *testing.T, r *http.Request) {
 	t.Helper()
 
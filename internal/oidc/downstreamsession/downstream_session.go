@@ -21,6 +21,7 @@ import (
 	"go.pinniped.dev/internal/oidc/provider"
 	"go.pinniped.dev/internal/plog"
 	"go.pinniped.dev/internal/psession"
+	"go.pinniped.dev/internal/upstreamoidc"
 	"go.pinniped.dev/pkg/oidcclient/oidctypes"
 )
 
@@ -163,10 +164,11 @@ func getSubjectAndUsernameFromUpstreamIDToken(
 		return subject, subject, nil
 	}
 
-	// If the upstream username claim is configured to be the special "email" claim and the upstream "email_verified"
-	// claim is present, then validate that the "email_verified" claim is true.
+	// If the upstream username claim is configured to be (or to reference) the special "email" claim and the upstream
+	// "email_verified" claim is present, then validate that the "email_verified" claim is true, unless the admin has
+	// configured the upstream provider to ignore it.
 	emailVerifiedAsInterface, ok := idTokenClaims[emailVerifiedClaimName]
-	if usernameClaimName == emailClaimName && ok {
+	if usernameClaimReferencesEmail(usernameClaimName) && ok && !upstreamIDPConfig.IgnoresEmailVerified() {
 		emailVerified, ok := emailVerifiedAsInterface.(bool)
 		if !ok {
 			plog.Warning(
@@ -187,6 +189,27 @@ func getSubjectAndUsernameFromUpstreamIDToken(
 		}
 	}
 
+	if upstreamoidc.IsUsernameTemplate(usernameClaimName) {
+		username, err := upstreamoidc.EvaluateUsernameTemplate(usernameClaimName, idTokenClaims)
+		if err != nil {
+			plog.Warning(
+				"could not evaluate username template using upstream ID token claims",
+				"upstreamName", upstreamIDPConfig.GetName(),
+				"configuredUsernameClaim", usernameClaimName,
+				"reason", err.Error(),
+			)
+			switch {
+			case errors.Is(err, upstreamoidc.ErrUsernameTemplateClaimMissing):
+				return "", "", requiredClaimMissingErr
+			case errors.Is(err, upstreamoidc.ErrUsernameTemplateClaimEmpty):
+				return "", "", requiredClaimEmptyErr
+			default:
+				return "", "", requiredClaimInvalidFormatErr
+			}
+		}
+		return subject, username, nil
+	}
+
 	username, err := ExtractStringClaimValue(usernameClaimName, upstreamIDPConfig.GetName(), idTokenClaims)
 	if err != nil {
 		return "", "", err
@@ -195,6 +218,10 @@ func getSubjectAndUsernameFromUpstreamIDToken(
 	return subject, username, nil
 }
 
+func usernameClaimReferencesEmail(usernameClaimName string) bool {
+	return upstreamoidc.UsernameClaimReferences(usernameClaimName, emailClaimName)
+}
+
 func ExtractStringClaimValue(claimName string, upstreamIDPName string, idTokenClaims map[string]interface{}) (string, error) {
 	value, ok := idTokenClaims[claimName]
 	if !ok {
@@ -261,6 +288,10 @@ func GetGroupsFromUpstreamIDToken(
 		return nil, nil // the upstream IDP may have omitted the claim if the user has no groups
 	}
 
+	if groupsAsString, okAsString := groupsAsInterface.(string); okAsString && upstreamIDPConfig.GetGroupsDelimiter() != "" {
+		return upstreamoidc.SplitDelimitedGroups(groupsAsString, upstreamIDPConfig.GetGroupsDelimiter()), nil
+	}
+
 	groupsAsArray, okAsArray := extractGroups(groupsAsInterface)
 	if !okAsArray {
 		plog.Warning(
@@ -53,6 +53,9 @@ const (
 	// The name of the subject claim specified in the OIDC spec.
 	IDTokenSubjectClaim = "sub"
 
+	// The name of the hosted domain claim used by Google's OIDC provider.
+	IDTokenHostedDomainClaim = "hd"
+
 	// DownstreamUsernameClaim is a custom claim in the downstream ID token
 	// whose value is mapped from a claim in the upstream token.
 	// By default the value is the same as the downstream subject claim's.
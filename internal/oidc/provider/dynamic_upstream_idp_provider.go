@@ -54,6 +54,14 @@ type UpstreamOIDCIdentityProviderI interface {
 	// try to read groups from the upstream provider.
 	GetGroupsClaim() string
 
+	// GetGroupsDelimiter returns the character which separates group names when the upstream provider returns the
+	// groups claim as a single string. May return empty string, in which case such a string is a single group name.
+	GetGroupsDelimiter() string
+
+	// IgnoresEmailVerified returns true when the "email_verified" claim should not be enforced when the username is
+	// mapped from the "email" claim. When false, a present "email_verified" claim must be true.
+	IgnoresEmailVerified() bool
+
 	// AllowsPasswordGrant returns true if a client should be allowed to use the resource owner password credentials grant
 	// flow with this upstream provider. When false, it should not be allowed.
 	AllowsPasswordGrant() bool
@@ -22,6 +22,7 @@ import (
 	"go.pinniped.dev/internal/oidc/provider"
 	"go.pinniped.dev/internal/plog"
 	"go.pinniped.dev/internal/psession"
+	"go.pinniped.dev/internal/upstreamoidc"
 )
 
 var (
@@ -244,7 +245,7 @@ func validateIdentityUnchangedSinceInitialLogin(mergedClaims map[string]interfac
 			WithDebugf("provider name: %q, provider type: %q", s.ProviderName, s.ProviderType))
 	}
 
-	newUsername, hasUsername := getString(mergedClaims, usernameClaimName)
+	newUsername, hasUsername := getUsername(mergedClaims, usernameClaimName)
 	oldUsername := session.Fosite.Claims.Extra[oidc.DownstreamUsernameClaim]
 	// It's possible that a username wasn't returned by the upstream provider during refresh,
 	// but if it is, verify that it hasn't changed.
@@ -266,6 +267,15 @@ func validateIdentityUnchangedSinceInitialLogin(mergedClaims map[string]interfac
 	return nil
 }
 
+// getUsername returns the username from the merged claims, evaluating the username claim when it is a template.
+func getUsername(mergedClaims map[string]interface{}, usernameClaimName string) (string, bool) {
+	if !upstreamoidc.IsUsernameTemplate(usernameClaimName) {
+		return getString(mergedClaims, usernameClaimName)
+	}
+	username, err := upstreamoidc.EvaluateUsernameTemplate(usernameClaimName, mergedClaims)
+	return username, err == nil
+}
+
 func getString(m map[string]interface{}, key string) (string, bool) {
 	val, ok := m[key].(string)
 	return val, ok
@@ -39,11 +39,19 @@ const (
 	klogLevelAll
 )
 
-func ValidateAndSetLogLevelGlobally(level LogLevel) error {
-	klogLevel := klogLevelForPlogLevel(level)
-	if klogLevel < 0 {
+// ValidateLogLevel returns an error when the provided level is not one of the valid choices.
+func ValidateLogLevel(level LogLevel) error {
+	if klogLevelForPlogLevel(level) < 0 {
 		return errInvalidLogLevel
 	}
+	return nil
+}
+
+func ValidateAndSetLogLevelGlobally(level LogLevel) error {
+	if err := ValidateLogLevel(level); err != nil {
+		return err
+	}
+	klogLevel := klogLevelForPlogLevel(level)
 
 	if _, err := logs.GlogSetter(strconv.Itoa(int(klogLevel))); err != nil {
 		panic(err) // programmer error
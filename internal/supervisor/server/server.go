@@ -22,12 +22,14 @@ import (
 	"github.com/joshlf/go-acl"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	genericapifilters "k8s.io/apiserver/pkg/endpoints/filters"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/pkg/version"
 	"k8s.io/client-go/rest"
 	"k8s.io/component-base/logs"
+	"k8s.io/component-base/metrics/legacyregistry"
 	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/klogr"
 	"k8s.io/utils/clock"
@@ -62,13 +64,17 @@ const (
 	defaultResyncInterval = 3 * time.Minute
 )
 
-func startServer(ctx context.Context, shutdown *sync.WaitGroup, l net.Listener, handler http.Handler) {
+func startServer(ctx context.Context, shutdown *sync.WaitGroup, l net.Listener, handler http.Handler, timeouts *supervisor.EndpointTimeouts, shutdownGracePeriod time.Duration) {
 	handler = genericapifilters.WithWarningRecorder(handler)
 	handler = withBootstrapPaths(handler, "/healthz") // only health checks are allowed for bootstrap connections
 
 	server := http.Server{
-		Handler:     handler,
-		ConnContext: withBootstrapConnCtx,
+		Handler:           handler,
+		ConnContext:       withBootstrapConnCtx,
+		ReadTimeout:       durationOrZero(timeouts.ReadTimeout),
+		ReadHeaderTimeout: durationOrZero(timeouts.ReadHeaderTimeout),
+		WriteTimeout:      durationOrZero(timeouts.WriteTimeout),
+		IdleTimeout:       durationOrZero(timeouts.IdleTimeout),
 	}
 
 	shutdown.Add(1)
@@ -86,8 +92,8 @@ func startServer(ctx context.Context, shutdown *sync.WaitGroup, l net.Listener,
 		<-ctx.Done()
 		plog.Debug("server context cancelled", "err", ctx.Err())
 
-		// allow up to a minute grace period for active connections to return to idle
-		connectionsCtx, connectionsCancel := context.WithTimeout(context.Background(), time.Minute)
+		// allow up to the configured grace period for active connections to return to idle
+		connectionsCtx, connectionsCancel := context.WithTimeout(context.Background(), shutdownGracePeriod)
 		defer connectionsCancel()
 
 		if err := server.Shutdown(connectionsCtx); err != nil {
@@ -96,6 +102,13 @@ func startServer(ctx context.Context, shutdown *sync.WaitGroup, l net.Listener,
 	}()
 }
 
+func durationOrZero(d *metav1.Duration) time.Duration {
+	if d == nil {
+		return 0
+	}
+	return d.Duration
+}
+
 func signalCtx() context.Context {
 	signalCh := make(chan os.Signal, 1)
 	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
@@ -175,7 +188,8 @@ func prepareControllers(
 		WithController(
 			supervisorconfig.NewTLSCertObserverController(
 				dynamicTLSCertProvider,
-				cfg.NamesConfig.DefaultTLSCertificateSecret,
+				cfg.NamesConfig.DefaultTLSCertificateSecrets(),
+				clock.RealClock{},
 				secretInformer,
 				federationDomainInformer,
 				controllerlib.WithInformer,
@@ -272,6 +286,10 @@ func prepareControllers(
 				pinnipedClient,
 				pinnipedInformers.IDP().V1alpha1().OIDCIdentityProviders(),
 				secretInformer,
+				nil, // the informer only watches the Supervisor's own namespace
+				upstreamOIDCDiscoveryFailureThreshold(cfg),
+				cfg.UpstreamOIDCAllowedIssuerRegexp(),
+				clock.RealClock{},
 				klogr.New(),
 				controllerlib.WithInformer,
 			),
@@ -314,6 +332,14 @@ func startControllers(ctx context.Context, shutdown *sync.WaitGroup, buildContro
 	return nil
 }
 
+// upstreamOIDCDiscoveryFailureThreshold returns the configured threshold, or zero to use the controller's default.
+func upstreamOIDCDiscoveryFailureThreshold(cfg *supervisor.Config) int {
+	if cfg.UpstreamOIDCDiscoveryFailureThreshold == nil {
+		return 0
+	}
+	return *cfg.UpstreamOIDCDiscoveryFailureThreshold
+}
+
 //nolint:funlen
 func runSupervisor(podInfo *downward.PodInfo, cfg *supervisor.Config) error {
 	serverInstallationNamespace := podInfo.Namespace
@@ -409,7 +435,7 @@ func runSupervisor(podInfo *downward.PodInfo, cfg *supervisor.Config) error {
 		}
 
 		defer func() { _ = httpListener.Close() }()
-		startServer(ctx, shutdown, httpListener, oidProvidersManager)
+		startServer(ctx, shutdown, httpListener, oidProvidersManager, cfg.Endpoints.Timeouts, durationOrZero(cfg.ShutdownGracePeriod))
 		plog.Debug("supervisor http listener started", "address", httpListener.Addr().String())
 	}
 
@@ -422,6 +448,12 @@ func runSupervisor(podInfo *downward.PodInfo, cfg *supervisor.Config) error {
 		}
 
 		c := ptls.Default(nil)
+		if t := cfg.TLS; t != nil {
+			c.MinVersion = t.ParsedMinVersion
+			if len(t.ParsedCipherSuites) > 0 {
+				c.CipherSuites = t.ParsedCipherSuites
+			}
+		}
 		c.GetCertificate = func(info *tls.ClientHelloInfo) (*tls.Certificate, error) {
 			cert := dynamicTLSCertProvider.GetTLSCert(strings.ToLower(info.ServerName))
 
@@ -461,10 +493,30 @@ func runSupervisor(podInfo *downward.PodInfo, cfg *supervisor.Config) error {
 		}
 
 		defer func() { _ = httpsListener.Close() }()
-		startServer(ctx, shutdown, httpsListener, oidProvidersManager)
+		startServer(ctx, shutdown, httpsListener, oidProvidersManager, cfg.Endpoints.Timeouts, durationOrZero(cfg.ShutdownGracePeriod))
 		plog.Debug("supervisor https listener started", "address", httpsListener.Addr().String())
 	}
 
+	if e := cfg.Endpoints.Metrics; e != nil && e.Network != supervisor.NetworkDisabled {
+		finishSetupPerms := maybeSetupUnixPerms(e, supervisorPod)
+
+		metricsListener, err := net.Listen(e.Network, e.Address)
+		if err != nil {
+			return fmt.Errorf("cannot create metrics listener with network %q and address %q: %w", e.Network, e.Address, err)
+		}
+
+		if err := finishSetupPerms(); err != nil {
+			return fmt.Errorf("cannot setup metrics listener permissions for network %q and address %q: %w", e.Network, e.Address, err)
+		}
+
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("/metrics", legacyregistry.Handler())
+
+		defer func() { _ = metricsListener.Close() }()
+		startServer(ctx, shutdown, metricsListener, metricsMux, cfg.Endpoints.Timeouts, durationOrZero(cfg.ShutdownGracePeriod))
+		plog.Debug("supervisor metrics listener started", "address", metricsListener.Addr().String())
+	}
+
 	plog.Debug("supervisor started")
 	defer plog.Debug("supervisor exiting")
 
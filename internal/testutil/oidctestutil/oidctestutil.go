@@ -159,6 +159,8 @@ type TestUpstreamOIDCIdentityProvider struct {
 	RevocationURL            *url.URL
 	UsernameClaim            string
 	GroupsClaim              string
+	GroupsDelimiter          string
+	IgnoreEmailVerified      bool
 	Scopes                   []string
 	AdditionalAuthcodeParams map[string]string
 	AllowPasswordGrant       bool
@@ -236,6 +238,14 @@ func (u *TestUpstreamOIDCIdentityProvider) GetGroupsClaim() string {
 	return u.GroupsClaim
 }
 
+func (u *TestUpstreamOIDCIdentityProvider) GetGroupsDelimiter() string {
+	return u.GroupsDelimiter
+}
+
+func (u *TestUpstreamOIDCIdentityProvider) IgnoresEmailVerified() bool {
+	return u.IgnoreEmailVerified
+}
+
 func (u *TestUpstreamOIDCIdentityProvider) AllowsPasswordGrant() bool {
 	return u.AllowPasswordGrant
 }
@@ -622,6 +632,8 @@ type TestUpstreamOIDCIdentityProviderBuilder struct {
 	accessToken                          *oidctypes.AccessToken
 	usernameClaim                        string
 	groupsClaim                          string
+	groupsDelimiter                      string
+	ignoreEmailVerified                  bool
 	refreshedTokens                      *oauth2.Token
 	validatedAndMergedWithUserInfoTokens *oidctypes.Token
 	authorizationURL                     url.URL
@@ -695,6 +707,16 @@ func (u *TestUpstreamOIDCIdentityProviderBuilder) WithoutGroupsClaim() *TestUpst
 	return u
 }
 
+func (u *TestUpstreamOIDCIdentityProviderBuilder) WithGroupsDelimiter(value string) *TestUpstreamOIDCIdentityProviderBuilder {
+	u.groupsDelimiter = value
+	return u
+}
+
+func (u *TestUpstreamOIDCIdentityProviderBuilder) WithIgnoreEmailVerified() *TestUpstreamOIDCIdentityProviderBuilder {
+	u.ignoreEmailVerified = true
+	return u
+}
+
 func (u *TestUpstreamOIDCIdentityProviderBuilder) WithIDTokenClaim(name string, value interface{}) *TestUpstreamOIDCIdentityProviderBuilder {
 	if u.idToken == nil {
 		u.idToken = map[string]interface{}{}
@@ -784,6 +806,8 @@ func (u *TestUpstreamOIDCIdentityProviderBuilder) Build() *TestUpstreamOIDCIdent
 		ResourceUID:              u.resourceUID,
 		UsernameClaim:            u.usernameClaim,
 		GroupsClaim:              u.groupsClaim,
+		GroupsDelimiter:          u.groupsDelimiter,
+		IgnoreEmailVerified:      u.ignoreEmailVerified,
 		Scopes:                   u.scopes,
 		AllowPasswordGrant:       u.allowPasswordGrant,
 		AuthorizationURL:         u.authorizationURL,
@@ -39,10 +39,14 @@ type ProviderConfig struct {
 	ResourceUID              types.UID
 	UsernameClaim            string
 	GroupsClaim              string
+	GroupsDelimiter          string
+	IgnoreEmailVerified      bool
 	Config                   *oauth2.Config
 	Client                   *http.Client
 	AllowPasswordGrant       bool
 	AdditionalAuthcodeParams map[string]string
+	AdditionalTokenParams    map[string]string
+	HostedDomain             string   // when set, ID tokens must have a matching "hd" claim
 	RevocationURL            *url.URL // will commonly be nil: many providers do not offer this
 	Provider                 interface {
 		Verifier(*coreosoidc.Config) *coreosoidc.IDTokenVerifier
@@ -103,6 +107,26 @@ func (p *ProviderConfig) GetGroupsClaim() string {
 	return p.GroupsClaim
 }
 
+func (p *ProviderConfig) GetGroupsDelimiter() string {
+	return p.GroupsDelimiter
+}
+
+func (p *ProviderConfig) IgnoresEmailVerified() bool {
+	return p.IgnoreEmailVerified
+}
+
+// SplitDelimitedGroups splits a groups claim value which was returned as a single string into the names of the groups.
+// Surrounding whitespace is trimmed from each group name and empty group names are skipped.
+func SplitDelimitedGroups(groups string, delimiter string) []string {
+	result := []string{}
+	for _, group := range strings.Split(groups, delimiter) {
+		if group = strings.TrimSpace(group); group != "" {
+			result = append(result, group)
+		}
+	}
+	return result
+}
+
 func (p *ProviderConfig) AllowsPasswordGrant() bool {
 	return p.AllowPasswordGrant
 }
@@ -130,11 +154,18 @@ func (p *ProviderConfig) PasswordCredentialsGrantAndValidateTokens(ctx context.C
 }
 
 func (p *ProviderConfig) ExchangeAuthcodeAndValidateTokens(ctx context.Context, authcode string, pkceCodeVerifier pkce.Code, expectedIDTokenNonce nonce.Nonce, redirectURI string) (*oidctypes.Token, error) {
+	opts := []oauth2.AuthCodeOption{
+		pkceCodeVerifier.Verifier(),
+		oauth2.SetAuthURLParam("redirect_uri", redirectURI),
+	}
+	for name, value := range p.AdditionalTokenParams {
+		opts = append(opts, oauth2.SetAuthURLParam(name, value))
+	}
+
 	tok, err := p.Config.Exchange(
 		coreosoidc.ClientContext(ctx, p.Client),
 		authcode,
-		pkceCodeVerifier.Verifier(),
-		oauth2.SetAuthURLParam("redirect_uri", redirectURI),
+		opts...,
 	)
 	if err != nil {
 		return nil, err
@@ -341,6 +372,12 @@ func (p *ProviderConfig) validateIDToken(ctx context.Context, tok *oauth2.Token,
 		return time.Time{}, "", httperr.Wrap(http.StatusInternalServerError, "could not unmarshal id token claims", err)
 	}
 	maybeLogClaims("claims from ID token", p.Name, validatedClaims)
+	if p.HostedDomain != "" {
+		if hostedDomain, _ := validatedClaims[oidc.IDTokenHostedDomainClaim].(string); hostedDomain != p.HostedDomain {
+			return time.Time{}, "", httperr.Newf(http.StatusBadRequest,
+				"received ID token with unexpected hosted domain (expected %q, got %q)", p.HostedDomain, hostedDomain)
+		}
+	}
 	idTokenExpiry = validated.Expiry // keep track of the id token expiry if we have an id token. Otherwise, it'll just be the zero value.
 	return idTokenExpiry, idTok, nil
 }
@@ -0,0 +1,133 @@
+// Copyright 2022 the Pinniped contributors. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package upstreamoidc
+
+import (
+	"fmt"
+	"strings"
+
+	"go.pinniped.dev/internal/constable"
+)
+
+const (
+	// ErrUsernameTemplateClaimMissing is returned when a claim referenced by a username template is not present.
+	ErrUsernameTemplateClaimMissing = constable.Error("claim referenced by username template is missing")
+
+	// ErrUsernameTemplateClaimInvalid is returned when a claim referenced by a username template is not a string.
+	ErrUsernameTemplateClaimInvalid = constable.Error("claim referenced by username template is not a string")
+
+	// ErrUsernameTemplateClaimEmpty is returned when a claim referenced by a username template is an empty string.
+	ErrUsernameTemplateClaimEmpty = constable.Error("claim referenced by username template is an empty string")
+)
+
+// usernameTemplatePart is either a literal string or a reference to a claim, but never both.
+type usernameTemplatePart struct {
+	literal string
+	claim   string
+}
+
+// IsUsernameTemplate returns true when the configured username claim is a template, e.g. "{preferred_username}@{iss}",
+// rather than the plain name of a single claim.
+func IsUsernameTemplate(usernameClaim string) bool {
+	return strings.ContainsAny(usernameClaim, "{}")
+}
+
+// ValidateUsernameClaim returns an error when the configured username claim is a template which cannot be parsed.
+// Plain claim names are always valid.
+func ValidateUsernameClaim(usernameClaim string) error {
+	if !IsUsernameTemplate(usernameClaim) {
+		return nil
+	}
+	_, err := parseUsernameTemplate(usernameClaim)
+	return err
+}
+
+// UsernameTemplateClaims returns the names of the claims referenced by a valid username template, in order.
+func UsernameTemplateClaims(template string) []string {
+	parts, err := parseUsernameTemplate(template)
+	if err != nil {
+		return nil
+	}
+	var claims []string
+	for _, part := range parts {
+		if part.claim != "" {
+			claims = append(claims, part.claim)
+		}
+	}
+	return claims
+}
+
+// UsernameClaimReferences returns whether the username claim is the given claim, or is a valid username template
+// which references the given claim.
+func UsernameClaimReferences(usernameClaim string, claimName string) bool {
+	if !IsUsernameTemplate(usernameClaim) {
+		return usernameClaim == claimName
+	}
+	for _, claim := range UsernameTemplateClaims(usernameClaim) {
+		if claim == claimName {
+			return true
+		}
+	}
+	return false
+}
+
+// EvaluateUsernameTemplate replaces each "{claim}" reference in the template with the value of that claim.
+// Every referenced claim must be present and must be a non-empty string.
+func EvaluateUsernameTemplate(template string, claims map[string]interface{}) (string, error) {
+	parts, err := parseUsernameTemplate(template)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, part := range parts {
+		if part.claim == "" {
+			b.WriteString(part.literal)
+			continue
+		}
+		value, ok := claims[part.claim]
+		if !ok {
+			return "", fmt.Errorf("%w: %q", ErrUsernameTemplateClaimMissing, part.claim)
+		}
+		valueAsString, ok := value.(string)
+		if !ok {
+			return "", fmt.Errorf("%w: %q", ErrUsernameTemplateClaimInvalid, part.claim)
+		}
+		if valueAsString == "" {
+			return "", fmt.Errorf("%w: %q", ErrUsernameTemplateClaimEmpty, part.claim)
+		}
+		b.WriteString(valueAsString)
+	}
+	return b.String(), nil
+}
+
+func parseUsernameTemplate(template string) ([]usernameTemplatePart, error) {
+	var parts []usernameTemplatePart
+	rest := template
+	for rest != "" {
+		open := strings.IndexAny(rest, "{}")
+		if open == -1 {
+			parts = append(parts, usernameTemplatePart{literal: rest})
+			break
+		}
+		if rest[open] == '}' {
+			return nil, fmt.Errorf("unexpected %q at position %d", "}", len(template)-len(rest)+open)
+		}
+		if open > 0 {
+			parts = append(parts, usernameTemplatePart{literal: rest[:open]})
+		}
+
+		closing := strings.IndexAny(rest[open+1:], "{}")
+		if closing == -1 || rest[open+1+closing] != '}' {
+			return nil, fmt.Errorf("unclosed %q at position %d", "{", len(template)-len(rest)+open)
+		}
+		claim := rest[open+1 : open+1+closing]
+		if strings.TrimSpace(claim) != claim || claim == "" {
+			return nil, fmt.Errorf("invalid claim name %q at position %d", claim, len(template)-len(rest)+open)
+		}
+		parts = append(parts, usernameTemplatePart{claim: claim})
+		rest = rest[open+1+closing+1:]
+	}
+	return parts, nil
+}
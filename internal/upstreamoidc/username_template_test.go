@@ -0,0 +1,91 @@
+// Copyright 2022 the Pinniped contributors. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package upstreamoidc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateUsernameClaim(t *testing.T) {
+	tests := []struct {
+		name          string
+		usernameClaim string
+		wantErr       string
+	}{
+		{name: "empty", usernameClaim: ""},
+		{name: "plain claim name", usernameClaim: "email"},
+		{name: "single reference", usernameClaim: "{email}"},
+		{name: "multiple references with literals", usernameClaim: "{preferred_username}@{iss}"},
+		{name: "claim name with punctuation", usernameClaim: "prefix:{https://example.com/claims/user}"},
+		{name: "unexpected closing brace", usernameClaim: "user}", wantErr: `unexpected "}" at position 4`},
+		{name: "unclosed reference", usernameClaim: "{email", wantErr: `unclosed "{" at position 0`},
+		{name: "nested reference", usernameClaim: "x{a{b}}", wantErr: `unclosed "{" at position 1`},
+		{name: "empty reference", usernameClaim: "{preferred_username}@{}", wantErr: `invalid claim name "" at position 21`},
+		{name: "reference with surrounding spaces", usernameClaim: "{ email }", wantErr: `invalid claim name " email " at position 0`},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUsernameClaim(tt.usernameClaim)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestUsernameTemplateClaims(t *testing.T) {
+	require.Equal(t, []string{"preferred_username", "iss"}, UsernameTemplateClaims("{preferred_username}@{iss}"))
+	require.Nil(t, UsernameTemplateClaims("no-references"))
+	require.Nil(t, UsernameTemplateClaims("{invalid"))
+}
+
+func TestUsernameClaimReferences(t *testing.T) {
+	require.True(t, UsernameClaimReferences("email", "email"))
+	require.True(t, UsernameClaimReferences("{preferred_username}:{email}", "email"))
+	require.False(t, UsernameClaimReferences("preferred_username", "email"))
+	require.False(t, UsernameClaimReferences("{preferred_username}@{iss}", "email"))
+	require.False(t, UsernameClaimReferences("", "email"))
+}
+
+func TestEvaluateUsernameTemplate(t *testing.T) {
+	claims := map[string]interface{}{
+		"iss":                "https://issuer.example.com",
+		"preferred_username": "pinny",
+		"email":              "pinny@example.com",
+		"empty":              "",
+		"number":             42,
+	}
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  string
+	}{
+		{name: "single reference", template: "{email}", want: "pinny@example.com"},
+		{name: "multiple references with literals", template: "{preferred_username}@{iss}", want: "pinny@https://issuer.example.com"},
+		{name: "literal prefix and suffix", template: "oidc:{preferred_username}:user", want: "oidc:pinny:user"},
+		{name: "missing claim", template: "{upn}@{iss}", wantErr: `claim referenced by username template is missing: "upn"`},
+		{name: "empty claim", template: "{empty}", wantErr: `claim referenced by username template is an empty string: "empty"`},
+		{name: "non-string claim", template: "{number}", wantErr: `claim referenced by username template is not a string: "number"`},
+		{name: "invalid template", template: "{email", wantErr: `unclosed "{" at position 0`},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EvaluateUsernameTemplate(tt.template, claims)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				require.Empty(t, got)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -275,7 +275,7 @@ func Login(issuer string, clientID string, opts ...Option) (*oidctypes.Token, er
 		ctx:          context.Background(),
 		logger:       logr.Discard(), // discard logs unless a logger is specified
 		callbacks:    make(chan callbackResult, 2),
-		httpClient:   phttp.Default(nil),
+		httpClient:   phttp.Default(nil, nil),
 
 		// Default implementations of external dependencies (to be mocked in tests).
 		generateState: state.Generate,
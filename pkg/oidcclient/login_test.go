@@ -70,7 +70,7 @@ func newClientForServer(server *httptest.Server) *http.Client {
 	pool := x509.NewCertPool()
 	caPEMData := tlsserver.TLSTestServerCA(server)
 	pool.AppendCertsFromPEM(caPEMData)
-	return phttp.Default(pool)
+	return phttp.Default(pool, nil)
 }
 
 func TestLogin(t *testing.T) { // nolint:gocyclo
@@ -2032,7 +2032,7 @@ func performImpersonatorDiscoveryURL(ctx context.Context, t *testing.T, env *tes
 			} else if strategy.Type == conciergev1alpha.ImpersonationProxyStrategyType {
 				t.Logf("Waiting for successful impersonation proxy strategy on %s: found status %s with reason %s and message: %s",
 					credentialIssuerName(env), strategy.Status, strategy.Reason, strategy.Message)
-				if strategy.Reason == conciergev1alpha.ErrorDuringSetupStrategyReason {
+				if isImpersonationProxySetupErrorReason(strategy.Reason) {
 					// The server encountered an unexpected error while starting the impersonator, so fail the test fast.
 					return false, fmt.Errorf("found impersonation strategy in %s state with message: %s", strategy.Reason, strategy.Message)
 				}
@@ -2046,6 +2046,21 @@ func performImpersonatorDiscoveryURL(ctx context.Context, t *testing.T, env *tes
 	return impersonationProxyURL, impersonationProxyCACertPEM
 }
 
+// isImpersonationProxySetupErrorReason returns whether the reason means that the impersonation proxy could not be set up.
+func isImpersonationProxySetupErrorReason(reason conciergev1alpha.StrategyReason) bool {
+	switch reason {
+	case conciergev1alpha.ErrorDuringSetupStrategyReason,
+		conciergev1alpha.ServiceErrorStrategyReason,
+		conciergev1alpha.SecretErrorStrategyReason,
+		conciergev1alpha.CAErrorStrategyReason,
+		conciergev1alpha.ValidationErrorStrategyReason,
+		conciergev1alpha.ServerStartErrorStrategyReason:
+		return true
+	default:
+		return false
+	}
+}
+
 func requireDisabledStrategy(ctx context.Context, t *testing.T, env *testlib.TestEnv, adminConciergeClient pinnipedconciergeclientset.Interface) {
 	t.Helper()
 
@@ -2064,7 +2079,7 @@ func requireDisabledStrategy(ctx context.Context, t *testing.T, env *testlib.Tes
 			} else if strategy.Type == conciergev1alpha.ImpersonationProxyStrategyType {
 				t.Logf("Waiting for disabled impersonation proxy strategy on %s: found status %s with reason %s and message: %s",
 					credentialIssuerName(env), strategy.Status, strategy.Reason, strategy.Message)
-				if strategy.Reason == conciergev1alpha.ErrorDuringSetupStrategyReason {
+				if isImpersonationProxySetupErrorReason(strategy.Reason) {
 					// The server encountered an unexpected error while stopping the impersonator, so fail the test fast.
 					return false, fmt.Errorf("found impersonation strategy in %s state with message: %s", strategy.Reason, strategy.Message)
 				}
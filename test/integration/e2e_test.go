@@ -133,8 +133,10 @@ func TestE2EFullIntegration_Browser(t *testing.T) { // nolint:gocyclo
 		// Create upstream OIDC provider and wait for it to become ready.
 		testlib.CreateTestOIDCIdentityProvider(t, idpv1alpha1.OIDCIdentityProviderSpec{
 			Issuer: env.SupervisorUpstreamOIDC.Issuer,
-			TLS: &idpv1alpha1.TLSSpec{
-				CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(env.SupervisorUpstreamOIDC.CABundle)),
+			TLS: &idpv1alpha1.OIDCTLSSpec{
+				TLSSpec: idpv1alpha1.TLSSpec{
+					CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(env.SupervisorUpstreamOIDC.CABundle)),
+				},
 			},
 			AuthorizationConfig: idpv1alpha1.OIDCAuthorizationConfig{
 				AdditionalScopes: env.SupervisorUpstreamOIDC.AdditionalScopes,
@@ -299,8 +301,10 @@ func TestE2EFullIntegration_Browser(t *testing.T) { // nolint:gocyclo
 		// Create upstream OIDC provider and wait for it to become ready.
 		testlib.CreateTestOIDCIdentityProvider(t, idpv1alpha1.OIDCIdentityProviderSpec{
 			Issuer: env.SupervisorUpstreamOIDC.Issuer,
-			TLS: &idpv1alpha1.TLSSpec{
-				CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(env.SupervisorUpstreamOIDC.CABundle)),
+			TLS: &idpv1alpha1.OIDCTLSSpec{
+				TLSSpec: idpv1alpha1.TLSSpec{
+					CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(env.SupervisorUpstreamOIDC.CABundle)),
+				},
 			},
 			AuthorizationConfig: idpv1alpha1.OIDCAuthorizationConfig{
 				AdditionalScopes: env.SupervisorUpstreamOIDC.AdditionalScopes,
@@ -418,8 +422,10 @@ func TestE2EFullIntegration_Browser(t *testing.T) { // nolint:gocyclo
 		// Create upstream OIDC provider and wait for it to become ready.
 		testlib.CreateTestOIDCIdentityProvider(t, idpv1alpha1.OIDCIdentityProviderSpec{
 			Issuer: env.SupervisorUpstreamOIDC.Issuer,
-			TLS: &idpv1alpha1.TLSSpec{
-				CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(env.SupervisorUpstreamOIDC.CABundle)),
+			TLS: &idpv1alpha1.OIDCTLSSpec{
+				TLSSpec: idpv1alpha1.TLSSpec{
+					CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(env.SupervisorUpstreamOIDC.CABundle)),
+				},
 			},
 			AuthorizationConfig: idpv1alpha1.OIDCAuthorizationConfig{
 				AdditionalScopes: additionalScopes,
@@ -543,8 +549,10 @@ func TestE2EFullIntegration_Browser(t *testing.T) { // nolint:gocyclo
 		// Create upstream OIDC provider and wait for it to become ready.
 		testlib.CreateTestOIDCIdentityProvider(t, idpv1alpha1.OIDCIdentityProviderSpec{
 			Issuer: env.SupervisorUpstreamOIDC.Issuer,
-			TLS: &idpv1alpha1.TLSSpec{
-				CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(env.SupervisorUpstreamOIDC.CABundle)),
+			TLS: &idpv1alpha1.OIDCTLSSpec{
+				TLSSpec: idpv1alpha1.TLSSpec{
+					CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(env.SupervisorUpstreamOIDC.CABundle)),
+				},
 			},
 			AuthorizationConfig: idpv1alpha1.OIDCAuthorizationConfig{
 				AdditionalScopes:   env.SupervisorUpstreamOIDC.AdditionalScopes,
@@ -611,8 +619,10 @@ func TestE2EFullIntegration_Browser(t *testing.T) { // nolint:gocyclo
 		// Create upstream OIDC provider and wait for it to become ready.
 		oidcIdentityProvider := testlib.CreateTestOIDCIdentityProvider(t, idpv1alpha1.OIDCIdentityProviderSpec{
 			Issuer: env.SupervisorUpstreamOIDC.Issuer,
-			TLS: &idpv1alpha1.TLSSpec{
-				CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(env.SupervisorUpstreamOIDC.CABundle)),
+			TLS: &idpv1alpha1.OIDCTLSSpec{
+				TLSSpec: idpv1alpha1.TLSSpec{
+					CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(env.SupervisorUpstreamOIDC.CABundle)),
+				},
 			},
 			AuthorizationConfig: idpv1alpha1.OIDCAuthorizationConfig{
 				AdditionalScopes:   env.SupervisorUpstreamOIDC.AdditionalScopes,
@@ -79,8 +79,10 @@ func TestSupervisorLogin_Browser(t *testing.T) {
 				t.Helper()
 				oidcIDP := testlib.CreateTestOIDCIdentityProvider(t, idpv1alpha1.OIDCIdentityProviderSpec{
 					Issuer: env.SupervisorUpstreamOIDC.Issuer,
-					TLS: &idpv1alpha1.TLSSpec{
-						CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(env.SupervisorUpstreamOIDC.CABundle)),
+					TLS: &idpv1alpha1.OIDCTLSSpec{
+						TLSSpec: idpv1alpha1.TLSSpec{
+							CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(env.SupervisorUpstreamOIDC.CABundle)),
+						},
 					},
 					Client: idpv1alpha1.OIDCClient{
 						SecretName: testlib.CreateClientCredsSecret(t, env.SupervisorUpstreamOIDC.ClientID, env.SupervisorUpstreamOIDC.ClientSecret).Name,
@@ -107,8 +109,10 @@ func TestSupervisorLogin_Browser(t *testing.T) {
 				t.Helper()
 				oidcIDP := testlib.CreateTestOIDCIdentityProvider(t, idpv1alpha1.OIDCIdentityProviderSpec{
 					Issuer: env.SupervisorUpstreamOIDC.Issuer,
-					TLS: &idpv1alpha1.TLSSpec{
-						CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(env.SupervisorUpstreamOIDC.CABundle)),
+					TLS: &idpv1alpha1.OIDCTLSSpec{
+						TLSSpec: idpv1alpha1.TLSSpec{
+							CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(env.SupervisorUpstreamOIDC.CABundle)),
+						},
 					},
 					Client: idpv1alpha1.OIDCClient{
 						SecretName: testlib.CreateClientCredsSecret(t, env.SupervisorUpstreamOIDC.ClientID, env.SupervisorUpstreamOIDC.ClientSecret).Name,
@@ -161,8 +165,10 @@ func TestSupervisorLogin_Browser(t *testing.T) {
 				}
 				oidcIDP := testlib.CreateTestOIDCIdentityProvider(t, idpv1alpha1.OIDCIdentityProviderSpec{
 					Issuer: env.SupervisorUpstreamOIDC.Issuer,
-					TLS: &idpv1alpha1.TLSSpec{
-						CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(env.SupervisorUpstreamOIDC.CABundle)),
+					TLS: &idpv1alpha1.OIDCTLSSpec{
+						TLSSpec: idpv1alpha1.TLSSpec{
+							CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(env.SupervisorUpstreamOIDC.CABundle)),
+						},
 					},
 					Client: idpv1alpha1.OIDCClient{
 						SecretName: testlib.CreateClientCredsSecret(t, env.SupervisorUpstreamOIDC.ClientID, env.SupervisorUpstreamOIDC.ClientSecret).Name,
@@ -195,8 +201,10 @@ func TestSupervisorLogin_Browser(t *testing.T) {
 				t.Helper()
 				oidcIDP := testlib.CreateTestOIDCIdentityProvider(t, idpv1alpha1.OIDCIdentityProviderSpec{
 					Issuer: env.SupervisorUpstreamOIDC.Issuer,
-					TLS: &idpv1alpha1.TLSSpec{
-						CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(env.SupervisorUpstreamOIDC.CABundle)),
+					TLS: &idpv1alpha1.OIDCTLSSpec{
+						TLSSpec: idpv1alpha1.TLSSpec{
+							CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(env.SupervisorUpstreamOIDC.CABundle)),
+						},
 					},
 					Client: idpv1alpha1.OIDCClient{
 						SecretName: testlib.CreateClientCredsSecret(t, env.SupervisorUpstreamOIDC.ClientID, env.SupervisorUpstreamOIDC.ClientSecret).Name,
@@ -40,12 +40,36 @@ func TestSupervisorUpstreamOIDCDiscovery(t *testing.T) {
 				Message: `failed to perform OIDC discovery against "https://127.0.0.1:444444/invalid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee":
 Get "https://127.0.0.1:444444/invalid-url-that-is-really-really-long-nanananananananannanananan-batman-nanananananananananananananana-batman-lalalalalalalalalal-batman-weeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee/.well-known/openid-configuration": dial tcp: address 444444: in [truncated 10 chars]`,
 			},
+			{
+				Type:    "JWKSFetchSucceeded",
+				Status:  v1alpha1.ConditionUnknown,
+				Reason:  "OIDCDiscoveryFailed",
+				Message: "cannot fetch JWKS until OIDC discovery succeeds",
+			},
+			{
+				Type:    "ScopesSupported",
+				Status:  v1alpha1.ConditionUnknown,
+				Reason:  "OIDCDiscoveryFailed",
+				Message: "cannot check scopes until OIDC discovery succeeds",
+			},
+			{
+				Type:    "RefreshTokenSupported",
+				Status:  v1alpha1.ConditionUnknown,
+				Reason:  "OIDCDiscoveryFailed",
+				Message: "cannot check refresh token support until OIDC discovery succeeds",
+			},
 			{
 				Type:    "AdditionalAuthorizeParametersValid",
 				Status:  "True",
 				Reason:  "Success",
 				Message: "additionalAuthorizeParameters parameter names are allowed",
 			},
+			{
+				Type:    "ClaimsValid",
+				Status:  "True",
+				Reason:  "Success",
+				Message: "claims are valid",
+			},
 		})
 	})
 
@@ -53,8 +77,10 @@ Get "https://127.0.0.1:444444/invalid-url-that-is-really-really-long-nananananan
 		t.Parallel()
 		spec := v1alpha1.OIDCIdentityProviderSpec{
 			Issuer: env.SupervisorUpstreamOIDC.Issuer + "/",
-			TLS: &v1alpha1.TLSSpec{
-				CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(env.SupervisorUpstreamOIDC.CABundle)),
+			TLS: &v1alpha1.OIDCTLSSpec{
+				TLSSpec: v1alpha1.TLSSpec{
+					CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(env.SupervisorUpstreamOIDC.CABundle)),
+				},
 			},
 			AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{
 				AdditionalScopes: []string{"email", "profile"},
@@ -78,12 +104,36 @@ Get "https://127.0.0.1:444444/invalid-url-that-is-really-really-long-nananananan
 				Message: `failed to perform OIDC discovery against "` + env.SupervisorUpstreamOIDC.Issuer + `/":
 oidc: issuer did not match the issuer returned by provider, expected "` + env.SupervisorUpstreamOIDC.Issuer + `/" got "` + env.SupervisorUpstreamOIDC.Issuer + `"`,
 			},
+			{
+				Type:    "JWKSFetchSucceeded",
+				Status:  v1alpha1.ConditionUnknown,
+				Reason:  "OIDCDiscoveryFailed",
+				Message: "cannot fetch JWKS until OIDC discovery succeeds",
+			},
+			{
+				Type:    "ScopesSupported",
+				Status:  v1alpha1.ConditionUnknown,
+				Reason:  "OIDCDiscoveryFailed",
+				Message: "cannot check scopes until OIDC discovery succeeds",
+			},
+			{
+				Type:    "RefreshTokenSupported",
+				Status:  v1alpha1.ConditionUnknown,
+				Reason:  "OIDCDiscoveryFailed",
+				Message: "cannot check refresh token support until OIDC discovery succeeds",
+			},
 			{
 				Type:    "AdditionalAuthorizeParametersValid",
 				Status:  "True",
 				Reason:  "Success",
 				Message: "additionalAuthorizeParameters parameter names are allowed",
 			},
+			{
+				Type:    "ClaimsValid",
+				Status:  "True",
+				Reason:  "Success",
+				Message: "claims are valid",
+			},
 		})
 	})
 
@@ -91,8 +141,10 @@ oidc: issuer did not match the issuer returned by provider, expected "` + env.Su
 		t.Parallel()
 		spec := v1alpha1.OIDCIdentityProviderSpec{
 			Issuer: env.SupervisorUpstreamOIDC.Issuer,
-			TLS: &v1alpha1.TLSSpec{
-				CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(env.SupervisorUpstreamOIDC.CABundle)),
+			TLS: &v1alpha1.OIDCTLSSpec{
+				TLSSpec: v1alpha1.TLSSpec{
+					CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(env.SupervisorUpstreamOIDC.CABundle)),
+				},
 			},
 			AuthorizationConfig: v1alpha1.OIDCAuthorizationConfig{
 				AdditionalScopes: []string{"email", "profile"},
@@ -115,12 +167,36 @@ oidc: issuer did not match the issuer returned by provider, expected "` + env.Su
 				Reason:  "Success",
 				Message: "discovered issuer configuration",
 			},
+			{
+				Type:    "JWKSFetchSucceeded",
+				Status:  v1alpha1.ConditionTrue,
+				Reason:  "Success",
+				Message: "fetched JWKS with usable signing keys",
+			},
+			{
+				Type:    "ScopesSupported",
+				Status:  v1alpha1.ConditionTrue,
+				Reason:  "Success",
+				Message: "all requested scopes are advertised by the OIDC provider",
+			},
+			{
+				Type:    "RefreshTokenSupported",
+				Status:  v1alpha1.ConditionTrue,
+				Reason:  "Success",
+				Message: "OIDC provider advertises support for refresh tokens",
+			},
 			{
 				Type:    "AdditionalAuthorizeParametersValid",
 				Status:  "True",
 				Reason:  "Success",
 				Message: "additionalAuthorizeParameters parameter names are allowed",
 			},
+			{
+				Type:    "ClaimsValid",
+				Status:  "True",
+				Reason:  "Success",
+				Message: "claims are valid",
+			},
 		})
 	})
 }